@@ -0,0 +1,101 @@
+// Package apperrors defines the kinds of failure usecases report, so that
+// transports can pick a status code without inspecting error messages.
+package apperrors
+
+import (
+	"errors"
+	"net/http"
+
+	"google.golang.org/grpc/codes"
+)
+
+var (
+	ErrNotFound         = errors.New("not found")
+	ErrAlreadyExists    = errors.New("already exists")
+	ErrPermissionDenied = errors.New("permission denied")
+	ErrUnauthenticated  = errors.New("unauthenticated")
+	ErrInvalidArgument  = errors.New("invalid argument")
+	ErrRateLimited      = errors.New("rate limited")
+	ErrTooLarge         = errors.New("too large")
+)
+
+// CodeInternal is reported for any error that is not one of the kinds above.
+const CodeInternal = "internal"
+
+const internalMessage = "internal server error"
+
+// Error carries a client-facing message together with its kind.
+// errors.Is(err, ErrNotFound) holds for an Error of that kind.
+type Error struct {
+	Kind    error
+	Message string
+}
+
+func (e *Error) Error() string { return e.Message }
+
+func (e *Error) Unwrap() error { return e.Kind }
+
+func New(kind error, message string) error {
+	return &Error{Kind: kind, Message: message}
+}
+
+type mapping struct {
+	kind       error
+	code       string
+	httpStatus int
+	grpcCode   codes.Code
+}
+
+var mappings = []mapping{
+	{ErrNotFound, "not_found", http.StatusNotFound, codes.NotFound},
+	{ErrAlreadyExists, "already_exists", http.StatusConflict, codes.AlreadyExists},
+	{ErrPermissionDenied, "permission_denied", http.StatusForbidden, codes.PermissionDenied},
+	{ErrUnauthenticated, "unauthenticated", http.StatusUnauthorized, codes.Unauthenticated},
+	{ErrInvalidArgument, "invalid_argument", http.StatusBadRequest, codes.InvalidArgument},
+	{ErrRateLimited, "rate_limited", http.StatusTooManyRequests, codes.ResourceExhausted},
+	{ErrTooLarge, "too_large", http.StatusRequestEntityTooLarge, codes.InvalidArgument},
+}
+
+func lookup(err error) (mapping, bool) {
+	for _, m := range mappings {
+		if errors.Is(err, m.kind) {
+			return m, true
+		}
+	}
+	return mapping{}, false
+}
+
+// Code returns the machine-readable code sent to clients.
+func Code(err error) string {
+	if m, ok := lookup(err); ok {
+		return m.code
+	}
+	return CodeInternal
+}
+
+func HTTPStatus(err error) int {
+	if m, ok := lookup(err); ok {
+		return m.httpStatus
+	}
+	return http.StatusInternalServerError
+}
+
+func GRPCCode(err error) codes.Code {
+	if m, ok := lookup(err); ok {
+		return m.grpcCode
+	}
+	return codes.Internal
+}
+
+// Message returns the text that is safe to show to clients. Errors of an
+// unknown kind may carry driver or stack details, so they are masked.
+func Message(err error) string {
+	var appErr *Error
+	if errors.As(err, &appErr) {
+		return appErr.Message
+	}
+	if _, ok := lookup(err); ok {
+		return err.Error()
+	}
+	return internalMessage
+}
@@ -0,0 +1,104 @@
+package apperrors
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+)
+
+func TestMapping(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		code       string
+		httpStatus int
+		grpcCode   codes.Code
+		message    string
+	}{
+		{
+			name:       "not found",
+			err:        New(ErrNotFound, "user not found"),
+			code:       "not_found",
+			httpStatus: http.StatusNotFound,
+			grpcCode:   codes.NotFound,
+			message:    "user not found",
+		},
+		{
+			name:       "already exists",
+			err:        New(ErrAlreadyExists, "username already taken"),
+			code:       "already_exists",
+			httpStatus: http.StatusConflict,
+			grpcCode:   codes.AlreadyExists,
+			message:    "username already taken",
+		},
+		{
+			name:       "permission denied",
+			err:        New(ErrPermissionDenied, "not allowed"),
+			code:       "permission_denied",
+			httpStatus: http.StatusForbidden,
+			grpcCode:   codes.PermissionDenied,
+			message:    "not allowed",
+		},
+		{
+			name:       "unauthenticated",
+			err:        New(ErrUnauthenticated, "invalid token"),
+			code:       "unauthenticated",
+			httpStatus: http.StatusUnauthorized,
+			grpcCode:   codes.Unauthenticated,
+			message:    "invalid token",
+		},
+		{
+			name:       "invalid argument",
+			err:        New(ErrInvalidArgument, "bad id"),
+			code:       "invalid_argument",
+			httpStatus: http.StatusBadRequest,
+			grpcCode:   codes.InvalidArgument,
+			message:    "bad id",
+		},
+		{
+			name:       "rate limited",
+			err:        ErrRateLimited,
+			code:       "rate_limited",
+			httpStatus: http.StatusTooManyRequests,
+			grpcCode:   codes.ResourceExhausted,
+			message:    "rate limited",
+		},
+		{
+			name:       "too large",
+			err:        New(ErrTooLarge, "file is too large"),
+			code:       "too_large",
+			httpStatus: http.StatusRequestEntityTooLarge,
+			grpcCode:   codes.InvalidArgument,
+			message:    "file is too large",
+		},
+		{
+			name:       "wrapped kind",
+			err:        fmt.Errorf("get post: %w", New(ErrNotFound, "post not found")),
+			code:       "not_found",
+			httpStatus: http.StatusNotFound,
+			grpcCode:   codes.NotFound,
+			message:    "post not found",
+		},
+		{
+			name:       "unknown error is masked",
+			err:        errors.New("pq: relation \"users\" does not exist"),
+			code:       CodeInternal,
+			httpStatus: http.StatusInternalServerError,
+			grpcCode:   codes.Internal,
+			message:    "internal server error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.code, Code(tt.err))
+			assert.Equal(t, tt.httpStatus, HTTPStatus(tt.err))
+			assert.Equal(t, tt.grpcCode, GRPCCode(tt.err))
+			assert.Equal(t, tt.message, Message(tt.err))
+		})
+	}
+}
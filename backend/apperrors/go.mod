@@ -0,0 +1,15 @@
+module backend.com/forum/apperrors
+
+go 1.24
+
+require (
+	github.com/stretchr/testify v1.10.0
+	google.golang.org/grpc v1.72.0
+)
+
+require (
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	golang.org/x/sys v0.30.0 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
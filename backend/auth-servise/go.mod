@@ -3,6 +3,7 @@ module github.com/Ulyana-kru00/forum-project
 go 1.24.0
 
 require (
+	backend.com/forum/apperrors v0.0.0-00010101000000-000000000000
 	backend.com/forum/proto v0.0.0-00010101000000-000000000000
 	github.com/DATA-DOG/go-sqlmock v1.5.2
 	github.com/dgrijalva/jwt-go v3.2.0+incompatible
@@ -12,6 +13,7 @@ require (
 	github.com/jmoiron/sqlx v1.3.5
 	github.com/lib/pq v1.10.7
 	github.com/stretchr/testify v1.10.0
+	github.com/swaggo/gin-swagger v1.6.0
 	github.com/swaggo/swag v1.8.12
 	go.uber.org/zap v1.27.0
 	golang.org/x/crypto v0.38.0
@@ -27,10 +29,11 @@ require (
 	github.com/go-openapi/swag v0.23.1 // indirect
 	github.com/josharian/intern v1.0.0 // indirect
 	github.com/mailru/easyjson v0.9.0 // indirect
-	github.com/swaggo/gin-swagger v1.6.0 // indirect
 	golang.org/x/tools v0.33.0 // indirect
 )
 
+replace backend.com/forum/apperrors => ../apperrors
+
 replace backend.com/forum/proto => ../proto
 
 require (
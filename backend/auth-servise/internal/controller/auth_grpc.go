@@ -36,7 +36,7 @@ func (c *AuthController) Register(
 
 	ucResp, err := c.uc.Register(ctx, ucReq)
 	if err != nil {
-		return nil, status.Error(codes.Internal, err.Error())
+		return nil, grpcError(err)
 	}
 
 	return &pb.RegisterResponse{UserId: ucResp.UserID}, nil
@@ -57,13 +57,36 @@ func (c *AuthController) Login(
 
 	ucResp, err := c.uc.Login(ctx, ucReq)
 	if err != nil {
-		return nil, status.Error(codes.Internal, err.Error())
+		return nil, grpcError(err)
 	}
 
+	return convertLoginResponseToProto(ucResp), nil
+}
+
+func (c *AuthController) Refresh(
+	ctx context.Context,
+	req *pb.RefreshRequest,
+) (*pb.LoginResponse, error) {
+	if req == nil {
+		return nil, status.Error(codes.InvalidArgument, "request cannot be nil")
+	}
+
+	ucResp, err := c.uc.Refresh(ctx, &usecase.RefreshRequest{RefreshToken: req.RefreshToken})
+	if err != nil {
+		return nil, grpcError(err)
+	}
+
+	return convertLoginResponseToProto(ucResp), nil
+}
+
+func convertLoginResponseToProto(resp *usecase.LoginResponse) *pb.LoginResponse {
 	return &pb.LoginResponse{
-		Token:    ucResp.Token,
-		Username: ucResp.Username,
-	}, nil
+		Token:            resp.Token,
+		Username:         resp.Username,
+		ExpiresAt:        timestamppb.New(resp.ExpiresAt),
+		RefreshToken:     resp.RefreshToken,
+		RefreshExpiresAt: timestamppb.New(resp.RefreshExpiresAt),
+	}
 }
 
 func (c *AuthController) GetUser(
@@ -74,11 +97,11 @@ func (c *AuthController) GetUser(
 		return nil, status.Error(codes.InvalidArgument, "request cannot be nil")
 	}
 
-	ucReq := &usecase.GetUserRequest{UserID: req.Id}
+	ucReq := &usecase.GetUserRequest{UserID: req.Id, Username: req.Username}
 
 	ucResp, err := c.uc.GetUser(ctx, ucReq)
 	if err != nil {
-		return nil, status.Error(codes.Internal, err.Error())
+		return nil, grpcError(err)
 	}
 
 	return &pb.GetUserResponse{
@@ -86,6 +109,27 @@ func (c *AuthController) GetUser(
 	}, nil
 }
 
+func (c *AuthController) GetUsers(
+	ctx context.Context,
+	req *pb.GetUsersRequest,
+) (*pb.GetUsersResponse, error) {
+	if req == nil {
+		return nil, status.Error(codes.InvalidArgument, "request cannot be nil")
+	}
+
+	ucResp, err := c.uc.GetUsers(ctx, &usecase.GetUsersRequest{UserIDs: req.Ids})
+	if err != nil {
+		return nil, grpcError(err)
+	}
+
+	users := make([]*pb.User, 0, len(ucResp.Users))
+	for _, user := range ucResp.Users {
+		users = append(users, convertUserToProto(user))
+	}
+
+	return &pb.GetUsersResponse{Users: users}, nil
+}
+
 // auth_grpc.go
 func convertUserToProto(user *entity.User) *pb.User {
 	if user == nil {
@@ -121,12 +165,48 @@ func (c *AuthController) ValidateToken(
 
 	ucResp, err := c.uc.ValidateToken(ctx, ucReq)
 	if err != nil {
-		return nil, status.Error(codes.Internal, err.Error())
+		return nil, grpcError(err)
 	}
 
 	return &pb.ValidateTokenResponse{
-		Valid:  ucResp.Valid,
-		UserId: ucResp.UserID,
-		Role:   ucResp.Role,
+		Valid:    ucResp.Valid,
+		UserId:   ucResp.UserID,
+		Username: ucResp.Username,
+		Role:     ucResp.Role,
 	}, nil
 }
+
+func (c *AuthController) Logout(
+	ctx context.Context,
+	req *pb.LogoutRequest,
+) (*pb.LogoutResponse, error) {
+	if req == nil {
+		return nil, status.Error(codes.InvalidArgument, "request cannot be nil")
+	}
+
+	ucResp, err := c.uc.Logout(ctx, &usecase.LogoutRequest{
+		Token:        req.Token,
+		RefreshToken: req.RefreshToken,
+	})
+	if err != nil {
+		return nil, grpcError(err)
+	}
+
+	return &pb.LogoutResponse{Success: ucResp.Success}, nil
+}
+
+func (c *AuthController) LogoutAll(
+	ctx context.Context,
+	req *pb.LogoutAllRequest,
+) (*pb.LogoutAllResponse, error) {
+	if req == nil {
+		return nil, status.Error(codes.InvalidArgument, "request cannot be nil")
+	}
+
+	ucResp, err := c.uc.LogoutAll(ctx, &usecase.LogoutAllRequest{Token: req.Token})
+	if err != nil {
+		return nil, grpcError(err)
+	}
+
+	return &pb.LogoutAllResponse{RevokedSessions: ucResp.RevokedSessions}, nil
+}
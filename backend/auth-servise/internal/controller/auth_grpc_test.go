@@ -7,6 +7,7 @@ import (
 	"testing"
 	"time"
 
+	"backend.com/forum/apperrors"
 	pb "backend.com/forum/proto"
 	"github.com/Ulyana-kru00/forum-project/internal/entity"
 	"github.com/Ulyana-kru00/forum-project/internal/usecase"
@@ -61,9 +62,9 @@ func TestAuthController_Register(t *testing.T) {
 						Username: "",
 						Password: "testpass",
 					},
-				).Return(nil, errors.New("username cannot be empty"))
+				).Return(nil, apperrors.New(apperrors.ErrInvalidArgument, "username cannot be empty"))
 			},
-			expectedErr: status.New(codes.Internal, "username cannot be empty"),
+			expectedErr: status.New(codes.InvalidArgument, "username cannot be empty"),
 		},
 		{
 			name: "empty password",
@@ -78,9 +79,9 @@ func TestAuthController_Register(t *testing.T) {
 						Username: "testuser",
 						Password: "",
 					},
-				).Return(nil, errors.New("password cannot be empty"))
+				).Return(nil, apperrors.New(apperrors.ErrInvalidArgument, "password cannot be empty"))
 			},
-			expectedErr: status.New(codes.Internal, "password cannot be empty"),
+			expectedErr: status.New(codes.InvalidArgument, "password cannot be empty"),
 		},
 		{
 			name: "usecase returns error",
@@ -94,7 +95,7 @@ func TestAuthController_Register(t *testing.T) {
 					gomock.Any(),
 				).Return(nil, errors.New("database error"))
 			},
-			expectedErr: status.New(codes.Internal, "database error"),
+			expectedErr: status.New(codes.Internal, "internal server error"),
 		},
 	}
 
@@ -121,6 +122,9 @@ func TestAuthController_Login(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
+	loginExpiresAt := time.Now().Add(15 * time.Minute)
+	loginRefreshExpiresAt := time.Now().Add(30 * 24 * time.Hour)
+
 	mockUC := NewMockAuthUsecase(ctrl)
 	controller := NewAuthController(mockUC)
 
@@ -145,13 +149,19 @@ func TestAuthController_Login(t *testing.T) {
 						Password: "testpass",
 					},
 				).Return(&usecase.LoginResponse{
-					Token:    "test_token",
-					Username: "testuser",
+					Token:            "test_token",
+					ExpiresAt:        loginExpiresAt,
+					RefreshToken:     "test_refresh",
+					RefreshExpiresAt: loginRefreshExpiresAt,
+					Username:         "testuser",
 				}, nil)
 			},
 			want: &pb.LoginResponse{
-				Token:    "test_token",
-				Username: "testuser",
+				Token:            "test_token",
+				ExpiresAt:        timestamppb.New(loginExpiresAt),
+				RefreshToken:     "test_refresh",
+				RefreshExpiresAt: timestamppb.New(loginRefreshExpiresAt),
+				Username:         "testuser",
 			},
 		},
 		{
@@ -167,9 +177,9 @@ func TestAuthController_Login(t *testing.T) {
 						Username: "",
 						Password: "testpass",
 					},
-				).Return(nil, errors.New("username cannot be empty"))
+				).Return(nil, apperrors.New(apperrors.ErrInvalidArgument, "username cannot be empty"))
 			},
-			expectedErr: status.New(codes.Internal, "username cannot be empty"),
+			expectedErr: status.New(codes.InvalidArgument, "username cannot be empty"),
 		},
 		{
 			name: "empty password",
@@ -184,9 +194,9 @@ func TestAuthController_Login(t *testing.T) {
 						Username: "testuser",
 						Password: "",
 					},
-				).Return(nil, errors.New("password cannot be empty"))
+				).Return(nil, apperrors.New(apperrors.ErrInvalidArgument, "password cannot be empty"))
 			},
-			expectedErr: status.New(codes.Internal, "password cannot be empty"),
+			expectedErr: status.New(codes.InvalidArgument, "password cannot be empty"),
 		},
 		{
 			name: "invalid credentials",
@@ -198,9 +208,9 @@ func TestAuthController_Login(t *testing.T) {
 				m.EXPECT().Login(
 					gomock.Any(),
 					gomock.Any(),
-				).Return(nil, errors.New("invalid credentials"))
+				).Return(nil, apperrors.New(apperrors.ErrUnauthenticated, "invalid credentials"))
 			},
-			expectedErr: status.New(codes.Internal, "invalid credentials"),
+			expectedErr: status.New(codes.Unauthenticated, "invalid credentials"),
 		},
 	}
 
@@ -271,9 +281,9 @@ func TestAuthController_GetUser(t *testing.T) {
 				m.EXPECT().GetUser(
 					gomock.Any(),
 					&usecase.GetUserRequest{UserID: 0},
-				).Return(nil, errors.New("user id cannot be empty"))
+				).Return(nil, apperrors.New(apperrors.ErrInvalidArgument, "user id cannot be empty"))
 			},
-			expectedErr: status.New(codes.Internal, "user id cannot be empty"),
+			expectedErr: status.New(codes.InvalidArgument, "user id cannot be empty"),
 		},
 		{
 			name: "user not found",
@@ -282,9 +292,9 @@ func TestAuthController_GetUser(t *testing.T) {
 				m.EXPECT().GetUser(
 					gomock.Any(),
 					&usecase.GetUserRequest{UserID: 999},
-				).Return(nil, errors.New("user not found"))
+				).Return(nil, apperrors.New(apperrors.ErrNotFound, "user not found"))
 			},
-			expectedErr: status.New(codes.Internal, "user not found"),
+			expectedErr: status.New(codes.NotFound, "user not found"),
 		},
 	}
 
@@ -347,9 +357,9 @@ func TestAuthController_ValidateToken(t *testing.T) {
 				m.EXPECT().ValidateToken(
 					gomock.Any(),
 					&usecase.ValidateTokenRequest{Token: ""},
-				).Return(nil, errors.New("token cannot be empty"))
+				).Return(nil, apperrors.New(apperrors.ErrInvalidArgument, "token cannot be empty"))
 			},
-			expectedErr: status.New(codes.Internal, "token cannot be empty"),
+			expectedErr: status.New(codes.InvalidArgument, "token cannot be empty"),
 		},
 		{
 			name: "invalid token",
@@ -358,9 +368,9 @@ func TestAuthController_ValidateToken(t *testing.T) {
 				m.EXPECT().ValidateToken(
 					gomock.Any(),
 					&usecase.ValidateTokenRequest{Token: "invalid_token"},
-				).Return(nil, errors.New("invalid token"))
+				).Return(nil, apperrors.New(apperrors.ErrUnauthenticated, "invalid token"))
 			},
-			expectedErr: status.New(codes.Internal, "invalid token"),
+			expectedErr: status.New(codes.Unauthenticated, "invalid token"),
 		},
 	}
 
@@ -660,18 +670,15 @@ func TestAuthController_Register_InvalidArguments(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			if tt.req != nil {
-				mockUC.EXPECT().Register(gomock.Any(), gomock.Any()).Return(nil, errors.New(tt.err))
+				mockUC.EXPECT().Register(gomock.Any(), gomock.Any()).
+					Return(nil, apperrors.New(apperrors.ErrInvalidArgument, tt.err))
 			}
 
 			_, err := controller.Register(context.Background(), tt.req)
 			assert.Error(t, err)
 			st, ok := status.FromError(err)
 			assert.True(t, ok)
-			if tt.req == nil {
-				assert.Equal(t, codes.InvalidArgument, st.Code())
-			} else {
-				assert.Equal(t, codes.Internal, st.Code())
-			}
+			assert.Equal(t, codes.InvalidArgument, st.Code())
 			assert.Contains(t, st.Message(), tt.err)
 		})
 	}
@@ -685,13 +692,13 @@ func TestAuthController_GetUser_InvalidID(t *testing.T) {
 	controller := NewAuthController(mockUC)
 
 	mockUC.EXPECT().GetUser(gomock.Any(), &usecase.GetUserRequest{UserID: -1}).
-		Return(nil, errors.New("invalid user id"))
+		Return(nil, apperrors.New(apperrors.ErrInvalidArgument, "invalid user id"))
 
 	_, err := controller.GetUser(context.Background(), &pb.GetUserRequest{Id: -1})
 	assert.Error(t, err)
 	st, ok := status.FromError(err)
 	assert.True(t, ok)
-	assert.Equal(t, codes.Internal, st.Code())
+	assert.Equal(t, codes.InvalidArgument, st.Code())
 	assert.Equal(t, "invalid user id", st.Message())
 }
 
@@ -710,5 +717,114 @@ func TestAuthController_ValidateToken_InvalidResponse(t *testing.T) {
 	st, ok := status.FromError(err)
 	assert.True(t, ok)
 	assert.Equal(t, codes.Internal, st.Code())
-	assert.Equal(t, "token validation failed", st.Message())
+	assert.Equal(t, "internal server error", st.Message())
+}
+
+func TestAuthController_Logout(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUC := NewMockAuthUsecase(ctrl)
+	controller := NewAuthController(mockUC)
+
+	mockUC.EXPECT().Logout(
+		gomock.Any(),
+		&usecase.LogoutRequest{Token: "valid_token"},
+	).Return(&usecase.LogoutResponse{Success: true}, nil)
+
+	resp, err := controller.Logout(context.Background(), &pb.LogoutRequest{Token: "valid_token"})
+	assert.NoError(t, err)
+	assert.True(t, resp.Success)
+
+	_, err = controller.Logout(context.Background(), nil)
+	st, ok := status.FromError(err)
+	assert.True(t, ok)
+	assert.Equal(t, codes.InvalidArgument, st.Code())
+}
+
+func TestAuthController_LogoutAll(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUC := NewMockAuthUsecase(ctrl)
+	controller := NewAuthController(mockUC)
+
+	mockUC.EXPECT().LogoutAll(
+		gomock.Any(),
+		&usecase.LogoutAllRequest{Token: "invalid_token"},
+	).Return(nil, apperrors.New(apperrors.ErrUnauthenticated, "invalid token"))
+
+	resp, err := controller.LogoutAll(context.Background(), &pb.LogoutAllRequest{Token: "invalid_token"})
+	assert.Nil(t, resp)
+	st, ok := status.FromError(err)
+	assert.True(t, ok)
+	assert.Equal(t, codes.Unauthenticated, st.Code())
+	assert.Equal(t, "invalid token", st.Message())
+}
+
+func TestAuthController_Refresh(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUC := NewMockAuthUsecase(ctrl)
+	controller := NewAuthController(mockUC)
+
+	mockUC.EXPECT().Refresh(
+		gomock.Any(),
+		&usecase.RefreshRequest{RefreshToken: "old_refresh"},
+	).Return(&usecase.LoginResponse{
+		Token:        "new_token",
+		RefreshToken: "new_refresh",
+		Username:     "testuser",
+	}, nil)
+
+	resp, err := controller.Refresh(context.Background(), &pb.RefreshRequest{RefreshToken: "old_refresh"})
+	assert.NoError(t, err)
+	assert.Equal(t, "new_token", resp.Token)
+	assert.Equal(t, "new_refresh", resp.RefreshToken)
+
+	mockUC.EXPECT().Refresh(
+		gomock.Any(),
+		&usecase.RefreshRequest{RefreshToken: "reused"},
+	).Return(nil, apperrors.New(apperrors.ErrUnauthenticated, "refresh token reuse detected"))
+
+	_, err = controller.Refresh(context.Background(), &pb.RefreshRequest{RefreshToken: "reused"})
+	st, ok := status.FromError(err)
+	assert.True(t, ok)
+	assert.Equal(t, codes.Unauthenticated, st.Code())
+}
+
+func TestAuthController_GetUsers(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUC := NewMockAuthUsecase(ctrl)
+	controller := NewAuthController(mockUC)
+
+	createdAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	mockUC.EXPECT().GetUsers(
+		gomock.Any(),
+		&usecase.GetUsersRequest{UserIDs: []int64{1, 2}},
+	).Return(&usecase.GetUsersResponse{Users: []*entity.User{
+		{ID: 1, Username: "alice", Role: entity.RoleUser, CreatedAt: createdAt},
+		{ID: 2, Username: "bob", Role: entity.RoleAdmin, CreatedAt: createdAt},
+	}}, nil)
+
+	resp, err := controller.GetUsers(context.Background(), &pb.GetUsersRequest{Ids: []int64{1, 2}})
+	assert.NoError(t, err)
+	assert.Len(t, resp.Users, 2)
+	assert.Equal(t, "alice", resp.Users[0].Username)
+	assert.Equal(t, "admin", resp.Users[1].Role)
+
+	mockUC.EXPECT().GetUsers(gomock.Any(), gomock.Any()).
+		Return(nil, apperrors.New(apperrors.ErrInvalidArgument, "at most 1000 user ids per request"))
+
+	_, err = controller.GetUsers(context.Background(), &pb.GetUsersRequest{Ids: []int64{1}})
+	st, ok := status.FromError(err)
+	assert.True(t, ok)
+	assert.Equal(t, codes.InvalidArgument, st.Code())
+
+	_, err = controller.GetUsers(context.Background(), nil)
+	st, _ = status.FromError(err)
+	assert.Equal(t, codes.InvalidArgument, st.Code())
 }
@@ -4,11 +4,19 @@ package controller
 import (
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
+	"backend.com/forum/apperrors"
 	"github.com/Ulyana-kru00/forum-project/internal/usecase"
 	"github.com/gin-gonic/gin"
 )
 
+var (
+	errInvalidRequest    = apperrors.New(apperrors.ErrInvalidArgument, "Invalid request")
+	errMissingAuthHeader = apperrors.New(apperrors.ErrUnauthenticated, "Authorization header is required")
+)
+
 type HTTPAuthController struct {
 	uc usecase.AuthUsecaseInterface
 }
@@ -27,6 +35,14 @@ type HTTPLoginRequest struct {
 	Password string `json:"password"`
 }
 
+type HTTPRefreshRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+type HTTPLogoutRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
 // Register регистрирует пользователя через HTTP
 // @Summary Регистрация пользователя
 // @Description Регистрирует нового пользователя
@@ -36,12 +52,13 @@ type HTTPLoginRequest struct {
 // @Param request body HTTPRegisterRequest true "Данные для регистрации"
 // @Success 200 {object} map[string]interface{} "user_id"
 // @Failure 400 {object} entity.ErrorResponse
+// @Failure 409 {object} entity.ErrorResponse
 // @Failure 500 {object} entity.ErrorResponse
 // @Router /api/v1/auth/register [post]
 func (ctrl *HTTPAuthController) Register(c *gin.Context) {
 	var req HTTPRegisterRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		writeError(c, errInvalidRequest)
 		return
 	}
 
@@ -52,7 +69,7 @@ func (ctrl *HTTPAuthController) Register(c *gin.Context) {
 
 	ucResp, err := ctrl.uc.Register(c.Request.Context(), ucReq)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeError(c, err)
 		return
 	}
 
@@ -70,12 +87,13 @@ func (ctrl *HTTPAuthController) Register(c *gin.Context) {
 // @Param request body HTTPLoginRequest true "Данные для входа"
 // @Success 200 {object} map[string]interface{} "token"
 // @Failure 400 {object} entity.ErrorResponse
+// @Failure 401 {object} entity.ErrorResponse
 // @Failure 500 {object} entity.ErrorResponse
 // @Router /api/v1/auth/login [post]
 func (ctrl *HTTPAuthController) Login(c *gin.Context) {
 	var req HTTPLoginRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		writeError(c, errInvalidRequest)
 		return
 	}
 
@@ -86,14 +104,50 @@ func (ctrl *HTTPAuthController) Login(c *gin.Context) {
 
 	ucResp, err := ctrl.uc.Login(c.Request.Context(), ucReq)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"token":    ucResp.Token,
-		"username": ucResp.Username,
+	c.JSON(http.StatusOK, loginResponseJSON(ucResp))
+}
+
+// Refresh обменивает refresh-токен на новую пару токенов
+// @Summary Обновление токенов
+// @Description Выдает новый access-токен и новый refresh-токен. Каждый refresh-токен одноразовый; повторное использование отзывает всю цепочку
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body HTTPRefreshRequest true "Refresh-токен"
+// @Success 200 {object} map[string]interface{} "token, refresh_token"
+// @Failure 400 {object} entity.ErrorResponse
+// @Failure 401 {object} entity.ErrorResponse
+// @Router /api/v1/auth/refresh [post]
+func (ctrl *HTTPAuthController) Refresh(c *gin.Context) {
+	var req HTTPRefreshRequest
+	if err := c.ShouldBindJSON(&req); err != nil || req.RefreshToken == "" {
+		writeError(c, errInvalidRequest)
+		return
+	}
+
+	ucResp, err := ctrl.uc.Refresh(c.Request.Context(), &usecase.RefreshRequest{
+		RefreshToken: req.RefreshToken,
 	})
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, loginResponseJSON(ucResp))
+}
+
+func loginResponseJSON(resp *usecase.LoginResponse) gin.H {
+	return gin.H{
+		"token":              resp.Token,
+		"expires_at":         resp.ExpiresAt.Format(time.RFC3339),
+		"refresh_token":      resp.RefreshToken,
+		"refresh_expires_at": resp.RefreshExpiresAt.Format(time.RFC3339),
+		"username":           resp.Username,
+	}
 }
 
 // GetUser получает информацию о пользователе
@@ -104,6 +158,7 @@ func (ctrl *HTTPAuthController) Login(c *gin.Context) {
 // @Produce json
 // @Param id path string true "ID пользователя"
 // @Success 200 {object} map[string]interface{} "Данные пользователя"
+// @Failure 400 {object} entity.ErrorResponse
 // @Failure 404 {object} entity.ErrorResponse
 // @Failure 500 {object} entity.ErrorResponse
 // @Router /api/v1/auth/user/{id} [get]
@@ -111,13 +166,13 @@ func (ctrl *HTTPAuthController) GetUser(ctx *gin.Context) {
 	userIDStr := ctx.Param("id")
 	userID, err := strconv.ParseInt(userIDStr, 10, 64)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID format"})
+		writeError(ctx, apperrors.New(apperrors.ErrInvalidArgument, "Invalid user ID format"))
 		return
 	}
 
 	user, err := ctrl.uc.GetUserByID(ctx.Request.Context(), userID)
 	if err != nil {
-		ctx.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		writeError(ctx, err)
 		return
 	}
 
@@ -126,3 +181,78 @@ func (ctrl *HTTPAuthController) GetUser(ctx *gin.Context) {
 		"username": user.Username,
 	})
 }
+
+// Logout завершает текущую сессию пользователя
+// @Summary Выход из системы
+// @Description Отзывает сессию, связанную с переданным токеном
+// @Tags auth
+// @Produce json
+// @Security ApiKeyAuth
+// @Param Authorization header string true "Bearer токен"
+// @Param request body HTTPLogoutRequest false "Refresh-токен, цепочка которого будет отозвана"
+// @Success 200 {object} map[string]interface{} "success"
+// @Failure 401 {object} entity.ErrorResponse
+// @Failure 500 {object} entity.ErrorResponse
+// @Router /api/v1/auth/logout [post]
+func (ctrl *HTTPAuthController) Logout(c *gin.Context) {
+	token, ok := bearerToken(c)
+	if !ok {
+		writeError(c, errMissingAuthHeader)
+		return
+	}
+
+	// Тело необязательно: без refresh-токена отзывается только access-сессия
+	var req HTTPLogoutRequest
+	_ = c.ShouldBindJSON(&req)
+
+	ucResp, err := ctrl.uc.Logout(c.Request.Context(), &usecase.LogoutRequest{
+		Token:        token,
+		RefreshToken: req.RefreshToken,
+	})
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": ucResp.Success,
+	})
+}
+
+// LogoutAll завершает все сессии пользователя
+// @Summary Выход со всех устройств
+// @Description Отзывает все сессии владельца переданного токена
+// @Tags auth
+// @Produce json
+// @Security ApiKeyAuth
+// @Param Authorization header string true "Bearer токен"
+// @Success 200 {object} map[string]interface{} "revoked_sessions"
+// @Failure 401 {object} entity.ErrorResponse
+// @Failure 500 {object} entity.ErrorResponse
+// @Router /api/v1/auth/logout/all [post]
+func (ctrl *HTTPAuthController) LogoutAll(c *gin.Context) {
+	token, ok := bearerToken(c)
+	if !ok {
+		writeError(c, errMissingAuthHeader)
+		return
+	}
+
+	ucResp, err := ctrl.uc.LogoutAll(c.Request.Context(), &usecase.LogoutAllRequest{Token: token})
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"revoked_sessions": ucResp.RevokedSessions,
+	})
+}
+
+func bearerToken(c *gin.Context) (string, bool) {
+	authHeader := c.GetHeader("Authorization")
+	if authHeader == "" {
+		return "", false
+	}
+	token := strings.TrimPrefix(authHeader, "Bearer ")
+	return token, token != ""
+}
@@ -7,7 +7,9 @@ import (
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
+	"backend.com/forum/apperrors"
 	"github.com/Ulyana-kru00/forum-project/internal/entity"
 	"github.com/Ulyana-kru00/forum-project/internal/usecase"
 	"github.com/gin-gonic/gin"
@@ -40,17 +42,27 @@ func TestHTTPAuthController_Register(t *testing.T) {
 			requestBody:    `{"username": "testuser"`, // malformed JSON
 			mockSetup:      func(m *MockAuthUsecase) {},
 			expectedStatus: http.StatusBadRequest,
-			expectedBody:   `{"error":"Invalid request"}`,
+			expectedBody:   `{"error":"Invalid request","code":"invalid_argument"}`,
 		},
 		{
 			name:        "usecase error",
 			requestBody: `{"username": "testuser", "password": "testpass"}`,
 			mockSetup: func(m *MockAuthUsecase) {
 				m.EXPECT().Register(gomock.Any(), gomock.Any()).
-					Return(nil, errors.New("some error"))
+					Return(nil, errors.New("pq: connection refused"))
 			},
 			expectedStatus: http.StatusInternalServerError,
-			expectedBody:   `{"error":"some error"}`,
+			expectedBody:   `{"error":"internal server error","code":"internal"}`,
+		},
+		{
+			name:        "username taken",
+			requestBody: `{"username": "testuser", "password": "testpass"}`,
+			mockSetup: func(m *MockAuthUsecase) {
+				m.EXPECT().Register(gomock.Any(), gomock.Any()).
+					Return(nil, apperrors.New(apperrors.ErrAlreadyExists, "username already taken"))
+			},
+			expectedStatus: http.StatusConflict,
+			expectedBody:   `{"error":"username already taken","code":"already_exists"}`,
 		},
 	}
 
@@ -94,29 +106,33 @@ func TestHTTPAuthController_Login(t *testing.T) {
 					Username: "testuser",
 					Password: "testpass",
 				}).Return(&usecase.LoginResponse{
-					Token:    "testtoken",
-					Username: "testuser",
+					Token:            "testtoken",
+					ExpiresAt:        time.Date(2025, 1, 1, 0, 15, 0, 0, time.UTC),
+					RefreshToken:     "testrefresh",
+					RefreshExpiresAt: time.Date(2025, 1, 31, 0, 0, 0, 0, time.UTC),
+					Username:         "testuser",
 				}, nil)
 			},
 			expectedStatus: http.StatusOK,
-			expectedBody:   `{"token":"testtoken","username":"testuser"}`,
+			expectedBody: `{"token":"testtoken","expires_at":"2025-01-01T00:15:00Z",` +
+				`"refresh_token":"testrefresh","refresh_expires_at":"2025-01-31T00:00:00Z","username":"testuser"}`,
 		},
 		{
 			name:           "invalid request body",
 			requestBody:    `{"username": "testuser"`, // malformed JSON
 			mockSetup:      func(m *MockAuthUsecase) {},
 			expectedStatus: http.StatusBadRequest,
-			expectedBody:   `{"error":"Invalid request"}`,
+			expectedBody:   `{"error":"Invalid request","code":"invalid_argument"}`,
 		},
 		{
 			name:        "usecase error",
 			requestBody: `{"username": "testuser", "password": "testpass"}`,
 			mockSetup: func(m *MockAuthUsecase) {
 				m.EXPECT().Login(gomock.Any(), gomock.Any()).
-					Return(nil, errors.New("invalid credentials"))
+					Return(nil, apperrors.New(apperrors.ErrUnauthenticated, "invalid credentials"))
 			},
-			expectedStatus: http.StatusInternalServerError,
-			expectedBody:   `{"error":"invalid credentials"}`,
+			expectedStatus: http.StatusUnauthorized,
+			expectedBody:   `{"error":"invalid credentials","code":"unauthenticated"}`,
 		},
 	}
 
@@ -170,10 +186,10 @@ func TestHTTPAuthController_GetUser(t *testing.T) {
 			userID: "456",
 			mockSetup: func(m *MockAuthUsecase) {
 				m.EXPECT().GetUserByID(gomock.Any(), int64(456)).
-					Return(nil, errors.New("not found"))
+					Return(nil, apperrors.New(apperrors.ErrNotFound, "user not found"))
 			},
 			expectedStatus: http.StatusNotFound,
-			expectedBody:   `{"error":"User not found"}`,
+			expectedBody:   `{"error":"user not found","code":"not_found"}`,
 		},
 		{
 			name:   "invalid user ID format",
@@ -181,17 +197,17 @@ func TestHTTPAuthController_GetUser(t *testing.T) {
 			mockSetup: func(m *MockAuthUsecase) {
 				// No expectation as it should fail before calling usecase
 			},
-			expectedStatus: http.StatusNotFound,
-			expectedBody:   `{"error":"User not found"}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"Invalid user ID format","code":"invalid_argument"}`,
 		},
 		{
 			name:   "empty user ID",
 			userID: "",
 			mockSetup: func(m *MockAuthUsecase) {
-				// No expectation as it should fail before calling usecase
+				// No route matches, so gin answers before the controller
 			},
 			expectedStatus: http.StatusNotFound,
-			expectedBody:   `{"error":"User not found"}`,
+			expectedBody:   `404 page not found`,
 		},
 	}
 
@@ -221,3 +237,156 @@ func TestHTTPAuthController_GetUser(t *testing.T) {
 		})
 	}
 }
+
+func TestHTTPAuthController_Logout(t *testing.T) {
+	tests := []struct {
+		name           string
+		authHeader     string
+		mockSetup      func(*MockAuthUsecase)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:       "successful logout",
+			authHeader: "Bearer valid_token",
+			mockSetup: func(m *MockAuthUsecase) {
+				m.EXPECT().Logout(gomock.Any(), &usecase.LogoutRequest{Token: "valid_token"}).
+					Return(&usecase.LogoutResponse{Success: true}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"success":true}`,
+		},
+		{
+			name:           "missing authorization header",
+			authHeader:     "",
+			mockSetup:      func(m *MockAuthUsecase) {},
+			expectedStatus: http.StatusUnauthorized,
+			expectedBody:   `{"error":"Authorization header is required","code":"unauthenticated"}`,
+		},
+		{
+			name:       "usecase error",
+			authHeader: "Bearer stale_token",
+			mockSetup: func(m *MockAuthUsecase) {
+				m.EXPECT().Logout(gomock.Any(), gomock.Any()).
+					Return(nil, apperrors.New(apperrors.ErrUnauthenticated, "session not found"))
+			},
+			expectedStatus: http.StatusUnauthorized,
+			expectedBody:   `{"error":"session not found","code":"unauthenticated"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockUsecase := NewMockAuthUsecase(ctrl)
+			tt.mockSetup(mockUsecase)
+
+			router := gin.Default()
+			authController := NewHTTPAuthController(mockUsecase)
+			router.POST("/logout", authController.Logout)
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("POST", "/logout", nil)
+			if tt.authHeader != "" {
+				req.Header.Set("Authorization", tt.authHeader)
+			}
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.JSONEq(t, tt.expectedBody, w.Body.String())
+		})
+	}
+}
+
+func TestHTTPAuthController_LogoutAll(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUsecase := NewMockAuthUsecase(ctrl)
+	mockUsecase.EXPECT().LogoutAll(gomock.Any(), &usecase.LogoutAllRequest{Token: "valid_token"}).
+		Return(&usecase.LogoutAllResponse{RevokedSessions: 2}, nil)
+
+	router := gin.Default()
+	authController := NewHTTPAuthController(mockUsecase)
+	router.POST("/logout/all", authController.LogoutAll)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/logout/all", nil)
+	req.Header.Set("Authorization", "Bearer valid_token")
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"revoked_sessions":2}`, w.Body.String())
+}
+
+func TestHTTPAuthController_Refresh(t *testing.T) {
+	tests := []struct {
+		name           string
+		requestBody    string
+		mockSetup      func(*MockAuthUsecase)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:        "successful refresh",
+			requestBody: `{"refresh_token": "old"}`,
+			mockSetup: func(m *MockAuthUsecase) {
+				m.EXPECT().Refresh(gomock.Any(), &usecase.RefreshRequest{RefreshToken: "old"}).
+					Return(&usecase.LoginResponse{
+						Token:            "access",
+						ExpiresAt:        time.Date(2025, 1, 1, 0, 15, 0, 0, time.UTC),
+						RefreshToken:     "new",
+						RefreshExpiresAt: time.Date(2025, 1, 31, 0, 0, 0, 0, time.UTC),
+						Username:         "testuser",
+					}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: `{"token":"access","expires_at":"2025-01-01T00:15:00Z",` +
+				`"refresh_token":"new","refresh_expires_at":"2025-01-31T00:00:00Z","username":"testuser"}`,
+		},
+		{
+			name:           "missing refresh token",
+			requestBody:    `{}`,
+			mockSetup:      func(m *MockAuthUsecase) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"Invalid request","code":"invalid_argument"}`,
+		},
+		{
+			name:        "reused refresh token",
+			requestBody: `{"refresh_token": "reused"}`,
+			mockSetup: func(m *MockAuthUsecase) {
+				m.EXPECT().Refresh(gomock.Any(), gomock.Any()).
+					Return(nil, apperrors.New(apperrors.ErrUnauthenticated, "refresh token reuse detected"))
+			},
+			expectedStatus: http.StatusUnauthorized,
+			expectedBody:   `{"error":"refresh token reuse detected","code":"unauthenticated"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockUsecase := NewMockAuthUsecase(ctrl)
+			tt.mockSetup(mockUsecase)
+
+			router := gin.Default()
+			authController := NewHTTPAuthController(mockUsecase)
+			router.POST("/refresh", authController.Refresh)
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("POST", "/refresh", strings.NewReader(tt.requestBody))
+			req.Header.Set("Content-Type", "application/json")
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.JSONEq(t, tt.expectedBody, w.Body.String())
+		})
+	}
+}
@@ -0,0 +1,23 @@
+// controller/errors.go
+package controller
+
+import (
+	"backend.com/forum/apperrors"
+	"github.com/Ulyana-kru00/forum-project/internal/entity"
+	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc/status"
+)
+
+// writeError renders err as the JSON error envelope with the HTTP status
+// of its kind.
+func writeError(c *gin.Context, err error) {
+	c.JSON(apperrors.HTTPStatus(err), entity.ErrorResponse{
+		Error: apperrors.Message(err),
+		Code:  apperrors.Code(err),
+	})
+}
+
+// grpcError converts a usecase error into a gRPC status error.
+func grpcError(err error) error {
+	return status.Error(apperrors.GRPCCode(err), apperrors.Message(err))
+}
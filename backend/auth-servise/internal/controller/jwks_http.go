@@ -0,0 +1,29 @@
+// controller/jwks_http.go
+package controller
+
+import (
+	"net/http"
+
+	"github.com/Ulyana-kru00/forum-project/pkg/auth"
+	"github.com/gin-gonic/gin"
+)
+
+type JWKSController struct {
+	keys *auth.KeySet
+}
+
+func NewJWKSController(keys *auth.KeySet) *JWKSController {
+	return &JWKSController{keys: keys}
+}
+
+// JWKS публикует открытые ключи для проверки токенов
+// @Summary Набор открытых ключей (JWKS)
+// @Description Возвращает открытые ключи, которыми можно проверить подпись access-токенов. Закрытые ключи не публикуются
+// @Tags auth
+// @Produce json
+// @Success 200 {object} auth.JWKS
+// @Router /.well-known/jwks.json [get]
+func (ctrl *JWKSController) JWKS(c *gin.Context) {
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, ctrl.keys.JWKS())
+}
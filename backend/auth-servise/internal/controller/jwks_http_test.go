@@ -0,0 +1,38 @@
+// controller/jwks_http_test.go
+package controller
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Ulyana-kru00/forum-project/pkg/auth"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJWKSController_JWKS(t *testing.T) {
+	key, err := auth.GenerateSigningKey("test-key", auth.AlgEdDSA)
+	require.NoError(t, err)
+	keys, err := auth.NewKeySet("", key)
+	require.NoError(t, err)
+
+	router := gin.Default()
+	router.GET("/.well-known/jwks.json", NewJWKSController(keys).JWKS)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/.well-known/jwks.json", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Header().Get("Cache-Control"), "max-age")
+
+	var body auth.JWKS
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	require.Len(t, body.Keys, 1)
+	assert.Equal(t, "test-key", body.Keys[0].Kid)
+	assert.Equal(t, "EdDSA", body.Keys[0].Alg)
+	assert.NotContains(t, w.Body.String(), "\"d\"")
+}
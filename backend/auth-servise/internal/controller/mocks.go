@@ -67,6 +67,34 @@ func (m *MockAuthUsecase) GetUserByID(ctx context.Context, userID int64) (*entit
 	return ret0, ret1
 }
 
+func (m *MockAuthUsecase) Logout(ctx context.Context, req *usecase.LogoutRequest) (*usecase.LogoutResponse, error) {
+	ret := m.ctrl.Call(m, "Logout", ctx, req)
+	ret0, _ := ret[0].(*usecase.LogoutResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (m *MockAuthUsecase) LogoutAll(ctx context.Context, req *usecase.LogoutAllRequest) (*usecase.LogoutAllResponse, error) {
+	ret := m.ctrl.Call(m, "LogoutAll", ctx, req)
+	ret0, _ := ret[0].(*usecase.LogoutAllResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (m *MockAuthUsecase) Refresh(ctx context.Context, req *usecase.RefreshRequest) (*usecase.LoginResponse, error) {
+	ret := m.ctrl.Call(m, "Refresh", ctx, req)
+	ret0, _ := ret[0].(*usecase.LoginResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (m *MockAuthUsecase) GetUsers(ctx context.Context, req *usecase.GetUsersRequest) (*usecase.GetUsersResponse, error) {
+	ret := m.ctrl.Call(m, "GetUsers", ctx, req)
+	ret0, _ := ret[0].(*usecase.GetUsersResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
 func (mr *MockAuthUsecaseRecorder) Register(ctx, req interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(
 		mr.mock,
@@ -116,3 +144,43 @@ func (mr *MockAuthUsecaseRecorder) GetUserByID(ctx, userID interface{}) *gomock.
 		userID,
 	)
 }
+
+func (mr *MockAuthUsecaseRecorder) Logout(ctx, req interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(
+		mr.mock,
+		"Logout",
+		reflect.TypeOf((*MockAuthUsecase)(nil).Logout),
+		ctx,
+		req,
+	)
+}
+
+func (mr *MockAuthUsecaseRecorder) LogoutAll(ctx, req interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(
+		mr.mock,
+		"LogoutAll",
+		reflect.TypeOf((*MockAuthUsecase)(nil).LogoutAll),
+		ctx,
+		req,
+	)
+}
+
+func (mr *MockAuthUsecaseRecorder) Refresh(ctx, req interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(
+		mr.mock,
+		"Refresh",
+		reflect.TypeOf((*MockAuthUsecase)(nil).Refresh),
+		ctx,
+		req,
+	)
+}
+
+func (mr *MockAuthUsecaseRecorder) GetUsers(ctx, req interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(
+		mr.mock,
+		"GetUsers",
+		reflect.TypeOf((*MockAuthUsecase)(nil).GetUsers),
+		ctx,
+		req,
+	)
+}
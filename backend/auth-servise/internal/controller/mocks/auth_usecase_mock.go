@@ -66,6 +66,21 @@ func (mr *MockAuthUsecaseInterfaceMockRecorder) GetUserByID(ctx, userID interfac
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserByID", reflect.TypeOf((*MockAuthUsecaseInterface)(nil).GetUserByID), ctx, userID)
 }
 
+// GetUsers mocks base method.
+func (m *MockAuthUsecaseInterface) GetUsers(ctx context.Context, req *usecase.GetUsersRequest) (*usecase.GetUsersResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUsers", ctx, req)
+	ret0, _ := ret[0].(*usecase.GetUsersResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUsers indicates an expected call of GetUsers.
+func (mr *MockAuthUsecaseInterfaceMockRecorder) GetUsers(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUsers", reflect.TypeOf((*MockAuthUsecaseInterface)(nil).GetUsers), ctx, req)
+}
+
 // Login mocks base method.
 func (m *MockAuthUsecaseInterface) Login(ctx context.Context, req *usecase.LoginRequest) (*usecase.LoginResponse, error) {
 	m.ctrl.T.Helper()
@@ -81,6 +96,51 @@ func (mr *MockAuthUsecaseInterfaceMockRecorder) Login(ctx, req interface{}) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Login", reflect.TypeOf((*MockAuthUsecaseInterface)(nil).Login), ctx, req)
 }
 
+// Logout mocks base method.
+func (m *MockAuthUsecaseInterface) Logout(ctx context.Context, req *usecase.LogoutRequest) (*usecase.LogoutResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Logout", ctx, req)
+	ret0, _ := ret[0].(*usecase.LogoutResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Logout indicates an expected call of Logout.
+func (mr *MockAuthUsecaseInterfaceMockRecorder) Logout(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Logout", reflect.TypeOf((*MockAuthUsecaseInterface)(nil).Logout), ctx, req)
+}
+
+// LogoutAll mocks base method.
+func (m *MockAuthUsecaseInterface) LogoutAll(ctx context.Context, req *usecase.LogoutAllRequest) (*usecase.LogoutAllResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LogoutAll", ctx, req)
+	ret0, _ := ret[0].(*usecase.LogoutAllResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LogoutAll indicates an expected call of LogoutAll.
+func (mr *MockAuthUsecaseInterfaceMockRecorder) LogoutAll(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LogoutAll", reflect.TypeOf((*MockAuthUsecaseInterface)(nil).LogoutAll), ctx, req)
+}
+
+// Refresh mocks base method.
+func (m *MockAuthUsecaseInterface) Refresh(ctx context.Context, req *usecase.RefreshRequest) (*usecase.LoginResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Refresh", ctx, req)
+	ret0, _ := ret[0].(*usecase.LoginResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Refresh indicates an expected call of Refresh.
+func (mr *MockAuthUsecaseInterfaceMockRecorder) Refresh(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Refresh", reflect.TypeOf((*MockAuthUsecaseInterface)(nil).Refresh), ctx, req)
+}
+
 // Register mocks base method.
 func (m *MockAuthUsecaseInterface) Register(ctx context.Context, req *usecase.RegisterRequest) (*usecase.RegisterResponse, error) {
 	m.ctrl.T.Helper()
@@ -2,5 +2,5 @@ package entity
 
 type ErrorResponse struct {
 	Error string `json:"error" example:"invalid request"`
-	
+	Code  string `json:"code" example:"invalid_argument"`
 }
@@ -8,4 +8,5 @@ type Session struct {
 	UserID    int64     `db:"user_id"`
 	Token     string    `db:"token"`
 	ExpiresAt time.Time `db:"expires_at"`
+	FamilyID  string    `db:"family_id"`
 }
@@ -0,0 +1,172 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/Ulyana-kru00/forum-project/internal/entity"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateRefreshToken(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+	r := &refreshTokenRepository{db: sqlxDB}
+
+	token := &entity.RefreshToken{
+		UserID:    1,
+		FamilyID:  "family",
+		TokenHash: "hash",
+		ExpiresAt: time.Now().Add(time.Hour),
+	}
+
+	mock.ExpectQuery(`INSERT INTO refresh_tokens \(user_id, family_id, token_hash, expires_at\) VALUES \(\$1, \$2, \$3, \$4\) RETURNING id`).
+		WithArgs(1, "family", "hash", sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(5))
+
+	err = r.CreateRefreshToken(context.Background(), token)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(5), token.ID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetRefreshTokenByHash(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+	r := &refreshTokenRepository{db: sqlxDB}
+
+	expiresAt := time.Now().Add(time.Hour)
+	columns := []string{"id", "user_id", "family_id", "token_hash", "expires_at", "created_at", "used_at", "revoked_at"}
+
+	t.Run("Success", func(t *testing.T) {
+		mock.ExpectQuery(`SELECT (.+) FROM refresh_tokens WHERE token_hash = \$1`).
+			WithArgs("hash").
+			WillReturnRows(sqlmock.NewRows(columns).AddRow(5, 1, "family", "hash", expiresAt, time.Now(), nil, nil))
+
+		token, err := r.GetRefreshTokenByHash(context.Background(), "hash")
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(5), token.ID)
+		assert.Equal(t, "family", token.FamilyID)
+		assert.Nil(t, token.UsedAt)
+		assert.Nil(t, token.RevokedAt)
+	})
+
+	t.Run("Not Found", func(t *testing.T) {
+		mock.ExpectQuery(`SELECT (.+) FROM refresh_tokens WHERE token_hash = \$1`).
+			WithArgs("missing").
+			WillReturnError(sql.ErrNoRows)
+
+		token, err := r.GetRefreshTokenByHash(context.Background(), "missing")
+
+		assert.Equal(t, sql.ErrNoRows, err)
+		assert.Nil(t, token)
+	})
+}
+
+func TestRotateRefreshToken(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+	r := &refreshTokenRepository{db: sqlxDB}
+
+	const (
+		consumeQuery = `UPDATE refresh_tokens SET used_at = NOW\(\) WHERE id = \$1 AND used_at IS NULL AND revoked_at IS NULL`
+		sessionQuery = `INSERT INTO sessions \(user_id, token, expires_at, family_id\) VALUES \(\$1, \$2, \$3, \$4\)`
+		tokenQuery   = `INSERT INTO refresh_tokens \(user_id, family_id, token_hash, expires_at\) VALUES \(\$1, \$2, \$3, \$4\) RETURNING id`
+	)
+
+	newPair := func() (*entity.Session, *entity.RefreshToken) {
+		expiresAt := time.Now().Add(time.Hour)
+		return &entity.Session{UserID: 1, Token: "access", ExpiresAt: expiresAt, FamilyID: "family"},
+			&entity.RefreshToken{UserID: 1, FamilyID: "family", TokenHash: "hash", ExpiresAt: expiresAt}
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec(consumeQuery).WithArgs(5).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(sessionQuery).
+			WithArgs(1, "access", sqlmock.AnyArg(), "family").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectQuery(tokenQuery).
+			WithArgs(1, "family", "hash", sqlmock.AnyArg()).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(6))
+		mock.ExpectCommit()
+
+		session, next := newPair()
+		consumed, err := r.RotateRefreshToken(context.Background(), 5, session, next)
+
+		assert.NoError(t, err)
+		assert.True(t, consumed)
+		assert.Equal(t, int64(6), next.ID)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Already used", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec(consumeQuery).WithArgs(5).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectRollback()
+
+		session, next := newPair()
+		consumed, err := r.RotateRefreshToken(context.Background(), 5, session, next)
+
+		assert.NoError(t, err)
+		assert.False(t, consumed)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Insert fails", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec(consumeQuery).WithArgs(5).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(sessionQuery).
+			WithArgs(1, "access", sqlmock.AnyArg(), "family").
+			WillReturnError(errors.New("duplicate key"))
+		mock.ExpectRollback()
+
+		session, next := newPair()
+		consumed, err := r.RotateRefreshToken(context.Background(), 5, session, next)
+
+		// The token stays unused because the transaction rolled back.
+		assert.Error(t, err)
+		assert.False(t, consumed)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestRevokeFamily(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+	r := &refreshTokenRepository{db: sqlxDB}
+
+	mock.ExpectExec(`UPDATE refresh_tokens SET revoked_at = NOW\(\) WHERE family_id = \$1 AND revoked_at IS NULL`).
+		WithArgs("family").
+		WillReturnResult(sqlmock.NewResult(0, 3))
+
+	assert.NoError(t, r.RevokeFamily(context.Background(), "family"))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
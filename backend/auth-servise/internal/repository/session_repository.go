@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"database/sql"
 
 	domain "github.com/Ulyana-kru00/forum-project/internal/entity"
 	"github.com/jmoiron/sqlx"
@@ -10,6 +11,9 @@ import (
 type SessionRepository interface {
 	CreateSession(ctx context.Context, session *domain.Session) error
 	GetSessionByToken(ctx context.Context, token string) (*domain.Session, error)
+	DeleteSession(ctx context.Context, token string) error
+	DeleteSessionsByUserID(ctx context.Context, userID int64) (int64, error)
+	DeleteSessionsByFamily(ctx context.Context, familyID string) (int64, error)
 }
 
 type sessionRepository struct {
@@ -21,8 +25,8 @@ func NewSessionRepository(db *sqlx.DB) SessionRepository {
 }
 
 func (r *sessionRepository) CreateSession(ctx context.Context, session *domain.Session) error {
-	query := `INSERT INTO sessions (user_id, token, expires_at) VALUES ($1, $2, $3)`
-	_, err := r.db.ExecContext(ctx, query, session.UserID, session.Token, session.ExpiresAt)
+	query := `INSERT INTO sessions (user_id, token, expires_at, family_id) VALUES ($1, $2, $3, $4)`
+	_, err := r.db.ExecContext(ctx, query, session.UserID, session.Token, session.ExpiresAt, session.FamilyID)
 	return err
 }
 
@@ -32,3 +36,42 @@ func (r *sessionRepository) GetSessionByToken(ctx context.Context, token string)
 	err := r.db.GetContext(ctx, session, query, token)
 	return session, err
 }
+
+func (r *sessionRepository) DeleteSession(ctx context.Context, token string) error {
+	query := `DELETE FROM sessions WHERE token = $1`
+	result, err := r.db.ExecContext(ctx, query, token)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+func (r *sessionRepository) DeleteSessionsByUserID(ctx context.Context, userID int64) (int64, error) {
+	query := `DELETE FROM sessions WHERE user_id = $1`
+	result, err := r.db.ExecContext(ctx, query, userID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// DeleteSessionsByFamily revokes the access tokens issued in one refresh
+// token family, leaving the user's other devices signed in.
+func (r *sessionRepository) DeleteSessionsByFamily(ctx context.Context, familyID string) (int64, error) {
+	query := `DELETE FROM sessions WHERE family_id = $1`
+	result, err := r.db.ExecContext(ctx, query, familyID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
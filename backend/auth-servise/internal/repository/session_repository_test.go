@@ -35,10 +35,11 @@ func TestCreateSession(t *testing.T) {
 				UserID:    1,
 				Token:     "test-token",
 				ExpiresAt: time.Now().Add(24 * time.Hour),
+				FamilyID:  "family",
 			},
 			mock: func() {
-				mock.ExpectExec(`INSERT INTO sessions \(user_id, token, expires_at\) VALUES \(\$1, \$2, \$3\)`).
-					WithArgs(1, "test-token", sqlmock.AnyArg()).
+				mock.ExpectExec(`INSERT INTO sessions \(user_id, token, expires_at, family_id\) VALUES \(\$1, \$2, \$3, \$4\)`).
+					WithArgs(1, "test-token", sqlmock.AnyArg(), "family").
 					WillReturnResult(sqlmock.NewResult(1, 1))
 			},
 			expectedErr: nil,
@@ -51,8 +52,8 @@ func TestCreateSession(t *testing.T) {
 				ExpiresAt: time.Now().Add(24 * time.Hour),
 			},
 			mock: func() {
-				mock.ExpectExec(`INSERT INTO sessions \(user_id, token, expires_at\) VALUES \(\$1, \$2, \$3\)`).
-					WithArgs(1, "", sqlmock.AnyArg()).
+				mock.ExpectExec(`INSERT INTO sessions \(user_id, token, expires_at, family_id\) VALUES \(\$1, \$2, \$3, \$4\)`).
+					WithArgs(1, "", sqlmock.AnyArg(), "").
 					WillReturnError(errors.New("empty token"))
 			},
 			expectedErr: errors.New("empty token"),
@@ -133,3 +134,102 @@ func TestGetSessionByToken(t *testing.T) {
 		})
 	}
 }
+
+func TestDeleteSession(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+	r := &sessionRepository{db: sqlxDB}
+
+	tests := []struct {
+		name        string
+		token       string
+		mock        func()
+		expectedErr error
+	}{
+		{
+			name:  "Success",
+			token: "valid-token",
+			mock: func() {
+				mock.ExpectExec(`DELETE FROM sessions WHERE token = \$1`).
+					WithArgs("valid-token").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			expectedErr: nil,
+		},
+		{
+			name:  "Not Found",
+			token: "unknown-token",
+			mock: func() {
+				mock.ExpectExec(`DELETE FROM sessions WHERE token = \$1`).
+					WithArgs("unknown-token").
+					WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			expectedErr: sql.ErrNoRows,
+		},
+		{
+			name:  "DB Error",
+			token: "valid-token",
+			mock: func() {
+				mock.ExpectExec(`DELETE FROM sessions WHERE token = \$1`).
+					WithArgs("valid-token").
+					WillReturnError(errors.New("db error"))
+			},
+			expectedErr: errors.New("db error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+			err := r.DeleteSession(context.Background(), tt.token)
+			assert.Equal(t, tt.expectedErr, err)
+		})
+	}
+}
+
+func TestDeleteSessionsByUserID(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+	r := &sessionRepository{db: sqlxDB}
+
+	mock.ExpectExec(`DELETE FROM sessions WHERE user_id = \$1`).
+		WithArgs(1).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+
+	revoked, err := r.DeleteSessionsByUserID(context.Background(), 1)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), revoked)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDeleteSessionsByFamily(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+	r := &sessionRepository{db: sqlxDB}
+
+	mock.ExpectExec(`DELETE FROM sessions WHERE family_id = \$1`).
+		WithArgs("family").
+		WillReturnResult(sqlmock.NewResult(0, 2))
+
+	revoked, err := r.DeleteSessionsByFamily(context.Background(), "family")
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), revoked)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
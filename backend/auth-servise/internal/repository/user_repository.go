@@ -7,12 +7,19 @@ import (
 
 	domain "github.com/Ulyana-kru00/forum-project/internal/entity"
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 )
 
+var ErrUserAlreadyExists = errors.New("user already exists")
+
+// uniqueViolation is the PostgreSQL SQLSTATE for a unique constraint failure.
+const uniqueViolation = "23505"
+
 type UserRepository interface {
 	CreateUser(ctx context.Context, user *domain.User) (int64, error)
 	GetUserByUsername(ctx context.Context, username string) (*domain.User, error)
 	GetUserByID(ctx context.Context, id int64) (*domain.User, error) // Добавьте этот метод
+	GetUsersByIDs(ctx context.Context, ids []int64) ([]*domain.User, error)
 }
 
 type userRepository struct {
@@ -28,6 +35,10 @@ func (r *userRepository) CreateUser(ctx context.Context, user *domain.User) (int
 	var id int64
 	err := r.db.QueryRowContext(ctx, query, user.Username, user.Password, user.Role, user.CreatedAt).Scan(&id)
 	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == uniqueViolation {
+			return 0, ErrUserAlreadyExists
+		}
 		return 0, err
 	}
 	return id, nil
@@ -57,3 +68,18 @@ func (r *userRepository) GetUserByID(ctx context.Context, id int64) (*domain.Use
 	}
 	return user, nil
 }
+
+// GetUsersByIDs returns the users that exist among ids in a single query.
+// Unknown ids are skipped, so the result may be shorter than ids.
+func (r *userRepository) GetUsersByIDs(ctx context.Context, ids []int64) ([]*domain.User, error) {
+	if len(ids) == 0 {
+		return []*domain.User{}, nil
+	}
+
+	query := `SELECT id, username, password, role, created_at FROM users WHERE id = ANY($1)`
+	users := []*domain.User{}
+	if err := r.db.SelectContext(ctx, &users, query, pq.Array(ids)); err != nil {
+		return nil, err
+	}
+	return users, nil
+}
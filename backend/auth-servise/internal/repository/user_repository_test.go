@@ -10,6 +10,7 @@ import (
 	"github.com/DATA-DOG/go-sqlmock"
 	domain "github.com/Ulyana-kru00/forum-project/internal/entity"
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -67,6 +68,33 @@ func TestCreateUser_Error(t *testing.T) {
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
+func TestCreateUser_Duplicate(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+	repo := NewUserRepository(sqlxDB)
+
+	user := &domain.User{
+		Username:  "testuser",
+		Password:  "password",
+		Role:      "user",
+		CreatedAt: time.Now(),
+	}
+
+	mock.ExpectQuery("INSERT INTO users").
+		WithArgs(user.Username, user.Password, user.Role, user.CreatedAt).
+		WillReturnError(&pq.Error{Code: "23505", Constraint: "users_username_key"})
+
+	id, err := repo.CreateUser(context.Background(), user)
+	assert.ErrorIs(t, err, ErrUserAlreadyExists)
+	assert.Equal(t, int64(0), id)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
 func TestGetUserByUsername_Success(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	if err != nil {
@@ -214,3 +242,34 @@ func TestGetUserByID_Error(t *testing.T) {
 	assert.Nil(t, user)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
+
+func TestGetUsersByIDs(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+	repo := NewUserRepository(sqlxDB)
+
+	now := time.Now()
+	rows := sqlmock.NewRows([]string{"id", "username", "password", "role", "created_at"}).
+		AddRow(1, "alice", "hash", "user", now).
+		AddRow(2, "bob", "hash", "admin", now)
+
+	mock.ExpectQuery(`SELECT id, username, password, role, created_at FROM users WHERE id = ANY\(\$1\)`).
+		WithArgs(pq.Array([]int64{1, 2, 3})).
+		WillReturnRows(rows)
+
+	users, err := repo.GetUsersByIDs(context.Background(), []int64{1, 2, 3})
+	assert.NoError(t, err)
+	assert.Len(t, users, 2)
+	assert.Equal(t, "alice", users[0].Username)
+	assert.Equal(t, "bob", users[1].Username)
+	assert.NoError(t, mock.ExpectationsWereMet())
+
+	users, err = repo.GetUsersByIDs(context.Background(), nil)
+	assert.NoError(t, err)
+	assert.Empty(t, users)
+}
@@ -3,9 +3,12 @@ package usecase
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"fmt"
 	"time"
 
+	"backend.com/forum/apperrors"
 	"github.com/Ulyana-kru00/forum-project/internal/entity"
 	"github.com/Ulyana-kru00/forum-project/internal/repository"
 	"github.com/Ulyana-kru00/forum-project/pkg/auth"
@@ -14,9 +17,20 @@ import (
 	"golang.org/x/crypto/bcrypt"
 )
 
+var (
+	errInvalidCredentials  = apperrors.New(apperrors.ErrUnauthenticated, "invalid username or password")
+	errInvalidRefreshToken = apperrors.New(apperrors.ErrUnauthenticated, "invalid refresh token")
+	errUserNotFound        = apperrors.New(apperrors.ErrNotFound, "user not found")
+)
+
+// maxUsersPerBatch bounds a single GetUsers call so one request cannot ask
+// the database for an unbounded id list.
+const maxUsersPerBatch = 1000
+
 type AuthUsecase struct {
 	userRepo    repository.UserRepository
 	sessionRepo repository.SessionRepository
+	refreshRepo repository.RefreshTokenRepository
 	cfg         *auth.Config
 	logger      *zap.Logger
 }
@@ -27,17 +41,23 @@ type AuthUsecaseInterface interface {
 	GetUserByID(ctx context.Context, userID int64) (*entity.User, error)
 	ValidateToken(ctx context.Context, req *ValidateTokenRequest) (*ValidateTokenResponse, error)
 	GetUser(ctx context.Context, req *GetUserRequest) (*GetUserResponse, error)
+	GetUsers(ctx context.Context, req *GetUsersRequest) (*GetUsersResponse, error)
+	Logout(ctx context.Context, req *LogoutRequest) (*LogoutResponse, error)
+	LogoutAll(ctx context.Context, req *LogoutAllRequest) (*LogoutAllResponse, error)
+	Refresh(ctx context.Context, req *RefreshRequest) (*LoginResponse, error)
 }
 
 func NewAuthUsecase(
 	userRepo repository.UserRepository,
 	sessionRepo repository.SessionRepository,
+	refreshRepo repository.RefreshTokenRepository,
 	cfg *auth.Config,
 	logger *zap.Logger,
 ) *AuthUsecase {
 	return &AuthUsecase{
 		userRepo:    userRepo,
 		sessionRepo: sessionRepo,
+		refreshRepo: refreshRepo,
 		cfg:         cfg,
 		logger:      logger,
 	}
@@ -47,6 +67,10 @@ func (uc *AuthUsecase) Register(
 	ctx context.Context,
 	req *RegisterRequest,
 ) (*RegisterResponse, error) {
+	if req.Username == "" || req.Password == "" {
+		return nil, apperrors.New(apperrors.ErrInvalidArgument, "username and password are required")
+	}
+
 	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
 	if err != nil {
 		uc.logger.Error("Failed to hash password", zap.Error(err))
@@ -62,8 +86,11 @@ func (uc *AuthUsecase) Register(
 
 	userID, err := uc.userRepo.CreateUser(ctx, user)
 	if err != nil {
+		if errors.Is(err, repository.ErrUserAlreadyExists) {
+			return nil, apperrors.New(apperrors.ErrAlreadyExists, "username already taken")
+		}
 		uc.logger.Error("Failed to create user", zap.Error(err))
-		return nil, err
+		return nil, fmt.Errorf("internal server error")
 	}
 
 	return &RegisterResponse{UserID: userID}, nil
@@ -75,19 +102,43 @@ func (uc *AuthUsecase) Login(
 ) (*LoginResponse, error) {
 	user, err := uc.userRepo.GetUserByUsername(ctx, req.Username)
 	if err != nil {
-		return nil, fmt.Errorf("invalid username or password")
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errInvalidCredentials
+		}
+		uc.logger.Error("failed to get user", zap.Error(err))
+		return nil, fmt.Errorf("internal server error")
 	}
 
 	err = bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(req.Password))
 	if err != nil {
-		return nil, fmt.Errorf("invalid username or password")
+		return nil, errInvalidCredentials
 	}
 
+	familyID, err := auth.GenerateOpaqueToken()
+	if err != nil {
+		uc.logger.Error("failed to generate token family", zap.Error(err))
+		return nil, fmt.Errorf("internal server error")
+	}
+
+	return uc.issueTokens(ctx, user, familyID, nil)
+}
+
+// issueTokens creates a short-lived access token with its session and a new
+// refresh token in the given family. When rotated is set, it is consumed in
+// the same transaction that stores the new pair.
+func (uc *AuthUsecase) issueTokens(
+	ctx context.Context,
+	user *entity.User,
+	familyID string,
+	rotated *entity.RefreshToken,
+) (*LoginResponse, error) {
+	now := time.Now()
+
 	token, err := auth.GenerateToken(
 		user.ID,
 		user.Role,
 		user.Username,
-		uc.cfg.TokenSecret,
+		uc.cfg.Keys,
 		uc.cfg.TokenExpiration,
 	)
 	if err != nil {
@@ -98,17 +149,51 @@ func (uc *AuthUsecase) Login(
 	session := &entity.Session{
 		UserID:    user.ID,
 		Token:     token,
-		ExpiresAt: time.Now().Add(uc.cfg.TokenExpiration),
+		ExpiresAt: now.Add(uc.cfg.TokenExpiration),
+		FamilyID:  familyID,
 	}
 
-	if err := uc.sessionRepo.CreateSession(ctx, session); err != nil {
-		uc.logger.Error("failed to create session", zap.Error(err))
+	refreshToken, err := auth.GenerateOpaqueToken()
+	if err != nil {
+		uc.logger.Error("failed to generate refresh token", zap.Error(err))
 		return nil, fmt.Errorf("internal server error")
 	}
 
+	stored := &entity.RefreshToken{
+		UserID:    user.ID,
+		FamilyID:  familyID,
+		TokenHash: auth.HashRefreshToken(refreshToken),
+		ExpiresAt: now.Add(uc.cfg.RefreshTokenExpiration),
+	}
+
+	if rotated != nil {
+		consumed, err := uc.refreshRepo.RotateRefreshToken(ctx, rotated.ID, session, stored)
+		if err != nil {
+			uc.logger.Error("failed to rotate refresh token", zap.Error(err))
+			return nil, fmt.Errorf("internal server error")
+		}
+		if !consumed {
+			// Another request consumed the same token concurrently.
+			return nil, uc.revokeReusedFamily(ctx, rotated)
+		}
+	} else {
+		if err := uc.sessionRepo.CreateSession(ctx, session); err != nil {
+			uc.logger.Error("failed to create session", zap.Error(err))
+			return nil, fmt.Errorf("internal server error")
+		}
+
+		if err := uc.refreshRepo.CreateRefreshToken(ctx, stored); err != nil {
+			uc.logger.Error("failed to create refresh token", zap.Error(err))
+			return nil, fmt.Errorf("internal server error")
+		}
+	}
+
 	return &LoginResponse{
-		Token:    token,
-		Username: user.Username,
+		Token:            token,
+		ExpiresAt:        session.ExpiresAt,
+		RefreshToken:     refreshToken,
+		RefreshExpiresAt: stored.ExpiresAt,
+		Username:         user.Username,
 	}, nil
 }
 
@@ -116,7 +201,15 @@ func (uc *AuthUsecase) GetUserByID(
 	ctx context.Context,
 	userID int64,
 ) (*entity.User, error) {
-	return uc.userRepo.GetUserByID(ctx, userID)
+	user, err := uc.userRepo.GetUserByID(ctx, userID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		uc.logger.Error("failed to get user", zap.Error(err))
+		return nil, fmt.Errorf("internal server error")
+	}
+	if user == nil {
+		return nil, errUserNotFound
+	}
+	return user, nil
 }
 
 func (uc *AuthUsecase) ValidateToken(
@@ -125,7 +218,7 @@ func (uc *AuthUsecase) ValidateToken(
 ) (*ValidateTokenResponse, error) {
 	uc.logger.Info("Token validation request")
 
-	token, err := auth.ParseToken(req.Token, uc.cfg.TokenSecret)
+	token, err := auth.ParseToken(req.Token, uc.cfg.Keys)
 	if err != nil || !token.Valid {
 		uc.logger.Warn("Invalid token", zap.Error(err))
 		return &ValidateTokenResponse{Valid: false}, nil
@@ -149,10 +242,30 @@ func (uc *AuthUsecase) ValidateToken(
 		return &ValidateTokenResponse{Valid: false}, nil
 	}
 
+	// A signed token is only honoured while its session exists, so logout
+	// revokes it before the JWT itself expires.
+	session, err := uc.sessionRepo.GetSessionByToken(ctx, req.Token)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			uc.logger.Warn("Session not found for token")
+			return &ValidateTokenResponse{Valid: false}, nil
+		}
+		uc.logger.Error("failed to get session", zap.Error(err))
+		return nil, fmt.Errorf("internal server error")
+	}
+
+	if time.Now().After(session.ExpiresAt) {
+		uc.logger.Warn("Session expired", zap.Int64("user_id", session.UserID))
+		return &ValidateTokenResponse{Valid: false}, nil
+	}
+
+	username, _ := claims["username"].(string)
+
 	return &ValidateTokenResponse{
-		Valid:  true,
-		UserID: int64(userID),
-		Role:   role,
+		Valid:    true,
+		UserID:   int64(userID),
+		Username: username,
+		Role:     role,
 	}, nil
 }
 
@@ -160,13 +273,182 @@ func (uc *AuthUsecase) GetUser(
 	ctx context.Context,
 	req *GetUserRequest,
 ) (*GetUserResponse, error) {
+	if req.Username != "" {
+		return uc.getUserByUsername(ctx, req.Username)
+	}
 	uc.logger.Info("Get user request", zap.Int64("user_id", req.UserID))
 
 	user, err := uc.userRepo.GetUserByID(ctx, req.UserID)
-	if err != nil || user == nil {
-		uc.logger.Error("User not found", zap.Error(err))
-		return nil, fmt.Errorf("user not found")
+	if err != nil {
+		uc.logger.Error("failed to get user", zap.Error(err))
+		return nil, fmt.Errorf("internal server error")
+	}
+	if user == nil {
+		uc.logger.Error("User not found", zap.Int64("user_id", req.UserID))
+		return nil, errUserNotFound
 	}
 
 	return &GetUserResponse{User: user}, nil
 }
+
+func (uc *AuthUsecase) getUserByUsername(ctx context.Context, username string) (*GetUserResponse, error) {
+	uc.logger.Info("Get user request", zap.String("username", username))
+
+	user, err := uc.userRepo.GetUserByUsername(ctx, username)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, errUserNotFound
+	}
+	if err != nil {
+		uc.logger.Error("failed to get user", zap.Error(err))
+		return nil, fmt.Errorf("internal server error")
+	}
+
+	return &GetUserResponse{User: user}, nil
+}
+
+// GetUsers resolves many users in one query. Duplicate ids are collapsed and
+// unknown ids are left out of the response rather than failing the batch.
+func (uc *AuthUsecase) GetUsers(
+	ctx context.Context,
+	req *GetUsersRequest,
+) (*GetUsersResponse, error) {
+	seen := make(map[int64]struct{}, len(req.UserIDs))
+	ids := make([]int64, 0, len(req.UserIDs))
+	for _, id := range req.UserIDs {
+		if _, ok := seen[id]; ok {
+			continue
+		}
+		seen[id] = struct{}{}
+		ids = append(ids, id)
+	}
+
+	if len(ids) > maxUsersPerBatch {
+		return nil, apperrors.New(apperrors.ErrInvalidArgument,
+			fmt.Sprintf("at most %d user ids per request", maxUsersPerBatch))
+	}
+
+	users, err := uc.userRepo.GetUsersByIDs(ctx, ids)
+	if err != nil {
+		uc.logger.Error("failed to get users", zap.Error(err))
+		return nil, fmt.Errorf("internal server error")
+	}
+
+	return &GetUsersResponse{Users: users}, nil
+}
+
+func (uc *AuthUsecase) Logout(
+	ctx context.Context,
+	req *LogoutRequest,
+) (*LogoutResponse, error) {
+	if req.RefreshToken != "" {
+		stored, err := uc.refreshRepo.GetRefreshTokenByHash(ctx, auth.HashRefreshToken(req.RefreshToken))
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			uc.logger.Error("failed to get refresh token", zap.Error(err))
+			return nil, fmt.Errorf("internal server error")
+		}
+		if stored != nil {
+			if err := uc.refreshRepo.RevokeFamily(ctx, stored.FamilyID); err != nil {
+				uc.logger.Error("failed to revoke refresh token family", zap.Error(err))
+				return nil, fmt.Errorf("internal server error")
+			}
+		}
+	}
+
+	if err := uc.sessionRepo.DeleteSession(ctx, req.Token); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, apperrors.New(apperrors.ErrUnauthenticated, "session not found")
+		}
+		uc.logger.Error("failed to delete session", zap.Error(err))
+		return nil, fmt.Errorf("internal server error")
+	}
+
+	return &LogoutResponse{Success: true}, nil
+}
+
+func (uc *AuthUsecase) LogoutAll(
+	ctx context.Context,
+	req *LogoutAllRequest,
+) (*LogoutAllResponse, error) {
+	validateResp, err := uc.ValidateToken(ctx, &ValidateTokenRequest{Token: req.Token})
+	if err != nil {
+		return nil, err
+	}
+	if !validateResp.Valid {
+		return nil, apperrors.New(apperrors.ErrUnauthenticated, "invalid token")
+	}
+
+	if err := uc.refreshRepo.RevokeByUserID(ctx, validateResp.UserID); err != nil {
+		uc.logger.Error("failed to revoke refresh tokens", zap.Error(err))
+		return nil, fmt.Errorf("internal server error")
+	}
+
+	revoked, err := uc.sessionRepo.DeleteSessionsByUserID(ctx, validateResp.UserID)
+	if err != nil {
+		uc.logger.Error("failed to delete sessions", zap.Error(err))
+		return nil, fmt.Errorf("internal server error")
+	}
+
+	uc.logger.Info("User sessions revoked",
+		zap.Int64("user_id", validateResp.UserID),
+		zap.Int64("revoked", revoked),
+	)
+
+	return &LogoutAllResponse{RevokedSessions: revoked}, nil
+}
+
+// Refresh exchanges a refresh token for a new access/refresh pair. Every
+// refresh token is single-use: presenting one that was already consumed
+// means it leaked, so the whole family is revoked.
+func (uc *AuthUsecase) Refresh(
+	ctx context.Context,
+	req *RefreshRequest,
+) (*LoginResponse, error) {
+	stored, err := uc.refreshRepo.GetRefreshTokenByHash(ctx, auth.HashRefreshToken(req.RefreshToken))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errInvalidRefreshToken
+		}
+		uc.logger.Error("failed to get refresh token", zap.Error(err))
+		return nil, fmt.Errorf("internal server error")
+	}
+
+	if stored.RevokedAt != nil {
+		return nil, errInvalidRefreshToken
+	}
+
+	if stored.UsedAt != nil {
+		return nil, uc.revokeReusedFamily(ctx, stored)
+	}
+
+	if time.Now().After(stored.ExpiresAt) {
+		return nil, apperrors.New(apperrors.ErrUnauthenticated, "refresh token expired")
+	}
+
+	user, err := uc.userRepo.GetUserByID(ctx, stored.UserID)
+	if err != nil || user == nil {
+		uc.logger.Error("User not found for refresh token", zap.Error(err))
+		return nil, errInvalidRefreshToken
+	}
+
+	return uc.issueTokens(ctx, user, stored.FamilyID, stored)
+}
+
+func (uc *AuthUsecase) revokeReusedFamily(ctx context.Context, stored *entity.RefreshToken) error {
+	uc.logger.Warn("Refresh token reuse detected, revoking family",
+		zap.Int64("user_id", stored.UserID),
+		zap.String("family_id", stored.FamilyID),
+	)
+
+	if err := uc.refreshRepo.RevokeFamily(ctx, stored.FamilyID); err != nil {
+		uc.logger.Error("failed to revoke refresh token family", zap.Error(err))
+		return fmt.Errorf("internal server error")
+	}
+
+	// Access tokens already issued in the family may be the attacker's.
+	if _, err := uc.sessionRepo.DeleteSessionsByFamily(ctx, stored.FamilyID); err != nil {
+		uc.logger.Error("failed to delete family sessions", zap.Error(err))
+		return fmt.Errorf("internal server error")
+	}
+
+	return apperrors.New(apperrors.ErrUnauthenticated, "refresh token reuse detected")
+}
@@ -7,7 +7,9 @@ import (
 	"testing"
 	"time"
 
+	"backend.com/forum/apperrors"
 	"github.com/Ulyana-kru00/forum-project/internal/entity"
+	"github.com/Ulyana-kru00/forum-project/internal/repository"
 	"github.com/Ulyana-kru00/forum-project/pkg/auth"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -42,6 +44,14 @@ func (m *MockUserRepo) GetUserByID(ctx context.Context, id int64) (*entity.User,
 	return args.Get(0).(*entity.User), args.Error(1)
 }
 
+func (m *MockUserRepo) GetUsersByIDs(ctx context.Context, ids []int64) ([]*entity.User, error) {
+	args := m.Called(ctx, ids)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entity.User), args.Error(1)
+}
+
 type MockSessionRepo struct {
 	mock.Mock
 }
@@ -59,17 +69,83 @@ func (m *MockSessionRepo) GetSessionByToken(ctx context.Context, token string) (
 	return args.Get(0).(*entity.Session), args.Error(1)
 }
 
+func (m *MockSessionRepo) DeleteSession(ctx context.Context, token string) error {
+	args := m.Called(ctx, token)
+	return args.Error(0)
+}
+
+func (m *MockSessionRepo) DeleteSessionsByUserID(ctx context.Context, userID int64) (int64, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockSessionRepo) DeleteSessionsByFamily(ctx context.Context, familyID string) (int64, error) {
+	args := m.Called(ctx, familyID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+type MockRefreshTokenRepo struct {
+	mock.Mock
+}
+
+func (m *MockRefreshTokenRepo) CreateRefreshToken(ctx context.Context, token *entity.RefreshToken) error {
+	args := m.Called(ctx, token)
+	return args.Error(0)
+}
+
+func (m *MockRefreshTokenRepo) GetRefreshTokenByHash(ctx context.Context, tokenHash string) (*entity.RefreshToken, error) {
+	args := m.Called(ctx, tokenHash)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.RefreshToken), args.Error(1)
+}
+
+func (m *MockRefreshTokenRepo) RotateRefreshToken(ctx context.Context, usedID int64, session *entity.Session, next *entity.RefreshToken) (bool, error) {
+	args := m.Called(ctx, usedID, session, next)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockRefreshTokenRepo) RevokeFamily(ctx context.Context, familyID string) error {
+	args := m.Called(ctx, familyID)
+	return args.Error(0)
+}
+
+func (m *MockRefreshTokenRepo) RevokeByUserID(ctx context.Context, userID int64) error {
+	args := m.Called(ctx, userID)
+	return args.Error(0)
+}
+
+func newTestKeySet(t *testing.T) *auth.KeySet {
+	key, err := auth.GenerateSigningKey("test-key", auth.AlgEdDSA)
+	if err != nil {
+		t.Fatalf("failed to generate signing key: %v", err)
+	}
+	keys, err := auth.NewKeySet("", key)
+	if err != nil {
+		t.Fatalf("failed to build key set: %v", err)
+	}
+	return keys
+}
+
 func setupTest(t *testing.T) (*AuthUsecase, *MockUserRepo, *MockSessionRepo) {
+	uc, userRepo, sessionRepo, _ := setupTestWithRefreshRepo(t)
+	return uc, userRepo, sessionRepo
+}
+
+func setupTestWithRefreshRepo(t *testing.T) (*AuthUsecase, *MockUserRepo, *MockSessionRepo, *MockRefreshTokenRepo) {
 	userRepo := new(MockUserRepo)
 	sessionRepo := new(MockSessionRepo)
+	refreshRepo := new(MockRefreshTokenRepo)
 	cfg := &auth.Config{
-		TokenSecret:     "test-secret",
-		TokenExpiration: time.Hour,
+		Keys:                   newTestKeySet(t),
+		TokenExpiration:        time.Hour,
+		RefreshTokenExpiration: 24 * time.Hour,
 	}
 
 	logger := zaptest.NewLogger(t)
 
-	return NewAuthUsecase(userRepo, sessionRepo, cfg, logger), userRepo, sessionRepo
+	return NewAuthUsecase(userRepo, sessionRepo, refreshRepo, cfg, logger), userRepo, sessionRepo, refreshRepo
 }
 
 func TestGetUserByID_Success(t *testing.T) {
@@ -142,8 +218,38 @@ func TestRegister_DBError(t *testing.T) {
 	userRepo.AssertExpectations(t)
 }
 
+func TestRegister_UsernameTaken(t *testing.T) {
+	uc, userRepo, _ := setupTest(t)
+	ctx := context.Background()
+
+	req := &RegisterRequest{
+		Username: "testuser",
+		Password: "password123",
+	}
+
+	userRepo.On("CreateUser", ctx, mock.AnythingOfType("*entity.User")).
+		Return(int64(0), repository.ErrUserAlreadyExists)
+
+	resp, err := uc.Register(ctx, req)
+
+	assert.True(t, errors.Is(err, apperrors.ErrAlreadyExists))
+	assert.Equal(t, "username already taken", err.Error())
+	assert.Nil(t, resp)
+	userRepo.AssertExpectations(t)
+}
+
+func TestRegister_EmptyCredentials(t *testing.T) {
+	uc, userRepo, _ := setupTest(t)
+
+	resp, err := uc.Register(context.Background(), &RegisterRequest{Username: "testuser"})
+
+	assert.True(t, errors.Is(err, apperrors.ErrInvalidArgument))
+	assert.Nil(t, resp)
+	userRepo.AssertNotCalled(t, "CreateUser", mock.Anything, mock.Anything)
+}
+
 func TestLogin_Success(t *testing.T) {
-	uc, userRepo, sessionRepo := setupTest(t)
+	uc, userRepo, sessionRepo, refreshRepo := setupTestWithRefreshRepo(t)
 	ctx := context.Background()
 
 	password := "password123"
@@ -157,6 +263,7 @@ func TestLogin_Success(t *testing.T) {
 
 	userRepo.On("GetUserByUsername", ctx, "testuser").Return(user, nil)
 	sessionRepo.On("CreateSession", ctx, mock.AnythingOfType("*entity.Session")).Return(nil)
+	refreshRepo.On("CreateRefreshToken", ctx, mock.AnythingOfType("*entity.RefreshToken")).Return(nil)
 
 	req := &LoginRequest{
 		Username: "testuser",
@@ -167,9 +274,16 @@ func TestLogin_Success(t *testing.T) {
 
 	assert.NoError(t, err)
 	assert.NotEmpty(t, resp.Token)
+	assert.NotEmpty(t, resp.RefreshToken)
+	assert.True(t, resp.RefreshExpiresAt.After(resp.ExpiresAt))
 	assert.Equal(t, "testuser", resp.Username)
 	userRepo.AssertExpectations(t)
 	sessionRepo.AssertExpectations(t)
+	refreshRepo.AssertExpectations(t)
+
+	stored := refreshRepo.Calls[0].Arguments.Get(1).(*entity.RefreshToken)
+	assert.Equal(t, auth.HashRefreshToken(resp.RefreshToken), stored.TokenHash)
+	assert.NotEqual(t, resp.RefreshToken, stored.TokenHash)
 }
 
 func TestLogin_UserNotFound(t *testing.T) {
@@ -185,7 +299,7 @@ func TestLogin_UserNotFound(t *testing.T) {
 
 	resp, err := uc.Login(ctx, req)
 
-	assert.Error(t, err)
+	assert.True(t, errors.Is(err, apperrors.ErrUnauthenticated))
 	assert.Equal(t, "invalid username or password", err.Error())
 	assert.Nil(t, resp)
 	userRepo.AssertExpectations(t)
@@ -211,7 +325,7 @@ func TestLogin_InvalidPassword(t *testing.T) {
 
 	resp, err := uc.Login(ctx, req)
 
-	assert.Error(t, err)
+	assert.True(t, errors.Is(err, apperrors.ErrUnauthenticated))
 	assert.Equal(t, "invalid username or password", err.Error())
 	assert.Nil(t, resp)
 	userRepo.AssertExpectations(t)
@@ -282,6 +396,25 @@ func TestGetUser_NotFound(t *testing.T) {
 	userRepo.AssertExpectations(t)
 }
 
+func TestGetUser_ByUsername(t *testing.T) {
+	uc, userRepo, _ := setupTest(t)
+	ctx := context.Background()
+
+	user := &entity.User{ID: 2, Username: "alice", Role: "user"}
+	userRepo.On("GetUserByUsername", ctx, "alice").Return(user, nil)
+	userRepo.On("GetUserByUsername", ctx, "nobody").Return(nil, sql.ErrNoRows)
+
+	resp, err := uc.GetUser(ctx, &GetUserRequest{Username: "alice"})
+	assert.NoError(t, err)
+	assert.Equal(t, user, resp.User)
+
+	resp, err = uc.GetUser(ctx, &GetUserRequest{Username: "nobody"})
+	assert.Error(t, err)
+	assert.Equal(t, "user not found", err.Error())
+	assert.Nil(t, resp)
+	userRepo.AssertExpectations(t)
+}
+
 func TestGetUser_DBError(t *testing.T) {
 	uc, userRepo, _ := setupTest(t)
 	ctx := context.Background()
@@ -292,7 +425,7 @@ func TestGetUser_DBError(t *testing.T) {
 	resp, err := uc.GetUser(ctx, req)
 
 	assert.Error(t, err)
-	assert.Equal(t, "user not found", err.Error())
+	assert.Equal(t, "internal server error", err.Error())
 	assert.Nil(t, resp)
 	userRepo.AssertExpectations(t)
 }
@@ -346,7 +479,7 @@ func TestGetUser_Logging(t *testing.T) {
 		resp, err := uc.GetUser(ctx, req)
 
 		assert.Error(t, err)
-		assert.Equal(t, "user not found", err.Error())
+		assert.Equal(t, "internal server error", err.Error())
 		assert.Nil(t, resp)
 		userRepo.AssertExpectations(t)
 
@@ -356,14 +489,14 @@ func setupTestWithLogObserver(t *testing.T) (*AuthUsecase, *MockUserRepo, *MockS
 	userRepo := new(MockUserRepo)
 	sessionRepo := new(MockSessionRepo)
 	cfg := &auth.Config{
-		TokenSecret:     "test-secret",
+		Keys:            newTestKeySet(t),
 		TokenExpiration: time.Hour,
 	}
 
 	core, recorded := observer.New(zap.InfoLevel)
 	logger := zap.New(core)
 
-	return NewAuthUsecase(userRepo, sessionRepo, cfg, logger), userRepo, sessionRepo, recorded
+	return NewAuthUsecase(userRepo, sessionRepo, new(MockRefreshTokenRepo), cfg, logger), userRepo, sessionRepo, recorded
 }
 
 func TestGetUser_LoggingWithObserver(t *testing.T) {
@@ -421,17 +554,63 @@ func TestGetUser_LoggingWithObserver(t *testing.T) {
 		resp, err := uc.GetUser(ctx, req)
 
 		assert.Error(t, err)
-		assert.Equal(t, "user not found", err.Error())
+		assert.Equal(t, "internal server error", err.Error())
 		assert.Nil(t, resp)
 		userRepo.AssertExpectations(t)
 
 		assert.Equal(t, 2, logs.Len())
-		assert.Contains(t, logs.All()[1].Message, "User not found")
+		assert.Contains(t, logs.All()[1].Message, "failed to get user")
 		assert.Equal(t, zap.ErrorLevel, logs.All()[1].Level)
 		assert.Equal(t, dbError, logs.All()[1].Context[0].Interface.(error))
 	})
 }
 
+func TestGetUsers(t *testing.T) {
+	t.Run("deduplicates ids", func(t *testing.T) {
+		uc, userRepo, _ := setupTest(t)
+		ctx := context.Background()
+
+		users := []*entity.User{
+			{ID: 1, Username: "alice"},
+			{ID: 2, Username: "bob"},
+		}
+		userRepo.On("GetUsersByIDs", ctx, []int64{1, 2, 3}).Return(users, nil)
+
+		resp, err := uc.GetUsers(ctx, &GetUsersRequest{UserIDs: []int64{1, 2, 1, 3, 2}})
+
+		assert.NoError(t, err)
+		assert.Equal(t, users, resp.Users)
+		userRepo.AssertExpectations(t)
+	})
+
+	t.Run("too many ids", func(t *testing.T) {
+		uc, userRepo, _ := setupTest(t)
+
+		ids := make([]int64, maxUsersPerBatch+1)
+		for i := range ids {
+			ids[i] = int64(i + 1)
+		}
+
+		resp, err := uc.GetUsers(context.Background(), &GetUsersRequest{UserIDs: ids})
+
+		assert.True(t, errors.Is(err, apperrors.ErrInvalidArgument))
+		assert.Nil(t, resp)
+		userRepo.AssertNotCalled(t, "GetUsersByIDs", mock.Anything, mock.Anything)
+	})
+
+	t.Run("db error", func(t *testing.T) {
+		uc, userRepo, _ := setupTest(t)
+		ctx := context.Background()
+
+		userRepo.On("GetUsersByIDs", ctx, []int64{1}).Return(nil, errors.New("db error"))
+
+		resp, err := uc.GetUsers(ctx, &GetUsersRequest{UserIDs: []int64{1}})
+
+		assert.EqualError(t, err, "internal server error")
+		assert.Nil(t, resp)
+	})
+}
+
 func TestGetUserByID_NotFound(t *testing.T) {
 	uc, userRepo, _ := setupTest(t)
 	ctx := context.Background()
@@ -441,7 +620,284 @@ func TestGetUserByID_NotFound(t *testing.T) {
 	user, err := uc.GetUserByID(ctx, 1) // Изменено: передаем int64 вместо строки
 
 	assert.Error(t, err)
-	assert.True(t, errors.Is(err, sql.ErrNoRows))
+	assert.True(t, errors.Is(err, apperrors.ErrNotFound))
 	assert.Nil(t, user)
 	userRepo.AssertExpectations(t)
 }
+
+func TestValidateToken_ActiveSession(t *testing.T) {
+	uc, _, sessionRepo := setupTest(t)
+	ctx := context.Background()
+
+	token, err := auth.GenerateToken(1, "user", "testuser", uc.cfg.Keys, time.Hour)
+	assert.NoError(t, err)
+
+	sessionRepo.On("GetSessionByToken", ctx, token).Return(&entity.Session{
+		UserID:    1,
+		Token:     token,
+		ExpiresAt: time.Now().Add(time.Hour),
+	}, nil)
+
+	resp, err := uc.ValidateToken(ctx, &ValidateTokenRequest{Token: token})
+
+	assert.NoError(t, err)
+	assert.True(t, resp.Valid)
+	assert.Equal(t, int64(1), resp.UserID)
+	assert.Equal(t, "testuser", resp.Username)
+	assert.Equal(t, "user", resp.Role)
+	sessionRepo.AssertExpectations(t)
+}
+
+func TestValidateToken_RevokedSession(t *testing.T) {
+	uc, _, sessionRepo := setupTest(t)
+	ctx := context.Background()
+
+	token, err := auth.GenerateToken(1, "user", "testuser", uc.cfg.Keys, time.Hour)
+	assert.NoError(t, err)
+
+	sessionRepo.On("GetSessionByToken", ctx, token).Return(nil, sql.ErrNoRows)
+
+	resp, err := uc.ValidateToken(ctx, &ValidateTokenRequest{Token: token})
+
+	assert.NoError(t, err)
+	assert.False(t, resp.Valid)
+	sessionRepo.AssertExpectations(t)
+}
+
+func TestValidateToken_ExpiredSession(t *testing.T) {
+	uc, _, sessionRepo := setupTest(t)
+	ctx := context.Background()
+
+	token, err := auth.GenerateToken(1, "user", "testuser", uc.cfg.Keys, time.Hour)
+	assert.NoError(t, err)
+
+	sessionRepo.On("GetSessionByToken", ctx, token).Return(&entity.Session{
+		UserID:    1,
+		Token:     token,
+		ExpiresAt: time.Now().Add(-time.Minute),
+	}, nil)
+
+	resp, err := uc.ValidateToken(ctx, &ValidateTokenRequest{Token: token})
+
+	assert.NoError(t, err)
+	assert.False(t, resp.Valid)
+	sessionRepo.AssertExpectations(t)
+}
+
+func TestLogout_RevokesRefreshFamily(t *testing.T) {
+	uc, _, sessionRepo, refreshRepo := setupTestWithRefreshRepo(t)
+	ctx := context.Background()
+
+	refreshRepo.On("GetRefreshTokenByHash", ctx, auth.HashRefreshToken("refresh")).Return(&entity.RefreshToken{
+		ID:       7,
+		UserID:   1,
+		FamilyID: "family",
+	}, nil)
+	refreshRepo.On("RevokeFamily", ctx, "family").Return(nil)
+	sessionRepo.On("DeleteSession", ctx, "token").Return(nil)
+
+	resp, err := uc.Logout(ctx, &LogoutRequest{Token: "token", RefreshToken: "refresh"})
+
+	assert.NoError(t, err)
+	assert.True(t, resp.Success)
+	sessionRepo.AssertExpectations(t)
+	refreshRepo.AssertExpectations(t)
+}
+
+func TestLogout_Success(t *testing.T) {
+	uc, _, sessionRepo := setupTest(t)
+	ctx := context.Background()
+
+	sessionRepo.On("DeleteSession", ctx, "token").Return(nil)
+
+	resp, err := uc.Logout(ctx, &LogoutRequest{Token: "token"})
+
+	assert.NoError(t, err)
+	assert.True(t, resp.Success)
+	sessionRepo.AssertExpectations(t)
+}
+
+func TestLogout_SessionNotFound(t *testing.T) {
+	uc, _, sessionRepo := setupTest(t)
+	ctx := context.Background()
+
+	sessionRepo.On("DeleteSession", ctx, "token").Return(sql.ErrNoRows)
+
+	resp, err := uc.Logout(ctx, &LogoutRequest{Token: "token"})
+
+	assert.Error(t, err)
+	assert.Equal(t, "session not found", err.Error())
+	assert.Nil(t, resp)
+	sessionRepo.AssertExpectations(t)
+}
+
+func TestLogoutAll_Success(t *testing.T) {
+	uc, _, sessionRepo, refreshRepo := setupTestWithRefreshRepo(t)
+	ctx := context.Background()
+
+	token, err := auth.GenerateToken(1, "user", "testuser", uc.cfg.Keys, time.Hour)
+	assert.NoError(t, err)
+
+	sessionRepo.On("GetSessionByToken", ctx, token).Return(&entity.Session{
+		UserID:    1,
+		Token:     token,
+		ExpiresAt: time.Now().Add(time.Hour),
+	}, nil)
+	refreshRepo.On("RevokeByUserID", ctx, int64(1)).Return(nil)
+	sessionRepo.On("DeleteSessionsByUserID", ctx, int64(1)).Return(int64(3), nil)
+
+	resp, err := uc.LogoutAll(ctx, &LogoutAllRequest{Token: token})
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), resp.RevokedSessions)
+	sessionRepo.AssertExpectations(t)
+}
+
+func TestLogoutAll_InvalidToken(t *testing.T) {
+	uc, _, sessionRepo := setupTest(t)
+	ctx := context.Background()
+
+	resp, err := uc.LogoutAll(ctx, &LogoutAllRequest{Token: "garbage"})
+
+	assert.Error(t, err)
+	assert.Equal(t, "invalid token", err.Error())
+	assert.Nil(t, resp)
+	sessionRepo.AssertNotCalled(t, "DeleteSessionsByUserID", mock.Anything, mock.Anything)
+}
+
+func TestRefresh_Success(t *testing.T) {
+	uc, userRepo, sessionRepo, refreshRepo := setupTestWithRefreshRepo(t)
+	ctx := context.Background()
+
+	refreshRepo.On("GetRefreshTokenByHash", ctx, auth.HashRefreshToken("refresh")).Return(&entity.RefreshToken{
+		ID:        7,
+		UserID:    1,
+		FamilyID:  "family",
+		ExpiresAt: time.Now().Add(time.Hour),
+	}, nil)
+	userRepo.On("GetUserByID", ctx, int64(1)).Return(&entity.User{ID: 1, Username: "testuser", Role: "user"}, nil)
+	refreshRepo.On("RotateRefreshToken", ctx, int64(7),
+		mock.MatchedBy(func(session *entity.Session) bool {
+			return session.FamilyID == "family" && session.UserID == 1
+		}),
+		mock.MatchedBy(func(token *entity.RefreshToken) bool {
+			return token.FamilyID == "family" && token.UserID == 1
+		}),
+	).Return(true, nil)
+
+	resp, err := uc.Refresh(ctx, &RefreshRequest{RefreshToken: "refresh"})
+
+	assert.NoError(t, err)
+	assert.NotEmpty(t, resp.Token)
+	assert.NotEmpty(t, resp.RefreshToken)
+	assert.NotEqual(t, "refresh", resp.RefreshToken)
+	assert.Equal(t, "testuser", resp.Username)
+	userRepo.AssertExpectations(t)
+	sessionRepo.AssertExpectations(t)
+	refreshRepo.AssertExpectations(t)
+}
+
+func TestRefresh_UnknownToken(t *testing.T) {
+	uc, _, _, refreshRepo := setupTestWithRefreshRepo(t)
+	ctx := context.Background()
+
+	refreshRepo.On("GetRefreshTokenByHash", ctx, auth.HashRefreshToken("unknown")).Return(nil, sql.ErrNoRows)
+
+	resp, err := uc.Refresh(ctx, &RefreshRequest{RefreshToken: "unknown"})
+
+	assert.Error(t, err)
+	assert.Equal(t, "invalid refresh token", err.Error())
+	assert.Nil(t, resp)
+	refreshRepo.AssertExpectations(t)
+}
+
+func TestRefresh_ReuseRevokesFamily(t *testing.T) {
+	uc, _, sessionRepo, refreshRepo := setupTestWithRefreshRepo(t)
+	ctx := context.Background()
+
+	usedAt := time.Now().Add(-time.Minute)
+	refreshRepo.On("GetRefreshTokenByHash", ctx, auth.HashRefreshToken("stolen")).Return(&entity.RefreshToken{
+		ID:        7,
+		UserID:    1,
+		FamilyID:  "family",
+		ExpiresAt: time.Now().Add(time.Hour),
+		UsedAt:    &usedAt,
+	}, nil)
+	refreshRepo.On("RevokeFamily", ctx, "family").Return(nil)
+	sessionRepo.On("DeleteSessionsByFamily", ctx, "family").Return(int64(2), nil)
+
+	resp, err := uc.Refresh(ctx, &RefreshRequest{RefreshToken: "stolen"})
+
+	assert.Error(t, err)
+	assert.Equal(t, "refresh token reuse detected", err.Error())
+	assert.Nil(t, resp)
+	refreshRepo.AssertExpectations(t)
+	sessionRepo.AssertExpectations(t)
+	refreshRepo.AssertNotCalled(t, "RotateRefreshToken", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestRefresh_ConcurrentUseRevokesFamily(t *testing.T) {
+	uc, userRepo, sessionRepo, refreshRepo := setupTestWithRefreshRepo(t)
+	ctx := context.Background()
+
+	refreshRepo.On("GetRefreshTokenByHash", ctx, auth.HashRefreshToken("refresh")).Return(&entity.RefreshToken{
+		ID:        7,
+		UserID:    1,
+		FamilyID:  "family",
+		ExpiresAt: time.Now().Add(time.Hour),
+	}, nil)
+	userRepo.On("GetUserByID", ctx, int64(1)).Return(&entity.User{ID: 1, Username: "testuser", Role: "user"}, nil)
+	refreshRepo.On("RotateRefreshToken", ctx, int64(7), mock.Anything, mock.Anything).Return(false, nil)
+	refreshRepo.On("RevokeFamily", ctx, "family").Return(nil)
+	sessionRepo.On("DeleteSessionsByFamily", ctx, "family").Return(int64(1), nil)
+
+	resp, err := uc.Refresh(ctx, &RefreshRequest{RefreshToken: "refresh"})
+
+	assert.Error(t, err)
+	assert.Equal(t, "refresh token reuse detected", err.Error())
+	assert.Nil(t, resp)
+	refreshRepo.AssertExpectations(t)
+	sessionRepo.AssertExpectations(t)
+}
+
+func TestRefresh_StoreFailureKeepsFamily(t *testing.T) {
+	uc, userRepo, sessionRepo, refreshRepo := setupTestWithRefreshRepo(t)
+	ctx := context.Background()
+
+	refreshRepo.On("GetRefreshTokenByHash", ctx, auth.HashRefreshToken("refresh")).Return(&entity.RefreshToken{
+		ID:        7,
+		UserID:    1,
+		FamilyID:  "family",
+		ExpiresAt: time.Now().Add(time.Hour),
+	}, nil)
+	userRepo.On("GetUserByID", ctx, int64(1)).Return(&entity.User{ID: 1, Username: "testuser", Role: "user"}, nil)
+	refreshRepo.On("RotateRefreshToken", ctx, int64(7), mock.Anything, mock.Anything).Return(false, errors.New("db error"))
+
+	resp, err := uc.Refresh(ctx, &RefreshRequest{RefreshToken: "refresh"})
+
+	// The rotation rolled back, so the client may retry with the same token.
+	assert.Error(t, err)
+	assert.Equal(t, "internal server error", err.Error())
+	assert.Nil(t, resp)
+	refreshRepo.AssertNotCalled(t, "RevokeFamily", mock.Anything, mock.Anything)
+	sessionRepo.AssertNotCalled(t, "DeleteSessionsByFamily", mock.Anything, mock.Anything)
+}
+
+func TestRefresh_Expired(t *testing.T) {
+	uc, _, _, refreshRepo := setupTestWithRefreshRepo(t)
+	ctx := context.Background()
+
+	refreshRepo.On("GetRefreshTokenByHash", ctx, auth.HashRefreshToken("refresh")).Return(&entity.RefreshToken{
+		ID:        7,
+		UserID:    1,
+		FamilyID:  "family",
+		ExpiresAt: time.Now().Add(-time.Minute),
+	}, nil)
+
+	resp, err := uc.Refresh(ctx, &RefreshRequest{RefreshToken: "refresh"})
+
+	assert.Error(t, err)
+	assert.Equal(t, "refresh token expired", err.Error())
+	assert.Nil(t, resp)
+	refreshRepo.AssertNotCalled(t, "RotateRefreshToken", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
@@ -18,7 +18,27 @@ type ValidateTokenRequest struct {
 
 type GetUserRequest struct {
 	UserID int64
+	// Username, when set, looks the user up by name instead of UserID.
+	Username string
 }
+
+type GetUsersRequest struct {
+	UserIDs []int64
+}
+
+type LogoutRequest struct {
+	Token        string
+	RefreshToken string
+}
+
+type LogoutAllRequest struct {
+	Token string
+}
+
+type RefreshRequest struct {
+	RefreshToken string
+}
+
 type Config struct {
 	TokenSecret     string
 	TokenExpiration time.Duration
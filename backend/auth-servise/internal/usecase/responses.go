@@ -1,22 +1,42 @@
 package usecase
 
-import "github.com/Ulyana-kru00/forum-project/internal/entity"
+import (
+	"time"
+
+	"github.com/Ulyana-kru00/forum-project/internal/entity"
+)
 
 type RegisterResponse struct {
 	UserID int64
 }
 
 type LoginResponse struct {
-	Token    string
-	Username string
+	Token            string
+	ExpiresAt        time.Time
+	RefreshToken     string
+	RefreshExpiresAt time.Time
+	Username         string
 }
 
 type ValidateTokenResponse struct {
-	UserID int64
-	Role   string
-	Valid  bool
+	UserID   int64
+	Username string
+	Role     string
+	Valid    bool
 }
 
 type GetUserResponse struct {
 	User *entity.User
 }
+
+type GetUsersResponse struct {
+	Users []*entity.User
+}
+
+type LogoutResponse struct {
+	Success bool
+}
+
+type LogoutAllResponse struct {
+	RevokedSessions int64
+}
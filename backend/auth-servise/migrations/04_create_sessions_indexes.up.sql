@@ -0,0 +1,2 @@
+-- Индекс для отзыва всех сессий пользователя (LogoutAll)
+CREATE INDEX IF NOT EXISTS idx_sessions_user_id ON sessions(user_id);
@@ -0,0 +1 @@
+ALTER TABLE sessions ALTER COLUMN token TYPE VARCHAR(255);
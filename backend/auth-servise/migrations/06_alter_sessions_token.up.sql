@@ -0,0 +1,2 @@
+-- Асимметрично подписанные JWT (RS256) не помещаются в VARCHAR(255)
+ALTER TABLE sessions ALTER COLUMN token TYPE TEXT;
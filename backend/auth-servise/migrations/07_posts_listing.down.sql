@@ -0,0 +1,13 @@
+DROP INDEX IF EXISTS idx_comments_post_id;
+DROP INDEX IF EXISTS idx_posts_last_activity_at_id;
+DROP INDEX IF EXISTS idx_posts_comment_count_id;
+DROP INDEX IF EXISTS idx_posts_author_created_at_id;
+DROP INDEX IF EXISTS idx_posts_created_at_id;
+
+DROP TRIGGER IF EXISTS post_comment_stats ON comments;
+DROP FUNCTION IF EXISTS update_post_comment_stats();
+
+ALTER TABLE posts DROP COLUMN IF EXISTS last_activity_at;
+ALTER TABLE posts DROP COLUMN IF EXISTS comment_count;
+ALTER TABLE posts ALTER COLUMN created_at DROP NOT NULL;
+ALTER TABLE comments DROP COLUMN IF EXISTS created_at;
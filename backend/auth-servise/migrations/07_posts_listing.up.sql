@@ -0,0 +1,41 @@
+-- Денормализованные поля для сортировок "most_commented" и "recently_active".
+ALTER TABLE comments ADD COLUMN IF NOT EXISTS created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP;
+
+UPDATE posts SET created_at = CURRENT_TIMESTAMP WHERE created_at IS NULL;
+ALTER TABLE posts ALTER COLUMN created_at SET NOT NULL;
+
+ALTER TABLE posts ADD COLUMN IF NOT EXISTS comment_count INT NOT NULL DEFAULT 0;
+ALTER TABLE posts ADD COLUMN IF NOT EXISTS last_activity_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP;
+
+UPDATE posts p SET
+    comment_count = (SELECT COUNT(*) FROM comments c WHERE c.post_id = p.id),
+    last_activity_at = p.created_at;
+
+-- Счетчик комментариев и время последней активности обновляются триггером.
+CREATE OR REPLACE FUNCTION update_post_comment_stats()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP = 'INSERT' THEN
+        UPDATE posts
+        SET comment_count = comment_count + 1,
+            last_activity_at = GREATEST(last_activity_at, NEW.created_at)
+        WHERE id = NEW.post_id;
+        RETURN NEW;
+    END IF;
+
+    UPDATE posts SET comment_count = comment_count - 1 WHERE id = OLD.post_id;
+    RETURN OLD;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER post_comment_stats
+AFTER INSERT OR DELETE ON comments
+FOR EACH ROW
+EXECUTE FUNCTION update_post_comment_stats();
+
+-- Индексы под keyset-пагинацию: (ключ сортировки, id).
+CREATE INDEX IF NOT EXISTS idx_posts_created_at_id ON posts(created_at, id);
+CREATE INDEX IF NOT EXISTS idx_posts_author_created_at_id ON posts(author_id, created_at, id);
+CREATE INDEX IF NOT EXISTS idx_posts_comment_count_id ON posts(comment_count, id);
+CREATE INDEX IF NOT EXISTS idx_posts_last_activity_at_id ON posts(last_activity_at, id);
+CREATE INDEX IF NOT EXISTS idx_comments_post_id ON comments(post_id);
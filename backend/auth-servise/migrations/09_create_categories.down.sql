@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_categories_position;
+DROP INDEX IF EXISTS idx_posts_category_created_at_id;
+
+ALTER TABLE posts DROP COLUMN IF EXISTS category_id;
+DROP TABLE IF EXISTS categories;
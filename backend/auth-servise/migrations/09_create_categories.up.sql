@@ -0,0 +1,18 @@
+CREATE TABLE categories (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    slug VARCHAR(100) UNIQUE NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    position INT NOT NULL DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Категория по умолчанию: в нее попадают существующие посты и посты без category_id.
+INSERT INTO categories (name, slug, description) VALUES ('Общее', 'general', 'Обсуждения на любые темы');
+
+ALTER TABLE posts ADD COLUMN category_id INT REFERENCES categories(id) ON DELETE RESTRICT;
+UPDATE posts SET category_id = (SELECT id FROM categories WHERE slug = 'general');
+ALTER TABLE posts ALTER COLUMN category_id SET NOT NULL;
+
+CREATE INDEX IF NOT EXISTS idx_posts_category_created_at_id ON posts(category_id, created_at, id);
+CREATE INDEX IF NOT EXISTS idx_categories_position ON categories(position, id);
@@ -0,0 +1,5 @@
+-- Редактирование и удаление комментариев.
+ALTER TABLE comments ADD COLUMN IF NOT EXISTS edited_at TIMESTAMP WITH TIME ZONE;
+
+-- Удаленный комментарий, на который есть ответы, остается в дереве как "[deleted]".
+ALTER TABLE comments ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP WITH TIME ZONE;
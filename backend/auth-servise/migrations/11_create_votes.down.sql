@@ -0,0 +1,13 @@
+DROP INDEX IF EXISTS idx_posts_hot_rank_id;
+DROP INDEX IF EXISTS idx_posts_score_id;
+
+DROP TRIGGER IF EXISTS vote_score ON votes;
+DROP FUNCTION IF EXISTS update_vote_score();
+
+ALTER TABLE posts DROP COLUMN IF EXISTS hot_rank;
+DROP FUNCTION IF EXISTS post_hot_rank(INT, TIMESTAMP WITH TIME ZONE);
+
+ALTER TABLE comments DROP COLUMN IF EXISTS score;
+ALTER TABLE posts DROP COLUMN IF EXISTS score;
+
+DROP TABLE IF EXISTS votes;
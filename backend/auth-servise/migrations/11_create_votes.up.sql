@@ -0,0 +1,54 @@
+-- Голоса за посты и комментарии: один голос пользователя на объект.
+CREATE TABLE votes (
+    id SERIAL PRIMARY KEY,
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    post_id INT REFERENCES posts(id) ON DELETE CASCADE,
+    comment_id INT REFERENCES comments(id) ON DELETE CASCADE,
+    value SMALLINT NOT NULL CHECK (value IN (-1, 1)),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    -- Голос относится ровно к одному объекту
+    CHECK ((post_id IS NULL) <> (comment_id IS NULL))
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_votes_user_post ON votes(user_id, post_id) WHERE post_id IS NOT NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_votes_user_comment ON votes(user_id, comment_id) WHERE comment_id IS NOT NULL;
+
+ALTER TABLE posts ADD COLUMN IF NOT EXISTS score INT NOT NULL DEFAULT 0;
+ALTER TABLE comments ADD COLUMN IF NOT EXISTS score INT NOT NULL DEFAULT 0;
+
+-- Рейтинг для сортировки "hot": логарифм счета плюс время публикации, так что
+-- 10 голосов весят как 12.5 часов свежести. От текущего времени не зависит,
+-- поэтому хранится в колонке и индексируется.
+CREATE OR REPLACE FUNCTION post_hot_rank(score INT, created_at TIMESTAMP WITH TIME ZONE)
+RETURNS DOUBLE PRECISION AS $$
+    SELECT (sign(score) * log(greatest(abs(score), 1)) + extract(epoch FROM created_at) / 45000)::DOUBLE PRECISION;
+$$ LANGUAGE sql IMMUTABLE;
+
+ALTER TABLE posts ADD COLUMN IF NOT EXISTS hot_rank DOUBLE PRECISION
+    GENERATED ALWAYS AS (post_hot_rank(score, created_at)) STORED;
+
+-- Счета обновляются триггером при голосовании, смене и отзыве голоса.
+CREATE OR REPLACE FUNCTION update_vote_score()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP IN ('UPDATE', 'DELETE') THEN
+        UPDATE posts SET score = score - OLD.value WHERE id = OLD.post_id;
+        UPDATE comments SET score = score - OLD.value WHERE id = OLD.comment_id;
+    END IF;
+    IF TG_OP IN ('INSERT', 'UPDATE') THEN
+        UPDATE posts SET score = score + NEW.value WHERE id = NEW.post_id;
+        UPDATE comments SET score = score + NEW.value WHERE id = NEW.comment_id;
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER vote_score
+AFTER INSERT OR UPDATE OR DELETE ON votes
+FOR EACH ROW
+EXECUTE FUNCTION update_vote_score();
+
+CREATE INDEX IF NOT EXISTS idx_posts_score_id ON posts(score, id);
+CREATE INDEX IF NOT EXISTS idx_posts_hot_rank_id ON posts(hot_rank, id);
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_post_tags_tag_id_post_id;
+
+DROP TABLE IF EXISTS post_tags;
+DROP TABLE IF EXISTS tags;
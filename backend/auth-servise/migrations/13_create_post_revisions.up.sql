@@ -0,0 +1,19 @@
+-- История правок постов: каждая ревизия - состояние поста после создания или правки.
+CREATE TABLE post_revisions (
+    id SERIAL PRIMARY KEY,
+    post_id INT NOT NULL REFERENCES posts(id) ON DELETE CASCADE,
+    revision INT NOT NULL,
+    title VARCHAR(255) NOT NULL,
+    content TEXT NOT NULL,
+    editor_id INT REFERENCES users(id) ON DELETE SET NULL,
+    -- Номер ревизии, из которой восстановлен пост
+    restored_from INT,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    UNIQUE (post_id, revision)
+);
+
+-- Существующие посты получают первую ревизию с текущим содержимым
+INSERT INTO post_revisions (post_id, revision, title, content, editor_id, created_at)
+SELECT id, 1, title, content, author_id, COALESCE(created_at, CURRENT_TIMESTAMP)
+FROM posts;
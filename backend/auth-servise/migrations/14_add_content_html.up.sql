@@ -0,0 +1,18 @@
+-- Markdown-исходник хранится в content, отрендеренный и очищенный HTML - в content_html.
+ALTER TABLE posts ADD COLUMN content_html TEXT NOT NULL DEFAULT '';
+ALTER TABLE comments ADD COLUMN content_html TEXT NOT NULL DEFAULT '';
+ALTER TABLE post_revisions ADD COLUMN content_html TEXT NOT NULL DEFAULT '';
+
+-- Старые записи рендерить в SQL нечем: показываем их как экранированный текст.
+CREATE FUNCTION plain_text_html(src TEXT) RETURNS TEXT AS $$
+    SELECT CASE WHEN src = '' THEN '' ELSE
+        '<p>' || replace(replace(replace(replace(src,
+            '&', '&amp;'), '<', '&lt;'), '>', '&gt;'), '"', '&#34;') || '</p>'
+    END
+$$ LANGUAGE SQL IMMUTABLE;
+
+UPDATE posts SET content_html = plain_text_html(content);
+UPDATE comments SET content_html = plain_text_html(content);
+UPDATE post_revisions SET content_html = plain_text_html(content);
+
+DROP FUNCTION plain_text_html(TEXT);
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_attachments_orphaned;
+DROP INDEX IF EXISTS idx_attachments_hash;
+DROP INDEX IF EXISTS idx_attachments_post_id;
+
+DROP TABLE IF EXISTS attachments;
@@ -0,0 +1,23 @@
+-- Файлы, загруженные к постам. Содержимое лежит в BlobStore под ключом,
+-- производным от SHA-256, поэтому одинаковые файлы хранятся один раз.
+-- post_id пуст, пока файл не прикреплен к посту; такие загрузки через
+-- некоторое время удаляет сборщик мусора.
+CREATE TABLE attachments (
+    id SERIAL PRIMARY KEY,
+    post_id INT REFERENCES posts(id) ON DELETE SET NULL,
+    uploader_id INT REFERENCES users(id) ON DELETE SET NULL,
+    hash CHAR(64) NOT NULL,
+    filename VARCHAR(255) NOT NULL,
+    content_type VARCHAR(100) NOT NULL,
+    size BIGINT NOT NULL,
+    -- Размеры исходного изображения; у остальных файлов пустые
+    width INT,
+    height INT,
+    has_thumbnail BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_attachments_post_id ON attachments(post_id);
+CREATE INDEX IF NOT EXISTS idx_attachments_hash ON attachments(hash);
+-- Поиск осиротевших загрузок сборщиком мусора
+CREATE INDEX IF NOT EXISTS idx_attachments_orphaned ON attachments(created_at) WHERE post_id IS NULL;
@@ -0,0 +1,10 @@
+ALTER TABLE chat_messages DROP COLUMN IF EXISTS hidden_at;
+ALTER TABLE comments DROP COLUMN IF EXISTS hidden_at;
+ALTER TABLE posts DROP COLUMN IF EXISTS hidden_at;
+
+DROP INDEX IF EXISTS idx_user_warnings_user_id;
+DROP TABLE IF EXISTS user_warnings;
+
+DROP INDEX IF EXISTS idx_reports_status_target;
+DROP INDEX IF EXISTS ux_reports_open_reporter;
+DROP TABLE IF EXISTS reports;
@@ -0,0 +1,39 @@
+-- Жалобы пользователей на посты, комментарии и сообщения чата.
+-- Жалоба открыта, пока модератор не применит меру (actioned) или не отклонит ее (dismissed).
+CREATE TABLE reports (
+    id SERIAL PRIMARY KEY,
+    target_type VARCHAR(16) NOT NULL CHECK (target_type IN ('post', 'comment', 'chat_message')),
+    target_id INT NOT NULL,
+    reporter_id INT REFERENCES users(id) ON DELETE SET NULL,
+    reason VARCHAR(16) NOT NULL,
+    details TEXT NOT NULL DEFAULT '',
+    status VARCHAR(16) NOT NULL DEFAULT 'open' CHECK (status IN ('open', 'actioned', 'dismissed')),
+    -- Мера модератора: hide, delete, warn или dismiss
+    action VARCHAR(16),
+    resolved_by INT REFERENCES users(id) ON DELETE SET NULL,
+    resolved_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Одна открытая жалоба пользователя на один объект
+CREATE UNIQUE INDEX IF NOT EXISTS ux_reports_open_reporter ON reports(target_type, target_id, reporter_id) WHERE status = 'open';
+-- Очередь модерации группирует жалобы по объекту
+CREATE INDEX IF NOT EXISTS idx_reports_status_target ON reports(status, target_type, target_id);
+
+-- Предупреждения авторам от модераторов
+CREATE TABLE user_warnings (
+    id SERIAL PRIMARY KEY,
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    moderator_id INT REFERENCES users(id) ON DELETE SET NULL,
+    target_type VARCHAR(16) NOT NULL,
+    target_id INT NOT NULL,
+    note TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_warnings_user_id ON user_warnings(user_id);
+
+-- Скрытый контент не показывается в списках, но остается в базе
+ALTER TABLE posts ADD COLUMN IF NOT EXISTS hidden_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE comments ADD COLUMN IF NOT EXISTS hidden_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE chat_messages ADD COLUMN IF NOT EXISTS hidden_at TIMESTAMP WITH TIME ZONE;
@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS idx_posts_deleted_at;
+DROP INDEX IF EXISTS idx_posts_pinned_at;
+
+ALTER TABLE posts DROP COLUMN IF EXISTS deleted_by;
+ALTER TABLE posts DROP COLUMN IF EXISTS deleted_at;
+ALTER TABLE posts DROP COLUMN IF EXISTS locked_at;
+ALTER TABLE posts DROP COLUMN IF EXISTS pinned_at;
@@ -0,0 +1,25 @@
+-- Уведомления пользователей о новых комментариях, ответах и упоминаниях.
+-- Прочитанное уведомление получает read_at.
+CREATE TABLE notifications (
+    id SERIAL PRIMARY KEY,
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    type VARCHAR(16) NOT NULL CHECK (type IN ('post_comment', 'comment_reply', 'mention')),
+    actor_id INT REFERENCES users(id) ON DELETE SET NULL,
+    post_id INT NOT NULL REFERENCES posts(id) ON DELETE CASCADE,
+    comment_id INT REFERENCES comments(id) ON DELETE CASCADE,
+    read_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Лента уведомлений пользователя, начиная с новых
+CREATE INDEX IF NOT EXISTS idx_notifications_user_id ON notifications(user_id, id DESC);
+-- Счетчик непрочитанных
+CREATE INDEX IF NOT EXISTS idx_notifications_unread ON notifications(user_id) WHERE read_at IS NULL;
+
+-- Настройки уведомлений. Тип без строки в таблице включен.
+CREATE TABLE notification_preferences (
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    type VARCHAR(16) NOT NULL CHECK (type IN ('post_comment', 'comment_reply', 'mention')),
+    enabled BOOLEAN NOT NULL,
+    PRIMARY KEY (user_id, type)
+);
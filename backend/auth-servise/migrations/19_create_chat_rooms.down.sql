@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_chat_messages_room_id;
+ALTER TABLE chat_messages DROP COLUMN IF EXISTS room_id;
+
+DROP INDEX IF EXISTS idx_chat_room_members_user_id;
+DROP TABLE IF EXISTS chat_room_members;
+DROP TABLE IF EXISTS chat_rooms;
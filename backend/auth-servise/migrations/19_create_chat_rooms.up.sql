@@ -0,0 +1,29 @@
+-- Комнаты чата. Закрытая комната видна только участникам,
+-- вступить в нее можно лишь по приглашению владельца.
+CREATE TABLE chat_rooms (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL UNIQUE,
+    private BOOLEAN NOT NULL DEFAULT FALSE,
+    owner_id INT REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE chat_room_members (
+    room_id INT NOT NULL REFERENCES chat_rooms(id) ON DELETE CASCADE,
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    joined_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (room_id, user_id)
+);
+
+-- Комнаты пользователя
+CREATE INDEX IF NOT EXISTS idx_chat_room_members_user_id ON chat_room_members(user_id);
+
+-- Сообщения, написанные до появления комнат, попадают в общую комнату без владельца
+INSERT INTO chat_rooms (name) VALUES ('general');
+
+ALTER TABLE chat_messages ADD COLUMN IF NOT EXISTS room_id INT REFERENCES chat_rooms(id) ON DELETE CASCADE;
+UPDATE chat_messages SET room_id = (SELECT id FROM chat_rooms WHERE name = 'general');
+ALTER TABLE chat_messages ALTER COLUMN room_id SET NOT NULL;
+
+-- История комнаты
+CREATE INDEX IF NOT EXISTS idx_chat_messages_room_id ON chat_messages(room_id, id);
@@ -0,0 +1,15 @@
+ALTER TABLE chat_rooms DROP COLUMN IF EXISTS retention_seconds;
+
+CREATE OR REPLACE FUNCTION delete_old_messages()
+RETURNS TRIGGER AS $$
+BEGIN
+    DELETE FROM chat_messages
+    WHERE timestamp < NOW() - INTERVAL '10 minutes';
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER cleanup_old_messages
+AFTER INSERT ON chat_messages
+FOR EACH ROW
+EXECUTE FUNCTION delete_old_messages();
@@ -0,0 +1,7 @@
+-- Старые сообщения удаляет chat-service по политике хранения,
+-- а не триггер на каждую вставку.
+DROP TRIGGER IF EXISTS cleanup_old_messages ON chat_messages;
+DROP FUNCTION IF EXISTS delete_old_messages();
+
+-- Срок хранения сообщений комнаты в секундах: NULL — срок по умолчанию, 0 — хранить всегда
+ALTER TABLE chat_rooms ADD COLUMN IF NOT EXISTS retention_seconds BIGINT CHECK (retention_seconds >= 0);
@@ -0,0 +1,5 @@
+-- Сессия access-токена принадлежит той же цепочке ротации, что и refresh-токен,
+-- чтобы при обнаружении повторного использования отзывать и выданные access-токены.
+ALTER TABLE sessions ADD COLUMN IF NOT EXISTS family_id VARCHAR(64);
+
+CREATE INDEX IF NOT EXISTS idx_sessions_family_id ON sessions(family_id);
@@ -0,0 +1,4 @@
+-- Древовидные комментарии: ответ ссылается на родительский комментарий того же поста.
+ALTER TABLE comments ADD COLUMN IF NOT EXISTS parent_id INT REFERENCES comments(id) ON DELETE CASCADE;
+
+CREATE INDEX IF NOT EXISTS idx_comments_parent_id ON comments(parent_id);
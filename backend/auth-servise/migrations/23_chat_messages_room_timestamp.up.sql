@@ -0,0 +1,2 @@
+-- Очистка по сроку хранения ищет старые сообщения комнаты по времени
+CREATE INDEX IF NOT EXISTS idx_chat_messages_room_id_timestamp ON chat_messages(room_id, timestamp);
@@ -1,6 +1,10 @@
 package auth
 
 import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
 	"errors"
 	"time"
 
@@ -9,30 +13,70 @@ import (
 )
 
 type Config struct {
-	TokenSecret     string
-	TokenExpiration time.Duration
+	Keys                   *KeySet
+	TokenExpiration        time.Duration
+	RefreshTokenExpiration time.Duration
 }
 
-func GenerateToken(userID int64, role string, username string, secret string, expiration time.Duration) (string, error) {
+// GenerateToken signs an access token with the active key of the set and
+// records its kid in the header so verifiers can pick the matching key.
+// Every token carries a random jti: signatures are deterministic, and two
+// logins within the same second must still yield distinct sessions.
+func GenerateToken(userID int64, role string, username string, keys *KeySet, expiration time.Duration) (string, error) {
+	key := keys.Active()
+	jti, err := GenerateOpaqueToken()
+	if err != nil {
+		return "", err
+	}
+	now := time.Now()
 	claims := jwt.MapClaims{
 		"user_id":  userID,
 		"role":     role,
 		"username": username,
-		"exp":      time.Now().Add(expiration).Unix(),
+		"jti":      jti,
+		"iat":      now.Unix(),
+		"exp":      now.Add(expiration).Unix(),
 	}
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString([]byte(secret))
+	token := jwt.NewWithClaims(key.Method, claims)
+	token.Header["kid"] = key.ID
+	return token.SignedString(key.Private)
 }
 
-func ParseToken(tokenString string, secret string) (*jwt.Token, error) {
+func ParseToken(tokenString string, keys *KeySet) (*jwt.Token, error) {
 	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, errors.New("invalid signing method")
+		kid, ok := token.Header["kid"].(string)
+		if !ok {
+			return nil, errors.New("missing kid header")
 		}
-		return []byte(secret), nil
+		key, err := keys.Lookup(kid)
+		if err != nil {
+			return nil, err
+		}
+		if token.Method.Alg() != key.Method.Alg() {
+			return nil, ErrAlgorithmMismatch
+		}
+		return key.Public, nil
 	})
 	return token, err
 }
+
+// GenerateOpaqueToken returns a random URL-safe string used for refresh
+// tokens and token family identifiers.
+func GenerateOpaqueToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// HashRefreshToken returns the hex SHA-256 digest under which a refresh
+// token is stored, so a database leak does not expose usable tokens.
+func HashRefreshToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
 func HashPassword(password string) (string, error) {
 	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
 	if err != nil {
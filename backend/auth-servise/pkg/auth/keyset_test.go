@@ -0,0 +1,155 @@
+package auth
+
+import (
+	"crypto/ed25519"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateAndParseToken(t *testing.T) {
+	for _, alg := range []string{AlgRS256, AlgEdDSA} {
+		t.Run(alg, func(t *testing.T) {
+			key, err := GenerateSigningKey("k1", alg)
+			require.NoError(t, err)
+			keys, err := NewKeySet("", key)
+			require.NoError(t, err)
+
+			tokenString, err := GenerateToken(1, "user", "testuser", keys, time.Hour)
+			require.NoError(t, err)
+
+			token, err := ParseToken(tokenString, keys)
+			require.NoError(t, err)
+			assert.True(t, token.Valid)
+			assert.Equal(t, "k1", token.Header["kid"])
+			assert.Equal(t, alg, token.Header["alg"])
+			assert.Equal(t, "testuser", token.Claims.(jwt.MapClaims)["username"])
+		})
+	}
+}
+
+func TestGenerateToken_Unique(t *testing.T) {
+	for _, alg := range []string{AlgRS256, AlgEdDSA} {
+		t.Run(alg, func(t *testing.T) {
+			key, err := GenerateSigningKey("k1", alg)
+			require.NoError(t, err)
+			keys, err := NewKeySet("", key)
+			require.NoError(t, err)
+
+			// Both tokens are issued within the same second, so only the
+			// jti tells them apart.
+			first, err := GenerateToken(1, "user", "testuser", keys, time.Hour)
+			require.NoError(t, err)
+			second, err := GenerateToken(1, "user", "testuser", keys, time.Hour)
+			require.NoError(t, err)
+			assert.NotEqual(t, first, second)
+
+			a, err := ParseToken(first, keys)
+			require.NoError(t, err)
+			b, err := ParseToken(second, keys)
+			require.NoError(t, err)
+			claimsA, claimsB := a.Claims.(jwt.MapClaims), b.Claims.(jwt.MapClaims)
+			assert.NotEmpty(t, claimsA["jti"])
+			assert.NotEqual(t, claimsA["jti"], claimsB["jti"])
+			assert.Contains(t, claimsA, "iat")
+		})
+	}
+}
+
+func TestKeyRotation(t *testing.T) {
+	oldKey, err := GenerateSigningKey("2024-01", AlgEdDSA)
+	require.NoError(t, err)
+	newKey, err := GenerateSigningKey("2024-06", AlgEdDSA)
+	require.NoError(t, err)
+
+	before, err := NewKeySet("", oldKey)
+	require.NoError(t, err)
+	oldToken, err := GenerateToken(1, "user", "testuser", before, time.Hour)
+	require.NoError(t, err)
+
+	// Old key is kept verify-only after rotation.
+	retired := &SigningKey{ID: oldKey.ID, Method: oldKey.Method, Public: oldKey.Public}
+	after, err := NewKeySet("", retired, newKey)
+	require.NoError(t, err)
+	assert.Equal(t, "2024-06", after.Active().ID)
+
+	token, err := ParseToken(oldToken, after)
+	require.NoError(t, err)
+	assert.True(t, token.Valid)
+
+	newToken, err := GenerateToken(1, "user", "testuser", after, time.Hour)
+	require.NoError(t, err)
+	token, err = ParseToken(newToken, after)
+	require.NoError(t, err)
+	assert.Equal(t, "2024-06", token.Header["kid"])
+
+	// Once the old key is dropped its tokens stop verifying.
+	dropped, err := NewKeySet("", newKey)
+	require.NoError(t, err)
+	_, err = ParseToken(oldToken, dropped)
+	assert.Error(t, err)
+}
+
+func TestParseToken_RejectsAlgorithmMismatch(t *testing.T) {
+	key, err := GenerateSigningKey("k1", AlgEdDSA)
+	require.NoError(t, err)
+	keys, err := NewKeySet("", key)
+	require.NoError(t, err)
+
+	// An HMAC token keyed with the public key bytes must not verify.
+	forged := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"user_id": 1, "role": "admin"})
+	forged.Header["kid"] = "k1"
+	tokenString, err := forged.SignedString([]byte(key.Public.(ed25519.PublicKey)))
+	require.NoError(t, err)
+
+	_, err = ParseToken(tokenString, keys)
+	assert.Error(t, err)
+}
+
+func TestNewKeySet_NoPrivateKey(t *testing.T) {
+	key, err := GenerateSigningKey("k1", AlgEdDSA)
+	require.NoError(t, err)
+
+	_, err = NewKeySet("", &SigningKey{ID: key.ID, Method: key.Method, Public: key.Public})
+	assert.ErrorIs(t, err, ErrNoSigningKey)
+}
+
+func TestLoadKeySet(t *testing.T) {
+	dir := t.TempDir()
+
+	rsaKey, err := GenerateSigningKey("a", AlgRS256)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKCS8PrivateKey(rsaKey.Private)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "a.pem"),
+		pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), 0o600))
+
+	edKey, err := GenerateSigningKey("b", AlgEdDSA)
+	require.NoError(t, err)
+	der, err = x509.MarshalPKIXPublicKey(edKey.Public)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "b.pem"),
+		pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), 0o644))
+
+	keys, err := LoadKeySet(dir, "")
+	require.NoError(t, err)
+	// "b" is public-only, so "a" is the only key that can sign.
+	assert.Equal(t, "a", keys.Active().ID)
+
+	jwks := keys.JWKS()
+	require.Len(t, jwks.Keys, 2)
+	assert.Equal(t, "RSA", jwks.Keys[0].Kty)
+	assert.Equal(t, AlgRS256, jwks.Keys[0].Alg)
+	assert.NotEmpty(t, jwks.Keys[0].N)
+	assert.Equal(t, "AQAB", jwks.Keys[0].E)
+	assert.Equal(t, "OKP", jwks.Keys[1].Kty)
+	assert.Equal(t, "Ed25519", jwks.Keys[1].Crv)
+	assert.NotEmpty(t, jwks.Keys[1].X)
+}
@@ -0,0 +1,60 @@
+package entity
+
+import "time"
+
+// FrameType tells what a WebSocket frame is about.
+type FrameType string
+
+const (
+	// Sent by clients.
+	FrameJoin    FrameType = "join"
+	FrameLeave   FrameType = "leave"
+	FrameMessage FrameType = "message"
+
+	// Sent by the server. A message frame carries a saved message, a
+	// history frame the messages missed by a client that rejoined.
+	FrameJoined  FrameType = "joined"
+	FrameLeft    FrameType = "left"
+	FrameHistory FrameType = "history"
+	FrameError   FrameType = "error"
+)
+
+// Frame is one WebSocket message in either direction. Clients send join and
+// leave with a room_id, and message with a room_id and the text. A join
+// with after, the id of the last message seen, asks for the ones missed
+// since.
+type Frame struct {
+	Type      FrameType    `json:"type" example:"message"`
+	RoomID    int64        `json:"room_id,omitempty" example:"1"`
+	ID        int          `json:"id,omitempty" example:"1"`
+	UserID    int64        `json:"user_id,omitempty" example:"32"`
+	Username  string       `json:"username,omitempty" example:"john_doe"`
+	Message   string       `json:"message,omitempty" example:"Hello, world!"`
+	CreatedAt *time.Time   `json:"created_at,omitempty"`
+	After     *int         `json:"after,omitempty" example:"41"`
+	History   *MessagePage `json:"history,omitempty"`
+	Error     string       `json:"error,omitempty"`
+	Code      string       `json:"code,omitempty"`
+}
+
+// MessageFrame wraps a saved message for delivery to room members.
+func MessageFrame(msg Message) Frame {
+	return Frame{
+		Type:      FrameMessage,
+		RoomID:    msg.RoomID,
+		ID:        msg.ID,
+		UserID:    msg.UserID,
+		Username:  msg.Username,
+		Message:   msg.Message,
+		CreatedAt: &msg.CreatedAt,
+	}
+}
+
+// HistoryFrame carries the messages a client missed in a room. The
+// messages are sent even if empty, so the client knows it is up to date.
+func HistoryFrame(roomID int64, page MessagePage) Frame {
+	if page.Messages == nil {
+		page.Messages = []Message{}
+	}
+	return Frame{Type: FrameHistory, RoomID: roomID, History: &page}
+}
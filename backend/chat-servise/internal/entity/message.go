@@ -1,8 +1,32 @@
 // internal/entity/message.go
 package entity
 
+import "time"
+
 type Message struct {
-	ID       int    `json:"id" example:"1"`
-	Username string `json:"username" example:"john_doe"`
-	Message  string `json:"message" example:"Hello, world!"`
-}
\ No newline at end of file
+	ID        int       `json:"id" example:"1"`
+	RoomID    int64     `json:"room_id" example:"1"`
+	UserID    int64     `json:"user_id" example:"32"`
+	Username  string    `json:"username" example:"john_doe"`
+	Message   string    `json:"message" example:"Hello, world!"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// MessageFilter selects messages of a room by id. Of the messages matching
+// Before or After, the newest Limit are taken.
+type MessageFilter struct {
+	RoomID int64
+	// Before, if set, keeps messages with smaller ids.
+	Before int
+	// After, if set, keeps messages with greater ids.
+	After int
+	Limit int
+}
+
+// MessagePage is a run of messages, oldest first.
+type MessagePage struct {
+	Messages []Message `json:"messages"`
+	// NextBefore is set when older messages exist; pass it as before to
+	// get them.
+	NextBefore int `json:"next_before,omitempty" example:"41"`
+}
@@ -2,4 +2,5 @@ package entity
 
 type ErrorResponse struct {
 	Error string `json:"error" example:"Internal server error"`
+	Code  string `json:"code" example:"internal"`
 }
@@ -0,0 +1,29 @@
+package entity
+
+import "time"
+
+type Room struct {
+	ID      int64  `json:"id" example:"1"`
+	Name    string `json:"name" example:"general"`
+	Private bool   `json:"private" example:"false"`
+	// OwnerID is 0 for rooms nobody owns, such as the initial general room;
+	// administrators manage those.
+	OwnerID int64 `json:"owner_id,omitempty" example:"32"`
+	// RetentionSeconds is how long the room keeps messages: nil for the
+	// service default, 0 for forever.
+	RetentionSeconds *int64    `json:"retention_seconds" example:"604800"`
+	CreatedAt        time.Time `json:"created_at"`
+}
+
+// RoomUpdate holds the room settings to change; nil fields are kept.
+type RoomUpdate struct {
+	Name    *string `json:"name,omitempty" example:"off-topic"`
+	Private *bool   `json:"private,omitempty" example:"true"`
+	// RetentionSeconds of -1 returns the room to the service default.
+	RetentionSeconds *int64 `json:"retention_seconds,omitempty" example:"604800"`
+}
+
+type RoomMember struct {
+	UserID   int64     `json:"user_id" example:"32"`
+	JoinedAt time.Time `json:"joined_at"`
+}
@@ -0,0 +1,42 @@
+package handler
+
+import (
+	"strings"
+
+	"backend.com/forum/apperrors"
+	"github.com/Ulyana-kru00/forum-project/chat/pkg/auth"
+
+	"github.com/gin-gonic/gin"
+)
+
+const identityKey = "identity"
+
+var errMissingAuthHeader = apperrors.New(apperrors.ErrUnauthenticated, "Authorization header is required")
+
+// RequireAuth rejects requests without a valid bearer token and stores the
+// identity of the caller for the handlers behind it.
+func RequireAuth(authenticator auth.Authenticator) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		token, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+		if !ok || token == "" {
+			writeError(c, errMissingAuthHeader)
+			c.Abort()
+			return
+		}
+
+		identity, err := authenticator.Authenticate(c.Request.Context(), token)
+		if err != nil {
+			writeError(c, err)
+			c.Abort()
+			return
+		}
+		c.Set(identityKey, identity)
+		c.Next()
+	}
+}
+
+// caller returns the identity RequireAuth stored.
+func caller(c *gin.Context) *auth.Identity {
+	identity, _ := c.MustGet(identityKey).(*auth.Identity)
+	return identity
+}
@@ -2,73 +2,237 @@
 package handler
 
 import (
+	"backend.com/forum/apperrors"
 	"github.com/Ulyana-kru00/forum-project/chat/internal/entity"
 	"github.com/Ulyana-kru00/forum-project/chat/internal/usecase"
+	"github.com/Ulyana-kru00/forum-project/chat/pkg/auth"
 	myWeb "github.com/Ulyana-kru00/forum-project/chat/pkg/websocket"
 
+	"errors"
 	"log"
 	"net/http"
+	"strconv"
+	"strings"
 
 	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
 )
 
+var (
+	errInvalidRoomID  = apperrors.New(apperrors.ErrInvalidArgument, "invalid room id")
+	errUnknownFrame   = apperrors.New(apperrors.ErrInvalidArgument, "frame type must be one of join, leave, message")
+	errRoomIDRequired = apperrors.New(apperrors.ErrInvalidArgument, "room_id is required")
+	errInvalidAfter   = apperrors.New(apperrors.ErrInvalidArgument, "after must be a message id")
+	errInvalidBefore  = apperrors.New(apperrors.ErrInvalidArgument, "before must be a positive message id")
+	errInvalidLimit   = apperrors.New(apperrors.ErrInvalidArgument, "limit must be a positive number")
+)
+
+// resumeLimit bounds the missed messages sent on a rejoin; older ones are
+// paged in over GET /rooms/{id}/messages.
+const resumeLimit = 100
+
 type MessageHandler struct {
-	Uc usecase.MessageUseCase
+	Uc       usecase.MessageUseCase
+	Rooms    usecase.RoomUseCase
+	Auth     auth.Authenticator
+	Upgrader *websocket.Upgrader
+	Hub      *myWeb.Hub
 }
 
-func NewMessageHandler(uc usecase.MessageUseCase) *MessageHandler {
-	return &MessageHandler{Uc: uc}
+func NewMessageHandler(
+	uc usecase.MessageUseCase,
+	rooms usecase.RoomUseCase,
+	authenticator auth.Authenticator,
+	upgrader *websocket.Upgrader,
+	hub *myWeb.Hub,
+) *MessageHandler {
+	return &MessageHandler{Uc: uc, Rooms: rooms, Auth: authenticator, Upgrader: upgrader, Hub: hub}
 }
 
+// HandleConnections upgrades an authenticated request to a WebSocket. The
+// client joins rooms with {"type":"join","room_id":1} and posts with
+// {"type":"message","room_id":1,"message":"..."}. Every message is
+// attributed to the token's user, whatever the client puts in the frame.
+// A client back from a dropped connection joins with
+// {"type":"join","room_id":1,"after":41} and gets a history frame with what
+// it missed before any newer message.
+//
+// @Summary Подключение к чату
+// @Description Открывает WebSocket-соединение. Токен передается в заголовке Authorization, или в подпротоколе "bearer, <токен>".
+// @Description Клиент подписывается на комнаты кадрами join и leave и пишет в них кадрами message; сервер присылает сообщения комнат, подтверждения joined и left и ошибки error.
+// @Description После переподключения клиент передает в join поле after — ID последнего полученного сообщения — и до новых сообщений получает кадр history с пропущенными. Если пропущено больше 100, history содержит последние из них и next_before для загрузки остальных через историю комнаты.
+// @Tags chat
+// @Param Authorization header string false "Bearer токен"
+// @Success 101 {string} string "Switching Protocols"
+// @Failure 401 {object} entity.ErrorResponse
+// @Failure 403 {string} string "Источник запроса не разрешен"
+// @Router /ws [get]
 func (h *MessageHandler) HandleConnections(c *gin.Context) {
-	ws, err := myWeb.Upgrader.Upgrade(c.Writer, c.Request, nil)
+	identity, err := h.Auth.Authenticate(c.Request.Context(), bearerToken(c.Request))
 	if err != nil {
-		log.Fatal(err)
+		writeError(c, err)
+		return
 	}
-	defer ws.Close()
 
-	myWeb.Clients[ws] = true
+	ws, err := h.Upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		// The upgrader has already answered with an HTTP error.
+		log.Printf("websocket upgrade failed: %v", err)
+		return
+	}
 
-	for {
-		var msg entity.Message
-		err := ws.ReadJSON(&msg)
-		if err != nil {
-			delete(myWeb.Clients, ws)
-			break
+	err = h.Hub.Serve(ws, identity.UserID, func(client *myWeb.Client, frame entity.Frame) {
+		if err := h.handleFrame(client, identity, frame); err != nil {
+			if apperrors.Code(err) == apperrors.CodeInternal {
+				log.Printf("failed to handle %s frame: %v", frame.Type, err)
+			}
+			h.Hub.Reply(client, entity.Frame{
+				Type:   entity.FrameError,
+				RoomID: frame.RoomID,
+				Error:  apperrors.Message(err),
+				Code:   apperrors.Code(err),
+			})
 		}
-		h.Uc.SaveMessage(msg)
-		myWeb.Broadcast <- msg
+	})
+	if err != nil && !errors.Is(err, myWeb.ErrHubStopped) {
+		log.Printf("websocket connection of user %d closed: %v", identity.UserID, err)
 	}
 }
 
-func (h *MessageHandler) HandleMessages() {
-	for {
-		msg := <-myWeb.Broadcast
-		for client := range myWeb.Clients {
-			err := client.WriteJSON(msg)
-			if err != nil {
-				log.Printf("error: %v", err)
-				client.Close()
-				delete(myWeb.Clients, client)
-			}
+func (h *MessageHandler) handleFrame(client *myWeb.Client, identity *auth.Identity, frame entity.Frame) error {
+	if frame.RoomID <= 0 {
+		return errRoomIDRequired
+	}
+
+	switch frame.Type {
+	case entity.FrameJoin:
+		if frame.After != nil && *frame.After < 0 {
+			return errInvalidAfter
+		}
+		if err := h.Rooms.JoinRoom(identity.UserID, frame.RoomID); err != nil {
+			return err
+		}
+		if frame.After == nil {
+			h.Hub.Join(client, frame.RoomID)
+			return nil
+		}
+		return h.resume(client, identity, frame.RoomID, *frame.After)
+	case entity.FrameLeave:
+		h.Hub.Leave(client, frame.RoomID)
+	case entity.FrameMessage:
+		msg := entity.Message{
+			RoomID:   frame.RoomID,
+			UserID:   identity.UserID,
+			Username: identity.Username,
+			Message:  frame.Message,
 		}
+		if err := h.Uc.SaveMessage(&msg); err != nil {
+			return err
+		}
+		h.Hub.Broadcast(msg)
+	default:
+		return errUnknownFrame
+	}
+	return nil
+}
+
+// resume subscribes client to a room and sends it the messages after the
+// last one it saw. The room is subscribed to before the history is read, so
+// a message saved meanwhile is either in the history or delivered live.
+func (h *MessageHandler) resume(client *myWeb.Client, identity *auth.Identity, roomID int64, after int) error {
+	h.Hub.Resume(client, roomID)
+	page, err := h.Uc.GetMessages(identity.UserID, entity.MessageFilter{
+		RoomID: roomID,
+		After:  after,
+		Limit:  resumeLimit,
+	})
+	if err != nil {
+		h.Hub.Leave(client, roomID)
+		return err
 	}
+	h.Hub.Replay(client, roomID, *page)
+	return nil
 }
 
-// GetMessages получает список всех сообщений.
+// GetMessages получает историю комнаты.
 //
-// @Summary Получить сообщения
-// @Description Возвращает все сообщения из чата
+// @Summary Получить сообщения комнаты
+// @Description Возвращает страницу сообщений комнаты от старых к новым: последние сообщения или, с параметром before, предшествующие ему. Закрытая комната доступна только участникам
 // @Tags messages
 // @Produce json
-// @Success 200 {array} entity.Message
+// @Security ApiKeyAuth
+// @Param id path int true "ID комнаты"
+// @Param before query int false "Вернуть сообщения с ID меньше указанного, например next_before предыдущей страницы"
+// @Param limit query int false "Размер страницы (по умолчанию 50, максимум 100)"
+// @Success 200 {object} entity.MessagePage
+// @Failure 400 {object} entity.ErrorResponse
+// @Failure 401 {object} entity.ErrorResponse
+// @Failure 404 {object} entity.ErrorResponse
 // @Failure 500 {object} entity.ErrorResponse
-// @Router /messages [get]
+// @Router /rooms/{id}/messages [get]
 func (h *MessageHandler) GetMessages(c *gin.Context) {
-	messages, err := h.Uc.GetMessages()
+	roomID, ok := roomIDParam(c)
+	if !ok {
+		return
+	}
+	filter := entity.MessageFilter{RoomID: roomID}
+	if filter.Before, ok = positiveQueryInt(c, "before", errInvalidBefore); !ok {
+		return
+	}
+	if filter.Limit, ok = positiveQueryInt(c, "limit", errInvalidLimit); !ok {
+		return
+	}
+
+	page, err := h.Uc.GetMessages(caller(c).UserID, filter)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeError(c, err)
 		return
 	}
-	c.JSON(http.StatusOK, messages)
+	c.JSON(http.StatusOK, page)
+}
+
+// positiveQueryInt reads an optional positive query parameter, 0 if it is
+// missing.
+func positiveQueryInt(c *gin.Context, name string, invalid error) (int, bool) {
+	v := c.Query(name)
+	if v == "" {
+		return 0, true
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil || n <= 0 {
+		writeError(c, invalid)
+		return 0, false
+	}
+	return n, true
+}
+
+func roomIDParam(c *gin.Context) (int64, bool) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		writeError(c, errInvalidRoomID)
+		return 0, false
+	}
+	return id, true
+}
+
+// bearerToken takes the access token from the Authorization header or the
+// "bearer" subprotocol. Tokens in the query string are refused, as URLs end
+// up in access logs.
+func bearerToken(r *http.Request) string {
+	if token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); ok {
+		return token
+	}
+	if protocols := websocket.Subprotocols(r); len(protocols) == 2 && protocols[0] == myWeb.BearerSubprotocol {
+		return protocols[1]
+	}
+	return ""
+}
+
+// writeError renders err as the JSON error envelope with the HTTP status
+// of its kind.
+func writeError(c *gin.Context, err error) {
+	c.JSON(apperrors.HTTPStatus(err), entity.ErrorResponse{
+		Error: apperrors.Message(err),
+		Code:  apperrors.Code(err),
+	})
 }
@@ -1,70 +1,152 @@
 package handler
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
+	"backend.com/forum/apperrors"
 	"github.com/Ulyana-kru00/forum-project/chat/internal/entity"
+	"github.com/Ulyana-kru00/forum-project/chat/internal/repository"
+	"github.com/Ulyana-kru00/forum-project/chat/pkg/auth"
 	myWeb "github.com/Ulyana-kru00/forum-project/chat/pkg/websocket"
 
 	"github.com/gin-gonic/gin"
 	"github.com/gorilla/websocket"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 type MockMessageUseCase struct {
 	mock.Mock
 }
 
-func (m *MockMessageUseCase) SaveMessage(msg entity.Message) error {
-	args := m.Called(msg)
+// authHeader authenticates test connections as the user tokenAuth knows.
+var authHeader = http.Header{"Authorization": {"Bearer token"}}
+
+// savedAt is when MockMessageUseCase saves every message.
+var savedAt = time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+
+func (m *MockMessageUseCase) SaveMessage(msg *entity.Message) error {
+	args := m.Called(*msg)
+	if args.Error(0) == nil {
+		msg.ID = 100
+		msg.CreatedAt = savedAt
+	}
 	return args.Error(0)
 }
 
-func (m *MockMessageUseCase) GetMessages() ([]entity.Message, error) {
-	args := m.Called()
-	return args.Get(0).([]entity.Message), args.Error(1)
+func (m *MockMessageUseCase) GetMessages(userID int64, filter entity.MessageFilter) (*entity.MessagePage, error) {
+	args := m.Called(userID, filter)
+	page, _ := args.Get(0).(*entity.MessagePage)
+	return page, args.Error(1)
+}
+
+// tokenAuth knows a single user, whose token is "token".
+type tokenAuth struct{}
+
+func (tokenAuth) Authenticate(ctx context.Context, token string) (*auth.Identity, error) {
+	if token != "token" {
+		return nil, auth.ErrInvalidToken
+	}
+	return &auth.Identity{UserID: 7, Username: "alice"}, nil
+}
+
+func startHub(t *testing.T) *myWeb.Hub {
+	hub := myWeb.NewHub(myWeb.Config{})
+	ctx, cancel := context.WithCancel(context.Background())
+	go hub.Run(ctx)
+	t.Cleanup(cancel)
+	return hub
+}
+
+// newTestHandler returns a handler where alice may join any room but 2.
+func newTestHandler(t *testing.T, uc *MockMessageUseCase) *MessageHandler {
+	rooms := new(MockRoomUseCase)
+	rooms.On("JoinRoom", int64(7), int64(2)).Return(repository.ErrRoomNotFound)
+	rooms.On("JoinRoom", int64(7), mock.Anything).Return(nil)
+	return NewMessageHandler(uc, rooms, tokenAuth{}, myWeb.NewUpgrader([]string{"http://localhost:3000"}), startHub(t))
+}
+
+func dialRoom(t *testing.T, url string, roomID int64) *websocket.Conn {
+	ws, _, err := websocket.DefaultDialer.Dial(url, authHeader)
+	require.NoError(t, err)
+	t.Cleanup(func() { ws.Close() })
+
+	require.NoError(t, ws.WriteJSON(entity.Frame{Type: entity.FrameJoin, RoomID: roomID}))
+	var joined entity.Frame
+	require.NoError(t, ws.ReadJSON(&joined))
+	require.Equal(t, entity.Frame{Type: entity.FrameJoined, RoomID: roomID}, joined)
+	return ws
+}
+
+func newMessagesRouter(handler *MessageHandler) *gin.Engine {
+	router := gin.Default()
+	router.GET("/rooms/:id/messages", RequireAuth(tokenAuth{}), handler.GetMessages)
+	return router
 }
 
 func TestMessageHandler_GetMessages(t *testing.T) {
 
 	uc := new(MockMessageUseCase)
 
-	uc.On("GetMessages").Return([]entity.Message{
-		{ID: 1, Username: "testuser", Message: "Hello, World!"},
+	uc.On("GetMessages", int64(7), entity.MessageFilter{RoomID: 1, Before: 40, Limit: 20}).Return(&entity.MessagePage{
+		Messages:   []entity.Message{{ID: 39, RoomID: 1, Username: "testuser", Message: "Hello, World!", CreatedAt: savedAt}},
+		NextBefore: 39,
 	}, nil)
 
-	handler := NewMessageHandler(uc)
+	router := newMessagesRouter(newTestHandler(t, uc))
 
-	router := gin.Default()
-	router.GET("/messages", handler.GetMessages)
-
-	req, _ := http.NewRequest("GET", "/messages", nil)
+	req, _ := http.NewRequest("GET", "/rooms/1/messages?before=40&limit=20", nil)
+	req.Header.Set("Authorization", "Bearer token")
 
 	w := httptest.NewRecorder()
 
 	router.ServeHTTP(w, req)
 
 	assert.Equal(t, http.StatusOK, w.Code)
-	var resp []entity.Message
+	var resp entity.MessagePage
 	json.Unmarshal(w.Body.Bytes(), &resp)
-	assert.Equal(t, 1, len(resp))
-	assert.Equal(t, "Hello, World!", resp[0].Message)
-	assert.Equal(t, "testuser", resp[0].Username)
+	assert.Equal(t, 1, len(resp.Messages))
+	assert.Equal(t, "Hello, World!", resp.Messages[0].Message)
+	assert.Equal(t, "testuser", resp.Messages[0].Username)
+	assert.Equal(t, savedAt, resp.Messages[0].CreatedAt)
+	assert.Equal(t, 39, resp.NextBefore)
 	uc.AssertExpectations(t)
 }
 
+func TestMessageHandler_GetMessages_BadRequest(t *testing.T) {
+	router := newMessagesRouter(newTestHandler(t, new(MockMessageUseCase)))
+
+	req, _ := http.NewRequest("GET", "/rooms/1/messages", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	for _, url := range []string{"/rooms/abc/messages", "/rooms/1/messages?before=0", "/rooms/1/messages?limit=many"} {
+		req, _ = http.NewRequest("GET", url, nil)
+		req.Header.Set("Authorization", "Bearer token")
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusBadRequest, w.Code, url)
+	}
+}
+
 func TestMessageHandler_HandleConnections(t *testing.T) {
 
 	uc := new(MockMessageUseCase)
 
-	uc.On("SaveMessage", mock.Anything).Return(nil)
+	saved := make(chan entity.Message, 1)
+	uc.On("SaveMessage", mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		saved <- args.Get(0).(entity.Message)
+	})
 
-	handler := NewMessageHandler(uc)
+	handler := newTestHandler(t, uc)
 
 	router := gin.Default()
 	router.GET("/ws", handler.HandleConnections)
@@ -73,51 +155,198 @@ func TestMessageHandler_HandleConnections(t *testing.T) {
 	defer server.Close()
 
 	url := "ws" + server.URL[4:] + "/ws"
-	ws, _, err := websocket.DefaultDialer.Dial(url, nil)
-	assert.NoError(t, err)
+	ws, _, err := websocket.DefaultDialer.Dial(url, authHeader)
+	require.NoError(t, err)
 	defer ws.Close()
 
-	msg := entity.Message{Username: "testuser", Message: "Hello, World!"}
-	err = ws.WriteJSON(msg)
+	require.NoError(t, ws.WriteJSON(entity.Frame{Type: entity.FrameJoin, RoomID: 1}))
+	var frame entity.Frame
+	require.NoError(t, ws.ReadJSON(&frame))
+	assert.Equal(t, entity.FrameJoined, frame.Type)
+
+	// The client cannot speak for someone else.
+	err = ws.WriteJSON(entity.Frame{Type: entity.FrameMessage, RoomID: 1, ID: 99, UserID: 1, Username: "admin", Message: "Hello, World!"})
 	assert.NoError(t, err)
 
+	select {
+	case got := <-saved:
+		assert.Equal(t, entity.Message{RoomID: 1, UserID: 7, Username: "alice", Message: "Hello, World!"}, got)
+	case <-time.After(time.Second):
+		t.Fatal("message was not saved")
+	}
+	require.NoError(t, ws.ReadJSON(&frame))
+	assert.Equal(t, entity.Frame{Type: entity.FrameMessage, RoomID: 1, ID: 100, UserID: 7, Username: "alice", Message: "Hello, World!", CreatedAt: &savedAt}, frame)
 	uc.AssertExpectations(t)
 }
 
-func TestMessageHandler_HandleMessages(t *testing.T) {
-	// Создаем мок usecase
+func TestMessageHandler_HandleConnections_Resume(t *testing.T) {
+	uc := new(MockMessageUseCase)
+	uc.On("GetMessages", int64(7), entity.MessageFilter{RoomID: 1, After: 41, Limit: resumeLimit}).Return(&entity.MessagePage{
+		Messages: []entity.Message{{ID: 42, RoomID: 1, Message: "missed"}, {ID: 43, RoomID: 1, Message: "missed too"}},
+	}, nil)
+	uc.On("GetMessages", int64(7), entity.MessageFilter{RoomID: 4, After: 41, Limit: resumeLimit}).Return(nil, errors.New("database error"))
+	uc.On("SaveMessage", mock.Anything).Return(nil)
+
+	router := gin.New()
+	router.GET("/ws", newTestHandler(t, uc).HandleConnections)
+
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	ws, _, err := websocket.DefaultDialer.Dial("ws"+server.URL[4:]+"/ws", authHeader)
+	require.NoError(t, err)
+	defer ws.Close()
+
+	after := 41
+	require.NoError(t, ws.WriteJSON(entity.Frame{Type: entity.FrameJoin, RoomID: 1, After: &after}))
+	require.NoError(t, ws.WriteJSON(entity.Frame{Type: entity.FrameMessage, RoomID: 1, Message: "new"}))
+
+	// What was missed comes first, then the live messages.
+	var types []entity.FrameType
+	var frame entity.Frame
+	for len(types) < 3 {
+		require.NoError(t, ws.ReadJSON(&frame))
+		types = append(types, frame.Type)
+		if frame.Type == entity.FrameHistory {
+			assert.Len(t, frame.History.Messages, 2)
+			assert.Zero(t, frame.History.NextBefore)
+		}
+	}
+	assert.Equal(t, []entity.FrameType{entity.FrameJoined, entity.FrameHistory, entity.FrameMessage}, types)
+	assert.Equal(t, 100, frame.ID)
+
+	// A history that cannot be read leaves the room again.
+	require.NoError(t, ws.WriteJSON(entity.Frame{Type: entity.FrameJoin, RoomID: 4, After: &after}))
+	types = nil
+	for len(types) < 3 {
+		require.NoError(t, ws.ReadJSON(&frame))
+		types = append(types, frame.Type)
+	}
+	assert.Equal(t, []entity.FrameType{entity.FrameJoined, entity.FrameLeft, entity.FrameError}, types)
+	assert.Equal(t, "internal", frame.Code)
+}
+
+func TestMessageHandler_HandleConnections_Errors(t *testing.T) {
+	uc := new(MockMessageUseCase)
+	uc.On("SaveMessage", mock.Anything).Return(apperrors.New(apperrors.ErrPermissionDenied, "join the room first"))
+
+	router := gin.New()
+	router.GET("/ws", newTestHandler(t, uc).HandleConnections)
+
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	ws, _, err := websocket.DefaultDialer.Dial("ws"+server.URL[4:]+"/ws", authHeader)
+	require.NoError(t, err)
+	defer ws.Close()
+
+	negative := -1
+	tests := []struct {
+		name  string
+		frame entity.Frame
+		code  string
+	}{
+		{"Hidden room", entity.Frame{Type: entity.FrameJoin, RoomID: 2}, "not_found"},
+		{"Bad resume point", entity.Frame{Type: entity.FrameJoin, RoomID: 1, After: &negative}, "invalid_argument"},
+		{"Not a member", entity.Frame{Type: entity.FrameMessage, RoomID: 3, Message: "hi"}, "permission_denied"},
+		{"No room", entity.Frame{Type: entity.FrameMessage, Message: "hi"}, "invalid_argument"},
+		{"Unknown type", entity.Frame{Type: "typing", RoomID: 1}, "invalid_argument"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.NoError(t, ws.WriteJSON(tt.frame))
+			var got entity.Frame
+			require.NoError(t, ws.ReadJSON(&got))
+			assert.Equal(t, entity.FrameError, got.Type)
+			assert.Equal(t, tt.frame.RoomID, got.RoomID)
+			assert.Equal(t, tt.code, got.Code)
+		})
+	}
+}
+
+func TestMessageHandler_HandleConnections_Auth(t *testing.T) {
+	router := gin.New()
+	router.GET("/ws", newTestHandler(t, new(MockMessageUseCase)).HandleConnections)
+
+	server := httptest.NewServer(router)
+	defer server.Close()
+	url := "ws" + server.URL[4:] + "/ws"
+
+	tests := []struct {
+		name   string
+		url    string
+		header http.Header
+		status int
+	}{
+		{"Subprotocol", url, http.Header{"Sec-WebSocket-Protocol": {"bearer, token"}}, http.StatusSwitchingProtocols},
+		{"Header", url, authHeader, http.StatusSwitchingProtocols},
+		{"Allowed origin", url, http.Header{"Authorization": {"Bearer token"}, "Origin": {"http://localhost:3000"}}, http.StatusSwitchingProtocols},
+		{"Query param is not accepted", url + "?token=token", nil, http.StatusUnauthorized},
+		{"No token", url, nil, http.StatusUnauthorized},
+		{"Invalid token", url, http.Header{"Authorization": {"Bearer forged"}}, http.StatusUnauthorized},
+		{"Foreign origin", url, http.Header{"Authorization": {"Bearer token"}, "Origin": {"http://evil.example"}}, http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ws, resp, err := websocket.DefaultDialer.Dial(tt.url, tt.header)
+			if ws != nil {
+				defer ws.Close()
+			}
+			if tt.status != http.StatusSwitchingProtocols {
+				assert.ErrorIs(t, err, websocket.ErrBadHandshake)
+			}
+			if assert.NotNil(t, resp) {
+				assert.Equal(t, tt.status, resp.StatusCode)
+			}
+		})
+	}
+}
+
+func TestMessageHandler_Broadcast(t *testing.T) {
 	uc := new(MockMessageUseCase)
+	uc.On("SaveMessage", mock.Anything).Return(nil)
 
-	// Создаем MessageHandler
-	handler := NewMessageHandler(uc)
+	router := gin.New()
+	router.GET("/ws", newTestHandler(t, uc).HandleConnections)
 
-	// Создаем канал для тестирования
-	broadcast := make(chan entity.Message)
-	myWeb.Broadcast = broadcast
+	server := httptest.NewServer(router)
+	defer server.Close()
+	url := "ws" + server.URL[4:] + "/ws"
+
+	sender, _, err := websocket.DefaultDialer.Dial(url, authHeader)
+	require.NoError(t, err)
+	defer sender.Close()
+	listener := dialRoom(t, url, 1)
+	outsider := dialRoom(t, url, 3)
 
-	// Запускаем горутину для обработки сообщений
-	go handler.HandleMessages()
+	require.NoError(t, sender.WriteJSON(entity.Frame{Type: entity.FrameMessage, RoomID: 1, Message: "Hello, World!"}))
+	require.NoError(t, sender.WriteJSON(entity.Frame{Type: entity.FrameMessage, RoomID: 3, Message: "Hello, room 3!"}))
 
-	// Отправляем сообщение в канал
-	msg := entity.Message{Username: "testuser", Message: "Hello, World!"}
-	broadcast <- msg
+	var got entity.Frame
+	listener.SetReadDeadline(time.Now().Add(time.Second))
+	require.NoError(t, listener.ReadJSON(&got))
+	assert.Equal(t, entity.Frame{Type: entity.FrameMessage, RoomID: 1, ID: 100, UserID: 7, Username: "alice", Message: "Hello, World!", CreatedAt: &savedAt}, got)
 
-	// Проверяем, что сообщение было отправлено всем клиентам
-	// (здесь можно добавить проверку для клиентов, если они есть)
+	// Room 1 is not delivered to room 3.
+	outsider.SetReadDeadline(time.Now().Add(time.Second))
+	require.NoError(t, outsider.ReadJSON(&got))
+	assert.Equal(t, "Hello, room 3!", got.Message)
 }
 
 func TestMessageHandler_GetMessages_Error(t *testing.T) {
 	uc := new(MockMessageUseCase)
-	uc.On("GetMessages").Return(nil, errors.New("database error"))
+	uc.On("GetMessages", int64(7), entity.MessageFilter{RoomID: 1}).Return(nil, errors.New("database error"))
 
-	handler := NewMessageHandler(uc)
-	router := gin.Default()
-	router.GET("/messages", handler.GetMessages)
+	router := newMessagesRouter(newTestHandler(t, uc))
 
-	req, _ := http.NewRequest("GET", "/messages", nil)
+	req, _ := http.NewRequest("GET", "/rooms/1/messages", nil)
+	req.Header.Set("Authorization", "Bearer token")
 	w := httptest.NewRecorder()
 	router.ServeHTTP(w, req)
 
 	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.JSONEq(t, `{"error":"internal server error","code":"internal"}`, w.Body.String())
 	uc.AssertExpectations(t)
 }
@@ -0,0 +1,329 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"backend.com/forum/apperrors"
+	"github.com/Ulyana-kru00/forum-project/chat/internal/entity"
+	"github.com/Ulyana-kru00/forum-project/chat/internal/usecase"
+	myWeb "github.com/Ulyana-kru00/forum-project/chat/pkg/websocket"
+
+	"github.com/gin-gonic/gin"
+)
+
+var (
+	errInvalidBody   = apperrors.New(apperrors.ErrInvalidArgument, "invalid request body")
+	errInvalidUserID = apperrors.New(apperrors.ErrInvalidArgument, "invalid user id")
+)
+
+// RoomHandler manages rooms over REST. Membership changes are pushed to the
+// hub, so removed users stop receiving the room at once.
+type RoomHandler struct {
+	Uc  usecase.RoomUseCase
+	Hub *myWeb.Hub
+}
+
+func NewRoomHandler(uc usecase.RoomUseCase, hub *myWeb.Hub) *RoomHandler {
+	return &RoomHandler{Uc: uc, Hub: hub}
+}
+
+type createRoomRequest struct {
+	Name    string `json:"name" binding:"required" example:"general"`
+	Private bool   `json:"private" example:"false"`
+}
+
+type addMemberRequest struct {
+	UserID int64 `json:"user_id" binding:"required" example:"32"`
+}
+
+// CreateRoom создает комнату.
+//
+// @Summary Создать комнату
+// @Description Создатель становится владельцем и первым участником. В закрытую комнату можно попасть только по приглашению владельца
+// @Tags rooms
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param request body createRoomRequest true "Название и тип комнаты"
+// @Success 201 {object} entity.Room
+// @Failure 400 {object} entity.ErrorResponse
+// @Failure 401 {object} entity.ErrorResponse
+// @Failure 409 {object} entity.ErrorResponse
+// @Failure 500 {object} entity.ErrorResponse
+// @Router /rooms [post]
+func (h *RoomHandler) CreateRoom(c *gin.Context) {
+	var req createRoomRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		writeError(c, errInvalidBody)
+		return
+	}
+
+	room, err := h.Uc.CreateRoom(caller(c).UserID, req.Name, req.Private)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, room)
+}
+
+// ListRooms возвращает доступные комнаты.
+//
+// @Summary Список комнат
+// @Description Открытые комнаты и закрытые комнаты, в которых состоит пользователь
+// @Tags rooms
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {array} entity.Room
+// @Failure 401 {object} entity.ErrorResponse
+// @Failure 500 {object} entity.ErrorResponse
+// @Router /rooms [get]
+func (h *RoomHandler) ListRooms(c *gin.Context) {
+	rooms, err := h.Uc.ListRooms(caller(c).UserID)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, rooms)
+}
+
+// GetRoom возвращает комнату.
+//
+// @Summary Получить комнату
+// @Tags rooms
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "ID комнаты"
+// @Success 200 {object} entity.Room
+// @Failure 400 {object} entity.ErrorResponse
+// @Failure 401 {object} entity.ErrorResponse
+// @Failure 404 {object} entity.ErrorResponse
+// @Failure 500 {object} entity.ErrorResponse
+// @Router /rooms/{id} [get]
+func (h *RoomHandler) GetRoom(c *gin.Context) {
+	roomID, ok := roomIDParam(c)
+	if !ok {
+		return
+	}
+
+	room, err := h.Uc.GetRoom(caller(c).UserID, roomID)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, room)
+}
+
+// UpdateRoom меняет настройки комнаты.
+//
+// @Summary Изменить комнату
+// @Description Доступно только владельцу, комнатой без владельца управляют администраторы. Не указанные поля не меняются. retention_seconds: срок хранения сообщений, 0 — хранить всегда, -1 — срок по умолчанию
+// @Tags rooms
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "ID комнаты"
+// @Param request body entity.RoomUpdate true "Новые настройки"
+// @Success 200 {object} entity.Room
+// @Failure 400 {object} entity.ErrorResponse
+// @Failure 401 {object} entity.ErrorResponse
+// @Failure 403 {object} entity.ErrorResponse
+// @Failure 404 {object} entity.ErrorResponse
+// @Failure 409 {object} entity.ErrorResponse
+// @Failure 500 {object} entity.ErrorResponse
+// @Router /rooms/{id} [patch]
+func (h *RoomHandler) UpdateRoom(c *gin.Context) {
+	roomID, ok := roomIDParam(c)
+	if !ok {
+		return
+	}
+	var update entity.RoomUpdate
+	if err := c.ShouldBindJSON(&update); err != nil {
+		writeError(c, errInvalidBody)
+		return
+	}
+
+	room, err := h.Uc.UpdateRoom(caller(c), roomID, update)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, room)
+}
+
+// DeleteRoom удаляет комнату вместе с историей.
+//
+// @Summary Удалить комнату
+// @Description Доступно только владельцу, комнатой без владельца управляют администраторы. Подключенные участники получают кадр left
+// @Tags rooms
+// @Security ApiKeyAuth
+// @Param id path int true "ID комнаты"
+// @Success 204
+// @Failure 400 {object} entity.ErrorResponse
+// @Failure 401 {object} entity.ErrorResponse
+// @Failure 403 {object} entity.ErrorResponse
+// @Failure 404 {object} entity.ErrorResponse
+// @Failure 500 {object} entity.ErrorResponse
+// @Router /rooms/{id} [delete]
+func (h *RoomHandler) DeleteRoom(c *gin.Context) {
+	roomID, ok := roomIDParam(c)
+	if !ok {
+		return
+	}
+
+	if err := h.Uc.DeleteRoom(caller(c), roomID); err != nil {
+		writeError(c, err)
+		return
+	}
+	h.Hub.Evict(roomID, 0)
+	c.Status(http.StatusNoContent)
+}
+
+// JoinRoom добавляет пользователя в открытую комнату.
+//
+// @Summary Вступить в комнату
+// @Description В закрытую комнату вступить нельзя, нужно приглашение владельца
+// @Tags rooms
+// @Security ApiKeyAuth
+// @Param id path int true "ID комнаты"
+// @Success 204
+// @Failure 400 {object} entity.ErrorResponse
+// @Failure 401 {object} entity.ErrorResponse
+// @Failure 404 {object} entity.ErrorResponse
+// @Failure 500 {object} entity.ErrorResponse
+// @Router /rooms/{id}/join [post]
+func (h *RoomHandler) JoinRoom(c *gin.Context) {
+	roomID, ok := roomIDParam(c)
+	if !ok {
+		return
+	}
+
+	if err := h.Uc.JoinRoom(caller(c).UserID, roomID); err != nil {
+		writeError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// LeaveRoom выводит пользователя из комнаты.
+//
+// @Summary Покинуть комнату
+// @Description Владелец покинуть комнату не может, он может только удалить ее
+// @Tags rooms
+// @Security ApiKeyAuth
+// @Param id path int true "ID комнаты"
+// @Success 204
+// @Failure 400 {object} entity.ErrorResponse
+// @Failure 401 {object} entity.ErrorResponse
+// @Failure 404 {object} entity.ErrorResponse
+// @Failure 500 {object} entity.ErrorResponse
+// @Router /rooms/{id}/leave [post]
+func (h *RoomHandler) LeaveRoom(c *gin.Context) {
+	roomID, ok := roomIDParam(c)
+	if !ok {
+		return
+	}
+	userID := caller(c).UserID
+
+	if err := h.Uc.LeaveRoom(userID, roomID); err != nil {
+		writeError(c, err)
+		return
+	}
+	h.Hub.Evict(roomID, userID)
+	c.Status(http.StatusNoContent)
+}
+
+// ListMembers возвращает участников комнаты.
+//
+// @Summary Участники комнаты
+// @Tags rooms
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "ID комнаты"
+// @Success 200 {array} entity.RoomMember
+// @Failure 400 {object} entity.ErrorResponse
+// @Failure 401 {object} entity.ErrorResponse
+// @Failure 404 {object} entity.ErrorResponse
+// @Failure 500 {object} entity.ErrorResponse
+// @Router /rooms/{id}/members [get]
+func (h *RoomHandler) ListMembers(c *gin.Context) {
+	roomID, ok := roomIDParam(c)
+	if !ok {
+		return
+	}
+
+	members, err := h.Uc.ListMembers(caller(c).UserID, roomID)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, members)
+}
+
+// AddMember приглашает пользователя в комнату.
+//
+// @Summary Пригласить в комнату
+// @Description Доступно только владельцу, комнатой без владельца управляют администраторы
+// @Tags rooms
+// @Accept json
+// @Security ApiKeyAuth
+// @Param id path int true "ID комнаты"
+// @Param request body addMemberRequest true "Кого пригласить"
+// @Success 204
+// @Failure 400 {object} entity.ErrorResponse
+// @Failure 401 {object} entity.ErrorResponse
+// @Failure 403 {object} entity.ErrorResponse
+// @Failure 404 {object} entity.ErrorResponse
+// @Failure 500 {object} entity.ErrorResponse
+// @Router /rooms/{id}/members [post]
+func (h *RoomHandler) AddMember(c *gin.Context) {
+	roomID, ok := roomIDParam(c)
+	if !ok {
+		return
+	}
+	var req addMemberRequest
+	if err := c.ShouldBindJSON(&req); err != nil || req.UserID <= 0 {
+		writeError(c, errInvalidBody)
+		return
+	}
+
+	if err := h.Uc.AddMember(caller(c), roomID, req.UserID); err != nil {
+		writeError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// RemoveMember исключает участника из комнаты.
+//
+// @Summary Исключить из комнаты
+// @Description Доступно только владельцу, комнатой без владельца управляют администраторы. Подключенный участник сразу перестает получать сообщения комнаты
+// @Tags rooms
+// @Security ApiKeyAuth
+// @Param id path int true "ID комнаты"
+// @Param user_id path int true "ID участника"
+// @Success 204
+// @Failure 400 {object} entity.ErrorResponse
+// @Failure 401 {object} entity.ErrorResponse
+// @Failure 403 {object} entity.ErrorResponse
+// @Failure 404 {object} entity.ErrorResponse
+// @Failure 500 {object} entity.ErrorResponse
+// @Router /rooms/{id}/members/{user_id} [delete]
+func (h *RoomHandler) RemoveMember(c *gin.Context) {
+	roomID, ok := roomIDParam(c)
+	if !ok {
+		return
+	}
+	memberID, err := strconv.ParseInt(c.Param("user_id"), 10, 64)
+	if err != nil || memberID <= 0 {
+		writeError(c, errInvalidUserID)
+		return
+	}
+
+	if err := h.Uc.RemoveMember(caller(c), roomID, memberID); err != nil {
+		writeError(c, err)
+		return
+	}
+	h.Hub.Evict(roomID, memberID)
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,161 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"backend.com/forum/apperrors"
+	"github.com/Ulyana-kru00/forum-project/chat/internal/entity"
+	"github.com/Ulyana-kru00/forum-project/chat/internal/repository"
+	"github.com/Ulyana-kru00/forum-project/chat/pkg/auth"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockRoomUseCase struct {
+	mock.Mock
+}
+
+func (m *MockRoomUseCase) CreateRoom(userID int64, name string, private bool) (*entity.Room, error) {
+	args := m.Called(userID, name, private)
+	room, _ := args.Get(0).(*entity.Room)
+	return room, args.Error(1)
+}
+
+func (m *MockRoomUseCase) ListRooms(userID int64) ([]entity.Room, error) {
+	args := m.Called(userID)
+	return args.Get(0).([]entity.Room), args.Error(1)
+}
+
+func (m *MockRoomUseCase) GetRoom(userID, roomID int64) (*entity.Room, error) {
+	args := m.Called(userID, roomID)
+	room, _ := args.Get(0).(*entity.Room)
+	return room, args.Error(1)
+}
+
+func (m *MockRoomUseCase) UpdateRoom(caller *auth.Identity, roomID int64, update entity.RoomUpdate) (*entity.Room, error) {
+	args := m.Called(caller.UserID, roomID, update)
+	room, _ := args.Get(0).(*entity.Room)
+	return room, args.Error(1)
+}
+
+func (m *MockRoomUseCase) DeleteRoom(caller *auth.Identity, roomID int64) error {
+	return m.Called(caller.UserID, roomID).Error(0)
+}
+
+func (m *MockRoomUseCase) IsMember(userID, roomID int64) (bool, error) {
+	args := m.Called(userID, roomID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockRoomUseCase) JoinRoom(userID, roomID int64) error {
+	return m.Called(userID, roomID).Error(0)
+}
+
+func (m *MockRoomUseCase) LeaveRoom(userID, roomID int64) error {
+	return m.Called(userID, roomID).Error(0)
+}
+
+func (m *MockRoomUseCase) ListMembers(userID, roomID int64) ([]entity.RoomMember, error) {
+	args := m.Called(userID, roomID)
+	return args.Get(0).([]entity.RoomMember), args.Error(1)
+}
+
+func (m *MockRoomUseCase) AddMember(caller *auth.Identity, roomID, memberID int64) error {
+	return m.Called(caller.UserID, roomID, memberID).Error(0)
+}
+
+func (m *MockRoomUseCase) RemoveMember(caller *auth.Identity, roomID, memberID int64) error {
+	return m.Called(caller.UserID, roomID, memberID).Error(0)
+}
+
+func newRoomRouter(t *testing.T, uc *MockRoomUseCase) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	h := NewRoomHandler(uc, startHub(t))
+
+	r := gin.New()
+	rooms := r.Group("/rooms", RequireAuth(tokenAuth{}))
+	rooms.GET("", h.ListRooms)
+	rooms.POST("", h.CreateRoom)
+	rooms.GET("/:id", h.GetRoom)
+	rooms.PATCH("/:id", h.UpdateRoom)
+	rooms.DELETE("/:id", h.DeleteRoom)
+	rooms.POST("/:id/join", h.JoinRoom)
+	rooms.POST("/:id/leave", h.LeaveRoom)
+	rooms.GET("/:id/members", h.ListMembers)
+	rooms.POST("/:id/members", h.AddMember)
+	rooms.DELETE("/:id/members/:user_id", h.RemoveMember)
+	return r
+}
+
+func TestRoomHandler(t *testing.T) {
+	name := "lobby"
+	mockUC := new(MockRoomUseCase)
+	mockUC.On("CreateRoom", int64(7), "go", true).Return(&entity.Room{ID: 3, Name: "go", Private: true, OwnerID: 7}, nil)
+	mockUC.On("CreateRoom", int64(7), "general", false).Return(nil, repository.ErrRoomNameExists)
+	mockUC.On("ListRooms", int64(7)).Return([]entity.Room{{ID: 1, Name: "general"}}, nil)
+	mockUC.On("GetRoom", int64(7), int64(2)).Return(nil, repository.ErrRoomNotFound)
+	mockUC.On("UpdateRoom", int64(7), int64(1), entity.RoomUpdate{Name: &name}).
+		Return(nil, apperrors.New(apperrors.ErrPermissionDenied, "only the room owner can do this"))
+	mockUC.On("DeleteRoom", int64(7), int64(3)).Return(nil)
+	mockUC.On("JoinRoom", int64(7), int64(1)).Return(nil)
+	mockUC.On("LeaveRoom", int64(7), int64(1)).Return(nil)
+	mockUC.On("ListMembers", int64(7), int64(3)).Return([]entity.RoomMember{{UserID: 7}, {UserID: 8}}, nil)
+	mockUC.On("AddMember", int64(7), int64(3), int64(8)).Return(nil)
+	mockUC.On("RemoveMember", int64(7), int64(3), int64(8)).Return(nil)
+	r := newRoomRouter(t, mockUC)
+
+	tests := []struct {
+		name   string
+		method string
+		url    string
+		body   string
+		status int
+		want   string
+	}{
+		{"Create", http.MethodPost, "/rooms", `{"name":"go","private":true}`, http.StatusCreated, `"owner_id":7`},
+		{"Create taken name", http.MethodPost, "/rooms", `{"name":"general"}`, http.StatusConflict, `"code":"already_exists"`},
+		{"Create without name", http.MethodPost, "/rooms", `{"private":true}`, http.StatusBadRequest, `"code":"invalid_argument"`},
+		{"List", http.MethodGet, "/rooms", "", http.StatusOK, `"name":"general"`},
+		{"Get hidden", http.MethodGet, "/rooms/2", "", http.StatusNotFound, `"code":"not_found"`},
+		{"Get bad id", http.MethodGet, "/rooms/x", "", http.StatusBadRequest, `"code":"invalid_argument"`},
+		{"Update not owner", http.MethodPatch, "/rooms/1", `{"name":"lobby"}`, http.StatusForbidden, `"code":"permission_denied"`},
+		{"Delete", http.MethodDelete, "/rooms/3", "", http.StatusNoContent, ""},
+		{"Join", http.MethodPost, "/rooms/1/join", "", http.StatusNoContent, ""},
+		{"Leave", http.MethodPost, "/rooms/1/leave", "", http.StatusNoContent, ""},
+		{"Members", http.MethodGet, "/rooms/3/members", "", http.StatusOK, `"user_id":8`},
+		{"Invite", http.MethodPost, "/rooms/3/members", `{"user_id":8}`, http.StatusNoContent, ""},
+		{"Invite bad body", http.MethodPost, "/rooms/3/members", `{"user_id":-1}`, http.StatusBadRequest, `"code":"invalid_argument"`},
+		{"Remove", http.MethodDelete, "/rooms/3/members/8", "", http.StatusNoContent, ""},
+		{"Remove bad user", http.MethodDelete, "/rooms/3/members/me", "", http.StatusBadRequest, `"code":"invalid_argument"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.url, strings.NewReader(tt.body))
+			req.Header.Set("Authorization", "Bearer token")
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+			assert.Equal(t, tt.status, w.Code)
+			assert.Contains(t, w.Body.String(), tt.want)
+		})
+	}
+	mockUC.AssertExpectations(t)
+}
+
+func TestRequireAuth(t *testing.T) {
+	r := newRoomRouter(t, new(MockRoomUseCase))
+
+	for _, header := range []string{"", "token", "Bearer forged"} {
+		req := httptest.NewRequest(http.MethodGet, "/rooms", nil)
+		req.Header.Set("Authorization", header)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusUnauthorized, w.Code, header)
+		assert.Contains(t, w.Body.String(), `"code":"unauthenticated"`)
+	}
+}
@@ -5,13 +5,27 @@ import (
 	"database/sql"
 	"fmt"
 	"log"
+	"slices"
+	"time"
 
 	"github.com/Ulyana-kru00/forum-project/chat/internal/entity"
+
+	"github.com/lib/pq"
 )
 
 type MessageRepository interface {
-	SaveMessage(msg entity.Message) error
-	GetMessages() ([]entity.Message, error)
+	// SaveMessage stores msg and sets its ID and CreatedAt.
+	SaveMessage(msg *entity.Message) error
+	// GetMessages returns the visible messages selected by filter, oldest
+	// first.
+	GetMessages(filter entity.MessageFilter) ([]entity.Message, error)
+
+	// ExpiredMessages returns up to limit messages, oldest first, that have
+	// outlived the retention of their room at now. Rooms without their own
+	// setting keep messages for defaultRetention; a retention of 0 keeps
+	// them forever.
+	ExpiredMessages(now time.Time, defaultRetention time.Duration, limit int) ([]entity.Message, error)
+	DeleteMessages(ids []int64) (int64, error)
 }
 
 type messageRepository struct {
@@ -22,9 +36,9 @@ func NewMessageRepository(db *sql.DB) MessageRepository {
 	return &messageRepository{db: db}
 }
 
-func (repo *messageRepository) SaveMessage(msg entity.Message) error {
-	query := `INSERT INTO chat_messages (user_id, username, content) VALUES ($1, $2, $3)`
-	_, err := repo.db.Exec(query, 32, msg.Username, msg.Message)
+func (repo *messageRepository) SaveMessage(msg *entity.Message) error {
+	query := `INSERT INTO chat_messages (room_id, user_id, username, content) VALUES ($1, $2, $3, $4) RETURNING id, timestamp`
+	err := repo.db.QueryRow(query, msg.RoomID, msg.UserID, msg.Username, msg.Message).Scan(&msg.ID, &msg.CreatedAt)
 	if err != nil {
 		log.Printf("Error saving message: %v", err)
 		return err
@@ -32,24 +46,75 @@ func (repo *messageRepository) SaveMessage(msg entity.Message) error {
 	return nil
 }
 
-func (repo *messageRepository) GetMessages() ([]entity.Message, error) {
-	rows, err := repo.db.Query("SELECT id, username, content FROM chat_messages")
+// GetMessages reads the newest matching messages first, which the index on
+// (room_id, id) serves directly, and then puts them in order.
+func (repo *messageRepository) GetMessages(filter entity.MessageFilter) ([]entity.Message, error) {
+	query := "SELECT id, room_id, user_id, username, content, timestamp FROM chat_messages WHERE room_id = $1 AND hidden_at IS NULL"
+	args := []any{filter.RoomID}
+	if filter.Before > 0 {
+		args = append(args, filter.Before)
+		query += fmt.Sprintf(" AND id < $%d", len(args))
+	}
+	if filter.After > 0 {
+		args = append(args, filter.After)
+		query += fmt.Sprintf(" AND id > $%d", len(args))
+	}
+	args = append(args, filter.Limit)
+	query += fmt.Sprintf(" ORDER BY id DESC LIMIT $%d", len(args))
+
+	rows, err := repo.db.Query(query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("query error: %w", err)
 	}
+	messages, err := scanMessages(rows)
+	if err != nil {
+		return nil, err
+	}
+	slices.Reverse(messages)
+	return messages, nil
+}
+
+func (repo *messageRepository) ExpiredMessages(now time.Time, defaultRetention time.Duration, limit int) ([]entity.Message, error) {
+	rows, err := repo.db.Query(`
+		SELECT m.id, m.room_id, m.user_id, m.username, m.content, m.timestamp
+		FROM chat_messages m
+		JOIN chat_rooms r ON r.id = m.room_id
+		WHERE COALESCE(r.retention_seconds, $2) > 0
+		  AND m.timestamp < $1::timestamptz - COALESCE(r.retention_seconds, $2) * INTERVAL '1 second'
+		ORDER BY m.id
+		LIMIT $3`,
+		now, int64(defaultRetention/time.Second), limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query error: %w", err)
+	}
+	return scanMessages(rows)
+}
+
+func (repo *messageRepository) DeleteMessages(ids []int64) (int64, error) {
+	res, err := repo.db.Exec(`DELETE FROM chat_messages WHERE id = ANY($1)`, pq.Array(ids))
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+// scanMessages reads and closes rows of id, room_id, user_id, username,
+// content and timestamp.
+func scanMessages(rows *sql.Rows) ([]entity.Message, error) {
 	defer rows.Close()
 
-	var messages []entity.Message
+	messages := []entity.Message{}
 	for rows.Next() {
 		var msg entity.Message
 		// Исправленный маппинг столбцов
-		err := rows.Scan(&msg.ID, &msg.Username, &msg.Message)
+		err := rows.Scan(&msg.ID, &msg.RoomID, &msg.UserID, &msg.Username, &msg.Message, &msg.CreatedAt)
 		if err != nil {
 			return nil, fmt.Errorf("scan error: %w", err)
 		}
 		messages = append(messages, msg)
 	}
-	return messages, nil
+	return messages, rows.Err()
 }
 
 // // internal/repository/message_repository.go
@@ -4,11 +4,14 @@ package repository
 import (
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/Ulyana-kru00/forum-project/chat/internal/entity"
 
 	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestSaveMessage(t *testing.T) {
@@ -19,6 +22,7 @@ func TestSaveMessage(t *testing.T) {
 	defer db.Close()
 
 	repo := NewMessageRepository(db)
+	now := time.Now()
 
 	tests := []struct {
 		name    string
@@ -29,25 +33,29 @@ func TestSaveMessage(t *testing.T) {
 		{
 			name: "successful message save",
 			msg: entity.Message{
+				RoomID:   1,
+				UserID:   7,
 				Username: "testuser",
 				Message:  "Hello world",
 			},
 			mock: func() {
-				mock.ExpectExec("INSERT INTO chat_messages").
-					WithArgs("testuser", "Hello world").
-					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectQuery(`INSERT INTO chat_messages \(room_id, user_id, username, content\) VALUES \(\$1, \$2, \$3, \$4\) RETURNING id, timestamp`).
+					WithArgs(int64(1), int64(7), "testuser", "Hello world").
+					WillReturnRows(sqlmock.NewRows([]string{"id", "timestamp"}).AddRow(42, now))
 			},
 			wantErr: false,
 		},
 		{
 			name: "database error on save",
 			msg: entity.Message{
+				RoomID:   1,
+				UserID:   7,
 				Username: "testuser",
 				Message:  "Hello world",
 			},
 			mock: func() {
-				mock.ExpectExec("INSERT INTO chat_messages").
-					WithArgs("testuser", "Hello world").
+				mock.ExpectQuery("INSERT INTO chat_messages").
+					WithArgs(int64(1), int64(7), "testuser", "Hello world").
 					WillReturnError(errors.New("database error"))
 			},
 			wantErr: true,
@@ -55,13 +63,15 @@ func TestSaveMessage(t *testing.T) {
 		{
 			name: "empty username",
 			msg: entity.Message{
+				RoomID:   1,
+				UserID:   7,
 				Username: "",
 				Message:  "test",
 			},
 			mock: func() {
-				mock.ExpectExec("INSERT INTO chat_messages").
-					WithArgs("", "test").
-					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectQuery("INSERT INTO chat_messages").
+					WithArgs(int64(1), int64(7), "", "test").
+					WillReturnRows(sqlmock.NewRows([]string{"id", "timestamp"}).AddRow(43, now))
 			},
 			wantErr: false,
 		},
@@ -70,8 +80,12 @@ func TestSaveMessage(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.mock()
-			err := repo.SaveMessage(tt.msg)
+			err := repo.SaveMessage(&tt.msg)
 			assert.Equal(t, tt.wantErr, err != nil)
+			if !tt.wantErr {
+				assert.NotZero(t, tt.msg.ID)
+				assert.Equal(t, now, tt.msg.CreatedAt)
+			}
 			assert.NoError(t, mock.ExpectationsWereMet())
 		})
 	}
@@ -85,44 +99,50 @@ func TestGetMessages(t *testing.T) {
 	defer db.Close()
 
 	repo := NewMessageRepository(db)
+	now := time.Now()
+	columns := []string{"id", "room_id", "user_id", "username", "content", "timestamp"}
 
 	tests := []struct {
 		name    string
+		filter  entity.MessageFilter
 		mock    func()
 		want    []entity.Message
 		wantErr bool
 	}{
 		{
-			name: "successful get messages",
+			name:   "latest page",
+			filter: entity.MessageFilter{RoomID: 1, Limit: 2},
 			mock: func() {
-				rows := sqlmock.NewRows([]string{"id", "username", "content"}).
-					AddRow(1, "user1", "message 1").
-					AddRow(2, "user2", "message 2")
-				mock.ExpectQuery("SELECT id, username, content FROM chat_messages").
+				// Newest first from the database, oldest first to the caller.
+				rows := sqlmock.NewRows(columns).
+					AddRow(2, 1, 8, "user2", "message 2", now).
+					AddRow(1, 1, 7, "user1", "message 1", now)
+				mock.ExpectQuery(`SELECT id, room_id, user_id, username, content, timestamp FROM chat_messages WHERE room_id = \$1 AND hidden_at IS NULL ORDER BY id DESC LIMIT \$2`).
+					WithArgs(int64(1), 2).
 					WillReturnRows(rows)
 			},
 			want: []entity.Message{
-				{ID: 1, Username: "user1", Message: "message 1"},
-				{ID: 2, Username: "user2", Message: "message 2"},
+				{ID: 1, RoomID: 1, UserID: 7, Username: "user1", Message: "message 1", CreatedAt: now},
+				{ID: 2, RoomID: 1, UserID: 8, Username: "user2", Message: "message 2", CreatedAt: now},
 			},
-			wantErr: false,
 		},
 		{
-			name: "empty result",
+			name:   "before and after",
+			filter: entity.MessageFilter{RoomID: 1, Before: 50, After: 10, Limit: 20},
 			mock: func() {
-				rows := sqlmock.NewRows([]string{"id", "username", "content"})
-				mock.ExpectQuery("SELECT id, username, content FROM chat_messages").
-					WillReturnRows(rows)
+				mock.ExpectQuery(`WHERE room_id = \$1 AND hidden_at IS NULL AND id < \$2 AND id > \$3 ORDER BY id DESC LIMIT \$4`).
+					WithArgs(int64(1), 50, 10, 20).
+					WillReturnRows(sqlmock.NewRows(columns))
 			},
-			want:    []entity.Message{},
-			wantErr: false,
+			want: []entity.Message{},
 		},
 		{
-			name: "scan error",
+			name:   "scan error",
+			filter: entity.MessageFilter{RoomID: 1, Limit: 20},
 			mock: func() {
 				rows := sqlmock.NewRows([]string{"id", "username"}).
 					AddRow(1, "user1")
-				mock.ExpectQuery("SELECT id, username, content FROM chat_messages").
+				mock.ExpectQuery("SELECT id, room_id, user_id, username, content, timestamp FROM chat_messages WHERE room_id = \\$1").
 					WillReturnRows(rows)
 			},
 			want:    nil,
@@ -133,7 +153,7 @@ func TestGetMessages(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.mock()
-			messages, err := repo.GetMessages()
+			messages, err := repo.GetMessages(tt.filter)
 			assert.Equal(t, tt.wantErr, err != nil)
 			assert.Equal(t, tt.want, messages)
 			assert.NoError(t, mock.ExpectationsWereMet())
@@ -141,6 +161,37 @@ func TestGetMessages(t *testing.T) {
 	}
 }
 
+func TestExpiredMessages(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create mock: %v", err)
+	}
+	defer db.Close()
+
+	repo := NewMessageRepository(db)
+	now := time.Now()
+
+	mock.ExpectQuery(`FROM chat_messages m\s+JOIN chat_rooms r ON r.id = m.room_id\s+WHERE COALESCE\(r.retention_seconds, \$2\) > 0\s+AND m.timestamp < \$1::timestamptz - COALESCE\(r.retention_seconds, \$2\) \* INTERVAL '1 second'\s+ORDER BY m.id\s+LIMIT \$3`).
+		WithArgs(now, int64(86400), 2).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "room_id", "user_id", "username", "content", "timestamp"}).
+			AddRow(1, 1, 7, "user1", "old", now.Add(-48*time.Hour)).
+			AddRow(4, 2, 8, "user2", "older room", now.Add(-48*time.Hour)))
+
+	messages, err := repo.ExpiredMessages(now, 24*time.Hour, 2)
+	require.NoError(t, err)
+	require.Len(t, messages, 2)
+	assert.Equal(t, 4, messages[1].ID)
+
+	mock.ExpectExec(`DELETE FROM chat_messages WHERE id = ANY\(\$1\)`).
+		WithArgs(pq.Array([]int64{1, 4})).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+
+	n, err := repo.DeleteMessages([]int64{1, 4})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), n)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
 // // internal/repository/message_repository_test.go
 // package repository
 
@@ -0,0 +1,211 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"backend.com/forum/apperrors"
+	"github.com/Ulyana-kru00/forum-project/chat/internal/entity"
+
+	"github.com/lib/pq"
+)
+
+var (
+	ErrRoomNotFound   = apperrors.New(apperrors.ErrNotFound, "room not found")
+	ErrRoomNameExists = apperrors.New(apperrors.ErrAlreadyExists, "room name already taken")
+	ErrUserNotFound   = apperrors.New(apperrors.ErrNotFound, "user not found")
+)
+
+// PostgreSQL SQLSTATE codes for constraint failures.
+const (
+	uniqueViolation     = "23505"
+	foreignKeyViolation = "23503"
+)
+
+type RoomRepository interface {
+	// CreateRoom stores room and makes its owner the first member.
+	CreateRoom(room *entity.Room) error
+	GetRoom(id int64) (*entity.Room, error)
+	// ListRooms returns the public rooms and the private rooms userID
+	// belongs to.
+	ListRooms(userID int64) ([]entity.Room, error)
+	UpdateRoom(room *entity.Room) error
+	DeleteRoom(id int64) error
+
+	IsMember(roomID, userID int64) (bool, error)
+	// AddMember is a no-op for users who already are members.
+	AddMember(roomID, userID int64) error
+	RemoveMember(roomID, userID int64) error
+	ListMembers(roomID int64) ([]entity.RoomMember, error)
+}
+
+type roomRepository struct {
+	db *sql.DB
+}
+
+func NewRoomRepository(db *sql.DB) RoomRepository {
+	return &roomRepository{db: db}
+}
+
+const roomSelect = `SELECT id, name, private, COALESCE(owner_id, 0), retention_seconds, created_at FROM chat_rooms`
+
+// scanRoom reads a row selected with roomSelect.
+func scanRoom(row interface{ Scan(dest ...any) error }) (entity.Room, error) {
+	var (
+		room      entity.Room
+		retention sql.NullInt64
+	)
+	err := row.Scan(&room.ID, &room.Name, &room.Private, &room.OwnerID, &retention, &room.CreatedAt)
+	if retention.Valid {
+		room.RetentionSeconds = &retention.Int64
+	}
+	return room, err
+}
+
+func (repo *roomRepository) CreateRoom(room *entity.Room) error {
+	tx, err := repo.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	err = tx.QueryRow(
+		`INSERT INTO chat_rooms (name, private, owner_id) VALUES ($1, $2, $3) RETURNING id, created_at`,
+		room.Name, room.Private, room.OwnerID,
+	).Scan(&room.ID, &room.CreatedAt)
+	if err != nil {
+		if isPQError(err, uniqueViolation) {
+			return ErrRoomNameExists
+		}
+		return err
+	}
+
+	if _, err := tx.Exec(
+		`INSERT INTO chat_room_members (room_id, user_id) VALUES ($1, $2)`,
+		room.ID, room.OwnerID,
+	); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (repo *roomRepository) GetRoom(id int64) (*entity.Room, error) {
+	room, err := scanRoom(repo.db.QueryRow(roomSelect+` WHERE id = $1`, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrRoomNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &room, nil
+}
+
+func (repo *roomRepository) ListRooms(userID int64) ([]entity.Room, error) {
+	rows, err := repo.db.Query(roomSelect+`
+		WHERE NOT private
+		   OR EXISTS (SELECT 1 FROM chat_room_members m WHERE m.room_id = chat_rooms.id AND m.user_id = $1)
+		ORDER BY name`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("query error: %w", err)
+	}
+	defer rows.Close()
+
+	rooms := []entity.Room{}
+	for rows.Next() {
+		room, err := scanRoom(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan error: %w", err)
+		}
+		rooms = append(rooms, room)
+	}
+	return rooms, rows.Err()
+}
+
+func (repo *roomRepository) UpdateRoom(room *entity.Room) error {
+	res, err := repo.db.Exec(
+		`UPDATE chat_rooms SET name = $1, private = $2, retention_seconds = $3 WHERE id = $4`,
+		room.Name, room.Private, room.RetentionSeconds, room.ID,
+	)
+	if err != nil {
+		if isPQError(err, uniqueViolation) {
+			return ErrRoomNameExists
+		}
+		return err
+	}
+	return expectAffected(res, ErrRoomNotFound)
+}
+
+func (repo *roomRepository) DeleteRoom(id int64) error {
+	res, err := repo.db.Exec(`DELETE FROM chat_rooms WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	return expectAffected(res, ErrRoomNotFound)
+}
+
+func (repo *roomRepository) IsMember(roomID, userID int64) (bool, error) {
+	var member bool
+	err := repo.db.QueryRow(
+		`SELECT EXISTS (SELECT 1 FROM chat_room_members WHERE room_id = $1 AND user_id = $2)`,
+		roomID, userID,
+	).Scan(&member)
+	return member, err
+}
+
+func (repo *roomRepository) AddMember(roomID, userID int64) error {
+	_, err := repo.db.Exec(
+		`INSERT INTO chat_room_members (room_id, user_id) VALUES ($1, $2) ON CONFLICT DO NOTHING`,
+		roomID, userID,
+	)
+	if err != nil && isPQError(err, foreignKeyViolation) {
+		// The room is checked before; what is left is an unknown user.
+		return ErrUserNotFound
+	}
+	return err
+}
+
+func (repo *roomRepository) RemoveMember(roomID, userID int64) error {
+	_, err := repo.db.Exec(
+		`DELETE FROM chat_room_members WHERE room_id = $1 AND user_id = $2`,
+		roomID, userID,
+	)
+	return err
+}
+
+func (repo *roomRepository) ListMembers(roomID int64) ([]entity.RoomMember, error) {
+	rows, err := repo.db.Query(
+		`SELECT user_id, joined_at FROM chat_room_members WHERE room_id = $1 ORDER BY joined_at, user_id`,
+		roomID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query error: %w", err)
+	}
+	defer rows.Close()
+
+	members := []entity.RoomMember{}
+	for rows.Next() {
+		var m entity.RoomMember
+		if err := rows.Scan(&m.UserID, &m.JoinedAt); err != nil {
+			return nil, fmt.Errorf("scan error: %w", err)
+		}
+		members = append(members, m)
+	}
+	return members, rows.Err()
+}
+
+func expectAffected(res sql.Result, notFound error) error {
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return notFound
+	}
+	return nil
+}
+
+func isPQError(err error, code pq.ErrorCode) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == code
+}
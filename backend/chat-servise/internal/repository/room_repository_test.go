@@ -0,0 +1,159 @@
+package repository
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/Ulyana-kru00/forum-project/chat/internal/entity"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newRoomRepoMock(t *testing.T) (RoomRepository, sqlmock.Sqlmock) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create mock: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return NewRoomRepository(db), mock
+}
+
+func TestCreateRoom(t *testing.T) {
+	repo, mock := newRoomRepoMock(t)
+	now := time.Now()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO chat_rooms \(name, private, owner_id\) VALUES \(\$1, \$2, \$3\) RETURNING id, created_at`).
+		WithArgs("go", true, int64(7)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow(3, now))
+	mock.ExpectExec(`INSERT INTO chat_room_members \(room_id, user_id\) VALUES \(\$1, \$2\)`).
+		WithArgs(int64(3), int64(7)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	room := &entity.Room{Name: "go", Private: true, OwnerID: 7}
+	require.NoError(t, repo.CreateRoom(room))
+	assert.Equal(t, int64(3), room.ID)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO chat_rooms`).
+		WillReturnError(&pq.Error{Code: uniqueViolation})
+	mock.ExpectRollback()
+
+	assert.ErrorIs(t, repo.CreateRoom(&entity.Room{Name: "go", OwnerID: 8}), ErrRoomNameExists)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetRoom(t *testing.T) {
+	repo, mock := newRoomRepoMock(t)
+	columns := []string{"id", "name", "private", "owner_id", "retention_seconds", "created_at"}
+
+	mock.ExpectQuery(`SELECT id, name, private, COALESCE\(owner_id, 0\), retention_seconds, created_at FROM chat_rooms WHERE id = \$1`).
+		WithArgs(int64(1)).
+		WillReturnRows(sqlmock.NewRows(columns).AddRow(1, "general", false, 0, nil, time.Now()))
+	room, err := repo.GetRoom(1)
+	require.NoError(t, err)
+	assert.Equal(t, "general", room.Name)
+	assert.Zero(t, room.OwnerID)
+	assert.Nil(t, room.RetentionSeconds)
+
+	mock.ExpectQuery(`FROM chat_rooms WHERE id = \$1`).
+		WithArgs(int64(2)).
+		WillReturnRows(sqlmock.NewRows(columns).AddRow(2, "archive", false, 7, 0, time.Now()))
+	room, err = repo.GetRoom(2)
+	require.NoError(t, err)
+	require.NotNil(t, room.RetentionSeconds)
+	assert.Zero(t, *room.RetentionSeconds)
+
+	mock.ExpectQuery(`FROM chat_rooms WHERE id = \$1`).
+		WithArgs(int64(9)).
+		WillReturnError(sql.ErrNoRows)
+	_, err = repo.GetRoom(9)
+	assert.ErrorIs(t, err, ErrRoomNotFound)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestListRooms(t *testing.T) {
+	repo, mock := newRoomRepoMock(t)
+
+	mock.ExpectQuery(`WHERE NOT private\s+OR EXISTS \(SELECT 1 FROM chat_room_members m WHERE m.room_id = chat_rooms.id AND m.user_id = \$1\)\s+ORDER BY name`).
+		WithArgs(int64(7)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "private", "owner_id", "retention_seconds", "created_at"}).
+			AddRow(1, "general", false, 0, nil, time.Now()).
+			AddRow(3, "go", true, 7, 3600, time.Now()))
+
+	rooms, err := repo.ListRooms(7)
+	require.NoError(t, err)
+	require.Len(t, rooms, 2)
+	assert.True(t, rooms[1].Private)
+	assert.Equal(t, int64(3600), *rooms[1].RetentionSeconds)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdateAndDeleteRoom(t *testing.T) {
+	repo, mock := newRoomRepoMock(t)
+
+	mock.ExpectExec(`UPDATE chat_rooms SET name = \$1, private = \$2, retention_seconds = \$3 WHERE id = \$4`).
+		WithArgs("lobby", false, nil, int64(1)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	assert.NoError(t, repo.UpdateRoom(&entity.Room{ID: 1, Name: "lobby"}))
+
+	week := int64(7 * 24 * 3600)
+	mock.ExpectExec(`UPDATE chat_rooms SET`).
+		WithArgs("lobby", false, week, int64(1)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	assert.NoError(t, repo.UpdateRoom(&entity.Room{ID: 1, Name: "lobby", RetentionSeconds: &week}))
+
+	mock.ExpectExec(`UPDATE chat_rooms`).
+		WillReturnError(&pq.Error{Code: uniqueViolation})
+	assert.ErrorIs(t, repo.UpdateRoom(&entity.Room{ID: 1, Name: "go"}), ErrRoomNameExists)
+
+	mock.ExpectExec(`DELETE FROM chat_rooms WHERE id = \$1`).
+		WithArgs(int64(9)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	assert.ErrorIs(t, repo.DeleteRoom(9), ErrRoomNotFound)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRoomMembers(t *testing.T) {
+	repo, mock := newRoomRepoMock(t)
+
+	mock.ExpectQuery(`SELECT EXISTS \(SELECT 1 FROM chat_room_members WHERE room_id = \$1 AND user_id = \$2\)`).
+		WithArgs(int64(3), int64(7)).
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+	member, err := repo.IsMember(3, 7)
+	require.NoError(t, err)
+	assert.True(t, member)
+
+	mock.ExpectExec(`INSERT INTO chat_room_members \(room_id, user_id\) VALUES \(\$1, \$2\) ON CONFLICT DO NOTHING`).
+		WithArgs(int64(3), int64(8)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	assert.NoError(t, repo.AddMember(3, 8))
+
+	mock.ExpectExec(`INSERT INTO chat_room_members`).
+		WithArgs(int64(3), int64(404)).
+		WillReturnError(&pq.Error{Code: foreignKeyViolation})
+	assert.ErrorIs(t, repo.AddMember(3, 404), ErrUserNotFound)
+
+	mock.ExpectQuery(`SELECT user_id, joined_at FROM chat_room_members WHERE room_id = \$1`).
+		WithArgs(int64(3)).
+		WillReturnRows(sqlmock.NewRows([]string{"user_id", "joined_at"}).
+			AddRow(7, time.Now()).
+			AddRow(8, time.Now()))
+	members, err := repo.ListMembers(3)
+	require.NoError(t, err)
+	assert.Len(t, members, 2)
+
+	mock.ExpectExec(`DELETE FROM chat_room_members WHERE room_id = \$1 AND user_id = \$2`).
+		WithArgs(int64(3), int64(8)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	assert.NoError(t, repo.RemoveMember(3, 8))
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -2,27 +2,73 @@
 package usecase
 
 import (
+	"backend.com/forum/apperrors"
 	"github.com/Ulyana-kru00/forum-project/chat/internal/entity"
-"github.com/Ulyana-kru00/forum-project/chat/internal/repository"
+	"github.com/Ulyana-kru00/forum-project/chat/internal/repository"
+)
+
+var errEmptyMessage = apperrors.New(apperrors.ErrInvalidArgument, "username and message are required")
+
+const (
+	defaultMessagesLimit = 50
+	maxMessagesLimit     = 100
 )
 
 type MessageUseCase interface {
-	SaveMessage(msg entity.Message) error
-	GetMessages() ([]entity.Message, error)
+	// SaveMessage stores a message its author posts to a room they belong
+	// to and sets its ID and CreatedAt.
+	SaveMessage(msg *entity.Message) error
+	// GetMessages returns a page of the history of a room userID may see:
+	// the newest messages matching filter, up to its limit.
+	GetMessages(userID int64, filter entity.MessageFilter) (*entity.MessagePage, error)
 }
 
 type messageUseCase struct {
-	repo repository.MessageRepository
+	repo  repository.MessageRepository
+	rooms RoomUseCase
 }
 
-func NewMessageUseCase(repo repository.MessageRepository) MessageUseCase {
-	return &messageUseCase{repo: repo}
+func NewMessageUseCase(repo repository.MessageRepository, rooms RoomUseCase) MessageUseCase {
+	return &messageUseCase{repo: repo, rooms: rooms}
 }
 
-func (uc *messageUseCase) SaveMessage(msg entity.Message) error {
+func (uc *messageUseCase) SaveMessage(msg *entity.Message) error {
+	if msg.Username == "" || msg.Message == "" {
+		return errEmptyMessage
+	}
+	member, err := uc.rooms.IsMember(msg.UserID, msg.RoomID)
+	if err != nil {
+		return err
+	}
+	if !member {
+		return errNotRoomMember
+	}
 	return uc.repo.SaveMessage(msg)
 }
 
-func (uc *messageUseCase) GetMessages() ([]entity.Message, error) {
-	return uc.repo.GetMessages()
+func (uc *messageUseCase) GetMessages(userID int64, filter entity.MessageFilter) (*entity.MessagePage, error) {
+	if _, err := uc.rooms.GetRoom(userID, filter.RoomID); err != nil {
+		return nil, err
+	}
+	if filter.Limit <= 0 {
+		filter.Limit = defaultMessagesLimit
+	}
+	if filter.Limit > maxMessagesLimit {
+		filter.Limit = maxMessagesLimit
+	}
+
+	// One extra message tells whether there is an older page.
+	limit := filter.Limit
+	filter.Limit++
+	messages, err := uc.repo.GetMessages(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &entity.MessagePage{Messages: messages}
+	if len(messages) > limit {
+		page.Messages = messages[1:]
+		page.NextBefore = page.Messages[0].ID
+	}
+	return page, nil
 }
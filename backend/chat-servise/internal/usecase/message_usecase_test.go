@@ -4,8 +4,11 @@ package usecase
 import (
 	"errors"
 	"testing"
+	"time"
 
+	"backend.com/forum/apperrors"
 	"github.com/Ulyana-kru00/forum-project/chat/internal/entity"
+	"github.com/Ulyana-kru00/forum-project/chat/internal/repository"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -15,42 +18,103 @@ type MockMessageRepository struct {
 	mock.Mock
 }
 
-func (m *MockMessageRepository) SaveMessage(msg entity.Message) error {
-	args := m.Called(msg)
+func (m *MockMessageRepository) SaveMessage(msg *entity.Message) error {
+	args := m.Called(*msg)
+	if args.Error(0) == nil {
+		msg.ID = 42
+	}
 	return args.Error(0)
 }
 
-func (m *MockMessageRepository) GetMessages() ([]entity.Message, error) {
-	args := m.Called()
+func (m *MockMessageRepository) GetMessages(filter entity.MessageFilter) ([]entity.Message, error) {
+	args := m.Called(filter)
 	return args.Get(0).([]entity.Message), args.Error(1)
 }
+
+func (m *MockMessageRepository) ExpiredMessages(now time.Time, defaultRetention time.Duration, limit int) ([]entity.Message, error) {
+	args := m.Called(now, defaultRetention, limit)
+	return args.Get(0).([]entity.Message), args.Error(1)
+}
+
+func (m *MockMessageRepository) DeleteMessages(ids []int64) (int64, error) {
+	args := m.Called(ids)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+// newMessageUseCase returns a message usecase where user 1 belongs to room 1
+// only.
+func newMessageUseCase(repo *MockMessageRepository) MessageUseCase {
+	rooms := new(MockRoomRepository)
+	rooms.On("IsMember", int64(1), int64(1)).Return(true, nil)
+	rooms.On("IsMember", mock.Anything, mock.Anything).Return(false, nil)
+	rooms.On("GetRoom", int64(1)).Return(&entity.Room{ID: 1, Name: "general"}, nil)
+	rooms.On("GetRoom", mock.Anything).Return(nil, repository.ErrRoomNotFound)
+	return NewMessageUseCase(repo, NewRoomUseCase(rooms))
+}
 func TestMessageUseCase_SaveMessage(t *testing.T) {
 	mockRepo := new(MockMessageRepository)
-	uc := NewMessageUseCase(mockRepo)
+	uc := newMessageUseCase(mockRepo)
 
-	mockRepo.On("SaveMessage", entity.Message{Username: "test", Message: "hello"}).Return(nil)
-	err := uc.SaveMessage(entity.Message{Username: "test", Message: "hello"})
+	mockRepo.On("SaveMessage", entity.Message{RoomID: 1, UserID: 1, Username: "test", Message: "hello"}).Return(nil)
+	msg := entity.Message{RoomID: 1, UserID: 1, Username: "test", Message: "hello"}
+	err := uc.SaveMessage(&msg)
 	assert.NoError(t, err)
+	assert.Equal(t, 42, msg.ID)
 
-	mockRepo.On("SaveMessage", entity.Message{Username: "error", Message: "fail"}).Return(errors.New("db error"))
-	err = uc.SaveMessage(entity.Message{Username: "error", Message: "fail"})
+	mockRepo.On("SaveMessage", entity.Message{RoomID: 1, UserID: 1, Username: "error", Message: "fail"}).Return(errors.New("db error"))
+	err = uc.SaveMessage(&entity.Message{RoomID: 1, UserID: 1, Username: "error", Message: "fail"})
 	assert.Error(t, err)
 }
 
+func TestMessageUseCase_SaveMessage_NotMember(t *testing.T) {
+	mockRepo := new(MockMessageRepository)
+	uc := newMessageUseCase(mockRepo)
+
+	err := uc.SaveMessage(&entity.Message{RoomID: 2, UserID: 1, Username: "test", Message: "hello"})
+	assert.ErrorIs(t, err, apperrors.ErrPermissionDenied)
+	mockRepo.AssertNotCalled(t, "SaveMessage", mock.Anything)
+}
+
+func TestMessageUseCase_SaveMessage_Empty(t *testing.T) {
+	mockRepo := new(MockMessageRepository)
+	uc := newMessageUseCase(mockRepo)
+
+	err := uc.SaveMessage(&entity.Message{Username: "test"})
+	assert.True(t, errors.Is(err, apperrors.ErrInvalidArgument))
+	mockRepo.AssertNotCalled(t, "SaveMessage", mock.Anything)
+}
+
 func TestMessageUseCase_GetMessages(t *testing.T) {
 	mockRepo := new(MockMessageRepository)
-	uc := NewMessageUseCase(mockRepo)
+	uc := newMessageUseCase(mockRepo)
+
+	expected := []entity.Message{{ID: 1, RoomID: 1, Username: "user", Message: "test"}}
+	mockRepo.On("GetMessages", entity.MessageFilter{RoomID: 1, Limit: defaultMessagesLimit + 1}).Return(expected, nil).Once()
+	result, err := uc.GetMessages(1, entity.MessageFilter{RoomID: 1})
+	assert.NoError(t, err)
+	assert.Equal(t, &entity.MessagePage{Messages: expected}, result)
 
-	expected := []entity.Message{{ID: 1, Username: "user", Message: "test"}}
-	mockRepo.On("GetMessages").Return(expected, nil)
-	result, err := uc.GetMessages()
+	mockRepo.On("GetMessages", entity.MessageFilter{RoomID: 1, Before: 5, Limit: maxMessagesLimit + 1}).Return([]entity.Message{}, nil).Once()
+	result, err = uc.GetMessages(1, entity.MessageFilter{RoomID: 1, Before: 5, Limit: 1000})
 	assert.NoError(t, err)
-	assert.Equal(t, expected, result)
+	assert.Empty(t, result.Messages)
+	assert.Zero(t, result.NextBefore)
+
+	_, err = uc.GetMessages(1, entity.MessageFilter{RoomID: 2})
+	assert.ErrorIs(t, err, apperrors.ErrNotFound)
+}
+
+func TestMessageUseCase_GetMessages_NextPage(t *testing.T) {
+	mockRepo := new(MockMessageRepository)
+	uc := newMessageUseCase(mockRepo)
 
-	mockRepo.On("GetMessages").Return([]entity.Message{}, nil)
-	result, err = uc.GetMessages()
+	// The extra, oldest message only tells that there is an older page.
+	mockRepo.On("GetMessages", entity.MessageFilter{RoomID: 1, After: 7, Limit: 3}).
+		Return([]entity.Message{{ID: 8}, {ID: 9}, {ID: 11}}, nil)
+	page, err := uc.GetMessages(1, entity.MessageFilter{RoomID: 1, After: 7, Limit: 2})
 	assert.NoError(t, err)
-	assert.Empty(t, result)
+	assert.Equal(t, []entity.Message{{ID: 9}, {ID: 11}}, page.Messages)
+	assert.Equal(t, 9, page.NextBefore)
 }
 
 // func TestMessageUseCase_SaveMessage(t *testing.T) {
@@ -0,0 +1,109 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/Ulyana-kru00/forum-project/chat/internal/entity"
+	"github.com/Ulyana-kru00/forum-project/chat/internal/repository"
+)
+
+const defaultPurgeBatch = 1000
+
+// Archiver keeps a copy of messages before they are purged.
+type Archiver interface {
+	Archive(msgs []entity.Message) error
+}
+
+// RetentionPolicy says how long chat messages are kept.
+type RetentionPolicy struct {
+	// Default applies to rooms without their own retention; 0 keeps
+	// messages forever.
+	Default time.Duration
+	// Batch is how many messages are removed at a time, 1000 if unset.
+	Batch int
+	// Archiver, if set, gets every batch before it is removed.
+	Archiver Archiver
+}
+
+type RetentionUseCase interface {
+	// Purge removes the messages that have outlived their room's retention
+	// and returns how many were removed.
+	Purge(ctx context.Context) (int64, error)
+	// RunPurger purges every interval until ctx is done.
+	RunPurger(ctx context.Context, interval time.Duration)
+}
+
+type retentionUseCase struct {
+	repo   repository.MessageRepository
+	policy RetentionPolicy
+	now    func() time.Time
+}
+
+func NewRetentionUseCase(repo repository.MessageRepository, policy RetentionPolicy) RetentionUseCase {
+	if policy.Batch <= 0 {
+		policy.Batch = defaultPurgeBatch
+	}
+	return &retentionUseCase{repo: repo, policy: policy, now: time.Now}
+}
+
+// Purge works in batches so no single statement holds locks on a large
+// part of the table. A batch that cannot be archived is left in place and
+// ends the run, so nothing is removed without its copy.
+func (uc *retentionUseCase) Purge(ctx context.Context) (int64, error) {
+	now := uc.now()
+	var purged int64
+	for {
+		if err := ctx.Err(); err != nil {
+			return purged, err
+		}
+
+		msgs, err := uc.repo.ExpiredMessages(now, uc.policy.Default, uc.policy.Batch)
+		if err != nil {
+			return purged, err
+		}
+		if len(msgs) == 0 {
+			return purged, nil
+		}
+		if uc.policy.Archiver != nil {
+			if err := uc.policy.Archiver.Archive(msgs); err != nil {
+				return purged, fmt.Errorf("archive messages: %w", err)
+			}
+		}
+
+		ids := make([]int64, len(msgs))
+		for i, msg := range msgs {
+			ids[i] = int64(msg.ID)
+		}
+		n, err := uc.repo.DeleteMessages(ids)
+		if err != nil {
+			return purged, err
+		}
+		purged += n
+		if len(msgs) < uc.policy.Batch {
+			return purged, nil
+		}
+	}
+}
+
+func (uc *retentionUseCase) RunPurger(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			n, err := uc.Purge(ctx)
+			if err != nil {
+				log.Printf("Failed to purge chat messages: %v", err)
+			}
+			if n > 0 {
+				log.Printf("Purged %d chat messages", n)
+			}
+		}
+	}
+}
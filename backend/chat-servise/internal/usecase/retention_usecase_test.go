@@ -0,0 +1,80 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Ulyana-kru00/forum-project/chat/internal/entity"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type archiveFunc func(msgs []entity.Message) error
+
+func (f archiveFunc) Archive(msgs []entity.Message) error { return f(msgs) }
+
+func newRetentionUseCase(repo *MockMessageRepository, policy RetentionPolicy, now time.Time) RetentionUseCase {
+	uc := NewRetentionUseCase(repo, policy).(*retentionUseCase)
+	uc.now = func() time.Time { return now }
+	return uc
+}
+
+func TestRetentionUseCase_PurgeInBatches(t *testing.T) {
+	now := time.Now()
+	repo := new(MockMessageRepository)
+	repo.On("ExpiredMessages", now, 24*time.Hour, 2).
+		Return([]entity.Message{{ID: 1}, {ID: 2}}, nil).Once()
+	repo.On("ExpiredMessages", now, 24*time.Hour, 2).
+		Return([]entity.Message{{ID: 5}}, nil).Once()
+	repo.On("DeleteMessages", []int64{1, 2}).Return(int64(2), nil)
+	repo.On("DeleteMessages", []int64{5}).Return(int64(1), nil)
+
+	var archived []int
+	uc := newRetentionUseCase(repo, RetentionPolicy{
+		Default: 24 * time.Hour,
+		Batch:   2,
+		Archiver: archiveFunc(func(msgs []entity.Message) error {
+			for _, msg := range msgs {
+				archived = append(archived, msg.ID)
+			}
+			return nil
+		}),
+	}, now)
+
+	n, err := uc.Purge(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), n)
+	assert.Equal(t, []int{1, 2, 5}, archived)
+	// A short batch means nothing is left, so there is no third query.
+	repo.AssertNumberOfCalls(t, "ExpiredMessages", 2)
+}
+
+func TestRetentionUseCase_ArchiveFailureKeepsMessages(t *testing.T) {
+	now := time.Now()
+	repo := new(MockMessageRepository)
+	repo.On("ExpiredMessages", now, time.Duration(0), defaultPurgeBatch).
+		Return([]entity.Message{{ID: 1}}, nil)
+
+	uc := newRetentionUseCase(repo, RetentionPolicy{
+		Archiver: archiveFunc(func([]entity.Message) error { return errors.New("disk full") }),
+	}, now)
+
+	n, err := uc.Purge(context.Background())
+	assert.Error(t, err)
+	assert.Zero(t, n)
+	repo.AssertNumberOfCalls(t, "DeleteMessages", 0)
+}
+
+func TestRetentionUseCase_PurgeStopsWhenCancelled(t *testing.T) {
+	repo := new(MockMessageRepository)
+	uc := newRetentionUseCase(repo, RetentionPolicy{Default: time.Hour}, time.Now())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := uc.Purge(ctx)
+	assert.ErrorIs(t, err, context.Canceled)
+	repo.AssertNumberOfCalls(t, "ExpiredMessages", 0)
+}
@@ -0,0 +1,196 @@
+package usecase
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"backend.com/forum/apperrors"
+	"github.com/Ulyana-kru00/forum-project/chat/internal/entity"
+	"github.com/Ulyana-kru00/forum-project/chat/internal/repository"
+	"github.com/Ulyana-kru00/forum-project/chat/pkg/auth"
+)
+
+const maxRoomNameLength = 100
+
+var (
+	errInvalidRoomName  = apperrors.New(apperrors.ErrInvalidArgument, "room name must be 1 to 100 characters")
+	errNotRoomOwner     = apperrors.New(apperrors.ErrPermissionDenied, "only the room owner can do this")
+	errNotRoomMember    = apperrors.New(apperrors.ErrPermissionDenied, "join the room first")
+	errOwnerCannotLeave = apperrors.New(apperrors.ErrInvalidArgument, "the owner cannot leave the room, delete it instead")
+	errInvalidRetention = apperrors.New(apperrors.ErrInvalidArgument, "retention_seconds must be -1 for the default, 0 to keep messages forever or a number of seconds")
+)
+
+type RoomUseCase interface {
+	CreateRoom(userID int64, name string, private bool) (*entity.Room, error)
+	ListRooms(userID int64) ([]entity.Room, error)
+	GetRoom(userID, roomID int64) (*entity.Room, error)
+	UpdateRoom(caller *auth.Identity, roomID int64, update entity.RoomUpdate) (*entity.Room, error)
+	DeleteRoom(caller *auth.Identity, roomID int64) error
+
+	IsMember(userID, roomID int64) (bool, error)
+	JoinRoom(userID, roomID int64) error
+	LeaveRoom(userID, roomID int64) error
+	ListMembers(userID, roomID int64) ([]entity.RoomMember, error)
+	AddMember(caller *auth.Identity, roomID, memberID int64) error
+	RemoveMember(caller *auth.Identity, roomID, memberID int64) error
+}
+
+type roomUseCase struct {
+	repo repository.RoomRepository
+}
+
+func NewRoomUseCase(repo repository.RoomRepository) RoomUseCase {
+	return &roomUseCase{repo: repo}
+}
+
+func normalizeRoomName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || utf8.RuneCountInString(name) > maxRoomNameLength {
+		return "", errInvalidRoomName
+	}
+	return name, nil
+}
+
+// CreateRoom creates a room owned by userID.
+func (uc *roomUseCase) CreateRoom(userID int64, name string, private bool) (*entity.Room, error) {
+	name, err := normalizeRoomName(name)
+	if err != nil {
+		return nil, err
+	}
+
+	room := &entity.Room{Name: name, Private: private, OwnerID: userID}
+	if err := uc.repo.CreateRoom(room); err != nil {
+		return nil, err
+	}
+	return room, nil
+}
+
+func (uc *roomUseCase) ListRooms(userID int64) ([]entity.Room, error) {
+	return uc.repo.ListRooms(userID)
+}
+
+// GetRoom returns a room userID may see. Private rooms of others are
+// reported as missing rather than forbidden, so their names do not leak.
+func (uc *roomUseCase) GetRoom(userID, roomID int64) (*entity.Room, error) {
+	room, err := uc.repo.GetRoom(roomID)
+	if err != nil {
+		return nil, err
+	}
+	if room.Private {
+		member, err := uc.repo.IsMember(roomID, userID)
+		if err != nil {
+			return nil, err
+		}
+		if !member {
+			return nil, repository.ErrRoomNotFound
+		}
+	}
+	return room, nil
+}
+
+// ownedRoom returns the room if caller owns it. Rooms nobody owns, such as
+// the general room, are managed by administrators.
+func (uc *roomUseCase) ownedRoom(caller *auth.Identity, roomID int64) (*entity.Room, error) {
+	room, err := uc.GetRoom(caller.UserID, roomID)
+	if err != nil {
+		return nil, err
+	}
+	if room.OwnerID == caller.UserID || room.OwnerID == 0 && caller.IsAdmin() {
+		return room, nil
+	}
+	return nil, errNotRoomOwner
+}
+
+func (uc *roomUseCase) UpdateRoom(caller *auth.Identity, roomID int64, update entity.RoomUpdate) (*entity.Room, error) {
+	room, err := uc.ownedRoom(caller, roomID)
+	if err != nil {
+		return nil, err
+	}
+	if update.Name != nil {
+		if room.Name, err = normalizeRoomName(*update.Name); err != nil {
+			return nil, err
+		}
+	}
+	if update.Private != nil {
+		room.Private = *update.Private
+	}
+	if update.RetentionSeconds != nil {
+		switch retention := *update.RetentionSeconds; {
+		case retention == -1:
+			room.RetentionSeconds = nil
+		case retention >= 0:
+			room.RetentionSeconds = &retention
+		default:
+			return nil, errInvalidRetention
+		}
+	}
+
+	if err := uc.repo.UpdateRoom(room); err != nil {
+		return nil, err
+	}
+	return room, nil
+}
+
+func (uc *roomUseCase) DeleteRoom(caller *auth.Identity, roomID int64) error {
+	if _, err := uc.ownedRoom(caller, roomID); err != nil {
+		return err
+	}
+	return uc.repo.DeleteRoom(roomID)
+}
+
+func (uc *roomUseCase) IsMember(userID, roomID int64) (bool, error) {
+	return uc.repo.IsMember(roomID, userID)
+}
+
+// JoinRoom makes userID a member of a public room. Private rooms can only be
+// joined by invitation: to anyone else they do not exist.
+func (uc *roomUseCase) JoinRoom(userID, roomID int64) error {
+	room, err := uc.GetRoom(userID, roomID)
+	if err != nil {
+		return err
+	}
+	if room.Private {
+		// GetRoom only returns private rooms to their members.
+		return nil
+	}
+	return uc.repo.AddMember(roomID, userID)
+}
+
+func (uc *roomUseCase) LeaveRoom(userID, roomID int64) error {
+	room, err := uc.GetRoom(userID, roomID)
+	if err != nil {
+		return err
+	}
+	if room.OwnerID == userID {
+		return errOwnerCannotLeave
+	}
+	return uc.repo.RemoveMember(roomID, userID)
+}
+
+func (uc *roomUseCase) ListMembers(userID, roomID int64) ([]entity.RoomMember, error) {
+	if _, err := uc.GetRoom(userID, roomID); err != nil {
+		return nil, err
+	}
+	return uc.repo.ListMembers(roomID)
+}
+
+// AddMember lets the owner invite memberID.
+func (uc *roomUseCase) AddMember(caller *auth.Identity, roomID, memberID int64) error {
+	if _, err := uc.ownedRoom(caller, roomID); err != nil {
+		return err
+	}
+	return uc.repo.AddMember(roomID, memberID)
+}
+
+// RemoveMember lets the owner remove memberID, who must join or be invited
+// again to come back.
+func (uc *roomUseCase) RemoveMember(caller *auth.Identity, roomID, memberID int64) error {
+	room, err := uc.ownedRoom(caller, roomID)
+	if err != nil {
+		return err
+	}
+	if memberID == room.OwnerID {
+		return errOwnerCannotLeave
+	}
+	return uc.repo.RemoveMember(roomID, memberID)
+}
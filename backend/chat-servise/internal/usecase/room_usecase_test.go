@@ -0,0 +1,204 @@
+package usecase
+
+import (
+	"testing"
+
+	"backend.com/forum/apperrors"
+	"github.com/Ulyana-kru00/forum-project/chat/internal/entity"
+	"github.com/Ulyana-kru00/forum-project/chat/internal/repository"
+	"github.com/Ulyana-kru00/forum-project/chat/pkg/auth"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockRoomRepository struct {
+	mock.Mock
+}
+
+func (m *MockRoomRepository) CreateRoom(room *entity.Room) error {
+	args := m.Called(room)
+	return args.Error(0)
+}
+
+func (m *MockRoomRepository) GetRoom(id int64) (*entity.Room, error) {
+	args := m.Called(id)
+	room, _ := args.Get(0).(*entity.Room)
+	return room, args.Error(1)
+}
+
+func (m *MockRoomRepository) ListRooms(userID int64) ([]entity.Room, error) {
+	args := m.Called(userID)
+	return args.Get(0).([]entity.Room), args.Error(1)
+}
+
+func (m *MockRoomRepository) UpdateRoom(room *entity.Room) error {
+	args := m.Called(room)
+	return args.Error(0)
+}
+
+func (m *MockRoomRepository) DeleteRoom(id int64) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockRoomRepository) IsMember(roomID, userID int64) (bool, error) {
+	args := m.Called(roomID, userID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockRoomRepository) AddMember(roomID, userID int64) error {
+	args := m.Called(roomID, userID)
+	return args.Error(0)
+}
+
+func (m *MockRoomRepository) RemoveMember(roomID, userID int64) error {
+	args := m.Called(roomID, userID)
+	return args.Error(0)
+}
+
+func (m *MockRoomRepository) ListMembers(roomID int64) ([]entity.RoomMember, error) {
+	args := m.Called(roomID)
+	return args.Get(0).([]entity.RoomMember), args.Error(1)
+}
+
+// user returns the identity of a user with the given role.
+func user(id int64, role string) *auth.Identity {
+	return &auth.Identity{UserID: id, Role: role}
+}
+
+// Room 1 is public and owned by user 1, room 2 is private and owned by
+// user 1, who is its only member. Room 3 is public and owned by nobody.
+func newRoomRepository() *MockRoomRepository {
+	repo := new(MockRoomRepository)
+	repo.On("GetRoom", int64(3)).Return(&entity.Room{ID: 3, Name: "lobby"}, nil).Maybe()
+	repo.On("GetRoom", int64(1)).Return(&entity.Room{ID: 1, Name: "general", OwnerID: 1}, nil).Maybe()
+	repo.On("GetRoom", int64(2)).Return(&entity.Room{ID: 2, Name: "secret", Private: true, OwnerID: 1}, nil).Maybe()
+	repo.On("GetRoom", mock.Anything).Return(nil, repository.ErrRoomNotFound).Maybe()
+	repo.On("IsMember", int64(2), int64(1)).Return(true, nil).Maybe()
+	repo.On("IsMember", int64(2), mock.Anything).Return(false, nil).Maybe()
+	return repo
+}
+
+func TestRoomUseCase_CreateRoom(t *testing.T) {
+	repo := new(MockRoomRepository)
+	repo.On("CreateRoom", &entity.Room{Name: "go", Private: true, OwnerID: 7}).Return(nil)
+	uc := NewRoomUseCase(repo)
+
+	room, err := uc.CreateRoom(7, "  go ", true)
+	require.NoError(t, err)
+	assert.Equal(t, int64(7), room.OwnerID)
+	assert.Equal(t, "go", room.Name)
+
+	_, err = uc.CreateRoom(7, "   ", false)
+	assert.ErrorIs(t, err, apperrors.ErrInvalidArgument)
+	repo.AssertNumberOfCalls(t, "CreateRoom", 1)
+}
+
+func TestRoomUseCase_GetRoom(t *testing.T) {
+	uc := NewRoomUseCase(newRoomRepository())
+
+	room, err := uc.GetRoom(5, 1)
+	require.NoError(t, err)
+	assert.Equal(t, "general", room.Name)
+
+	room, err = uc.GetRoom(1, 2)
+	require.NoError(t, err)
+	assert.Equal(t, "secret", room.Name)
+
+	// Private rooms of others look like they do not exist.
+	_, err = uc.GetRoom(5, 2)
+	assert.ErrorIs(t, err, repository.ErrRoomNotFound)
+}
+
+func TestRoomUseCase_JoinRoom(t *testing.T) {
+	repo := newRoomRepository()
+	repo.On("AddMember", int64(1), int64(5)).Return(nil)
+	uc := NewRoomUseCase(repo)
+
+	assert.NoError(t, uc.JoinRoom(5, 1))
+	assert.ErrorIs(t, uc.JoinRoom(5, 2), apperrors.ErrNotFound)
+	// Members of a private room are already in.
+	assert.NoError(t, uc.JoinRoom(1, 2))
+	repo.AssertNumberOfCalls(t, "AddMember", 1)
+}
+
+func TestRoomUseCase_OwnerOnly(t *testing.T) {
+	repo := newRoomRepository()
+	repo.On("AddMember", int64(2), int64(5)).Return(nil)
+	repo.On("RemoveMember", int64(2), int64(5)).Return(nil)
+	repo.On("UpdateRoom", &entity.Room{ID: 1, Name: "lobby", OwnerID: 1}).Return(nil)
+	repo.On("DeleteRoom", int64(1)).Return(nil)
+	uc := NewRoomUseCase(repo)
+
+	assert.NoError(t, uc.AddMember(user(1, "user"), 2, 5))
+	assert.NoError(t, uc.RemoveMember(user(1, "user"), 2, 5))
+	assert.ErrorIs(t, uc.RemoveMember(user(1, "user"), 2, 1), apperrors.ErrInvalidArgument)
+
+	name := "lobby"
+	room, err := uc.UpdateRoom(user(1, "user"), 1, entity.RoomUpdate{Name: &name})
+	require.NoError(t, err)
+	assert.Equal(t, "lobby", room.Name)
+	assert.NoError(t, uc.DeleteRoom(user(1, "user"), 1))
+
+	assert.ErrorIs(t, uc.AddMember(user(5, "user"), 1, 6), apperrors.ErrPermissionDenied)
+	_, err = uc.UpdateRoom(user(5, "user"), 1, entity.RoomUpdate{Name: &name})
+	assert.ErrorIs(t, err, apperrors.ErrPermissionDenied)
+	assert.ErrorIs(t, uc.DeleteRoom(user(5, "user"), 1), apperrors.ErrPermissionDenied)
+	// Administrators manage only the rooms nobody owns.
+	assert.ErrorIs(t, uc.DeleteRoom(user(5, auth.RoleAdmin), 1), apperrors.ErrPermissionDenied)
+}
+
+func TestRoomUseCase_OwnerlessRoom(t *testing.T) {
+	week := int64(7 * 24 * 3600)
+	repo := newRoomRepository()
+	repo.On("UpdateRoom", &entity.Room{ID: 3, Name: "lobby", RetentionSeconds: &week}).Return(nil)
+	repo.On("AddMember", int64(3), int64(6)).Return(nil)
+	repo.On("RemoveMember", int64(3), int64(6)).Return(nil)
+	uc := NewRoomUseCase(repo)
+
+	admin := user(5, auth.RoleAdmin)
+	room, err := uc.UpdateRoom(admin, 3, entity.RoomUpdate{RetentionSeconds: &week})
+	require.NoError(t, err)
+	assert.Equal(t, week, *room.RetentionSeconds)
+	assert.NoError(t, uc.AddMember(admin, 3, 6))
+	assert.NoError(t, uc.RemoveMember(admin, 3, 6))
+
+	_, err = uc.UpdateRoom(user(6, "moderator"), 3, entity.RoomUpdate{RetentionSeconds: &week})
+	assert.ErrorIs(t, err, apperrors.ErrPermissionDenied)
+	assert.ErrorIs(t, uc.DeleteRoom(user(6, "user"), 3), apperrors.ErrPermissionDenied)
+}
+
+func TestRoomUseCase_LeaveRoom(t *testing.T) {
+	repo := newRoomRepository()
+	repo.On("RemoveMember", int64(1), int64(5)).Return(nil)
+	uc := NewRoomUseCase(repo)
+
+	assert.NoError(t, uc.LeaveRoom(5, 1))
+	assert.ErrorIs(t, uc.LeaveRoom(1, 1), apperrors.ErrInvalidArgument)
+}
+
+func TestRoomUseCase_UpdateRetention(t *testing.T) {
+	week := int64(7 * 24 * 3600)
+	repo := new(MockRoomRepository)
+	repo.On("GetRoom", int64(1)).Return(&entity.Room{ID: 1, Name: "general", OwnerID: 1, RetentionSeconds: &week}, nil)
+	repo.On("UpdateRoom", mock.Anything).Return(nil)
+	uc := NewRoomUseCase(repo)
+
+	forever := int64(0)
+	room, err := uc.UpdateRoom(user(1, "user"), 1, entity.RoomUpdate{RetentionSeconds: &forever})
+	require.NoError(t, err)
+	require.NotNil(t, room.RetentionSeconds)
+	assert.Zero(t, *room.RetentionSeconds)
+
+	reset := int64(-1)
+	room, err = uc.UpdateRoom(user(1, "user"), 1, entity.RoomUpdate{RetentionSeconds: &reset})
+	require.NoError(t, err)
+	assert.Nil(t, room.RetentionSeconds)
+
+	invalid := int64(-3600)
+	_, err = uc.UpdateRoom(user(1, "user"), 1, entity.RoomUpdate{RetentionSeconds: &invalid})
+	assert.ErrorIs(t, err, errInvalidRetention)
+	repo.AssertNumberOfCalls(t, "UpdateRoom", 2)
+}
@@ -2,6 +2,7 @@
 package mocks
 
 import (
+	"context"
 	"database/sql"
 	"encoding/json"
 	"errors"
@@ -13,10 +14,12 @@ import (
 	"github.com/Ulyana-kru00/forum-project/chat/internal/handler"
 	"github.com/Ulyana-kru00/forum-project/chat/internal/repository"
 	"github.com/Ulyana-kru00/forum-project/chat/internal/usecase"
+	"github.com/Ulyana-kru00/forum-project/chat/pkg/auth"
 	myWeb "github.com/Ulyana-kru00/forum-project/chat/pkg/websocket"
 
 	"os"
 	"strings"
+	"sync/atomic"
 	"testing"
 
 	"github.com/DATA-DOG/go-sqlmock"
@@ -67,8 +70,12 @@ func (suite *MessageIntegrationTestSuite) SetupSuite() {
 	_, err = suite.db.Exec(`
         CREATE TABLE IF NOT EXISTS chat_messages (
             id SERIAL PRIMARY KEY,
+            room_id INTEGER NOT NULL,
+            user_id INTEGER NOT NULL,
             username VARCHAR(255) NOT NULL,
-            content TEXT NOT NULL
+            content TEXT NOT NULL,
+            timestamp TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+            hidden_at TIMESTAMP
         )
     `)
 	if err != nil {
@@ -76,7 +83,7 @@ func (suite *MessageIntegrationTestSuite) SetupSuite() {
 	}
 
 	suite.repo = repository.NewMessageRepository(suite.db)
-	suite.messageUC = usecase.NewMessageUseCase(suite.repo)
+	suite.messageUC = usecase.NewMessageUseCase(suite.repo, openRooms{})
 }
 
 func (suite *MessageIntegrationTestSuite) TearDownSuite() {
@@ -114,6 +121,8 @@ func (suite *MessageIntegrationTestSuite) TestSaveMessage() {
 		{
 			name: "successful save",
 			message: entity.Message{
+				RoomID:   1,
+				UserID:   1,
 				Username: "user1",
 				Message:  "Hello",
 			},
@@ -122,6 +131,8 @@ func (suite *MessageIntegrationTestSuite) TestSaveMessage() {
 		{
 			name: "empty message",
 			message: entity.Message{
+				RoomID:   1,
+				UserID:   1,
 				Username: "user1",
 				Message:  "",
 			},
@@ -135,14 +146,14 @@ func (suite *MessageIntegrationTestSuite) TestSaveMessage() {
 			_, err := suite.db.Exec("DELETE FROM chat_messages")
 			assert.NoError(suite.T(), err)
 
-			err = suite.messageUC.SaveMessage(tt.message)
+			err = suite.messageUC.SaveMessage(&tt.message)
 			if tt.expectError {
 				assert.Error(suite.T(), err)
 			} else {
 				assert.NoError(suite.T(), err)
 			}
 
-			messages, err := suite.repo.GetMessages()
+			messages, err := suite.repo.GetMessages(entity.MessageFilter{RoomID: 1, Limit: 10})
 			assert.NoError(suite.T(), err)
 			assert.Len(suite.T(), messages, 1, "Должно быть ровно одно сообщение в базе")
 			assert.Equal(suite.T(), tt.message.Username, messages[0].Username)
@@ -153,41 +164,56 @@ func (suite *MessageIntegrationTestSuite) TestSaveMessage() {
 func (suite *MessageIntegrationTestSuite) TestGetMessages() {
 
 	messagesToSave := []entity.Message{
-		{Username: "user1", Message: "Message 1"},
-		{Username: "user2", Message: "Message 2"},
+		{RoomID: 1, UserID: 1, Username: "user1", Message: "Message 1"},
+		{RoomID: 1, UserID: 2, Username: "user2", Message: "Message 2"},
+		{RoomID: 2, UserID: 2, Username: "user2", Message: "Elsewhere"},
 	}
 
 	for _, msg := range messagesToSave {
-		err := suite.repo.SaveMessage(msg)
+		err := suite.repo.SaveMessage(&msg)
 		assert.NoError(suite.T(), err)
 	}
 
-	messages, err := suite.messageUC.GetMessages()
+	page, err := suite.messageUC.GetMessages(1, entity.MessageFilter{RoomID: 1})
 	assert.NoError(suite.T(), err)
-	assert.Len(suite.T(), messages, len(messagesToSave))
+	assert.Len(suite.T(), page.Messages, 2, "Сообщения других комнат не попадают в историю")
 
-	for i, msg := range messages {
+	for i, msg := range page.Messages {
 		assert.Equal(suite.T(), messagesToSave[i].Username, msg.Username)
 		assert.Equal(suite.T(), messagesToSave[i].Message, msg.Message)
 		assert.NotZero(suite.T(), msg.ID)
+		assert.NotZero(suite.T(), msg.CreatedAt)
 	}
+
+	page, err = suite.messageUC.GetMessages(1, entity.MessageFilter{RoomID: 1, Limit: 1})
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "Message 2", page.Messages[0].Message)
+	assert.Equal(suite.T(), page.Messages[0].ID, page.NextBefore)
+
+	page, err = suite.messageUC.GetMessages(1, entity.MessageFilter{RoomID: 1, Before: page.NextBefore})
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "Message 1", page.Messages[0].Message)
+	assert.Zero(suite.T(), page.NextBefore)
 }
 
 func (suite *MessageIntegrationTestSuite) TestMessageFlow() {
 
 	testMsg := entity.Message{
+		RoomID:   1,
+		UserID:   1,
 		Username: "testuser",
 		Message:  "Integration test message",
 	}
 
-	err := suite.messageUC.SaveMessage(testMsg)
+	err := suite.messageUC.SaveMessage(&testMsg)
 	assert.NoError(suite.T(), err)
 
-	messages, err := suite.messageUC.GetMessages()
+	page, err := suite.messageUC.GetMessages(1, entity.MessageFilter{RoomID: 1})
 	assert.NoError(suite.T(), err)
-	assert.Len(suite.T(), messages, 1)
-	assert.Equal(suite.T(), testMsg.Username, messages[0].Username)
-	assert.Equal(suite.T(), testMsg.Message, messages[0].Message)
+	assert.Len(suite.T(), page.Messages, 1)
+	assert.Equal(suite.T(), testMsg.ID, page.Messages[0].ID)
+	assert.Equal(suite.T(), testMsg.Username, page.Messages[0].Username)
+	assert.Equal(suite.T(), testMsg.Message, page.Messages[0].Message)
 }
 
 func TestGetMessages_EdgeCases(t *testing.T) {
@@ -198,10 +224,11 @@ func TestGetMessages_EdgeCases(t *testing.T) {
 	repo := repository.NewMessageRepository(db)
 
 	t.Run("empty result", func(t *testing.T) {
-		mock.ExpectQuery("SELECT id, username, content FROM chat_messages").
-			WillReturnRows(sqlmock.NewRows([]string{"id", "username", "content"}))
+		mock.ExpectQuery("SELECT id, room_id, user_id, username, content, timestamp FROM chat_messages").
+			WithArgs(int64(1), 10).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "room_id", "user_id", "username", "content", "timestamp"}))
 
-		messages, err := repo.GetMessages()
+		messages, err := repo.GetMessages(entity.MessageFilter{RoomID: 1, Limit: 10})
 		require.NoError(t, err)
 		require.Empty(t, messages)
 	})
@@ -209,10 +236,11 @@ func TestGetMessages_EdgeCases(t *testing.T) {
 	t.Run("scan error", func(t *testing.T) {
 		rows := sqlmock.NewRows([]string{"id", "username"}).
 			AddRow(1, "user1")
-		mock.ExpectQuery("SELECT id, username, content FROM chat_messages").
+		mock.ExpectQuery("SELECT id, room_id, user_id, username, content, timestamp FROM chat_messages").
+			WithArgs(int64(1), 10).
 			WillReturnRows(rows)
 
-		_, err := repo.GetMessages()
+		_, err := repo.GetMessages(entity.MessageFilter{RoomID: 1, Limit: 10})
 		require.Error(t, err)
 	})
 }
@@ -223,47 +251,58 @@ func TestMessageHandler(t *testing.T) {
 		saveFunc: func(msg entity.Message) error {
 			return nil
 		},
-		getMessagesFunc: func() ([]entity.Message, error) {
-			return []entity.Message{
+		getMessagesFunc: func(userID int64, filter entity.MessageFilter) (*entity.MessagePage, error) {
+			return &entity.MessagePage{Messages: []entity.Message{
 				{ID: 1, Username: "user1", Message: "Hello"},
 				{ID: 2, Username: "user2", Message: "Hi there"},
-			}, nil
+			}}, nil
 		},
 	}
 
+	hub := myWeb.NewHub(myWeb.Config{})
+	ctx, cancel := context.WithCancel(context.Background())
+	go hub.Run(ctx)
+	defer cancel()
+
 	h := &handler.MessageHandler{
-		Uc: mockUC,
+		Uc:       mockUC,
+		Rooms:    openRooms{},
+		Auth:     stubAuth{},
+		Upgrader: myWeb.NewUpgrader([]string{"*"}),
+		Hub:      hub,
 	}
 
 	t.Run("GetMessages success", func(t *testing.T) {
 		router := gin.Default()
-		router.GET("/messages", h.GetMessages)
+		router.GET("/rooms/:id/messages", handler.RequireAuth(stubAuth{}), h.GetMessages)
 
-		req, _ := http.NewRequest("GET", "/messages", nil)
+		req, _ := http.NewRequest("GET", "/rooms/1/messages", nil)
+		req.Header.Set("Authorization", "Bearer token")
 		w := httptest.NewRecorder()
 		router.ServeHTTP(w, req)
 
 		assert.Equal(t, http.StatusOK, w.Code)
 
-		var response []entity.Message
+		var response entity.MessagePage
 		err := json.Unmarshal(w.Body.Bytes(), &response)
 		require.NoError(t, err)
-		assert.Len(t, response, 2)
+		assert.Len(t, response.Messages, 2)
 	})
 
 	t.Run("GetMessages database error", func(t *testing.T) {
 		errorUC := &mockMessageUseCase{
-			getMessagesFunc: func() ([]entity.Message, error) {
+			getMessagesFunc: func(userID int64, filter entity.MessageFilter) (*entity.MessagePage, error) {
 				return nil, errors.New("database error")
 			},
 		}
 
-		errorHandler := handler.NewMessageHandler(errorUC)
+		errorHandler := handler.NewMessageHandler(errorUC, nil, nil, nil, nil)
 
 		router := gin.Default()
-		router.GET("/messages", errorHandler.GetMessages)
+		router.GET("/rooms/:id/messages", handler.RequireAuth(stubAuth{}), errorHandler.GetMessages)
 
-		req, _ := http.NewRequest("GET", "/messages", nil)
+		req, _ := http.NewRequest("GET", "/rooms/1/messages", nil)
+		req.Header.Set("Authorization", "Bearer token")
 		w := httptest.NewRecorder()
 		router.ServeHTTP(w, req)
 
@@ -272,8 +311,6 @@ func TestMessageHandler(t *testing.T) {
 
 	t.Run("HandleConnections websocket upgrade", func(t *testing.T) {
 
-		myWeb.Clients = make(map[*websocket.Conn]bool)
-
 		s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 
 			c, _ := gin.CreateTestContext(w)
@@ -283,23 +320,24 @@ func TestMessageHandler(t *testing.T) {
 		defer s.Close()
 
 		u := "ws" + strings.TrimPrefix(s.URL, "http")
-		ws, _, err := websocket.DefaultDialer.Dial(u+"/ws", nil)
+		ws, _, err := websocket.DefaultDialer.Dial(u+"/ws", http.Header{"Authorization": {"Bearer token"}})
 		require.NoError(t, err)
 		defer ws.Close()
 
-		assert.Equal(t, 1, len(myWeb.Clients))
+		var echoed entity.Frame
+		require.NoError(t, ws.WriteJSON(entity.Frame{Type: entity.FrameJoin, RoomID: 1}))
+		require.NoError(t, ws.ReadJSON(&echoed))
+		assert.Equal(t, entity.FrameJoined, echoed.Type)
 
-		testMsg := entity.Message{Username: "test", Message: "hello"}
-		err = ws.WriteJSON(testMsg)
+		err = ws.WriteJSON(entity.Frame{Type: entity.FrameMessage, RoomID: 1, Username: "someone", Message: "hello"})
 		require.NoError(t, err)
 
-		assert.Equal(t, 1, mockUC.saveCount)
+		require.NoError(t, ws.ReadJSON(&echoed))
+		assert.Equal(t, "test", echoed.Username)
+		assert.Equal(t, int32(1), mockUC.saveCount.Load())
 	})
 
-	t.Run("HandleMessages broadcast", func(t *testing.T) {
-
-		myWeb.Clients = make(map[*websocket.Conn]bool)
-		myWeb.Broadcast = make(chan entity.Message)
+	t.Run("Hub broadcast", func(t *testing.T) {
 
 		s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			c, _ := gin.CreateTestContext(w)
@@ -309,20 +347,23 @@ func TestMessageHandler(t *testing.T) {
 		defer s.Close()
 
 		u := "ws" + strings.TrimPrefix(s.URL, "http")
-		ws1, _, err := websocket.DefaultDialer.Dial(u+"/ws", nil)
+		// A client is in the room once the hub confirms the join.
+		var msg1, msg2 entity.Frame
+		ws1, _, err := websocket.DefaultDialer.Dial(u+"/ws", http.Header{"Authorization": {"Bearer token"}})
 		require.NoError(t, err)
 		defer ws1.Close()
+		require.NoError(t, ws1.WriteJSON(entity.Frame{Type: entity.FrameJoin, RoomID: 1}))
+		require.NoError(t, ws1.ReadJSON(&msg1))
 
-		ws2, _, err := websocket.DefaultDialer.Dial(u+"/ws", nil)
+		ws2, _, err := websocket.DefaultDialer.Dial(u+"/ws", http.Header{"Authorization": {"Bearer token"}})
 		require.NoError(t, err)
 		defer ws2.Close()
+		require.NoError(t, ws2.WriteJSON(entity.Frame{Type: entity.FrameJoin, RoomID: 1}))
+		require.NoError(t, ws2.ReadJSON(&msg2))
 
-		go h.HandleMessages()
-
-		broadcastMsg := entity.Message{Username: "system", Message: "broadcast"}
-		myWeb.Broadcast <- broadcastMsg
+		broadcastMsg := entity.Message{RoomID: 1, Username: "system", Message: "broadcast"}
+		hub.Broadcast(broadcastMsg)
 
-		var msg1, msg2 entity.Message
 		err = ws1.ReadJSON(&msg1)
 		require.NoError(t, err)
 		assert.Equal(t, broadcastMsg.Message, msg1.Message)
@@ -333,24 +374,47 @@ func TestMessageHandler(t *testing.T) {
 	})
 }
 
+type stubAuth struct{}
+
+func (stubAuth) Authenticate(ctx context.Context, token string) (*auth.Identity, error) {
+	return &auth.Identity{UserID: 1, Username: "test"}, nil
+}
+
+// openRooms lets everyone into every room.
+type openRooms struct {
+	usecase.RoomUseCase
+}
+
+func (openRooms) GetRoom(userID, roomID int64) (*entity.Room, error) {
+	return &entity.Room{ID: roomID, Name: "general"}, nil
+}
+
+func (openRooms) IsMember(userID, roomID int64) (bool, error) {
+	return true, nil
+}
+
+func (openRooms) JoinRoom(userID, roomID int64) error {
+	return nil
+}
+
 type mockMessageUseCase struct {
 	usecase.MessageUseCase
 	saveFunc        func(entity.Message) error
-	getMessagesFunc func() ([]entity.Message, error)
-	saveCount       int
+	getMessagesFunc func(userID int64, filter entity.MessageFilter) (*entity.MessagePage, error)
+	saveCount       atomic.Int32
 }
 
-func (m *mockMessageUseCase) SaveMessage(msg entity.Message) error {
-	m.saveCount++
+func (m *mockMessageUseCase) SaveMessage(msg *entity.Message) error {
+	m.saveCount.Add(1)
 	if m.saveFunc != nil {
-		return m.saveFunc(msg)
+		return m.saveFunc(*msg)
 	}
 	return nil
 }
 
-func (m *mockMessageUseCase) GetMessages() ([]entity.Message, error) {
+func (m *mockMessageUseCase) GetMessages(userID int64, filter entity.MessageFilter) (*entity.MessagePage, error) {
 	if m.getMessagesFunc != nil {
-		return m.getMessagesFunc()
+		return m.getMessagesFunc(userID, filter)
 	}
 	return nil, nil
 }
@@ -0,0 +1,64 @@
+// Package archive writes chat messages to gzip-compressed NDJSON files.
+package archive
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/Ulyana-kru00/forum-project/chat/internal/entity"
+)
+
+// Dir archives messages to files in a directory, one file per batch, named
+// after the time of writing and the ids of the first and last message.
+type Dir struct {
+	path string
+	now  func() time.Time
+}
+
+// New returns an archive in dir, creating the directory if needed.
+func New(dir string) (*Dir, error) {
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, err
+	}
+	return &Dir{path: dir, now: time.Now}, nil
+}
+
+// Archive writes msgs, one JSON object per line. The file only appears
+// under its final name once it is complete and synced to disk.
+func (d *Dir) Archive(msgs []entity.Message) error {
+	if len(msgs) == 0 {
+		return nil
+	}
+
+	tmp, err := os.CreateTemp(d.path, ".chat-*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	zw := gzip.NewWriter(tmp)
+	enc := json.NewEncoder(zw)
+	for _, msg := range msgs {
+		if err := enc.Encode(msg); err != nil {
+			return err
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	name := fmt.Sprintf("chat-%s-%d-%d.ndjson.gz",
+		d.now().UTC().Format("20060102T150405Z"), msgs[0].ID, msgs[len(msgs)-1].ID)
+	return os.Rename(tmp.Name(), filepath.Join(d.path, name))
+}
@@ -0,0 +1,52 @@
+package archive
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Ulyana-kru00/forum-project/chat/internal/entity"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDir_Archive(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "chat")
+	archive, err := New(dir)
+	require.NoError(t, err)
+	archive.now = func() time.Time { return time.Date(2026, 10, 18, 12, 30, 0, 0, time.UTC) }
+
+	created := time.Date(2026, 9, 1, 8, 0, 0, 0, time.UTC)
+	msgs := []entity.Message{
+		{ID: 3, RoomID: 1, UserID: 7, Username: "anna", Message: "привет", CreatedAt: created},
+		{ID: 9, RoomID: 2, UserID: 8, Username: "bob", Message: "hi", CreatedAt: created},
+	}
+	require.NoError(t, archive.Archive(msgs))
+	require.NoError(t, archive.Archive(nil))
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, entries, 1, "only the finished file is left")
+	assert.Equal(t, "chat-20261018T123000Z-3-9.ndjson.gz", entries[0].Name())
+
+	f, err := os.Open(filepath.Join(dir, entries[0].Name()))
+	require.NoError(t, err)
+	defer f.Close()
+	zr, err := gzip.NewReader(f)
+	require.NoError(t, err)
+
+	var got []entity.Message
+	scanner := bufio.NewScanner(zr)
+	for scanner.Scan() {
+		var msg entity.Message
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &msg))
+		got = append(got, msg)
+	}
+	require.NoError(t, scanner.Err())
+	assert.Equal(t, msgs, got)
+}
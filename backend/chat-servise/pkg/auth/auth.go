@@ -0,0 +1,63 @@
+// Package auth identifies chat users by the access tokens auth-service
+// issues.
+package auth
+
+import (
+	"context"
+	"fmt"
+
+	"backend.com/forum/apperrors"
+	pb "backend.com/forum/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var ErrInvalidToken = apperrors.New(apperrors.ErrUnauthenticated, "invalid token")
+
+// RoleAdmin is the auth-service role of administrators.
+const RoleAdmin = "admin"
+
+// Identity is the user a connection belongs to.
+type Identity struct {
+	UserID   int64
+	Username string
+	Role     string
+}
+
+// IsAdmin reports whether the user is an administrator.
+func (i *Identity) IsAdmin() bool {
+	return i.Role == RoleAdmin
+}
+
+type Authenticator interface {
+	Authenticate(ctx context.Context, token string) (*Identity, error)
+}
+
+type authenticator struct {
+	client pb.AuthServiceClient
+}
+
+// New returns an Authenticator that checks tokens with auth-service's
+// ValidateToken, so revoked sessions are refused as well as bad tokens.
+func New(client pb.AuthServiceClient) Authenticator {
+	return &authenticator{client: client}
+}
+
+func (a *authenticator) Authenticate(ctx context.Context, token string) (*Identity, error) {
+	if token == "" {
+		return nil, ErrInvalidToken
+	}
+
+	resp, err := a.client.ValidateToken(ctx, &pb.ValidateTokenRequest{Token: token})
+	if err != nil {
+		if status.Code(err) == codes.Unauthenticated {
+			return nil, ErrInvalidToken
+		}
+		return nil, fmt.Errorf("validate token: %w", err)
+	}
+	if !resp.Valid || resp.UserId <= 0 || resp.Username == "" {
+		return nil, ErrInvalidToken
+	}
+
+	return &Identity{UserID: resp.UserId, Username: resp.Username, Role: resp.Role}, nil
+}
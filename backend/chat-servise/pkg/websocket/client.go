@@ -0,0 +1,98 @@
+package websocket
+
+import (
+	"errors"
+	"time"
+
+	"github.com/Ulyana-kru00/forum-project/chat/internal/entity"
+
+	"github.com/gorilla/websocket"
+)
+
+// ErrHubStopped is returned for connections that arrive after the hub has
+// been shut down.
+var ErrHubStopped = errors.New("chat hub stopped")
+
+// Client is one WebSocket connection registered with a Hub. Only its writer
+// goroutine writes to the connection.
+type Client struct {
+	hub    *Hub
+	conn   *websocket.Conn
+	userID int64
+	send   chan entity.Frame
+}
+
+// Serve registers the connection of userID with the hub and reads frames
+// from it, passing each to handle, until the peer goes away, stops answering
+// pings or falls too far behind. It closes conn before returning.
+func (h *Hub) Serve(conn *websocket.Conn, userID int64, handle func(*Client, entity.Frame)) error {
+	c := &Client{
+		hub:    h,
+		conn:   conn,
+		userID: userID,
+		send:   make(chan entity.Frame, h.cfg.SendQueue),
+	}
+	if !h.add(c) {
+		conn.Close()
+		return ErrHubStopped
+	}
+	go c.writePump()
+
+	err := c.readPump(handle)
+	h.remove(c)
+	conn.Close()
+	if websocket.IsCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway) {
+		return nil
+	}
+	return err
+}
+
+func (c *Client) readPump(handle func(*Client, entity.Frame)) error {
+	cfg := c.hub.cfg
+	c.conn.SetReadLimit(cfg.MaxMessageSize)
+	c.conn.SetReadDeadline(time.Now().Add(cfg.PongWait))
+	c.conn.SetPongHandler(func(string) error {
+		return c.conn.SetReadDeadline(time.Now().Add(cfg.PongWait))
+	})
+
+	for {
+		var frame entity.Frame
+		if err := c.conn.ReadJSON(&frame); err != nil {
+			return err
+		}
+		c.conn.SetReadDeadline(time.Now().Add(cfg.PongWait))
+		handle(c, frame)
+	}
+}
+
+// writePump sends queued messages and pings to the peer. It closes the
+// connection when the queue is closed or a write fails, which also ends
+// readPump.
+func (c *Client) writePump() {
+	cfg := c.hub.cfg
+	ticker := time.NewTicker(cfg.PingPeriod)
+	defer func() {
+		ticker.Stop()
+		c.conn.Close()
+	}()
+
+	for {
+		select {
+		case frame, ok := <-c.send:
+			c.conn.SetWriteDeadline(time.Now().Add(cfg.WriteWait))
+			if !ok {
+				c.conn.WriteMessage(websocket.CloseMessage,
+					websocket.FormatCloseMessage(websocket.CloseTryAgainLater, "disconnected by server"))
+				return
+			}
+			if err := c.conn.WriteJSON(frame); err != nil {
+				return
+			}
+		case <-ticker.C:
+			c.conn.SetWriteDeadline(time.Now().Add(cfg.WriteWait))
+			if err := c.conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,344 @@
+package websocket
+
+import (
+	"context"
+	"time"
+
+	"github.com/Ulyana-kru00/forum-project/chat/internal/entity"
+)
+
+type Config struct {
+	// WriteWait bounds a single write to a peer.
+	WriteWait time.Duration
+	// PongWait is how long a peer may stay silent, pongs included, before
+	// it is considered dead.
+	PongWait time.Duration
+	// PingPeriod is how often peers are pinged; it must be below PongWait.
+	PingPeriod time.Duration
+	// MaxMessageSize is the largest message read from a peer, in bytes.
+	MaxMessageSize int64
+	// SendQueue is how many outgoing messages may wait for a peer. A peer
+	// that falls further behind is disconnected.
+	SendQueue int
+}
+
+var defaultConfig = Config{
+	WriteWait:      10 * time.Second,
+	PongWait:       60 * time.Second,
+	PingPeriod:     54 * time.Second,
+	MaxMessageSize: 4096,
+	SendQueue:      64,
+}
+
+// Hub fans messages out to the clients subscribed to their room. All of its
+// state is owned by the Run goroutine; other goroutines talk to it through
+// channels.
+type Hub struct {
+	cfg Config
+
+	// clients maps each connected client to the rooms it listens to.
+	clients    map[*Client]map[int64]*roomState
+	rooms      map[int64]map[*Client]bool
+	register   chan *Client
+	unregister chan *Client
+	subscribe  chan subscription
+	evict      chan eviction
+	broadcast  chan entity.Message
+	history    chan history
+	reply      chan reply
+	done       chan struct{}
+}
+
+// roomState is where a client stands in one room.
+type roomState struct {
+	// resuming is set from a Resume until the matching Replay; meanwhile
+	// the room's messages are held back.
+	resuming bool
+	held     []entity.Message
+	// replayed holds the ids sent in the history that have not been
+	// broadcast since, so they are not delivered twice.
+	replayed map[int]bool
+	// floor is the oldest message of a history that left out older ones.
+	// The client pages in the rest itself, so live messages below it are
+	// skipped.
+	floor int
+}
+
+type subscription struct {
+	client *Client
+	roomID int64
+	join   bool
+	resume bool
+}
+
+type history struct {
+	client *Client
+	roomID int64
+	page   entity.MessagePage
+}
+
+type eviction struct {
+	roomID int64
+	userID int64
+}
+
+type reply struct {
+	client *Client
+	frame  entity.Frame
+}
+
+// NewHub returns a hub with cfg, where zero fields take their defaults.
+func NewHub(cfg Config) *Hub {
+	if cfg.WriteWait <= 0 {
+		cfg.WriteWait = defaultConfig.WriteWait
+	}
+	if cfg.PongWait <= 0 {
+		cfg.PongWait = defaultConfig.PongWait
+	}
+	if cfg.PingPeriod <= 0 || cfg.PingPeriod >= cfg.PongWait {
+		cfg.PingPeriod = cfg.PongWait * 9 / 10
+	}
+	if cfg.MaxMessageSize <= 0 {
+		cfg.MaxMessageSize = defaultConfig.MaxMessageSize
+	}
+	if cfg.SendQueue <= 0 {
+		cfg.SendQueue = defaultConfig.SendQueue
+	}
+
+	return &Hub{
+		cfg:        cfg,
+		clients:    make(map[*Client]map[int64]*roomState),
+		rooms:      make(map[int64]map[*Client]bool),
+		register:   make(chan *Client),
+		unregister: make(chan *Client),
+		subscribe:  make(chan subscription),
+		evict:      make(chan eviction),
+		broadcast:  make(chan entity.Message),
+		history:    make(chan history),
+		reply:      make(chan reply),
+		done:       make(chan struct{}),
+	}
+}
+
+// Run serves the hub until ctx is done, then disconnects every client.
+func (h *Hub) Run(ctx context.Context) {
+	defer close(h.done)
+
+	for {
+		select {
+		case <-ctx.Done():
+			for c := range h.clients {
+				h.drop(c)
+			}
+			return
+		case c := <-h.register:
+			h.clients[c] = make(map[int64]*roomState)
+		case c := <-h.unregister:
+			if _, ok := h.clients[c]; ok {
+				h.drop(c)
+			}
+		case s := <-h.subscribe:
+			if _, ok := h.clients[s.client]; !ok {
+				continue
+			}
+			if s.join {
+				h.join(s.client, s.roomID, s.resume)
+			} else {
+				h.leave(s.client, s.roomID)
+			}
+		case e := <-h.evict:
+			for c := range h.rooms[e.roomID] {
+				if e.userID == 0 || c.userID == e.userID {
+					h.leave(c, e.roomID)
+				}
+			}
+		case msg := <-h.broadcast:
+			for c := range h.rooms[msg.RoomID] {
+				h.deliver(c, h.clients[c][msg.RoomID], msg)
+			}
+		case hist := <-h.history:
+			h.replay(hist)
+		case r := <-h.reply:
+			if _, ok := h.clients[r.client]; ok {
+				h.enqueue(r.client, r.frame)
+			}
+		}
+	}
+}
+
+func (h *Hub) join(c *Client, roomID int64, resume bool) {
+	if h.rooms[roomID] == nil {
+		h.rooms[roomID] = make(map[*Client]bool)
+	}
+	h.rooms[roomID][c] = true
+	h.clients[c][roomID] = &roomState{resuming: resume}
+	h.enqueue(c, entity.Frame{Type: entity.FrameJoined, RoomID: roomID})
+}
+
+func (h *Hub) leave(c *Client, roomID int64) {
+	if h.clients[c][roomID] == nil {
+		return
+	}
+	h.unsubscribe(c, roomID)
+	h.enqueue(c, entity.Frame{Type: entity.FrameLeft, RoomID: roomID})
+}
+
+func (h *Hub) unsubscribe(c *Client, roomID int64) {
+	delete(h.clients[c], roomID)
+	delete(h.rooms[roomID], c)
+	if len(h.rooms[roomID]) == 0 {
+		delete(h.rooms, roomID)
+	}
+}
+
+// deliver sends msg to c, holds it back while c waits for its history, or
+// skips it if c already has it or is to page it in.
+func (h *Hub) deliver(c *Client, st *roomState, msg entity.Message) bool {
+	if msg.ID < st.floor {
+		return true
+	}
+	if st.replayed[msg.ID] {
+		delete(st.replayed, msg.ID)
+		return true
+	}
+	if st.resuming {
+		if len(st.held) >= h.cfg.SendQueue {
+			h.drop(c)
+			return false
+		}
+		st.held = append(st.held, msg)
+		return true
+	}
+	return h.enqueue(c, entity.MessageFrame(msg))
+}
+
+// replay sends a resuming client its history, then the messages held back
+// meanwhile that the history does not cover, and makes it live.
+func (h *Hub) replay(hist history) {
+	c := hist.client
+	st := h.clients[c][hist.roomID]
+	if st == nil || !st.resuming {
+		// The client left or was evicted while the history was loaded.
+		return
+	}
+	if !h.enqueue(c, entity.HistoryFrame(hist.roomID, hist.page)) {
+		return
+	}
+
+	msgs := hist.page.Messages
+	st.resuming = false
+	st.replayed = make(map[int]bool, len(msgs))
+	for _, msg := range msgs {
+		st.replayed[msg.ID] = true
+	}
+	if hist.page.NextBefore != 0 && len(msgs) > 0 {
+		st.floor = msgs[0].ID
+	}
+
+	held := st.held
+	st.held = nil
+	for _, msg := range held {
+		if !h.deliver(c, st, msg) {
+			return
+		}
+	}
+}
+
+// enqueue hands frame to the writer of c, or drops c if it is too slow to
+// keep up rather than stall everyone else. It reports whether c is still
+// connected.
+func (h *Hub) enqueue(c *Client, frame entity.Frame) bool {
+	select {
+	case c.send <- frame:
+		return true
+	default:
+		h.drop(c)
+		return false
+	}
+}
+
+// drop forgets c and closes its queue, which makes its writer hang up.
+func (h *Hub) drop(c *Client) {
+	for roomID := range h.clients[c] {
+		h.unsubscribe(c, roomID)
+	}
+	delete(h.clients, c)
+	close(c.send)
+}
+
+// Broadcast queues msg for every client in its room. Like the other methods
+// below, it returns without effect once the hub has stopped.
+func (h *Hub) Broadcast(msg entity.Message) {
+	select {
+	case h.broadcast <- msg:
+	case <-h.done:
+	}
+}
+
+// Join subscribes c to a room and confirms with a joined frame. Checking
+// that the user may read the room is up to the caller.
+func (h *Hub) Join(c *Client, roomID int64) {
+	h.changeSubscription(subscription{client: c, roomID: roomID, join: true})
+}
+
+// Resume subscribes c to a room like Join, but holds the room's messages
+// back until Replay brings the client up to date. The caller loads the
+// messages the client missed only after Resume returns, so none can fall
+// between the history and the live messages.
+func (h *Hub) Resume(c *Client, roomID int64) {
+	h.changeSubscription(subscription{client: c, roomID: roomID, join: true, resume: true})
+}
+
+// Replay sends the history of a room to c after a Resume, followed by the
+// messages held back meanwhile, leaving out those the history has.
+func (h *Hub) Replay(c *Client, roomID int64, page entity.MessagePage) {
+	select {
+	case h.history <- history{client: c, roomID: roomID, page: page}:
+	case <-h.done:
+	}
+}
+
+// Leave unsubscribes c from a room and confirms with a left frame.
+func (h *Hub) Leave(c *Client, roomID int64) {
+	h.changeSubscription(subscription{client: c, roomID: roomID})
+}
+
+// Evict unsubscribes the connections of userID from a room, or all of its
+// connections when userID is 0, telling each with a left frame.
+func (h *Hub) Evict(roomID, userID int64) {
+	select {
+	case h.evict <- eviction{roomID: roomID, userID: userID}:
+	case <-h.done:
+	}
+}
+
+// Reply queues frame for c alone.
+func (h *Hub) Reply(c *Client, frame entity.Frame) {
+	select {
+	case h.reply <- reply{client: c, frame: frame}:
+	case <-h.done:
+	}
+}
+
+func (h *Hub) changeSubscription(s subscription) {
+	select {
+	case h.subscribe <- s:
+	case <-h.done:
+	}
+}
+
+func (h *Hub) add(c *Client) bool {
+	select {
+	case h.register <- c:
+		return true
+	case <-h.done:
+		return false
+	}
+}
+
+func (h *Hub) remove(c *Client) {
+	select {
+	case h.unregister <- c:
+	case <-h.done:
+	}
+}
@@ -0,0 +1,236 @@
+package websocket
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Ulyana-kru00/forum-project/chat/internal/entity"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func startHub(t *testing.T, cfg Config) *Hub {
+	hub := NewHub(cfg)
+	ctx, cancel := context.WithCancel(context.Background())
+	go hub.Run(ctx)
+	t.Cleanup(cancel)
+	return hub
+}
+
+// echoServer subscribes clients to the rooms they join and broadcasts their
+// messages to the room they name.
+func echoServer(t *testing.T, hub *Hub) string {
+	upgrader := NewUpgrader(nil)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		hub.Serve(conn, 1, func(c *Client, f entity.Frame) {
+			switch f.Type {
+			case entity.FrameJoin:
+				hub.Join(c, f.RoomID)
+			case entity.FrameMessage:
+				hub.Broadcast(entity.Message{RoomID: f.RoomID, Message: f.Message})
+			}
+		})
+	}))
+	t.Cleanup(server.Close)
+	return "ws" + strings.TrimPrefix(server.URL, "http")
+}
+
+// dialRoom connects to url and waits until the hub confirms the join.
+func dialRoom(t *testing.T, url string, roomID int64) *websocket.Conn {
+	conn, _, err := websocket.DefaultDialer.Dial(url, nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	require.NoError(t, conn.WriteJSON(entity.Frame{Type: entity.FrameJoin, RoomID: roomID}))
+	var frame entity.Frame
+	require.NoError(t, conn.ReadJSON(&frame))
+	require.Equal(t, entity.Frame{Type: entity.FrameJoined, RoomID: roomID}, frame)
+	return conn
+}
+
+func TestHub_ConcurrentBroadcast(t *testing.T) {
+	const clients, perClient = 20, 25
+	hub := startHub(t, Config{SendQueue: clients * perClient})
+	url := echoServer(t, hub)
+
+	conns := make([]*websocket.Conn, clients)
+	for i := range conns {
+		conns[i] = dialRoom(t, url, 1)
+	}
+
+	var wg sync.WaitGroup
+	for _, conn := range conns {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < perClient; i++ {
+				assert.NoError(t, conn.WriteJSON(entity.Frame{Type: entity.FrameMessage, RoomID: 1, Message: "hi"}))
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+			for i := 0; i < clients*perClient; i++ {
+				var frame entity.Frame
+				if !assert.NoError(t, conn.ReadJSON(&frame)) {
+					return
+				}
+				assert.Equal(t, entity.FrameMessage, frame.Type)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestHub_RoomScoping(t *testing.T) {
+	hub := startHub(t, Config{})
+	url := echoServer(t, hub)
+
+	general := dialRoom(t, url, 1)
+	other := dialRoom(t, url, 2)
+
+	require.NoError(t, general.WriteJSON(entity.Frame{Type: entity.FrameMessage, RoomID: 1, Message: "to general"}))
+	require.NoError(t, general.WriteJSON(entity.Frame{Type: entity.FrameMessage, RoomID: 2, Message: "to other"}))
+
+	// The room 1 message reaches only its subscriber; the client in room 2
+	// sees just the message sent there.
+	var frame entity.Frame
+	require.NoError(t, general.ReadJSON(&frame))
+	assert.Equal(t, "to general", frame.Message)
+	require.NoError(t, other.ReadJSON(&frame))
+	assert.Equal(t, "to other", frame.Message)
+	assert.Equal(t, int64(2), frame.RoomID)
+}
+
+func TestHub_Evict(t *testing.T) {
+	hub := startHub(t, Config{})
+	url := echoServer(t, hub)
+
+	conn := dialRoom(t, url, 1)
+	hub.Evict(1, 1)
+
+	var frame entity.Frame
+	require.NoError(t, conn.ReadJSON(&frame))
+	assert.Equal(t, entity.Frame{Type: entity.FrameLeft, RoomID: 1}, frame)
+
+	// Evicted from the room, the client no longer gets its messages.
+	hub.Broadcast(entity.Message{RoomID: 1, Message: "missed"})
+	require.NoError(t, conn.WriteJSON(entity.Frame{Type: entity.FrameJoin, RoomID: 2}))
+	require.NoError(t, conn.ReadJSON(&frame))
+	assert.Equal(t, entity.FrameJoined, frame.Type)
+}
+
+func TestHub_ResumeWithoutGapsOrDuplicates(t *testing.T) {
+	hub := startHub(t, Config{})
+	c := &Client{hub: hub, send: make(chan entity.Frame, 8)}
+	require.True(t, hub.add(c))
+
+	hub.Resume(c, 1)
+	// While the history is loaded, 3 and 5 were saved earlier and are only
+	// now broadcast, 7 was saved after the history was read.
+	hub.Broadcast(entity.Message{ID: 3, RoomID: 1})
+	hub.Broadcast(entity.Message{ID: 5, RoomID: 1})
+	hub.Broadcast(entity.Message{ID: 7, RoomID: 1})
+	// The history holds the newest of the missed messages, 4 to 6; the
+	// client pages in the ones before 4 itself.
+	hub.Replay(c, 1, entity.MessagePage{
+		Messages:   []entity.Message{{ID: 4, RoomID: 1}, {ID: 5, RoomID: 1}, {ID: 6, RoomID: 1}},
+		NextBefore: 4,
+	})
+	hub.Broadcast(entity.Message{ID: 6, RoomID: 1})
+	hub.Broadcast(entity.Message{ID: 8, RoomID: 1})
+
+	frame := <-c.send
+	assert.Equal(t, entity.FrameJoined, frame.Type)
+	frame = <-c.send
+	require.Equal(t, entity.FrameHistory, frame.Type)
+	assert.Len(t, frame.History.Messages, 3)
+	assert.Equal(t, 4, frame.History.NextBefore)
+
+	var live []int
+	for len(live) < 2 {
+		frame = <-c.send
+		live = append(live, frame.ID)
+	}
+	assert.Equal(t, []int{7, 8}, live)
+	assert.Empty(t, c.send)
+}
+
+func TestHub_ReplayAfterLeaveIsDropped(t *testing.T) {
+	hub := startHub(t, Config{})
+	c := &Client{hub: hub, send: make(chan entity.Frame, 8)}
+	require.True(t, hub.add(c))
+
+	hub.Resume(c, 1)
+	hub.Leave(c, 1)
+	hub.Replay(c, 1, entity.MessagePage{Messages: []entity.Message{{ID: 1, RoomID: 1}}})
+	hub.Join(c, 2)
+
+	var types []entity.FrameType
+	for len(types) < 3 {
+		types = append(types, (<-c.send).Type)
+	}
+	assert.Equal(t, []entity.FrameType{entity.FrameJoined, entity.FrameLeft, entity.FrameJoined}, types)
+}
+
+func TestHub_DropsSlowConsumer(t *testing.T) {
+	hub := startHub(t, Config{SendQueue: 2})
+	slow := &Client{hub: hub, send: make(chan entity.Frame, 2)}
+	require.True(t, hub.add(slow))
+	hub.Join(slow, 1)
+
+	hub.Broadcast(entity.Message{RoomID: 1, Message: "first"})
+	hub.Broadcast(entity.Message{RoomID: 1, Message: "second"})
+	// The hub takes the next message only after it is done with "second".
+	hub.Broadcast(entity.Message{RoomID: 1, Message: "third"})
+
+	frame, ok := <-slow.send
+	assert.True(t, ok)
+	assert.Equal(t, entity.FrameJoined, frame.Type)
+	frame, ok = <-slow.send
+	assert.True(t, ok)
+	assert.Equal(t, "first", frame.Message)
+	_, ok = <-slow.send
+	assert.False(t, ok, "the queue of a client that fell behind is closed")
+}
+
+func TestHub_DisconnectsDeadPeer(t *testing.T) {
+	hub := startHub(t, Config{PongWait: 200 * time.Millisecond, PingPeriod: 50 * time.Millisecond})
+	url := echoServer(t, hub)
+
+	conn, _, err := websocket.DefaultDialer.Dial(url, nil)
+	require.NoError(t, err)
+	defer conn.Close()
+	// A peer that reads but never answers pings.
+	conn.SetPingHandler(func(string) error { return nil })
+
+	conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	for {
+		if _, _, err = conn.ReadMessage(); err != nil {
+			break
+		}
+	}
+	// The server hung up; the client's own deadline would show as a timeout.
+	assert.True(t, websocket.IsCloseError(err, websocket.CloseAbnormalClosure), "unexpected error: %v", err)
+}
+
+func TestHub_StoppedHubRefusesClients(t *testing.T) {
+	hub := NewHub(Config{})
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	hub.Run(ctx)
+
+	assert.False(t, hub.add(&Client{hub: hub, send: make(chan entity.Frame)}))
+	hub.Broadcast(entity.Message{RoomID: 1, Message: "nobody listens"})
+}
@@ -2,17 +2,32 @@ package websocket
 
 import (
 	"net/http"
-
-	"github.com/Ulyana-kru00/forum-project/chat/internal/entity"
+	"strings"
 
 	"github.com/gorilla/websocket"
 )
 
-var Upgrader = websocket.Upgrader{
-	CheckOrigin: func(r *http.Request) bool {
-		return true
-	},
-}
+// BearerSubprotocol lets browsers, which cannot set headers on a WebSocket
+// handshake, pass the token as "Sec-WebSocket-Protocol: bearer, <token>".
+const BearerSubprotocol = "bearer"
 
-var Clients = make(map[*websocket.Conn]bool)
-var Broadcast = make(chan entity.Message)
+// NewUpgrader returns an upgrader that accepts browser connections only from
+// allowedOrigins; "*" allows any origin. Requests without an Origin header
+// come from non-browser clients and are accepted.
+func NewUpgrader(allowedOrigins []string) *websocket.Upgrader {
+	return &websocket.Upgrader{
+		Subprotocols: []string{BearerSubprotocol},
+		CheckOrigin: func(r *http.Request) bool {
+			origin := r.Header.Get("Origin")
+			if origin == "" {
+				return true
+			}
+			for _, allowed := range allowedOrigins {
+				if allowed == "*" || strings.EqualFold(allowed, origin) {
+					return true
+				}
+			}
+			return false
+		},
+	}
+}
@@ -2,7 +2,9 @@ package main
 
 import (
 	"context"
+	"flag"
 	"fmt"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
@@ -14,7 +16,10 @@ import (
 	"github.com/Ulyana-kru00/forum-project/forum-servise/internal/handler"
 	"github.com/Ulyana-kru00/forum-project/forum-servise/internal/repository"
 	"github.com/Ulyana-kru00/forum-project/forum-servise/internal/usecase"
+	"github.com/Ulyana-kru00/forum-project/forum-servise/pkg/blobstore"
+	"github.com/Ulyana-kru00/forum-project/forum-servise/pkg/loader"
 	"github.com/Ulyana-kru00/forum-project/forum-servise/pkg/logger"
+	"github.com/Ulyana-kru00/forum-project/forum-servise/pkg/verifier"
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
 	"github.com/jmoiron/sqlx"
@@ -23,9 +28,42 @@ import (
 	ginSwagger "github.com/swaggo/gin-swagger"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/reflection"
 )
 
+var (
+	blobStoreKind = flag.String("blob-store", "local", "Where uploaded files are stored: local or s3")
+	uploadsDir    = flag.String("uploads-dir", "./uploads", "Directory of the local blob store")
+	s3Endpoint    = flag.String("s3-endpoint", "http://localhost:9000", "Base URL of the S3-compatible service, e.g. a local MinIO")
+	s3Bucket      = flag.String("s3-bucket", "forum-uploads", "S3 bucket for uploaded files")
+	s3Region      = flag.String("s3-region", "us-east-1", "S3 region used to sign requests")
+	s3AccessKey   = flag.String("s3-access-key", "", "S3 access key")
+	s3SecretKey   = flag.String("s3-secret-key", "", "S3 secret key")
+	maxUploadSize = flag.Int64("max-upload-size", 10<<20, "Largest accepted upload in bytes")
+
+	reportHideThreshold = flag.Int64("report-hide-threshold", 5, "Open reports after which content is hidden until moderated, 0 disables")
+	trashRetention      = flag.Duration("trash-retention", 30*24*time.Hour, "How long deleted posts stay in the trash before they are removed for good")
+
+	jwksURL = flag.String("jwks-url", "http://localhost:8080/.well-known/jwks.json", "Auth service JWKS endpoint; if empty, every token is checked with a ValidateToken call")
+)
+
+// @title Forum Service API
+// @version 1.0
+// @description API for forum service with posts and comments management
+
+// @host localhost:8081
+// @schemes http
+
+// @securityDefinitions.apikey BearerAuth
+// @in header
+// @name Authorization
+
+// @securityDefinitions.apikey ApiKeyAuth
+// @in header
+// @name Authorization
 func main() {
+	flag.Parse()
+
 	log, err := logger.NewLogger("debug")
 	if err != nil {
 		fmt.Printf("Failed to create logger: %v\n", err)
@@ -67,19 +105,89 @@ func main() {
 	}
 	defer authConn.Close()
 
-	// Инициализация репозиториев и usecases
 	authClient := pb.NewAuthServiceClient(authConn)
+
+	// Локальная проверка JWT по ключам Auth Service
+	verifierCtx, stopVerifier := context.WithCancel(context.Background())
+	defer stopVerifier()
+
+	var tokenVerifier verifier.TokenVerifier
+	if *jwksURL != "" {
+		keySource := verifier.NewKeySource(*jwksURL, log)
+		if err := keySource.Refresh(verifierCtx); err != nil {
+			log.Warnw("Failed to fetch JWKS, keys will be fetched on demand", "error", err)
+		}
+		go keySource.Run(verifierCtx, 10*time.Minute)
+
+		tokenVerifier = verifier.New(keySource, authClient, verifier.Config{
+			RevocationTTL:     30 * time.Second,
+			RevocationTimeout: 500 * time.Millisecond,
+		}, log)
+	} else {
+		// Без JWKS каждый токен проверяется запросом в Auth Service
+		log.Warn("No --jwks-url given, validating every token with auth-service")
+		tokenVerifier = verifier.NewRemote(authClient)
+	}
+
+	// Инициализация репозиториев и usecases
 	postRepo := repository.NewPostRepository(db)
 	commentRepo := repository.NewCommentRepository(db)
-	postUsecase := usecase.NewPostUsecase(postRepo, authClient, log)
-	commentUC := usecase.NewCommentUseCase(commentRepo, postRepo, authClient)
+	searchRepo := repository.NewSearchRepository(db)
+	categoryRepo := repository.NewCategoryRepository(db)
+	voteRepo := repository.NewVoteRepository(db)
+	tagRepo := repository.NewTagRepository(db)
+	revisionRepo := repository.NewRevisionRepository(db)
+	attachmentRepo := repository.NewAttachmentRepository(db)
+	moderationRepo := repository.NewModerationRepository(db)
+	notificationRepo := repository.NewNotificationRepository(db)
+	postUsecase := usecase.NewPostUsecase(postRepo, authClient, tokenVerifier, log)
+	commentUC := usecase.NewCommentUseCase(commentRepo, postRepo, authClient, tokenVerifier)
+	notificationUC := usecase.NewNotificationUsecase(notificationRepo, authClient, tokenVerifier, log)
+	postUsecase.Notifier = notificationUC
+	commentUC.Notifier = notificationUC
+	searchUC := usecase.NewSearchUsecase(searchRepo, authClient)
+	categoryUC := usecase.NewCategoryUsecase(categoryRepo, tokenVerifier)
+	voteUC := usecase.NewVoteUsecase(voteRepo, tokenVerifier)
+	tagUC := usecase.NewTagUsecase(tagRepo, tokenVerifier)
+	revisionUC := usecase.NewRevisionUsecase(revisionRepo, authClient, tokenVerifier)
+	moderationUC := usecase.NewModerationUsecase(moderationRepo, tokenVerifier, usecase.ModerationConfig{
+		AutoHideThreshold: *reportHideThreshold,
+	}, log)
+	postAdminUC := usecase.NewPostAdminUsecase(postRepo, tokenVerifier, log)
+	go postAdminUC.RunTrashPurger(verifierCtx, time.Hour, *trashRetention)
+	go notificationUC.RunDispatcher(verifierCtx)
+
+	// Хранилище загруженных файлов
+	blobStore, err := newBlobStore()
+	if err != nil {
+		log.Error("Failed to create blob store", err)
+		os.Exit(1)
+	}
+	attachmentUC := usecase.NewAttachmentUsecase(attachmentRepo, postRepo, blobStore, tokenVerifier, usecase.AttachmentConfig{
+		MaxSize:       *maxUploadSize,
+		ThumbnailSize: 320,
+		OrphanTTL:     24 * time.Hour,
+		GCBatch:       500,
+	}, log)
+	go attachmentUC.RunGarbageCollector(verifierCtx, time.Hour)
 
 	// Регистрация обработчиков
 	postHandler := handler.NewPostHandler(postUsecase, log)
 	commentHandler := handler.NewCommentHandler(commentUC)
+	searchHandler := handler.NewSearchHandler(searchUC, log)
+	categoryHandler := handler.NewCategoryHandler(categoryUC, log)
+	voteHandler := handler.NewVoteHandler(voteUC, log)
+	tagHandler := handler.NewTagHandler(tagUC, log)
+	revisionHandler := handler.NewRevisionHandler(revisionUC, log)
+	renderHandler := handler.NewRenderHandler(log)
+	attachmentHandler := handler.NewAttachmentHandler(attachmentUC, *maxUploadSize, log)
+	moderationHandler := handler.NewModerationHandler(moderationUC, log)
+	postAdminHandler := handler.NewPostAdminHandler(postAdminUC, log)
+	notificationHandler := handler.NewNotificationHandler(notificationUC, log)
 
 	// Группировка роутов
 	api := router.Group("/api/v1")
+	api.Use(loader.Middleware(authClient))
 	{
 		// Роуты для постов
 		posts := api.Group("/posts")
@@ -88,14 +196,91 @@ func main() {
 			posts.GET("", postHandler.GetPosts)
 			posts.DELETE("/:id", postHandler.DeletePost)
 			posts.PUT("/:id", postHandler.UpdatePost)
+			posts.PUT("/:id/vote", voteHandler.VotePost)
+			posts.DELETE("/:id/vote", voteHandler.RetractPostVote)
+			posts.PUT("/:id/pin", postAdminHandler.PinPost)
+			posts.DELETE("/:id/pin", postAdminHandler.UnpinPost)
+			posts.PUT("/:id/lock", postAdminHandler.LockPost)
+			posts.DELETE("/:id/lock", postAdminHandler.UnlockPost)
+		}
+
+		// Корзина удаленных постов
+		trash := api.Group("/trash")
+		{
+			trash.GET("/posts", postAdminHandler.ListTrash)
+			trash.POST("/posts/:id/restore", postAdminHandler.RestorePost)
+		}
+
+		// Роуты для истории правок
+		revisions := api.Group("/posts/:id/revisions")
+		{
+			revisions.GET("", revisionHandler.ListRevisions)
+			revisions.GET("/diff", revisionHandler.DiffRevisions)
+			revisions.GET("/:revision", revisionHandler.GetRevision)
+			revisions.POST("/:revision/restore", revisionHandler.RestoreRevision)
 		}
 
+		// Роуты для файлов
+		api.POST("/attachments", attachmentHandler.Upload)
+		api.GET("/attachments/:id", attachmentHandler.GetAttachment)
+		api.GET("/attachments/:id/thumbnail", attachmentHandler.GetThumbnail)
+		api.GET("/posts/:id/attachments", attachmentHandler.ListPostAttachments)
+		api.POST("/posts/:id/attachments", attachmentHandler.AttachToPost)
+
 		// Роуты для комментариев
 		comments := api.Group("/posts/:id/comments")
 		{
 			comments.POST("", commentHandler.CreateComment)
 			comments.GET("", commentHandler.GetCommentsByPostID)
+			comments.GET("/:commentId/replies", commentHandler.GetCommentReplies)
+			comments.PUT("/:commentId", commentHandler.UpdateComment)
+			comments.DELETE("/:commentId", commentHandler.DeleteComment)
+			comments.PUT("/:commentId/vote", voteHandler.VoteComment)
+			comments.DELETE("/:commentId/vote", voteHandler.RetractCommentVote)
+		}
+
+		// Роуты для категорий
+		categories := api.Group("/categories")
+		{
+			categories.GET("", categoryHandler.ListCategories)
+			categories.GET("/:slug", categoryHandler.GetCategory)
+			categories.POST("", categoryHandler.CreateCategory)
+			categories.PUT("/:id", categoryHandler.UpdateCategory)
+		}
+
+		// Роуты для тегов
+		tags := api.Group("/tags")
+		{
+			tags.GET("", tagHandler.ListTags)
+			tags.PUT("/:name", tagHandler.RenameTag)
+			tags.POST("/:name/merge", tagHandler.MergeTags)
 		}
+
+		// Жалобы и модерация
+		api.POST("/reports", moderationHandler.CreateReport)
+		api.GET("/warnings", moderationHandler.MyWarnings)
+		moderation := api.Group("/moderation")
+		{
+			moderation.GET("/reports", moderationHandler.GetQueue)
+			moderation.GET("/reports/:type/:id", moderationHandler.ListReports)
+			moderation.POST("/:type/:id/actions", moderationHandler.Act)
+		}
+
+		// Уведомления
+		notifications := api.Group("/notifications")
+		{
+			notifications.GET("", notificationHandler.ListNotifications)
+			notifications.GET("/unread-count", notificationHandler.UnreadCount)
+			notifications.POST("/read", notificationHandler.MarkRead)
+			notifications.GET("/preferences", notificationHandler.GetPreferences)
+			notifications.PUT("/preferences", notificationHandler.UpdatePreferences)
+		}
+
+		// Полнотекстовый поиск
+		api.GET("/search", searchHandler.Search)
+
+		// Предпросмотр Markdown
+		api.POST("/render", renderHandler.Render)
 	}
 
 	// Запуск сервера
@@ -113,6 +298,13 @@ func main() {
 
 	log.Info("Server started on :8081")
 
+	// Запуск gRPC сервера
+	grpcServer, err := startGRPCServer(":50053", handler.NewForumServer(postUsecase, categoryUC), authClient, log)
+	if err != nil {
+		log.Error("Failed to start gRPC server", err)
+		os.Exit(1)
+	}
+
 	// Graceful shutdown
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
@@ -124,6 +316,44 @@ func main() {
 	if err := server.Shutdown(ctx); err != nil {
 		log.Error("Server shutdown error", err)
 	}
+	grpcServer.GracefulStop()
 
 	log.Info("Server stopped")
 }
+
+// newBlobStore creates the store selected by the -blob-store flag.
+func newBlobStore() (blobstore.BlobStore, error) {
+	switch *blobStoreKind {
+	case "local":
+		return blobstore.NewLocalStore(*uploadsDir)
+	case "s3":
+		return blobstore.NewS3Store(blobstore.S3Config{
+			Endpoint:  *s3Endpoint,
+			Bucket:    *s3Bucket,
+			Region:    *s3Region,
+			AccessKey: *s3AccessKey,
+			SecretKey: *s3SecretKey,
+		}), nil
+	}
+	return nil, fmt.Errorf("unknown blob store %q", *blobStoreKind)
+}
+
+func startGRPCServer(addr string, forumServer pb.ForumServiceServer, authClient pb.AuthServiceClient, log *logger.Logger) (*grpc.Server, error) {
+	lis, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+
+	s := grpc.NewServer(grpc.UnaryInterceptor(loader.UnaryServerInterceptor(authClient)))
+	pb.RegisterForumServiceServer(s, forumServer)
+	reflection.Register(s)
+
+	go func() {
+		if err := s.Serve(lis); err != nil {
+			log.Error("gRPC server error", err)
+		}
+	}()
+
+	log.Info("gRPC server started on " + addr)
+	return s, nil
+}
@@ -0,0 +1,43 @@
+package entity
+
+import "time"
+
+// Attachment is a file uploaded to the forum. PostID is nil until it is
+// attached to a post; such uploads are garbage-collected after a while.
+// The content is stored once per Hash, however many attachments share it.
+type Attachment struct {
+	ID           int64     `json:"id" db:"id" example:"7"`
+	PostID       *int64    `json:"post_id" db:"post_id" example:"123"`
+	UploaderID   *int64    `json:"uploader_id" db:"uploader_id" example:"456"`
+	Hash         string    `json:"-" db:"hash"`
+	Filename     string    `json:"filename" db:"filename" example:"diagram.png"`
+	ContentType  string    `json:"content_type" db:"content_type" example:"image/png"`
+	Size         int64     `json:"size" db:"size" example:"48213"`
+	Width        *int      `json:"width,omitempty" db:"width" example:"1024"`
+	Height       *int      `json:"height,omitempty" db:"height" example:"768"`
+	HasThumbnail bool      `json:"-" db:"has_thumbnail"`
+	URL          string    `json:"url" db:"-" example:"/api/v1/attachments/7"`
+	ThumbnailURL string    `json:"thumbnail_url,omitempty" db:"-" example:"/api/v1/attachments/7/thumbnail"`
+	CreatedAt    time.Time `json:"created_at" db:"created_at" example:"2023-01-01T00:00:00Z"`
+}
+
+// BlobKey is the BlobStore key of the content of an attachment.
+func (a *Attachment) BlobKey() string {
+	return BlobKey(a.Hash)
+}
+
+// ThumbnailKey is the BlobStore key of the thumbnail of an image
+// attachment.
+func (a *Attachment) ThumbnailKey() string {
+	return ThumbnailKey(a.Hash)
+}
+
+// BlobKey names content by its SHA-256 in hex, fanned out over
+// directories by the first two digits.
+func BlobKey(hash string) string {
+	return hash[:2] + "/" + hash
+}
+
+func ThumbnailKey(hash string) string {
+	return BlobKey(hash) + ".thumb.jpg"
+}
@@ -5,8 +5,14 @@ import (
 )
 
 type Category struct {
-	ID          int64     `db:"id"`
-	Name        string    `db:"name"`
-	Description string    `db:"description"`
-	CreatedAt   time.Time `db:"created_at"`
+	ID          int64     `json:"id" db:"id" example:"1"`
+	Name        string    `json:"name" db:"name" example:"Общее"`
+	Slug        string    `json:"slug" db:"slug" example:"general"`
+	Description string    `json:"description" db:"description" example:"Обсуждения на любые темы"`
+	Position    int       `json:"position" db:"position" example:"0"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at" example:"2023-01-01T00:00:00Z"`
+
+	// Aggregates over the posts of the category.
+	PostCount      int64      `json:"post_count" db:"post_count" example:"42"`
+	LastActivityAt *time.Time `json:"last_activity_at,omitempty" db:"last_activity_at" example:"2023-01-02T00:00:00Z"`
 }
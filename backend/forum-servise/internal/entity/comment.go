@@ -3,11 +3,66 @@ package entity
 
 import "time"
 
+// DeletedCommentPlaceholder replaces the content of a deleted comment that
+// is kept because it has replies.
+const DeletedCommentPlaceholder = "[deleted]"
+
+// HiddenCommentPlaceholder replaces the content of a comment hidden by
+// moderation that is kept because it has replies.
+const HiddenCommentPlaceholder = "[hidden by moderator]"
+
 type Comment struct {
-	ID         int64     `json:"id" db:"id" example:"1"`
-	AuthorID   int64     `json:"author_id" db:"author_id" example:"1"`
-	PostID     int64     `json:"post_id" db:"post_id" example:"1"`
-	Content    string    `json:"content" db:"content" example:"текст комментария"`
-	CreatedAt  time.Time `db:"created_at"`
-	AuthorName string    `json:"author_name" db:"author_name"` // Исправлено db:"-"
+	ID          int64      `json:"id" db:"id" example:"1"`
+	AuthorID    int64      `json:"author_id" db:"author_id" example:"1"`
+	PostID      int64      `json:"post_id" db:"post_id" example:"1"`
+	ParentID    *int64     `json:"parent_id,omitempty" db:"parent_id" example:"1"`
+	Content     string     `json:"content" db:"content" example:"текст комментария"`
+	ContentHTML string     `json:"content_html" db:"content_html" example:"<p>текст комментария</p>"`
+	CreatedAt   time.Time  `db:"created_at"`
+	EditedAt    *time.Time `json:"edited_at,omitempty" db:"edited_at" example:"2023-01-02T00:00:00Z"`
+	Deleted     bool       `json:"deleted" db:"deleted" example:"false"`
+	Hidden      bool       `json:"hidden" db:"hidden" example:"false"`
+	AuthorName  string     `json:"author_name" db:"author_name"` // Исправлено db:"-"
+
+	// Position in the thread. Depth is 0 for top-level comments; Path lists
+	// the ids from the top-level comment down to this one, joined by dots.
+	Depth      int    `json:"depth" db:"depth" example:"1"`
+	Path       string `json:"path,omitempty" db:"path" example:"1.5"`
+	ReplyCount int64  `json:"reply_count" db:"reply_count" example:"2"`
+	Score      int64  `json:"score" db:"score" example:"3"`
+}
+
+// CommentSort is the ordering of comments within each level of a thread.
+type CommentSort string
+
+const (
+	// CommentSortNewest lists top-level comments newest first and replies
+	// in the order they were written.
+	CommentSortNewest CommentSort = "newest"
+	// CommentSortTop lists comments by score, older first among equals.
+	CommentSortTop CommentSort = "top"
+)
+
+// Valid reports whether s is one of the supported orderings.
+func (s CommentSort) Valid() bool {
+	return s == CommentSortNewest || s == CommentSortTop
+}
+
+// CommentTreeFilter selects one page of a comment thread: the direct
+// children of ParentID (top-level comments of the post when it is 0) and,
+// below each of them, up to RepliesLimit replies per comment.
+type CommentTreeFilter struct {
+	PostID       int64
+	ParentID     int64
+	Limit        int
+	RepliesLimit int
+	Sort         CommentSort
+	Cursor       string
+}
+
+// CommentPage is a flattened comment tree in display order. NextCursor
+// continues the list of direct children and is empty on the last page.
+type CommentPage struct {
+	Comments   []Comment `json:"comments"`
+	NextCursor string    `json:"next_cursor"`
 }
@@ -0,0 +1,62 @@
+package entity
+
+import "time"
+
+// NotificationType is the event a notification tells its recipient about.
+type NotificationType string
+
+const (
+	// NotificationPostComment: someone commented on the recipient's post.
+	NotificationPostComment NotificationType = "post_comment"
+	// NotificationCommentReply: someone replied to the recipient's comment.
+	NotificationCommentReply NotificationType = "comment_reply"
+	// NotificationMention: someone mentioned the recipient as @username in
+	// a post or comment.
+	NotificationMention NotificationType = "mention"
+)
+
+// NotificationTypes lists every notification type.
+var NotificationTypes = []NotificationType{NotificationPostComment, NotificationCommentReply, NotificationMention}
+
+// Valid reports whether t is one of the notification types.
+func (t NotificationType) Valid() bool {
+	switch t {
+	case NotificationPostComment, NotificationCommentReply, NotificationMention:
+		return true
+	}
+	return false
+}
+
+// Notification tells UserID that ActorID did something on a post.
+// CommentID is set when the event is about a comment.
+type Notification struct {
+	ID        int64            `json:"id" db:"id" example:"1"`
+	UserID    int64            `json:"-" db:"user_id"`
+	Type      NotificationType `json:"type" db:"type" example:"comment_reply"`
+	ActorID   *int64           `json:"actor_id" db:"actor_id" example:"7"`
+	ActorName string           `json:"actor_name,omitempty" db:"-" example:"john_doe"`
+	PostID    int64            `json:"post_id" db:"post_id" example:"42"`
+	CommentID *int64           `json:"comment_id,omitempty" db:"comment_id" example:"105"`
+	Read      bool             `json:"read" db:"read" example:"false"`
+	CreatedAt time.Time        `json:"created_at" db:"created_at" example:"2023-01-01T00:00:00Z"`
+}
+
+// NotificationFilter selects one page of the notifications of UserID,
+// newest first.
+type NotificationFilter struct {
+	UserID     int64
+	UnreadOnly bool
+	Limit      int
+	Cursor     string
+}
+
+// NotificationPage is one page of notifications. NextCursor is empty on the
+// last page.
+type NotificationPage struct {
+	Notifications []*Notification `json:"data"`
+	NextCursor    string          `json:"next_cursor"`
+}
+
+// NotificationPreferences tells for each type whether a user gets
+// notifications of it.
+type NotificationPreferences map[NotificationType]bool
@@ -3,9 +3,100 @@ package entity
 import "time"
 
 type Post struct {
-	ID        int64     `json:"id" db:"id" example:"123"`
-	Title     string    `json:"title" db:"title" example:"My Post Title"`
-	Content   string    `json:"content" db:"content" example:"Post content text"`
-	AuthorID  int64     `json:"author_id" db:"author_id" example:"456"`
-	CreatedAt time.Time `json:"created_at" db:"created_at" example:"2023-01-01T00:00:00Z"`
+	ID             int64     `json:"id" db:"id" example:"123"`
+	Title          string    `json:"title" db:"title" example:"My Post Title"`
+	Content        string    `json:"content" db:"content" example:"Post **content** text"`
+	ContentHTML    string    `json:"content_html" db:"content_html" example:"<p>Post <strong>content</strong> text</p>"`
+	AuthorID       int64     `json:"author_id" db:"author_id" example:"456"`
+	CategoryID     int64     `json:"category_id" db:"category_id" example:"1"`
+	CreatedAt      time.Time `json:"created_at" db:"created_at" example:"2023-01-01T00:00:00Z"`
+	CommentCount   int64     `json:"comment_count" db:"comment_count" example:"3"`
+	LastActivityAt time.Time `json:"last_activity_at" db:"last_activity_at" example:"2023-01-02T00:00:00Z"`
+	Score          int64     `json:"score" db:"score" example:"5"`
+	HotRank        float64   `json:"-" db:"hot_rank"`
+	Tags           []string  `json:"tags" db:"-" example:"golang,postgres"`
+
+	// Pinned posts are listed before all others; locked posts take no new
+	// comments.
+	Pinned bool `json:"pinned" db:"pinned" example:"false"`
+	Locked bool `json:"locked" db:"locked" example:"false"`
+	// Set for posts in the trash only.
+	DeletedAt *time.Time `json:"deleted_at,omitempty" db:"deleted_at" example:"2023-01-03T00:00:00Z"`
+	DeletedBy *int64     `json:"deleted_by,omitempty" db:"deleted_by" example:"1"`
+}
+
+// PostSort is the ordering of a posts listing.
+type PostSort string
+
+const (
+	PostSortNewest         PostSort = "newest"
+	PostSortOldest         PostSort = "oldest"
+	PostSortMostCommented  PostSort = "most_commented"
+	PostSortRecentlyActive PostSort = "recently_active"
+	PostSortTop            PostSort = "top"
+	PostSortHot            PostSort = "hot"
+)
+
+// Valid reports whether s is one of the supported orderings.
+func (s PostSort) Valid() bool {
+	switch s {
+	case PostSortNewest, PostSortOldest, PostSortMostCommented, PostSortRecentlyActive, PostSortTop, PostSortHot:
+		return true
+	}
+	return false
+}
+
+// TopWindow limits the "top" ordering to posts created within a period.
+type TopWindow string
+
+const (
+	TopWindowDay   TopWindow = "day"
+	TopWindowWeek  TopWindow = "week"
+	TopWindowMonth TopWindow = "month"
+	TopWindowYear  TopWindow = "year"
+	TopWindowAll   TopWindow = "all"
+)
+
+// Duration returns the length of the window, or 0 for TopWindowAll and
+// unknown windows.
+func (w TopWindow) Duration() time.Duration {
+	switch w {
+	case TopWindowDay:
+		return 24 * time.Hour
+	case TopWindowWeek:
+		return 7 * 24 * time.Hour
+	case TopWindowMonth:
+		return 30 * 24 * time.Hour
+	case TopWindowYear:
+		return 365 * 24 * time.Hour
+	}
+	return 0
+}
+
+// Valid reports whether w is one of the supported windows.
+func (w TopWindow) Valid() bool {
+	return w == TopWindowAll || w.Duration() > 0
+}
+
+// PostFilter selects one page of posts. Zero values mean "no restriction".
+type PostFilter struct {
+	AuthorID   int64
+	CategoryID int64
+	From       time.Time // inclusive lower bound on created_at
+	To         time.Time // exclusive upper bound on created_at
+	Query      string
+	Tags       []string // normalized tag names
+	TagMatch   TagMatch // how Tags are combined, TagMatchAll by default
+	Sort       PostSort
+	Window     TopWindow // only with PostSortTop
+	Limit      int
+	Cursor     string // next_cursor of the previous page
+}
+
+// PostPage is one page of a posts listing. The first page starts with the
+// pinned posts matching the filter, on top of Limit others. NextCursor is
+// empty on the last page.
+type PostPage struct {
+	Posts      []*Post
+	NextCursor string
 }
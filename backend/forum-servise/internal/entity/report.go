@@ -0,0 +1,148 @@
+package entity
+
+import "time"
+
+// ReportTargetType is the kind of content a report is about.
+type ReportTargetType string
+
+const (
+	ReportTargetPost        ReportTargetType = "post"
+	ReportTargetComment     ReportTargetType = "comment"
+	ReportTargetChatMessage ReportTargetType = "chat_message"
+)
+
+// Valid reports whether t is a kind of content that can be reported.
+func (t ReportTargetType) Valid() bool {
+	switch t {
+	case ReportTargetPost, ReportTargetComment, ReportTargetChatMessage:
+		return true
+	}
+	return false
+}
+
+// ReportTarget identifies one reported post, comment or chat message.
+type ReportTarget struct {
+	Type ReportTargetType `json:"type" db:"target_type" example:"comment"`
+	ID   int64            `json:"id" db:"target_id" example:"42"`
+}
+
+// ReportReason is why a user reported content.
+type ReportReason string
+
+const (
+	ReportReasonSpam     ReportReason = "spam"
+	ReportReasonAbuse    ReportReason = "abuse"
+	ReportReasonOffTopic ReportReason = "off_topic"
+	ReportReasonIllegal  ReportReason = "illegal"
+	ReportReasonOther    ReportReason = "other"
+)
+
+// Valid reports whether r is one of the supported reasons.
+func (r ReportReason) Valid() bool {
+	switch r {
+	case ReportReasonSpam, ReportReasonAbuse, ReportReasonOffTopic, ReportReasonIllegal, ReportReasonOther:
+		return true
+	}
+	return false
+}
+
+// ReportStatus is the triage state of a report.
+type ReportStatus string
+
+const (
+	ReportStatusOpen      ReportStatus = "open"
+	ReportStatusActioned  ReportStatus = "actioned"
+	ReportStatusDismissed ReportStatus = "dismissed"
+)
+
+// Valid reports whether s is one of the triage states.
+func (s ReportStatus) Valid() bool {
+	return s == ReportStatusOpen || s == ReportStatusActioned || s == ReportStatusDismissed
+}
+
+// ModerationAction is what a moderator does about reported content.
+type ModerationAction string
+
+const (
+	// ModerationHide keeps the content but takes it out of listings.
+	ModerationHide ModerationAction = "hide"
+	// ModerationDelete removes the content.
+	ModerationDelete ModerationAction = "delete"
+	// ModerationWarn leaves the content alone and warns its author.
+	ModerationWarn ModerationAction = "warn"
+	// ModerationDismiss closes the reports without action and brings back
+	// content that was hidden automatically.
+	ModerationDismiss ModerationAction = "dismiss"
+)
+
+// Valid reports whether a is one of the supported actions.
+func (a ModerationAction) Valid() bool {
+	switch a {
+	case ModerationHide, ModerationDelete, ModerationWarn, ModerationDismiss:
+		return true
+	}
+	return false
+}
+
+// Status is the state the reports are left in by a.
+func (a ModerationAction) Status() ReportStatus {
+	if a == ModerationDismiss {
+		return ReportStatusDismissed
+	}
+	return ReportStatusActioned
+}
+
+type Report struct {
+	ID         int64             `json:"id" db:"id" example:"1"`
+	TargetType ReportTargetType  `json:"target_type" db:"target_type" example:"comment"`
+	TargetID   int64             `json:"target_id" db:"target_id" example:"42"`
+	ReporterID *int64            `json:"reporter_id" db:"reporter_id" example:"7"`
+	Reason     ReportReason      `json:"reason" db:"reason" example:"spam"`
+	Details    string            `json:"details" db:"details" example:"Ссылки на казино"`
+	Status     ReportStatus      `json:"status" db:"status" example:"open"`
+	Action     *ModerationAction `json:"action,omitempty" db:"action" example:"hide"`
+	ResolvedBy *int64            `json:"resolved_by,omitempty" db:"resolved_by" example:"1"`
+	ResolvedAt *time.Time        `json:"resolved_at,omitempty" db:"resolved_at" example:"2023-01-02T00:00:00Z"`
+	CreatedAt  time.Time         `json:"created_at" db:"created_at" example:"2023-01-01T00:00:00Z"`
+}
+
+// ModerationQueueItem sums up the reports in one state about one piece of
+// content. AuthorID and Excerpt are empty when the content is gone.
+type ModerationQueueItem struct {
+	TargetType      ReportTargetType `json:"target_type" db:"target_type" example:"comment"`
+	TargetID        int64            `json:"target_id" db:"target_id" example:"42"`
+	AuthorID        *int64           `json:"author_id" db:"author_id" example:"456"`
+	Excerpt         string           `json:"excerpt" db:"excerpt" example:"Лучшее казино..."`
+	Hidden          bool             `json:"hidden" db:"hidden" example:"true"`
+	ReportCount     int64            `json:"report_count" db:"report_count" example:"3"`
+	Reasons         []string         `json:"reasons" db:"-" example:"spam,abuse"`
+	FirstReportedAt time.Time        `json:"first_reported_at" db:"first_reported_at" example:"2023-01-01T00:00:00Z"`
+	LastReportedAt  time.Time        `json:"last_reported_at" db:"last_reported_at" example:"2023-01-02T00:00:00Z"`
+}
+
+// ModerationQueueFilter selects one page of the moderation queue, oldest
+// reported content first. An empty TargetType means all kinds.
+type ModerationQueueFilter struct {
+	Status     ReportStatus
+	TargetType ReportTargetType
+	Limit      int
+	Cursor     string
+}
+
+// ModerationQueuePage is one page of the queue. NextCursor is empty on the
+// last page.
+type ModerationQueuePage struct {
+	Items      []*ModerationQueueItem `json:"items"`
+	NextCursor string                 `json:"next_cursor"`
+}
+
+// UserWarning is a warning a moderator gave the author of reported content.
+type UserWarning struct {
+	ID          int64            `json:"id" db:"id" example:"1"`
+	UserID      int64            `json:"user_id" db:"user_id" example:"456"`
+	ModeratorID *int64           `json:"moderator_id" db:"moderator_id" example:"1"`
+	TargetType  ReportTargetType `json:"target_type" db:"target_type" example:"comment"`
+	TargetID    int64            `json:"target_id" db:"target_id" example:"42"`
+	Note        string           `json:"note" db:"note" example:"Пожалуйста, без рекламы"`
+	CreatedAt   time.Time        `json:"created_at" db:"created_at" example:"2023-01-01T00:00:00Z"`
+}
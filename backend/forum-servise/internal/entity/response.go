@@ -2,12 +2,15 @@ package entity
 
 type ErrorResponse struct {
 	Error string `json:"error" example:"error message"`
+	Code  string `json:"code" example:"not_found"`
 }
 type SuccessResponse struct {
 	Message string `json:"message" example:"success message"`
 }
 
 type CreatePostRequest struct {
-	Title   string `json:"title" example:"My Post Title"`
-	Content string `json:"content" example:"Post content text"`
+	Title      string   `json:"title" example:"My Post Title"`
+	Content    string   `json:"content" example:"Post **content** text"`
+	CategoryID int64    `json:"category_id,omitempty" example:"1"`
+	Tags       []string `json:"tags,omitempty" example:"golang,postgres"`
 }
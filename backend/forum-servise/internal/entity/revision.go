@@ -0,0 +1,29 @@
+package entity
+
+import "time"
+
+// PostRevision is the state of a post after it was created or edited.
+// Revisions of a post are numbered from 1.
+type PostRevision struct {
+	ID           int64     `json:"id" db:"id" example:"10"`
+	PostID       int64     `json:"post_id" db:"post_id" example:"123"`
+	Revision     int       `json:"revision" db:"revision" example:"2"`
+	Title        string    `json:"title" db:"title" example:"My Post Title"`
+	Content      string    `json:"content" db:"content" example:"Post content text"`
+	ContentHTML  string    `json:"content_html" db:"content_html" example:"<p>Post content text</p>"`
+	EditorID     *int64    `json:"editor_id" db:"editor_id" example:"456"`
+	EditorName   string    `json:"editor_name,omitempty" db:"-" example:"alice"`
+	RestoredFrom *int      `json:"restored_from,omitempty" db:"restored_from" example:"1"`
+	CreatedAt    time.Time `json:"created_at" db:"created_at" example:"2023-01-01T00:00:00Z"`
+}
+
+// RevisionDiff compares two revisions of a post. Diff is a unified diff of
+// their content, empty when it did not change.
+type RevisionDiff struct {
+	PostID    int64  `json:"post_id" example:"123"`
+	From      int    `json:"from" example:"1"`
+	To        int    `json:"to" example:"2"`
+	TitleFrom string `json:"title_from" example:"My Post Title"`
+	TitleTo   string `json:"title_to" example:"My Better Post Title"`
+	Diff      string `json:"diff" example:"--- revision 1\n+++ revision 2\n@@ -1 +1 @@\n-old line\n+new line\n"`
+}
@@ -0,0 +1,49 @@
+package entity
+
+import "time"
+
+// SearchType restricts a search to posts or comments. Empty means both.
+type SearchType string
+
+const (
+	SearchTypeAll     SearchType = ""
+	SearchTypePost    SearchType = "post"
+	SearchTypeComment SearchType = "comment"
+)
+
+// Valid reports whether t is a supported search type.
+func (t SearchType) Valid() bool {
+	switch t {
+	case SearchTypeAll, SearchTypePost, SearchTypeComment:
+		return true
+	}
+	return false
+}
+
+type SearchFilter struct {
+	Query    string
+	Type     SearchType
+	AuthorID int64
+	Limit    int
+	Cursor   string // next_cursor of the previous page
+}
+
+// SearchResult is a matching post or comment. Snippet is HTML-escaped text
+// with the matched words wrapped in <mark> tags.
+type SearchResult struct {
+	Type       SearchType `json:"type" db:"kind" example:"post"`
+	ID         int64      `json:"id" db:"id" example:"1"`
+	PostID     int64      `json:"post_id" db:"post_id" example:"1"`
+	PostTitle  string     `json:"post_title" db:"post_title" example:"My Post Title"`
+	Snippet    string     `json:"snippet" db:"snippet" example:"... about <mark>golang</mark> ..."`
+	AuthorID   int64      `json:"author_id" db:"author_id" example:"1"`
+	AuthorName string     `json:"author_name" db:"-" example:"john_doe"`
+	Rank       float64    `json:"rank" db:"rank" example:"0.6"`
+	CreatedAt  time.Time  `json:"created_at" db:"created_at" example:"2023-01-01T00:00:00Z"`
+}
+
+// SearchPage is one page of search results. NextCursor is empty on the last page.
+type SearchPage struct {
+	Results    []*SearchResult
+	NextCursor string
+}
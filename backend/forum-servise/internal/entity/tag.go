@@ -0,0 +1,29 @@
+package entity
+
+type Tag struct {
+	ID        int64  `json:"id" db:"id" example:"1"`
+	Name      string `json:"name" db:"name" example:"golang"`
+	PostCount int64  `json:"post_count" db:"post_count" example:"42"`
+}
+
+// TagMatch says how a posts listing filtered by several tags combines them.
+type TagMatch string
+
+const (
+	// TagMatchAll keeps posts that have every tag of the filter.
+	TagMatchAll TagMatch = "all"
+	// TagMatchAny keeps posts that have at least one of them.
+	TagMatchAny TagMatch = "any"
+)
+
+// Valid reports whether m is one of the supported modes.
+func (m TagMatch) Valid() bool {
+	return m == TagMatchAll || m == TagMatchAny
+}
+
+// TagFilter selects tags for the tag directory. Zero values mean "no
+// restriction".
+type TagFilter struct {
+	Prefix string
+	Limit  int
+}
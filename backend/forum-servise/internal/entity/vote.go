@@ -0,0 +1,8 @@
+package entity
+
+// VoteResult is the score of a post or comment after a vote together with
+// the caller's vote: 1, -1, or 0 when it was retracted.
+type VoteResult struct {
+	Score int64 `json:"score" example:"12"`
+	Vote  int   `json:"vote" example:"1"`
+}
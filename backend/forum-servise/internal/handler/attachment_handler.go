@@ -0,0 +1,265 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"backend.com/forum/apperrors"
+	"github.com/Ulyana-kru00/forum-project/forum-servise/internal/entity"
+	"github.com/Ulyana-kru00/forum-project/forum-servise/internal/usecase"
+	"github.com/Ulyana-kru00/forum-project/forum-servise/pkg/logger"
+	"github.com/Ulyana-kru00/forum-project/forum-servise/pkg/media"
+	"github.com/gin-gonic/gin"
+)
+
+// multipartOverhead is the room left in an upload request for the
+// multipart framing around the file itself.
+const multipartOverhead = 64 << 10
+
+var (
+	errInvalidAttachmentID = apperrors.New(apperrors.ErrInvalidArgument, "invalid attachment id")
+	errMissingFile         = apperrors.New(apperrors.ErrInvalidArgument, "multipart field \"file\" is required")
+	errRequestTooLarge     = apperrors.New(apperrors.ErrTooLarge, "request body is too large")
+)
+
+type AttachmentHandler struct {
+	uc            usecase.AttachmentUsecaseInterface
+	maxUploadSize int64
+	logger        *logger.Logger
+}
+
+func NewAttachmentHandler(uc usecase.AttachmentUsecaseInterface, maxUploadSize int64, logger *logger.Logger) *AttachmentHandler {
+	return &AttachmentHandler{
+		uc:            uc,
+		maxUploadSize: maxUploadSize,
+		logger:        logger,
+	}
+}
+
+type attachRequest struct {
+	AttachmentIDs []int64 `json:"attachment_ids" binding:"required" example:"7,8"`
+}
+
+// Upload godoc
+// @Summary Загрузить файл
+// @Description Тип файла определяется по содержимому: изображения (JPEG, PNG, GIF, WebP), PDF и текст. Для изображений создается миниатюра. Файл, не прикрепленный к посту, со временем удаляется
+// @Tags attachments
+// @Accept multipart/form-data
+// @Produce json
+// @Security BearerAuth
+// @Param Authorization header string true "Bearer токен"
+// @Param file formData file true "Файл"
+// @Success 201 {object} entity.Attachment
+// @Failure 400 {object} entity.ErrorResponse
+// @Failure 401 {object} entity.ErrorResponse
+// @Failure 413 {object} entity.ErrorResponse
+// @Failure 500 {object} entity.ErrorResponse
+// @Router /api/v1/attachments [post]
+func (h *AttachmentHandler) Upload(c *gin.Context) {
+	token, ok := bearerToken(c)
+	if !ok {
+		return
+	}
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, h.maxUploadSize+multipartOverhead)
+
+	// The file is streamed from the request rather than spooled to disk
+	// first, so the size limit applies before anything is stored.
+	reader, err := c.Request.MultipartReader()
+	if err != nil {
+		writeError(c, errInvalidBody)
+		return
+	}
+	var filename string
+	var file io.Reader
+	for {
+		part, err := reader.NextPart()
+		if err != nil {
+			writeError(c, uploadError(err, errMissingFile))
+			return
+		}
+		if part.FormName() == "file" {
+			filename, file = part.FileName(), part
+			break
+		}
+	}
+
+	attachment, err := h.uc.Upload(c.Request.Context(), token, filename, file)
+	if err != nil {
+		err = uploadError(err, err)
+		logServerError(h.logger, "Failed to upload attachment", err, "filename", filename)
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, withURLs(attachment))
+}
+
+// GetAttachment godoc
+// @Summary Содержимое файла
+// @Description Изображения отдаются для показа в браузере, остальные файлы - для скачивания
+// @Tags attachments
+// @Produce octet-stream
+// @Param Authorization header string false "Bearer токен; модераторам отдаются и файлы скрытых постов"
+// @Param id path int true "ID файла"
+// @Success 200 {file} file
+// @Failure 400 {object} entity.ErrorResponse
+// @Failure 404 {object} entity.ErrorResponse
+// @Failure 500 {object} entity.ErrorResponse
+// @Router /api/v1/attachments/{id} [get]
+func (h *AttachmentHandler) GetAttachment(c *gin.Context) {
+	h.serve(c, false)
+}
+
+// GetThumbnail godoc
+// @Summary Миниатюра изображения
+// @Tags attachments
+// @Produce jpeg
+// @Param Authorization header string false "Bearer токен; модераторам отдаются и файлы скрытых постов"
+// @Param id path int true "ID файла"
+// @Success 200 {file} file
+// @Failure 400 {object} entity.ErrorResponse
+// @Failure 404 {object} entity.ErrorResponse
+// @Failure 500 {object} entity.ErrorResponse
+// @Router /api/v1/attachments/{id}/thumbnail [get]
+func (h *AttachmentHandler) GetThumbnail(c *gin.Context) {
+	h.serve(c, true)
+}
+
+func (h *AttachmentHandler) serve(c *gin.Context, thumbnail bool) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		writeError(c, errInvalidAttachmentID)
+		return
+	}
+
+	// The token is optional: it only lets moderators see attachments of
+	// hidden and deleted posts.
+	token := strings.TrimPrefix(c.GetHeader("Authorization"), "Bearer ")
+	attachment, body, err := h.uc.Open(c.Request.Context(), token, id, thumbnail)
+	if err != nil {
+		logServerError(h.logger, "Failed to open attachment", err, "attachment_id", id)
+		writeError(c, err)
+		return
+	}
+	defer body.Close()
+
+	contentType, size, disposition := attachment.ContentType, attachment.Size, "attachment"
+	if thumbnail {
+		contentType, size = media.ThumbnailType, -1
+	}
+	if media.IsImage(contentType) {
+		disposition = "inline"
+	}
+
+	// Content never changes under an id, so it may be cached for good, but
+	// what a moderator may see must not end up in shared caches.
+	cacheControl := "public, max-age=31536000, immutable"
+	if token != "" {
+		cacheControl = "private, no-store"
+	}
+	c.DataFromReader(http.StatusOK, size, contentType, body, map[string]string{
+		"Content-Disposition":    mime.FormatMediaType(disposition, map[string]string{"filename": attachment.Filename}),
+		"Cache-Control":          cacheControl,
+		"X-Content-Type-Options": "nosniff",
+	})
+}
+
+// ListPostAttachments godoc
+// @Summary Файлы поста
+// @Tags attachments
+// @Produce json
+// @Param id path int true "ID поста"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} entity.ErrorResponse
+// @Failure 404 {object} entity.ErrorResponse
+// @Failure 500 {object} entity.ErrorResponse
+// @Router /api/v1/posts/{id}/attachments [get]
+func (h *AttachmentHandler) ListPostAttachments(c *gin.Context) {
+	postID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		writeError(c, errInvalidPostID)
+		return
+	}
+
+	attachments, err := h.uc.ListPostAttachments(c.Request.Context(), postID)
+	if err != nil {
+		logServerError(h.logger, "Failed to list attachments", err, "post_id", postID)
+		writeError(c, err)
+		return
+	}
+
+	for _, a := range attachments {
+		withURLs(a)
+	}
+	c.JSON(http.StatusOK, gin.H{"data": attachments})
+}
+
+// AttachToPost godoc
+// @Summary Прикрепить файлы к посту
+// @Description Прикрепляются только собственные загрузки, еще не прикрепленные к другому посту. Автор поста или администратор
+// @Tags attachments
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param Authorization header string true "Bearer токен"
+// @Param id path int true "ID поста"
+// @Param request body attachRequest true "ID загруженных файлов (не больше 20)"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} entity.ErrorResponse
+// @Failure 401 {object} entity.ErrorResponse
+// @Failure 403 {object} entity.ErrorResponse
+// @Failure 404 {object} entity.ErrorResponse
+// @Failure 500 {object} entity.ErrorResponse
+// @Router /api/v1/posts/{id}/attachments [post]
+func (h *AttachmentHandler) AttachToPost(c *gin.Context) {
+	token, ok := bearerToken(c)
+	if !ok {
+		return
+	}
+	postID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		writeError(c, errInvalidPostID)
+		return
+	}
+	var req attachRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		writeError(c, errInvalidBody)
+		return
+	}
+
+	attachments, err := h.uc.AttachToPost(c.Request.Context(), token, postID, req.AttachmentIDs)
+	if err != nil {
+		logServerError(h.logger, "Failed to attach files", err, "post_id", postID)
+		writeError(c, err)
+		return
+	}
+
+	for _, a := range attachments {
+		withURLs(a)
+	}
+	c.JSON(http.StatusOK, gin.H{"data": attachments})
+}
+
+// uploadError reports a request body cut off by the size limit as such,
+// and any other failure as fallback.
+func uploadError(err, fallback error) error {
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		return errRequestTooLarge
+	}
+	return fallback
+}
+
+// withURLs fills in where the content of an attachment is served.
+func withURLs(a *entity.Attachment) *entity.Attachment {
+	a.URL = fmt.Sprintf("/api/v1/attachments/%d", a.ID)
+	if a.HasThumbnail {
+		a.ThumbnailURL = a.URL + "/thumbnail"
+	}
+	return a
+}
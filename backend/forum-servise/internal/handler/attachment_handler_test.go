@@ -0,0 +1,207 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Ulyana-kru00/forum-project/forum-servise/internal/entity"
+	"github.com/Ulyana-kru00/forum-project/forum-servise/internal/repository"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type mockAttachmentUsecase struct {
+	mock.Mock
+}
+
+func (m *mockAttachmentUsecase) Upload(ctx context.Context, token, filename string, r io.Reader) (*entity.Attachment, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	args := m.Called(ctx, token, filename, string(data))
+	a, _ := args.Get(0).(*entity.Attachment)
+	return a, args.Error(1)
+}
+
+func (m *mockAttachmentUsecase) AttachToPost(ctx context.Context, token string, postID int64, ids []int64) ([]*entity.Attachment, error) {
+	args := m.Called(ctx, token, postID, ids)
+	a, _ := args.Get(0).([]*entity.Attachment)
+	return a, args.Error(1)
+}
+
+func (m *mockAttachmentUsecase) ListPostAttachments(ctx context.Context, postID int64) ([]*entity.Attachment, error) {
+	args := m.Called(ctx, postID)
+	a, _ := args.Get(0).([]*entity.Attachment)
+	return a, args.Error(1)
+}
+
+func (m *mockAttachmentUsecase) Open(ctx context.Context, token string, id int64, thumbnail bool) (*entity.Attachment, io.ReadCloser, error) {
+	args := m.Called(ctx, token, id, thumbnail)
+	a, _ := args.Get(0).(*entity.Attachment)
+	body, _ := args.Get(1).(io.ReadCloser)
+	return a, body, args.Error(2)
+}
+
+func newAttachmentRouter(uc *mockAttachmentUsecase) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	h := NewAttachmentHandler(uc, 1024, newTestLogger())
+
+	r := gin.New()
+	r.POST("/attachments", h.Upload)
+	r.GET("/attachments/:id", h.GetAttachment)
+	r.GET("/attachments/:id/thumbnail", h.GetThumbnail)
+	r.GET("/posts/:id/attachments", h.ListPostAttachments)
+	r.POST("/posts/:id/attachments", h.AttachToPost)
+	return r
+}
+
+func multipartBody(t *testing.T, field, filename, content string) (*bytes.Buffer, string) {
+	var buf bytes.Buffer
+	w := multipart.NewWriter(&buf)
+	part, err := w.CreateFormFile(field, filename)
+	assert.NoError(t, err)
+	part.Write([]byte(content))
+	w.Close()
+	return &buf, w.FormDataContentType()
+}
+
+func TestUploadAttachment(t *testing.T) {
+	mockUC := new(mockAttachmentUsecase)
+	mockUC.On("Upload", mock.Anything, "token", "cat.png", "png-bytes").
+		Return(&entity.Attachment{ID: 7, Filename: "cat.png", HasThumbnail: true}, nil)
+	r := newAttachmentRouter(mockUC)
+
+	tests := []struct {
+		name    string
+		field   string
+		content string
+		auth    string
+		status  int
+		body    string
+	}{
+		{"Success", "file", "png-bytes", "Bearer token", http.StatusCreated, `"thumbnail_url":"/api/v1/attachments/7/thumbnail"`},
+		{"Missing file field", "other", "png-bytes", "Bearer token", http.StatusBadRequest, `"code":"invalid_argument"`},
+		{"Request too large", "file", strings.Repeat("a", 128<<10), "Bearer token", http.StatusRequestEntityTooLarge, `"code":"too_large"`},
+		{"No auth header", "file", "png-bytes", "", http.StatusUnauthorized, `"code":"unauthenticated"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body, contentType := multipartBody(t, tt.field, "cat.png", tt.content)
+			req := httptest.NewRequest(http.MethodPost, "/attachments", body)
+			req.Header.Set("Content-Type", contentType)
+			if tt.auth != "" {
+				req.Header.Set("Authorization", tt.auth)
+			}
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+			assert.Equal(t, tt.status, w.Code)
+			assert.Contains(t, w.Body.String(), tt.body)
+		})
+	}
+	mockUC.AssertExpectations(t)
+}
+
+func TestGetAttachment(t *testing.T) {
+	mockUC := new(mockAttachmentUsecase)
+	mockUC.On("Open", mock.Anything, "", int64(7), false).
+		Return(&entity.Attachment{ID: 7, Filename: "report.pdf", ContentType: "application/pdf", Size: 4}, io.NopCloser(strings.NewReader("%PDF")), nil)
+	mockUC.On("Open", mock.Anything, "", int64(8), true).
+		Return(&entity.Attachment{ID: 8, Filename: "cat.png", ContentType: "image/png", Size: 100}, io.NopCloser(strings.NewReader("jpeg")), nil)
+	mockUC.On("Open", mock.Anything, "", int64(9), false).
+		Return(nil, nil, repository.ErrAttachmentNotFound)
+	r := newAttachmentRouter(mockUC)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/attachments/7", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "%PDF", w.Body.String())
+	assert.Equal(t, "application/pdf", w.Header().Get("Content-Type"))
+	assert.Equal(t, "attachment; filename=report.pdf", w.Header().Get("Content-Disposition"))
+	assert.Equal(t, "nosniff", w.Header().Get("X-Content-Type-Options"))
+	assert.Equal(t, "public, max-age=31536000, immutable", w.Header().Get("Cache-Control"))
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/attachments/8/thumbnail", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "image/jpeg", w.Header().Get("Content-Type"))
+	assert.Equal(t, "inline; filename=cat.png", w.Header().Get("Content-Disposition"))
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/attachments/9", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/attachments/x", nil))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockUC.AssertExpectations(t)
+}
+
+func TestGetAttachment_Moderator(t *testing.T) {
+	mockUC := new(mockAttachmentUsecase)
+	mockUC.On("Open", mock.Anything, "mod-token", int64(7), false).
+		Return(&entity.Attachment{ID: 7, Filename: "report.pdf", ContentType: "application/pdf", Size: 4}, io.NopCloser(strings.NewReader("%PDF")), nil)
+	r := newAttachmentRouter(mockUC)
+
+	req := httptest.NewRequest(http.MethodGet, "/attachments/7", nil)
+	req.Header.Set("Authorization", "Bearer mod-token")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "private, no-store", w.Header().Get("Cache-Control"), "hidden content must not be cached publicly")
+	mockUC.AssertExpectations(t)
+}
+
+func TestAttachToPost(t *testing.T) {
+	mockUC := new(mockAttachmentUsecase)
+	mockUC.On("AttachToPost", mock.Anything, "token", int64(1), []int64{7, 8}).
+		Return([]*entity.Attachment{{ID: 7}, {ID: 8}}, nil)
+	mockUC.On("AttachToPost", mock.Anything, "token", int64(1), []int64{9}).
+		Return(nil, repository.ErrAttachmentUnavailable)
+	r := newAttachmentRouter(mockUC)
+
+	tests := []struct {
+		name   string
+		body   string
+		status int
+	}{
+		{"Success", `{"attachment_ids":[7,8]}`, http.StatusOK},
+		{"Unavailable upload", `{"attachment_ids":[9]}`, http.StatusBadRequest},
+		{"Invalid body", `{"attachment_ids":"7"}`, http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/posts/1/attachments", strings.NewReader(tt.body))
+			req.Header.Set("Authorization", "Bearer token")
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+			assert.Equal(t, tt.status, w.Code)
+		})
+	}
+	mockUC.AssertExpectations(t)
+}
+
+func TestListPostAttachments(t *testing.T) {
+	mockUC := new(mockAttachmentUsecase)
+	mockUC.On("ListPostAttachments", mock.Anything, int64(1)).
+		Return([]*entity.Attachment{{ID: 7, Filename: "notes.txt"}}, nil)
+	r := newAttachmentRouter(mockUC)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/posts/1/attachments", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"url":"/api/v1/attachments/7"`)
+	assert.NotContains(t, w.Body.String(), "thumbnail_url")
+	mockUC.AssertExpectations(t)
+}
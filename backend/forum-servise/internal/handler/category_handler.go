@@ -0,0 +1,170 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"backend.com/forum/apperrors"
+	"github.com/Ulyana-kru00/forum-project/forum-servise/internal/entity"
+	"github.com/Ulyana-kru00/forum-project/forum-servise/internal/usecase"
+	"github.com/Ulyana-kru00/forum-project/forum-servise/pkg/logger"
+	"github.com/gin-gonic/gin"
+)
+
+var errInvalidCategoryID = apperrors.New(apperrors.ErrInvalidArgument, "invalid category id")
+
+type CategoryHandler struct {
+	uc     usecase.CategoryUsecaseInterface
+	logger *logger.Logger
+}
+
+func NewCategoryHandler(uc usecase.CategoryUsecaseInterface, logger *logger.Logger) *CategoryHandler {
+	return &CategoryHandler{
+		uc:     uc,
+		logger: logger,
+	}
+}
+
+type categoryRequest struct {
+	Name        string `json:"name" binding:"required" example:"Go"`
+	Slug        string `json:"slug" example:"go"`
+	Description string `json:"description" example:"Все о языке Go"`
+	Position    int    `json:"position" example:"1"`
+}
+
+// ListCategories godoc
+// @Summary Список категорий
+// @Description Возвращает категории в порядке position с количеством постов и временем последней активности
+// @Tags categories
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Failure 500 {object} entity.ErrorResponse
+// @Router /api/v1/categories [get]
+func (h *CategoryHandler) ListCategories(c *gin.Context) {
+	categories, err := h.uc.ListCategories(c.Request.Context())
+	if err != nil {
+		logServerError(h.logger, "Failed to list categories", err)
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": categories})
+}
+
+// GetCategory godoc
+// @Summary Получить категорию
+// @Description Возвращает категорию по slug
+// @Tags categories
+// @Produce json
+// @Param slug path string true "Category slug"
+// @Success 200 {object} entity.Category
+// @Failure 404 {object} entity.ErrorResponse
+// @Failure 500 {object} entity.ErrorResponse
+// @Router /api/v1/categories/{slug} [get]
+func (h *CategoryHandler) GetCategory(c *gin.Context) {
+	category, err := h.uc.GetCategoryBySlug(c.Request.Context(), c.Param("slug"))
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, category)
+}
+
+// CreateCategory godoc
+// @Summary Создать категорию
+// @Description Только для администраторов. Без slug он строится из латинских букв и цифр названия
+// @Tags categories
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param Authorization header string true "Bearer токен"
+// @Param request body categoryRequest true "Данные категории"
+// @Success 201 {object} entity.Category
+// @Failure 400 {object} entity.ErrorResponse
+// @Failure 401 {object} entity.ErrorResponse
+// @Failure 403 {object} entity.ErrorResponse
+// @Failure 409 {object} entity.ErrorResponse
+// @Failure 500 {object} entity.ErrorResponse
+// @Router /api/v1/categories [post]
+func (h *CategoryHandler) CreateCategory(c *gin.Context) {
+	token, request, ok := h.bindCategory(c)
+	if !ok {
+		return
+	}
+
+	category, err := h.uc.CreateCategory(c.Request.Context(), token, request.toEntity())
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, category)
+}
+
+// UpdateCategory godoc
+// @Summary Изменить категорию
+// @Description Только для администраторов. Пустой slug оставляет текущий
+// @Tags categories
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param Authorization header string true "Bearer токен"
+// @Param id path int true "Category ID"
+// @Param request body categoryRequest true "Данные категории"
+// @Success 200 {object} entity.Category
+// @Failure 400 {object} entity.ErrorResponse
+// @Failure 401 {object} entity.ErrorResponse
+// @Failure 403 {object} entity.ErrorResponse
+// @Failure 404 {object} entity.ErrorResponse
+// @Failure 409 {object} entity.ErrorResponse
+// @Failure 500 {object} entity.ErrorResponse
+// @Router /api/v1/categories/{id} [put]
+func (h *CategoryHandler) UpdateCategory(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		writeError(c, errInvalidCategoryID)
+		return
+	}
+
+	token, request, ok := h.bindCategory(c)
+	if !ok {
+		return
+	}
+
+	category := request.toEntity()
+	category.ID = id
+	category, err = h.uc.UpdateCategory(c.Request.Context(), token, category)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, category)
+}
+
+func (h *CategoryHandler) bindCategory(c *gin.Context) (string, *categoryRequest, bool) {
+	authHeader := c.GetHeader("Authorization")
+	if authHeader == "" {
+		writeError(c, errMissingAuthHeader)
+		return "", nil, false
+	}
+
+	var request categoryRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		writeError(c, errInvalidBody)
+		return "", nil, false
+	}
+
+	return strings.TrimPrefix(authHeader, "Bearer "), &request, true
+}
+
+func (r *categoryRequest) toEntity() *entity.Category {
+	return &entity.Category{
+		Name:        r.Name,
+		Slug:        r.Slug,
+		Description: r.Description,
+		Position:    r.Position,
+	}
+}
@@ -0,0 +1,154 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"backend.com/forum/apperrors"
+	"github.com/Ulyana-kru00/forum-project/forum-servise/internal/entity"
+	"github.com/Ulyana-kru00/forum-project/forum-servise/internal/repository"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type mockCategoryUsecase struct {
+	mock.Mock
+}
+
+func (m *mockCategoryUsecase) CreateCategory(ctx context.Context, token string, category *entity.Category) (*entity.Category, error) {
+	args := m.Called(ctx, token, category)
+	c, _ := args.Get(0).(*entity.Category)
+	return c, args.Error(1)
+}
+
+func (m *mockCategoryUsecase) UpdateCategory(ctx context.Context, token string, category *entity.Category) (*entity.Category, error) {
+	args := m.Called(ctx, token, category)
+	c, _ := args.Get(0).(*entity.Category)
+	return c, args.Error(1)
+}
+
+func (m *mockCategoryUsecase) GetCategoryByID(ctx context.Context, id int64) (*entity.Category, error) {
+	args := m.Called(ctx, id)
+	c, _ := args.Get(0).(*entity.Category)
+	return c, args.Error(1)
+}
+
+func (m *mockCategoryUsecase) GetCategoryBySlug(ctx context.Context, slug string) (*entity.Category, error) {
+	args := m.Called(ctx, slug)
+	c, _ := args.Get(0).(*entity.Category)
+	return c, args.Error(1)
+}
+
+func (m *mockCategoryUsecase) ListCategories(ctx context.Context) ([]*entity.Category, error) {
+	args := m.Called(ctx)
+	c, _ := args.Get(0).([]*entity.Category)
+	return c, args.Error(1)
+}
+
+func newCategoryRouter(uc *mockCategoryUsecase) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	h := NewCategoryHandler(uc, newTestLogger())
+
+	r := gin.New()
+	r.GET("/categories", h.ListCategories)
+	r.GET("/categories/:slug", h.GetCategory)
+	r.POST("/categories", h.CreateCategory)
+	r.PUT("/categories/:id", h.UpdateCategory)
+	return r
+}
+
+func TestListCategories(t *testing.T) {
+	mockUC := new(mockCategoryUsecase)
+	mockUC.On("ListCategories", mock.Anything).
+		Return([]*entity.Category{{ID: 1, Name: "Общее", Slug: "general", PostCount: 3}}, nil)
+
+	w := httptest.NewRecorder()
+	newCategoryRouter(mockUC).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/categories", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"slug":"general"`)
+	assert.Contains(t, w.Body.String(), `"post_count":3`)
+}
+
+func TestGetCategory_NotFound(t *testing.T) {
+	mockUC := new(mockCategoryUsecase)
+	mockUC.On("GetCategoryBySlug", mock.Anything, "missing").Return(nil, repository.ErrCategoryNotFound)
+
+	w := httptest.NewRecorder()
+	newCategoryRouter(mockUC).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/categories/missing", nil))
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestCreateCategory(t *testing.T) {
+	mockUC := new(mockCategoryUsecase)
+	want := &entity.Category{Name: "Go", Slug: "go", Description: "About Go", Position: 1}
+	mockUC.On("CreateCategory", mock.Anything, "admin-token", want).
+		Return(&entity.Category{ID: 2, Name: "Go", Slug: "go"}, nil)
+
+	req := httptest.NewRequest(http.MethodPost, "/categories",
+		bytes.NewBufferString(`{"name":"Go","slug":"go","description":"About Go","position":1}`))
+	req.Header.Set("Authorization", "Bearer admin-token")
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	newCategoryRouter(mockUC).ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	mockUC.AssertExpectations(t)
+}
+
+func TestCreateCategory_Errors(t *testing.T) {
+	mockUC := new(mockCategoryUsecase)
+	mockUC.On("CreateCategory", mock.Anything, "user-token", mock.Anything).
+		Return(nil, apperrors.New(apperrors.ErrPermissionDenied, "only admins can manage categories"))
+	r := newCategoryRouter(mockUC)
+
+	tests := []struct {
+		name   string
+		auth   string
+		body   string
+		status int
+	}{
+		{"No auth header", "", `{"name":"Go"}`, http.StatusUnauthorized},
+		{"Missing name", "Bearer admin-token", `{"slug":"go"}`, http.StatusBadRequest},
+		{"Not admin", "Bearer user-token", `{"name":"Go"}`, http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/categories", bytes.NewBufferString(tt.body))
+			if tt.auth != "" {
+				req.Header.Set("Authorization", tt.auth)
+			}
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.status, w.Code)
+		})
+	}
+}
+
+func TestUpdateCategory(t *testing.T) {
+	mockUC := new(mockCategoryUsecase)
+	mockUC.On("UpdateCategory", mock.Anything, "admin-token", &entity.Category{ID: 3, Name: "Go"}).
+		Return(&entity.Category{ID: 3, Name: "Go", Slug: "go"}, nil)
+	r := newCategoryRouter(mockUC)
+
+	req := httptest.NewRequest(http.MethodPut, "/categories/3", bytes.NewBufferString(`{"name":"Go"}`))
+	req.Header.Set("Authorization", "Bearer admin-token")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	req = httptest.NewRequest(http.MethodPut, "/categories/abc", bytes.NewBufferString(`{"name":"Go"}`))
+	req.Header.Set("Authorization", "Bearer admin-token")
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	mockUC.AssertExpectations(t)
+}
@@ -7,7 +7,6 @@ import (
 	"strconv"
 	"strings"
 
-	pb "backend.com/forum/proto"
 	"github.com/Ulyana-kru00/forum-project/forum-servise/internal/entity"
 	"github.com/Ulyana-kru00/forum-project/forum-servise/internal/usecase"
 	"github.com/gin-gonic/gin"
@@ -30,102 +29,250 @@ func NewCommentHandler(commentUC *usecase.CommentUseCase) *CommentHandler {
 // @Security ApiKeyAuth
 // @Param Authorization header string true "Bearer token"
 // @Param id path int true "Post ID"
-// @Param request body entity.Comment true "Comment data"
+// @Param request body entity.Comment true "Comment data; parent_id makes it a reply"
 // @Success 201 {object} entity.Comment
 // @Failure 400 {object} entity.ErrorResponse
 // @Failure 401 {object} entity.ErrorResponse
+// @Failure 404 {object} entity.ErrorResponse
 // @Failure 500 {object} entity.ErrorResponse
 // @Router /api/v1/posts/{id}/comments [post]
 func (h *CommentHandler) CreateComment(c *gin.Context) {
 	authHeader := c.GetHeader("Authorization")
 	if authHeader == "" {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Authorization header is required"})
+		writeError(c, errMissingAuthHeader)
 		return
 	}
 	token := strings.TrimPrefix(authHeader, "Bearer ")
 
-	authResponse, err := h.commentUC.AuthClient.ValidateToken(c.Request.Context(), &pb.ValidateTokenRequest{
-		Token: token,
-	})
-	if err != nil || authResponse == nil || !authResponse.Valid {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token"})
-		return
-	}
-
 	postID, err := strconv.ParseInt(c.Param("id"), 10, 64)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid post id"})
+		writeError(c, errInvalidPostID)
 		return
 	}
 
 	var request struct {
-		Content string `json:"content" binding:"required"`
+		Content  string `json:"content" binding:"required"`
+		ParentID *int64 `json:"parent_id"`
 	}
 	if err := c.ShouldBindJSON(&request); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		writeError(c, errInvalidBody)
 		return
 	}
 
-	userResponse, err := h.commentUC.AuthClient.GetUser(c.Request.Context(), &pb.GetUserRequest{
-		Id: authResponse.UserId,
-	})
-
 	comment := entity.Comment{
-		Content:    request.Content,
-		AuthorID:   authResponse.UserId,
-		AuthorName: "Unknown",
-		PostID:     postID,
-	}
-
-	if err == nil && userResponse != nil && userResponse.User != nil {
-		comment.AuthorName = userResponse.User.Username
+		Content:  request.Content,
+		PostID:   postID,
+		ParentID: request.ParentID,
 	}
 
-	if err := h.commentUC.CreateComment(c.Request.Context(), &comment); err != nil {
-		log.Printf("Error creating comment: %v", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create comment"})
+	if err := h.commentUC.CreateComment(c.Request.Context(), token, &comment); err != nil {
+		if isServerError(err) {
+			log.Printf("Error creating comment: %v", err)
+		}
+		writeError(c, err)
 		return
 	}
 
 	c.JSON(http.StatusCreated, gin.H{
-		"id":          comment.ID,
-		"content":     comment.Content,
-		"author_id":   comment.AuthorID,
-		"post_id":     comment.PostID,
-		"author_name": comment.AuthorName,
+		"id":           comment.ID,
+		"content":      comment.Content,
+		"content_html": comment.ContentHTML,
+		"author_id":    comment.AuthorID,
+		"post_id":      comment.PostID,
+		"parent_id":    comment.ParentID,
+		"author_name":  comment.AuthorName,
 	})
 }
 
 // GetCommentsByPostID godoc
 // @Summary Get comments for a post
-// @Description Get all comments for a specific post
+// @Description Get a page of the comment thread of a post as a flattened tree with depth and path. With sort=newest top-level comments are newest first and replies oldest first; with sort=top every level is ordered by score. Each comment carries up to "replies" of its replies
 // @Tags comments
 // @Accept json
 // @Produce json
 // @Param id path int true "Post ID"
-// @Success 200 {object} []entity.Comment
+// @Param limit query int false "Top-level comments per page (default 20, max 100)"
+// @Param replies query int false "Replies shown per comment (default 3, max 20)"
+// @Param sort query string false "Sort order" Enums(newest, top) default(newest)
+// @Param cursor query string false "next_cursor of the previous page"
+// @Success 200 {object} entity.CommentPage
 // @Failure 400 {object} entity.ErrorResponse
+// @Failure 404 {object} entity.ErrorResponse
 // @Failure 500 {object} entity.ErrorResponse
 // @Router /api/v1/posts/{id}/comments [get]
 func (h *CommentHandler) GetCommentsByPostID(c *gin.Context) {
 	postID, err := strconv.ParseInt(c.Param("id"), 10, 64)
 	if err != nil {
 		log.Printf("Invalid post ID: %v", err)
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid post id"})
+		writeError(c, errInvalidPostID)
+		return
+	}
+
+	h.writeCommentTree(c, entity.CommentTreeFilter{PostID: postID})
+}
+
+// GetCommentReplies godoc
+// @Summary Load more replies
+// @Description Get a page of the replies to a comment, each with its own first replies
+// @Tags comments
+// @Accept json
+// @Produce json
+// @Param id path int true "Post ID"
+// @Param commentId path int true "Comment ID"
+// @Param limit query int false "Direct replies per page (default 20, max 100)"
+// @Param replies query int false "Nested replies shown per reply (default 3, max 20)"
+// @Param sort query string false "Sort order" Enums(newest, top) default(newest)
+// @Param cursor query string false "next_cursor of the previous page"
+// @Success 200 {object} entity.CommentPage
+// @Failure 400 {object} entity.ErrorResponse
+// @Failure 404 {object} entity.ErrorResponse
+// @Failure 500 {object} entity.ErrorResponse
+// @Router /api/v1/posts/{id}/comments/{commentId}/replies [get]
+func (h *CommentHandler) GetCommentReplies(c *gin.Context) {
+	postID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		writeError(c, errInvalidPostID)
+		return
+	}
+	commentID, err := strconv.ParseInt(c.Param("commentId"), 10, 64)
+	if err != nil || commentID <= 0 {
+		writeError(c, errInvalidCommentID)
 		return
 	}
 
-	comments, err := h.commentUC.GetCommentsByPostID(c.Request.Context(), postID)
+	h.writeCommentTree(c, entity.CommentTreeFilter{PostID: postID, ParentID: commentID})
+}
+
+func (h *CommentHandler) writeCommentTree(c *gin.Context, filter entity.CommentTreeFilter) {
+	filter.Cursor = c.Query("cursor")
+	filter.Sort = entity.CommentSort(c.Query("sort"))
+
+	var err error
+	if v := c.Query("limit"); v != "" {
+		if filter.Limit, err = strconv.Atoi(v); err != nil || filter.Limit <= 0 {
+			writeError(c, invalidQueryParam("limit"))
+			return
+		}
+	}
+	if v := c.Query("replies"); v != "" {
+		if filter.RepliesLimit, err = strconv.Atoi(v); err != nil || filter.RepliesLimit <= 0 {
+			writeError(c, invalidQueryParam("replies"))
+			return
+		}
+	}
+
+	page, err := h.commentUC.GetCommentTree(c.Request.Context(), filter)
 	if err != nil {
-		log.Printf("Error getting comments: %v", err)
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":   "failed to get comments",
-			"details": err.Error(),
-		})
+		if isServerError(err) {
+			log.Printf("Error getting comments: %v", err)
+		}
+		writeError(c, err)
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"comments": comments,
+		"comments":    page.Comments,
+		"next_cursor": page.NextCursor,
 	})
 }
+
+// UpdateComment godoc
+// @Summary Edit a comment
+// @Description Change the content of a comment. Allowed for its author or an admin
+// @Tags comments
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param Authorization header string true "Bearer token"
+// @Param id path int true "Post ID"
+// @Param commentId path int true "Comment ID"
+// @Param request body object true "Comment data"
+// @Success 200 {object} entity.Comment
+// @Failure 400 {object} entity.ErrorResponse
+// @Failure 401 {object} entity.ErrorResponse
+// @Failure 403 {object} entity.ErrorResponse
+// @Failure 404 {object} entity.ErrorResponse
+// @Failure 500 {object} entity.ErrorResponse
+// @Router /api/v1/posts/{id}/comments/{commentId} [put]
+func (h *CommentHandler) UpdateComment(c *gin.Context) {
+	token, postID, commentID, ok := commentRequestParams(c)
+	if !ok {
+		return
+	}
+
+	var request struct {
+		Content string `json:"content" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		writeError(c, errInvalidBody)
+		return
+	}
+
+	comment, err := h.commentUC.UpdateComment(c.Request.Context(), token, postID, commentID, request.Content)
+	if err != nil {
+		if isServerError(err) {
+			log.Printf("Error updating comment: %v", err)
+		}
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, comment)
+}
+
+// DeleteComment godoc
+// @Summary Delete a comment
+// @Description Delete a comment. Allowed for its author or an admin. A comment with replies is kept as a "[deleted]" placeholder
+// @Tags comments
+// @Produce json
+// @Security ApiKeyAuth
+// @Param Authorization header string true "Bearer token"
+// @Param id path int true "Post ID"
+// @Param commentId path int true "Comment ID"
+// @Success 204
+// @Failure 400 {object} entity.ErrorResponse
+// @Failure 401 {object} entity.ErrorResponse
+// @Failure 403 {object} entity.ErrorResponse
+// @Failure 404 {object} entity.ErrorResponse
+// @Failure 500 {object} entity.ErrorResponse
+// @Router /api/v1/posts/{id}/comments/{commentId} [delete]
+func (h *CommentHandler) DeleteComment(c *gin.Context) {
+	token, postID, commentID, ok := commentRequestParams(c)
+	if !ok {
+		return
+	}
+
+	if err := h.commentUC.DeleteComment(c.Request.Context(), token, postID, commentID); err != nil {
+		if isServerError(err) {
+			log.Printf("Error deleting comment: %v", err)
+		}
+		writeError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// commentRequestParams reads the bearer token and the post and comment ids
+// of a request addressing a single comment. It writes the error response
+// itself and reports whether the request may proceed.
+func commentRequestParams(c *gin.Context) (token string, postID, commentID int64, ok bool) {
+	authHeader := c.GetHeader("Authorization")
+	if authHeader == "" {
+		writeError(c, errMissingAuthHeader)
+		return "", 0, 0, false
+	}
+
+	postID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		writeError(c, errInvalidPostID)
+		return "", 0, 0, false
+	}
+	commentID, err = strconv.ParseInt(c.Param("commentId"), 10, 64)
+	if err != nil {
+		writeError(c, errInvalidCommentID)
+		return "", 0, 0, false
+	}
+
+	return strings.TrimPrefix(authHeader, "Bearer "), postID, commentID, true
+}
@@ -9,7 +9,9 @@ import (
 
 	pb "backend.com/forum/proto"
 	"github.com/Ulyana-kru00/forum-project/forum-servise/internal/entity"
+	"github.com/Ulyana-kru00/forum-project/forum-servise/internal/repository"
 	"github.com/Ulyana-kru00/forum-project/forum-servise/internal/usecase"
+	"github.com/Ulyana-kru00/forum-project/forum-servise/pkg/verifier"
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -40,6 +42,26 @@ func (m *MockAuthClient) Register(ctx context.Context, in *pb.RegisterRequest, o
 	return args.Get(0).(*pb.RegisterResponse), args.Error(1)
 }
 
+func (m *MockAuthClient) Logout(ctx context.Context, in *pb.LogoutRequest, opts ...grpc.CallOption) (*pb.LogoutResponse, error) {
+	args := m.Called(ctx, in, opts)
+	return args.Get(0).(*pb.LogoutResponse), args.Error(1)
+}
+
+func (m *MockAuthClient) LogoutAll(ctx context.Context, in *pb.LogoutAllRequest, opts ...grpc.CallOption) (*pb.LogoutAllResponse, error) {
+	args := m.Called(ctx, in, opts)
+	return args.Get(0).(*pb.LogoutAllResponse), args.Error(1)
+}
+
+func (m *MockAuthClient) Refresh(ctx context.Context, in *pb.RefreshRequest, opts ...grpc.CallOption) (*pb.LoginResponse, error) {
+	args := m.Called(ctx, in, opts)
+	return args.Get(0).(*pb.LoginResponse), args.Error(1)
+}
+
+func (m *MockAuthClient) GetUsers(ctx context.Context, in *pb.GetUsersRequest, opts ...grpc.CallOption) (*pb.GetUsersResponse, error) {
+	args := m.Called(ctx, in, opts)
+	return args.Get(0).(*pb.GetUsersResponse), args.Error(1)
+}
+
 type MockCommentRepository struct {
 	mock.Mock
 }
@@ -54,16 +76,36 @@ func (m *MockCommentRepository) GetCommentsByPostID(ctx context.Context, postID
 	return args.Get(0).([]entity.Comment), args.Error(1)
 }
 
+func (m *MockCommentRepository) GetCommentByID(ctx context.Context, id int64) (*entity.Comment, error) {
+	args := m.Called(ctx, id)
+	c, _ := args.Get(0).(*entity.Comment)
+	return c, args.Error(1)
+}
+
+func (m *MockCommentRepository) GetCommentTree(ctx context.Context, filter entity.CommentTreeFilter) (*entity.CommentPage, error) {
+	args := m.Called(ctx, filter)
+	p, _ := args.Get(0).(*entity.CommentPage)
+	return p, args.Error(1)
+}
+
+func (m *MockCommentRepository) UpdateComment(ctx context.Context, id, postID, authorID int64, role, content, contentHTML string) (*entity.Comment, error) {
+	args := m.Called(ctx, id, postID, authorID, role, content, contentHTML)
+	c, _ := args.Get(0).(*entity.Comment)
+	return c, args.Error(1)
+}
+
+func (m *MockCommentRepository) DeleteComment(ctx context.Context, id, postID, authorID int64, role string) error {
+	args := m.Called(ctx, id, postID, authorID, role)
+	return args.Error(0)
+}
+
 func TestCreateComment_Success(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
 	authClient := new(MockAuthClient)
 	commentRepo := new(MockCommentRepository)
 
-	uc := &usecase.CommentUseCase{
-		AuthClient:  authClient,
-		CommentRepo: commentRepo,
-	}
+	uc := usecase.NewCommentUseCase(commentRepo, nil, authClient, verifier.NewRemote(authClient))
 
 	handler := NewCommentHandler(uc)
 	router := gin.Default()
@@ -76,10 +118,11 @@ func TestCreateComment_Success(t *testing.T) {
 		Return(&pb.GetUserResponse{User: &pb.User{Username: "alice"}}, nil)
 
 	expectedComment := &entity.Comment{
-		Content:    "test comment",
-		AuthorID:   42,
-		PostID:     1,
-		AuthorName: "alice",
+		Content:     "test comment",
+		ContentHTML: "<p>test comment</p>\n",
+		AuthorID:    42,
+		PostID:      1,
+		AuthorName:  "alice",
 	}
 	commentRepo.On("CreateComment", mock.Anything, expectedComment).Return(nil)
 
@@ -95,3 +138,118 @@ func TestCreateComment_Success(t *testing.T) {
 	authClient.AssertExpectations(t)
 	commentRepo.AssertExpectations(t)
 }
+
+// mockPostRepository serves the post lookups made by CommentUseCase.
+type mockPostRepository struct {
+	repository.PostRepository
+	mock.Mock
+}
+
+func (m *mockPostRepository) GetPostByID(ctx context.Context, id int64) (*entity.Post, error) {
+	args := m.Called(ctx, id)
+	p, _ := args.Get(0).(*entity.Post)
+	return p, args.Error(1)
+}
+
+func newCommentRouter(commentRepo *MockCommentRepository, postRepo *mockPostRepository, role string) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	authClient := new(MockAuthClient)
+	authClient.On("ValidateToken", mock.Anything, mock.Anything, mock.Anything).
+		Return(&pb.ValidateTokenResponse{Valid: true, UserId: 42, Role: role}, nil)
+	authClient.On("GetUsers", mock.Anything, mock.Anything, mock.Anything).
+		Return(&pb.GetUsersResponse{}, nil)
+
+	handler := NewCommentHandler(usecase.NewCommentUseCase(commentRepo, postRepo, authClient, verifier.NewRemote(authClient)))
+	router := gin.New()
+	router.GET("/posts/:id/comments/:commentId/replies", handler.GetCommentReplies)
+	router.PUT("/posts/:id/comments/:commentId", handler.UpdateComment)
+	router.DELETE("/posts/:id/comments/:commentId", handler.DeleteComment)
+	return router
+}
+
+func TestUpdateComment(t *testing.T) {
+	commentRepo := new(MockCommentRepository)
+	commentRepo.On("UpdateComment", mock.Anything, int64(5), int64(1), int64(42), "user", "edited", "<p>edited</p>\n").
+		Return(&entity.Comment{ID: 5, PostID: 1, AuthorID: 42, Content: "edited"}, nil)
+
+	req := httptest.NewRequest(http.MethodPut, "/posts/1/comments/5", bytes.NewBufferString(`{"content":"edited"}`))
+	req.Header.Set("Authorization", "Bearer valid-token")
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	newCommentRouter(commentRepo, new(mockPostRepository), "user").ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"content":"edited"`)
+	commentRepo.AssertExpectations(t)
+}
+
+func TestDeleteComment(t *testing.T) {
+	tests := []struct {
+		name     string
+		path     string
+		auth     string
+		repoErr  error
+		callRepo bool
+		expCode  int
+	}{
+		{"Success", "/posts/1/comments/5", "Bearer valid-token", nil, true, http.StatusNoContent},
+		{"Not owner", "/posts/1/comments/5", "Bearer valid-token", repository.ErrPermissionDenied, true, http.StatusForbidden},
+		{"Not found", "/posts/1/comments/5", "Bearer valid-token", repository.ErrCommentNotFound, true, http.StatusNotFound},
+		{"Invalid comment id", "/posts/1/comments/abc", "Bearer valid-token", nil, false, http.StatusBadRequest},
+		{"Missing auth", "/posts/1/comments/5", "", nil, false, http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			commentRepo := new(MockCommentRepository)
+			if tt.callRepo {
+				commentRepo.On("DeleteComment", mock.Anything, int64(5), int64(1), int64(42), "user").Return(tt.repoErr)
+			}
+
+			req := httptest.NewRequest(http.MethodDelete, tt.path, nil)
+			if tt.auth != "" {
+				req.Header.Set("Authorization", tt.auth)
+			}
+			w := httptest.NewRecorder()
+			newCommentRouter(commentRepo, new(mockPostRepository), "user").ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expCode, w.Code)
+			commentRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestGetCommentReplies(t *testing.T) {
+	parentID := int64(5)
+	commentRepo := new(MockCommentRepository)
+	commentRepo.On("GetCommentByID", mock.Anything, int64(5)).
+		Return(&entity.Comment{ID: 5, PostID: 1}, nil)
+	commentRepo.On("GetCommentTree", mock.Anything, entity.CommentTreeFilter{
+		PostID: 1, ParentID: 5, Limit: 2, RepliesLimit: 3, Sort: entity.CommentSortNewest, Cursor: "6",
+	}).Return(&entity.CommentPage{
+		Comments:   []entity.Comment{{ID: 7, PostID: 1, ParentID: &parentID, Depth: 1, Path: "5.7"}},
+		NextCursor: "7",
+	}, nil)
+
+	postRepo := new(mockPostRepository)
+	postRepo.On("GetPostByID", mock.Anything, int64(1)).Return(&entity.Post{ID: 1}, nil)
+
+	router := newCommentRouter(commentRepo, postRepo, "user")
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/posts/1/comments/5/replies?limit=2&cursor=6", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"path":"5.7"`)
+	assert.Contains(t, w.Body.String(), `"next_cursor":"7"`)
+	commentRepo.AssertExpectations(t)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/posts/1/comments/5/replies?replies=-1", nil))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/posts/1/comments/5/replies?sort=best", nil))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
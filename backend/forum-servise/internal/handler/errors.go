@@ -0,0 +1,54 @@
+// internal/handler/errors.go
+package handler
+
+import (
+	"net/http"
+
+	"backend.com/forum/apperrors"
+	"github.com/Ulyana-kru00/forum-project/forum-servise/internal/entity"
+	"github.com/Ulyana-kru00/forum-project/forum-servise/pkg/logger"
+	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc/status"
+)
+
+var (
+	errMissingAuthHeader = apperrors.New(apperrors.ErrUnauthenticated, "Authorization header is required")
+	errInvalidBody       = apperrors.New(apperrors.ErrInvalidArgument, "Invalid request body")
+	errInvalidPostID     = apperrors.New(apperrors.ErrInvalidArgument, "invalid post id")
+	errInvalidCommentID  = apperrors.New(apperrors.ErrInvalidArgument, "invalid comment id")
+
+	errMissingAuthMetadata = apperrors.New(apperrors.ErrUnauthenticated, "authorization metadata is required")
+	errCategoryKeyRequired = apperrors.New(apperrors.ErrInvalidArgument, "category id or slug is required")
+)
+
+func invalidQueryParam(name string) error {
+	return apperrors.New(apperrors.ErrInvalidArgument, "invalid query parameter: "+name)
+}
+
+// writeError renders err as the JSON error envelope with the HTTP status
+// of its kind.
+func writeError(c *gin.Context, err error) {
+	c.JSON(apperrors.HTTPStatus(err), entity.ErrorResponse{
+		Error: apperrors.Message(err),
+		Code:  apperrors.Code(err),
+	})
+}
+
+// isServerError reports whether err renders as a 5xx response. Client
+// errors such as 400, 403 and 404 are part of normal traffic and not logged.
+func isServerError(err error) bool {
+	return apperrors.HTTPStatus(err) >= http.StatusInternalServerError
+}
+
+// logServerError logs err at Error level when it is a server error.
+func logServerError(l *logger.Logger, msg string, err error, keysAndValues ...interface{}) {
+	if !isServerError(err) {
+		return
+	}
+	l.Errorw(msg, append(keysAndValues, "error", err)...)
+}
+
+// grpcError converts err into a gRPC status with the code of its kind.
+func grpcError(err error) error {
+	return status.Error(apperrors.GRPCCode(err), apperrors.Message(err))
+}
@@ -0,0 +1,44 @@
+package handler
+
+import (
+	"errors"
+	"testing"
+
+	"backend.com/forum/apperrors"
+	"github.com/Ulyana-kru00/forum-project/forum-servise/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestLogServerError(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		logged bool
+	}{
+		{"Internal", errors.New("db down"), true},
+		{"Not found", apperrors.New(apperrors.ErrNotFound, "post not found"), false},
+		{"Permission denied", apperrors.New(apperrors.ErrPermissionDenied, "permission denied"), false},
+		{"Invalid argument", errInvalidBody, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			core, logs := observer.New(zap.ErrorLevel)
+			l := &logger.Logger{SugaredLogger: zap.New(core).Sugar()}
+
+			logServerError(l, "Failed", tt.err, "post_id", int64(1))
+
+			if !tt.logged {
+				assert.Zero(t, logs.Len())
+				return
+			}
+			if assert.Equal(t, 1, logs.Len()) {
+				fields := logs.All()[0].ContextMap()
+				assert.Equal(t, int64(1), fields["post_id"])
+				assert.Equal(t, "db down", fields["error"])
+			}
+		})
+	}
+}
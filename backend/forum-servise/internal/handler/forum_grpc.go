@@ -0,0 +1,241 @@
+// internal/handler/forum_grpc.go
+package handler
+
+import (
+	"context"
+	"strings"
+
+	pb "backend.com/forum/proto"
+	"github.com/Ulyana-kru00/forum-project/forum-servise/internal/entity"
+	"github.com/Ulyana-kru00/forum-project/forum-servise/internal/usecase"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// ForumServer serves the post and category RPCs of ForumService on top of
+// the same usecases as the HTTP handlers. Message and chat RPCs are left
+// unimplemented.
+type ForumServer struct {
+	postUC     usecase.PostUsecaseInterface
+	categoryUC usecase.CategoryUsecaseInterface
+	pb.UnimplementedForumServiceServer
+}
+
+func NewForumServer(postUC usecase.PostUsecaseInterface, categoryUC usecase.CategoryUsecaseInterface) *ForumServer {
+	return &ForumServer{
+		postUC:     postUC,
+		categoryUC: categoryUC,
+	}
+}
+
+func (s *ForumServer) CreatePost(ctx context.Context, req *pb.CreatePostRequest) (*pb.CreatePostResponse, error) {
+	if req == nil {
+		return nil, status.Error(codes.InvalidArgument, "request cannot be nil")
+	}
+	token, err := tokenFromMetadata(ctx)
+	if err != nil {
+		return nil, grpcError(err)
+	}
+
+	post, err := s.postUC.CreatePost(ctx, token, req.Title, req.Content, req.CategoryId, req.Tags)
+	if err != nil {
+		return nil, grpcError(err)
+	}
+
+	return &pb.CreatePostResponse{Id: post.ID, Post: postToProto(post, "")}, nil
+}
+
+func (s *ForumServer) GetPosts(ctx context.Context, req *pb.GetPostsRequest) (*pb.GetPostsResponse, error) {
+	if req == nil {
+		return nil, status.Error(codes.InvalidArgument, "request cannot be nil")
+	}
+
+	filter, err := postFilterFromProto(req)
+	if err != nil {
+		return nil, grpcError(err)
+	}
+
+	page, authorNames, err := s.postUC.GetPosts(ctx, filter)
+	if err != nil {
+		return nil, grpcError(err)
+	}
+
+	posts := make([]*pb.Post, 0, len(page.Posts))
+	for _, post := range page.Posts {
+		posts = append(posts, postToProto(post, authorNames[int(post.AuthorID)]))
+	}
+
+	return &pb.GetPostsResponse{Posts: posts, NextCursor: page.NextCursor}, nil
+}
+
+func (s *ForumServer) CreateCategory(ctx context.Context, req *pb.CreateCategoryRequest) (*pb.CreateCategoryResponse, error) {
+	if req == nil {
+		return nil, status.Error(codes.InvalidArgument, "request cannot be nil")
+	}
+	token, err := tokenFromMetadata(ctx)
+	if err != nil {
+		return nil, grpcError(err)
+	}
+
+	category, err := s.categoryUC.CreateCategory(ctx, token, &entity.Category{
+		Name:        req.Name,
+		Slug:        req.Slug,
+		Description: req.Description,
+		Position:    int(req.Position),
+	})
+	if err != nil {
+		return nil, grpcError(err)
+	}
+
+	return &pb.CreateCategoryResponse{Id: category.ID, Category: categoryToProto(category)}, nil
+}
+
+// GetCategory looks the category up by id, or by slug when id is not set.
+func (s *ForumServer) GetCategory(ctx context.Context, req *pb.GetCategoryRequest) (*pb.GetCategoryResponse, error) {
+	if req == nil {
+		return nil, status.Error(codes.InvalidArgument, "request cannot be nil")
+	}
+
+	var (
+		category *entity.Category
+		err      error
+	)
+	switch {
+	case req.Id > 0:
+		category, err = s.categoryUC.GetCategoryByID(ctx, req.Id)
+	case req.Slug != "":
+		category, err = s.categoryUC.GetCategoryBySlug(ctx, req.Slug)
+	default:
+		err = errCategoryKeyRequired
+	}
+	if err != nil {
+		return nil, grpcError(err)
+	}
+
+	return &pb.GetCategoryResponse{Category: categoryToProto(category)}, nil
+}
+
+func (s *ForumServer) UpdateCategory(ctx context.Context, req *pb.UpdateCategoryRequest) (*pb.UpdateCategoryResponse, error) {
+	if req == nil {
+		return nil, status.Error(codes.InvalidArgument, "request cannot be nil")
+	}
+	if req.Id <= 0 {
+		return nil, grpcError(errInvalidCategoryID)
+	}
+	token, err := tokenFromMetadata(ctx)
+	if err != nil {
+		return nil, grpcError(err)
+	}
+
+	category, err := s.categoryUC.UpdateCategory(ctx, token, &entity.Category{
+		ID:          req.Id,
+		Name:        req.Name,
+		Slug:        req.Slug,
+		Description: req.Description,
+		Position:    int(req.Position),
+	})
+	if err != nil {
+		return nil, grpcError(err)
+	}
+
+	return &pb.UpdateCategoryResponse{Category: categoryToProto(category)}, nil
+}
+
+func (s *ForumServer) ListCategories(ctx context.Context, req *pb.ListCategoriesRequest) (*pb.ListCategoriesResponse, error) {
+	categories, err := s.categoryUC.ListCategories(ctx)
+	if err != nil {
+		return nil, grpcError(err)
+	}
+
+	resp := &pb.ListCategoriesResponse{Categories: make([]*pb.Category, 0, len(categories))}
+	for _, category := range categories {
+		resp.Categories = append(resp.Categories, categoryToProto(category))
+	}
+	return resp, nil
+}
+
+// tokenFromMetadata extracts the bearer token from the "authorization"
+// metadata key, mirroring the Authorization header of the HTTP API.
+func tokenFromMetadata(ctx context.Context) (string, error) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return "", errMissingAuthMetadata
+	}
+	values := md.Get("authorization")
+	if len(values) == 0 || values[0] == "" {
+		return "", errMissingAuthMetadata
+	}
+	return strings.TrimPrefix(values[0], "Bearer "), nil
+}
+
+func postFilterFromProto(req *pb.GetPostsRequest) (entity.PostFilter, error) {
+	filter := entity.PostFilter{
+		Cursor:   req.Cursor,
+		Query:    strings.TrimSpace(req.Query),
+		Sort:     entity.PostSort(req.Sort),
+		Window:   entity.TopWindow(req.Window),
+		Tags:     req.Tags,
+		TagMatch: entity.TagMatch(req.TagMatch),
+		Limit:    int(req.Limit),
+	}
+
+	if req.Limit < 0 {
+		return filter, invalidQueryParam("limit")
+	}
+	if req.AuthorId < 0 {
+		return filter, invalidQueryParam("author_id")
+	}
+	filter.AuthorID = req.AuthorId
+	if req.CategoryId < 0 {
+		return filter, invalidQueryParam("category_id")
+	}
+	filter.CategoryID = req.CategoryId
+	if req.From != nil {
+		if err := req.From.CheckValid(); err != nil {
+			return filter, invalidQueryParam("from")
+		}
+		filter.From = req.From.AsTime()
+	}
+	if req.To != nil {
+		if err := req.To.CheckValid(); err != nil {
+			return filter, invalidQueryParam("to")
+		}
+		filter.To = req.To.AsTime()
+	}
+	return filter, nil
+}
+
+func postToProto(post *entity.Post, authorName string) *pb.Post {
+	return &pb.Post{
+		Id:             post.ID,
+		Title:          post.Title,
+		Content:        post.Content,
+		ContentHtml:    post.ContentHTML,
+		AuthorId:       post.AuthorID,
+		AuthorName:     authorName,
+		CategoryId:     post.CategoryID,
+		CreatedAt:      timestamppb.New(post.CreatedAt),
+		CommentCount:   post.CommentCount,
+		LastActivityAt: timestamppb.New(post.LastActivityAt),
+		Score:          post.Score,
+		Tags:           post.Tags,
+	}
+}
+
+func categoryToProto(category *entity.Category) *pb.Category {
+	c := &pb.Category{
+		Id:          category.ID,
+		Name:        category.Name,
+		Slug:        category.Slug,
+		Description: category.Description,
+		Position:    int32(category.Position),
+		PostCount:   category.PostCount,
+		CreatedAt:   timestamppb.New(category.CreatedAt),
+	}
+	if category.LastActivityAt != nil {
+		c.LastActivityAt = timestamppb.New(*category.LastActivityAt)
+	}
+	return c
+}
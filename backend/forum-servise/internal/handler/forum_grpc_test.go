@@ -0,0 +1,202 @@
+package handler
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"backend.com/forum/apperrors"
+	pb "backend.com/forum/proto"
+	"github.com/Ulyana-kru00/forum-project/forum-servise/internal/entity"
+	"github.com/Ulyana-kru00/forum-project/forum-servise/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func withToken(token string) context.Context {
+	return metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Bearer "+token))
+}
+
+func TestForumServer_CreatePost(t *testing.T) {
+	postUC := new(mockPostUsecase)
+	postUC.On("CreatePost", mock.Anything, "user-token", "Title", "Body", int64(2), []string{"go"}).
+		Return(&entity.Post{ID: 7, Title: "Title", Content: "Body", AuthorID: 3, CategoryID: 2, Tags: []string{"go"}}, nil)
+
+	s := NewForumServer(postUC, new(mockCategoryUsecase))
+	resp, err := s.CreatePost(withToken("user-token"), &pb.CreatePostRequest{
+		Title:      "Title",
+		Content:    "Body",
+		AuthorId:   99, // ignored, the author comes from the token
+		CategoryId: 2,
+		Tags:       []string{"go"},
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(7), resp.Id)
+	assert.Equal(t, int64(3), resp.Post.AuthorId)
+	assert.Equal(t, int64(2), resp.Post.CategoryId)
+	assert.Equal(t, []string{"go"}, resp.Post.Tags)
+	postUC.AssertExpectations(t)
+}
+
+func TestForumServer_CreatePost_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		ctx     context.Context
+		ucErr   error
+		expCode codes.Code
+	}{
+		{"No metadata", context.Background(), nil, codes.Unauthenticated},
+		{"Empty authorization", metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "")), nil, codes.Unauthenticated},
+		{"Invalid token", withToken("bad"), apperrors.New(apperrors.ErrUnauthenticated, "invalid token"), codes.Unauthenticated},
+		{"Unknown category", withToken("user-token"), repository.ErrCategoryNotFound, codes.NotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			postUC := new(mockPostUsecase)
+			if tt.ucErr != nil {
+				postUC.On("CreatePost", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+					Return((*entity.Post)(nil), tt.ucErr)
+			}
+
+			s := NewForumServer(postUC, new(mockCategoryUsecase))
+			_, err := s.CreatePost(tt.ctx, &pb.CreatePostRequest{Title: "Title", Content: "Body"})
+
+			assert.Equal(t, tt.expCode, status.Code(err))
+			postUC.AssertExpectations(t)
+		})
+	}
+}
+
+func TestForumServer_GetPosts(t *testing.T) {
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	postUC := new(mockPostUsecase)
+	postUC.On("GetPosts", mock.Anything, entity.PostFilter{
+		AuthorID:   3,
+		CategoryID: 2,
+		From:       from,
+		Query:      "go",
+		Sort:       entity.PostSortMostCommented,
+		Limit:      5,
+		Cursor:     "abc",
+	}).Return(&entity.PostPage{
+		Posts:      []*entity.Post{{ID: 1, Title: "First", AuthorID: 3, CommentCount: 4}},
+		NextCursor: "next",
+	}, map[int]string{3: "alice"}, nil)
+
+	s := NewForumServer(postUC, new(mockCategoryUsecase))
+	resp, err := s.GetPosts(context.Background(), &pb.GetPostsRequest{
+		Limit:      5,
+		Cursor:     "abc",
+		Sort:       "most_commented",
+		AuthorId:   3,
+		CategoryId: 2,
+		From:       timestamppb.New(from),
+		Query:      "  go ",
+	})
+
+	require.NoError(t, err)
+	require.Len(t, resp.Posts, 1)
+	assert.Equal(t, "alice", resp.Posts[0].AuthorName)
+	assert.Equal(t, int64(4), resp.Posts[0].CommentCount)
+	assert.Equal(t, "next", resp.NextCursor)
+	postUC.AssertExpectations(t)
+}
+
+func TestForumServer_GetPosts_InvalidArgument(t *testing.T) {
+	s := NewForumServer(new(mockPostUsecase), new(mockCategoryUsecase))
+
+	_, err := s.GetPosts(context.Background(), &pb.GetPostsRequest{Limit: -1})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = s.GetPosts(context.Background(), &pb.GetPostsRequest{AuthorId: -1})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = s.GetPosts(context.Background(), nil)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestForumServer_GetCategory(t *testing.T) {
+	categoryUC := new(mockCategoryUsecase)
+	categoryUC.On("GetCategoryByID", mock.Anything, int64(1)).
+		Return(&entity.Category{ID: 1, Name: "Общее", Slug: "general"}, nil)
+	categoryUC.On("GetCategoryBySlug", mock.Anything, "missing").
+		Return(nil, repository.ErrCategoryNotFound)
+
+	s := NewForumServer(new(mockPostUsecase), categoryUC)
+
+	resp, err := s.GetCategory(context.Background(), &pb.GetCategoryRequest{Id: 1})
+	require.NoError(t, err)
+	assert.Equal(t, "general", resp.Category.Slug)
+	assert.Nil(t, resp.Category.LastActivityAt)
+
+	_, err = s.GetCategory(context.Background(), &pb.GetCategoryRequest{Slug: "missing"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	_, err = s.GetCategory(context.Background(), &pb.GetCategoryRequest{})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	categoryUC.AssertExpectations(t)
+}
+
+func TestForumServer_CreateCategory(t *testing.T) {
+	categoryUC := new(mockCategoryUsecase)
+	categoryUC.On("CreateCategory", mock.Anything, "admin-token", &entity.Category{Name: "Go", Position: 1}).
+		Return(&entity.Category{ID: 5, Name: "Go", Slug: "go", Position: 1}, nil)
+	categoryUC.On("CreateCategory", mock.Anything, "user-token", mock.Anything).
+		Return(nil, apperrors.New(apperrors.ErrPermissionDenied, "only admins can manage categories"))
+
+	s := NewForumServer(new(mockPostUsecase), categoryUC)
+
+	resp, err := s.CreateCategory(withToken("admin-token"), &pb.CreateCategoryRequest{Name: "Go", Position: 1})
+	require.NoError(t, err)
+	assert.Equal(t, int64(5), resp.Id)
+	assert.Equal(t, "go", resp.Category.Slug)
+
+	_, err = s.CreateCategory(withToken("user-token"), &pb.CreateCategoryRequest{Name: "Go"})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	categoryUC.AssertExpectations(t)
+}
+
+func TestForumServer_UpdateCategory(t *testing.T) {
+	categoryUC := new(mockCategoryUsecase)
+	categoryUC.On("UpdateCategory", mock.Anything, "admin-token", &entity.Category{ID: 5, Name: "Golang"}).
+		Return(&entity.Category{ID: 5, Name: "Golang", Slug: "go"}, nil)
+
+	s := NewForumServer(new(mockPostUsecase), categoryUC)
+
+	resp, err := s.UpdateCategory(withToken("admin-token"), &pb.UpdateCategoryRequest{Id: 5, Name: "Golang"})
+	require.NoError(t, err)
+	assert.Equal(t, "Golang", resp.Category.Name)
+
+	_, err = s.UpdateCategory(withToken("admin-token"), &pb.UpdateCategoryRequest{Name: "Golang"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	categoryUC.AssertExpectations(t)
+}
+
+func TestForumServer_ListCategories(t *testing.T) {
+	activity := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+	categoryUC := new(mockCategoryUsecase)
+	categoryUC.On("ListCategories", mock.Anything).Return([]*entity.Category{
+		{ID: 1, Name: "Общее", Slug: "general", PostCount: 2, LastActivityAt: &activity},
+	}, nil)
+
+	s := NewForumServer(new(mockPostUsecase), categoryUC)
+	resp, err := s.ListCategories(context.Background(), &pb.ListCategoriesRequest{})
+
+	require.NoError(t, err)
+	require.Len(t, resp.Categories, 1)
+	assert.Equal(t, int64(2), resp.Categories[0].PostCount)
+	assert.Equal(t, activity, resp.Categories[0].LastActivityAt.AsTime())
+}
+
+func TestForumServer_MessagesUnimplemented(t *testing.T) {
+	s := NewForumServer(new(mockPostUsecase), new(mockCategoryUsecase))
+	_, err := s.CreateMessage(context.Background(), &pb.CreateMessageRequest{})
+	assert.Equal(t, codes.Unimplemented, status.Code(err))
+}
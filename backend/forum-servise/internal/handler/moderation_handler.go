@@ -0,0 +1,241 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"backend.com/forum/apperrors"
+	"github.com/Ulyana-kru00/forum-project/forum-servise/internal/entity"
+	"github.com/Ulyana-kru00/forum-project/forum-servise/internal/usecase"
+	"github.com/Ulyana-kru00/forum-project/forum-servise/pkg/logger"
+	"github.com/gin-gonic/gin"
+)
+
+var errInvalidTargetID = apperrors.New(apperrors.ErrInvalidArgument, "invalid target id")
+
+type ModerationHandler struct {
+	uc     usecase.ModerationUsecaseInterface
+	logger *logger.Logger
+}
+
+func NewModerationHandler(uc usecase.ModerationUsecaseInterface, logger *logger.Logger) *ModerationHandler {
+	return &ModerationHandler{
+		uc:     uc,
+		logger: logger,
+	}
+}
+
+type reportRequest struct {
+	TargetType entity.ReportTargetType `json:"target_type" binding:"required" example:"comment"`
+	TargetID   int64                   `json:"target_id" binding:"required" example:"42"`
+	Reason     entity.ReportReason     `json:"reason" binding:"required" example:"spam"`
+	Details    string                  `json:"details" example:"Ссылки на казино"`
+}
+
+type moderationActionRequest struct {
+	Action entity.ModerationAction `json:"action" binding:"required" example:"warn"`
+	Note   string                  `json:"note" example:"Пожалуйста, без рекламы"`
+}
+
+// CreateReport godoc
+// @Summary Пожаловаться на контент
+// @Description Жалоба на пост, комментарий или сообщение чата. Повторная жалоба на тот же контент до решения модератора отклоняется. Контент, набравший порог жалоб, скрывается автоматически
+// @Tags moderation
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param Authorization header string true "Bearer токен"
+// @Param request body reportRequest true "Жалоба (target_type: post, comment, chat_message; reason: spam, abuse, off_topic, illegal, other)"
+// @Success 201 {object} entity.Report
+// @Failure 400 {object} entity.ErrorResponse
+// @Failure 401 {object} entity.ErrorResponse
+// @Failure 404 {object} entity.ErrorResponse
+// @Failure 409 {object} entity.ErrorResponse
+// @Failure 500 {object} entity.ErrorResponse
+// @Router /api/v1/reports [post]
+func (h *ModerationHandler) CreateReport(c *gin.Context) {
+	token, ok := bearerToken(c)
+	if !ok {
+		return
+	}
+	var req reportRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		writeError(c, errInvalidBody)
+		return
+	}
+
+	report, err := h.uc.Report(c.Request.Context(), token, &entity.Report{
+		TargetType: req.TargetType,
+		TargetID:   req.TargetID,
+		Reason:     req.Reason,
+		Details:    req.Details,
+	})
+	if err != nil {
+		logServerError(h.logger, "Failed to create report", err, "target_type", req.TargetType, "target_id", req.TargetID)
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, report)
+}
+
+// GetQueue godoc
+// @Summary Очередь модерации
+// @Description Контент с жалобами, сгруппированными по объекту, начиная с самых давних. Только для модераторов и администраторов
+// @Tags moderation
+// @Produce json
+// @Security BearerAuth
+// @Param Authorization header string true "Bearer токен"
+// @Param status query string false "Состояние жалоб: open (по умолчанию), actioned, dismissed"
+// @Param type query string false "Тип контента: post, comment, chat_message"
+// @Param limit query int false "Размер страницы (по умолчанию 20, максимум 100)"
+// @Param cursor query string false "Курсор следующей страницы"
+// @Success 200 {object} entity.ModerationQueuePage
+// @Failure 400 {object} entity.ErrorResponse
+// @Failure 401 {object} entity.ErrorResponse
+// @Failure 403 {object} entity.ErrorResponse
+// @Failure 500 {object} entity.ErrorResponse
+// @Router /api/v1/moderation/reports [get]
+func (h *ModerationHandler) GetQueue(c *gin.Context) {
+	token, ok := bearerToken(c)
+	if !ok {
+		return
+	}
+	filter := entity.ModerationQueueFilter{
+		Status:     entity.ReportStatus(c.Query("status")),
+		TargetType: entity.ReportTargetType(c.Query("type")),
+		Cursor:     c.Query("cursor"),
+	}
+	if v := c.Query("limit"); v != "" {
+		var err error
+		if filter.Limit, err = strconv.Atoi(v); err != nil || filter.Limit <= 0 {
+			writeError(c, invalidQueryParam("limit"))
+			return
+		}
+	}
+
+	page, err := h.uc.GetQueue(c.Request.Context(), token, filter)
+	if err != nil {
+		logServerError(h.logger, "Failed to get moderation queue", err)
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, page)
+}
+
+// ListReports godoc
+// @Summary Жалобы на контент
+// @Description Все жалобы на один объект, начиная с новых. Только для модераторов и администраторов
+// @Tags moderation
+// @Produce json
+// @Security BearerAuth
+// @Param Authorization header string true "Bearer токен"
+// @Param type path string true "Тип контента: post, comment, chat_message"
+// @Param id path int true "ID контента"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} entity.ErrorResponse
+// @Failure 401 {object} entity.ErrorResponse
+// @Failure 403 {object} entity.ErrorResponse
+// @Failure 500 {object} entity.ErrorResponse
+// @Router /api/v1/moderation/reports/{type}/{id} [get]
+func (h *ModerationHandler) ListReports(c *gin.Context) {
+	token, ok := bearerToken(c)
+	if !ok {
+		return
+	}
+	target, err := reportTarget(c)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	reports, err := h.uc.ListReports(c.Request.Context(), token, target)
+	if err != nil {
+		logServerError(h.logger, "Failed to list reports", err, "target_type", target.Type, "target_id", target.ID)
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": reports})
+}
+
+// Act godoc
+// @Summary Решение по жалобам
+// @Description Закрывает открытые жалобы на контент действием: hide - скрыть, delete - удалить, warn - предупредить автора, dismiss - отклонить жалобы и вернуть автоматически скрытый контент. Только для модераторов и администраторов
+// @Tags moderation
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param Authorization header string true "Bearer токен"
+// @Param type path string true "Тип контента: post, comment, chat_message"
+// @Param id path int true "ID контента"
+// @Param request body moderationActionRequest true "Действие и комментарий к предупреждению"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} entity.ErrorResponse
+// @Failure 401 {object} entity.ErrorResponse
+// @Failure 403 {object} entity.ErrorResponse
+// @Failure 404 {object} entity.ErrorResponse
+// @Failure 500 {object} entity.ErrorResponse
+// @Router /api/v1/moderation/{type}/{id}/actions [post]
+func (h *ModerationHandler) Act(c *gin.Context) {
+	token, ok := bearerToken(c)
+	if !ok {
+		return
+	}
+	target, err := reportTarget(c)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+	var req moderationActionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		writeError(c, errInvalidBody)
+		return
+	}
+
+	resolved, err := h.uc.Act(c.Request.Context(), token, target, req.Action, req.Note)
+	if err != nil {
+		logServerError(h.logger, "Failed to resolve reports", err, "target_type", target.Type, "target_id", target.ID, "action", req.Action)
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"resolved_reports": resolved})
+}
+
+// MyWarnings godoc
+// @Summary Мои предупреждения
+// @Tags moderation
+// @Produce json
+// @Security BearerAuth
+// @Param Authorization header string true "Bearer токен"
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} entity.ErrorResponse
+// @Failure 500 {object} entity.ErrorResponse
+// @Router /api/v1/warnings [get]
+func (h *ModerationHandler) MyWarnings(c *gin.Context) {
+	token, ok := bearerToken(c)
+	if !ok {
+		return
+	}
+
+	warnings, err := h.uc.MyWarnings(c.Request.Context(), token)
+	if err != nil {
+		logServerError(h.logger, "Failed to list warnings", err)
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": warnings})
+}
+
+// reportTarget reads the content a moderation route is about from its
+// path. The type is checked by the usecase.
+func reportTarget(c *gin.Context) (entity.ReportTarget, error) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return entity.ReportTarget{}, errInvalidTargetID
+	}
+	return entity.ReportTarget{Type: entity.ReportTargetType(c.Param("type")), ID: id}, nil
+}
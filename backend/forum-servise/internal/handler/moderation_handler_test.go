@@ -0,0 +1,186 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"backend.com/forum/apperrors"
+	"github.com/Ulyana-kru00/forum-project/forum-servise/internal/entity"
+	"github.com/Ulyana-kru00/forum-project/forum-servise/internal/repository"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type mockModerationUsecase struct {
+	mock.Mock
+}
+
+func (m *mockModerationUsecase) Report(ctx context.Context, token string, report *entity.Report) (*entity.Report, error) {
+	args := m.Called(ctx, token, report)
+	r, _ := args.Get(0).(*entity.Report)
+	return r, args.Error(1)
+}
+
+func (m *mockModerationUsecase) GetQueue(ctx context.Context, token string, filter entity.ModerationQueueFilter) (*entity.ModerationQueuePage, error) {
+	args := m.Called(ctx, token, filter)
+	p, _ := args.Get(0).(*entity.ModerationQueuePage)
+	return p, args.Error(1)
+}
+
+func (m *mockModerationUsecase) ListReports(ctx context.Context, token string, target entity.ReportTarget) ([]*entity.Report, error) {
+	args := m.Called(ctx, token, target)
+	r, _ := args.Get(0).([]*entity.Report)
+	return r, args.Error(1)
+}
+
+func (m *mockModerationUsecase) Act(ctx context.Context, token string, target entity.ReportTarget, action entity.ModerationAction, note string) (int64, error) {
+	args := m.Called(ctx, token, target, action, note)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *mockModerationUsecase) MyWarnings(ctx context.Context, token string) ([]*entity.UserWarning, error) {
+	args := m.Called(ctx, token)
+	w, _ := args.Get(0).([]*entity.UserWarning)
+	return w, args.Error(1)
+}
+
+func newModerationRouter(uc *mockModerationUsecase) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	h := NewModerationHandler(uc, newTestLogger())
+
+	r := gin.New()
+	r.POST("/reports", h.CreateReport)
+	r.GET("/moderation/reports", h.GetQueue)
+	r.GET("/moderation/reports/:type/:id", h.ListReports)
+	r.POST("/moderation/:type/:id/actions", h.Act)
+	r.GET("/warnings", h.MyWarnings)
+	return r
+}
+
+func TestCreateReport(t *testing.T) {
+	mockUC := new(mockModerationUsecase)
+	mockUC.On("Report", mock.Anything, "token", &entity.Report{TargetType: "comment", TargetID: 42, Reason: "spam"}).
+		Return(&entity.Report{ID: 3, TargetType: "comment", TargetID: 42, Reason: "spam", Status: "open"}, nil)
+	mockUC.On("Report", mock.Anything, "token", &entity.Report{TargetType: "post", TargetID: 1, Reason: "abuse"}).
+		Return(nil, repository.ErrAlreadyReported)
+	r := newModerationRouter(mockUC)
+
+	tests := []struct {
+		name   string
+		body   string
+		auth   string
+		status int
+	}{
+		{"Success", `{"target_type":"comment","target_id":42,"reason":"spam"}`, "Bearer token", http.StatusCreated},
+		{"Already reported", `{"target_type":"post","target_id":1,"reason":"abuse"}`, "Bearer token", http.StatusConflict},
+		{"Missing reason", `{"target_type":"post","target_id":1}`, "Bearer token", http.StatusBadRequest},
+		{"No auth header", `{"target_type":"post","target_id":1,"reason":"spam"}`, "", http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/reports", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			if tt.auth != "" {
+				req.Header.Set("Authorization", tt.auth)
+			}
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+			assert.Equal(t, tt.status, w.Code)
+		})
+	}
+	mockUC.AssertExpectations(t)
+}
+
+func TestGetModerationQueue(t *testing.T) {
+	mockUC := new(mockModerationUsecase)
+	mockUC.On("GetQueue", mock.Anything, "token", entity.ModerationQueueFilter{Status: "open", TargetType: "post", Limit: 5, Cursor: "c"}).
+		Return(&entity.ModerationQueuePage{Items: []*entity.ModerationQueueItem{{TargetType: "post", TargetID: 1, ReportCount: 2}}, NextCursor: "n"}, nil)
+	mockUC.On("GetQueue", mock.Anything, "user-token", entity.ModerationQueueFilter{}).
+		Return(nil, apperrors.New(apperrors.ErrPermissionDenied, "only moderators can review reports"))
+	r := newModerationRouter(mockUC)
+
+	tests := []struct {
+		name   string
+		url    string
+		auth   string
+		status int
+		body   string
+	}{
+		{"Success", "/moderation/reports?status=open&type=post&limit=5&cursor=c", "Bearer token", http.StatusOK, `"next_cursor":"n"`},
+		{"Not a moderator", "/moderation/reports", "Bearer user-token", http.StatusForbidden, `"code":"permission_denied"`},
+		{"Invalid limit", "/moderation/reports?limit=x", "Bearer token", http.StatusBadRequest, `"code":"invalid_argument"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.url, nil)
+			req.Header.Set("Authorization", tt.auth)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+			assert.Equal(t, tt.status, w.Code)
+			assert.Contains(t, w.Body.String(), tt.body)
+		})
+	}
+	mockUC.AssertExpectations(t)
+}
+
+func TestModerationAct(t *testing.T) {
+	target := entity.ReportTarget{Type: "chat_message", ID: 9}
+	mockUC := new(mockModerationUsecase)
+	mockUC.On("Act", mock.Anything, "token", target, entity.ModerationAction("warn"), "no ads").Return(int64(2), nil)
+	mockUC.On("Act", mock.Anything, "token", target, entity.ModerationAction("hide"), "").Return(int64(0), repository.ErrNoOpenReports)
+	r := newModerationRouter(mockUC)
+
+	tests := []struct {
+		name   string
+		url    string
+		body   string
+		status int
+	}{
+		{"Success", "/moderation/chat_message/9/actions", `{"action":"warn","note":"no ads"}`, http.StatusOK},
+		{"No open reports", "/moderation/chat_message/9/actions", `{"action":"hide"}`, http.StatusNotFound},
+		{"Invalid id", "/moderation/chat_message/x/actions", `{"action":"hide"}`, http.StatusBadRequest},
+		{"Missing action", "/moderation/chat_message/9/actions", `{}`, http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, tt.url, strings.NewReader(tt.body))
+			req.Header.Set("Authorization", "Bearer token")
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+			assert.Equal(t, tt.status, w.Code)
+		})
+	}
+	mockUC.AssertExpectations(t)
+}
+
+func TestListReportsAndWarnings(t *testing.T) {
+	mockUC := new(mockModerationUsecase)
+	mockUC.On("ListReports", mock.Anything, "token", entity.ReportTarget{Type: "post", ID: 1}).
+		Return([]*entity.Report{{ID: 3}}, nil)
+	mockUC.On("MyWarnings", mock.Anything, "token").
+		Return([]*entity.UserWarning{{ID: 4, Note: "no ads"}}, nil)
+	r := newModerationRouter(mockUC)
+
+	req := httptest.NewRequest(http.MethodGet, "/moderation/reports/post/1", nil)
+	req.Header.Set("Authorization", "Bearer token")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"id":3`)
+
+	req = httptest.NewRequest(http.MethodGet, "/warnings", nil)
+	req.Header.Set("Authorization", "Bearer token")
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"note":"no ads"`)
+	mockUC.AssertExpectations(t)
+}
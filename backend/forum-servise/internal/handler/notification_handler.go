@@ -0,0 +1,198 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/Ulyana-kru00/forum-project/forum-servise/internal/entity"
+	"github.com/Ulyana-kru00/forum-project/forum-servise/internal/usecase"
+	"github.com/Ulyana-kru00/forum-project/forum-servise/pkg/logger"
+	"github.com/gin-gonic/gin"
+)
+
+type NotificationHandler struct {
+	uc     usecase.NotificationUsecaseInterface
+	logger *logger.Logger
+}
+
+func NewNotificationHandler(uc usecase.NotificationUsecaseInterface, logger *logger.Logger) *NotificationHandler {
+	return &NotificationHandler{
+		uc:     uc,
+		logger: logger,
+	}
+}
+
+type markReadRequest struct {
+	IDs []int64 `json:"ids" example:"1,2"`
+}
+
+// ListNotifications godoc
+// @Summary Мои уведомления
+// @Description Уведомления о комментариях к моим постам, ответах на мои комментарии и упоминаниях, начиная с новых
+// @Tags notifications
+// @Produce json
+// @Security BearerAuth
+// @Param Authorization header string true "Bearer токен"
+// @Param unread query bool false "Только непрочитанные"
+// @Param limit query int false "Размер страницы (по умолчанию 20, максимум 100)"
+// @Param cursor query string false "Курсор следующей страницы"
+// @Success 200 {object} entity.NotificationPage
+// @Failure 400 {object} entity.ErrorResponse
+// @Failure 401 {object} entity.ErrorResponse
+// @Failure 500 {object} entity.ErrorResponse
+// @Router /api/v1/notifications [get]
+func (h *NotificationHandler) ListNotifications(c *gin.Context) {
+	token, ok := bearerToken(c)
+	if !ok {
+		return
+	}
+	filter := entity.NotificationFilter{Cursor: c.Query("cursor")}
+	if v := c.Query("unread"); v != "" {
+		var err error
+		if filter.UnreadOnly, err = strconv.ParseBool(v); err != nil {
+			writeError(c, invalidQueryParam("unread"))
+			return
+		}
+	}
+	if v := c.Query("limit"); v != "" {
+		var err error
+		if filter.Limit, err = strconv.Atoi(v); err != nil || filter.Limit <= 0 {
+			writeError(c, invalidQueryParam("limit"))
+			return
+		}
+	}
+
+	page, err := h.uc.List(c.Request.Context(), token, filter)
+	if err != nil {
+		logServerError(h.logger, "Failed to list notifications", err)
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, page)
+}
+
+// UnreadCount godoc
+// @Summary Число непрочитанных уведомлений
+// @Tags notifications
+// @Produce json
+// @Security BearerAuth
+// @Param Authorization header string true "Bearer токен"
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} entity.ErrorResponse
+// @Failure 500 {object} entity.ErrorResponse
+// @Router /api/v1/notifications/unread-count [get]
+func (h *NotificationHandler) UnreadCount(c *gin.Context) {
+	token, ok := bearerToken(c)
+	if !ok {
+		return
+	}
+
+	count, err := h.uc.UnreadCount(c.Request.Context(), token)
+	if err != nil {
+		logServerError(h.logger, "Failed to count unread notifications", err)
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"unread": count})
+}
+
+// MarkRead godoc
+// @Summary Отметить уведомления прочитанными
+// @Description Без списка ids прочитанными отмечаются все уведомления
+// @Tags notifications
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param Authorization header string true "Bearer токен"
+// @Param request body markReadRequest false "ID уведомлений, не больше 100"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} entity.ErrorResponse
+// @Failure 401 {object} entity.ErrorResponse
+// @Failure 500 {object} entity.ErrorResponse
+// @Router /api/v1/notifications/read [post]
+func (h *NotificationHandler) MarkRead(c *gin.Context) {
+	token, ok := bearerToken(c)
+	if !ok {
+		return
+	}
+	var req markReadRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			writeError(c, errInvalidBody)
+			return
+		}
+	}
+
+	marked, err := h.uc.MarkRead(c.Request.Context(), token, req.IDs)
+	if err != nil {
+		logServerError(h.logger, "Failed to mark notifications read", err)
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"marked": marked})
+}
+
+// GetPreferences godoc
+// @Summary Настройки уведомлений
+// @Description Для каждого типа уведомлений: post_comment, comment_reply, mention — включен ли он
+// @Tags notifications
+// @Produce json
+// @Security BearerAuth
+// @Param Authorization header string true "Bearer токен"
+// @Success 200 {object} entity.NotificationPreferences
+// @Failure 401 {object} entity.ErrorResponse
+// @Failure 500 {object} entity.ErrorResponse
+// @Router /api/v1/notifications/preferences [get]
+func (h *NotificationHandler) GetPreferences(c *gin.Context) {
+	token, ok := bearerToken(c)
+	if !ok {
+		return
+	}
+
+	prefs, err := h.uc.GetPreferences(c.Request.Context(), token)
+	if err != nil {
+		logServerError(h.logger, "Failed to get notification preferences", err)
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, prefs)
+}
+
+// UpdatePreferences godoc
+// @Summary Изменить настройки уведомлений
+// @Description Типы, не указанные в запросе, сохраняют прежнюю настройку
+// @Tags notifications
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param Authorization header string true "Bearer токен"
+// @Param request body entity.NotificationPreferences true "Например, {\"mention\": false}"
+// @Success 200 {object} entity.NotificationPreferences
+// @Failure 400 {object} entity.ErrorResponse
+// @Failure 401 {object} entity.ErrorResponse
+// @Failure 500 {object} entity.ErrorResponse
+// @Router /api/v1/notifications/preferences [put]
+func (h *NotificationHandler) UpdatePreferences(c *gin.Context) {
+	token, ok := bearerToken(c)
+	if !ok {
+		return
+	}
+	var prefs entity.NotificationPreferences
+	if err := c.ShouldBindJSON(&prefs); err != nil {
+		writeError(c, errInvalidBody)
+		return
+	}
+
+	prefs, err := h.uc.UpdatePreferences(c.Request.Context(), token, prefs)
+	if err != nil {
+		logServerError(h.logger, "Failed to update notification preferences", err)
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, prefs)
+}
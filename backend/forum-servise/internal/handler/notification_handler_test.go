@@ -0,0 +1,107 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"backend.com/forum/apperrors"
+	"github.com/Ulyana-kru00/forum-project/forum-servise/internal/entity"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type mockNotificationUsecase struct {
+	mock.Mock
+}
+
+func (m *mockNotificationUsecase) List(ctx context.Context, token string, filter entity.NotificationFilter) (*entity.NotificationPage, error) {
+	args := m.Called(ctx, token, filter)
+	p, _ := args.Get(0).(*entity.NotificationPage)
+	return p, args.Error(1)
+}
+
+func (m *mockNotificationUsecase) UnreadCount(ctx context.Context, token string) (int64, error) {
+	args := m.Called(ctx, token)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *mockNotificationUsecase) MarkRead(ctx context.Context, token string, ids []int64) (int64, error) {
+	args := m.Called(ctx, token, ids)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *mockNotificationUsecase) GetPreferences(ctx context.Context, token string) (entity.NotificationPreferences, error) {
+	args := m.Called(ctx, token)
+	p, _ := args.Get(0).(entity.NotificationPreferences)
+	return p, args.Error(1)
+}
+
+func (m *mockNotificationUsecase) UpdatePreferences(ctx context.Context, token string, prefs entity.NotificationPreferences) (entity.NotificationPreferences, error) {
+	args := m.Called(ctx, token, prefs)
+	p, _ := args.Get(0).(entity.NotificationPreferences)
+	return p, args.Error(1)
+}
+
+func newNotificationRouter(uc *mockNotificationUsecase) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	h := NewNotificationHandler(uc, newTestLogger())
+
+	r := gin.New()
+	r.GET("/notifications", h.ListNotifications)
+	r.GET("/notifications/unread-count", h.UnreadCount)
+	r.POST("/notifications/read", h.MarkRead)
+	r.GET("/notifications/preferences", h.GetPreferences)
+	r.PUT("/notifications/preferences", h.UpdatePreferences)
+	return r
+}
+
+func TestNotificationHandler(t *testing.T) {
+	mockUC := new(mockNotificationUsecase)
+	mockUC.On("List", mock.Anything, "token", entity.NotificationFilter{UnreadOnly: true, Limit: 5}).
+		Return(&entity.NotificationPage{Notifications: []*entity.Notification{{ID: 1, Type: entity.NotificationMention}}, NextCursor: "n"}, nil)
+	mockUC.On("UnreadCount", mock.Anything, "token").Return(int64(3), nil)
+	mockUC.On("MarkRead", mock.Anything, "token", []int64{1, 2}).Return(int64(2), nil)
+	mockUC.On("MarkRead", mock.Anything, "token", []int64(nil)).Return(int64(3), nil)
+	mockUC.On("GetPreferences", mock.Anything, "token").
+		Return(entity.NotificationPreferences{entity.NotificationMention: true}, nil)
+	mockUC.On("UpdatePreferences", mock.Anything, "token", entity.NotificationPreferences{entity.NotificationMention: false}).
+		Return(entity.NotificationPreferences{entity.NotificationMention: false}, nil)
+	mockUC.On("UpdatePreferences", mock.Anything, "token", entity.NotificationPreferences{"likes": true}).
+		Return(nil, apperrors.New(apperrors.ErrInvalidArgument, "notification type must be one of post_comment, comment_reply, mention"))
+	r := newNotificationRouter(mockUC)
+
+	tests := []struct {
+		name   string
+		method string
+		url    string
+		body   string
+		status int
+		want   string
+	}{
+		{"List unread", http.MethodGet, "/notifications?unread=true&limit=5", "", http.StatusOK, `"next_cursor":"n"`},
+		{"Invalid unread", http.MethodGet, "/notifications?unread=maybe", "", http.StatusBadRequest, `"code":"invalid_argument"`},
+		{"Unread count", http.MethodGet, "/notifications/unread-count", "", http.StatusOK, `{"unread":3}`},
+		{"Mark some read", http.MethodPost, "/notifications/read", `{"ids":[1,2]}`, http.StatusOK, `{"marked":2}`},
+		{"Mark all read", http.MethodPost, "/notifications/read", "", http.StatusOK, `{"marked":3}`},
+		{"Mark read bad body", http.MethodPost, "/notifications/read", `{"ids":"all"}`, http.StatusBadRequest, `"code":"invalid_argument"`},
+		{"Get preferences", http.MethodGet, "/notifications/preferences", "", http.StatusOK, `{"mention":true}`},
+		{"Update preferences", http.MethodPut, "/notifications/preferences", `{"mention":false}`, http.StatusOK, `{"mention":false}`},
+		{"Unknown type", http.MethodPut, "/notifications/preferences", `{"likes":true}`, http.StatusBadRequest, `"code":"invalid_argument"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.url, strings.NewReader(tt.body))
+			req.Header.Set("Authorization", "Bearer token")
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+			assert.Equal(t, tt.status, w.Code)
+			assert.Contains(t, w.Body.String(), tt.want)
+		})
+	}
+	mockUC.AssertExpectations(t)
+}
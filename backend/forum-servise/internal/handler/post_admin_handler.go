@@ -0,0 +1,196 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/Ulyana-kru00/forum-project/forum-servise/internal/usecase"
+	"github.com/Ulyana-kru00/forum-project/forum-servise/pkg/logger"
+	"github.com/gin-gonic/gin"
+)
+
+type PostAdminHandler struct {
+	uc     usecase.PostAdminUsecaseInterface
+	logger *logger.Logger
+}
+
+func NewPostAdminHandler(uc usecase.PostAdminUsecaseInterface, logger *logger.Logger) *PostAdminHandler {
+	return &PostAdminHandler{
+		uc:     uc,
+		logger: logger,
+	}
+}
+
+// PinPost godoc
+// @Summary Закрепить пост
+// @Description Закрепленные посты показываются первыми на первой странице списка. Только для модераторов и администраторов
+// @Tags posts
+// @Produce json
+// @Security BearerAuth
+// @Param Authorization header string true "Bearer токен"
+// @Param id path int true "ID поста"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} entity.ErrorResponse
+// @Failure 401 {object} entity.ErrorResponse
+// @Failure 403 {object} entity.ErrorResponse
+// @Failure 404 {object} entity.ErrorResponse
+// @Failure 500 {object} entity.ErrorResponse
+// @Router /api/v1/posts/{id}/pin [put]
+func (h *PostAdminHandler) PinPost(c *gin.Context) {
+	h.setFlag(c, "pinned", true, h.uc.SetPinned)
+}
+
+// UnpinPost godoc
+// @Summary Открепить пост
+// @Description Только для модераторов и администраторов
+// @Tags posts
+// @Produce json
+// @Security BearerAuth
+// @Param Authorization header string true "Bearer токен"
+// @Param id path int true "ID поста"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} entity.ErrorResponse
+// @Failure 401 {object} entity.ErrorResponse
+// @Failure 403 {object} entity.ErrorResponse
+// @Failure 404 {object} entity.ErrorResponse
+// @Failure 500 {object} entity.ErrorResponse
+// @Router /api/v1/posts/{id}/pin [delete]
+func (h *PostAdminHandler) UnpinPost(c *gin.Context) {
+	h.setFlag(c, "pinned", false, h.uc.SetPinned)
+}
+
+// LockPost godoc
+// @Summary Закрыть пост для комментариев
+// @Description Новые комментарии к закрытому посту отклоняются. Только для модераторов и администраторов
+// @Tags posts
+// @Produce json
+// @Security BearerAuth
+// @Param Authorization header string true "Bearer токен"
+// @Param id path int true "ID поста"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} entity.ErrorResponse
+// @Failure 401 {object} entity.ErrorResponse
+// @Failure 403 {object} entity.ErrorResponse
+// @Failure 404 {object} entity.ErrorResponse
+// @Failure 500 {object} entity.ErrorResponse
+// @Router /api/v1/posts/{id}/lock [put]
+func (h *PostAdminHandler) LockPost(c *gin.Context) {
+	h.setFlag(c, "locked", true, h.uc.SetLocked)
+}
+
+// UnlockPost godoc
+// @Summary Открыть пост для комментариев
+// @Description Только для модераторов и администраторов
+// @Tags posts
+// @Produce json
+// @Security BearerAuth
+// @Param Authorization header string true "Bearer токен"
+// @Param id path int true "ID поста"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} entity.ErrorResponse
+// @Failure 401 {object} entity.ErrorResponse
+// @Failure 403 {object} entity.ErrorResponse
+// @Failure 404 {object} entity.ErrorResponse
+// @Failure 500 {object} entity.ErrorResponse
+// @Router /api/v1/posts/{id}/lock [delete]
+func (h *PostAdminHandler) UnlockPost(c *gin.Context) {
+	h.setFlag(c, "locked", false, h.uc.SetLocked)
+}
+
+func (h *PostAdminHandler) setFlag(c *gin.Context, name string, on bool, set func(ctx context.Context, token string, postID int64, on bool) error) {
+	token, ok := bearerToken(c)
+	if !ok {
+		return
+	}
+	postID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		writeError(c, errInvalidPostID)
+		return
+	}
+
+	if err := set(c.Request.Context(), token, postID, on); err != nil {
+		logServerError(h.logger, "Failed to change post", err, "post_id", postID, name, on)
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"id": postID, name: on})
+}
+
+// ListTrash godoc
+// @Summary Корзина
+// @Description Удаленные посты, начиная с удаленных последними. По истечении срока хранения посты удаляются окончательно. Только для администраторов
+// @Tags posts
+// @Produce json
+// @Security BearerAuth
+// @Param Authorization header string true "Bearer токен"
+// @Param limit query int false "Размер страницы (по умолчанию 20, максимум 100)"
+// @Param cursor query string false "Курсор следующей страницы"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} entity.ErrorResponse
+// @Failure 401 {object} entity.ErrorResponse
+// @Failure 403 {object} entity.ErrorResponse
+// @Failure 500 {object} entity.ErrorResponse
+// @Router /api/v1/trash/posts [get]
+func (h *PostAdminHandler) ListTrash(c *gin.Context) {
+	token, ok := bearerToken(c)
+	if !ok {
+		return
+	}
+	var limit int
+	if v := c.Query("limit"); v != "" {
+		var err error
+		if limit, err = strconv.Atoi(v); err != nil || limit <= 0 {
+			writeError(c, invalidQueryParam("limit"))
+			return
+		}
+	}
+
+	page, err := h.uc.ListTrash(c.Request.Context(), token, limit, c.Query("cursor"))
+	if err != nil {
+		logServerError(h.logger, "Failed to list trash", err)
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":        page.Posts,
+		"next_cursor": page.NextCursor,
+	})
+}
+
+// RestorePost godoc
+// @Summary Восстановить пост из корзины
+// @Description Только для администраторов
+// @Tags posts
+// @Produce json
+// @Security BearerAuth
+// @Param Authorization header string true "Bearer токен"
+// @Param id path int true "ID поста"
+// @Success 200 {object} entity.SuccessResponse
+// @Failure 400 {object} entity.ErrorResponse
+// @Failure 401 {object} entity.ErrorResponse
+// @Failure 403 {object} entity.ErrorResponse
+// @Failure 404 {object} entity.ErrorResponse
+// @Failure 500 {object} entity.ErrorResponse
+// @Router /api/v1/trash/posts/{id}/restore [post]
+func (h *PostAdminHandler) RestorePost(c *gin.Context) {
+	token, ok := bearerToken(c)
+	if !ok {
+		return
+	}
+	postID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		writeError(c, errInvalidPostID)
+		return
+	}
+
+	if err := h.uc.RestorePost(c.Request.Context(), token, postID); err != nil {
+		logServerError(h.logger, "Failed to restore post", err, "post_id", postID)
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Post restored successfully"})
+}
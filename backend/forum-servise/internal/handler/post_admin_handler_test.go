@@ -0,0 +1,127 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"backend.com/forum/apperrors"
+	"github.com/Ulyana-kru00/forum-project/forum-servise/internal/entity"
+	"github.com/Ulyana-kru00/forum-project/forum-servise/internal/repository"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type mockPostAdminUsecase struct {
+	mock.Mock
+}
+
+func (m *mockPostAdminUsecase) SetPinned(ctx context.Context, token string, postID int64, pinned bool) error {
+	return m.Called(ctx, token, postID, pinned).Error(0)
+}
+
+func (m *mockPostAdminUsecase) SetLocked(ctx context.Context, token string, postID int64, locked bool) error {
+	return m.Called(ctx, token, postID, locked).Error(0)
+}
+
+func (m *mockPostAdminUsecase) ListTrash(ctx context.Context, token string, limit int, cursor string) (*entity.PostPage, error) {
+	args := m.Called(ctx, token, limit, cursor)
+	p, _ := args.Get(0).(*entity.PostPage)
+	return p, args.Error(1)
+}
+
+func (m *mockPostAdminUsecase) RestorePost(ctx context.Context, token string, postID int64) error {
+	return m.Called(ctx, token, postID).Error(0)
+}
+
+func newPostAdminRouter(uc *mockPostAdminUsecase) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	h := NewPostAdminHandler(uc, newTestLogger())
+
+	r := gin.New()
+	r.PUT("/posts/:id/pin", h.PinPost)
+	r.DELETE("/posts/:id/pin", h.UnpinPost)
+	r.PUT("/posts/:id/lock", h.LockPost)
+	r.DELETE("/posts/:id/lock", h.UnlockPost)
+	r.GET("/trash/posts", h.ListTrash)
+	r.POST("/trash/posts/:id/restore", h.RestorePost)
+	return r
+}
+
+func TestPinAndLockPost(t *testing.T) {
+	mockUC := new(mockPostAdminUsecase)
+	mockUC.On("SetPinned", mock.Anything, "token", int64(1), true).Return(nil)
+	mockUC.On("SetPinned", mock.Anything, "token", int64(1), false).Return(nil)
+	mockUC.On("SetLocked", mock.Anything, "token", int64(1), true).Return(nil)
+	mockUC.On("SetLocked", mock.Anything, "token", int64(2), false).Return(repository.ErrPostNotFound)
+	mockUC.On("SetLocked", mock.Anything, "user-token", int64(1), true).
+		Return(apperrors.New(apperrors.ErrPermissionDenied, "only moderators can pin and lock posts"))
+	r := newPostAdminRouter(mockUC)
+
+	tests := []struct {
+		name   string
+		method string
+		url    string
+		auth   string
+		status int
+		body   string
+	}{
+		{"Pin", http.MethodPut, "/posts/1/pin", "Bearer token", http.StatusOK, `"pinned":true`},
+		{"Unpin", http.MethodDelete, "/posts/1/pin", "Bearer token", http.StatusOK, `"pinned":false`},
+		{"Lock", http.MethodPut, "/posts/1/lock", "Bearer token", http.StatusOK, `"locked":true`},
+		{"Unlock missing post", http.MethodDelete, "/posts/2/lock", "Bearer token", http.StatusNotFound, `"code":"not_found"`},
+		{"Not a moderator", http.MethodPut, "/posts/1/lock", "Bearer user-token", http.StatusForbidden, `"code":"permission_denied"`},
+		{"Invalid id", http.MethodPut, "/posts/x/pin", "Bearer token", http.StatusBadRequest, `"code":"invalid_argument"`},
+		{"No auth header", http.MethodPut, "/posts/1/pin", "", http.StatusUnauthorized, `"code":"unauthenticated"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.url, nil)
+			if tt.auth != "" {
+				req.Header.Set("Authorization", tt.auth)
+			}
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+			assert.Equal(t, tt.status, w.Code)
+			assert.Contains(t, w.Body.String(), tt.body)
+		})
+	}
+	mockUC.AssertExpectations(t)
+}
+
+func TestTrash(t *testing.T) {
+	mockUC := new(mockPostAdminUsecase)
+	mockUC.On("ListTrash", mock.Anything, "token", 5, "c").
+		Return(&entity.PostPage{Posts: []*entity.Post{{ID: 3, Title: "Gone"}}, NextCursor: "n"}, nil)
+	mockUC.On("RestorePost", mock.Anything, "token", int64(3)).Return(nil)
+	mockUC.On("RestorePost", mock.Anything, "token", int64(4)).Return(repository.ErrPostNotInTrash)
+	r := newPostAdminRouter(mockUC)
+
+	tests := []struct {
+		name   string
+		method string
+		url    string
+		status int
+		body   string
+	}{
+		{"List", http.MethodGet, "/trash/posts?limit=5&cursor=c", http.StatusOK, `"next_cursor":"n"`},
+		{"Invalid limit", http.MethodGet, "/trash/posts?limit=0", http.StatusBadRequest, `"code":"invalid_argument"`},
+		{"Restore", http.MethodPost, "/trash/posts/3/restore", http.StatusOK, `"message"`},
+		{"Restore a post not in the trash", http.MethodPost, "/trash/posts/4/restore", http.StatusNotFound, `"code":"not_found"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.url, nil)
+			req.Header.Set("Authorization", "Bearer token")
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+			assert.Equal(t, tt.status, w.Code)
+			assert.Contains(t, w.Body.String(), tt.body)
+		})
+	}
+	mockUC.AssertExpectations(t)
+}
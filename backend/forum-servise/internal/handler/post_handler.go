@@ -1,14 +1,13 @@
 package handler
 
 import (
-	"errors"
 	"net/http"
 	"strconv"
 	"strings"
 	"time"
 
 	_ "github.com/Ulyana-kru00/forum-project/forum-servise/docs"
-"github.com/Ulyana-kru00/forum-project/forum-servise/internal/repository"
+"github.com/Ulyana-kru00/forum-project/forum-servise/internal/entity"
 "github.com/Ulyana-kru00/forum-project/forum-servise/internal/usecase"
 "github.com/Ulyana-kru00/forum-project/forum-servise/pkg/logger"
 	"github.com/gin-gonic/gin"
@@ -26,42 +25,43 @@ func NewPostHandler(uc usecase.PostUsecaseInterface, logger *logger.Logger) *Pos
 
 // CreatePost godoc
 // @Summary Создать новый пост
-// @Description Создает новый пост в системе
+// @Description Создает новый пост в системе. Содержимое пишется в Markdown (CommonMark, таблицы, блоки кода) и возвращается также в виде очищенного HTML в content_html
 // @Tags Посты
 // @Accept json
 // @Produce json
 // @Security BearerAuth
 // @Param Authorization header string true "Bearer токен"
-// @Param request body entity.Post true "Данные поста"
+// @Param request body entity.CreatePostRequest true "Данные поста (без category_id пост попадает в первую категорию; не больше 5 тегов)"
 // @Success 201 {object} entity.Post
 // @Failure 400 {object} entity.ErrorResponse
 // @Failure 401 {object} entity.ErrorResponse
 // @Failure 500 {object} entity.ErrorResponse
 // @Router /api/v1/posts [post]
-
 func (h *PostHandler) CreatePost(ctx *gin.Context) {
 	authHeader := ctx.GetHeader("Authorization")
 	if authHeader == "" {
-		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Authorization header is required"})
+		writeError(ctx, errMissingAuthHeader)
 		return
 	}
 
 	token := strings.TrimPrefix(authHeader, "Bearer ")
 
 	var request struct {
-		Title   string `json:"title" binding:"required"`
-		Content string `json:"content" binding:"required"`
+		Title      string   `json:"title" binding:"required"`
+		Content    string   `json:"content" binding:"required"`
+		CategoryID int64    `json:"category_id"`
+		Tags       []string `json:"tags"`
 	}
 
 	if err := ctx.ShouldBindJSON(&request); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		writeError(ctx, errInvalidBody)
 		return
 	}
 
-	post, err := h.uc.CreatePost(ctx.Request.Context(), token, request.Title, request.Content)
+	post, err := h.uc.CreatePost(ctx.Request.Context(), token, request.Title, request.Content, request.CategoryID, request.Tags)
 	if err != nil {
-		h.logger.Error("Failed to create post", err)
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create post"})
+		logServerError(h.logger, "Failed to create post", err)
+		writeError(ctx, err)
 		return
 	}
 
@@ -73,47 +73,111 @@ func (h *PostHandler) CreatePost(ctx *gin.Context) {
 }
 
 // GetPosts godoc
-// @Summary Get all posts
-// @Description Get list of all forum posts with pagination
+// @Summary Get posts
+// @Description Get a page of forum posts. Pages are chained with next_cursor.
 // @Tags posts
 // @Accept json
 // @Produce json
-// @Param page query int false "Page number" default(1)
-// @Param limit query int false "Posts per page" default(10)
+// @Param limit query int false "Posts per page (max 100)" default(10)
+// @Param cursor query string false "next_cursor from the previous page"
+// @Param sort query string false "Sort order" Enums(newest, oldest, most_commented, recently_active, top, hot) default(newest)
+// @Param window query string false "Period for sort=top" Enums(day, week, month, year, all) default(all)
+// @Param author_id query int false "Only posts by this author"
+// @Param category_id query int false "Only posts in this category"
+// @Param from query string false "Created at or after (RFC3339)"
+// @Param to query string false "Created before (RFC3339)"
+// @Param q query string false "Text to search in title and content"
+// @Param tag query []string false "Only posts with these tags (repeat or separate with commas)" collectionFormat(multi)
+// @Param tag_match query string false "Whether posts need all of the tags or any of them" Enums(all, any) default(all)
 // @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} entity.ErrorResponse
 // @Failure 500 {object} entity.ErrorResponse
 // @Router /api/v1/posts [get]
 func (h *PostHandler) GetPosts(c *gin.Context) {
-	posts, authorNames, err := h.uc.GetPosts(c.Request.Context())
+	filter, err := parsePostFilter(c)
 	if err != nil {
-		h.logger.Error("Failed to get posts", err)
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":   "Failed to get posts",
-			"details": err.Error(),
-		})
+		writeError(c, err)
+		return
+	}
+
+	page, authorNames, err := h.uc.GetPosts(c.Request.Context(), filter)
+	if err != nil {
+		logServerError(h.logger, "Failed to get posts", err)
+		writeError(c, err)
 		return
 	}
 
-	response := make([]gin.H, 0, len(posts))
-	for _, post := range posts {
+	response := make([]gin.H, 0, len(page.Posts))
+	for _, post := range page.Posts {
 		response = append(response, gin.H{
-			"id":          post.ID,
-			"title":       post.Title,
-			"content":     post.Content,
-			"author_id":   post.AuthorID,
-			"author_name": authorNames[int(post.AuthorID)],
-			"created_at":  post.CreatedAt.Format(time.RFC3339),
+			"id":               post.ID,
+			"title":            post.Title,
+			"content":          post.Content,
+			"content_html":     post.ContentHTML,
+			"author_id":        post.AuthorID,
+			"author_name":      authorNames[int(post.AuthorID)],
+			"category_id":      post.CategoryID,
+			"created_at":       post.CreatedAt.Format(time.RFC3339),
+			"comment_count":    post.CommentCount,
+			"last_activity_at": post.LastActivityAt.Format(time.RFC3339),
+			"score":            post.Score,
+			"tags":             post.Tags,
+			"pinned":           post.Pinned,
+			"locked":           post.Locked,
 		})
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"data": response,
+		"data":        response,
+		"next_cursor": page.NextCursor,
 	})
 }
 
+// parsePostFilter reads the listing parameters of GetPosts.
+func parsePostFilter(c *gin.Context) (entity.PostFilter, error) {
+	filter := entity.PostFilter{
+		Cursor:   c.Query("cursor"),
+		Query:    strings.TrimSpace(c.Query("q")),
+		Sort:     entity.PostSort(c.Query("sort")),
+		Window:   entity.TopWindow(c.Query("window")),
+		TagMatch: entity.TagMatch(c.Query("tag_match")),
+	}
+	for _, v := range c.QueryArray("tag") {
+		filter.Tags = append(filter.Tags, strings.Split(v, ",")...)
+	}
+
+	var err error
+	if v := c.Query("limit"); v != "" {
+		if filter.Limit, err = strconv.Atoi(v); err != nil || filter.Limit <= 0 {
+			return filter, invalidQueryParam("limit")
+		}
+	}
+	if v := c.Query("author_id"); v != "" {
+		if filter.AuthorID, err = strconv.ParseInt(v, 10, 64); err != nil || filter.AuthorID <= 0 {
+			return filter, invalidQueryParam("author_id")
+		}
+	}
+	if v := c.Query("category_id"); v != "" {
+		if filter.CategoryID, err = strconv.ParseInt(v, 10, 64); err != nil || filter.CategoryID <= 0 {
+			return filter, invalidQueryParam("category_id")
+		}
+	}
+	if v := c.Query("from"); v != "" {
+		if filter.From, err = time.Parse(time.RFC3339, v); err != nil {
+			return filter, invalidQueryParam("from")
+		}
+	}
+	if v := c.Query("to"); v != "" {
+		if filter.To, err = time.Parse(time.RFC3339, v); err != nil {
+			return filter, invalidQueryParam("to")
+		}
+	}
+	return filter, nil
+}
+
 // DeletePost godoc
 // @Summary Delete a post
-// @Description Delete a forum post by ID (only author or admin can delete)
+// @Description Move a forum post to the trash by ID (only author or admin can delete). Admins can restore it until it is purged
 // @Tags posts
 // @Accept json
 // @Produce json
@@ -130,13 +194,13 @@ func (h *PostHandler) GetPosts(c *gin.Context) {
 func (h *PostHandler) DeletePost(ctx *gin.Context) {
 	postID, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid post ID"})
+		writeError(ctx, errInvalidPostID)
 		return
 	}
 
 	authHeader := ctx.GetHeader("Authorization")
 	if authHeader == "" {
-		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Authorization header is required"})
+		writeError(ctx, errMissingAuthHeader)
 		return
 	}
 
@@ -148,16 +212,8 @@ func (h *PostHandler) DeletePost(ctx *gin.Context) {
 	)
 
 	if err := h.uc.DeletePost(ctx.Request.Context(), token, postID); err != nil {
-		h.logger.Error("Failed to delete post", err)
-
-		switch {
-		case errors.Is(err, repository.ErrPostNotFound):
-			ctx.JSON(http.StatusNotFound, gin.H{"error": "Post not found"})
-		case errors.Is(err, repository.ErrPermissionDenied):
-			ctx.JSON(http.StatusForbidden, gin.H{"error": "You don't have permission"})
-		default:
-			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete post"})
-		}
+		logServerError(h.logger, "Failed to delete post", err)
+		writeError(ctx, err)
 		return
 	}
 
@@ -166,7 +222,7 @@ func (h *PostHandler) DeletePost(ctx *gin.Context) {
 
 // UpdatePost godoc
 // @Summary Update a post
-// @Description Update an existing forum post (only author can update)
+// @Description Update an existing forum post (only author can update). A "category_id" moves the post to that category, without it the category is kept. Without "tags" the tags are kept, an empty list removes them
 // @Tags posts
 // @Accept json
 // @Produce json
@@ -184,38 +240,33 @@ func (h *PostHandler) DeletePost(ctx *gin.Context) {
 func (h *PostHandler) UpdatePost(ctx *gin.Context) {
 	postID, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid post ID"})
+		writeError(ctx, errInvalidPostID)
 		return
 	}
 
 	authHeader := ctx.GetHeader("Authorization")
 	if authHeader == "" {
-		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Authorization header is required"})
+		writeError(ctx, errMissingAuthHeader)
 		return
 	}
 	token := strings.TrimPrefix(authHeader, "Bearer ")
 
 	var request struct {
-		Title   string `json:"title" binding:"required"`
-		Content string `json:"content" binding:"required"`
+		Title      string   `json:"title" binding:"required"`
+		Content    string   `json:"content" binding:"required"`
+		CategoryID int64    `json:"category_id"`
+		Tags       []string `json:"tags"`
 	}
 
 	if err := ctx.ShouldBindJSON(&request); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		writeError(ctx, errInvalidBody)
 		return
 	}
 
-	updatedPost, err := h.uc.UpdatePost(ctx.Request.Context(), token, postID, request.Title, request.Content)
+	updatedPost, err := h.uc.UpdatePost(ctx.Request.Context(), token, postID, request.Title, request.Content, request.CategoryID, request.Tags)
 	if err != nil {
-		switch {
-		case errors.Is(err, repository.ErrPostNotFound):
-			ctx.JSON(http.StatusNotFound, gin.H{"error": "Post not found"})
-		case errors.Is(err, repository.ErrPermissionDenied):
-			ctx.JSON(http.StatusForbidden, gin.H{"error": "Permission denied"})
-		default:
-			h.logger.Error("Failed to update post", err)
-			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update post"})
-		}
+		logServerError(h.logger, "Failed to update post", err)
+		writeError(ctx, err)
 		return
 	}
 
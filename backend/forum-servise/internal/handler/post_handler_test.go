@@ -3,6 +3,7 @@ package handler
 import (
 	"bytes"
 	"context"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -14,20 +15,21 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
 )
 
 type mockPostUsecase struct {
 	mock.Mock
 }
 
-func (m *mockPostUsecase) CreatePost(ctx context.Context, token, title, content string) (*entity.Post, error) {
-	args := m.Called(ctx, token, title, content)
+func (m *mockPostUsecase) CreatePost(ctx context.Context, token, title, content string, categoryID int64, tags []string) (*entity.Post, error) {
+	args := m.Called(ctx, token, title, content, categoryID, tags)
 	return args.Get(0).(*entity.Post), args.Error(1)
 }
 
-func (m *mockPostUsecase) GetPosts(ctx context.Context) ([]*entity.Post, map[int]string, error) {
-	args := m.Called(ctx)
-	return args.Get(0).([]*entity.Post), args.Get(1).(map[int]string), args.Error(2)
+func (m *mockPostUsecase) GetPosts(ctx context.Context, filter entity.PostFilter) (*entity.PostPage, map[int]string, error) {
+	args := m.Called(ctx, filter)
+	return args.Get(0).(*entity.PostPage), args.Get(1).(map[int]string), args.Error(2)
 }
 
 func (m *mockPostUsecase) DeletePost(ctx context.Context, token string, postID int64) error {
@@ -35,13 +37,13 @@ func (m *mockPostUsecase) DeletePost(ctx context.Context, token string, postID i
 	return args.Error(0)
 }
 
-func (m *mockPostUsecase) UpdatePost(ctx context.Context, token string, postID int64, title, content string) (*entity.Post, error) {
-	args := m.Called(ctx, token, postID, title, content)
+func (m *mockPostUsecase) UpdatePost(ctx context.Context, token string, postID int64, title, content string, categoryID int64, tags []string) (*entity.Post, error) {
+	args := m.Called(ctx, token, postID, title, content, categoryID, tags)
 	return args.Get(0).(*entity.Post), args.Error(1)
 }
 
 func newTestLogger() *logger.Logger {
-	return &logger.Logger{SugaredLogger: nil}
+	return &logger.Logger{SugaredLogger: zap.NewNop().Sugar()}
 }
 
 func TestCreatePost(t *testing.T) {
@@ -61,10 +63,10 @@ func TestCreatePost(t *testing.T) {
 		CreatedAt: time.Now(),
 	}
 
-	mockUC.On("CreatePost", mock.Anything, "valid-token", "Test Title", "Test Content").
+	mockUC.On("CreatePost", mock.Anything, "valid-token", "Test Title", "Test Content", int64(2), []string{"Go", "sql"}).
 		Return(post, nil)
 
-	body := `{"title":"Test Title", "content":"Test Content"}`
+	body := `{"title":"Test Title", "content":"Test Content", "category_id": 2, "tags": ["Go", "sql"]}`
 	req, _ := http.NewRequest(http.MethodPost, "/posts", bytes.NewBufferString(body))
 	req.Header.Set("Authorization", "Bearer valid-token")
 	req.Header.Set("Content-Type", "application/json")
@@ -86,21 +88,56 @@ func TestGetPosts(t *testing.T) {
 	r.GET("/posts", handler.GetPosts)
 
 	mockPosts := []*entity.Post{
-		{ID: 1, Title: "Test", Content: "Body", AuthorID: 1, CreatedAt: time.Now()},
+		{ID: 1, Title: "Test", Content: "Body", AuthorID: 1, CreatedAt: time.Now(), Tags: []string{"go"}},
 	}
 	authors := map[int]string{1: "Alice"}
 
-	mockUC.On("GetPosts", mock.Anything).Return(mockPosts, authors, nil)
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	wantFilter := entity.PostFilter{
+		Limit:    5,
+		Cursor:   "abc",
+		Sort:     entity.PostSortMostCommented,
+		AuthorID: 1,
+		From:     from,
+		Query:    "go",
+		Tags:     []string{"go", "sql", "web"},
+		TagMatch: entity.TagMatchAny,
+	}
+	mockUC.On("GetPosts", mock.Anything, wantFilter).
+		Return(&entity.PostPage{Posts: mockPosts, NextCursor: "next"}, authors, nil)
 
-	req, _ := http.NewRequest(http.MethodGet, "/posts", nil)
+	req, _ := http.NewRequest(http.MethodGet,
+		"/posts?limit=5&cursor=abc&sort=most_commented&author_id=1&from=2024-01-01T00:00:00Z&q=+go+&tag=go,sql&tag=web&tag_match=any", nil)
 	w := httptest.NewRecorder()
 
 	r.ServeHTTP(w, req)
 
 	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"next_cursor":"next"`)
+	assert.Contains(t, w.Body.String(), `"tags":["go"]`)
 	mockUC.AssertExpectations(t)
 }
 
+func TestGetPosts_InvalidParams(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockUC := new(mockPostUsecase)
+	handler := NewPostHandler(mockUC, newTestLogger())
+
+	r := gin.Default()
+	r.GET("/posts", handler.GetPosts)
+
+	for _, query := range []string{"limit=abc", "limit=0", "author_id=x", "from=yesterday", "to=2024-13-01"} {
+		req, _ := http.NewRequest(http.MethodGet, "/posts?"+query, nil)
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code, query)
+	}
+	mockUC.AssertNotCalled(t, "GetPosts", mock.Anything, mock.Anything)
+}
+
 func TestDeletePost_Success(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
@@ -140,6 +177,67 @@ func TestDeletePost_NotFound(t *testing.T) {
 	r.ServeHTTP(w, req)
 
 	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.JSONEq(t, `{"error":"post not found","code":"not_found"}`, w.Body.String())
+}
+
+func TestUpdatePost_Errors(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name         string
+		err          error
+		expectedCode int
+		expectedBody string
+	}{
+		{
+			name:         "not the author",
+			err:          repository.ErrPermissionDenied,
+			expectedCode: http.StatusForbidden,
+			expectedBody: `{"error":"permission denied","code":"permission_denied"}`,
+		},
+		{
+			name:         "post not found",
+			err:          repository.ErrPostNotFound,
+			expectedCode: http.StatusNotFound,
+			expectedBody: `{"error":"post not found","code":"not_found"}`,
+		},
+		{
+			name:         "category not found",
+			err:          repository.ErrCategoryNotFound,
+			expectedCode: http.StatusNotFound,
+			expectedBody: `{"error":"category not found","code":"not_found"}`,
+		},
+		{
+			name:         "unexpected error is masked",
+			err:          errors.New("pq: connection reset"),
+			expectedCode: http.StatusInternalServerError,
+			expectedBody: `{"error":"internal server error","code":"internal"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUC := new(mockPostUsecase)
+			handler := NewPostHandler(mockUC, newTestLogger())
+
+			r := gin.Default()
+			r.PUT("/posts/:id", handler.UpdatePost)
+
+			mockUC.On("UpdatePost", mock.Anything, "valid-token", int64(1), "Updated", "Updated content", int64(0), []string(nil)).
+				Return((*entity.Post)(nil), tt.err)
+
+			body := `{"title":"Updated", "content":"Updated content"}`
+			req, _ := http.NewRequest(http.MethodPut, "/posts/1", bytes.NewBufferString(body))
+			req.Header.Set("Authorization", "Bearer valid-token")
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+			assert.JSONEq(t, tt.expectedBody, w.Body.String())
+		})
+	}
 }
 
 func TestUpdatePost(t *testing.T) {
@@ -152,17 +250,18 @@ func TestUpdatePost(t *testing.T) {
 	r.PUT("/posts/:id", handler.UpdatePost)
 
 	post := &entity.Post{
-		ID:        1,
-		Title:     "Updated",
-		Content:   "Updated content",
-		AuthorID:  42,
-		CreatedAt: time.Now(),
+		ID:         1,
+		Title:      "Updated",
+		Content:    "Updated content",
+		AuthorID:   42,
+		CategoryID: 3,
+		CreatedAt:  time.Now(),
 	}
 
-	mockUC.On("UpdatePost", mock.Anything, "valid-token", int64(1), "Updated", "Updated content").
+	mockUC.On("UpdatePost", mock.Anything, "valid-token", int64(1), "Updated", "Updated content", int64(3), []string(nil)).
 		Return(post, nil)
 
-	body := `{"title":"Updated", "content":"Updated content"}`
+	body := `{"title":"Updated", "content":"Updated content", "category_id":3}`
 	req, _ := http.NewRequest(http.MethodPut, "/posts/1", bytes.NewBufferString(body))
 	req.Header.Set("Authorization", "Bearer valid-token")
 	req.Header.Set("Content-Type", "application/json")
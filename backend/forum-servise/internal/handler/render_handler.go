@@ -0,0 +1,53 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/Ulyana-kru00/forum-project/forum-servise/pkg/logger"
+	"github.com/Ulyana-kru00/forum-project/forum-servise/pkg/markdown"
+	"github.com/gin-gonic/gin"
+)
+
+type RenderHandler struct {
+	logger *logger.Logger
+}
+
+func NewRenderHandler(logger *logger.Logger) *RenderHandler {
+	return &RenderHandler{logger: logger}
+}
+
+type renderRequest struct {
+	Content string `json:"content" example:"**Жирный** текст"`
+}
+
+type renderResponse struct {
+	ContentHTML string `json:"content_html" example:"<p><strong>Жирный</strong> текст</p>"`
+}
+
+// Render godoc
+// @Summary Предпросмотр Markdown
+// @Description Рендерит Markdown в очищенный HTML точно так же, как при публикации поста или комментария
+// @Tags render
+// @Accept json
+// @Produce json
+// @Param request body renderRequest true "Markdown-текст (не больше 64 КиБ)"
+// @Success 200 {object} renderResponse
+// @Failure 400 {object} entity.ErrorResponse
+// @Failure 500 {object} entity.ErrorResponse
+// @Router /api/v1/render [post]
+func (h *RenderHandler) Render(c *gin.Context) {
+	var request renderRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		writeError(c, errInvalidBody)
+		return
+	}
+
+	html, err := markdown.Render(request.Content)
+	if err != nil {
+		logServerError(h.logger, "Failed to render markdown", err)
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, renderResponse{ContentHTML: html})
+}
@@ -0,0 +1,42 @@
+package handler
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Ulyana-kru00/forum-project/forum-servise/pkg/markdown"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRender(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.POST("/render", NewRenderHandler(newTestLogger()).Render)
+
+	tests := []struct {
+		name   string
+		body   string
+		status int
+		want   string
+	}{
+		{"Success", `{"content":"**hi** <script>x</script>"}`, http.StatusOK, `{"content_html":"<p><strong>hi</strong> x</p>\n"}`},
+		{"Empty", `{"content":""}`, http.StatusOK, `{"content_html":""}`},
+		{"Too long", `{"content":"` + strings.Repeat("a", markdown.MaxSourceLength+1) + `"}`, http.StatusBadRequest, ""},
+		{"Invalid body", `{`, http.StatusBadRequest, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/render", bytes.NewBufferString(tt.body)))
+			assert.Equal(t, tt.status, w.Code)
+			if tt.want != "" {
+				assert.JSONEq(t, tt.want, w.Body.String())
+			}
+		})
+	}
+}
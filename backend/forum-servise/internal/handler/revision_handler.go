@@ -0,0 +1,172 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"backend.com/forum/apperrors"
+	"github.com/Ulyana-kru00/forum-project/forum-servise/internal/usecase"
+	"github.com/Ulyana-kru00/forum-project/forum-servise/pkg/logger"
+	"github.com/gin-gonic/gin"
+)
+
+var errInvalidRevision = apperrors.New(apperrors.ErrInvalidArgument, "invalid revision")
+
+type RevisionHandler struct {
+	uc     usecase.RevisionUsecaseInterface
+	logger *logger.Logger
+}
+
+func NewRevisionHandler(uc usecase.RevisionUsecaseInterface, logger *logger.Logger) *RevisionHandler {
+	return &RevisionHandler{
+		uc:     uc,
+		logger: logger,
+	}
+}
+
+// ListRevisions godoc
+// @Summary История правок поста
+// @Description Возвращает все ревизии поста, новые первыми. Ревизия 1 — исходная версия
+// @Tags revisions
+// @Produce json
+// @Param id path int true "ID поста"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} entity.ErrorResponse
+// @Failure 404 {object} entity.ErrorResponse
+// @Failure 500 {object} entity.ErrorResponse
+// @Router /api/v1/posts/{id}/revisions [get]
+func (h *RevisionHandler) ListRevisions(c *gin.Context) {
+	postID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		writeError(c, errInvalidPostID)
+		return
+	}
+
+	revisions, err := h.uc.ListRevisions(c.Request.Context(), postID)
+	if err != nil {
+		logServerError(h.logger, "Failed to list revisions", err, "post_id", postID)
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": revisions})
+}
+
+// GetRevision godoc
+// @Summary Ревизия поста
+// @Tags revisions
+// @Produce json
+// @Param id path int true "ID поста"
+// @Param revision path int true "Номер ревизии"
+// @Success 200 {object} entity.PostRevision
+// @Failure 400 {object} entity.ErrorResponse
+// @Failure 404 {object} entity.ErrorResponse
+// @Failure 500 {object} entity.ErrorResponse
+// @Router /api/v1/posts/{id}/revisions/{revision} [get]
+func (h *RevisionHandler) GetRevision(c *gin.Context) {
+	postID, revision, ok := revisionParams(c)
+	if !ok {
+		return
+	}
+
+	rev, err := h.uc.GetRevision(c.Request.Context(), postID, revision)
+	if err != nil {
+		logServerError(h.logger, "Failed to get revision", err, "post_id", postID, "revision", revision)
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, rev)
+}
+
+// DiffRevisions godoc
+// @Summary Сравнение ревизий
+// @Description Построчный unified diff содержимого двух ревизий поста
+// @Tags revisions
+// @Produce json
+// @Param id path int true "ID поста"
+// @Param from query int true "Исходная ревизия"
+// @Param to query int true "Конечная ревизия"
+// @Success 200 {object} entity.RevisionDiff
+// @Failure 400 {object} entity.ErrorResponse
+// @Failure 404 {object} entity.ErrorResponse
+// @Failure 500 {object} entity.ErrorResponse
+// @Router /api/v1/posts/{id}/revisions/diff [get]
+func (h *RevisionHandler) DiffRevisions(c *gin.Context) {
+	postID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		writeError(c, errInvalidPostID)
+		return
+	}
+	from, err := strconv.Atoi(c.Query("from"))
+	if err != nil || from <= 0 {
+		writeError(c, invalidQueryParam("from"))
+		return
+	}
+	to, err := strconv.Atoi(c.Query("to"))
+	if err != nil || to <= 0 {
+		writeError(c, invalidQueryParam("to"))
+		return
+	}
+
+	diff, err := h.uc.DiffRevisions(c.Request.Context(), postID, from, to)
+	if err != nil {
+		logServerError(h.logger, "Failed to diff revisions", err, "post_id", postID, "from", from, "to", to)
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, diff)
+}
+
+// RestoreRevision godoc
+// @Summary Откатить пост к ревизии
+// @Description Только для администраторов. Откат сохраняется как новая ревизия
+// @Tags revisions
+// @Produce json
+// @Security BearerAuth
+// @Param Authorization header string true "Bearer токен"
+// @Param id path int true "ID поста"
+// @Param revision path int true "Номер ревизии"
+// @Success 200 {object} entity.Post
+// @Failure 400 {object} entity.ErrorResponse
+// @Failure 401 {object} entity.ErrorResponse
+// @Failure 403 {object} entity.ErrorResponse
+// @Failure 404 {object} entity.ErrorResponse
+// @Failure 500 {object} entity.ErrorResponse
+// @Router /api/v1/posts/{id}/revisions/{revision}/restore [post]
+func (h *RevisionHandler) RestoreRevision(c *gin.Context) {
+	token, ok := bearerToken(c)
+	if !ok {
+		return
+	}
+	postID, revision, ok := revisionParams(c)
+	if !ok {
+		return
+	}
+
+	post, err := h.uc.RestoreRevision(c.Request.Context(), token, postID, revision)
+	if err != nil {
+		logServerError(h.logger, "Failed to restore revision", err, "post_id", postID, "revision", revision)
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, post)
+}
+
+// revisionParams reads the post id and revision number from the path,
+// writing the error response itself when one is malformed.
+func revisionParams(c *gin.Context) (postID int64, revision int, ok bool) {
+	postID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		writeError(c, errInvalidPostID)
+		return 0, 0, false
+	}
+	revision, err = strconv.Atoi(c.Param("revision"))
+	if err != nil || revision <= 0 {
+		writeError(c, errInvalidRevision)
+		return 0, 0, false
+	}
+	return postID, revision, true
+}
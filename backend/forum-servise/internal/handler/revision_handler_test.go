@@ -0,0 +1,149 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"backend.com/forum/apperrors"
+	"github.com/Ulyana-kru00/forum-project/forum-servise/internal/entity"
+	"github.com/Ulyana-kru00/forum-project/forum-servise/internal/repository"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type mockRevisionUsecase struct {
+	mock.Mock
+}
+
+func (m *mockRevisionUsecase) ListRevisions(ctx context.Context, postID int64) ([]*entity.PostRevision, error) {
+	args := m.Called(ctx, postID)
+	r, _ := args.Get(0).([]*entity.PostRevision)
+	return r, args.Error(1)
+}
+
+func (m *mockRevisionUsecase) GetRevision(ctx context.Context, postID int64, revision int) (*entity.PostRevision, error) {
+	args := m.Called(ctx, postID, revision)
+	r, _ := args.Get(0).(*entity.PostRevision)
+	return r, args.Error(1)
+}
+
+func (m *mockRevisionUsecase) DiffRevisions(ctx context.Context, postID int64, from, to int) (*entity.RevisionDiff, error) {
+	args := m.Called(ctx, postID, from, to)
+	d, _ := args.Get(0).(*entity.RevisionDiff)
+	return d, args.Error(1)
+}
+
+func (m *mockRevisionUsecase) RestoreRevision(ctx context.Context, token string, postID int64, revision int) (*entity.Post, error) {
+	args := m.Called(ctx, token, postID, revision)
+	p, _ := args.Get(0).(*entity.Post)
+	return p, args.Error(1)
+}
+
+func newRevisionRouter(uc *mockRevisionUsecase) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	h := NewRevisionHandler(uc, newTestLogger())
+
+	r := gin.New()
+	r.GET("/posts/:id/revisions", h.ListRevisions)
+	r.GET("/posts/:id/revisions/diff", h.DiffRevisions)
+	r.GET("/posts/:id/revisions/:revision", h.GetRevision)
+	r.POST("/posts/:id/revisions/:revision/restore", h.RestoreRevision)
+	return r
+}
+
+func TestListRevisions(t *testing.T) {
+	mockUC := new(mockRevisionUsecase)
+	mockUC.On("ListRevisions", mock.Anything, int64(1)).
+		Return([]*entity.PostRevision{{PostID: 1, Revision: 2, EditorName: "alice"}}, nil)
+	mockUC.On("ListRevisions", mock.Anything, int64(9)).
+		Return(nil, repository.ErrPostNotFound)
+	r := newRevisionRouter(mockUC)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/posts/1/revisions", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"editor_name":"alice"`)
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/posts/9/revisions", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	mockUC.AssertExpectations(t)
+}
+
+func TestGetRevision(t *testing.T) {
+	mockUC := new(mockRevisionUsecase)
+	mockUC.On("GetRevision", mock.Anything, int64(1), 2).
+		Return(&entity.PostRevision{PostID: 1, Revision: 2, Content: "Body"}, nil)
+	r := newRevisionRouter(mockUC)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/posts/1/revisions/2", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"content":"Body"`)
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/posts/1/revisions/0", nil))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockUC.AssertExpectations(t)
+}
+
+func TestDiffRevisions(t *testing.T) {
+	mockUC := new(mockRevisionUsecase)
+	mockUC.On("DiffRevisions", mock.Anything, int64(1), 1, 3).
+		Return(&entity.RevisionDiff{PostID: 1, From: 1, To: 3, Diff: "-a\n+b\n"}, nil)
+	r := newRevisionRouter(mockUC)
+
+	tests := []struct {
+		name   string
+		query  string
+		status int
+	}{
+		{"Success", "?from=1&to=3", http.StatusOK},
+		{"Missing to", "?from=1", http.StatusBadRequest},
+		{"Invalid from", "?from=x&to=3", http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/posts/1/revisions/diff"+tt.query, nil))
+			assert.Equal(t, tt.status, w.Code)
+		})
+	}
+	mockUC.AssertExpectations(t)
+}
+
+func TestRestoreRevision(t *testing.T) {
+	mockUC := new(mockRevisionUsecase)
+	mockUC.On("RestoreRevision", mock.Anything, "admin-token", int64(1), 1).
+		Return(&entity.Post{ID: 1, Title: "Old"}, nil)
+	mockUC.On("RestoreRevision", mock.Anything, "user-token", int64(1), 1).
+		Return(nil, apperrors.New(apperrors.ErrPermissionDenied, "only admins can restore revisions"))
+	r := newRevisionRouter(mockUC)
+
+	tests := []struct {
+		name   string
+		auth   string
+		status int
+	}{
+		{"Success", "Bearer admin-token", http.StatusOK},
+		{"Not admin", "Bearer user-token", http.StatusForbidden},
+		{"No auth header", "", http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/posts/1/revisions/1/restore", nil)
+			if tt.auth != "" {
+				req.Header.Set("Authorization", tt.auth)
+			}
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+			assert.Equal(t, tt.status, w.Code)
+		})
+	}
+	mockUC.AssertExpectations(t)
+}
@@ -0,0 +1,89 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Ulyana-kru00/forum-project/forum-servise/internal/entity"
+	"github.com/Ulyana-kru00/forum-project/forum-servise/internal/usecase"
+	"github.com/Ulyana-kru00/forum-project/forum-servise/pkg/logger"
+	"github.com/gin-gonic/gin"
+)
+
+type SearchHandler struct {
+	uc     usecase.SearchUsecaseInterface
+	logger *logger.Logger
+}
+
+func NewSearchHandler(uc usecase.SearchUsecaseInterface, logger *logger.Logger) *SearchHandler {
+	return &SearchHandler{
+		uc:     uc,
+		logger: logger,
+	}
+}
+
+// Search godoc
+// @Summary Search posts and comments
+// @Description Full-text search ranked by relevance, post titles weigh more than content.
+// @Description "quoted text" matches a phrase, word* matches a prefix.
+// @Tags search
+// @Accept json
+// @Produce json
+// @Param q query string true "Search query"
+// @Param type query string false "Result type" Enums(post, comment)
+// @Param author_id query int false "Only results by this author"
+// @Param limit query int false "Results per page (max 100)" default(10)
+// @Param cursor query string false "next_cursor from the previous page"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} entity.ErrorResponse
+// @Failure 500 {object} entity.ErrorResponse
+// @Router /api/v1/search [get]
+func (h *SearchHandler) Search(c *gin.Context) {
+	filter := entity.SearchFilter{
+		Query:  c.Query("q"),
+		Type:   entity.SearchType(c.Query("type")),
+		Cursor: c.Query("cursor"),
+	}
+
+	var err error
+	if v := c.Query("limit"); v != "" {
+		if filter.Limit, err = strconv.Atoi(v); err != nil || filter.Limit <= 0 {
+			writeError(c, invalidQueryParam("limit"))
+			return
+		}
+	}
+	if v := c.Query("author_id"); v != "" {
+		if filter.AuthorID, err = strconv.ParseInt(v, 10, 64); err != nil || filter.AuthorID <= 0 {
+			writeError(c, invalidQueryParam("author_id"))
+			return
+		}
+	}
+
+	page, err := h.uc.Search(c.Request.Context(), filter)
+	if err != nil {
+		logServerError(h.logger, "Search failed", err, "query", filter.Query)
+		writeError(c, err)
+		return
+	}
+
+	response := make([]gin.H, 0, len(page.Results))
+	for _, res := range page.Results {
+		response = append(response, gin.H{
+			"type":        res.Type,
+			"id":          res.ID,
+			"post_id":     res.PostID,
+			"post_title":  res.PostTitle,
+			"snippet":     res.Snippet,
+			"author_id":   res.AuthorID,
+			"author_name": res.AuthorName,
+			"rank":        res.Rank,
+			"created_at":  res.CreatedAt.Format(time.RFC3339),
+		})
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":        response,
+		"next_cursor": page.NextCursor,
+	})
+}
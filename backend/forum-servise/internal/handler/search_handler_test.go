@@ -0,0 +1,75 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Ulyana-kru00/forum-project/forum-servise/internal/entity"
+	"github.com/Ulyana-kru00/forum-project/forum-servise/internal/repository"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type mockSearchUsecase struct {
+	mock.Mock
+}
+
+func (m *mockSearchUsecase) Search(ctx context.Context, filter entity.SearchFilter) (*entity.SearchPage, error) {
+	args := m.Called(ctx, filter)
+	page, _ := args.Get(0).(*entity.SearchPage)
+	return page, args.Error(1)
+}
+
+func TestSearch(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockUC := new(mockSearchUsecase)
+	handler := NewSearchHandler(mockUC, newTestLogger())
+
+	r := gin.Default()
+	r.GET("/search", handler.Search)
+
+	wantFilter := entity.SearchFilter{Query: `"go channels"`, Type: entity.SearchTypePost, AuthorID: 3, Limit: 2, Cursor: "abc"}
+	mockUC.On("Search", mock.Anything, wantFilter).Return(&entity.SearchPage{
+		Results: []*entity.SearchResult{{
+			Type: entity.SearchTypePost, ID: 1, PostID: 1, Snippet: "<mark>go</mark>",
+			AuthorID: 3, AuthorName: "alice", CreatedAt: time.Now(),
+		}},
+		NextCursor: "next",
+	}, nil)
+
+	req, _ := http.NewRequest(http.MethodGet, `/search?q=%22go+channels%22&type=post&author_id=3&limit=2&cursor=abc`, nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"next_cursor":"next"`)
+	assert.Contains(t, w.Body.String(), `"author_name":"alice"`)
+	mockUC.AssertExpectations(t)
+}
+
+func TestSearch_Errors(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockUC := new(mockSearchUsecase)
+	handler := NewSearchHandler(mockUC, newTestLogger())
+
+	r := gin.Default()
+	r.GET("/search", handler.Search)
+
+	mockUC.On("Search", mock.Anything, entity.SearchFilter{Query: "go", Cursor: "bad"}).
+		Return(nil, repository.ErrInvalidCursor)
+
+	for _, query := range []string{"q=go&limit=x", "q=go&author_id=-1", "q=go&cursor=bad"} {
+		req, _ := http.NewRequest(http.MethodGet, "/search?"+query, nil)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code, query)
+	}
+	mockUC.AssertNumberOfCalls(t, "Search", 1)
+}
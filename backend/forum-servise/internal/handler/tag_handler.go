@@ -0,0 +1,149 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/Ulyana-kru00/forum-project/forum-servise/internal/entity"
+	"github.com/Ulyana-kru00/forum-project/forum-servise/internal/usecase"
+	"github.com/Ulyana-kru00/forum-project/forum-servise/pkg/logger"
+	"github.com/gin-gonic/gin"
+)
+
+type TagHandler struct {
+	uc     usecase.TagUsecaseInterface
+	logger *logger.Logger
+}
+
+func NewTagHandler(uc usecase.TagUsecaseInterface, logger *logger.Logger) *TagHandler {
+	return &TagHandler{
+		uc:     uc,
+		logger: logger,
+	}
+}
+
+type renameTagRequest struct {
+	Name string `json:"name" binding:"required" example:"golang"`
+}
+
+type mergeTagRequest struct {
+	Into string `json:"into" binding:"required" example:"go"`
+}
+
+// ListTags godoc
+// @Summary Список тегов
+// @Description Возвращает используемые теги с количеством постов, самые популярные первыми
+// @Tags tags
+// @Produce json
+// @Param q query string false "Начало имени тега"
+// @Param limit query int false "Количество тегов (по умолчанию 50, максимум 200)"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} entity.ErrorResponse
+// @Failure 500 {object} entity.ErrorResponse
+// @Router /api/v1/tags [get]
+func (h *TagHandler) ListTags(c *gin.Context) {
+	filter := entity.TagFilter{Prefix: c.Query("q")}
+	if v := c.Query("limit"); v != "" {
+		var err error
+		if filter.Limit, err = strconv.Atoi(v); err != nil || filter.Limit <= 0 {
+			writeError(c, invalidQueryParam("limit"))
+			return
+		}
+	}
+
+	tags, err := h.uc.ListTags(c.Request.Context(), filter)
+	if err != nil {
+		logServerError(h.logger, "Failed to list tags", err)
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": tags})
+}
+
+// RenameTag godoc
+// @Summary Переименовать тег
+// @Description Только для администраторов. Если новое имя уже занято, теги нужно объединить
+// @Tags tags
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param Authorization header string true "Bearer токен"
+// @Param name path string true "Имя тега"
+// @Param request body renameTagRequest true "Новое имя"
+// @Success 200 {object} entity.Tag
+// @Failure 400 {object} entity.ErrorResponse
+// @Failure 401 {object} entity.ErrorResponse
+// @Failure 403 {object} entity.ErrorResponse
+// @Failure 404 {object} entity.ErrorResponse
+// @Failure 409 {object} entity.ErrorResponse
+// @Failure 500 {object} entity.ErrorResponse
+// @Router /api/v1/tags/{name} [put]
+func (h *TagHandler) RenameTag(c *gin.Context) {
+	token, ok := bearerToken(c)
+	if !ok {
+		return
+	}
+	var request renameTagRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		writeError(c, errInvalidBody)
+		return
+	}
+
+	tag, err := h.uc.RenameTag(c.Request.Context(), token, c.Param("name"), request.Name)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, tag)
+}
+
+// MergeTags godoc
+// @Summary Объединить теги
+// @Description Только для администраторов. Посты с тегом получают тег into, сам тег удаляется
+// @Tags tags
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param Authorization header string true "Bearer токен"
+// @Param name path string true "Имя объединяемого тега"
+// @Param request body mergeTagRequest true "Тег, в который объединить"
+// @Success 200 {object} entity.Tag
+// @Failure 400 {object} entity.ErrorResponse
+// @Failure 401 {object} entity.ErrorResponse
+// @Failure 403 {object} entity.ErrorResponse
+// @Failure 404 {object} entity.ErrorResponse
+// @Failure 500 {object} entity.ErrorResponse
+// @Router /api/v1/tags/{name}/merge [post]
+func (h *TagHandler) MergeTags(c *gin.Context) {
+	token, ok := bearerToken(c)
+	if !ok {
+		return
+	}
+	var request mergeTagRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		writeError(c, errInvalidBody)
+		return
+	}
+
+	tag, err := h.uc.MergeTags(c.Request.Context(), token, c.Param("name"), request.Into)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, tag)
+}
+
+// bearerToken reads the token from the Authorization header, writing the
+// error response itself when the header is missing.
+func bearerToken(c *gin.Context) (string, bool) {
+	authHeader := c.GetHeader("Authorization")
+	if authHeader == "" {
+		writeError(c, errMissingAuthHeader)
+		return "", false
+	}
+	return strings.TrimPrefix(authHeader, "Bearer "), true
+}
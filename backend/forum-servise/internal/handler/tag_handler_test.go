@@ -0,0 +1,121 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"backend.com/forum/apperrors"
+	"github.com/Ulyana-kru00/forum-project/forum-servise/internal/entity"
+	"github.com/Ulyana-kru00/forum-project/forum-servise/internal/repository"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type mockTagUsecase struct {
+	mock.Mock
+}
+
+func (m *mockTagUsecase) ListTags(ctx context.Context, filter entity.TagFilter) ([]*entity.Tag, error) {
+	args := m.Called(ctx, filter)
+	t, _ := args.Get(0).([]*entity.Tag)
+	return t, args.Error(1)
+}
+
+func (m *mockTagUsecase) RenameTag(ctx context.Context, token, name, newName string) (*entity.Tag, error) {
+	args := m.Called(ctx, token, name, newName)
+	t, _ := args.Get(0).(*entity.Tag)
+	return t, args.Error(1)
+}
+
+func (m *mockTagUsecase) MergeTags(ctx context.Context, token, source, target string) (*entity.Tag, error) {
+	args := m.Called(ctx, token, source, target)
+	t, _ := args.Get(0).(*entity.Tag)
+	return t, args.Error(1)
+}
+
+func newTagRouter(uc *mockTagUsecase) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	h := NewTagHandler(uc, newTestLogger())
+
+	r := gin.New()
+	r.GET("/tags", h.ListTags)
+	r.PUT("/tags/:name", h.RenameTag)
+	r.POST("/tags/:name/merge", h.MergeTags)
+	return r
+}
+
+func TestListTags(t *testing.T) {
+	mockUC := new(mockTagUsecase)
+	mockUC.On("ListTags", mock.Anything, entity.TagFilter{Prefix: "go", Limit: 5}).
+		Return([]*entity.Tag{{ID: 1, Name: "golang", PostCount: 3}}, nil)
+	r := newTagRouter(mockUC)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/tags?q=go&limit=5", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"name":"golang"`)
+	assert.Contains(t, w.Body.String(), `"post_count":3`)
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/tags?limit=0", nil))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockUC.AssertExpectations(t)
+}
+
+func TestRenameTag(t *testing.T) {
+	mockUC := new(mockTagUsecase)
+	mockUC.On("RenameTag", mock.Anything, "admin-token", "golang", "go").
+		Return(nil, repository.ErrTagExists)
+	mockUC.On("RenameTag", mock.Anything, "admin-token", "golang", "go-lang").
+		Return(&entity.Tag{ID: 1, Name: "go-lang"}, nil)
+	mockUC.On("RenameTag", mock.Anything, "user-token", mock.Anything, mock.Anything).
+		Return(nil, apperrors.New(apperrors.ErrPermissionDenied, "only admins can manage tags"))
+	r := newTagRouter(mockUC)
+
+	tests := []struct {
+		name   string
+		auth   string
+		body   string
+		status int
+	}{
+		{"Success", "Bearer admin-token", `{"name":"go-lang"}`, http.StatusOK},
+		{"Name taken", "Bearer admin-token", `{"name":"go"}`, http.StatusConflict},
+		{"Not admin", "Bearer user-token", `{"name":"go"}`, http.StatusForbidden},
+		{"No auth header", "", `{"name":"go"}`, http.StatusUnauthorized},
+		{"Missing name", "Bearer admin-token", `{}`, http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPut, "/tags/golang", bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			if tt.auth != "" {
+				req.Header.Set("Authorization", tt.auth)
+			}
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.status, w.Code)
+		})
+	}
+}
+
+func TestMergeTags(t *testing.T) {
+	mockUC := new(mockTagUsecase)
+	mockUC.On("MergeTags", mock.Anything, "admin-token", "golang", "go").
+		Return(&entity.Tag{ID: 2, Name: "go", PostCount: 9}, nil)
+
+	req := httptest.NewRequest(http.MethodPost, "/tags/golang/merge", bytes.NewBufferString(`{"into":"go"}`))
+	req.Header.Set("Authorization", "Bearer admin-token")
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	newTagRouter(mockUC).ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"post_count":9`)
+	mockUC.AssertExpectations(t)
+}
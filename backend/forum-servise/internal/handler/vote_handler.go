@@ -0,0 +1,186 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"backend.com/forum/apperrors"
+	"github.com/Ulyana-kru00/forum-project/forum-servise/internal/usecase"
+	"github.com/Ulyana-kru00/forum-project/forum-servise/pkg/logger"
+	"github.com/gin-gonic/gin"
+)
+
+var errInvalidVote = apperrors.New(apperrors.ErrInvalidArgument, "value must be 1 or -1")
+
+type VoteHandler struct {
+	uc     usecase.VoteUsecaseInterface
+	logger *logger.Logger
+}
+
+func NewVoteHandler(uc usecase.VoteUsecaseInterface, logger *logger.Logger) *VoteHandler {
+	return &VoteHandler{
+		uc:     uc,
+		logger: logger,
+	}
+}
+
+type voteRequest struct {
+	Value int `json:"value" example:"1"`
+}
+
+// VotePost godoc
+// @Summary Vote on a post
+// @Description Upvote (1) or downvote (-1) a post. A user has one vote per post; voting again replaces it
+// @Tags votes
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param Authorization header string true "Bearer token"
+// @Param id path int true "Post ID"
+// @Param request body voteRequest true "Vote"
+// @Success 200 {object} entity.VoteResult
+// @Failure 400 {object} entity.ErrorResponse
+// @Failure 401 {object} entity.ErrorResponse
+// @Failure 404 {object} entity.ErrorResponse
+// @Failure 500 {object} entity.ErrorResponse
+// @Router /api/v1/posts/{id}/vote [put]
+func (h *VoteHandler) VotePost(c *gin.Context) {
+	token, postID, ok := postVoteParams(c)
+	if !ok {
+		return
+	}
+	value, ok := bindVote(c)
+	if !ok {
+		return
+	}
+
+	h.votePost(c, token, postID, value)
+}
+
+// RetractPostVote godoc
+// @Summary Retract a vote on a post
+// @Tags votes
+// @Produce json
+// @Security ApiKeyAuth
+// @Param Authorization header string true "Bearer token"
+// @Param id path int true "Post ID"
+// @Success 200 {object} entity.VoteResult
+// @Failure 400 {object} entity.ErrorResponse
+// @Failure 401 {object} entity.ErrorResponse
+// @Failure 404 {object} entity.ErrorResponse
+// @Failure 500 {object} entity.ErrorResponse
+// @Router /api/v1/posts/{id}/vote [delete]
+func (h *VoteHandler) RetractPostVote(c *gin.Context) {
+	token, postID, ok := postVoteParams(c)
+	if !ok {
+		return
+	}
+
+	h.votePost(c, token, postID, 0)
+}
+
+// VoteComment godoc
+// @Summary Vote on a comment
+// @Description Upvote (1) or downvote (-1) a comment. Deleted comments cannot be voted on
+// @Tags votes
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param Authorization header string true "Bearer token"
+// @Param id path int true "Post ID"
+// @Param commentId path int true "Comment ID"
+// @Param request body voteRequest true "Vote"
+// @Success 200 {object} entity.VoteResult
+// @Failure 400 {object} entity.ErrorResponse
+// @Failure 401 {object} entity.ErrorResponse
+// @Failure 404 {object} entity.ErrorResponse
+// @Failure 500 {object} entity.ErrorResponse
+// @Router /api/v1/posts/{id}/comments/{commentId}/vote [put]
+func (h *VoteHandler) VoteComment(c *gin.Context) {
+	token, postID, commentID, ok := commentRequestParams(c)
+	if !ok {
+		return
+	}
+	value, ok := bindVote(c)
+	if !ok {
+		return
+	}
+
+	h.voteComment(c, token, postID, commentID, value)
+}
+
+// RetractCommentVote godoc
+// @Summary Retract a vote on a comment
+// @Tags votes
+// @Produce json
+// @Security ApiKeyAuth
+// @Param Authorization header string true "Bearer token"
+// @Param id path int true "Post ID"
+// @Param commentId path int true "Comment ID"
+// @Success 200 {object} entity.VoteResult
+// @Failure 400 {object} entity.ErrorResponse
+// @Failure 401 {object} entity.ErrorResponse
+// @Failure 404 {object} entity.ErrorResponse
+// @Failure 500 {object} entity.ErrorResponse
+// @Router /api/v1/posts/{id}/comments/{commentId}/vote [delete]
+func (h *VoteHandler) RetractCommentVote(c *gin.Context) {
+	token, postID, commentID, ok := commentRequestParams(c)
+	if !ok {
+		return
+	}
+
+	h.voteComment(c, token, postID, commentID, 0)
+}
+
+func (h *VoteHandler) votePost(c *gin.Context, token string, postID int64, value int) {
+	result, err := h.uc.VotePost(c.Request.Context(), token, postID, value)
+	if err != nil {
+		logServerError(h.logger, "Failed to vote on post", err, "post_id", postID)
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+func (h *VoteHandler) voteComment(c *gin.Context, token string, postID, commentID int64, value int) {
+	result, err := h.uc.VoteComment(c.Request.Context(), token, postID, commentID, value)
+	if err != nil {
+		logServerError(h.logger, "Failed to vote on comment", err, "comment_id", commentID)
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// postVoteParams reads the bearer token and the post id, writing the error
+// response itself when one is missing or malformed.
+func postVoteParams(c *gin.Context) (token string, postID int64, ok bool) {
+	if token, ok = bearerToken(c); !ok {
+		return "", 0, false
+	}
+
+	postID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		writeError(c, errInvalidPostID)
+		return "", 0, false
+	}
+
+	return token, postID, true
+}
+
+// bindVote reads the vote body. Only 1 and -1 are accepted; retracting is
+// done with DELETE.
+func bindVote(c *gin.Context) (int, bool) {
+	var request voteRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		writeError(c, errInvalidBody)
+		return 0, false
+	}
+	if request.Value != 1 && request.Value != -1 {
+		writeError(c, errInvalidVote)
+		return 0, false
+	}
+	return request.Value, true
+}
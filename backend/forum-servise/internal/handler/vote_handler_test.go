@@ -0,0 +1,118 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Ulyana-kru00/forum-project/forum-servise/internal/entity"
+	"github.com/Ulyana-kru00/forum-project/forum-servise/internal/repository"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type mockVoteUsecase struct {
+	mock.Mock
+}
+
+func (m *mockVoteUsecase) VotePost(ctx context.Context, token string, postID int64, value int) (*entity.VoteResult, error) {
+	args := m.Called(ctx, token, postID, value)
+	r, _ := args.Get(0).(*entity.VoteResult)
+	return r, args.Error(1)
+}
+
+func (m *mockVoteUsecase) VoteComment(ctx context.Context, token string, postID, commentID int64, value int) (*entity.VoteResult, error) {
+	args := m.Called(ctx, token, postID, commentID, value)
+	r, _ := args.Get(0).(*entity.VoteResult)
+	return r, args.Error(1)
+}
+
+func newVoteRouter(uc *mockVoteUsecase) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	h := NewVoteHandler(uc, newTestLogger())
+
+	r := gin.New()
+	r.PUT("/posts/:id/vote", h.VotePost)
+	r.DELETE("/posts/:id/vote", h.RetractPostVote)
+	r.PUT("/posts/:id/comments/:commentId/vote", h.VoteComment)
+	r.DELETE("/posts/:id/comments/:commentId/vote", h.RetractCommentVote)
+	return r
+}
+
+func TestVotePost(t *testing.T) {
+	mockUC := new(mockVoteUsecase)
+	mockUC.On("VotePost", mock.Anything, "user-token", int64(1), -1).
+		Return(&entity.VoteResult{Score: 2, Vote: -1}, nil)
+	mockUC.On("VotePost", mock.Anything, "user-token", int64(9), 1).
+		Return(nil, repository.ErrPostNotFound)
+	r := newVoteRouter(mockUC)
+
+	tests := []struct {
+		name   string
+		auth   string
+		path   string
+		body   string
+		status int
+	}{
+		{"Downvote", "Bearer user-token", "/posts/1/vote", `{"value":-1}`, http.StatusOK},
+		{"No auth header", "", "/posts/1/vote", `{"value":1}`, http.StatusUnauthorized},
+		{"Invalid post id", "Bearer user-token", "/posts/abc/vote", `{"value":1}`, http.StatusBadRequest},
+		{"Zero value", "Bearer user-token", "/posts/1/vote", `{"value":0}`, http.StatusBadRequest},
+		{"Out of range", "Bearer user-token", "/posts/1/vote", `{"value":5}`, http.StatusBadRequest},
+		{"Unknown post", "Bearer user-token", "/posts/9/vote", `{"value":1}`, http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPut, tt.path, bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			if tt.auth != "" {
+				req.Header.Set("Authorization", tt.auth)
+			}
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.status, w.Code)
+		})
+	}
+	mockUC.AssertExpectations(t)
+}
+
+func TestRetractVotes(t *testing.T) {
+	mockUC := new(mockVoteUsecase)
+	mockUC.On("VotePost", mock.Anything, "user-token", int64(1), 0).
+		Return(&entity.VoteResult{Score: 3}, nil)
+	mockUC.On("VoteComment", mock.Anything, "user-token", int64(1), int64(7), 0).
+		Return(&entity.VoteResult{Score: -1}, nil)
+	r := newVoteRouter(mockUC)
+
+	for _, path := range []string{"/posts/1/vote", "/posts/1/comments/7/vote"} {
+		req := httptest.NewRequest(http.MethodDelete, path, nil)
+		req.Header.Set("Authorization", "Bearer user-token")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"vote":0`)
+	}
+	mockUC.AssertExpectations(t)
+}
+
+func TestVoteComment(t *testing.T) {
+	mockUC := new(mockVoteUsecase)
+	mockUC.On("VoteComment", mock.Anything, "user-token", int64(1), int64(7), 1).
+		Return(&entity.VoteResult{Score: 4, Vote: 1}, nil)
+
+	req := httptest.NewRequest(http.MethodPut, "/posts/1/comments/7/vote", bytes.NewBufferString(`{"value":1}`))
+	req.Header.Set("Authorization", "Bearer user-token")
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	newVoteRouter(mockUC).ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"score":4,"vote":1}`, w.Body.String())
+	mockUC.AssertExpectations(t)
+}
@@ -0,0 +1,211 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"backend.com/forum/apperrors"
+	"github.com/Ulyana-kru00/forum-project/forum-servise/internal/entity"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+var (
+	ErrAttachmentNotFound    = apperrors.New(apperrors.ErrNotFound, "attachment not found")
+	ErrAttachmentUnavailable = apperrors.New(apperrors.ErrInvalidArgument, "only your own uploads that are not attached to a post yet can be attached")
+)
+
+type AttachmentRepository interface {
+	CreateAttachment(ctx context.Context, attachment *entity.Attachment, store func(ctx context.Context) error) (int64, error)
+	GetAttachment(ctx context.Context, id int64, includeHidden bool) (*entity.Attachment, error)
+	ListPostAttachments(ctx context.Context, postID int64) ([]*entity.Attachment, error)
+	AttachToPost(ctx context.Context, postID, uploaderID int64, ids []int64) ([]*entity.Attachment, error)
+	DeleteOrphans(ctx context.Context, before time.Time, limit int, remove func(ctx context.Context, hash string) error) (int64, error)
+}
+
+type attachmentRepository struct {
+	db *sqlx.DB
+}
+
+func NewAttachmentRepository(db *sqlx.DB) AttachmentRepository {
+	return &attachmentRepository{db: db}
+}
+
+const attachmentColumns = `id, post_id, uploader_id, hash, filename, content_type, size, width, height, has_thumbnail, created_at`
+
+// lockHash serializes uploads and garbage collection of the same content
+// until the end of tx, so a blob is never deleted while an upload that
+// shares it is being recorded.
+func lockHash(ctx context.Context, tx *sqlx.Tx, hash string) error {
+	_, err := tx.ExecContext(ctx, `SELECT pg_advisory_xact_lock(hashtext($1))`, hash)
+	return err
+}
+
+// CreateAttachment records an upload. store puts the content into the blob
+// store and runs under the lock on its hash, before the row is inserted.
+func (r *attachmentRepository) CreateAttachment(ctx context.Context, attachment *entity.Attachment, store func(ctx context.Context) error) (int64, error) {
+	query := `
+		INSERT INTO attachments (uploader_id, hash, filename, content_type, size, width, height, has_thumbnail)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id, created_at`
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	if err := lockHash(ctx, tx, attachment.Hash); err != nil {
+		return 0, err
+	}
+	if err := store(ctx); err != nil {
+		return 0, err
+	}
+
+	err = tx.QueryRowContext(ctx, query,
+		attachment.UploaderID,
+		attachment.Hash,
+		attachment.Filename,
+		attachment.ContentType,
+		attachment.Size,
+		attachment.Width,
+		attachment.Height,
+		attachment.HasThumbnail,
+	).Scan(&attachment.ID, &attachment.CreatedAt)
+	if err != nil {
+		return 0, err
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return attachment.ID, nil
+}
+
+// GetAttachment returns an upload that is not attached yet, or an
+// attachment of a visible post. Attachments of hidden or deleted posts are
+// only found with includeHidden.
+func (r *attachmentRepository) GetAttachment(ctx context.Context, id int64, includeHidden bool) (*entity.Attachment, error) {
+	query := `
+		SELECT ` + attachmentColumns + `
+		FROM attachments a
+		WHERE a.id = $1 AND (
+			$2 OR a.post_id IS NULL OR EXISTS (
+				SELECT 1 FROM posts p
+				WHERE p.id = a.post_id AND p.deleted_at IS NULL AND p.hidden_at IS NULL
+			)
+		)`
+
+	var attachment entity.Attachment
+	err := r.db.GetContext(ctx, &attachment, query, id, includeHidden)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrAttachmentNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &attachment, nil
+}
+
+func (r *attachmentRepository) ListPostAttachments(ctx context.Context, postID int64) ([]*entity.Attachment, error) {
+	query := `SELECT ` + attachmentColumns + ` FROM attachments WHERE post_id = $1 ORDER BY id`
+
+	attachments := []*entity.Attachment{}
+	if err := r.db.SelectContext(ctx, &attachments, query, postID); err != nil {
+		return nil, err
+	}
+	return attachments, nil
+}
+
+// AttachToPost links uploads of uploaderID that are not attached yet to a
+// post. Either all of ids are attached or none.
+func (r *attachmentRepository) AttachToPost(ctx context.Context, postID, uploaderID int64, ids []int64) ([]*entity.Attachment, error) {
+	query := `
+		UPDATE attachments
+		SET post_id = $1
+		WHERE id = ANY($2) AND uploader_id = $3 AND post_id IS NULL
+		RETURNING ` + attachmentColumns
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	attachments := []*entity.Attachment{}
+	if err := tx.SelectContext(ctx, &attachments, query, postID, pq.Array(ids), uploaderID); err != nil {
+		return nil, err
+	}
+	if len(attachments) != len(ids) {
+		return nil, ErrAttachmentUnavailable
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return attachments, nil
+}
+
+// DeleteOrphans deletes up to limit distinct contents worth of attachments
+// that were uploaded before before and never attached, or whose post is
+// gone. remove is called for each hash no attachment refers to any longer;
+// if it fails, the attachments of that hash are kept for the next run.
+func (r *attachmentRepository) DeleteOrphans(ctx context.Context, before time.Time, limit int, remove func(ctx context.Context, hash string) error) (int64, error) {
+	query := `
+		SELECT DISTINCT hash
+		FROM attachments
+		WHERE post_id IS NULL AND created_at < $1
+		LIMIT $2`
+
+	var hashes []string
+	if err := r.db.SelectContext(ctx, &hashes, query, before, limit); err != nil {
+		return 0, err
+	}
+
+	var deleted int64
+	for _, hash := range hashes {
+		n, err := r.deleteOrphansOf(ctx, hash, before, remove)
+		if err != nil {
+			return deleted, err
+		}
+		deleted += n
+	}
+	return deleted, nil
+}
+
+func (r *attachmentRepository) deleteOrphansOf(ctx context.Context, hash string, before time.Time, remove func(ctx context.Context, hash string) error) (int64, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	if err := lockHash(ctx, tx, hash); err != nil {
+		return 0, err
+	}
+	res, err := tx.ExecContext(ctx, `
+		DELETE FROM attachments
+		WHERE hash = $1 AND post_id IS NULL AND created_at < $2`, hash, before)
+	if err != nil {
+		return 0, err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+
+	var referenced bool
+	if err := tx.GetContext(ctx, &referenced, `SELECT EXISTS(SELECT 1 FROM attachments WHERE hash = $1)`, hash); err != nil {
+		return 0, err
+	}
+	if !referenced {
+		if err := remove(ctx, hash); err != nil {
+			return 0, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return n, nil
+}
@@ -0,0 +1,170 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/Ulyana-kru00/forum-project/forum-servise/internal/entity"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newAttachmentRepoMock(t *testing.T) (AttachmentRepository, sqlmock.Sqlmock) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return NewAttachmentRepository(sqlx.NewDb(db, "sqlmock")), mock
+}
+
+var attachmentTableColumns = []string{"id", "post_id", "uploader_id", "hash", "filename", "content_type", "size", "width", "height", "has_thumbnail", "created_at"}
+
+func TestCreateAttachment(t *testing.T) {
+	repo, mock := newAttachmentRepoMock(t)
+	uploader := int64(5)
+	now := time.Now()
+
+	t.Run("Success", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec(`SELECT pg_advisory_xact_lock\(hashtext\(\$1\)\)`).
+			WithArgs("abc").
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectQuery(`INSERT INTO attachments`).
+			WithArgs(&uploader, "abc", "a.png", "image/png", int64(10), nil, nil, true).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow(3, now))
+		mock.ExpectCommit()
+
+		stored := false
+		a := &entity.Attachment{UploaderID: &uploader, Hash: "abc", Filename: "a.png", ContentType: "image/png", Size: 10, HasThumbnail: true}
+		id, err := repo.CreateAttachment(context.Background(), a, func(ctx context.Context) error {
+			stored = true
+			return nil
+		})
+		require.NoError(t, err)
+		assert.Equal(t, int64(3), id)
+		assert.True(t, stored)
+	})
+
+	t.Run("Store fails", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec(`pg_advisory_xact_lock`).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectRollback()
+
+		a := &entity.Attachment{UploaderID: &uploader, Hash: "abc"}
+		_, err := repo.CreateAttachment(context.Background(), a, func(ctx context.Context) error {
+			return errors.New("disk full")
+		})
+		assert.EqualError(t, err, "disk full")
+	})
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetAttachment(t *testing.T) {
+	repo, mock := newAttachmentRepoMock(t)
+	now := time.Now()
+	query := `FROM attachments a\s+WHERE a.id = \$1 AND \(\s+\$2 OR a.post_id IS NULL OR EXISTS \(\s+` +
+		`SELECT 1 FROM posts p\s+WHERE p.id = a.post_id AND p.deleted_at IS NULL AND p.hidden_at IS NULL`
+
+	t.Run("Visible", func(t *testing.T) {
+		mock.ExpectQuery(query).
+			WithArgs(int64(3), false).
+			WillReturnRows(sqlmock.NewRows(attachmentTableColumns).
+				AddRow(3, 1, 5, "abc", "a.png", "image/png", 10, 64, 32, true, now))
+
+		a, err := repo.GetAttachment(context.Background(), 3, false)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), *a.PostID)
+	})
+
+	t.Run("Hidden or deleted post", func(t *testing.T) {
+		mock.ExpectQuery(query).
+			WithArgs(int64(9), false).
+			WillReturnRows(sqlmock.NewRows(attachmentTableColumns))
+
+		_, err := repo.GetAttachment(context.Background(), 9, false)
+		assert.ErrorIs(t, err, ErrAttachmentNotFound)
+	})
+
+	t.Run("Hidden post for moderators", func(t *testing.T) {
+		mock.ExpectQuery(query).
+			WithArgs(int64(9), true).
+			WillReturnRows(sqlmock.NewRows(attachmentTableColumns).
+				AddRow(9, 2, 5, "abc", "a.png", "image/png", 10, nil, nil, false, now))
+
+		a, err := repo.GetAttachment(context.Background(), 9, true)
+		require.NoError(t, err)
+		assert.Equal(t, int64(9), a.ID)
+	})
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAttachToPost(t *testing.T) {
+	repo, mock := newAttachmentRepoMock(t)
+	now := time.Now()
+
+	t.Run("Success", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(`UPDATE attachments\s+SET post_id = \$1\s+WHERE id = ANY\(\$2\) AND uploader_id = \$3 AND post_id IS NULL`).
+			WithArgs(int64(1), sqlmock.AnyArg(), int64(5)).
+			WillReturnRows(sqlmock.NewRows(attachmentTableColumns).
+				AddRow(3, 1, 5, "abc", "a.png", "image/png", 10, 64, 32, true, now))
+		mock.ExpectCommit()
+
+		attachments, err := repo.AttachToPost(context.Background(), 1, 5, []int64{3})
+		require.NoError(t, err)
+		require.Len(t, attachments, 1)
+		assert.Equal(t, int64(1), *attachments[0].PostID)
+		assert.Equal(t, 64, *attachments[0].Width)
+	})
+
+	t.Run("Someone else's upload", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(`UPDATE attachments`).
+			WillReturnRows(sqlmock.NewRows(attachmentTableColumns).
+				AddRow(3, 1, 5, "abc", "a.png", "image/png", 10, nil, nil, false, now))
+		mock.ExpectRollback()
+
+		_, err := repo.AttachToPost(context.Background(), 1, 5, []int64{3, 4})
+		assert.ErrorIs(t, err, ErrAttachmentUnavailable)
+	})
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDeleteOrphans(t *testing.T) {
+	repo, mock := newAttachmentRepoMock(t)
+	before := time.Now()
+
+	mock.ExpectQuery(`SELECT DISTINCT hash\s+FROM attachments\s+WHERE post_id IS NULL AND created_at < \$1`).
+		WithArgs(before, 100).
+		WillReturnRows(sqlmock.NewRows([]string{"hash"}).AddRow("shared").AddRow("lonely"))
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`pg_advisory_xact_lock`).WithArgs("shared").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(`DELETE FROM attachments`).WithArgs("shared", before).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(`SELECT EXISTS`).WithArgs("shared").WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+	mock.ExpectCommit()
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`pg_advisory_xact_lock`).WithArgs("lonely").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(`DELETE FROM attachments`).WithArgs("lonely", before).WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectQuery(`SELECT EXISTS`).WithArgs("lonely").WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+	mock.ExpectCommit()
+
+	var removed []string
+	n, err := repo.DeleteOrphans(context.Background(), before, 100, func(ctx context.Context, hash string) error {
+		removed = append(removed, hash)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), n)
+	assert.Equal(t, []string{"lonely"}, removed, "content still referenced elsewhere is kept")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
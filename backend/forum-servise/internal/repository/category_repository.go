@@ -0,0 +1,141 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"backend.com/forum/apperrors"
+	"github.com/Ulyana-kru00/forum-project/forum-servise/internal/entity"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+var (
+	ErrCategoryNotFound   = apperrors.New(apperrors.ErrNotFound, "category not found")
+	ErrCategorySlugExists = apperrors.New(apperrors.ErrAlreadyExists, "category slug already taken")
+)
+
+// PostgreSQL SQLSTATE codes for constraint failures.
+const (
+	uniqueViolation     = "23505"
+	foreignKeyViolation = "23503"
+)
+
+type CategoryRepository interface {
+	CreateCategory(ctx context.Context, category *entity.Category) (int64, error)
+	UpdateCategory(ctx context.Context, category *entity.Category) error
+	GetCategoryByID(ctx context.Context, id int64) (*entity.Category, error)
+	GetCategoryBySlug(ctx context.Context, slug string) (*entity.Category, error)
+	ListCategories(ctx context.Context) ([]*entity.Category, error)
+}
+
+type categoryRepository struct {
+	db *sqlx.DB
+}
+
+func NewCategoryRepository(db *sqlx.DB) CategoryRepository {
+	return &categoryRepository{db: db}
+}
+
+// categorySelect reads categories together with the statistics of their
+// visible posts, leaving out trashed and hidden ones as listings do.
+const categorySelect = `
+		SELECT
+			c.id,
+			c.name,
+			c.slug,
+			c.description,
+			c.position,
+			c.created_at,
+			COUNT(p.id) AS post_count,
+			MAX(p.last_activity_at) AS last_activity_at
+		FROM categories c
+		LEFT JOIN posts p ON p.category_id = c.id AND p.deleted_at IS NULL AND p.hidden_at IS NULL`
+
+func (r *categoryRepository) CreateCategory(ctx context.Context, category *entity.Category) (int64, error) {
+	query := `
+		INSERT INTO categories (name, slug, description, position)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, created_at`
+
+	err := r.db.QueryRowContext(ctx, query,
+		category.Name,
+		category.Slug,
+		category.Description,
+		category.Position,
+	).Scan(&category.ID, &category.CreatedAt)
+	if err != nil {
+		if isPQError(err, uniqueViolation) {
+			return 0, ErrCategorySlugExists
+		}
+		return 0, err
+	}
+	return category.ID, nil
+}
+
+func (r *categoryRepository) UpdateCategory(ctx context.Context, category *entity.Category) error {
+	query := `
+		UPDATE categories
+		SET name = $1, slug = $2, description = $3, position = $4
+		WHERE id = $5
+		RETURNING created_at`
+
+	err := r.db.QueryRowContext(ctx, query,
+		category.Name,
+		category.Slug,
+		category.Description,
+		category.Position,
+		category.ID,
+	).Scan(&category.CreatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrCategoryNotFound
+		}
+		if isPQError(err, uniqueViolation) {
+			return ErrCategorySlugExists
+		}
+		return err
+	}
+	return nil
+}
+
+func (r *categoryRepository) GetCategoryByID(ctx context.Context, id int64) (*entity.Category, error) {
+	return r.getCategory(ctx, categorySelect+`
+		WHERE c.id = $1
+		GROUP BY c.id`, id)
+}
+
+func (r *categoryRepository) GetCategoryBySlug(ctx context.Context, slug string) (*entity.Category, error) {
+	return r.getCategory(ctx, categorySelect+`
+		WHERE c.slug = $1
+		GROUP BY c.id`, slug)
+}
+
+func (r *categoryRepository) getCategory(ctx context.Context, query string, arg interface{}) (*entity.Category, error) {
+	var category entity.Category
+	if err := r.db.GetContext(ctx, &category, query, arg); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrCategoryNotFound
+		}
+		return nil, err
+	}
+	return &category, nil
+}
+
+func (r *categoryRepository) ListCategories(ctx context.Context) ([]*entity.Category, error) {
+	query := categorySelect + `
+		GROUP BY c.id
+		ORDER BY c.position, c.id`
+
+	categories := []*entity.Category{}
+	if err := r.db.SelectContext(ctx, &categories, query); err != nil {
+		return nil, err
+	}
+	return categories, nil
+}
+
+func isPQError(err error, code pq.ErrorCode) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == code
+}
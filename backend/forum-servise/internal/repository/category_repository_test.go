@@ -0,0 +1,143 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/Ulyana-kru00/forum-project/forum-servise/internal/entity"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newCategoryRepoMock(t *testing.T) (CategoryRepository, sqlmock.Sqlmock) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return NewCategoryRepository(sqlx.NewDb(db, "sqlmock")), mock
+}
+
+var categoryColumns = []string{"id", "name", "slug", "description", "position", "created_at", "post_count", "last_activity_at"}
+
+func TestCreateCategory(t *testing.T) {
+	repo, mock := newCategoryRepoMock(t)
+	now := time.Now()
+
+	t.Run("Success", func(t *testing.T) {
+		category := &entity.Category{Name: "Go", Slug: "go", Description: "About Go", Position: 2}
+		mock.ExpectQuery(`INSERT INTO categories`).
+			WithArgs("Go", "go", "About Go", 2).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow(5, now))
+
+		id, err := repo.CreateCategory(context.Background(), category)
+		require.NoError(t, err)
+		assert.Equal(t, int64(5), id)
+		assert.Equal(t, now, category.CreatedAt)
+	})
+
+	t.Run("Duplicate slug", func(t *testing.T) {
+		mock.ExpectQuery(`INSERT INTO categories`).
+			WillReturnError(&pq.Error{Code: uniqueViolation})
+
+		_, err := repo.CreateCategory(context.Background(), &entity.Category{Name: "Go", Slug: "go"})
+		assert.ErrorIs(t, err, ErrCategorySlugExists)
+	})
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdateCategory(t *testing.T) {
+	repo, mock := newCategoryRepoMock(t)
+	now := time.Now()
+
+	t.Run("Success", func(t *testing.T) {
+		category := &entity.Category{ID: 1, Name: "Go", Slug: "go", Position: 1}
+		mock.ExpectQuery(`UPDATE categories`).
+			WithArgs("Go", "go", "", 1, int64(1)).
+			WillReturnRows(sqlmock.NewRows([]string{"created_at"}).AddRow(now))
+
+		require.NoError(t, repo.UpdateCategory(context.Background(), category))
+		assert.Equal(t, now, category.CreatedAt)
+	})
+
+	t.Run("Not found", func(t *testing.T) {
+		mock.ExpectQuery(`UPDATE categories`).WillReturnError(sql.ErrNoRows)
+
+		err := repo.UpdateCategory(context.Background(), &entity.Category{ID: 9, Name: "x", Slug: "x"})
+		assert.ErrorIs(t, err, ErrCategoryNotFound)
+	})
+
+	t.Run("Duplicate slug", func(t *testing.T) {
+		mock.ExpectQuery(`UPDATE categories`).WillReturnError(&pq.Error{Code: uniqueViolation})
+
+		err := repo.UpdateCategory(context.Background(), &entity.Category{ID: 1, Name: "x", Slug: "taken"})
+		assert.ErrorIs(t, err, ErrCategorySlugExists)
+	})
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetCategory(t *testing.T) {
+	repo, mock := newCategoryRepoMock(t)
+	now := time.Now()
+
+	t.Run("By slug with stats", func(t *testing.T) {
+		mock.ExpectQuery(`LEFT JOIN posts p ON p.category_id = c.id AND p.deleted_at IS NULL AND p.hidden_at IS NULL\s+WHERE c.slug = \$1\s+GROUP BY c.id`).
+			WithArgs("go").
+			WillReturnRows(sqlmock.NewRows(categoryColumns).AddRow(1, "Go", "go", "", 0, now, 3, now))
+
+		got, err := repo.GetCategoryBySlug(context.Background(), "go")
+		require.NoError(t, err)
+		assert.Equal(t, int64(3), got.PostCount)
+		require.NotNil(t, got.LastActivityAt)
+		assert.Equal(t, now, *got.LastActivityAt)
+	})
+
+	t.Run("By id without posts", func(t *testing.T) {
+		mock.ExpectQuery(`WHERE c.id = \$1`).
+			WithArgs(int64(2)).
+			WillReturnRows(sqlmock.NewRows(categoryColumns).AddRow(2, "Empty", "empty", "", 1, now, 0, nil))
+
+		got, err := repo.GetCategoryByID(context.Background(), 2)
+		require.NoError(t, err)
+		assert.Equal(t, int64(0), got.PostCount)
+		assert.Nil(t, got.LastActivityAt)
+	})
+
+	t.Run("Not found", func(t *testing.T) {
+		mock.ExpectQuery(`WHERE c.id = \$1`).WillReturnError(sql.ErrNoRows)
+
+		_, err := repo.GetCategoryByID(context.Background(), 3)
+		assert.ErrorIs(t, err, ErrCategoryNotFound)
+	})
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestListCategories(t *testing.T) {
+	repo, mock := newCategoryRepoMock(t)
+	now := time.Now()
+
+	mock.ExpectQuery(`GROUP BY c.id\s+ORDER BY c.position, c.id`).
+		WillReturnRows(sqlmock.NewRows(categoryColumns).
+			AddRow(1, "General", "general", "", 0, now, 10, now).
+			AddRow(2, "Go", "go", "", 1, now, 0, nil))
+
+	got, err := repo.ListCategories(context.Background())
+	require.NoError(t, err)
+	require.Len(t, got, 2)
+	assert.Equal(t, "general", got[0].Slug)
+	assert.Equal(t, int64(10), got[0].PostCount)
+
+	mock.ExpectQuery(`FROM categories`).WillReturnError(sql.ErrConnDone)
+	_, err = repo.ListCategories(context.Background())
+	assert.ErrorIs(t, err, sql.ErrConnDone)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
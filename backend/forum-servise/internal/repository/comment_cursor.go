@@ -0,0 +1,34 @@
+package repository
+
+import (
+	"encoding/base64"
+	"encoding/json"
+
+	"github.com/Ulyana-kru00/forum-project/forum-servise/internal/entity"
+)
+
+// commentCursor is the keyset position after the last first-level comment
+// of a thread page. Score is only set for CommentSortTop.
+type commentCursor struct {
+	Sort  entity.CommentSort `json:"s"`
+	Score int64              `json:"c,omitempty"`
+	ID    int64              `json:"i"`
+}
+
+func (c commentCursor) encode() string {
+	raw, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+// decodeCommentCursor parses s and checks that it was issued for sort.
+func decodeCommentCursor(s string, sort entity.CommentSort) (commentCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return commentCursor{}, ErrInvalidCursor
+	}
+	var c commentCursor
+	if err := json.Unmarshal(raw, &c); err != nil || c.Sort != sort || c.ID <= 0 {
+		return commentCursor{}, ErrInvalidCursor
+	}
+	return c, nil
+}
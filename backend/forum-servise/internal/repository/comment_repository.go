@@ -4,14 +4,26 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"fmt"
 
+	"backend.com/forum/apperrors"
 	"github.com/Ulyana-kru00/forum-project/forum-servise/internal/entity"
 	"github.com/jmoiron/sqlx"
 )
 
+var ErrCommentNotFound = apperrors.New(apperrors.ErrNotFound, "comment not found")
+
+// MaxCommentDepth is the deepest level a reply may have; top-level comments
+// have depth 0.
+const MaxCommentDepth = 8
+
 type CommentRepository interface {
 	CreateComment(ctx context.Context, comment *entity.Comment) error
+	GetCommentByID(ctx context.Context, id int64) (*entity.Comment, error)
 	GetCommentsByPostID(ctx context.Context, postID int64) ([]entity.Comment, error)
+	GetCommentTree(ctx context.Context, filter entity.CommentTreeFilter) (*entity.CommentPage, error)
+	UpdateComment(ctx context.Context, id, postID, authorID int64, role, content, contentHTML string) (*entity.Comment, error)
+	DeleteComment(ctx context.Context, id, postID, authorID int64, role string) error
 }
 
 type CommentRepo struct {
@@ -23,24 +35,57 @@ func NewCommentRepository(db *sqlx.DB) CommentRepository {
 }
 
 func (r *CommentRepo) CreateComment(ctx context.Context, comment *entity.Comment) error {
-	query := `INSERT INTO comments (content, author_id, post_id, author_name) 
-        VALUES ($1, $2, $3, $4) RETURNING id`
+	query := `INSERT INTO comments (content, content_html, author_id, post_id, author_name, parent_id) 
+        VALUES ($1, $2, $3, $4, $5, $6) RETURNING id`
 	return r.db.QueryRowContext(ctx, query,
 		comment.Content,
+		comment.ContentHTML,
 		comment.AuthorID,
 		comment.PostID,
 		comment.AuthorName,
+		comment.ParentID,
 	).Scan(&comment.ID)
 }
 
+// GetCommentByID returns a comment together with its depth in the thread.
+// The content of a deleted or hidden comment is not redacted.
+func (r *CommentRepo) GetCommentByID(ctx context.Context, id int64) (*entity.Comment, error) {
+	query := `
+		WITH RECURSIVE ancestors AS (
+			SELECT parent_id FROM comments WHERE id = $1
+			UNION ALL
+			SELECT c.parent_id FROM comments c JOIN ancestors a ON c.id = a.parent_id
+		)
+		SELECT
+			c.id, c.content, c.content_html, c.author_id, c.post_id, c.parent_id, c.author_name,
+			c.created_at, c.edited_at, c.deleted_at IS NOT NULL AS deleted, c.hidden_at IS NOT NULL AS hidden,
+			(SELECT COUNT(*) FROM ancestors WHERE parent_id IS NOT NULL) AS depth
+		FROM comments c
+		WHERE c.id = $1`
+
+	var comment entity.Comment
+	if err := r.db.GetContext(ctx, &comment, query, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrCommentNotFound
+		}
+		return nil, err
+	}
+	return &comment, nil
+}
+
 func (r *CommentRepo) GetCommentsByPostID(ctx context.Context, postID int64) ([]entity.Comment, error) {
 	query := `
         SELECT 
             id,
             content,
+            content_html,
             author_id,
             post_id,
-            author_name
+            author_name,
+            created_at,
+            edited_at,
+            deleted_at IS NOT NULL AS deleted,
+            hidden_at IS NOT NULL AS hidden
         FROM comments 
         WHERE post_id = $1
         ORDER BY id DESC`
@@ -53,5 +98,220 @@ func (r *CommentRepo) GetCommentsByPostID(ctx context.Context, postID int64) ([]
 		}
 		return nil, err
 	}
+	for i := range comments {
+		redactComment(&comments[i])
+	}
 	return comments, nil
 }
+
+// commentTreeRow is a row of the thread query; TopRank is the position of
+// the first-level ancestor in the page.
+type commentTreeRow struct {
+	entity.Comment
+	TopRank int `db:"top_rank"`
+}
+
+// GetCommentTree returns one page of a thread as a flattened tree ordered
+// for display. First-level comments are paged with the cursor; below them at
+// most RepliesLimit replies are returned per comment, down to
+// MaxCommentDepth. Remaining replies are loaded with ParentID set to the
+// comment whose ReplyCount exceeds what was returned.
+//
+// Deleted and hidden comments without replies are left out.
+func (r *CommentRepo) GetCommentTree(ctx context.Context, filter entity.CommentTreeFilter) (*entity.CommentPage, error) {
+	if filter.Sort == "" {
+		filter.Sort = entity.CommentSortNewest
+	}
+	if !filter.Sort.Valid() {
+		return nil, apperrors.New(apperrors.ErrInvalidArgument, "unknown sort: "+string(filter.Sort))
+	}
+
+	var args []interface{}
+	arg := func(v interface{}) string {
+		args = append(args, v)
+		return fmt.Sprintf("$%d", len(args))
+	}
+	postID, parentID := arg(filter.PostID), arg(filter.ParentID)
+
+	// Siblings are numbered in display order; the numbers along the way
+	// down from the first level give the order of the whole tree.
+	siblingOrder, firstOrder := "v.id", "r.id"
+	switch {
+	case filter.Sort == entity.CommentSortTop:
+		siblingOrder, firstOrder = "v.score DESC, v.id", "r.score DESC, r.id"
+	case filter.ParentID == 0:
+		firstOrder = "r.id DESC"
+	}
+
+	after := "TRUE"
+	if filter.Cursor != "" {
+		c, err := decodeCommentCursor(filter.Cursor, filter.Sort)
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case filter.Sort == entity.CommentSortTop:
+			score := arg(c.Score)
+			after = fmt.Sprintf("(r.score < %s OR (r.score = %s AND r.id > %s))", score, score, arg(c.ID))
+		case filter.ParentID == 0:
+			after = "r.id < " + arg(c.ID)
+		default:
+			after = "r.id > " + arg(c.ID)
+		}
+	}
+
+	query := fmt.Sprintf(`
+		WITH RECURSIVE ancestors AS (
+			SELECT id, parent_id, 1 AS n FROM comments WHERE id = %[2]s
+			UNION ALL
+			SELECT c.id, c.parent_id, a.n + 1 FROM comments c JOIN ancestors a ON c.id = a.parent_id
+		), base AS (
+			SELECT COUNT(*)::int AS depth, COALESCE(array_agg(id ORDER BY n DESC), '{}'::int[]) AS id_path
+			FROM ancestors
+		), visible AS (
+			SELECT
+				c.id, c.parent_id, c.content, c.content_html, c.author_id, c.post_id, c.author_name,
+				c.created_at, c.edited_at, c.deleted_at IS NOT NULL AS deleted, c.hidden_at IS NOT NULL AS hidden, c.score,
+				(SELECT COUNT(*) FROM comments r WHERE r.parent_id = c.id) AS reply_count
+			FROM comments c
+			WHERE c.post_id = %[1]s
+		), ranked AS (
+			SELECT v.*, row_number() OVER (PARTITION BY v.parent_id ORDER BY %[3]s) AS rn
+			FROM visible v
+			WHERE NOT (v.deleted OR v.hidden) OR v.reply_count > 0
+		), first_level AS (
+			SELECT r.*, row_number() OVER (ORDER BY %[4]s) AS top_rank
+			FROM ranked r
+			WHERE COALESCE(r.parent_id, 0) = %[2]s AND %[5]s
+			ORDER BY %[4]s
+			LIMIT %[6]s
+		), tree AS (
+			SELECT
+				f.id, f.parent_id, f.content, f.content_html, f.author_id, f.post_id, f.author_name,
+				f.created_at, f.edited_at, f.deleted, f.hidden, f.score, f.reply_count, f.top_rank,
+				b.depth, b.id_path || f.id AS id_path, ARRAY[f.top_rank] AS rank_path
+			FROM first_level f CROSS JOIN base b
+			UNION ALL
+			SELECT
+				r.id, r.parent_id, r.content, r.content_html, r.author_id, r.post_id, r.author_name,
+				r.created_at, r.edited_at, r.deleted, r.hidden, r.score, r.reply_count, t.top_rank,
+				t.depth + 1, t.id_path || r.id, t.rank_path || r.rn
+			FROM ranked r JOIN tree t ON r.parent_id = t.id
+			WHERE r.rn <= %[7]s AND t.depth < %[8]s
+		)
+		SELECT
+			id, parent_id, content, content_html, author_id, post_id, author_name, created_at,
+			edited_at, deleted, hidden, score, reply_count, top_rank, depth,
+			array_to_string(id_path, '.') AS path
+		FROM tree
+		ORDER BY rank_path`,
+		postID, parentID, siblingOrder, firstOrder, after,
+		arg(filter.Limit+1), arg(filter.RepliesLimit), arg(MaxCommentDepth))
+
+	var rows []commentTreeRow
+	if err := r.db.SelectContext(ctx, &rows, query, args...); err != nil {
+		return nil, err
+	}
+
+	page := &entity.CommentPage{Comments: make([]entity.Comment, 0, len(rows))}
+	var last *entity.Comment
+	for _, row := range rows {
+		if row.TopRank > filter.Limit {
+			// The extra first-level comment only tells that there is a next page.
+			page.NextCursor = commentCursor{Sort: filter.Sort, Score: last.Score, ID: last.ID}.encode()
+			break
+		}
+		redactComment(&row.Comment)
+		page.Comments = append(page.Comments, row.Comment)
+		if row.TopRank == filter.Limit && last == nil {
+			last = &page.Comments[len(page.Comments)-1]
+		}
+	}
+	return page, nil
+}
+
+// UpdateComment replaces the content of a comment. Only its author or an
+// admin may edit it; deleted comments cannot be edited.
+func (r *CommentRepo) UpdateComment(ctx context.Context, id, postID, authorID int64, role, content, contentHTML string) (*entity.Comment, error) {
+	query := `
+		UPDATE comments
+		SET content = $1, content_html = $2, edited_at = CURRENT_TIMESTAMP
+		WHERE id = $3 AND post_id = $4 AND deleted_at IS NULL
+		AND (author_id = $5 OR $6 = 'admin')
+		RETURNING id, content, content_html, author_id, post_id, author_name, created_at, edited_at`
+
+	var comment entity.Comment
+	err := r.db.GetContext(ctx, &comment, query, content, contentHTML, id, postID, authorID, role)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, r.missingOrForbidden(ctx, id, postID)
+		}
+		return nil, err
+	}
+	return &comment, nil
+}
+
+// DeleteComment removes a comment. A comment that has replies is kept as a
+// placeholder so that the thread below it stays intact, and no longer
+// counts towards the post's comment_count.
+func (r *CommentRepo) DeleteComment(ctx context.Context, id, postID, authorID int64, role string) error {
+	query := `
+		WITH target AS (
+			SELECT c.id, EXISTS (SELECT 1 FROM comments r WHERE r.parent_id = c.id) AS has_replies
+			FROM comments c
+			WHERE c.id = $1 AND c.post_id = $2 AND c.deleted_at IS NULL
+			AND (c.author_id = $3 OR $4 = 'admin')
+		), removed AS (
+			DELETE FROM comments
+			WHERE id IN (SELECT id FROM target WHERE NOT has_replies)
+			RETURNING id
+		), hidden AS (
+			UPDATE comments SET content = '', content_html = '', deleted_at = CURRENT_TIMESTAMP
+			WHERE id IN (SELECT id FROM target WHERE has_replies)
+			RETURNING id, post_id
+		), counted AS (
+			UPDATE posts SET comment_count = comment_count - 1
+			WHERE id IN (SELECT post_id FROM hidden)
+		)
+		SELECT (SELECT COUNT(*) FROM removed) + (SELECT COUNT(*) FROM hidden)`
+
+	var affected int64
+	if err := r.db.GetContext(ctx, &affected, query, id, postID, authorID, role); err != nil {
+		return err
+	}
+	if affected == 0 {
+		return r.missingOrForbidden(ctx, id, postID)
+	}
+	return nil
+}
+
+func (r *CommentRepo) missingOrForbidden(ctx context.Context, id, postID int64) error {
+	var exists bool
+	err := r.db.GetContext(ctx, &exists,
+		`SELECT EXISTS(SELECT 1 FROM comments WHERE id = $1 AND post_id = $2 AND deleted_at IS NULL)`, id, postID)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return ErrPermissionDenied
+	}
+	return ErrCommentNotFound
+}
+
+// redactComment hides the content and author of a deleted comment or one
+// hidden by moderation. Other comments are left as they are.
+func redactComment(comment *entity.Comment) {
+	placeholder := entity.DeletedCommentPlaceholder
+	switch {
+	case comment.Deleted:
+	case comment.Hidden:
+		placeholder = entity.HiddenCommentPlaceholder
+	default:
+		return
+	}
+	comment.Content = placeholder
+	comment.ContentHTML = placeholder
+	comment.AuthorID = 0
+	comment.AuthorName = ""
+	comment.EditedAt = nil
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"testing"
+	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/Ulyana-kru00/forum-project/forum-servise/internal/entity"
@@ -38,7 +39,7 @@ func TestCreateComment(t *testing.T) {
 			},
 			mock: func() {
 				mock.ExpectQuery(`INSERT INTO comments`).
-					WithArgs("Test comment", int64(1), int64(1), "testuser").
+					WithArgs("Test comment", "", int64(1), int64(1), "testuser", nil).
 					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
 			},
 			wantID: 1,
@@ -53,7 +54,7 @@ func TestCreateComment(t *testing.T) {
 			},
 			mock: func() {
 				mock.ExpectQuery(`INSERT INTO comments`).
-					WithArgs("", int64(1), int64(1), "testuser").
+					WithArgs("", "", int64(1), int64(1), "testuser", nil).
 					WillReturnError(sql.ErrConnDone)
 			},
 			wantErr: true,
@@ -152,3 +153,251 @@ func TestGetCommentsByPostID(t *testing.T) {
 		})
 	}
 }
+
+func TestGetCommentsByPostID_RedactsDeleted(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewCommentRepository(sqlx.NewDb(db, "sqlmock"))
+
+	rows := sqlmock.NewRows([]string{"id", "content", "author_id", "post_id", "author_name", "deleted"}).
+		AddRow(2, "Reply", 2, 1, "user2", false).
+		AddRow(1, "", 1, 1, "user1", true)
+	mock.ExpectQuery(`SELECT`).WithArgs(int64(1)).WillReturnRows(rows)
+
+	got, err := repo.GetCommentsByPostID(context.Background(), 1)
+	assert.NoError(t, err)
+	assert.Equal(t, "Reply", got[0].Content)
+	assert.Equal(t, entity.Comment{ID: 1, PostID: 1, Content: entity.DeletedCommentPlaceholder, ContentHTML: entity.DeletedCommentPlaceholder, Deleted: true}, got[1])
+}
+
+func TestUpdateComment(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewCommentRepository(sqlx.NewDb(db, "sqlmock"))
+
+	tests := []struct {
+		name    string
+		mock    func()
+		wantErr error
+	}{
+		{
+			name: "Success",
+			mock: func() {
+				mock.ExpectQuery(`UPDATE comments`).
+					WithArgs("New text", "<p>New text</p>\n", int64(5), int64(1), int64(2), "user").
+					WillReturnRows(sqlmock.NewRows([]string{"id", "content", "content_html", "author_id", "post_id", "author_name", "edited_at"}).
+						AddRow(5, "New text", "<p>New text</p>\n", 2, 1, "user2", time.Now()))
+			},
+		},
+		{
+			name: "Not Owner",
+			mock: func() {
+				mock.ExpectQuery(`UPDATE comments`).WillReturnError(sql.ErrNoRows)
+				mock.ExpectQuery(`SELECT EXISTS`).
+					WithArgs(int64(5), int64(1)).
+					WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+			},
+			wantErr: ErrPermissionDenied,
+		},
+		{
+			name: "Not Found",
+			mock: func() {
+				mock.ExpectQuery(`UPDATE comments`).WillReturnError(sql.ErrNoRows)
+				mock.ExpectQuery(`SELECT EXISTS`).
+					WithArgs(int64(5), int64(1)).
+					WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+			},
+			wantErr: ErrCommentNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			got, err := repo.UpdateComment(context.Background(), 5, 1, 2, "user", "New text", "<p>New text</p>\n")
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, "New text", got.Content)
+			assert.Equal(t, "<p>New text</p>\n", got.ContentHTML)
+			assert.NotNil(t, got.EditedAt)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestDeleteComment(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewCommentRepository(sqlx.NewDb(db, "sqlmock"))
+
+	tests := []struct {
+		name    string
+		role    string
+		mock    func()
+		wantErr error
+	}{
+		{
+			name: "Success - Admin",
+			role: "admin",
+			mock: func() {
+				mock.ExpectQuery(`WITH target AS (.+)UPDATE posts SET comment_count = comment_count - 1`).
+					WithArgs(int64(5), int64(1), int64(2), "admin").
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+			},
+		},
+		{
+			name: "Not Owner",
+			role: "user",
+			mock: func() {
+				mock.ExpectQuery(`WITH target AS`).
+					WithArgs(int64(5), int64(1), int64(2), "user").
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+				mock.ExpectQuery(`SELECT EXISTS`).
+					WithArgs(int64(5), int64(1)).
+					WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+			},
+			wantErr: ErrPermissionDenied,
+		},
+		{
+			name: "Not Found",
+			role: "user",
+			mock: func() {
+				mock.ExpectQuery(`WITH target AS`).
+					WithArgs(int64(5), int64(1), int64(2), "user").
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+				mock.ExpectQuery(`SELECT EXISTS`).
+					WithArgs(int64(5), int64(1)).
+					WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+			},
+			wantErr: ErrCommentNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			err := repo.DeleteComment(context.Background(), 5, 1, 2, tt.role)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestGetCommentByID(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewCommentRepository(sqlx.NewDb(db, "sqlmock"))
+
+	mock.ExpectQuery(`WITH RECURSIVE ancestors`).
+		WithArgs(int64(7)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "content", "author_id", "post_id", "parent_id", "author_name", "deleted", "depth"}).
+			AddRow(7, "Reply", 2, 1, 3, "user2", false, 2))
+
+	got, err := repo.GetCommentByID(context.Background(), 7)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, got.Depth)
+	assert.Equal(t, int64(3), *got.ParentID)
+
+	mock.ExpectQuery(`WITH RECURSIVE ancestors`).WithArgs(int64(8)).WillReturnError(sql.ErrNoRows)
+	_, err = repo.GetCommentByID(context.Background(), 8)
+	assert.ErrorIs(t, err, ErrCommentNotFound)
+}
+
+func TestGetCommentTree(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewCommentRepository(sqlx.NewDb(db, "sqlmock"))
+	columns := []string{"id", "parent_id", "content", "author_id", "post_id", "author_name", "deleted", "score", "reply_count", "top_rank", "depth", "path"}
+
+	t.Run("Top level with next page", func(t *testing.T) {
+		mock.ExpectQuery(`(?s)WITH RECURSIVE ancestors.*ORDER BY r.id DESC`).
+			WithArgs(int64(1), int64(0), 3, 3, MaxCommentDepth).
+			WillReturnRows(sqlmock.NewRows(columns).
+				AddRow(9, nil, "Newest", 1, 1, "user1", false, 0, 0, 1, 0, "9").
+				AddRow(5, nil, "", 2, 1, "user2", true, 0, 1, 2, 0, "5").
+				AddRow(6, 5, "Reply", 3, 1, "user3", false, 0, 0, 2, 1, "5.6").
+				AddRow(4, nil, "Extra", 1, 1, "user1", false, 0, 0, 3, 0, "4"))
+
+		page, err := repo.GetCommentTree(context.Background(), entity.CommentTreeFilter{PostID: 1, Limit: 2, RepliesLimit: 3})
+		assert.NoError(t, err)
+		assert.Len(t, page.Comments, 3)
+		assert.Equal(t, commentCursor{Sort: entity.CommentSortNewest, ID: 5}.encode(), page.NextCursor)
+		assert.Equal(t, entity.DeletedCommentPlaceholder, page.Comments[1].Content)
+		assert.Equal(t, int64(0), page.Comments[1].AuthorID)
+		assert.Equal(t, "5.6", page.Comments[2].Path)
+		assert.Equal(t, 1, page.Comments[2].Depth)
+	})
+
+	t.Run("Replies last page", func(t *testing.T) {
+		mock.ExpectQuery(`(?s)WITH RECURSIVE ancestors.*r.id > \$3.*ORDER BY r.id\s+LIMIT`).
+			WithArgs(int64(1), int64(5), int64(6), 3, 2, MaxCommentDepth).
+			WillReturnRows(sqlmock.NewRows(columns).
+				AddRow(7, 5, "Second reply", 1, 1, "user1", false, 0, 0, 1, 1, "5.7"))
+
+		page, err := repo.GetCommentTree(context.Background(), entity.CommentTreeFilter{
+			PostID: 1, ParentID: 5, Cursor: commentCursor{Sort: entity.CommentSortNewest, ID: 6}.encode(), Limit: 2, RepliesLimit: 2,
+		})
+		assert.NoError(t, err)
+		assert.Len(t, page.Comments, 1)
+		assert.Empty(t, page.NextCursor)
+	})
+
+	t.Run("Top sort", func(t *testing.T) {
+		cursor := commentCursor{Sort: entity.CommentSortTop, Score: 4, ID: 3}.encode()
+		mock.ExpectQuery(`(?s)ORDER BY v.score DESC, v.id.*r.score < \$3 OR \(r.score = \$3 AND r.id > \$4\).*ORDER BY r.score DESC, r.id`).
+			WithArgs(int64(1), int64(0), int64(4), int64(3), 2, 3, MaxCommentDepth).
+			WillReturnRows(sqlmock.NewRows(columns).
+				AddRow(8, nil, "Liked", 1, 1, "user1", false, 2, 0, 1, 0, "8").
+				AddRow(2, nil, "Fine", 2, 1, "user2", false, 2, 0, 2, 0, "2"))
+
+		page, err := repo.GetCommentTree(context.Background(), entity.CommentTreeFilter{
+			PostID: 1, Sort: entity.CommentSortTop, Cursor: cursor, Limit: 1, RepliesLimit: 3,
+		})
+		assert.NoError(t, err)
+		assert.Len(t, page.Comments, 1)
+		assert.Equal(t, int64(2), page.Comments[0].Score)
+		assert.Equal(t, commentCursor{Sort: entity.CommentSortTop, Score: 2, ID: 8}.encode(), page.NextCursor)
+	})
+
+	t.Run("Invalid cursor", func(t *testing.T) {
+		_, err := repo.GetCommentTree(context.Background(), entity.CommentTreeFilter{PostID: 1, Cursor: "abc", Limit: 2})
+		assert.ErrorIs(t, err, ErrInvalidCursor)
+
+		// A newest cursor cannot continue a top page.
+		_, err = repo.GetCommentTree(context.Background(), entity.CommentTreeFilter{
+			PostID: 1, Sort: entity.CommentSortTop, Cursor: commentCursor{Sort: entity.CommentSortNewest, ID: 5}.encode(), Limit: 2,
+		})
+		assert.ErrorIs(t, err, ErrInvalidCursor)
+	})
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,359 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"backend.com/forum/apperrors"
+	"github.com/Ulyana-kru00/forum-project/forum-servise/internal/entity"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+var (
+	ErrReportTargetNotFound = apperrors.New(apperrors.ErrNotFound, "reported content not found")
+	ErrAlreadyReported      = apperrors.New(apperrors.ErrAlreadyExists, "you have already reported this content")
+	ErrNoOpenReports        = apperrors.New(apperrors.ErrNotFound, "no open reports for this content")
+)
+
+type ModerationRepository interface {
+	CreateReport(ctx context.Context, report *entity.Report) (int64, error)
+	TargetAuthor(ctx context.Context, target entity.ReportTarget) (int64, error)
+	HideTarget(ctx context.Context, target entity.ReportTarget) error
+	GetQueue(ctx context.Context, filter entity.ModerationQueueFilter) (*entity.ModerationQueuePage, error)
+	ListReports(ctx context.Context, target entity.ReportTarget) ([]*entity.Report, error)
+	Resolve(ctx context.Context, target entity.ReportTarget, action entity.ModerationAction, moderatorID int64, note string) (int64, error)
+	ListWarnings(ctx context.Context, userID int64) ([]*entity.UserWarning, error)
+}
+
+type moderationRepository struct {
+	db *sqlx.DB
+}
+
+func NewModerationRepository(db *sqlx.DB) ModerationRepository {
+	return &moderationRepository{db: db}
+}
+
+// targetTable is where content of a kind lives and which column holds its
+// author.
+type targetTable struct {
+	name   string
+	author string
+}
+
+var targetTables = map[entity.ReportTargetType]targetTable{
+	entity.ReportTargetPost:        {name: "posts", author: "author_id"},
+	entity.ReportTargetComment:     {name: "comments", author: "author_id"},
+	entity.ReportTargetChatMessage: {name: "chat_messages", author: "user_id"},
+}
+
+// reportedContent resolves the target of the reports grouped as g to its
+// author, content and hidden state.
+const reportedContent = `
+		LEFT JOIN LATERAL (
+			SELECT author_id, content, hidden_at FROM posts
+			WHERE g.target_type = 'post' AND id = g.target_id
+			UNION ALL
+			SELECT author_id, content, hidden_at FROM comments
+			WHERE g.target_type = 'comment' AND id = g.target_id AND deleted_at IS NULL
+			UNION ALL
+			SELECT user_id, content, hidden_at FROM chat_messages
+			WHERE g.target_type = 'chat_message' AND id = g.target_id
+		) t ON TRUE`
+
+const reportColumns = `id, target_type, target_id, reporter_id, reason, details, status, action, resolved_by, resolved_at, created_at`
+
+// CreateReport stores an open report and returns how many open reports
+// there are about the same content, this one included.
+func (r *moderationRepository) CreateReport(ctx context.Context, report *entity.Report) (int64, error) {
+	query := `
+		WITH inserted AS (
+			INSERT INTO reports (target_type, target_id, reporter_id, reason, details)
+			VALUES ($1, $2, $3, $4, $5)
+			RETURNING id, status, created_at
+		)
+		SELECT id, status, created_at,
+			(SELECT COUNT(*) FROM reports WHERE target_type = $1 AND target_id = $2 AND status = 'open') + 1 AS open_reports
+		FROM inserted`
+
+	var row struct {
+		ID          int64               `db:"id"`
+		Status      entity.ReportStatus `db:"status"`
+		CreatedAt   time.Time           `db:"created_at"`
+		OpenReports int64               `db:"open_reports"`
+	}
+	err := r.db.GetContext(ctx, &row, query,
+		report.TargetType, report.TargetID, report.ReporterID, report.Reason, report.Details)
+	if isPQError(err, uniqueViolation) {
+		return 0, ErrAlreadyReported
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	report.ID, report.Status, report.CreatedAt = row.ID, row.Status, row.CreatedAt
+	return row.OpenReports, nil
+}
+
+// TargetAuthor returns the author of reported content. Deleted comments
+// and posts in the trash count as missing.
+func (r *moderationRepository) TargetAuthor(ctx context.Context, target entity.ReportTarget) (int64, error) {
+	table, ok := targetTables[target.Type]
+	if !ok {
+		return 0, ErrReportTargetNotFound
+	}
+	query := fmt.Sprintf(`SELECT %s FROM %s WHERE id = $1`, table.author, table.name)
+	if target.Type != entity.ReportTargetChatMessage {
+		query += ` AND deleted_at IS NULL`
+	}
+
+	var authorID int64
+	err := r.db.GetContext(ctx, &authorID, query, target.ID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, ErrReportTargetNotFound
+	}
+	if err != nil {
+		return 0, err
+	}
+	return authorID, nil
+}
+
+// HideTarget takes content out of listings. Hiding hidden content keeps the
+// time it was first hidden.
+func (r *moderationRepository) HideTarget(ctx context.Context, target entity.ReportTarget) error {
+	return hideTarget(ctx, r.db, target)
+}
+
+func hideTarget(ctx context.Context, db sqlx.ExecerContext, target entity.ReportTarget) error {
+	table, ok := targetTables[target.Type]
+	if !ok {
+		return ErrReportTargetNotFound
+	}
+	_, err := db.ExecContext(ctx,
+		fmt.Sprintf(`UPDATE %s SET hidden_at = CURRENT_TIMESTAMP WHERE id = $1 AND hidden_at IS NULL`, table.name),
+		target.ID)
+	return err
+}
+
+// moderationCursor is the keyset position after the last item of a queue
+// page.
+type moderationCursor struct {
+	Status entity.ReportStatus     `json:"s"`
+	Time   time.Time               `json:"t"`
+	Type   entity.ReportTargetType `json:"k"`
+	ID     int64                   `json:"i"`
+}
+
+func (c moderationCursor) encode() string {
+	raw, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+// decodeModerationCursor parses s and checks that it was issued for a
+// queue of status.
+func decodeModerationCursor(s string, status entity.ReportStatus) (moderationCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return moderationCursor{}, ErrInvalidCursor
+	}
+	var c moderationCursor
+	if err := json.Unmarshal(raw, &c); err != nil || c.Status != status || c.ID <= 0 {
+		return moderationCursor{}, ErrInvalidCursor
+	}
+	return c, nil
+}
+
+// GetQueue groups the reports in the filtered state by content and pages
+// through them, content reported first coming first.
+func (r *moderationRepository) GetQueue(ctx context.Context, filter entity.ModerationQueueFilter) (*entity.ModerationQueuePage, error) {
+	if filter.Limit <= 0 {
+		return nil, apperrors.New(apperrors.ErrInvalidArgument, "limit must be positive")
+	}
+
+	args := []interface{}{filter.Status}
+	arg := func(v interface{}) string {
+		args = append(args, v)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	groupWhere := "status = $1"
+	if filter.TargetType != "" {
+		groupWhere += " AND target_type = " + arg(filter.TargetType)
+	}
+	after := ""
+	if filter.Cursor != "" {
+		c, err := decodeModerationCursor(filter.Cursor, filter.Status)
+		if err != nil {
+			return nil, err
+		}
+		after = fmt.Sprintf("\n\t\tWHERE (g.first_reported_at, g.target_type, g.target_id) > (%s, %s, %s)",
+			arg(c.Time), arg(c.Type), arg(c.ID))
+	}
+
+	query := `
+		SELECT
+			g.target_type, g.target_id, g.report_count, g.reasons, g.first_reported_at, g.last_reported_at,
+			t.author_id, COALESCE(LEFT(t.content, 200), '') AS excerpt, COALESCE(t.hidden_at IS NOT NULL, FALSE) AS hidden
+		FROM (
+			SELECT target_type, target_id, COUNT(*) AS report_count, array_agg(DISTINCT reason) AS reasons,
+				MIN(created_at) AS first_reported_at, MAX(created_at) AS last_reported_at
+			FROM reports
+			WHERE ` + groupWhere + `
+			GROUP BY target_type, target_id
+		) g` + reportedContent + after + `
+		ORDER BY g.first_reported_at, g.target_type, g.target_id
+		LIMIT ` + arg(filter.Limit+1)
+
+	var rows []struct {
+		entity.ModerationQueueItem
+		Reasons pq.StringArray `db:"reasons"`
+	}
+	if err := r.db.SelectContext(ctx, &rows, query, args...); err != nil {
+		return nil, err
+	}
+
+	page := &entity.ModerationQueuePage{Items: make([]*entity.ModerationQueueItem, 0, len(rows))}
+	for i := range rows {
+		if i == filter.Limit {
+			last := page.Items[len(page.Items)-1]
+			page.NextCursor = moderationCursor{
+				Status: filter.Status,
+				Time:   last.FirstReportedAt,
+				Type:   last.TargetType,
+				ID:     last.TargetID,
+			}.encode()
+			break
+		}
+		item := rows[i].ModerationQueueItem
+		item.Reasons = rows[i].Reasons
+		page.Items = append(page.Items, &item)
+	}
+	return page, nil
+}
+
+// ListReports returns every report about content, newest first.
+func (r *moderationRepository) ListReports(ctx context.Context, target entity.ReportTarget) ([]*entity.Report, error) {
+	query := `SELECT ` + reportColumns + ` FROM reports WHERE target_type = $1 AND target_id = $2 ORDER BY id DESC`
+
+	reports := []*entity.Report{}
+	if err := r.db.SelectContext(ctx, &reports, query, target.Type, target.ID); err != nil {
+		return nil, err
+	}
+	return reports, nil
+}
+
+// Resolve closes the open reports about content with action and applies
+// the action in the same transaction. It returns how many reports were
+// closed. Content that is already gone is not an error, so its reports can
+// still be closed.
+func (r *moderationRepository) Resolve(ctx context.Context, target entity.ReportTarget, action entity.ModerationAction, moderatorID int64, note string) (int64, error) {
+	table, ok := targetTables[target.Type]
+	if !ok {
+		return 0, ErrReportTargetNotFound
+	}
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	res, err := tx.ExecContext(ctx, `
+		UPDATE reports
+		SET status = $3, action = $4, resolved_by = $5, resolved_at = CURRENT_TIMESTAMP
+		WHERE target_type = $1 AND target_id = $2 AND status = 'open'`,
+		target.Type, target.ID, action.Status(), action, moderatorID)
+	if err != nil {
+		return 0, err
+	}
+	resolved, err := res.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+	if resolved == 0 {
+		return 0, ErrNoOpenReports
+	}
+
+	switch action {
+	case entity.ModerationHide:
+		err = hideTarget(ctx, tx, target)
+	case entity.ModerationDelete:
+		err = deleteTarget(ctx, tx, target, moderatorID)
+	case entity.ModerationWarn:
+		_, err = tx.ExecContext(ctx, fmt.Sprintf(`
+			INSERT INTO user_warnings (user_id, moderator_id, target_type, target_id, note)
+			SELECT %s, $1, $2, $3, $4 FROM %s WHERE id = $3`, table.author, table.name),
+			moderatorID, target.Type, target.ID, note)
+	case entity.ModerationDismiss:
+		// Content a moderator hid on purpose stays hidden; only an
+		// automatic hide is undone.
+		_, err = tx.ExecContext(ctx, fmt.Sprintf(`
+			UPDATE %s SET hidden_at = NULL
+			WHERE id = $1 AND NOT EXISTS (
+				SELECT 1 FROM reports WHERE target_type = $2 AND target_id = $1 AND action = 'hide'
+			)`, table.name),
+			target.ID, target.Type)
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return resolved, nil
+}
+
+// deleteTarget removes reported content. As with DeletePost and
+// DeleteComment, a post goes to the trash and a comment with replies is
+// kept as a placeholder.
+func deleteTarget(ctx context.Context, tx *sqlx.Tx, target entity.ReportTarget, moderatorID int64) error {
+	var query string
+	args := []interface{}{target.ID}
+	switch target.Type {
+	case entity.ReportTargetPost:
+		query = `
+			UPDATE posts SET deleted_at = CURRENT_TIMESTAMP, deleted_by = $2
+			WHERE id = $1 AND deleted_at IS NULL`
+		args = append(args, moderatorID)
+	case entity.ReportTargetComment:
+		query = `
+			WITH target AS (
+				SELECT c.id, EXISTS (SELECT 1 FROM comments r WHERE r.parent_id = c.id) AS has_replies
+				FROM comments c
+				WHERE c.id = $1 AND c.deleted_at IS NULL
+			), removed AS (
+				DELETE FROM comments WHERE id IN (SELECT id FROM target WHERE NOT has_replies)
+			), hidden AS (
+				UPDATE comments SET content = '', content_html = '', deleted_at = CURRENT_TIMESTAMP
+				WHERE id IN (SELECT id FROM target WHERE has_replies)
+				RETURNING post_id
+			)
+			UPDATE posts SET comment_count = comment_count - 1
+			WHERE id IN (SELECT post_id FROM hidden)`
+	case entity.ReportTargetChatMessage:
+		query = `DELETE FROM chat_messages WHERE id = $1`
+	}
+	_, err := tx.ExecContext(ctx, query, args...)
+	return err
+}
+
+// ListWarnings returns the warnings a user got, newest first.
+func (r *moderationRepository) ListWarnings(ctx context.Context, userID int64) ([]*entity.UserWarning, error) {
+	query := `
+		SELECT id, user_id, moderator_id, target_type, target_id, note, created_at
+		FROM user_warnings
+		WHERE user_id = $1
+		ORDER BY id DESC`
+
+	warnings := []*entity.UserWarning{}
+	if err := r.db.SelectContext(ctx, &warnings, query, userID); err != nil {
+		return nil, err
+	}
+	return warnings, nil
+}
@@ -0,0 +1,172 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/Ulyana-kru00/forum-project/forum-servise/internal/entity"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newModerationRepoMock(t *testing.T) (ModerationRepository, sqlmock.Sqlmock) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return NewModerationRepository(sqlx.NewDb(db, "sqlmock")), mock
+}
+
+func TestCreateReport(t *testing.T) {
+	repo, mock := newModerationRepoMock(t)
+	reporter := int64(7)
+	now := time.Now()
+
+	t.Run("Success", func(t *testing.T) {
+		mock.ExpectQuery(`INSERT INTO reports`).
+			WithArgs(entity.ReportTargetComment, int64(42), &reporter, entity.ReportReasonSpam, "links").
+			WillReturnRows(sqlmock.NewRows([]string{"id", "status", "created_at", "open_reports"}).
+				AddRow(3, "open", now, 2))
+
+		report := &entity.Report{TargetType: entity.ReportTargetComment, TargetID: 42, ReporterID: &reporter, Reason: entity.ReportReasonSpam, Details: "links"}
+		open, err := repo.CreateReport(context.Background(), report)
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), open)
+		assert.Equal(t, int64(3), report.ID)
+		assert.Equal(t, entity.ReportStatusOpen, report.Status)
+	})
+
+	t.Run("Already reported", func(t *testing.T) {
+		mock.ExpectQuery(`INSERT INTO reports`).WillReturnError(&pq.Error{Code: uniqueViolation})
+
+		_, err := repo.CreateReport(context.Background(), &entity.Report{TargetType: entity.ReportTargetPost, TargetID: 1, ReporterID: &reporter})
+		assert.ErrorIs(t, err, ErrAlreadyReported)
+	})
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTargetAuthor(t *testing.T) {
+	repo, mock := newModerationRepoMock(t)
+
+	mock.ExpectQuery(`SELECT user_id FROM chat_messages WHERE id = \$1`).
+		WithArgs(int64(9)).
+		WillReturnRows(sqlmock.NewRows([]string{"user_id"}).AddRow(4))
+	author, err := repo.TargetAuthor(context.Background(), entity.ReportTarget{Type: entity.ReportTargetChatMessage, ID: 9})
+	require.NoError(t, err)
+	assert.Equal(t, int64(4), author)
+
+	mock.ExpectQuery(`SELECT author_id FROM comments WHERE id = \$1 AND deleted_at IS NULL`).
+		WithArgs(int64(8)).
+		WillReturnError(sql.ErrNoRows)
+	_, err = repo.TargetAuthor(context.Background(), entity.ReportTarget{Type: entity.ReportTargetComment, ID: 8})
+	assert.ErrorIs(t, err, ErrReportTargetNotFound)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetModerationQueue(t *testing.T) {
+	repo, mock := newModerationRepoMock(t)
+	first := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	columns := []string{"target_type", "target_id", "report_count", "reasons", "first_reported_at", "last_reported_at", "author_id", "excerpt", "hidden"}
+
+	mock.ExpectQuery(`WHERE status = \$1 AND target_type = \$2\s+GROUP BY target_type, target_id.*ORDER BY g.first_reported_at, g.target_type, g.target_id\s+LIMIT \$3`).
+		WithArgs(entity.ReportStatusOpen, entity.ReportTargetPost, 2).
+		WillReturnRows(sqlmock.NewRows(columns).
+			AddRow("post", 1, 3, "{abuse,spam}", first, first, 5, "Лучшее казино", true).
+			AddRow("post", 2, 1, "{other}", first.Add(time.Hour), first.Add(time.Hour), nil, "", false))
+
+	page, err := repo.GetQueue(context.Background(), entity.ModerationQueueFilter{Status: entity.ReportStatusOpen, TargetType: entity.ReportTargetPost, Limit: 1})
+	require.NoError(t, err)
+	require.Len(t, page.Items, 1)
+	assert.Equal(t, []string{"abuse", "spam"}, page.Items[0].Reasons)
+	assert.True(t, page.Items[0].Hidden)
+	assert.NotEmpty(t, page.NextCursor)
+
+	mock.ExpectQuery(`WHERE \(g.first_reported_at, g.target_type, g.target_id\) > \(\$2, \$3, \$4\)`).
+		WithArgs(entity.ReportStatusOpen, first, entity.ReportTargetPost, int64(1), 2).
+		WillReturnRows(sqlmock.NewRows(columns))
+
+	page, err = repo.GetQueue(context.Background(), entity.ModerationQueueFilter{Status: entity.ReportStatusOpen, Limit: 1, Cursor: page.NextCursor})
+	require.NoError(t, err)
+	assert.Empty(t, page.Items)
+	assert.Empty(t, page.NextCursor)
+
+	_, err = repo.GetQueue(context.Background(), entity.ModerationQueueFilter{Status: entity.ReportStatusDismissed, Limit: 1, Cursor: "bad"})
+	assert.ErrorIs(t, err, ErrInvalidCursor)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestResolveReports(t *testing.T) {
+	repo, mock := newModerationRepoMock(t)
+	target := entity.ReportTarget{Type: entity.ReportTargetComment, ID: 42}
+
+	expectResolve := func(action entity.ModerationAction, rows int64) {
+		mock.ExpectBegin()
+		mock.ExpectExec(`UPDATE reports\s+SET status = \$3`).
+			WithArgs(entity.ReportTargetComment, int64(42), action.Status(), action, int64(1)).
+			WillReturnResult(sqlmock.NewResult(0, rows))
+	}
+
+	t.Run("Hide", func(t *testing.T) {
+		expectResolve(entity.ModerationHide, 2)
+		mock.ExpectExec(`UPDATE comments SET hidden_at = CURRENT_TIMESTAMP WHERE id = \$1 AND hidden_at IS NULL`).
+			WithArgs(int64(42)).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		n, err := repo.Resolve(context.Background(), target, entity.ModerationHide, 1, "")
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), n)
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		expectResolve(entity.ModerationDelete, 1)
+		mock.ExpectExec(`DELETE FROM comments WHERE id IN \(SELECT id FROM target WHERE NOT has_replies\).*UPDATE comments SET content = ''.*UPDATE posts SET comment_count = comment_count - 1`).
+			WithArgs(int64(42)).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		_, err := repo.Resolve(context.Background(), target, entity.ModerationDelete, 1, "")
+		require.NoError(t, err)
+	})
+
+	t.Run("Warn", func(t *testing.T) {
+		expectResolve(entity.ModerationWarn, 1)
+		mock.ExpectExec(`INSERT INTO user_warnings .*SELECT author_id, \$1, \$2, \$3, \$4 FROM comments WHERE id = \$3`).
+			WithArgs(int64(1), entity.ReportTargetComment, int64(42), "no ads").
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectCommit()
+
+		_, err := repo.Resolve(context.Background(), target, entity.ModerationWarn, 1, "no ads")
+		require.NoError(t, err)
+	})
+
+	t.Run("Dismiss", func(t *testing.T) {
+		expectResolve(entity.ModerationDismiss, 1)
+		mock.ExpectExec(`UPDATE comments SET hidden_at = NULL\s+WHERE id = \$1 AND NOT EXISTS`).
+			WithArgs(int64(42), entity.ReportTargetComment).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		_, err := repo.Resolve(context.Background(), target, entity.ModerationDismiss, 1, "")
+		require.NoError(t, err)
+	})
+
+	t.Run("No open reports", func(t *testing.T) {
+		expectResolve(entity.ModerationHide, 0)
+		mock.ExpectRollback()
+
+		_, err := repo.Resolve(context.Background(), target, entity.ModerationHide, 1, "")
+		assert.ErrorIs(t, err, ErrNoOpenReports)
+	})
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
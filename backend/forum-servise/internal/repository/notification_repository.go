@@ -0,0 +1,227 @@
+package repository
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+
+	"backend.com/forum/apperrors"
+	"github.com/Ulyana-kru00/forum-project/forum-servise/internal/entity"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+type NotificationRepository interface {
+	CreateNotifications(ctx context.Context, notifications []*entity.Notification) error
+	DisabledTypes(ctx context.Context, userIDs []int64) (map[int64][]entity.NotificationType, error)
+	ListNotifications(ctx context.Context, filter entity.NotificationFilter) (*entity.NotificationPage, error)
+	UnreadCount(ctx context.Context, userID int64) (int64, error)
+	MarkRead(ctx context.Context, userID int64, ids []int64) (int64, error)
+	GetPreferences(ctx context.Context, userID int64) (entity.NotificationPreferences, error)
+	SetPreferences(ctx context.Context, userID int64, prefs entity.NotificationPreferences) error
+}
+
+type notificationRepository struct {
+	db *sqlx.DB
+}
+
+func NewNotificationRepository(db *sqlx.DB) NotificationRepository {
+	return &notificationRepository{db: db}
+}
+
+// CreateNotifications stores notifications in a single statement.
+func (r *notificationRepository) CreateNotifications(ctx context.Context, notifications []*entity.Notification) error {
+	if len(notifications) == 0 {
+		return nil
+	}
+
+	n := len(notifications)
+	userIDs, actorIDs, postIDs, commentIDs := make([]int64, n), make([]int64, n), make([]int64, n), make([]int64, n)
+	types := make([]string, n)
+	for i, notification := range notifications {
+		userIDs[i] = notification.UserID
+		types[i] = string(notification.Type)
+		postIDs[i] = notification.PostID
+		if notification.ActorID != nil {
+			actorIDs[i] = *notification.ActorID
+		}
+		if notification.CommentID != nil {
+			commentIDs[i] = *notification.CommentID
+		}
+	}
+
+	// Zero stands for NULL in the nullable columns, as arrays of ids
+	// cannot hold NULLs through pq.
+	query := `
+		INSERT INTO notifications (user_id, type, actor_id, post_id, comment_id)
+		SELECT n.user_id, n.type, NULLIF(n.actor_id, 0), n.post_id, NULLIF(n.comment_id, 0)
+		FROM unnest($1::int[], $2::text[], $3::int[], $4::int[], $5::int[])
+			AS n(user_id, type, actor_id, post_id, comment_id)`
+
+	_, err := r.db.ExecContext(ctx, query,
+		pq.Array(userIDs), pq.Array(types), pq.Array(actorIDs), pq.Array(postIDs), pq.Array(commentIDs))
+	return err
+}
+
+// DisabledTypes returns the notification types each of userIDs turned off.
+// Users with every type on are left out.
+func (r *notificationRepository) DisabledTypes(ctx context.Context, userIDs []int64) (map[int64][]entity.NotificationType, error) {
+	disabled := make(map[int64][]entity.NotificationType)
+	if len(userIDs) == 0 {
+		return disabled, nil
+	}
+
+	query := `
+		SELECT user_id, type
+		FROM notification_preferences
+		WHERE user_id = ANY($1) AND NOT enabled`
+
+	var rows []struct {
+		UserID int64                   `db:"user_id"`
+		Type   entity.NotificationType `db:"type"`
+	}
+	if err := r.db.SelectContext(ctx, &rows, query, pq.Array(userIDs)); err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		disabled[row.UserID] = append(disabled[row.UserID], row.Type)
+	}
+	return disabled, nil
+}
+
+// notificationCursor is the keyset position after the last notification of
+// a page.
+type notificationCursor struct {
+	ID int64 `json:"i"`
+}
+
+func (c notificationCursor) encode() string {
+	raw, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+func decodeNotificationCursor(s string) (notificationCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return notificationCursor{}, ErrInvalidCursor
+	}
+	var c notificationCursor
+	if err := json.Unmarshal(raw, &c); err != nil || c.ID <= 0 {
+		return notificationCursor{}, ErrInvalidCursor
+	}
+	return c, nil
+}
+
+// ListNotifications returns one page of the notifications of a user, newest
+// first.
+func (r *notificationRepository) ListNotifications(ctx context.Context, filter entity.NotificationFilter) (*entity.NotificationPage, error) {
+	if filter.Limit <= 0 {
+		return nil, apperrors.New(apperrors.ErrInvalidArgument, "limit must be positive")
+	}
+
+	conds := "user_id = $1"
+	args := []interface{}{filter.UserID}
+	if filter.UnreadOnly {
+		conds += " AND read_at IS NULL"
+	}
+	if filter.Cursor != "" {
+		c, err := decodeNotificationCursor(filter.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, c.ID)
+		conds += fmt.Sprintf(" AND id < $%d", len(args))
+	}
+	// One extra row tells whether there is a next page.
+	args = append(args, filter.Limit+1)
+
+	query := fmt.Sprintf(`
+		SELECT id, user_id, type, actor_id, post_id, comment_id, read_at IS NOT NULL AS read, created_at
+		FROM notifications
+		WHERE %s
+		ORDER BY id DESC
+		LIMIT $%d`, conds, len(args))
+
+	notifications := []*entity.Notification{}
+	if err := r.db.SelectContext(ctx, &notifications, query, args...); err != nil {
+		return nil, err
+	}
+
+	page := &entity.NotificationPage{Notifications: notifications}
+	if len(notifications) > filter.Limit {
+		page.Notifications = notifications[:filter.Limit]
+		page.NextCursor = notificationCursor{ID: page.Notifications[filter.Limit-1].ID}.encode()
+	}
+	return page, nil
+}
+
+func (r *notificationRepository) UnreadCount(ctx context.Context, userID int64) (int64, error) {
+	var count int64
+	err := r.db.GetContext(ctx, &count, `SELECT COUNT(*) FROM notifications WHERE user_id = $1 AND read_at IS NULL`, userID)
+	return count, err
+}
+
+// MarkRead marks the given unread notifications of a user read, or all of
+// them when ids is empty, and returns how many changed.
+func (r *notificationRepository) MarkRead(ctx context.Context, userID int64, ids []int64) (int64, error) {
+	query := `UPDATE notifications SET read_at = CURRENT_TIMESTAMP WHERE user_id = $1 AND read_at IS NULL`
+	args := []interface{}{userID}
+	if len(ids) > 0 {
+		query += ` AND id = ANY($2)`
+		args = append(args, pq.Array(ids))
+	}
+
+	result, err := r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// GetPreferences returns the setting of every notification type for a
+// user. Types the user never changed are on.
+func (r *notificationRepository) GetPreferences(ctx context.Context, userID int64) (entity.NotificationPreferences, error) {
+	var rows []struct {
+		Type    entity.NotificationType `db:"type"`
+		Enabled bool                    `db:"enabled"`
+	}
+	err := r.db.SelectContext(ctx, &rows, `SELECT type, enabled FROM notification_preferences WHERE user_id = $1`, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	prefs := make(entity.NotificationPreferences, len(entity.NotificationTypes))
+	for _, t := range entity.NotificationTypes {
+		prefs[t] = true
+	}
+	for _, row := range rows {
+		prefs[row.Type] = row.Enabled
+	}
+	return prefs, nil
+}
+
+// SetPreferences saves the settings in prefs and leaves types missing from
+// it unchanged.
+func (r *notificationRepository) SetPreferences(ctx context.Context, userID int64, prefs entity.NotificationPreferences) error {
+	var types []string
+	var enabled []bool
+	for _, t := range entity.NotificationTypes {
+		if on, ok := prefs[t]; ok {
+			types = append(types, string(t))
+			enabled = append(enabled, on)
+		}
+	}
+	if len(types) == 0 {
+		return nil
+	}
+
+	query := `
+		INSERT INTO notification_preferences (user_id, type, enabled)
+		SELECT $1, p.type, p.enabled
+		FROM unnest($2::text[], $3::boolean[]) AS p(type, enabled)
+		ON CONFLICT (user_id, type) DO UPDATE SET enabled = EXCLUDED.enabled`
+
+	_, err := r.db.ExecContext(ctx, query, userID, pq.Array(types), pq.Array(enabled))
+	return err
+}
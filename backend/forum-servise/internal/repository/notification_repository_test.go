@@ -0,0 +1,146 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/Ulyana-kru00/forum-project/forum-servise/internal/entity"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newNotificationRepoMock(t *testing.T) (NotificationRepository, sqlmock.Sqlmock) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return NewNotificationRepository(sqlx.NewDb(db, "sqlmock")), mock
+}
+
+func TestCreateNotifications(t *testing.T) {
+	repo, mock := newNotificationRepoMock(t)
+	actor, comment := int64(1), int64(21)
+
+	mock.ExpectExec(`INSERT INTO notifications \(user_id, type, actor_id, post_id, comment_id\)\s+SELECT n.user_id, n.type, NULLIF\(n.actor_id, 0\), n.post_id, NULLIF\(n.comment_id, 0\)\s+FROM unnest`).
+		WithArgs(
+			pq.Array([]int64{2, 3}),
+			pq.Array([]string{"post_comment", "mention"}),
+			pq.Array([]int64{1, 1}),
+			pq.Array([]int64{10, 10}),
+			pq.Array([]int64{21, 0}),
+		).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+
+	err := repo.CreateNotifications(context.Background(), []*entity.Notification{
+		{UserID: 2, Type: entity.NotificationPostComment, ActorID: &actor, PostID: 10, CommentID: &comment},
+		{UserID: 3, Type: entity.NotificationMention, ActorID: &actor, PostID: 10},
+	})
+	require.NoError(t, err)
+
+	// Nothing to store: no query.
+	require.NoError(t, repo.CreateNotifications(context.Background(), nil))
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDisabledNotificationTypes(t *testing.T) {
+	repo, mock := newNotificationRepoMock(t)
+
+	mock.ExpectQuery(`SELECT user_id, type\s+FROM notification_preferences\s+WHERE user_id = ANY\(\$1\) AND NOT enabled`).
+		WithArgs(pq.Array([]int64{2, 3})).
+		WillReturnRows(sqlmock.NewRows([]string{"user_id", "type"}).
+			AddRow(2, "mention").
+			AddRow(2, "post_comment"))
+
+	disabled, err := repo.DisabledTypes(context.Background(), []int64{2, 3})
+	require.NoError(t, err)
+	assert.Equal(t, map[int64][]entity.NotificationType{
+		2: {entity.NotificationMention, entity.NotificationPostComment},
+	}, disabled)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestListNotifications(t *testing.T) {
+	repo, mock := newNotificationRepoMock(t)
+	now := time.Now()
+	columns := []string{"id", "user_id", "type", "actor_id", "post_id", "comment_id", "read", "created_at"}
+
+	mock.ExpectQuery(`FROM notifications\s+WHERE user_id = \$1 AND read_at IS NULL\s+ORDER BY id DESC\s+LIMIT \$2`).
+		WithArgs(int64(1), 2).
+		WillReturnRows(sqlmock.NewRows(columns).
+			AddRow(9, 1, "mention", 3, 10, nil, false, now).
+			AddRow(8, 1, "post_comment", 3, 10, 21, false, now))
+
+	page, err := repo.ListNotifications(context.Background(), entity.NotificationFilter{UserID: 1, UnreadOnly: true, Limit: 1})
+	require.NoError(t, err)
+	require.Len(t, page.Notifications, 1)
+	assert.Equal(t, entity.NotificationMention, page.Notifications[0].Type)
+	assert.Nil(t, page.Notifications[0].CommentID)
+	require.NotEmpty(t, page.NextCursor)
+
+	mock.ExpectQuery(`WHERE user_id = \$1 AND id < \$2\s+ORDER BY id DESC\s+LIMIT \$3`).
+		WithArgs(int64(1), int64(9), 2).
+		WillReturnRows(sqlmock.NewRows(columns))
+
+	page, err = repo.ListNotifications(context.Background(), entity.NotificationFilter{UserID: 1, Limit: 1, Cursor: page.NextCursor})
+	require.NoError(t, err)
+	assert.Empty(t, page.Notifications)
+	assert.Empty(t, page.NextCursor)
+
+	_, err = repo.ListNotifications(context.Background(), entity.NotificationFilter{UserID: 1, Limit: 1, Cursor: "bad"})
+	assert.ErrorIs(t, err, ErrInvalidCursor)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMarkNotificationsRead(t *testing.T) {
+	repo, mock := newNotificationRepoMock(t)
+
+	mock.ExpectExec(`UPDATE notifications SET read_at = CURRENT_TIMESTAMP WHERE user_id = \$1 AND read_at IS NULL AND id = ANY\(\$2\)`).
+		WithArgs(int64(1), pq.Array([]int64{8, 9})).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	n, err := repo.MarkRead(context.Background(), 1, []int64{8, 9})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), n)
+
+	mock.ExpectExec(`UPDATE notifications SET read_at = CURRENT_TIMESTAMP WHERE user_id = \$1 AND read_at IS NULL$`).
+		WithArgs(int64(1)).
+		WillReturnResult(sqlmock.NewResult(0, 5))
+	n, err = repo.MarkRead(context.Background(), 1, nil)
+	require.NoError(t, err)
+	assert.Equal(t, int64(5), n)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestNotificationPreferences(t *testing.T) {
+	repo, mock := newNotificationRepoMock(t)
+
+	mock.ExpectQuery(`SELECT type, enabled FROM notification_preferences WHERE user_id = \$1`).
+		WithArgs(int64(1)).
+		WillReturnRows(sqlmock.NewRows([]string{"type", "enabled"}).AddRow("mention", false))
+	prefs, err := repo.GetPreferences(context.Background(), 1)
+	require.NoError(t, err)
+	assert.Equal(t, entity.NotificationPreferences{
+		entity.NotificationPostComment:  true,
+		entity.NotificationCommentReply: true,
+		entity.NotificationMention:      false,
+	}, prefs)
+
+	mock.ExpectExec(`INSERT INTO notification_preferences \(user_id, type, enabled\).*ON CONFLICT \(user_id, type\) DO UPDATE SET enabled = EXCLUDED.enabled`).
+		WithArgs(int64(1), pq.Array([]string{"post_comment", "mention"}), pq.Array([]bool{false, true})).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	err = repo.SetPreferences(context.Background(), 1, entity.NotificationPreferences{
+		entity.NotificationMention:     true,
+		entity.NotificationPostComment: false,
+	})
+	require.NoError(t, err)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
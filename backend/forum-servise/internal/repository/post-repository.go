@@ -4,22 +4,33 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"fmt"
+	"strings"
+	"time"
 
+	"backend.com/forum/apperrors"
 	"github.com/Ulyana-kru00/forum-project/forum-servise/internal/entity"
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 )
 
 var (
-	ErrPostNotFound     = errors.New("post not found")
-	ErrPermissionDenied = errors.New("permission denied")
+	ErrPostNotFound     = apperrors.New(apperrors.ErrNotFound, "post not found")
+	ErrPostNotInTrash   = apperrors.New(apperrors.ErrNotFound, "post is not in the trash")
+	ErrPermissionDenied = apperrors.New(apperrors.ErrPermissionDenied, "permission denied")
 )
 
 type PostRepository interface {
 	CreatePost(ctx context.Context, post *entity.Post) (int64, error)
-	GetPosts(ctx context.Context) ([]*entity.Post, error)
+	GetPosts(ctx context.Context, filter entity.PostFilter) (*entity.PostPage, error)
 	GetPostByID(ctx context.Context, id int64) (*entity.Post, error)
 	DeletePost(ctx context.Context, id, authorID int64, role string) error
-	UpdatePost(ctx context.Context, id, authorID int64, role, title, content string) (*entity.Post, error)
+	UpdatePost(ctx context.Context, id, authorID int64, role, title, content, contentHTML string, categoryID int64, tags []string) (*entity.Post, error)
+	SetPinned(ctx context.Context, id int64, pinned bool) error
+	SetLocked(ctx context.Context, id int64, locked bool) error
+	ListDeletedPosts(ctx context.Context, limit int, cursor string) (*entity.PostPage, error)
+	RestorePost(ctx context.Context, id int64) error
+	PurgeDeletedPosts(ctx context.Context, before time.Time, limit int) (int64, error)
 }
 
 type postRepository struct {
@@ -30,55 +41,245 @@ func NewPostRepository(db *sqlx.DB) PostRepository {
 	return &postRepository{db: db}
 }
 
+// CreatePost inserts a post together with its tags and first revision.
 func (r *postRepository) CreatePost(ctx context.Context, post *entity.Post) (int64, error) {
+	// Posts without a category go to the first one.
 	query := `
-		INSERT INTO posts (title, content, author_id, created_at)
-		VALUES ($1, $2, $3, $4)
-		RETURNING id`
+		INSERT INTO posts (title, content, content_html, author_id, created_at, category_id)
+		VALUES ($1, $2, $3, $4, $5, COALESCE(NULLIF($6, 0), (SELECT id FROM categories ORDER BY position, id LIMIT 1)))
+		RETURNING id, category_id`
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
 
 	var id int64
-	err := r.db.QueryRowContext(ctx, query,
+	err = tx.QueryRowContext(ctx, query,
 		post.Title,
 		post.Content,
+		post.ContentHTML,
 		post.AuthorID,
 		post.CreatedAt,
-	).Scan(&id)
+		post.CategoryID,
+	).Scan(&id, &post.CategoryID)
+	if err != nil {
+		if isPQError(err, foreignKeyViolation) {
+			return 0, ErrCategoryNotFound
+		}
+		return 0, err
+	}
 
-	return id, err
+	if len(post.Tags) > 0 {
+		if err := setPostTags(ctx, tx, id, post.Tags); err != nil {
+			return 0, err
+		}
+	}
+	if err := insertRevision(ctx, tx, id, post.AuthorID, nil); err != nil {
+		return 0, err
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return id, nil
 }
 
-func (r *postRepository) GetPosts(ctx context.Context) ([]*entity.Post, error) {
-	query := `
-		SELECT 
+// postListColumns are the columns of a post in listings.
+const postListColumns = `
 			id,
 			title,
 			content,
+			content_html,
 			author_id,
-			created_at
+			category_id,
+			created_at,
+			comment_count,
+			last_activity_at,
+			score,
+			hot_rank,
+			pinned_at IS NOT NULL AS pinned,
+			locked_at IS NOT NULL AS locked`
+
+// GetPosts returns one page of posts using keyset pagination on the sort
+// column and id. An empty Sort means newest first.
+func (r *postRepository) GetPosts(ctx context.Context, filter entity.PostFilter) (*entity.PostPage, error) {
+	if filter.Sort == "" {
+		filter.Sort = entity.PostSortNewest
+	}
+	key, ok := postSortKeys[filter.Sort]
+	if !ok {
+		return nil, apperrors.New(apperrors.ErrInvalidArgument, "unknown sort: "+string(filter.Sort))
+	}
+	if filter.Limit <= 0 {
+		return nil, apperrors.New(apperrors.ErrInvalidArgument, "limit must be positive")
+	}
+
+	// Posts in the trash and content hidden by moderation stay out of
+	// listings.
+	conds := []string{"deleted_at IS NULL", "hidden_at IS NULL"}
+	var args []interface{}
+	arg := func(v interface{}) string {
+		args = append(args, v)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	if filter.AuthorID != 0 {
+		conds = append(conds, "author_id = "+arg(filter.AuthorID))
+	}
+	if filter.CategoryID != 0 {
+		conds = append(conds, "category_id = "+arg(filter.CategoryID))
+	}
+	if !filter.From.IsZero() {
+		conds = append(conds, "created_at >= "+arg(filter.From))
+	}
+	if !filter.To.IsZero() {
+		conds = append(conds, "created_at < "+arg(filter.To))
+	}
+	if filter.Query != "" {
+		pattern := arg("%" + escapeLike(filter.Query) + "%")
+		conds = append(conds, fmt.Sprintf("(title ILIKE %s OR content ILIKE %s)", pattern, pattern))
+	}
+
+	if len(filter.Tags) > 0 {
+		tagged := `SELECT pt.post_id FROM post_tags pt JOIN tags t ON t.id = pt.tag_id WHERE t.name = ANY(` + arg(pq.Array(filter.Tags)) + `)`
+		if filter.TagMatch != entity.TagMatchAny {
+			// Tag names are distinct, so a post has all of them when it
+			// matches as many as there are.
+			tagged += " GROUP BY pt.post_id HAVING COUNT(*) = " + arg(len(filter.Tags))
+		}
+		conds = append(conds, "id IN ("+tagged+")")
+	}
+
+	// Pinned posts matching the filter come first on the first page and are
+	// not paged through.
+	pinnedWhere, pinnedArgs := strings.Join(append(conds, "pinned_at IS NOT NULL"), " AND "), args
+	conds = append(conds, "pinned_at IS NULL")
+
+	cmp, dir := ">", "ASC"
+	if key.desc {
+		cmp, dir = "<", "DESC"
+	}
+	if filter.Cursor != "" {
+		c, err := decodePostCursor(filter.Cursor, filter.Sort)
+		if err != nil {
+			return nil, err
+		}
+		conds = append(conds, fmt.Sprintf("(%s, id) %s (%s, %s)", key.column, cmp, arg(c.value()), arg(c.ID)))
+	}
+
+	query := `
+		SELECT ` + postListColumns + `
 		FROM posts
-		ORDER BY created_at DESC`
+		WHERE ` + strings.Join(conds, " AND ")
+	// One extra row tells whether there is a next page.
+	query += fmt.Sprintf("\n\t\tORDER BY %s %s, id %s\n\t\tLIMIT %s", key.column, dir, dir, arg(filter.Limit+1))
 
-	var posts []*entity.Post
-	err := r.db.SelectContext(ctx, &posts, query)
-	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			return []*entity.Post{}, nil
+	posts := []*entity.Post{}
+	if err := r.db.SelectContext(ctx, &posts, query, args...); err != nil {
+		return nil, err
+	}
+
+	page := &entity.PostPage{Posts: posts}
+	if len(posts) > filter.Limit {
+		page.Posts = posts[:filter.Limit]
+		page.NextCursor = cursorAfter(filter.Sort, page.Posts[filter.Limit-1]).encode()
+	}
+	if filter.Cursor == "" {
+		pinned := []*entity.Post{}
+		query := `
+		SELECT ` + postListColumns + `
+		FROM posts
+		WHERE ` + pinnedWhere + `
+		ORDER BY pinned_at DESC, id DESC`
+		if err := r.db.SelectContext(ctx, &pinned, query, pinnedArgs...); err != nil {
+			return nil, err
 		}
+		page.Posts = append(pinned, page.Posts...)
+	}
+	if err := r.attachTags(ctx, page.Posts); err != nil {
 		return nil, err
 	}
-	return posts, nil
+	return page, nil
 }
 
+// attachTags fills in the tags of posts, sorted by name.
+func (r *postRepository) attachTags(ctx context.Context, posts []*entity.Post) error {
+	if len(posts) == 0 {
+		return nil
+	}
+
+	ids := make([]int64, 0, len(posts))
+	byID := make(map[int64]*entity.Post, len(posts))
+	for _, post := range posts {
+		post.Tags = []string{}
+		ids = append(ids, post.ID)
+		byID[post.ID] = post
+	}
+
+	query := `
+		SELECT pt.post_id, t.name
+		FROM post_tags pt
+		JOIN tags t ON t.id = pt.tag_id
+		WHERE pt.post_id = ANY($1)
+		ORDER BY t.name`
+
+	var rows []struct {
+		PostID int64  `db:"post_id"`
+		Name   string `db:"name"`
+	}
+	if err := r.db.SelectContext(ctx, &rows, query, pq.Array(ids)); err != nil {
+		return err
+	}
+	for _, row := range rows {
+		byID[row.PostID].Tags = append(byID[row.PostID].Tags, row.Name)
+	}
+	return nil
+}
+
+// setPostTags replaces the tags of a post with tags, creating the ones that
+// do not exist yet. The names must already be normalized.
+func setPostTags(ctx context.Context, tx *sqlx.Tx, postID int64, tags []string) error {
+	// The no-op update makes RETURNING yield the ids of existing tags too.
+	query := `
+		WITH names AS (
+			SELECT DISTINCT unnest($2::text[]) AS name
+		), tag_ids AS (
+			INSERT INTO tags (name)
+			SELECT name FROM names
+			ON CONFLICT (name) DO UPDATE SET name = EXCLUDED.name
+			RETURNING id
+		), removed AS (
+			DELETE FROM post_tags
+			WHERE post_id = $1 AND tag_id NOT IN (SELECT id FROM tag_ids)
+		)
+		INSERT INTO post_tags (post_id, tag_id)
+		SELECT $1, id FROM tag_ids
+		ON CONFLICT DO NOTHING`
+
+	_, err := tx.ExecContext(ctx, query, postID, pq.Array(tags))
+	if isPQError(err, foreignKeyViolation) {
+		return ErrPostNotFound
+	}
+	return err
+}
+
+// GetPostByID returns a post that is neither in the trash nor hidden by
+// moderation. Moderators see hidden posts through the moderation queue;
+// everywhere else such a post does not exist until it is restored.
 func (r *postRepository) GetPostByID(ctx context.Context, id int64) (*entity.Post, error) {
 	query := `
 		SELECT 
 			id,
 			title,
 			content,
+			content_html,
 			author_id,
-			created_at
+			created_at,
+			pinned_at IS NOT NULL AS pinned,
+			locked_at IS NOT NULL AS locked
 		FROM posts
-		WHERE id = $1`
+		WHERE id = $1 AND deleted_at IS NULL AND hidden_at IS NULL`
 
 	var post entity.Post
 	err := r.db.GetContext(ctx, &post, query, id)
@@ -91,10 +292,13 @@ func (r *postRepository) GetPostByID(ctx context.Context, id int64) (*entity.Pos
 	return &post, nil
 }
 
+// DeletePost moves a post of authorID, or any post for admins, to the
+// trash. It is removed for good by PurgeDeletedPosts.
 func (r *postRepository) DeletePost(ctx context.Context, id, authorID int64, role string) error {
 	query := `
-		DELETE FROM posts 
-		WHERE id = $1 
+		UPDATE posts
+		SET deleted_at = CURRENT_TIMESTAMP, deleted_by = $2
+		WHERE id = $1 AND deleted_at IS NULL
 		AND (author_id = $2 OR $3 = 'admin')`
 
 	result, err := r.db.ExecContext(ctx, query, id, authorID, role)
@@ -108,23 +312,35 @@ func (r *postRepository) DeletePost(ctx context.Context, id, authorID int64, rol
 	}
 
 	if rowsAffected == 0 {
-		return ErrPostNotFound
+		return r.missingOrForbidden(ctx, id)
 	}
 
 	return nil
 }
 
-func (r *postRepository) UpdatePost(ctx context.Context, id, authorID int64, role, title, content string) (*entity.Post, error) {
+// UpdatePost changes the title and content of a post of authorID, or of any
+// post for admins, and records the result as a new revision edited by
+// authorID. A non-zero categoryID moves the post to that category; non-nil
+// tags replace the tags of the post.
+func (r *postRepository) UpdatePost(ctx context.Context, id, authorID int64, role, title, content, contentHTML string, categoryID int64, tags []string) (*entity.Post, error) {
 	query := `
 		UPDATE posts
-		SET title = $1, content = $2
-		WHERE id = $3 AND (author_id = $4 OR $5 = 'admin')
-		RETURNING id, title, content, author_id, created_at`
+		SET title = $1, content = $2, content_html = $3, category_id = COALESCE(NULLIF($4, 0), category_id)
+		WHERE id = $5 AND deleted_at IS NULL AND (author_id = $6 OR $7 = 'admin')
+		RETURNING id, title, content, content_html, author_id, category_id, created_at`
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
 
 	var post entity.Post
-	err := r.db.QueryRowContext(ctx, query,
+	err = tx.QueryRowContext(ctx, query,
 		title,
 		content,
+		contentHTML,
+		categoryID,
 		id,
 		authorID,
 		role,
@@ -132,16 +348,173 @@ func (r *postRepository) UpdatePost(ctx context.Context, id, authorID int64, rol
 		&post.ID,
 		&post.Title,
 		&post.Content,
+		&post.ContentHTML,
 		&post.AuthorID,
+		&post.CategoryID,
 		&post.CreatedAt,
 	)
 
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
-			return nil, ErrPostNotFound
+			return nil, r.missingOrForbidden(ctx, id)
+		}
+		if isPQError(err, foreignKeyViolation) {
+			return nil, ErrCategoryNotFound
 		}
 		return nil, err
 	}
 
+	if tags != nil {
+		if err := setPostTags(ctx, tx, id, tags); err != nil {
+			return nil, err
+		}
+		post.Tags = tags
+	}
+	if err := insertRevision(ctx, tx, id, authorID, nil); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
 	return &post, nil
 }
+
+// SetPinned pins or unpins a post. Pinning a pinned post keeps its place
+// among the pinned ones.
+func (r *postRepository) SetPinned(ctx context.Context, id int64, pinned bool) error {
+	return r.setFlag(ctx, "pinned_at", id, pinned)
+}
+
+// SetLocked locks or unlocks a post for new comments.
+func (r *postRepository) SetLocked(ctx context.Context, id int64, locked bool) error {
+	return r.setFlag(ctx, "locked_at", id, locked)
+}
+
+// setFlag sets or clears a timestamp column standing for a flag of a post
+// that is not in the trash.
+func (r *postRepository) setFlag(ctx context.Context, column string, id int64, on bool) error {
+	value := "NULL"
+	if on {
+		value = "COALESCE(" + column + ", CURRENT_TIMESTAMP)"
+	}
+	query := fmt.Sprintf(`UPDATE posts SET %s = %s WHERE id = $1 AND deleted_at IS NULL`, column, value)
+
+	result, err := r.db.ExecContext(ctx, query, id)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return ErrPostNotFound
+	}
+	return nil
+}
+
+// trashSort tags the cursors of the trash listing, which is ordered by
+// deleted_at, most recently deleted first.
+const trashSort entity.PostSort = "trash"
+
+// ListDeletedPosts returns one page of the trash.
+func (r *postRepository) ListDeletedPosts(ctx context.Context, limit int, cursor string) (*entity.PostPage, error) {
+	if limit <= 0 {
+		return nil, apperrors.New(apperrors.ErrInvalidArgument, "limit must be positive")
+	}
+
+	args := []interface{}{limit + 1}
+	after := ""
+	if cursor != "" {
+		c, err := decodePostCursor(cursor, trashSort)
+		if err != nil {
+			return nil, err
+		}
+		after = " AND (deleted_at, id) < ($2, $3)"
+		args = append(args, c.Time, c.ID)
+	}
+
+	query := `
+		SELECT ` + postListColumns + `,
+			deleted_at,
+			deleted_by
+		FROM posts
+		WHERE deleted_at IS NOT NULL` + after + `
+		ORDER BY deleted_at DESC, id DESC
+		LIMIT $1`
+
+	posts := []*entity.Post{}
+	if err := r.db.SelectContext(ctx, &posts, query, args...); err != nil {
+		return nil, err
+	}
+
+	page := &entity.PostPage{Posts: posts}
+	if len(posts) > limit {
+		page.Posts = posts[:limit]
+		last := page.Posts[limit-1]
+		page.NextCursor = postCursor{Sort: trashSort, Time: *last.DeletedAt, ID: last.ID}.encode()
+	}
+	if err := r.attachTags(ctx, page.Posts); err != nil {
+		return nil, err
+	}
+	return page, nil
+}
+
+// RestorePost takes a post out of the trash.
+func (r *postRepository) RestorePost(ctx context.Context, id int64) error {
+	query := `
+		UPDATE posts
+		SET deleted_at = NULL, deleted_by = NULL
+		WHERE id = $1 AND deleted_at IS NOT NULL`
+
+	result, err := r.db.ExecContext(ctx, query, id)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return ErrPostNotInTrash
+	}
+	return nil
+}
+
+// PurgeDeletedPosts removes up to limit posts put in the trash before
+// before, together with their comments, and returns how many were removed.
+func (r *postRepository) PurgeDeletedPosts(ctx context.Context, before time.Time, limit int) (int64, error) {
+	query := `
+		DELETE FROM posts
+		WHERE id IN (
+			SELECT id FROM posts
+			WHERE deleted_at < $1
+			ORDER BY deleted_at
+			LIMIT $2
+		)`
+
+	result, err := r.db.ExecContext(ctx, query, before, limit)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// missingOrForbidden explains why a guarded write touched no rows: the post
+// either does not exist or belongs to someone else.
+func (r *postRepository) missingOrForbidden(ctx context.Context, id int64) error {
+	var exists bool
+	err := r.db.GetContext(ctx, &exists, `SELECT EXISTS(SELECT 1 FROM posts WHERE id = $1 AND deleted_at IS NULL)`, id)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return ErrPermissionDenied
+	}
+	return ErrPostNotFound
+}
+
+// escapeLike escapes the LIKE wildcards in s so it matches literally.
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
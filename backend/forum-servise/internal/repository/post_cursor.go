@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"time"
+
+	"backend.com/forum/apperrors"
+	"github.com/Ulyana-kru00/forum-project/forum-servise/internal/entity"
+)
+
+var ErrInvalidCursor = apperrors.New(apperrors.ErrInvalidArgument, "invalid cursor")
+
+// postCursor is the keyset position after the last post of a page. Only the
+// field matching the sort is set, together with the post id as tie-breaker.
+type postCursor struct {
+	Sort  entity.PostSort `json:"s"`
+	Time  time.Time       `json:"t,omitempty"`
+	Count int64           `json:"c,omitempty"` // comment count or score
+	Rank  float64         `json:"r,omitempty"`
+	ID    int64           `json:"i"`
+}
+
+// postSortKey describes how a sort maps onto the posts table.
+type postSortKey struct {
+	column string
+	desc   bool
+}
+
+var postSortKeys = map[entity.PostSort]postSortKey{
+	entity.PostSortNewest:         {column: "created_at", desc: true},
+	entity.PostSortOldest:         {column: "created_at", desc: false},
+	entity.PostSortMostCommented:  {column: "comment_count", desc: true},
+	entity.PostSortRecentlyActive: {column: "last_activity_at", desc: true},
+	entity.PostSortTop:            {column: "score", desc: true},
+	entity.PostSortHot:            {column: "hot_rank", desc: true},
+}
+
+func cursorAfter(sort entity.PostSort, post *entity.Post) postCursor {
+	c := postCursor{Sort: sort, ID: post.ID}
+	switch sort {
+	case entity.PostSortMostCommented:
+		c.Count = post.CommentCount
+	case entity.PostSortRecentlyActive:
+		c.Time = post.LastActivityAt
+	case entity.PostSortTop:
+		c.Count = post.Score
+	case entity.PostSortHot:
+		c.Rank = post.HotRank
+	default:
+		c.Time = post.CreatedAt
+	}
+	return c
+}
+
+// value returns the sort column value stored in the cursor.
+func (c postCursor) value() interface{} {
+	switch c.Sort {
+	case entity.PostSortMostCommented, entity.PostSortTop:
+		return c.Count
+	case entity.PostSortHot:
+		return c.Rank
+	}
+	return c.Time
+}
+
+func (c postCursor) encode() string {
+	raw, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+// decodePostCursor parses s and checks that it was issued for sort.
+func decodePostCursor(s string, sort entity.PostSort) (postCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return postCursor{}, ErrInvalidCursor
+	}
+	var c postCursor
+	if err := json.Unmarshal(raw, &c); err != nil || c.Sort != sort || c.ID <= 0 {
+		return postCursor{}, ErrInvalidCursor
+	}
+	return c, nil
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"fmt"
 	"reflect"
 	"testing"
 	"time"
@@ -11,7 +12,9 @@ import (
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/Ulyana-kru00/forum-project/forum-servise/internal/entity"
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestCreatePost(t *testing.T) {
@@ -42,12 +45,35 @@ func TestCreatePost(t *testing.T) {
 				CreatedAt: now,
 			},
 			mock: func() {
+				mock.ExpectBegin()
 				mock.ExpectQuery(`INSERT INTO posts`).
-					WithArgs("Test Post", "Test Content", int64(1), now).
-					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+					WithArgs("Test Post", "Test Content", "", int64(1), now, int64(0)).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "category_id"}).AddRow(1, 1))
+				mock.ExpectExec(`INSERT INTO post_revisions`).
+					WithArgs(int64(1), int64(1), nil).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
 			},
 			want: 1,
 		},
+		{
+			name: "Unknown category",
+			post: &entity.Post{
+				Title:      "Test Post",
+				Content:    "Test Content",
+				AuthorID:   1,
+				CategoryID: 99,
+				CreatedAt:  now,
+			},
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery(`INSERT INTO posts`).
+					WithArgs("Test Post", "Test Content", "", int64(1), now, int64(99)).
+					WillReturnError(&pq.Error{Code: foreignKeyViolation})
+				mock.ExpectRollback()
+			},
+			wantErr: true,
+		},
 		{
 			name: "Empty Fields",
 			post: &entity.Post{
@@ -57,9 +83,11 @@ func TestCreatePost(t *testing.T) {
 				CreatedAt: now,
 			},
 			mock: func() {
+				mock.ExpectBegin()
 				mock.ExpectQuery(`INSERT INTO posts`).
-					WithArgs("", "", int64(1), now).
+					WithArgs("", "", "", int64(1), now, int64(0)).
 					WillReturnError(sql.ErrConnDone)
+				mock.ExpectRollback()
 			},
 			wantErr: true,
 		},
@@ -92,52 +120,128 @@ func TestGetPosts(t *testing.T) {
 	repo := NewPostRepository(sqlxDB)
 
 	now := time.Now()
+	columns := []string{"id", "title", "content", "author_id", "created_at", "comment_count", "last_activity_at"}
+	post := func(id int64, createdAt time.Time) *entity.Post {
+		return &entity.Post{
+			ID:             id,
+			Title:          fmt.Sprintf("Post %d", id),
+			Content:        fmt.Sprintf("Content %d", id),
+			AuthorID:       id,
+			CreatedAt:      createdAt,
+			LastActivityAt: createdAt,
+			Tags:           []string{},
+		}
+	}
+	tagColumns := []string{"post_id", "name"}
+	addRow := func(rows *sqlmock.Rows, p *entity.Post) *sqlmock.Rows {
+		return rows.AddRow(p.ID, p.Title, p.Content, p.AuthorID, p.CreatedAt, p.CommentCount, p.LastActivityAt)
+	}
+
+	p1, p2, p3 := post(3, now), post(2, now.Add(-time.Minute)), post(1, now.Add(-2*time.Minute))
+	nextCursor := cursorAfter(entity.PostSortNewest, p2).encode()
+	pinned := post(7, now.Add(-time.Hour))
+	pinned.Pinned = true
 
 	tests := []struct {
 		name    string
+		filter  entity.PostFilter
 		mock    func()
-		want    []*entity.Post
-		wantErr bool
+		want    *entity.PostPage
+		wantErr error
 	}{
 		{
-			name: "Success",
+			name:   "First page has next cursor",
+			filter: entity.PostFilter{Limit: 2},
 			mock: func() {
-				rows := sqlmock.NewRows([]string{"id", "title", "content", "author_id", "created_at"}).
-					AddRow(1, "Post 1", "Content 1", 1, now).
-					AddRow(2, "Post 2", "Content 2", 2, now)
-				mock.ExpectQuery(`SELECT`).WillReturnRows(rows)
+				rows := sqlmock.NewRows(columns)
+				addRow(addRow(addRow(rows, p1), p2), p3)
+				mock.ExpectQuery(`FROM posts\s+WHERE deleted_at IS NULL AND hidden_at IS NULL AND pinned_at IS NULL\s+ORDER BY created_at DESC, id DESC\s+LIMIT \$1`).
+					WithArgs(3).
+					WillReturnRows(rows)
+				mock.ExpectQuery(`WHERE deleted_at IS NULL AND hidden_at IS NULL AND pinned_at IS NOT NULL\s+ORDER BY pinned_at DESC, id DESC`).
+					WillReturnRows(sqlmock.NewRows(append(columns, "pinned")).
+						AddRow(pinned.ID, pinned.Title, pinned.Content, pinned.AuthorID, pinned.CreatedAt, 0, pinned.LastActivityAt, true))
+				mock.ExpectQuery(`FROM post_tags pt\s+JOIN tags t`).
+					WithArgs(pq.Array([]int64{7, 3, 2})).
+					WillReturnRows(sqlmock.NewRows(tagColumns))
 			},
-			want: []*entity.Post{
-				{
-					ID:        1,
-					Title:     "Post 1",
-					Content:   "Content 1",
-					AuthorID:  1,
-					CreatedAt: now,
-				},
-				{
-					ID:        2,
-					Title:     "Post 2",
-					Content:   "Content 2",
-					AuthorID:  2,
-					CreatedAt: now,
-				},
+			want: &entity.PostPage{Posts: []*entity.Post{pinned, p1, p2}, NextCursor: nextCursor},
+		},
+		{
+			name:   "Last page with filters and cursor",
+			filter: entity.PostFilter{Limit: 2, Cursor: nextCursor, AuthorID: 1, From: now.Add(-time.Hour), Query: "50%"},
+			mock: func() {
+				mock.ExpectQuery(`WHERE deleted_at IS NULL AND hidden_at IS NULL AND author_id = \$1 AND created_at >= \$2 AND \(title ILIKE \$3 OR content ILIKE \$3\) AND pinned_at IS NULL AND \(created_at, id\) < \(\$4, \$5\)\s+ORDER BY created_at DESC, id DESC\s+LIMIT \$6`).
+					WithArgs(int64(1), now.Add(-time.Hour), `%50\%%`, sqlmock.AnyArg(), int64(2), 3).
+					WillReturnRows(addRow(sqlmock.NewRows(columns), p3))
+				mock.ExpectQuery(`FROM post_tags pt\s+JOIN tags t`).
+					WithArgs(pq.Array([]int64{1})).
+					WillReturnRows(sqlmock.NewRows(tagColumns))
 			},
+			want: &entity.PostPage{Posts: []*entity.Post{p3}},
 		},
 		{
-			name: "No Posts",
+			name:   "All of several tags",
+			filter: entity.PostFilter{Limit: 2, Tags: []string{"go", "sql"}},
 			mock: func() {
-				rows := sqlmock.NewRows([]string{"id", "title", "content", "author_id", "created_at"})
-				mock.ExpectQuery(`SELECT`).WillReturnRows(rows)
+				mock.ExpectQuery(`WHERE deleted_at IS NULL AND hidden_at IS NULL AND id IN \(SELECT pt.post_id FROM post_tags pt JOIN tags t ON t.id = pt.tag_id WHERE t.name = ANY\(\$1\) GROUP BY pt.post_id HAVING COUNT\(\*\) = \$2\) AND pinned_at IS NULL`).
+					WithArgs(pq.Array([]string{"go", "sql"}), 2, 3).
+					WillReturnRows(addRow(sqlmock.NewRows(columns), post(4, now)))
+				mock.ExpectQuery(`HAVING COUNT\(\*\) = \$2\) AND pinned_at IS NOT NULL`).
+					WithArgs(pq.Array([]string{"go", "sql"}), 2).
+					WillReturnRows(sqlmock.NewRows(columns))
+				mock.ExpectQuery(`FROM post_tags pt\s+JOIN tags t`).
+					WithArgs(pq.Array([]int64{4})).
+					WillReturnRows(sqlmock.NewRows(tagColumns).AddRow(4, "go").AddRow(4, "sql"))
 			},
-			want: []*entity.Post{},
+			want: &entity.PostPage{Posts: []*entity.Post{func() *entity.Post {
+				p := post(4, now)
+				p.Tags = []string{"go", "sql"}
+				return p
+			}()}},
 		},
 		{
-			name: "Error",
+			name:   "Any of several tags",
+			filter: entity.PostFilter{Limit: 2, Tags: []string{"go", "sql"}, TagMatch: entity.TagMatchAny},
+			mock: func() {
+				mock.ExpectQuery(`WHERE deleted_at IS NULL AND hidden_at IS NULL AND id IN \(SELECT pt.post_id FROM post_tags pt JOIN tags t ON t.id = pt.tag_id WHERE t.name = ANY\(\$1\)\) AND pinned_at IS NULL\s+ORDER BY`).
+					WithArgs(pq.Array([]string{"go", "sql"}), 3).
+					WillReturnRows(sqlmock.NewRows(columns))
+				mock.ExpectQuery(`ANY\(\$1\)\) AND pinned_at IS NOT NULL`).
+					WithArgs(pq.Array([]string{"go", "sql"})).
+					WillReturnRows(sqlmock.NewRows(columns))
+			},
+			want: &entity.PostPage{Posts: []*entity.Post{}},
+		},
+		{
+			name:   "Most commented keyset",
+			filter: entity.PostFilter{Limit: 1, Sort: entity.PostSortMostCommented, Cursor: postCursor{Sort: entity.PostSortMostCommented, Count: 5, ID: 9}.encode()},
+			mock: func() {
+				mock.ExpectQuery(`WHERE deleted_at IS NULL AND hidden_at IS NULL AND pinned_at IS NULL AND \(comment_count, id\) < \(\$1, \$2\)\s+ORDER BY comment_count DESC, id DESC`).
+					WithArgs(int64(5), int64(9), 2).
+					WillReturnRows(sqlmock.NewRows(columns))
+			},
+			want: &entity.PostPage{Posts: []*entity.Post{}},
+		},
+		{
+			name:    "Cursor from another sort",
+			filter:  entity.PostFilter{Limit: 2, Sort: entity.PostSortOldest, Cursor: nextCursor},
+			mock:    func() {},
+			wantErr: ErrInvalidCursor,
+		},
+		{
+			name:    "Garbage cursor",
+			filter:  entity.PostFilter{Limit: 2, Cursor: "not-a-cursor"},
+			mock:    func() {},
+			wantErr: ErrInvalidCursor,
+		},
+		{
+			name:   "Error",
+			filter: entity.PostFilter{Limit: 2},
 			mock: func() {
 				mock.ExpectQuery(`SELECT`).WillReturnError(sql.ErrConnDone)
 			},
-			wantErr: true,
+			wantErr: sql.ErrConnDone,
 		},
 	}
 
@@ -145,12 +249,14 @@ func TestGetPosts(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.mock()
 
-			got, err := repo.GetPosts(context.Background())
-			if (err != nil) != tt.wantErr {
-				t.Errorf("GetPosts() error = %v, wantErr %v", err, tt.wantErr)
+			got, err := repo.GetPosts(context.Background(), tt.filter)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
 				return
 			}
+			require.NoError(t, err)
 			assert.Equal(t, tt.want, got)
+			assert.NoError(t, mock.ExpectationsWereMet())
 		})
 	}
 }
@@ -179,7 +285,7 @@ func TestDeletePost(t *testing.T) {
 			authorID: 1,
 			role:     "user",
 			mock: func() {
-				mock.ExpectExec(`DELETE FROM posts`).
+				mock.ExpectExec(`UPDATE posts\s+SET deleted_at = CURRENT_TIMESTAMP, deleted_by = \$2`).
 					WithArgs(int64(1), int64(1), "user").
 					WillReturnResult(sqlmock.NewResult(0, 1))
 			},
@@ -190,7 +296,7 @@ func TestDeletePost(t *testing.T) {
 			authorID: 2,
 			role:     "admin",
 			mock: func() {
-				mock.ExpectExec(`DELETE FROM posts`).
+				mock.ExpectExec(`UPDATE posts\s+SET deleted_at = CURRENT_TIMESTAMP, deleted_by = \$2`).
 					WithArgs(int64(1), int64(2), "admin").
 					WillReturnResult(sqlmock.NewResult(0, 1))
 			},
@@ -201,9 +307,27 @@ func TestDeletePost(t *testing.T) {
 			authorID: 1,
 			role:     "user",
 			mock: func() {
-				mock.ExpectExec(`DELETE FROM posts`).
+				mock.ExpectExec(`UPDATE posts\s+SET deleted_at = CURRENT_TIMESTAMP, deleted_by = \$2`).
 					WithArgs(int64(2), int64(1), "user").
 					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectQuery(`SELECT EXISTS`).
+					WithArgs(int64(2)).
+					WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+			},
+			wantErr: true,
+		},
+		{
+			name:     "Not Owner",
+			postID:   3,
+			authorID: 1,
+			role:     "user",
+			mock: func() {
+				mock.ExpectExec(`UPDATE posts\s+SET deleted_at = CURRENT_TIMESTAMP, deleted_by = \$2`).
+					WithArgs(int64(3), int64(1), "user").
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectQuery(`SELECT EXISTS`).
+					WithArgs(int64(3)).
+					WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
 			},
 			wantErr: true,
 		},
@@ -246,7 +370,7 @@ func TestGetPostByID(t *testing.T) {
 			mock: func() {
 				rows := sqlmock.NewRows([]string{"id", "title", "content", "author_id", "created_at"}).
 					AddRow(1, "Post 1", "Content 1", 1, now)
-				mock.ExpectQuery(`SELECT`).WithArgs(int64(1)).WillReturnRows(rows)
+				mock.ExpectQuery(`FROM posts\s+WHERE id = \$1 AND deleted_at IS NULL AND hidden_at IS NULL`).WithArgs(int64(1)).WillReturnRows(rows)
 			},
 			want: &entity.Post{
 				ID:        1,
@@ -311,6 +435,7 @@ func TestUpdatePost(t *testing.T) {
 		role     string
 		title    string
 		content  string
+		category int64
 		mock     func()
 		want     *entity.Post
 		wantErr  error
@@ -323,18 +448,25 @@ func TestUpdatePost(t *testing.T) {
 			title:    "Updated Title",
 			content:  "Updated Content",
 			mock: func() {
-				rows := sqlmock.NewRows([]string{"id", "title", "content", "author_id", "created_at"}).
-					AddRow(1, "Updated Title", "Updated Content", 1, now)
+				rows := sqlmock.NewRows([]string{"id", "title", "content", "content_html", "author_id", "category_id", "created_at"}).
+					AddRow(1, "Updated Title", "Updated Content", "<p>Updated Content</p>", 1, 1, now)
+				mock.ExpectBegin()
 				mock.ExpectQuery(`UPDATE posts`).
-					WithArgs("Updated Title", "Updated Content", int64(1), int64(1), "user").
+					WithArgs("Updated Title", "Updated Content", "<p>Updated Content</p>", int64(0), int64(1), int64(1), "user").
 					WillReturnRows(rows)
+				mock.ExpectExec(`INSERT INTO post_revisions`).
+					WithArgs(int64(1), int64(1), nil).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
 			},
 			want: &entity.Post{
-				ID:        1,
-				Title:     "Updated Title",
-				Content:   "Updated Content",
-				AuthorID:  1,
-				CreatedAt: now,
+				ID:          1,
+				Title:       "Updated Title",
+				Content:     "Updated Content",
+				ContentHTML: "<p>Updated Content</p>",
+				AuthorID:    1,
+				CategoryID:  1,
+				CreatedAt:   now,
 			},
 		},
 		{
@@ -345,20 +477,74 @@ func TestUpdatePost(t *testing.T) {
 			title:    "Updated Title",
 			content:  "Updated Content",
 			mock: func() {
-				rows := sqlmock.NewRows([]string{"id", "title", "content", "author_id", "created_at"}).
-					AddRow(1, "Updated Title", "Updated Content", 1, now)
+				rows := sqlmock.NewRows([]string{"id", "title", "content", "content_html", "author_id", "category_id", "created_at"}).
+					AddRow(1, "Updated Title", "Updated Content", "<p>Updated Content</p>", 1, 1, now)
+				mock.ExpectBegin()
 				mock.ExpectQuery(`UPDATE posts`).
-					WithArgs("Updated Title", "Updated Content", int64(1), int64(2), "admin").
+					WithArgs("Updated Title", "Updated Content", "<p>Updated Content</p>", int64(0), int64(1), int64(2), "admin").
 					WillReturnRows(rows)
+				mock.ExpectExec(`INSERT INTO post_revisions`).
+					WithArgs(int64(1), int64(2), nil).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
 			},
 			want: &entity.Post{
-				ID:        1,
-				Title:     "Updated Title",
-				Content:   "Updated Content",
-				AuthorID:  1,
-				CreatedAt: now,
+				ID:          1,
+				Title:       "Updated Title",
+				Content:     "Updated Content",
+				ContentHTML: "<p>Updated Content</p>",
+				AuthorID:    1,
+				CategoryID:  1,
+				CreatedAt:   now,
+			},
+		},
+		{
+			name:     "Success - Move To Category",
+			postID:   1,
+			authorID: 1,
+			role:     "user",
+			title:    "Updated Title",
+			content:  "Updated Content",
+			category: 3,
+			mock: func() {
+				rows := sqlmock.NewRows([]string{"id", "title", "content", "content_html", "author_id", "category_id", "created_at"}).
+					AddRow(1, "Updated Title", "Updated Content", "<p>Updated Content</p>", 1, 3, now)
+				mock.ExpectBegin()
+				mock.ExpectQuery(`UPDATE posts\s+SET .*category_id = COALESCE\(NULLIF\(\$4, 0\), category_id\)`).
+					WithArgs("Updated Title", "Updated Content", "<p>Updated Content</p>", int64(3), int64(1), int64(1), "user").
+					WillReturnRows(rows)
+				mock.ExpectExec(`INSERT INTO post_revisions`).
+					WithArgs(int64(1), int64(1), nil).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+			want: &entity.Post{
+				ID:          1,
+				Title:       "Updated Title",
+				Content:     "Updated Content",
+				ContentHTML: "<p>Updated Content</p>",
+				AuthorID:    1,
+				CategoryID:  3,
+				CreatedAt:   now,
 			},
 		},
+		{
+			name:     "Category Not Found",
+			postID:   1,
+			authorID: 1,
+			role:     "user",
+			title:    "Updated Title",
+			content:  "Updated Content",
+			category: 99,
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery(`UPDATE posts`).
+					WithArgs("Updated Title", "Updated Content", "<p>Updated Content</p>", int64(99), int64(1), int64(1), "user").
+					WillReturnError(&pq.Error{Code: foreignKeyViolation})
+				mock.ExpectRollback()
+			},
+			wantErr: ErrCategoryNotFound,
+		},
 		{
 			name:     "Not Found",
 			postID:   2,
@@ -367,12 +553,36 @@ func TestUpdatePost(t *testing.T) {
 			title:    "Updated Title",
 			content:  "Updated Content",
 			mock: func() {
+				mock.ExpectBegin()
 				mock.ExpectQuery(`UPDATE posts`).
-					WithArgs("Updated Title", "Updated Content", int64(2), int64(1), "user").
+					WithArgs("Updated Title", "Updated Content", "<p>Updated Content</p>", int64(0), int64(2), int64(1), "user").
 					WillReturnError(sql.ErrNoRows)
+				mock.ExpectQuery(`SELECT EXISTS`).
+					WithArgs(int64(2)).
+					WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+				mock.ExpectRollback()
 			},
 			wantErr: ErrPostNotFound,
 		},
+		{
+			name:     "Not Owner",
+			postID:   4,
+			authorID: 1,
+			role:     "user",
+			title:    "Updated Title",
+			content:  "Updated Content",
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery(`UPDATE posts`).
+					WithArgs("Updated Title", "Updated Content", "<p>Updated Content</p>", int64(0), int64(4), int64(1), "user").
+					WillReturnError(sql.ErrNoRows)
+				mock.ExpectQuery(`SELECT EXISTS`).
+					WithArgs(int64(4)).
+					WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+				mock.ExpectRollback()
+			},
+			wantErr: ErrPermissionDenied,
+		},
 		{
 			name:     "Database Error",
 			postID:   3,
@@ -381,9 +591,11 @@ func TestUpdatePost(t *testing.T) {
 			title:    "Updated Title",
 			content:  "Updated Content",
 			mock: func() {
+				mock.ExpectBegin()
 				mock.ExpectQuery(`UPDATE posts`).
-					WithArgs("Updated Title", "Updated Content", int64(3), int64(1), "user").
+					WithArgs("Updated Title", "Updated Content", "<p>Updated Content</p>", int64(0), int64(3), int64(1), "user").
 					WillReturnError(sql.ErrConnDone)
+				mock.ExpectRollback()
 			},
 			wantErr: sql.ErrConnDone,
 		},
@@ -393,7 +605,7 @@ func TestUpdatePost(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.mock()
 
-			got, err := repo.UpdatePost(context.Background(), tt.postID, tt.authorID, tt.role, tt.title, tt.content)
+			got, err := repo.UpdatePost(context.Background(), tt.postID, tt.authorID, tt.role, tt.title, tt.content, "<p>"+tt.content+"</p>", tt.category, nil)
 			if err != tt.wantErr {
 				if !errors.Is(err, tt.wantErr) {
 					t.Errorf("UpdatePost() error = %v, wantErr %v", err, tt.wantErr)
@@ -406,3 +618,168 @@ func TestUpdatePost(t *testing.T) {
 		})
 	}
 }
+
+func TestPostTags(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewPostRepository(sqlx.NewDb(db, "sqlmock"))
+	const tagsQuery = `INSERT INTO tags \(name\).*ON CONFLICT \(name\) DO UPDATE.*DELETE FROM post_tags.*INSERT INTO post_tags`
+	now := time.Now()
+
+	t.Run("Create", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(`INSERT INTO posts`).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "category_id"}).AddRow(1, 1))
+		mock.ExpectExec(tagsQuery).
+			WithArgs(int64(1), pq.Array([]string{"go", "sql"})).
+			WillReturnResult(sqlmock.NewResult(0, 2))
+		mock.ExpectExec(`INSERT INTO post_revisions`).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		id, err := repo.CreatePost(context.Background(), &entity.Post{
+			Title: "T", Content: "C", AuthorID: 1, CreatedAt: now, Tags: []string{"go", "sql"},
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), id)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Create rolls back when tagging fails", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(`INSERT INTO posts`).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "category_id"}).AddRow(2, 1))
+		mock.ExpectExec(tagsQuery).
+			WithArgs(int64(2), pq.Array([]string{"go"})).
+			WillReturnError(sql.ErrConnDone)
+		mock.ExpectRollback()
+
+		_, err := repo.CreatePost(context.Background(), &entity.Post{
+			Title: "T", Content: "C", AuthorID: 1, CreatedAt: now, Tags: []string{"go"},
+		})
+		assert.ErrorIs(t, err, sql.ErrConnDone)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Update", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(`UPDATE posts`).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "title", "content", "content_html", "author_id", "category_id", "created_at"}).
+				AddRow(1, "T", "C", "<p>C</p>", 1, 1, now))
+		mock.ExpectExec(tagsQuery).
+			WithArgs(int64(1), pq.Array([]string{})).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(`INSERT INTO post_revisions`).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		post, err := repo.UpdatePost(context.Background(), 1, 1, "user", "T", "C", "<p>C</p>", 0, []string{})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{}, post.Tags)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Update rolls back when tagging fails", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(`UPDATE posts`).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "title", "content", "content_html", "author_id", "category_id", "created_at"}).
+				AddRow(9, "T", "C", "<p>C</p>", 1, 1, now))
+		mock.ExpectExec(tagsQuery).
+			WithArgs(int64(9), pq.Array([]string{"go"})).
+			WillReturnError(&pq.Error{Code: foreignKeyViolation})
+		mock.ExpectRollback()
+
+		_, err := repo.UpdatePost(context.Background(), 9, 1, "user", "T", "C", "<p>C</p>", 0, []string{"go"})
+		assert.ErrorIs(t, err, ErrPostNotFound)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestSetPinned(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewPostRepository(sqlx.NewDb(db, "sqlmock"))
+
+	mock.ExpectExec(`UPDATE posts SET pinned_at = COALESCE\(pinned_at, CURRENT_TIMESTAMP\) WHERE id = \$1 AND deleted_at IS NULL`).
+		WithArgs(int64(1)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	assert.NoError(t, repo.SetPinned(context.Background(), 1, true))
+
+	mock.ExpectExec(`UPDATE posts SET locked_at = NULL WHERE id = \$1 AND deleted_at IS NULL`).
+		WithArgs(int64(2)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	assert.ErrorIs(t, repo.SetLocked(context.Background(), 2, false), ErrPostNotFound)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTrash(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewPostRepository(sqlx.NewDb(db, "sqlmock"))
+	deletedAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	columns := []string{"id", "title", "deleted_at", "deleted_by"}
+
+	t.Run("List", func(t *testing.T) {
+		mock.ExpectQuery(`WHERE deleted_at IS NOT NULL\s+ORDER BY deleted_at DESC, id DESC\s+LIMIT \$1`).
+			WithArgs(2).
+			WillReturnRows(sqlmock.NewRows(columns).
+				AddRow(5, "Post 5", deletedAt, 1).
+				AddRow(4, "Post 4", deletedAt.Add(-time.Hour), 1))
+		mock.ExpectQuery(`FROM post_tags pt\s+JOIN tags t`).
+			WithArgs(pq.Array([]int64{5})).
+			WillReturnRows(sqlmock.NewRows([]string{"post_id", "name"}))
+
+		page, err := repo.ListDeletedPosts(context.Background(), 1, "")
+		require.NoError(t, err)
+		require.Len(t, page.Posts, 1)
+		assert.Equal(t, deletedAt, *page.Posts[0].DeletedAt)
+		require.NotEmpty(t, page.NextCursor)
+
+		mock.ExpectQuery(`WHERE deleted_at IS NOT NULL AND \(deleted_at, id\) < \(\$2, \$3\)`).
+			WithArgs(2, deletedAt, int64(5)).
+			WillReturnRows(sqlmock.NewRows(columns))
+		page, err = repo.ListDeletedPosts(context.Background(), 1, page.NextCursor)
+		require.NoError(t, err)
+		assert.Empty(t, page.Posts)
+		assert.Empty(t, page.NextCursor)
+
+		_, err = repo.ListDeletedPosts(context.Background(), 1, postCursor{Sort: entity.PostSortNewest, ID: 1}.encode())
+		assert.ErrorIs(t, err, ErrInvalidCursor)
+	})
+
+	t.Run("Restore", func(t *testing.T) {
+		mock.ExpectExec(`UPDATE posts\s+SET deleted_at = NULL, deleted_by = NULL\s+WHERE id = \$1 AND deleted_at IS NOT NULL`).
+			WithArgs(int64(5)).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		assert.NoError(t, repo.RestorePost(context.Background(), 5))
+
+		mock.ExpectExec(`UPDATE posts\s+SET deleted_at = NULL`).
+			WithArgs(int64(6)).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		assert.ErrorIs(t, repo.RestorePost(context.Background(), 6), ErrPostNotInTrash)
+	})
+
+	t.Run("Purge", func(t *testing.T) {
+		mock.ExpectExec(`DELETE FROM posts\s+WHERE id IN \(\s+SELECT id FROM posts\s+WHERE deleted_at < \$1\s+ORDER BY deleted_at\s+LIMIT \$2`).
+			WithArgs(deletedAt, 100).
+			WillReturnResult(sqlmock.NewResult(0, 3))
+		n, err := repo.PurgeDeletedPosts(context.Background(), deletedAt, 100)
+		require.NoError(t, err)
+		assert.Equal(t, int64(3), n)
+	})
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,131 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"backend.com/forum/apperrors"
+	"github.com/Ulyana-kru00/forum-project/forum-servise/internal/entity"
+	"github.com/jmoiron/sqlx"
+)
+
+var ErrRevisionNotFound = apperrors.New(apperrors.ErrNotFound, "revision not found")
+
+type RevisionRepository interface {
+	ListRevisions(ctx context.Context, postID int64) ([]*entity.PostRevision, error)
+	GetRevision(ctx context.Context, postID int64, revision int) (*entity.PostRevision, error)
+	RestoreRevision(ctx context.Context, postID int64, revision int, editorID int64) (*entity.Post, error)
+}
+
+type revisionRepository struct {
+	db *sqlx.DB
+}
+
+func NewRevisionRepository(db *sqlx.DB) RevisionRepository {
+	return &revisionRepository{db: db}
+}
+
+// revisionSelect reads the revisions of posts that are neither in the trash
+// nor hidden by moderation; the history of other posts is not public.
+const revisionSelect = `
+		SELECT r.id, r.post_id, r.revision, r.title, r.content, r.content_html, r.editor_id, r.restored_from, r.created_at
+		FROM post_revisions r
+		JOIN posts p ON p.id = r.post_id AND p.deleted_at IS NULL AND p.hidden_at IS NULL`
+
+// ListRevisions returns the revisions of a post, newest first. Every post
+// has at least one, so none means there is no such post.
+func (r *revisionRepository) ListRevisions(ctx context.Context, postID int64) ([]*entity.PostRevision, error) {
+	query := revisionSelect + `
+		WHERE r.post_id = $1
+		ORDER BY r.revision DESC`
+
+	revisions := []*entity.PostRevision{}
+	if err := r.db.SelectContext(ctx, &revisions, query, postID); err != nil {
+		return nil, err
+	}
+	if len(revisions) == 0 {
+		return nil, ErrPostNotFound
+	}
+	return revisions, nil
+}
+
+func (r *revisionRepository) GetRevision(ctx context.Context, postID int64, revision int) (*entity.PostRevision, error) {
+	query := revisionSelect + `
+		WHERE r.post_id = $1 AND r.revision = $2`
+
+	var rev entity.PostRevision
+	err := r.db.GetContext(ctx, &rev, query, postID, revision)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, r.missingRevision(ctx, postID)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &rev, nil
+}
+
+// RestoreRevision puts the title and content of an earlier revision back
+// into the post and records that as a new revision by editorID.
+func (r *revisionRepository) RestoreRevision(ctx context.Context, postID int64, revision int, editorID int64) (*entity.Post, error) {
+	query := `
+		UPDATE posts p
+		SET title = r.title, content = r.content, content_html = r.content_html
+		FROM post_revisions r
+		WHERE p.id = $1 AND p.deleted_at IS NULL AND p.hidden_at IS NULL
+		AND r.post_id = p.id AND r.revision = $2
+		RETURNING p.id, p.title, p.content, p.content_html, p.author_id, p.created_at`
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var post entity.Post
+	err = tx.GetContext(ctx, &post, query, postID, revision)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, r.missingRevision(ctx, postID)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := insertRevision(ctx, tx, postID, editorID, &revision); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return &post, nil
+}
+
+// missingRevision tells whether a revision lookup failed because the post
+// or only the revision does not exist. Trashed and hidden posts count as
+// missing.
+func (r *revisionRepository) missingRevision(ctx context.Context, postID int64) error {
+	var exists bool
+	err := r.db.GetContext(ctx, &exists,
+		`SELECT EXISTS(SELECT 1 FROM posts WHERE id = $1 AND deleted_at IS NULL AND hidden_at IS NULL)`, postID)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return ErrRevisionNotFound
+	}
+	return ErrPostNotFound
+}
+
+// insertRevision records the current title and content of a post as its
+// next revision. It must run in the transaction that changed the post,
+// which also holds the row lock that keeps revision numbers unique.
+func insertRevision(ctx context.Context, tx *sqlx.Tx, postID, editorID int64, restoredFrom *int) error {
+	query := `
+		INSERT INTO post_revisions (post_id, revision, title, content, content_html, editor_id, restored_from)
+		SELECT id, COALESCE((SELECT MAX(revision) FROM post_revisions WHERE post_id = $1), 0) + 1, title, content, content_html, $2, $3
+		FROM posts
+		WHERE id = $1`
+
+	_, err := tx.ExecContext(ctx, query, postID, editorID, restoredFrom)
+	return err
+}
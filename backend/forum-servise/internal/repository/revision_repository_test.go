@@ -0,0 +1,126 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/Ulyana-kru00/forum-project/forum-servise/internal/entity"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newRevisionRepoMock(t *testing.T) (RevisionRepository, sqlmock.Sqlmock) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return NewRevisionRepository(sqlx.NewDb(db, "sqlmock")), mock
+}
+
+var revisionColumns = []string{"id", "post_id", "revision", "title", "content", "editor_id", "restored_from", "created_at"}
+
+func TestListRevisions(t *testing.T) {
+	repo, mock := newRevisionRepoMock(t)
+	now := time.Now()
+
+	t.Run("Success", func(t *testing.T) {
+		mock.ExpectQuery(`FROM post_revisions r\s+JOIN posts p ON p.id = r.post_id AND p.deleted_at IS NULL AND p.hidden_at IS NULL\s+WHERE r.post_id = \$1\s+ORDER BY r.revision DESC`).
+			WithArgs(int64(1)).
+			WillReturnRows(sqlmock.NewRows(revisionColumns).
+				AddRow(2, 1, 2, "Title", "New", 7, 1, now).
+				AddRow(1, 1, 1, "Title", "Old", nil, nil, now))
+
+		revisions, err := repo.ListRevisions(context.Background(), 1)
+		require.NoError(t, err)
+		require.Len(t, revisions, 2)
+		assert.Equal(t, 2, revisions[0].Revision)
+		assert.Equal(t, int64(7), *revisions[0].EditorID)
+		assert.Equal(t, 1, *revisions[0].RestoredFrom)
+		assert.Nil(t, revisions[1].EditorID)
+	})
+
+	t.Run("Post not found", func(t *testing.T) {
+		mock.ExpectQuery(`FROM post_revisions`).
+			WithArgs(int64(9)).
+			WillReturnRows(sqlmock.NewRows(revisionColumns))
+
+		_, err := repo.ListRevisions(context.Background(), 9)
+		assert.ErrorIs(t, err, ErrPostNotFound)
+	})
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetRevision(t *testing.T) {
+	repo, mock := newRevisionRepoMock(t)
+	now := time.Now()
+
+	t.Run("Success", func(t *testing.T) {
+		mock.ExpectQuery(`WHERE r.post_id = \$1 AND r.revision = \$2`).
+			WithArgs(int64(1), 2).
+			WillReturnRows(sqlmock.NewRows(revisionColumns).AddRow(2, 1, 2, "Title", "Body", 7, nil, now))
+
+		rev, err := repo.GetRevision(context.Background(), 1, 2)
+		require.NoError(t, err)
+		assert.Equal(t, "Body", rev.Content)
+	})
+
+	t.Run("Revision not found", func(t *testing.T) {
+		mock.ExpectQuery(`FROM post_revisions`).WithArgs(int64(1), 5).WillReturnError(sql.ErrNoRows)
+		mock.ExpectQuery(`SELECT EXISTS`).WithArgs(int64(1)).
+			WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+
+		_, err := repo.GetRevision(context.Background(), 1, 5)
+		assert.ErrorIs(t, err, ErrRevisionNotFound)
+	})
+
+	t.Run("Post not found", func(t *testing.T) {
+		mock.ExpectQuery(`FROM post_revisions`).WithArgs(int64(9), 1).WillReturnError(sql.ErrNoRows)
+		mock.ExpectQuery(`SELECT EXISTS\(SELECT 1 FROM posts WHERE id = \$1 AND deleted_at IS NULL AND hidden_at IS NULL\)`).WithArgs(int64(9)).
+			WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+
+		_, err := repo.GetRevision(context.Background(), 9, 1)
+		assert.ErrorIs(t, err, ErrPostNotFound)
+	})
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRestoreRevision(t *testing.T) {
+	repo, mock := newRevisionRepoMock(t)
+	now := time.Now()
+
+	t.Run("Success", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(`UPDATE posts p\s+SET title = r.title, content = r.content, content_html = r.content_html\s+FROM post_revisions r\s+WHERE p.id = \$1 AND p.deleted_at IS NULL AND p.hidden_at IS NULL`).
+			WithArgs(int64(1), 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "title", "content", "content_html", "author_id", "created_at"}).
+				AddRow(1, "Title", "Old", "<p>Old</p>\n", 3, now))
+		mock.ExpectExec(`INSERT INTO post_revisions`).
+			WithArgs(int64(1), int64(2), 1).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		post, err := repo.RestoreRevision(context.Background(), 1, 1, 2)
+		require.NoError(t, err)
+		assert.Equal(t, &entity.Post{ID: 1, Title: "Title", Content: "Old", ContentHTML: "<p>Old</p>\n", AuthorID: 3, CreatedAt: now}, post)
+	})
+
+	t.Run("Revision not found", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(`UPDATE posts p`).WithArgs(int64(1), 8).WillReturnError(sql.ErrNoRows)
+		mock.ExpectQuery(`SELECT EXISTS`).WithArgs(int64(1)).
+			WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+		mock.ExpectRollback()
+
+		_, err := repo.RestoreRevision(context.Background(), 1, 8, 2)
+		assert.ErrorIs(t, err, ErrRevisionNotFound)
+	})
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,187 @@
+package repository
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"html"
+	"strings"
+	"unicode"
+
+	"backend.com/forum/apperrors"
+	"github.com/Ulyana-kru00/forum-project/forum-servise/internal/entity"
+	"github.com/jmoiron/sqlx"
+)
+
+var ErrEmptySearchQuery = apperrors.New(apperrors.ErrInvalidArgument, "search query is empty")
+
+// searchConfig must match the configuration the search_vector columns are
+// generated with.
+const searchConfig = "simple"
+
+const headlineOptions = "StartSel=<mark>, StopSel=</mark>, MaxWords=35, MinWords=15, MaxFragments=2"
+
+type SearchRepository interface {
+	Search(ctx context.Context, filter entity.SearchFilter) (*entity.SearchPage, error)
+}
+
+type searchRepository struct {
+	db *sqlx.DB
+}
+
+func NewSearchRepository(db *sqlx.DB) SearchRepository {
+	return &searchRepository{db: db}
+}
+
+// searchCursor is the keyset position after the last result of a page.
+type searchCursor struct {
+	Rank float64           `json:"r"`
+	Kind entity.SearchType `json:"k"`
+	ID   int64             `json:"i"`
+}
+
+func (c searchCursor) encode() string {
+	raw, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+func decodeSearchCursor(s string) (searchCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return searchCursor{}, ErrInvalidCursor
+	}
+	var c searchCursor
+	if err := json.Unmarshal(raw, &c); err != nil || c.ID <= 0 ||
+		(c.Kind != entity.SearchTypePost && c.Kind != entity.SearchTypeComment) {
+		return searchCursor{}, ErrInvalidCursor
+	}
+	return c, nil
+}
+
+// Search ranks matching posts and comments by ts_rank and pages through them
+// by (rank, kind, id). Snippets are only built for the rows of the page.
+func (r *searchRepository) Search(ctx context.Context, filter entity.SearchFilter) (*entity.SearchPage, error) {
+	tsquery := toTSQuery(filter.Query)
+	if tsquery == "" {
+		return nil, ErrEmptySearchQuery
+	}
+	if filter.Limit <= 0 {
+		return nil, apperrors.New(apperrors.ErrInvalidArgument, "limit must be positive")
+	}
+
+	args := []interface{}{tsquery}
+	arg := func(v interface{}) string {
+		args = append(args, v)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	var parts []string
+	if filter.Type != entity.SearchTypeComment {
+		part := `
+			SELECT 'post' AS kind, p.id, p.id AS post_id, p.title AS post_title, p.content,
+				p.author_id, p.created_at, ts_rank(p.search_vector, q) AS rank
+			FROM posts p, to_tsquery('` + searchConfig + `', $1) q
+			WHERE p.search_vector @@ q AND p.hidden_at IS NULL AND p.deleted_at IS NULL`
+		if filter.AuthorID != 0 {
+			part += " AND p.author_id = " + arg(filter.AuthorID)
+		}
+		parts = append(parts, part)
+	}
+	if filter.Type != entity.SearchTypePost {
+		part := `
+			SELECT 'comment' AS kind, c.id, c.post_id, p.title AS post_title, c.content,
+				c.author_id, c.created_at, ts_rank(c.search_vector, q) AS rank
+			FROM comments c JOIN posts p ON p.id = c.post_id, to_tsquery('` + searchConfig + `', $1) q
+			WHERE c.search_vector @@ q AND c.hidden_at IS NULL AND p.hidden_at IS NULL AND p.deleted_at IS NULL`
+		if filter.AuthorID != 0 {
+			part += " AND c.author_id = " + arg(filter.AuthorID)
+		}
+		parts = append(parts, part)
+	}
+
+	where := ""
+	if filter.Cursor != "" {
+		c, err := decodeSearchCursor(filter.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		where = fmt.Sprintf("WHERE (rank, kind, id) < (%s, %s, %s)", arg(c.Rank), arg(string(c.Kind)), arg(c.ID))
+	}
+
+	query := fmt.Sprintf(`
+		SELECT
+			kind,
+			id,
+			post_id,
+			post_title,
+			ts_headline('%s', content, to_tsquery('%s', $1), '%s') AS snippet,
+			author_id,
+			created_at,
+			rank
+		FROM (
+			SELECT * FROM (%s
+			) matches
+			%s
+			ORDER BY rank DESC, kind DESC, id DESC
+			LIMIT %s
+		) page
+		ORDER BY rank DESC, kind DESC, id DESC`,
+		searchConfig, searchConfig, headlineOptions,
+		strings.Join(parts, "\n\t\t\tUNION ALL"), where, arg(filter.Limit+1))
+
+	results := []*entity.SearchResult{}
+	if err := r.db.SelectContext(ctx, &results, query, args...); err != nil {
+		return nil, err
+	}
+	for _, res := range results {
+		res.Snippet = escapeHeadline(res.Snippet)
+	}
+
+	page := &entity.SearchPage{Results: results}
+	if len(results) > filter.Limit {
+		page.Results = results[:filter.Limit]
+		last := page.Results[filter.Limit-1]
+		page.NextCursor = searchCursor{Rank: last.Rank, Kind: last.Type, ID: last.ID}.encode()
+	}
+	return page, nil
+}
+
+// toTSQuery turns user input into a to_tsquery expression. Quoted text is a
+// phrase, a trailing * makes a prefix match and every other word is required.
+// Only letters and digits survive, so the input cannot inject tsquery syntax.
+func toTSQuery(input string) string {
+	var terms []string
+	for i, part := range strings.Split(input, `"`) {
+		if i%2 == 1 {
+			if words := searchWords(part); len(words) > 0 {
+				terms = append(terms, "("+strings.Join(words, " <-> ")+")")
+			}
+			continue
+		}
+		for _, field := range strings.Fields(part) {
+			words := searchWords(field)
+			if len(words) == 0 {
+				continue
+			}
+			if strings.HasSuffix(field, "*") {
+				words[len(words)-1] += ":*"
+			}
+			terms = append(terms, words...)
+		}
+	}
+	return strings.Join(terms, " & ")
+}
+
+func searchWords(s string) []string {
+	return strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// escapeHeadline HTML-escapes a ts_headline result while keeping the <mark>
+// tags it inserted around the matches.
+func escapeHeadline(s string) string {
+	s = html.EscapeString(s)
+	return strings.NewReplacer("&lt;mark&gt;", "<mark>", "&lt;/mark&gt;", "</mark>").Replace(s)
+}
@@ -0,0 +1,128 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/Ulyana-kru00/forum-project/forum-servise/internal/entity"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestToTSQuery(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"golang", "golang"},
+		{"Go  Channels", "go & channels"},
+		{`"race condition" mutex`, "(race <-> condition) & mutex"},
+		{"gorout*", "gorout:*"},
+		{`"unterminated phrase`, "(unterminated <-> phrase)"},
+		{"привет мир*", "привет & мир:*"},
+		{"a&b | !c:* <->", "a & b & c:*"},
+		{`'); DROP TABLE posts; --`, "drop & table & posts"},
+		{`"" * !`, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			assert.Equal(t, tt.want, toTSQuery(tt.input))
+		})
+	}
+}
+
+func TestEscapeHeadline(t *testing.T) {
+	got := escapeHeadline(`<script>x</script> and <mark>go</mark> & more`)
+	assert.Equal(t, `&lt;script&gt;x&lt;/script&gt; and <mark>go</mark> &amp; more`, got)
+}
+
+func TestSearch(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewSearchRepository(sqlx.NewDb(db, "sqlmock"))
+
+	now := time.Now()
+	columns := []string{"kind", "id", "post_id", "post_title", "snippet", "author_id", "created_at", "rank"}
+	nextCursor := searchCursor{Rank: 0.5, Kind: entity.SearchTypeComment, ID: 7}.encode()
+
+	tests := []struct {
+		name    string
+		filter  entity.SearchFilter
+		mock    func()
+		want    *entity.SearchPage
+		wantErr error
+	}{
+		{
+			name:   "Posts and comments, first page",
+			filter: entity.SearchFilter{Query: "go*", Limit: 1},
+			mock: func() {
+				mock.ExpectQuery(`FROM posts p.*UNION ALL.*FROM comments c.*LIMIT \$2`).
+					WithArgs("go:*", 2).
+					WillReturnRows(sqlmock.NewRows(columns).
+						AddRow("post", 3, 3, "Go", "<mark>golang</mark> <b>", 1, now, 0.9).
+						AddRow("comment", 7, 3, "Go", "<mark>go</mark>", 2, now, 0.5))
+			},
+			want: &entity.SearchPage{
+				Results: []*entity.SearchResult{{
+					Type: entity.SearchTypePost, ID: 3, PostID: 3, PostTitle: "Go",
+					Snippet: "<mark>golang</mark> &lt;b&gt;", AuthorID: 1, CreatedAt: now, Rank: 0.9,
+				}},
+				NextCursor: searchCursor{Rank: 0.9, Kind: entity.SearchTypePost, ID: 3}.encode(),
+			},
+		},
+		{
+			name:   "Comments by author after cursor",
+			filter: entity.SearchFilter{Query: "go", Type: entity.SearchTypeComment, AuthorID: 2, Limit: 5, Cursor: nextCursor},
+			mock: func() {
+				mock.ExpectQuery(`FROM comments c .*AND c.author_id = \$2\s+\) matches\s+WHERE \(rank, kind, id\) < \(\$3, \$4, \$5\)`).
+					WithArgs("go", int64(2), 0.5, "comment", int64(7), 6).
+					WillReturnRows(sqlmock.NewRows(columns))
+			},
+			want: &entity.SearchPage{Results: []*entity.SearchResult{}},
+		},
+		{
+			name:    "Nothing searchable",
+			filter:  entity.SearchFilter{Query: "*** !!!", Limit: 5},
+			mock:    func() {},
+			wantErr: ErrEmptySearchQuery,
+		},
+		{
+			name:    "Invalid cursor",
+			filter:  entity.SearchFilter{Query: "go", Limit: 5, Cursor: postCursor{Sort: entity.PostSortNewest, ID: 1}.encode()},
+			mock:    func() {},
+			wantErr: ErrInvalidCursor,
+		},
+		{
+			name:   "Database error",
+			filter: entity.SearchFilter{Query: "go", Limit: 5},
+			mock: func() {
+				mock.ExpectQuery(`SELECT`).WillReturnError(sql.ErrConnDone)
+			},
+			wantErr: sql.ErrConnDone,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			got, err := repo.Search(context.Background(), tt.filter)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
@@ -0,0 +1,117 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"backend.com/forum/apperrors"
+	"github.com/Ulyana-kru00/forum-project/forum-servise/internal/entity"
+	"github.com/jmoiron/sqlx"
+)
+
+var (
+	ErrTagNotFound = apperrors.New(apperrors.ErrNotFound, "tag not found")
+	ErrTagExists   = apperrors.New(apperrors.ErrAlreadyExists, "tag already exists, merge the tags instead")
+)
+
+type TagRepository interface {
+	ListTags(ctx context.Context, filter entity.TagFilter) ([]*entity.Tag, error)
+	RenameTag(ctx context.Context, name, newName string) (*entity.Tag, error)
+	MergeTags(ctx context.Context, source, target string) (*entity.Tag, error)
+}
+
+type tagRepository struct {
+	db *sqlx.DB
+}
+
+func NewTagRepository(db *sqlx.DB) TagRepository {
+	return &tagRepository{db: db}
+}
+
+// ListTags returns the tags in use, most used first. Only posts that are
+// neither trashed nor hidden count as uses.
+func (r *tagRepository) ListTags(ctx context.Context, filter entity.TagFilter) ([]*entity.Tag, error) {
+	query := `
+		SELECT t.id, t.name, COUNT(*) AS post_count
+		FROM tags t
+		JOIN post_tags pt ON pt.tag_id = t.id
+		JOIN posts p ON p.id = pt.post_id AND p.deleted_at IS NULL AND p.hidden_at IS NULL
+		WHERE t.name LIKE $1
+		GROUP BY t.id
+		ORDER BY post_count DESC, t.name
+		LIMIT $2`
+
+	tags := []*entity.Tag{}
+	if err := r.db.SelectContext(ctx, &tags, query, escapeLike(filter.Prefix)+"%", filter.Limit); err != nil {
+		return nil, err
+	}
+	return tags, nil
+}
+
+// visibleTagPosts counts tagged posts the way ListTags does.
+const visibleTagPosts = `
+			SELECT COUNT(*) FROM post_tags pt
+			JOIN posts p ON p.id = pt.post_id AND p.deleted_at IS NULL AND p.hidden_at IS NULL`
+
+// RenameTag changes the name of a tag on all of its posts. Renaming to the
+// name of another tag fails with ErrTagExists.
+func (r *tagRepository) RenameTag(ctx context.Context, name, newName string) (*entity.Tag, error) {
+	query := `
+		UPDATE tags SET name = $2
+		WHERE name = $1
+		RETURNING id, name, (` + visibleTagPosts + ` WHERE pt.tag_id = tags.id) AS post_count`
+
+	var tag entity.Tag
+	err := r.db.GetContext(ctx, &tag, query, name, newName)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrTagNotFound
+	}
+	if isPQError(err, uniqueViolation) {
+		return nil, ErrTagExists
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &tag, nil
+}
+
+// MergeTags moves the posts of source to target and deletes source. It
+// returns target with its new post count.
+func (r *tagRepository) MergeTags(ctx context.Context, source, target string) (*entity.Tag, error) {
+	// All parts of the statement see the tags as they were before it, so
+	// the moved posts are added to the count of target explicitly.
+	query := `
+		WITH source AS (
+			SELECT id FROM tags WHERE name = $1
+		), target AS (
+			SELECT id, name FROM tags WHERE name = $2
+		), moved AS (
+			INSERT INTO post_tags (post_id, tag_id)
+			SELECT pt.post_id, target.id
+			FROM post_tags pt JOIN source ON pt.tag_id = source.id CROSS JOIN target
+			ON CONFLICT DO NOTHING
+			RETURNING post_id
+		), removed AS (
+			DELETE FROM tags
+			WHERE id IN (SELECT id FROM source) AND EXISTS (SELECT 1 FROM target)
+			RETURNING id
+		)
+		SELECT
+			target.id,
+			target.name,
+			(` + visibleTagPosts + ` WHERE pt.tag_id = target.id) +
+			(SELECT COUNT(*) FROM moved JOIN posts p ON p.id = moved.post_id AND p.deleted_at IS NULL AND p.hidden_at IS NULL) AS post_count
+		FROM target
+		WHERE EXISTS (SELECT 1 FROM removed)`
+
+	var tag entity.Tag
+	err := r.db.GetContext(ctx, &tag, query, source, target)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrTagNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &tag, nil
+}
@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/Ulyana-kru00/forum-project/forum-servise/internal/entity"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTagRepoMock(t *testing.T) (TagRepository, sqlmock.Sqlmock) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return NewTagRepository(sqlx.NewDb(db, "sqlmock")), mock
+}
+
+var tagColumns = []string{"id", "name", "post_count"}
+
+func TestListTags(t *testing.T) {
+	repo, mock := newTagRepoMock(t)
+
+	mock.ExpectQuery(`JOIN posts p ON p.id = pt.post_id AND p.deleted_at IS NULL AND p.hidden_at IS NULL\s+WHERE t.name LIKE \$1\s+GROUP BY t.id\s+ORDER BY post_count DESC, t.name\s+LIMIT \$2`).
+		WithArgs(`go\_%`, 10).
+		WillReturnRows(sqlmock.NewRows(tagColumns).AddRow(1, "go_lang", 4))
+
+	tags, err := repo.ListTags(context.Background(), entity.TagFilter{Prefix: "go_", Limit: 10})
+	require.NoError(t, err)
+	assert.Equal(t, []*entity.Tag{{ID: 1, Name: "go_lang", PostCount: 4}}, tags)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRenameTag(t *testing.T) {
+	repo, mock := newTagRepoMock(t)
+
+	t.Run("Success", func(t *testing.T) {
+		mock.ExpectQuery(`UPDATE tags SET name = \$2\s+WHERE name = \$1`).
+			WithArgs("golang", "go").
+			WillReturnRows(sqlmock.NewRows(tagColumns).AddRow(1, "go", 3))
+
+		tag, err := repo.RenameTag(context.Background(), "golang", "go")
+		require.NoError(t, err)
+		assert.Equal(t, &entity.Tag{ID: 1, Name: "go", PostCount: 3}, tag)
+	})
+
+	t.Run("Not found", func(t *testing.T) {
+		mock.ExpectQuery(`UPDATE tags`).WithArgs("missing", "go").WillReturnError(sql.ErrNoRows)
+
+		_, err := repo.RenameTag(context.Background(), "missing", "go")
+		assert.ErrorIs(t, err, ErrTagNotFound)
+	})
+
+	t.Run("Name taken", func(t *testing.T) {
+		mock.ExpectQuery(`UPDATE tags`).WithArgs("golang", "go").WillReturnError(&pq.Error{Code: uniqueViolation})
+
+		_, err := repo.RenameTag(context.Background(), "golang", "go")
+		assert.ErrorIs(t, err, ErrTagExists)
+	})
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMergeTags(t *testing.T) {
+	repo, mock := newTagRepoMock(t)
+
+	mock.ExpectQuery(`(?s)INSERT INTO post_tags.*ON CONFLICT DO NOTHING.*DELETE FROM tags.*FROM target\s+WHERE EXISTS \(SELECT 1 FROM removed\)`).
+		WithArgs("golang", "go").
+		WillReturnRows(sqlmock.NewRows(tagColumns).AddRow(1, "go", 7))
+
+	tag, err := repo.MergeTags(context.Background(), "golang", "go")
+	require.NoError(t, err)
+	assert.Equal(t, int64(7), tag.PostCount)
+
+	mock.ExpectQuery(`WITH source`).WithArgs("golang", "missing").WillReturnError(sql.ErrNoRows)
+	_, err = repo.MergeTags(context.Background(), "golang", "missing")
+	assert.ErrorIs(t, err, ErrTagNotFound)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
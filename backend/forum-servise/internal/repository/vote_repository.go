@@ -0,0 +1,108 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/Ulyana-kru00/forum-project/forum-servise/internal/entity"
+	"github.com/jmoiron/sqlx"
+)
+
+// VoteRepository stores one vote per user per post or comment. Scores are
+// kept up to date by the vote_score trigger.
+type VoteRepository interface {
+	VotePost(ctx context.Context, userID, postID int64, value int) (*entity.VoteResult, error)
+	VoteComment(ctx context.Context, userID, postID, commentID int64, value int) (*entity.VoteResult, error)
+}
+
+type voteRepository struct {
+	db *sqlx.DB
+}
+
+func NewVoteRepository(db *sqlx.DB) VoteRepository {
+	return &voteRepository{db: db}
+}
+
+// VotePost casts, changes or, with value 0, retracts the vote of userID on
+// a post and returns the new score. Trashed and hidden posts cannot be
+// voted on, but votes on them can still be retracted.
+func (r *voteRepository) VotePost(ctx context.Context, userID, postID int64, value int) (*entity.VoteResult, error) {
+	if value == 0 {
+		_, err := r.db.ExecContext(ctx, `DELETE FROM votes WHERE user_id = $1 AND post_id = $2`, userID, postID)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		query := `
+			INSERT INTO votes (user_id, post_id, value)
+			SELECT $1, id, $3 FROM posts WHERE id = $2 AND deleted_at IS NULL AND hidden_at IS NULL
+			ON CONFLICT (user_id, post_id) WHERE post_id IS NOT NULL
+			DO UPDATE SET value = EXCLUDED.value, updated_at = CURRENT_TIMESTAMP`
+		if err := r.upsert(ctx, ErrPostNotFound, query, userID, postID, value); err != nil {
+			return nil, err
+		}
+	}
+
+	result := &entity.VoteResult{Vote: value}
+	err := r.db.GetContext(ctx, &result.Score, `SELECT score FROM posts WHERE id = $1`, postID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrPostNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// VoteComment is VotePost for a comment of the given post. Deleted and
+// hidden comments, and comments of trashed or hidden posts, cannot be voted
+// on, but votes on them can still be retracted.
+func (r *voteRepository) VoteComment(ctx context.Context, userID, postID, commentID int64, value int) (*entity.VoteResult, error) {
+	if value == 0 {
+		_, err := r.db.ExecContext(ctx, `DELETE FROM votes WHERE user_id = $1 AND comment_id = $2`, userID, commentID)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		query := `
+			INSERT INTO votes (user_id, comment_id, value)
+			SELECT $1, c.id, $3
+			FROM comments c
+			JOIN posts p ON p.id = c.post_id AND p.deleted_at IS NULL AND p.hidden_at IS NULL
+			WHERE c.id = $2 AND c.post_id = $4 AND c.deleted_at IS NULL AND c.hidden_at IS NULL
+			ON CONFLICT (user_id, comment_id) WHERE comment_id IS NOT NULL
+			DO UPDATE SET value = EXCLUDED.value, updated_at = CURRENT_TIMESTAMP`
+		if err := r.upsert(ctx, ErrCommentNotFound, query, userID, commentID, value, postID); err != nil {
+			return nil, err
+		}
+	}
+
+	result := &entity.VoteResult{Vote: value}
+	err := r.db.GetContext(ctx, &result.Score,
+		`SELECT score FROM comments WHERE id = $1 AND post_id = $2`, commentID, postID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrCommentNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// upsert runs a vote insert that selects its target; no inserted or updated
+// row means the target does not exist.
+func (r *voteRepository) upsert(ctx context.Context, notFound error, query string, args ...interface{}) error {
+	res, err := r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return notFound
+	}
+	return nil
+}
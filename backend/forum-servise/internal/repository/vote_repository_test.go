@@ -0,0 +1,103 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/Ulyana-kru00/forum-project/forum-servise/internal/entity"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newVoteRepoMock(t *testing.T) (VoteRepository, sqlmock.Sqlmock) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return NewVoteRepository(sqlx.NewDb(db, "sqlmock")), mock
+}
+
+func TestVotePost(t *testing.T) {
+	repo, mock := newVoteRepoMock(t)
+
+	t.Run("Upvote", func(t *testing.T) {
+		mock.ExpectExec(`INSERT INTO votes \(user_id, post_id, value\).*FROM posts WHERE id = \$2 AND deleted_at IS NULL AND hidden_at IS NULL.*ON CONFLICT \(user_id, post_id\)`).
+			WithArgs(int64(3), int64(1), 1).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectQuery(`SELECT score FROM posts WHERE id = \$1`).
+			WithArgs(int64(1)).
+			WillReturnRows(sqlmock.NewRows([]string{"score"}).AddRow(5))
+
+		result, err := repo.VotePost(context.Background(), 3, 1, 1)
+		require.NoError(t, err)
+		assert.Equal(t, &entity.VoteResult{Score: 5, Vote: 1}, result)
+	})
+
+	t.Run("Retract", func(t *testing.T) {
+		mock.ExpectExec(`DELETE FROM votes WHERE user_id = \$1 AND post_id = \$2`).
+			WithArgs(int64(3), int64(1)).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectQuery(`SELECT score FROM posts`).
+			WithArgs(int64(1)).
+			WillReturnRows(sqlmock.NewRows([]string{"score"}).AddRow(4))
+
+		result, err := repo.VotePost(context.Background(), 3, 1, 0)
+		require.NoError(t, err)
+		assert.Equal(t, &entity.VoteResult{Score: 4, Vote: 0}, result)
+	})
+
+	t.Run("Missing or trashed post", func(t *testing.T) {
+		mock.ExpectExec(`INSERT INTO votes`).
+			WithArgs(int64(3), int64(9), -1).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+
+		_, err := repo.VotePost(context.Background(), 3, 9, -1)
+		assert.ErrorIs(t, err, ErrPostNotFound)
+
+		mock.ExpectExec(`DELETE FROM votes`).
+			WithArgs(int64(3), int64(9)).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectQuery(`SELECT score FROM posts`).
+			WithArgs(int64(9)).
+			WillReturnError(sql.ErrNoRows)
+
+		_, err = repo.VotePost(context.Background(), 3, 9, 0)
+		assert.ErrorIs(t, err, ErrPostNotFound)
+	})
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestVoteComment(t *testing.T) {
+	repo, mock := newVoteRepoMock(t)
+
+	t.Run("Downvote", func(t *testing.T) {
+		mock.ExpectExec(`INSERT INTO votes \(user_id, comment_id, value\)\s+SELECT \$1, c.id, \$3\s+FROM comments c\s+` +
+			`JOIN posts p ON p.id = c.post_id AND p.deleted_at IS NULL AND p.hidden_at IS NULL\s+` +
+			`WHERE c.id = \$2 AND c.post_id = \$4 AND c.deleted_at IS NULL AND c.hidden_at IS NULL`).
+			WithArgs(int64(3), int64(7), -1, int64(1)).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectQuery(`SELECT score FROM comments WHERE id = \$1 AND post_id = \$2`).
+			WithArgs(int64(7), int64(1)).
+			WillReturnRows(sqlmock.NewRows([]string{"score"}).AddRow(-2))
+
+		result, err := repo.VoteComment(context.Background(), 3, 1, 7, -1)
+		require.NoError(t, err)
+		assert.Equal(t, &entity.VoteResult{Score: -2, Vote: -1}, result)
+	})
+
+	t.Run("Hidden, deleted, on a hidden post or on another post", func(t *testing.T) {
+		mock.ExpectExec(`INSERT INTO votes`).
+			WithArgs(int64(3), int64(7), 1, int64(2)).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+
+		_, err := repo.VoteComment(context.Background(), 3, 2, 7, 1)
+		assert.ErrorIs(t, err, ErrCommentNotFound)
+	})
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,287 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"backend.com/forum/apperrors"
+	"github.com/Ulyana-kru00/forum-project/forum-servise/internal/entity"
+	"github.com/Ulyana-kru00/forum-project/forum-servise/internal/repository"
+	"github.com/Ulyana-kru00/forum-project/forum-servise/pkg/blobstore"
+	"github.com/Ulyana-kru00/forum-project/forum-servise/pkg/logger"
+	"github.com/Ulyana-kru00/forum-project/forum-servise/pkg/media"
+	"github.com/Ulyana-kru00/forum-project/forum-servise/pkg/verifier"
+)
+
+var (
+	errEmptyUpload          = apperrors.New(apperrors.ErrInvalidArgument, "file is empty")
+	errNoAttachments        = apperrors.New(apperrors.ErrInvalidArgument, "attachment ids are required")
+	errTooManyAttachments   = apperrors.New(apperrors.ErrInvalidArgument, fmt.Sprintf("at most %d attachments can be attached at once", maxAttachBatch))
+	errAttachNotAuthor      = apperrors.New(apperrors.ErrPermissionDenied, "only the author can attach files to a post")
+	errNoThumbnail          = apperrors.New(apperrors.ErrNotFound, "attachment has no thumbnail")
+	errInvalidAttachmentIDs = apperrors.New(apperrors.ErrInvalidArgument, "attachment ids must be positive")
+)
+
+const (
+	maxAttachBatch    = 20
+	maxFilenameLength = 255
+)
+
+type AttachmentConfig struct {
+	// MaxSize is the largest accepted upload in bytes.
+	MaxSize int64
+	// ThumbnailSize is the longest side of image thumbnails in pixels.
+	ThumbnailSize int
+	// OrphanTTL is how long an upload may stay unattached before it is
+	// garbage-collected.
+	OrphanTTL time.Duration
+	// GCBatch bounds the number of contents one collection removes.
+	GCBatch int
+}
+
+type AttachmentUsecaseInterface interface {
+	Upload(ctx context.Context, token, filename string, r io.Reader) (*entity.Attachment, error)
+	AttachToPost(ctx context.Context, token string, postID int64, ids []int64) ([]*entity.Attachment, error)
+	ListPostAttachments(ctx context.Context, postID int64) ([]*entity.Attachment, error)
+	Open(ctx context.Context, token string, id int64, thumbnail bool) (*entity.Attachment, io.ReadCloser, error)
+}
+
+type AttachmentUsecase struct {
+	attachmentRepo repository.AttachmentRepository
+	postRepo       repository.PostRepository
+	store          blobstore.BlobStore
+	verifier       verifier.TokenVerifier
+	cfg            AttachmentConfig
+	logger         *logger.Logger
+	now            func() time.Time
+}
+
+func NewAttachmentUsecase(
+	attachmentRepo repository.AttachmentRepository,
+	postRepo repository.PostRepository,
+	store blobstore.BlobStore,
+	tokenVerifier verifier.TokenVerifier,
+	cfg AttachmentConfig,
+	logger *logger.Logger,
+) *AttachmentUsecase {
+	return &AttachmentUsecase{
+		attachmentRepo: attachmentRepo,
+		postRepo:       postRepo,
+		store:          store,
+		verifier:       tokenVerifier,
+		cfg:            cfg,
+		logger:         logger,
+		now:            time.Now,
+	}
+}
+
+// Upload stores a file of the caller that is not attached to any post yet.
+// The type is sniffed from the content; images also get a thumbnail.
+// Identical content is stored once, under a name derived from its hash.
+func (uc *AttachmentUsecase) Upload(ctx context.Context, token, filename string, r io.Reader) (*entity.Attachment, error) {
+	claims, err := uc.verifier.Verify(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := io.ReadAll(io.LimitReader(r, uc.cfg.MaxSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > uc.cfg.MaxSize {
+		return nil, apperrors.New(apperrors.ErrTooLarge, fmt.Sprintf("file must be at most %d bytes", uc.cfg.MaxSize))
+	}
+	if len(data) == 0 {
+		return nil, errEmptyUpload
+	}
+
+	contentType, ext, err := media.Sniff(data)
+	if err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256(data)
+
+	attachment := &entity.Attachment{
+		UploaderID:  &claims.UserID,
+		Hash:        hex.EncodeToString(sum[:]),
+		Filename:    cleanFilename(filename, ext),
+		ContentType: contentType,
+		Size:        int64(len(data)),
+	}
+
+	var thumb *media.Thumbnail
+	if media.IsImage(contentType) {
+		if thumb, err = media.MakeThumbnail(data, uc.cfg.ThumbnailSize); err != nil {
+			return nil, err
+		}
+		attachment.Width = &thumb.Width
+		attachment.Height = &thumb.Height
+		attachment.HasThumbnail = true
+	}
+
+	_, err = uc.attachmentRepo.CreateAttachment(ctx, attachment, func(ctx context.Context) error {
+		if err := uc.store.Put(ctx, attachment.BlobKey(), bytes.NewReader(data), attachment.Size, contentType); err != nil {
+			return err
+		}
+		if thumb == nil {
+			return nil
+		}
+		return uc.store.Put(ctx, attachment.ThumbnailKey(), bytes.NewReader(thumb.Data), int64(len(thumb.Data)), media.ThumbnailType)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return attachment, nil
+}
+
+// AttachToPost attaches uploads of the caller to one of their posts, or to
+// any post for admins.
+func (uc *AttachmentUsecase) AttachToPost(ctx context.Context, token string, postID int64, ids []int64) ([]*entity.Attachment, error) {
+	ids, err := attachmentIDs(ids)
+	if err != nil {
+		return nil, err
+	}
+
+	claims, err := uc.verifier.Verify(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	post, err := uc.postRepo.GetPostByID(ctx, postID)
+	if err != nil {
+		return nil, err
+	}
+	if post.AuthorID != claims.UserID && claims.Role != "admin" {
+		return nil, errAttachNotAuthor
+	}
+
+	return uc.attachmentRepo.AttachToPost(ctx, postID, claims.UserID, ids)
+}
+
+func (uc *AttachmentUsecase) ListPostAttachments(ctx context.Context, postID int64) ([]*entity.Attachment, error) {
+	if _, err := uc.postRepo.GetPostByID(ctx, postID); err != nil {
+		return nil, err
+	}
+	return uc.attachmentRepo.ListPostAttachments(ctx, postID)
+}
+
+// Open returns an attachment together with its content, or the content of
+// its thumbnail. The caller closes the reader. The token is optional;
+// attachments of hidden or deleted posts are only served to moderators.
+func (uc *AttachmentUsecase) Open(ctx context.Context, token string, id int64, thumbnail bool) (*entity.Attachment, io.ReadCloser, error) {
+	includeHidden := false
+	if token != "" {
+		claims, err := uc.verifier.Verify(ctx, token)
+		if err != nil {
+			return nil, nil, err
+		}
+		includeHidden = isModerator(claims.Role)
+	}
+
+	attachment, err := uc.attachmentRepo.GetAttachment(ctx, id, includeHidden)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	key := attachment.BlobKey()
+	if thumbnail {
+		if !attachment.HasThumbnail {
+			return nil, nil, errNoThumbnail
+		}
+		key = attachment.ThumbnailKey()
+	}
+
+	rc, err := uc.store.Get(ctx, key)
+	if err != nil {
+		return nil, nil, err
+	}
+	return attachment, rc, nil
+}
+
+// CollectGarbage deletes uploads that stayed unattached for longer than
+// OrphanTTL or whose post was deleted, together with their content unless
+// another attachment shares it.
+func (uc *AttachmentUsecase) CollectGarbage(ctx context.Context) (int64, error) {
+	before := uc.now().Add(-uc.cfg.OrphanTTL)
+	return uc.attachmentRepo.DeleteOrphans(ctx, before, uc.cfg.GCBatch, func(ctx context.Context, hash string) error {
+		if err := uc.store.Delete(ctx, entity.ThumbnailKey(hash)); err != nil {
+			return err
+		}
+		return uc.store.Delete(ctx, entity.BlobKey(hash))
+	})
+}
+
+// RunGarbageCollector collects garbage every interval until ctx is
+// cancelled.
+func (uc *AttachmentUsecase) RunGarbageCollector(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			n, err := uc.CollectGarbage(ctx)
+			if err != nil {
+				uc.logger.Warnw("Failed to collect orphaned attachments", "error", err)
+			} else if n > 0 {
+				uc.logger.Infow("Collected orphaned attachments", "count", n)
+			}
+		}
+	}
+}
+
+// attachmentIDs validates the ids of a request and drops duplicates.
+func attachmentIDs(ids []int64) ([]int64, error) {
+	if len(ids) == 0 {
+		return nil, errNoAttachments
+	}
+	seen := make(map[int64]bool, len(ids))
+	unique := make([]int64, 0, len(ids))
+	for _, id := range ids {
+		if id <= 0 {
+			return nil, errInvalidAttachmentIDs
+		}
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+	if len(unique) > maxAttachBatch {
+		return nil, errTooManyAttachments
+	}
+	return unique, nil
+}
+
+// cleanFilename keeps the base name of a client-supplied file name without
+// control characters, and makes its extension match the sniffed type.
+func cleanFilename(name, ext string) string {
+	name = path.Base(strings.ReplaceAll(name, `\`, "/"))
+	name = strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) || r == utf8.RuneError {
+			return -1
+		}
+		return r
+	}, name)
+	name = strings.TrimSpace(strings.TrimSuffix(name, path.Ext(name)))
+	if name == "" || name == "." || name == "/" {
+		name = "file"
+	}
+
+	if maxBase := maxFilenameLength - len(ext); len(name) > maxBase {
+		name = name[:maxBase]
+		for !utf8.ValidString(name) {
+			name = name[:len(name)-1]
+		}
+	}
+	return name + ext
+}
@@ -0,0 +1,219 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/png"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"backend.com/forum/apperrors"
+	"github.com/Ulyana-kru00/forum-project/forum-servise/internal/entity"
+	"github.com/Ulyana-kru00/forum-project/forum-servise/internal/repository"
+	"github.com/Ulyana-kru00/forum-project/forum-servise/pkg/blobstore"
+	"github.com/Ulyana-kru00/forum-project/forum-servise/pkg/logger"
+	"github.com/Ulyana-kru00/forum-project/forum-servise/pkg/media"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+var testAttachmentConfig = AttachmentConfig{
+	MaxSize:       1 << 20,
+	ThumbnailSize: 64,
+	OrphanTTL:     time.Hour,
+	GCBatch:       100,
+}
+
+func newAttachmentUsecase(t *testing.T, repo *MockAttachmentRepository, postRepo *MockPostRepository, role string) (*AttachmentUsecase, blobstore.BlobStore) {
+	store, err := blobstore.NewLocalStore(t.TempDir())
+	require.NoError(t, err)
+	log := &logger.Logger{SugaredLogger: zap.NewNop().Sugar()}
+	return NewAttachmentUsecase(repo, postRepo, store, roleVerifier(role), testAttachmentConfig, log), store
+}
+
+func pngData(t *testing.T, w, h int) []byte {
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, image.NewGray(image.Rect(0, 0, w, h))))
+	return buf.Bytes()
+}
+
+// storingRepo records uploads the way the database would, running the
+// store callback before "inserting".
+func storingRepo() *MockAttachmentRepository {
+	return &MockAttachmentRepository{
+		CreateAttachmentFunc: func(ctx context.Context, attachment *entity.Attachment, store func(ctx context.Context) error) (int64, error) {
+			if err := store(ctx); err != nil {
+				return 0, err
+			}
+			attachment.ID = 1
+			return 1, nil
+		},
+	}
+}
+
+func TestAttachmentUsecase_Upload(t *testing.T) {
+	t.Run("Image", func(t *testing.T) {
+		uc, store := newAttachmentUsecase(t, storingRepo(), nil, "user")
+
+		a, err := uc.Upload(context.Background(), "token", `C:\photos\cat.jpeg`, bytes.NewReader(pngData(t, 200, 100)))
+		require.NoError(t, err)
+		assert.Equal(t, "cat.png", a.Filename, "extension follows the sniffed type")
+		assert.Equal(t, "image/png", a.ContentType)
+		assert.Equal(t, int64(1), *a.UploaderID)
+		assert.Equal(t, 200, *a.Width)
+		assert.True(t, a.HasThumbnail)
+		assert.Len(t, a.Hash, 64)
+
+		rc, err := store.Get(context.Background(), a.ThumbnailKey())
+		require.NoError(t, err)
+		defer rc.Close()
+		cfg, format, err := image.DecodeConfig(rc)
+		require.NoError(t, err)
+		assert.Equal(t, "jpeg", format)
+		assert.Equal(t, 64, cfg.Width)
+	})
+
+	t.Run("Text", func(t *testing.T) {
+		uc, store := newAttachmentUsecase(t, storingRepo(), nil, "user")
+
+		a, err := uc.Upload(context.Background(), "token", "notes", strings.NewReader("plain notes"))
+		require.NoError(t, err)
+		assert.Equal(t, "notes.txt", a.Filename)
+		assert.False(t, a.HasThumbnail)
+
+		rc, err := store.Get(context.Background(), a.BlobKey())
+		require.NoError(t, err)
+		data, _ := io.ReadAll(rc)
+		rc.Close()
+		assert.Equal(t, "plain notes", string(data))
+	})
+
+	t.Run("Too large", func(t *testing.T) {
+		uc, _ := newAttachmentUsecase(t, storingRepo(), nil, "user")
+
+		_, err := uc.Upload(context.Background(), "token", "big.txt", strings.NewReader(strings.Repeat("a", 1<<20+1)))
+		assert.ErrorIs(t, err, apperrors.ErrTooLarge)
+	})
+
+	t.Run("Unsupported type", func(t *testing.T) {
+		uc, _ := newAttachmentUsecase(t, storingRepo(), nil, "user")
+
+		_, err := uc.Upload(context.Background(), "token", "page.png", strings.NewReader("<html><body>hi</body></html>"))
+		assert.ErrorIs(t, err, media.ErrUnsupportedType)
+	})
+
+	t.Run("Empty", func(t *testing.T) {
+		uc, _ := newAttachmentUsecase(t, storingRepo(), nil, "user")
+
+		_, err := uc.Upload(context.Background(), "token", "empty.txt", strings.NewReader(""))
+		assert.ErrorIs(t, err, errEmptyUpload)
+	})
+}
+
+func TestAttachmentUsecase_AttachToPost(t *testing.T) {
+	var gotIDs []int64
+	repo := &MockAttachmentRepository{
+		AttachToPostFunc: func(ctx context.Context, postID, uploaderID int64, ids []int64) ([]*entity.Attachment, error) {
+			gotIDs = ids
+			return []*entity.Attachment{{ID: ids[0], PostID: &postID}}, nil
+		},
+	}
+	postRepo := &MockPostRepository{
+		GetPostByIDFunc: func(ctx context.Context, id int64) (*entity.Post, error) {
+			if id == 9 {
+				return nil, repository.ErrPostNotFound
+			}
+			return &entity.Post{ID: id, AuthorID: id}, nil
+		},
+	}
+
+	tests := []struct {
+		name    string
+		role    string
+		postID  int64
+		ids     []int64
+		wantErr error
+	}{
+		{"Own post", "user", 1, []int64{3, 3}, nil},
+		{"Admin on another post", "admin", 2, []int64{3}, nil},
+		{"Another post", "user", 2, []int64{3}, apperrors.ErrPermissionDenied},
+		{"Missing post", "user", 9, []int64{3}, apperrors.ErrNotFound},
+		{"No ids", "user", 1, nil, errNoAttachments},
+		{"Invalid id", "user", 1, []int64{0}, errInvalidAttachmentIDs},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc, _ := newAttachmentUsecase(t, repo, postRepo, tt.role)
+
+			_, err := uc.AttachToPost(context.Background(), "token", tt.postID, tt.ids)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, []int64{3}, gotIDs, "duplicates are dropped")
+		})
+	}
+}
+
+func TestAttachmentUsecase_Open(t *testing.T) {
+	uc, store := newAttachmentUsecase(t, nil, nil, "user")
+	hash := strings.Repeat("ab", 32)
+	require.NoError(t, store.Put(context.Background(), entity.BlobKey(hash), strings.NewReader("data"), 4, ""))
+	var gotIncludeHidden bool
+	uc.attachmentRepo = &MockAttachmentRepository{
+		GetAttachmentFunc: func(ctx context.Context, id int64, includeHidden bool) (*entity.Attachment, error) {
+			gotIncludeHidden = includeHidden
+			return &entity.Attachment{ID: id, Hash: hash}, nil
+		},
+	}
+
+	_, rc, err := uc.Open(context.Background(), "", 1, false)
+	require.NoError(t, err)
+	data, _ := io.ReadAll(rc)
+	rc.Close()
+	assert.Equal(t, "data", string(data))
+	assert.False(t, gotIncludeHidden, "anonymous readers do not see hidden posts")
+
+	_, _, err = uc.Open(context.Background(), "", 1, true)
+	assert.ErrorIs(t, err, errNoThumbnail)
+
+	for role, want := range map[string]bool{"user": false, "moderator": true, "admin": true} {
+		uc.verifier = roleVerifier(role)
+		_, rc, err := uc.Open(context.Background(), "token", 1, false)
+		require.NoError(t, err, role)
+		rc.Close()
+		assert.Equal(t, want, gotIncludeHidden, role)
+	}
+}
+
+func TestAttachmentUsecase_CollectGarbage(t *testing.T) {
+	now := time.Date(2024, 1, 2, 12, 0, 0, 0, time.UTC)
+	hash := strings.Repeat("cd", 32)
+	var before time.Time
+	repo := &MockAttachmentRepository{
+		DeleteOrphansFunc: func(ctx context.Context, b time.Time, limit int, remove func(ctx context.Context, hash string) error) (int64, error) {
+			before = b
+			return 1, remove(ctx, hash)
+		},
+	}
+	uc, store := newAttachmentUsecase(t, repo, nil, "user")
+	uc.now = func() time.Time { return now }
+	require.NoError(t, store.Put(context.Background(), entity.BlobKey(hash), strings.NewReader("x"), 1, ""))
+	require.NoError(t, store.Put(context.Background(), entity.ThumbnailKey(hash), strings.NewReader("y"), 1, ""))
+
+	n, err := uc.CollectGarbage(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), n)
+	assert.Equal(t, now.Add(-time.Hour), before)
+
+	_, err = store.Get(context.Background(), entity.BlobKey(hash))
+	assert.ErrorIs(t, err, blobstore.ErrNotFound)
+	_, err = store.Get(context.Background(), entity.ThumbnailKey(hash))
+	assert.ErrorIs(t, err, blobstore.ErrNotFound)
+}
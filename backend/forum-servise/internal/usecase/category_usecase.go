@@ -0,0 +1,145 @@
+package usecase
+
+import (
+	"context"
+	"regexp"
+	"strings"
+
+	"backend.com/forum/apperrors"
+	"github.com/Ulyana-kru00/forum-project/forum-servise/internal/entity"
+	"github.com/Ulyana-kru00/forum-project/forum-servise/internal/repository"
+	"github.com/Ulyana-kru00/forum-project/forum-servise/pkg/verifier"
+)
+
+var (
+	errAdminOnly        = apperrors.New(apperrors.ErrPermissionDenied, "only admins can manage categories")
+	errEmptyCategory    = apperrors.New(apperrors.ErrInvalidArgument, "category name is required")
+	errInvalidSlug      = apperrors.New(apperrors.ErrInvalidArgument, "slug may contain only lowercase latin letters, digits and dashes")
+	errSlugRequired     = apperrors.New(apperrors.ErrInvalidArgument, "slug is required when the name has no latin letters or digits")
+	errNegativePosition = apperrors.New(apperrors.ErrInvalidArgument, "position must not be negative")
+)
+
+var (
+	slugPattern  = regexp.MustCompile(`^[a-z0-9]+(?:-[a-z0-9]+)*$`)
+	nonSlugChars = regexp.MustCompile(`[^a-z0-9]+`)
+)
+
+// maxSlugLength matches the categories.slug column.
+const maxSlugLength = 100
+
+type CategoryUsecaseInterface interface {
+	CreateCategory(ctx context.Context, token string, category *entity.Category) (*entity.Category, error)
+	UpdateCategory(ctx context.Context, token string, category *entity.Category) (*entity.Category, error)
+	GetCategoryByID(ctx context.Context, id int64) (*entity.Category, error)
+	GetCategoryBySlug(ctx context.Context, slug string) (*entity.Category, error)
+	ListCategories(ctx context.Context) ([]*entity.Category, error)
+}
+
+type CategoryUsecase struct {
+	categoryRepo repository.CategoryRepository
+	verifier     verifier.TokenVerifier
+}
+
+func NewCategoryUsecase(categoryRepo repository.CategoryRepository, tokenVerifier verifier.TokenVerifier) *CategoryUsecase {
+	return &CategoryUsecase{
+		categoryRepo: categoryRepo,
+		verifier:     tokenVerifier,
+	}
+}
+
+// CreateCategory adds a category. The slug is derived from the name when
+// it is not given.
+func (uc *CategoryUsecase) CreateCategory(ctx context.Context, token string, category *entity.Category) (*entity.Category, error) {
+	if err := uc.requireAdmin(ctx, token); err != nil {
+		return nil, err
+	}
+	if err := normalizeCategory(category); err != nil {
+		return nil, err
+	}
+	if category.Slug == "" {
+		category.Slug = slugify(category.Name)
+		if category.Slug == "" {
+			return nil, errSlugRequired
+		}
+	}
+
+	if _, err := uc.categoryRepo.CreateCategory(ctx, category); err != nil {
+		return nil, err
+	}
+	return category, nil
+}
+
+// UpdateCategory replaces name, description and position of a category. An
+// empty slug keeps the current one so existing links stay valid.
+func (uc *CategoryUsecase) UpdateCategory(ctx context.Context, token string, category *entity.Category) (*entity.Category, error) {
+	if err := uc.requireAdmin(ctx, token); err != nil {
+		return nil, err
+	}
+	if err := normalizeCategory(category); err != nil {
+		return nil, err
+	}
+
+	current, err := uc.categoryRepo.GetCategoryByID(ctx, category.ID)
+	if err != nil {
+		return nil, err
+	}
+	if category.Slug == "" {
+		category.Slug = current.Slug
+	}
+
+	if err := uc.categoryRepo.UpdateCategory(ctx, category); err != nil {
+		return nil, err
+	}
+	category.PostCount = current.PostCount
+	category.LastActivityAt = current.LastActivityAt
+	return category, nil
+}
+
+func (uc *CategoryUsecase) GetCategoryByID(ctx context.Context, id int64) (*entity.Category, error) {
+	return uc.categoryRepo.GetCategoryByID(ctx, id)
+}
+
+func (uc *CategoryUsecase) GetCategoryBySlug(ctx context.Context, slug string) (*entity.Category, error) {
+	return uc.categoryRepo.GetCategoryBySlug(ctx, slug)
+}
+
+func (uc *CategoryUsecase) ListCategories(ctx context.Context) ([]*entity.Category, error) {
+	return uc.categoryRepo.ListCategories(ctx)
+}
+
+func (uc *CategoryUsecase) requireAdmin(ctx context.Context, token string) error {
+	claims, err := uc.verifier.Verify(ctx, token)
+	if err != nil {
+		return err
+	}
+	if claims.Role != "admin" {
+		return errAdminOnly
+	}
+	return nil
+}
+
+func normalizeCategory(category *entity.Category) error {
+	category.Name = strings.TrimSpace(category.Name)
+	category.Slug = strings.TrimSpace(category.Slug)
+	category.Description = strings.TrimSpace(category.Description)
+
+	if category.Name == "" {
+		return errEmptyCategory
+	}
+	if category.Slug != "" && (len(category.Slug) > maxSlugLength || !slugPattern.MatchString(category.Slug)) {
+		return errInvalidSlug
+	}
+	if category.Position < 0 {
+		return errNegativePosition
+	}
+	return nil
+}
+
+// slugify keeps the latin letters and digits of name, joined by dashes.
+func slugify(name string) string {
+	slug := strings.Trim(nonSlugChars.ReplaceAllString(strings.ToLower(name), "-"), "-")
+	if len(slug) > maxSlugLength {
+		slug = strings.TrimRight(slug[:maxSlugLength], "-")
+	}
+	return slug
+}
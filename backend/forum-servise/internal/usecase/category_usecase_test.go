@@ -0,0 +1,112 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"backend.com/forum/apperrors"
+	pb "backend.com/forum/proto"
+	"github.com/Ulyana-kru00/forum-project/forum-servise/internal/entity"
+	"github.com/Ulyana-kru00/forum-project/forum-servise/internal/repository"
+	"github.com/Ulyana-kru00/forum-project/forum-servise/pkg/verifier"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+)
+
+// roleVerifier accepts any token as user 1, "user1", with the given role.
+func roleVerifier(role string) verifier.TokenVerifier {
+	return verifier.NewRemote(&MockAuthServiceClient{
+		ValidateTokenFunc: func(ctx context.Context, in *pb.ValidateTokenRequest, opts ...grpc.CallOption) (*pb.ValidateTokenResponse, error) {
+			return &pb.ValidateTokenResponse{Valid: true, UserId: 1, Username: "user1", Role: role}, nil
+		},
+	})
+}
+
+func TestCategoryUsecase_CreateCategory(t *testing.T) {
+	tests := []struct {
+		name     string
+		role     string
+		input    entity.Category
+		wantSlug string
+		wantErr  error
+	}{
+		{name: "Slug from name", role: "admin", input: entity.Category{Name: "  Go & Rust 2024 "}, wantSlug: "go-rust-2024"},
+		{name: "Explicit slug", role: "admin", input: entity.Category{Name: "Новости", Slug: "news"}, wantSlug: "news"},
+		{name: "Non-latin name needs slug", role: "admin", input: entity.Category{Name: "Новости"}, wantErr: errSlugRequired},
+		{name: "Invalid slug", role: "admin", input: entity.Category{Name: "Go", Slug: "Go Lang"}, wantErr: errInvalidSlug},
+		{name: "Empty name", role: "admin", input: entity.Category{Name: "   "}, wantErr: errEmptyCategory},
+		{name: "Negative position", role: "admin", input: entity.Category{Name: "Go", Position: -1}, wantErr: errNegativePosition},
+		{name: "Not admin", role: "user", input: entity.Category{Name: "Go"}, wantErr: apperrors.ErrPermissionDenied},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var saved *entity.Category
+			repo := &MockCategoryRepository{
+				CreateCategoryFunc: func(ctx context.Context, category *entity.Category) (int64, error) {
+					saved = category
+					category.ID = 7
+					return 7, nil
+				},
+			}
+			uc := NewCategoryUsecase(repo, roleVerifier(tt.role))
+
+			input := tt.input
+			got, err := uc.CreateCategory(context.Background(), "token", &input)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Nil(t, saved)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantSlug, got.Slug)
+			assert.Equal(t, int64(7), got.ID)
+		})
+	}
+}
+
+func TestCategoryUsecase_UpdateCategory(t *testing.T) {
+	now := time.Now()
+	current := &entity.Category{ID: 1, Name: "Go", Slug: "go", PostCount: 4, LastActivityAt: &now}
+
+	t.Run("Empty slug keeps current", func(t *testing.T) {
+		var saved entity.Category
+		repo := &MockCategoryRepository{
+			GetCategoryByIDFunc: func(ctx context.Context, id int64) (*entity.Category, error) {
+				return current, nil
+			},
+			UpdateCategoryFunc: func(ctx context.Context, category *entity.Category) error {
+				saved = *category
+				return nil
+			},
+		}
+		uc := NewCategoryUsecase(repo, roleVerifier("admin"))
+
+		got, err := uc.UpdateCategory(context.Background(), "token", &entity.Category{ID: 1, Name: "Golang", Position: 3})
+		require.NoError(t, err)
+		assert.Equal(t, "go", saved.Slug)
+		assert.Equal(t, "Golang", saved.Name)
+		assert.Equal(t, int64(4), got.PostCount)
+	})
+
+	t.Run("Unknown category", func(t *testing.T) {
+		repo := &MockCategoryRepository{
+			GetCategoryByIDFunc: func(ctx context.Context, id int64) (*entity.Category, error) {
+				return nil, repository.ErrCategoryNotFound
+			},
+		}
+		uc := NewCategoryUsecase(repo, roleVerifier("admin"))
+
+		_, err := uc.UpdateCategory(context.Background(), "token", &entity.Category{ID: 9, Name: "x"})
+		assert.ErrorIs(t, err, apperrors.ErrNotFound)
+	})
+
+	t.Run("Not admin", func(t *testing.T) {
+		uc := NewCategoryUsecase(&MockCategoryRepository{}, roleVerifier("user"))
+
+		_, err := uc.UpdateCategory(context.Background(), "token", &entity.Category{ID: 1, Name: "x"})
+		assert.ErrorIs(t, err, apperrors.ErrPermissionDenied)
+	})
+}
@@ -3,44 +3,122 @@ package usecase
 import (
 	"context"
 	"errors"
+	"strings"
 
+	"backend.com/forum/apperrors"
 	pb "backend.com/forum/proto"
 	"github.com/Ulyana-kru00/forum-project/forum-servise/internal/entity"
 	"github.com/Ulyana-kru00/forum-project/forum-servise/internal/repository"
+	"github.com/Ulyana-kru00/forum-project/forum-servise/pkg/loader"
+	"github.com/Ulyana-kru00/forum-project/forum-servise/pkg/markdown"
+	"github.com/Ulyana-kru00/forum-project/forum-servise/pkg/verifier"
+)
+
+var (
+	errEmptyComment    = apperrors.New(apperrors.ErrInvalidArgument, "comment content is required")
+	errParentOtherPost = apperrors.New(apperrors.ErrInvalidArgument, "parent comment belongs to another post")
+	errReplyToDeleted  = apperrors.New(apperrors.ErrInvalidArgument, "cannot reply to a deleted comment")
+	errThreadTooDeep   = apperrors.New(apperrors.ErrInvalidArgument, "reply is nested too deeply")
+	errPostLocked      = apperrors.New(apperrors.ErrPermissionDenied, "post is locked; new comments are not allowed")
+)
+
+const (
+	defaultCommentsLimit = 20
+	maxCommentsLimit     = 100
+	defaultRepliesLimit  = 3
+	maxRepliesLimit      = 20
 )
 
 type CommentUseCase struct {
 	CommentRepo repository.CommentRepository
 	postRepo    repository.PostRepository
 	AuthClient  pb.AuthServiceClient
+	verifier    verifier.TokenVerifier
+	// Notifier, when set, is told about every new comment.
+	Notifier Notifier
 }
 
 func NewCommentUseCase(
 	commentRepo repository.CommentRepository,
 	postRepo repository.PostRepository,
 	authClient pb.AuthServiceClient,
+	tokenVerifier verifier.TokenVerifier,
 ) *CommentUseCase {
 	return &CommentUseCase{
 		CommentRepo: commentRepo,
 		postRepo:    postRepo,
 		AuthClient:  authClient,
+		verifier:    tokenVerifier,
 	}
 }
 
-func (uc *CommentUseCase) CreateComment(ctx context.Context, comment *entity.Comment) error {
+// CreateComment stores a comment by the owner of token together with its
+// Markdown content rendered to HTML and notifies the users it concerns.
+// Locked posts take no new comments.
+func (uc *CommentUseCase) CreateComment(ctx context.Context, token string, comment *entity.Comment) error {
+	claims, err := uc.verifier.Verify(ctx, token)
+	if err != nil {
+		return err
+	}
+	comment.AuthorID = claims.UserID
+	comment.AuthorName = claims.Username
+
+	html, err := markdown.Render(comment.Content)
+	if err != nil {
+		return err
+	}
+	comment.ContentHTML = html
 
-	_, err := uc.postRepo.GetPostByID(ctx, comment.PostID)
+	post, err := uc.postRepo.GetPostByID(ctx, comment.PostID)
 	if err != nil {
 		return err
 	}
+	if post.Locked {
+		return errPostLocked
+	}
+
+	// The author name normally comes from the token; look it up only when
+	// the token does not carry one.
+	if comment.AuthorName == "" {
+		userResp, err := uc.AuthClient.GetUser(ctx, &pb.GetUserRequest{Id: comment.AuthorID})
+		if err != nil || userResp == nil || userResp.User == nil {
+			return errors.New("failed to get user info")
+		}
+		comment.AuthorName = userResp.User.Username
+	}
+
+	var parent *entity.Comment
+	if comment.ParentID != nil {
+		if parent, err = uc.checkParent(ctx, comment.PostID, *comment.ParentID); err != nil {
+			return err
+		}
+	}
 
-	userResp, err := uc.AuthClient.GetUser(ctx, &pb.GetUserRequest{Id: comment.AuthorID})
-	if err != nil || userResp == nil || userResp.User == nil {
-		return errors.New("failed to get user info")
+	if err := uc.CommentRepo.CreateComment(ctx, comment); err != nil {
+		return err
+	}
+	if uc.Notifier != nil {
+		uc.Notifier.CommentCreated(ctx, post, comment, parent)
 	}
+	return nil
+}
 
-	comment.AuthorName = userResp.User.Username
-	return uc.CommentRepo.CreateComment(ctx, comment)
+// checkParent makes sure a reply goes to a live comment of the same post
+// and stays within the depth cap, and returns that comment.
+func (uc *CommentUseCase) checkParent(ctx context.Context, postID, parentID int64) (*entity.Comment, error) {
+	parent, err := uc.CommentRepo.GetCommentByID(ctx, parentID)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case parent.PostID != postID:
+		return nil, errParentOtherPost
+	case parent.Deleted:
+		return nil, errReplyToDeleted
+	case parent.Depth >= repository.MaxCommentDepth:
+		return nil, errThreadTooDeep
+	}
+	return parent, nil
 }
 
 func (uc *CommentUseCase) GetCommentsByPostID(ctx context.Context, postID int64) ([]entity.Comment, error) {
@@ -50,30 +128,103 @@ func (uc *CommentUseCase) GetCommentsByPostID(ctx context.Context, postID int64)
 		return nil, err
 	}
 
-	return uc.CommentRepo.GetCommentsByPostID(ctx, postID)
+	comments, err := uc.CommentRepo.GetCommentsByPostID(ctx, postID)
+	if err != nil {
+		return nil, err
+	}
+
+	uc.resolveAuthorNames(ctx, comments)
+	return comments, nil
+}
+
+// resolveAuthorNames fills in current usernames in one batch; the name
+// stored with a comment is kept when auth-service does not know the author.
+func (uc *CommentUseCase) resolveAuthorNames(ctx context.Context, comments []entity.Comment) {
+	authorIDs := make([]int64, 0, len(comments))
+	for _, comment := range comments {
+		if !comment.Deleted {
+			authorIDs = append(authorIDs, comment.AuthorID)
+		}
+	}
+	names, _ := loader.FromContext(ctx, uc.AuthClient).Names(ctx, authorIDs)
+	for i := range comments {
+		if name, ok := names[comments[i].AuthorID]; ok {
+			comments[i].AuthorName = name
+		}
+	}
+}
+
+// GetCommentTree returns a page of the comment thread of a post, or of the
+// replies to filter.ParentID when it is set.
+func (uc *CommentUseCase) GetCommentTree(ctx context.Context, filter entity.CommentTreeFilter) (*entity.CommentPage, error) {
+	if _, err := uc.postRepo.GetPostByID(ctx, filter.PostID); err != nil {
+		return nil, err
+	}
+	if filter.ParentID != 0 {
+		parent, err := uc.CommentRepo.GetCommentByID(ctx, filter.ParentID)
+		if err != nil {
+			return nil, err
+		}
+		if parent.PostID != filter.PostID {
+			return nil, repository.ErrCommentNotFound
+		}
+	}
+
+	if filter.Sort == "" {
+		filter.Sort = entity.CommentSortNewest
+	}
+	if !filter.Sort.Valid() {
+		return nil, apperrors.New(apperrors.ErrInvalidArgument, "unknown sort: "+string(filter.Sort))
+	}
+	if filter.Limit <= 0 {
+		filter.Limit = defaultCommentsLimit
+	}
+	if filter.Limit > maxCommentsLimit {
+		filter.Limit = maxCommentsLimit
+	}
+	if filter.RepliesLimit <= 0 {
+		filter.RepliesLimit = defaultRepliesLimit
+	}
+	if filter.RepliesLimit > maxRepliesLimit {
+		filter.RepliesLimit = maxRepliesLimit
+	}
+
+	page, err := uc.CommentRepo.GetCommentTree(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	uc.resolveAuthorNames(ctx, page.Comments)
+	return page, nil
 }
 
-// func (uc *CommentUseCase) DeleteComment(ctx context.Context, id int64) error {
-// 	// Получаем комментарий
-// 	comments, err := uc.CommentRepo.GetCommentsByPostID(ctx, id)
-// 	if err != nil {
-// 		return err
-// 	}
-// 	if len(comments) == 0 {
-// 		return sql.ErrNoRows
-// 	}
-// 	comment := comments[0]
-
-// 	// Проверяем права доступа
-// 	userResp, err := uc.AuthClient.GetUser(ctx, &pb.GetUserRequest{Id: comment.AuthorID})
-// 	if err != nil || userResp == nil || userResp.User == nil {
-// 		return errors.New("failed to verify user")
-// 	}
-
-// 	// Проверяем, что пользователь является автором
-// 	if userResp.User.Id != comment.AuthorID {
-// 		return errors.New("permission denied")
-// 	}
-
-// 	return uc.CommentRepo.DeleteComment(ctx, id)
-// }
+// UpdateComment changes the content of a comment on the given post. The
+// caller must be its author or an admin.
+func (uc *CommentUseCase) UpdateComment(ctx context.Context, token string, postID, commentID int64, content string) (*entity.Comment, error) {
+	content = strings.TrimSpace(content)
+	if content == "" {
+		return nil, errEmptyComment
+	}
+	html, err := markdown.Render(content)
+	if err != nil {
+		return nil, err
+	}
+
+	claims, err := uc.verifier.Verify(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+
+	return uc.CommentRepo.UpdateComment(ctx, commentID, postID, claims.UserID, claims.Role, content, html)
+}
+
+// DeleteComment deletes a comment on the given post. The caller must be its
+// author or an admin.
+func (uc *CommentUseCase) DeleteComment(ctx context.Context, token string, postID, commentID int64) error {
+	claims, err := uc.verifier.Verify(ctx, token)
+	if err != nil {
+		return err
+	}
+
+	return uc.CommentRepo.DeleteComment(ctx, commentID, postID, claims.UserID, claims.Role)
+}
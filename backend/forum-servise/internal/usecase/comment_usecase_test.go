@@ -5,17 +5,22 @@ import (
 	"errors"
 	"testing"
 
+	"backend.com/forum/apperrors"
 	pb "backend.com/forum/proto"
 	"github.com/Ulyana-kru00/forum-project/forum-servise/internal/entity"
 	"github.com/Ulyana-kru00/forum-project/forum-servise/internal/repository"
+	"github.com/Ulyana-kru00/forum-project/forum-servise/pkg/verifier"
 	"github.com/stretchr/testify/assert"
 	"google.golang.org/grpc"
 )
 
 type MockCommentRepository struct {
 	CreateCommentFunc       func(ctx context.Context, comment *entity.Comment) error
+	GetCommentByIDFunc      func(ctx context.Context, id int64) (*entity.Comment, error)
 	GetCommentsByPostIDFunc func(ctx context.Context, postID int64) ([]entity.Comment, error)
-	DeleteCommentFunc       func(ctx context.Context, id int64) error
+	GetCommentTreeFunc      func(ctx context.Context, filter entity.CommentTreeFilter) (*entity.CommentPage, error)
+	UpdateCommentFunc       func(ctx context.Context, id, postID, authorID int64, role, content, contentHTML string) (*entity.Comment, error)
+	DeleteCommentFunc       func(ctx context.Context, id, postID, authorID int64, role string) error
 }
 
 func (m *MockCommentRepository) CreateComment(ctx context.Context, comment *entity.Comment) error {
@@ -26,8 +31,20 @@ func (m *MockCommentRepository) GetCommentsByPostID(ctx context.Context, postID
 	return m.GetCommentsByPostIDFunc(ctx, postID)
 }
 
-func (m *MockCommentRepository) DeleteComment(ctx context.Context, id int64) error {
-	return m.DeleteCommentFunc(ctx, id)
+func (m *MockCommentRepository) GetCommentByID(ctx context.Context, id int64) (*entity.Comment, error) {
+	return m.GetCommentByIDFunc(ctx, id)
+}
+
+func (m *MockCommentRepository) GetCommentTree(ctx context.Context, filter entity.CommentTreeFilter) (*entity.CommentPage, error) {
+	return m.GetCommentTreeFunc(ctx, filter)
+}
+
+func (m *MockCommentRepository) UpdateComment(ctx context.Context, id, postID, authorID int64, role, content, contentHTML string) (*entity.Comment, error) {
+	return m.UpdateCommentFunc(ctx, id, postID, authorID, role, content, contentHTML)
+}
+
+func (m *MockCommentRepository) DeleteComment(ctx context.Context, id, postID, authorID int64, role string) error {
+	return m.DeleteCommentFunc(ctx, id, postID, authorID, role)
 }
 
 func TestCommentUseCase_CreateComment(t *testing.T) {
@@ -82,6 +99,26 @@ func TestCommentUseCase_CreateComment(t *testing.T) {
 			wantErr:     true,
 			expectedErr: repository.ErrPostNotFound,
 		},
+		{
+			name: "Post locked",
+			comment: &entity.Comment{
+				PostID:   1,
+				Content:  "Test comment",
+				AuthorID: 1,
+			},
+			mockPost: func() *MockPostRepository {
+				return &MockPostRepository{
+					GetPostByIDFunc: func(ctx context.Context, id int64) (*entity.Post, error) {
+						return &entity.Post{ID: id, Locked: true}, nil
+					},
+				}
+			},
+			mockComment: func() *MockCommentRepository {
+				return &MockCommentRepository{}
+			},
+			wantErr:     true,
+			expectedErr: errPostLocked,
+		},
 	}
 
 	for _, tt := range tests {
@@ -90,9 +127,9 @@ func TestCommentUseCase_CreateComment(t *testing.T) {
 			mockComment := tt.mockComment()
 			mockAuth := &MockAuthServiceClient{}
 
-			uc := NewCommentUseCase(mockComment, mockPost, mockAuth)
+			uc := NewCommentUseCase(mockComment, mockPost, mockAuth, roleVerifier("user"))
 
-			err := uc.CreateComment(context.Background(), tt.comment)
+			err := uc.CreateComment(context.Background(), "token", tt.comment)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("CreateComment() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -174,7 +211,7 @@ func TestCommentUseCase_GetCommentsByPostID(t *testing.T) {
 			mockPost := &MockPostRepository{}
 			mockAuth := tt.mockAuth()
 
-			uc := NewCommentUseCase(mockComment, mockPost, mockAuth)
+			uc := NewCommentUseCase(mockComment, mockPost, mockAuth, verifier.NewRemote(mockAuth))
 
 			got, err := uc.GetCommentsByPostID(context.Background(), tt.postID)
 			if (err != nil) != tt.wantErr {
@@ -186,3 +223,146 @@ func TestCommentUseCase_GetCommentsByPostID(t *testing.T) {
 		})
 	}
 }
+
+func TestCommentUseCase_UpdateComment(t *testing.T) {
+	var gotRole, gotContent, gotHTML string
+	mockComment := &MockCommentRepository{
+		UpdateCommentFunc: func(ctx context.Context, id, postID, authorID int64, role, content, contentHTML string) (*entity.Comment, error) {
+			gotRole, gotContent, gotHTML = role, content, contentHTML
+			return &entity.Comment{ID: id, PostID: postID, AuthorID: authorID, Content: content}, nil
+		},
+	}
+	uc := NewCommentUseCase(mockComment, &MockPostRepository{}, &MockAuthServiceClient{}, roleVerifier("admin"))
+
+	got, err := uc.UpdateComment(context.Background(), "token", 1, 5, "  edited  ")
+	assert.NoError(t, err)
+	assert.Equal(t, "edited", got.Content)
+	assert.Equal(t, "admin", gotRole)
+	assert.Equal(t, "edited", gotContent)
+	assert.Equal(t, "<p>edited</p>\n", gotHTML)
+
+	_, err = uc.UpdateComment(context.Background(), "token", 1, 5, "   ")
+	assert.ErrorIs(t, err, errEmptyComment)
+}
+
+func TestCommentUseCase_DeleteComment(t *testing.T) {
+	mockComment := &MockCommentRepository{
+		DeleteCommentFunc: func(ctx context.Context, id, postID, authorID int64, role string) error {
+			if role != "admin" && authorID != 7 {
+				return repository.ErrPermissionDenied
+			}
+			return nil
+		},
+	}
+	uc := NewCommentUseCase(mockComment, &MockPostRepository{}, &MockAuthServiceClient{}, roleVerifier("user"))
+
+	err := uc.DeleteComment(context.Background(), "token", 1, 5)
+	assert.ErrorIs(t, err, repository.ErrPermissionDenied)
+
+	uc = NewCommentUseCase(mockComment, &MockPostRepository{}, &MockAuthServiceClient{}, roleVerifier("admin"))
+	assert.NoError(t, uc.DeleteComment(context.Background(), "token", 1, 5))
+}
+
+// recordingNotifier remembers the last comment it was told about.
+type recordingNotifier struct {
+	comment, parent *entity.Comment
+}
+
+func (n *recordingNotifier) PostCreated(ctx context.Context, post *entity.Post) {}
+
+func (n *recordingNotifier) CommentCreated(ctx context.Context, post *entity.Post, comment, parent *entity.Comment) {
+	n.comment, n.parent = comment, parent
+}
+
+func TestCommentUseCase_CreateReply(t *testing.T) {
+	parentID := int64(5)
+	tests := []struct {
+		name    string
+		parent  *entity.Comment
+		wantErr error
+	}{
+		{name: "Success", parent: &entity.Comment{ID: 5, PostID: 1, Depth: 2}},
+		{name: "Parent on another post", parent: &entity.Comment{ID: 5, PostID: 2}, wantErr: errParentOtherPost},
+		{name: "Deleted parent", parent: &entity.Comment{ID: 5, PostID: 1, Deleted: true}, wantErr: errReplyToDeleted},
+		{name: "Too deep", parent: &entity.Comment{ID: 5, PostID: 1, Depth: repository.MaxCommentDepth}, wantErr: errThreadTooDeep},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			created := false
+			mockComment := &MockCommentRepository{
+				GetCommentByIDFunc: func(ctx context.Context, id int64) (*entity.Comment, error) {
+					return tt.parent, nil
+				},
+				CreateCommentFunc: func(ctx context.Context, comment *entity.Comment) error {
+					created = true
+					return nil
+				},
+			}
+			mockPost := &MockPostRepository{
+				GetPostByIDFunc: func(ctx context.Context, id int64) (*entity.Post, error) {
+					return &entity.Post{ID: id}, nil
+				},
+			}
+			notifier := &recordingNotifier{}
+			uc := NewCommentUseCase(mockComment, mockPost, &MockAuthServiceClient{}, roleVerifier("user"))
+			uc.Notifier = notifier
+
+			err := uc.CreateComment(context.Background(), "token", &entity.Comment{
+				PostID: 1, ParentID: &parentID, Content: "Reply",
+			})
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.False(t, created)
+				assert.Nil(t, notifier.comment)
+				return
+			}
+			assert.NoError(t, err)
+			assert.True(t, created)
+			assert.Equal(t, tt.parent, notifier.parent)
+		})
+	}
+}
+
+func TestCommentUseCase_GetCommentTree(t *testing.T) {
+	var gotFilter entity.CommentTreeFilter
+	mockComment := &MockCommentRepository{
+		GetCommentByIDFunc: func(ctx context.Context, id int64) (*entity.Comment, error) {
+			return &entity.Comment{ID: id, PostID: 2}, nil
+		},
+		GetCommentTreeFunc: func(ctx context.Context, filter entity.CommentTreeFilter) (*entity.CommentPage, error) {
+			gotFilter = filter
+			return &entity.CommentPage{Comments: []entity.Comment{
+				{ID: 1, PostID: filter.PostID, AuthorID: 1, AuthorName: "old"},
+				{ID: 2, PostID: filter.PostID, Deleted: true, Content: entity.DeletedCommentPlaceholder},
+			}}, nil
+		},
+	}
+	mockPost := &MockPostRepository{
+		GetPostByIDFunc: func(ctx context.Context, id int64) (*entity.Post, error) {
+			return &entity.Post{ID: id}, nil
+		},
+	}
+	mockAuth := &MockAuthServiceClient{
+		GetUsersFunc: func(ctx context.Context, in *pb.GetUsersRequest, opts ...grpc.CallOption) (*pb.GetUsersResponse, error) {
+			assert.Equal(t, []int64{1}, in.Ids)
+			return &pb.GetUsersResponse{Users: []*pb.User{{Id: 1, Username: "alice"}}}, nil
+		},
+	}
+	uc := NewCommentUseCase(mockComment, mockPost, mockAuth, roleVerifier("user"))
+
+	page, err := uc.GetCommentTree(context.Background(), entity.CommentTreeFilter{PostID: 1, Limit: 1000})
+	assert.NoError(t, err)
+	assert.Equal(t, maxCommentsLimit, gotFilter.Limit)
+	assert.Equal(t, defaultRepliesLimit, gotFilter.RepliesLimit)
+	assert.Equal(t, entity.CommentSortNewest, gotFilter.Sort)
+	assert.Equal(t, "alice", page.Comments[0].AuthorName)
+	assert.Empty(t, page.Comments[1].AuthorName)
+
+	_, err = uc.GetCommentTree(context.Background(), entity.CommentTreeFilter{PostID: 1, Sort: "best"})
+	assert.ErrorIs(t, err, apperrors.ErrInvalidArgument)
+
+	// The parent comment belongs to post 2.
+	_, err = uc.GetCommentTree(context.Background(), entity.CommentTreeFilter{PostID: 1, ParentID: 5})
+	assert.ErrorIs(t, err, repository.ErrCommentNotFound)
+}
@@ -2,6 +2,7 @@ package usecase
 
 import (
 	"context"
+	"time"
 
 	pb "backend.com/forum/proto"
 	"github.com/Ulyana-kru00/forum-project/forum-servise/internal/entity"
@@ -10,10 +11,16 @@ import (
 
 type MockPostRepository struct {
 	CreatePostFunc  func(ctx context.Context, post *entity.Post) (int64, error)
-	GetPostsFunc    func(ctx context.Context) ([]*entity.Post, error)
+	GetPostsFunc    func(ctx context.Context, filter entity.PostFilter) (*entity.PostPage, error)
 	GetPostByIDFunc func(ctx context.Context, id int64) (*entity.Post, error)
 	DeletePostFunc  func(ctx context.Context, postID, authorID int64, role string) error
-	UpdatePostFunc  func(ctx context.Context, postID, authorID int64, role, title, content string) (*entity.Post, error)
+	UpdatePostFunc  func(ctx context.Context, postID, authorID int64, role, title, content, contentHTML string, categoryID int64, tags []string) (*entity.Post, error)
+
+	SetPinnedFunc         func(ctx context.Context, id int64, pinned bool) error
+	SetLockedFunc         func(ctx context.Context, id int64, locked bool) error
+	ListDeletedPostsFunc  func(ctx context.Context, limit int, cursor string) (*entity.PostPage, error)
+	RestorePostFunc       func(ctx context.Context, id int64) error
+	PurgeDeletedPostsFunc func(ctx context.Context, before time.Time, limit int) (int64, error)
 }
 
 func (m *MockPostRepository) CreatePost(ctx context.Context, post *entity.Post) (int64, error) {
@@ -23,9 +30,9 @@ func (m *MockPostRepository) CreatePost(ctx context.Context, post *entity.Post)
 	return 0, nil
 }
 
-func (m *MockPostRepository) GetPosts(ctx context.Context) ([]*entity.Post, error) {
+func (m *MockPostRepository) GetPosts(ctx context.Context, filter entity.PostFilter) (*entity.PostPage, error) {
 	if m.GetPostsFunc != nil {
-		return m.GetPostsFunc(ctx)
+		return m.GetPostsFunc(ctx, filter)
 	}
 	return nil, nil
 }
@@ -44,9 +51,257 @@ func (m *MockPostRepository) DeletePost(ctx context.Context, postID, authorID in
 	return nil
 }
 
-func (m *MockPostRepository) UpdatePost(ctx context.Context, postID, authorID int64, role, title, content string) (*entity.Post, error) {
+func (m *MockPostRepository) UpdatePost(ctx context.Context, postID, authorID int64, role, title, content, contentHTML string, categoryID int64, tags []string) (*entity.Post, error) {
 	if m.UpdatePostFunc != nil {
-		return m.UpdatePostFunc(ctx, postID, authorID, role, title, content)
+		return m.UpdatePostFunc(ctx, postID, authorID, role, title, content, contentHTML, categoryID, tags)
+	}
+	return nil, nil
+}
+
+func (m *MockPostRepository) SetPinned(ctx context.Context, id int64, pinned bool) error {
+	if m.SetPinnedFunc != nil {
+		return m.SetPinnedFunc(ctx, id, pinned)
+	}
+	return nil
+}
+
+func (m *MockPostRepository) SetLocked(ctx context.Context, id int64, locked bool) error {
+	if m.SetLockedFunc != nil {
+		return m.SetLockedFunc(ctx, id, locked)
+	}
+	return nil
+}
+
+func (m *MockPostRepository) ListDeletedPosts(ctx context.Context, limit int, cursor string) (*entity.PostPage, error) {
+	if m.ListDeletedPostsFunc != nil {
+		return m.ListDeletedPostsFunc(ctx, limit, cursor)
+	}
+	return nil, nil
+}
+
+func (m *MockPostRepository) RestorePost(ctx context.Context, id int64) error {
+	if m.RestorePostFunc != nil {
+		return m.RestorePostFunc(ctx, id)
+	}
+	return nil
+}
+
+func (m *MockPostRepository) PurgeDeletedPosts(ctx context.Context, before time.Time, limit int) (int64, error) {
+	if m.PurgeDeletedPostsFunc != nil {
+		return m.PurgeDeletedPostsFunc(ctx, before, limit)
+	}
+	return 0, nil
+}
+
+type MockSearchRepository struct {
+	SearchFunc func(ctx context.Context, filter entity.SearchFilter) (*entity.SearchPage, error)
+}
+
+func (m *MockSearchRepository) Search(ctx context.Context, filter entity.SearchFilter) (*entity.SearchPage, error) {
+	if m.SearchFunc != nil {
+		return m.SearchFunc(ctx, filter)
+	}
+	return nil, nil
+}
+
+type MockVoteRepository struct {
+	VotePostFunc    func(ctx context.Context, userID, postID int64, value int) (*entity.VoteResult, error)
+	VoteCommentFunc func(ctx context.Context, userID, postID, commentID int64, value int) (*entity.VoteResult, error)
+}
+
+func (m *MockVoteRepository) VotePost(ctx context.Context, userID, postID int64, value int) (*entity.VoteResult, error) {
+	if m.VotePostFunc != nil {
+		return m.VotePostFunc(ctx, userID, postID, value)
+	}
+	return nil, nil
+}
+
+func (m *MockVoteRepository) VoteComment(ctx context.Context, userID, postID, commentID int64, value int) (*entity.VoteResult, error) {
+	if m.VoteCommentFunc != nil {
+		return m.VoteCommentFunc(ctx, userID, postID, commentID, value)
+	}
+	return nil, nil
+}
+
+type MockTagRepository struct {
+	ListTagsFunc  func(ctx context.Context, filter entity.TagFilter) ([]*entity.Tag, error)
+	RenameTagFunc func(ctx context.Context, name, newName string) (*entity.Tag, error)
+	MergeTagsFunc func(ctx context.Context, source, target string) (*entity.Tag, error)
+}
+
+func (m *MockTagRepository) ListTags(ctx context.Context, filter entity.TagFilter) ([]*entity.Tag, error) {
+	return m.ListTagsFunc(ctx, filter)
+}
+
+func (m *MockTagRepository) RenameTag(ctx context.Context, name, newName string) (*entity.Tag, error) {
+	return m.RenameTagFunc(ctx, name, newName)
+}
+
+func (m *MockTagRepository) MergeTags(ctx context.Context, source, target string) (*entity.Tag, error) {
+	return m.MergeTagsFunc(ctx, source, target)
+}
+
+type MockRevisionRepository struct {
+	ListRevisionsFunc   func(ctx context.Context, postID int64) ([]*entity.PostRevision, error)
+	GetRevisionFunc     func(ctx context.Context, postID int64, revision int) (*entity.PostRevision, error)
+	RestoreRevisionFunc func(ctx context.Context, postID int64, revision int, editorID int64) (*entity.Post, error)
+}
+
+func (m *MockRevisionRepository) ListRevisions(ctx context.Context, postID int64) ([]*entity.PostRevision, error) {
+	return m.ListRevisionsFunc(ctx, postID)
+}
+
+func (m *MockRevisionRepository) GetRevision(ctx context.Context, postID int64, revision int) (*entity.PostRevision, error) {
+	return m.GetRevisionFunc(ctx, postID, revision)
+}
+
+func (m *MockRevisionRepository) RestoreRevision(ctx context.Context, postID int64, revision int, editorID int64) (*entity.Post, error) {
+	return m.RestoreRevisionFunc(ctx, postID, revision, editorID)
+}
+
+type MockAttachmentRepository struct {
+	CreateAttachmentFunc    func(ctx context.Context, attachment *entity.Attachment, store func(ctx context.Context) error) (int64, error)
+	GetAttachmentFunc       func(ctx context.Context, id int64, includeHidden bool) (*entity.Attachment, error)
+	ListPostAttachmentsFunc func(ctx context.Context, postID int64) ([]*entity.Attachment, error)
+	AttachToPostFunc        func(ctx context.Context, postID, uploaderID int64, ids []int64) ([]*entity.Attachment, error)
+	DeleteOrphansFunc       func(ctx context.Context, before time.Time, limit int, remove func(ctx context.Context, hash string) error) (int64, error)
+}
+
+func (m *MockAttachmentRepository) CreateAttachment(ctx context.Context, attachment *entity.Attachment, store func(ctx context.Context) error) (int64, error) {
+	return m.CreateAttachmentFunc(ctx, attachment, store)
+}
+
+func (m *MockAttachmentRepository) GetAttachment(ctx context.Context, id int64, includeHidden bool) (*entity.Attachment, error) {
+	return m.GetAttachmentFunc(ctx, id, includeHidden)
+}
+
+func (m *MockAttachmentRepository) ListPostAttachments(ctx context.Context, postID int64) ([]*entity.Attachment, error) {
+	return m.ListPostAttachmentsFunc(ctx, postID)
+}
+
+func (m *MockAttachmentRepository) AttachToPost(ctx context.Context, postID, uploaderID int64, ids []int64) ([]*entity.Attachment, error) {
+	return m.AttachToPostFunc(ctx, postID, uploaderID, ids)
+}
+
+func (m *MockAttachmentRepository) DeleteOrphans(ctx context.Context, before time.Time, limit int, remove func(ctx context.Context, hash string) error) (int64, error) {
+	return m.DeleteOrphansFunc(ctx, before, limit, remove)
+}
+
+type MockModerationRepository struct {
+	CreateReportFunc func(ctx context.Context, report *entity.Report) (int64, error)
+	TargetAuthorFunc func(ctx context.Context, target entity.ReportTarget) (int64, error)
+	HideTargetFunc   func(ctx context.Context, target entity.ReportTarget) error
+	GetQueueFunc     func(ctx context.Context, filter entity.ModerationQueueFilter) (*entity.ModerationQueuePage, error)
+	ListReportsFunc  func(ctx context.Context, target entity.ReportTarget) ([]*entity.Report, error)
+	ResolveFunc      func(ctx context.Context, target entity.ReportTarget, action entity.ModerationAction, moderatorID int64, note string) (int64, error)
+	ListWarningsFunc func(ctx context.Context, userID int64) ([]*entity.UserWarning, error)
+}
+
+func (m *MockModerationRepository) CreateReport(ctx context.Context, report *entity.Report) (int64, error) {
+	return m.CreateReportFunc(ctx, report)
+}
+
+func (m *MockModerationRepository) TargetAuthor(ctx context.Context, target entity.ReportTarget) (int64, error) {
+	return m.TargetAuthorFunc(ctx, target)
+}
+
+func (m *MockModerationRepository) HideTarget(ctx context.Context, target entity.ReportTarget) error {
+	return m.HideTargetFunc(ctx, target)
+}
+
+func (m *MockModerationRepository) GetQueue(ctx context.Context, filter entity.ModerationQueueFilter) (*entity.ModerationQueuePage, error) {
+	return m.GetQueueFunc(ctx, filter)
+}
+
+func (m *MockModerationRepository) ListReports(ctx context.Context, target entity.ReportTarget) ([]*entity.Report, error) {
+	return m.ListReportsFunc(ctx, target)
+}
+
+func (m *MockModerationRepository) Resolve(ctx context.Context, target entity.ReportTarget, action entity.ModerationAction, moderatorID int64, note string) (int64, error) {
+	return m.ResolveFunc(ctx, target, action, moderatorID, note)
+}
+
+func (m *MockModerationRepository) ListWarnings(ctx context.Context, userID int64) ([]*entity.UserWarning, error) {
+	return m.ListWarningsFunc(ctx, userID)
+}
+
+type MockNotificationRepository struct {
+	CreateNotificationsFunc func(ctx context.Context, notifications []*entity.Notification) error
+	DisabledTypesFunc       func(ctx context.Context, userIDs []int64) (map[int64][]entity.NotificationType, error)
+	ListNotificationsFunc   func(ctx context.Context, filter entity.NotificationFilter) (*entity.NotificationPage, error)
+	UnreadCountFunc         func(ctx context.Context, userID int64) (int64, error)
+	MarkReadFunc            func(ctx context.Context, userID int64, ids []int64) (int64, error)
+	GetPreferencesFunc      func(ctx context.Context, userID int64) (entity.NotificationPreferences, error)
+	SetPreferencesFunc      func(ctx context.Context, userID int64, prefs entity.NotificationPreferences) error
+}
+
+func (m *MockNotificationRepository) CreateNotifications(ctx context.Context, notifications []*entity.Notification) error {
+	return m.CreateNotificationsFunc(ctx, notifications)
+}
+
+func (m *MockNotificationRepository) DisabledTypes(ctx context.Context, userIDs []int64) (map[int64][]entity.NotificationType, error) {
+	return m.DisabledTypesFunc(ctx, userIDs)
+}
+
+func (m *MockNotificationRepository) ListNotifications(ctx context.Context, filter entity.NotificationFilter) (*entity.NotificationPage, error) {
+	return m.ListNotificationsFunc(ctx, filter)
+}
+
+func (m *MockNotificationRepository) UnreadCount(ctx context.Context, userID int64) (int64, error) {
+	return m.UnreadCountFunc(ctx, userID)
+}
+
+func (m *MockNotificationRepository) MarkRead(ctx context.Context, userID int64, ids []int64) (int64, error) {
+	return m.MarkReadFunc(ctx, userID, ids)
+}
+
+func (m *MockNotificationRepository) GetPreferences(ctx context.Context, userID int64) (entity.NotificationPreferences, error) {
+	return m.GetPreferencesFunc(ctx, userID)
+}
+
+func (m *MockNotificationRepository) SetPreferences(ctx context.Context, userID int64, prefs entity.NotificationPreferences) error {
+	return m.SetPreferencesFunc(ctx, userID, prefs)
+}
+
+type MockCategoryRepository struct {
+	CreateCategoryFunc    func(ctx context.Context, category *entity.Category) (int64, error)
+	UpdateCategoryFunc    func(ctx context.Context, category *entity.Category) error
+	GetCategoryByIDFunc   func(ctx context.Context, id int64) (*entity.Category, error)
+	GetCategoryBySlugFunc func(ctx context.Context, slug string) (*entity.Category, error)
+	ListCategoriesFunc    func(ctx context.Context) ([]*entity.Category, error)
+}
+
+func (m *MockCategoryRepository) CreateCategory(ctx context.Context, category *entity.Category) (int64, error) {
+	if m.CreateCategoryFunc != nil {
+		return m.CreateCategoryFunc(ctx, category)
+	}
+	return 0, nil
+}
+
+func (m *MockCategoryRepository) UpdateCategory(ctx context.Context, category *entity.Category) error {
+	if m.UpdateCategoryFunc != nil {
+		return m.UpdateCategoryFunc(ctx, category)
+	}
+	return nil
+}
+
+func (m *MockCategoryRepository) GetCategoryByID(ctx context.Context, id int64) (*entity.Category, error) {
+	if m.GetCategoryByIDFunc != nil {
+		return m.GetCategoryByIDFunc(ctx, id)
+	}
+	return nil, nil
+}
+
+func (m *MockCategoryRepository) GetCategoryBySlug(ctx context.Context, slug string) (*entity.Category, error) {
+	if m.GetCategoryBySlugFunc != nil {
+		return m.GetCategoryBySlugFunc(ctx, slug)
+	}
+	return nil, nil
+}
+
+func (m *MockCategoryRepository) ListCategories(ctx context.Context) ([]*entity.Category, error) {
+	if m.ListCategoriesFunc != nil {
+		return m.ListCategoriesFunc(ctx)
 	}
 	return nil, nil
 }
@@ -56,6 +311,10 @@ type MockAuthServiceClient struct {
 	GetUserFunc       func(ctx context.Context, in *pb.GetUserRequest, opts ...grpc.CallOption) (*pb.GetUserResponse, error)
 	LoginFunc         func(ctx context.Context, in *pb.LoginRequest, opts ...grpc.CallOption) (*pb.LoginResponse, error)
 	RegisterFunc      func(ctx context.Context, in *pb.RegisterRequest, opts ...grpc.CallOption) (*pb.RegisterResponse, error)
+	LogoutFunc        func(ctx context.Context, in *pb.LogoutRequest, opts ...grpc.CallOption) (*pb.LogoutResponse, error)
+	LogoutAllFunc     func(ctx context.Context, in *pb.LogoutAllRequest, opts ...grpc.CallOption) (*pb.LogoutAllResponse, error)
+	RefreshFunc       func(ctx context.Context, in *pb.RefreshRequest, opts ...grpc.CallOption) (*pb.LoginResponse, error)
+	GetUsersFunc      func(ctx context.Context, in *pb.GetUsersRequest, opts ...grpc.CallOption) (*pb.GetUsersResponse, error)
 }
 
 func (m *MockAuthServiceClient) ValidateToken(ctx context.Context, in *pb.ValidateTokenRequest, opts ...grpc.CallOption) (*pb.ValidateTokenResponse, error) {
@@ -85,3 +344,31 @@ func (m *MockAuthServiceClient) Register(ctx context.Context, in *pb.RegisterReq
 	}
 	return nil, nil
 }
+
+func (m *MockAuthServiceClient) Logout(ctx context.Context, in *pb.LogoutRequest, opts ...grpc.CallOption) (*pb.LogoutResponse, error) {
+	if m.LogoutFunc != nil {
+		return m.LogoutFunc(ctx, in, opts...)
+	}
+	return nil, nil
+}
+
+func (m *MockAuthServiceClient) LogoutAll(ctx context.Context, in *pb.LogoutAllRequest, opts ...grpc.CallOption) (*pb.LogoutAllResponse, error) {
+	if m.LogoutAllFunc != nil {
+		return m.LogoutAllFunc(ctx, in, opts...)
+	}
+	return nil, nil
+}
+
+func (m *MockAuthServiceClient) Refresh(ctx context.Context, in *pb.RefreshRequest, opts ...grpc.CallOption) (*pb.LoginResponse, error) {
+	if m.RefreshFunc != nil {
+		return m.RefreshFunc(ctx, in, opts...)
+	}
+	return nil, nil
+}
+
+func (m *MockAuthServiceClient) GetUsers(ctx context.Context, in *pb.GetUsersRequest, opts ...grpc.CallOption) (*pb.GetUsersResponse, error) {
+	if m.GetUsersFunc != nil {
+		return m.GetUsersFunc(ctx, in, opts...)
+	}
+	return nil, nil
+}
@@ -0,0 +1,228 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"backend.com/forum/apperrors"
+	"github.com/Ulyana-kru00/forum-project/forum-servise/internal/entity"
+	"github.com/Ulyana-kru00/forum-project/forum-servise/internal/repository"
+	"github.com/Ulyana-kru00/forum-project/forum-servise/pkg/logger"
+	"github.com/Ulyana-kru00/forum-project/forum-servise/pkg/verifier"
+)
+
+var (
+	errModeratorOnly       = apperrors.New(apperrors.ErrPermissionDenied, "only moderators can review reports")
+	errInvalidReportTarget = apperrors.New(apperrors.ErrInvalidArgument, "target type must be one of post, comment, chat_message")
+	errInvalidTargetID     = apperrors.New(apperrors.ErrInvalidArgument, "target id must be positive")
+	errInvalidReportReason = apperrors.New(apperrors.ErrInvalidArgument, "reason must be one of spam, abuse, off_topic, illegal, other")
+	errInvalidReportStatus = apperrors.New(apperrors.ErrInvalidArgument, "status must be one of open, actioned, dismissed")
+	errInvalidModAction    = apperrors.New(apperrors.ErrInvalidArgument, "action must be one of hide, delete, warn, dismiss")
+	errReportOwnContent    = apperrors.New(apperrors.ErrInvalidArgument, "you cannot report your own content")
+	errReportDetailsLength = apperrors.New(apperrors.ErrInvalidArgument, fmt.Sprintf("details must be at most %d characters", maxReportDetailsLength))
+	errWarningNoteLength   = apperrors.New(apperrors.ErrInvalidArgument, fmt.Sprintf("note must be at most %d characters", maxWarningNoteLength))
+)
+
+const (
+	maxReportDetailsLength = 1000
+	maxWarningNoteLength   = 1000
+
+	defaultQueueLimit = 20
+	maxQueueLimit     = 100
+)
+
+type ModerationConfig struct {
+	// AutoHideThreshold is the number of open reports at which content is
+	// hidden until a moderator looks at it. Zero turns auto-hiding off.
+	AutoHideThreshold int64
+}
+
+type ModerationUsecaseInterface interface {
+	Report(ctx context.Context, token string, report *entity.Report) (*entity.Report, error)
+	GetQueue(ctx context.Context, token string, filter entity.ModerationQueueFilter) (*entity.ModerationQueuePage, error)
+	ListReports(ctx context.Context, token string, target entity.ReportTarget) ([]*entity.Report, error)
+	Act(ctx context.Context, token string, target entity.ReportTarget, action entity.ModerationAction, note string) (int64, error)
+	MyWarnings(ctx context.Context, token string) ([]*entity.UserWarning, error)
+}
+
+type ModerationUsecase struct {
+	repo     repository.ModerationRepository
+	verifier verifier.TokenVerifier
+	cfg      ModerationConfig
+	logger   *logger.Logger
+}
+
+func NewModerationUsecase(
+	repo repository.ModerationRepository,
+	tokenVerifier verifier.TokenVerifier,
+	cfg ModerationConfig,
+	logger *logger.Logger,
+) *ModerationUsecase {
+	return &ModerationUsecase{
+		repo:     repo,
+		verifier: tokenVerifier,
+		cfg:      cfg,
+		logger:   logger,
+	}
+}
+
+// isModerator reports whether role may work the moderation queue.
+func isModerator(role string) bool {
+	return role == "admin" || role == "moderator"
+}
+
+// Report files a report of the caller about a post, comment or chat
+// message. A user has at most one open report per piece of content, and
+// cannot report their own. Content reaching the auto-hide threshold is
+// hidden until a moderator acts on it.
+func (uc *ModerationUsecase) Report(ctx context.Context, token string, report *entity.Report) (*entity.Report, error) {
+	claims, err := uc.verifier.Verify(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+
+	target := entity.ReportTarget{Type: report.TargetType, ID: report.TargetID}
+	if err := validateReportTarget(target); err != nil {
+		return nil, err
+	}
+	if !report.Reason.Valid() {
+		return nil, errInvalidReportReason
+	}
+	report.Details = strings.TrimSpace(report.Details)
+	if utf8.RuneCountInString(report.Details) > maxReportDetailsLength {
+		return nil, errReportDetailsLength
+	}
+
+	authorID, err := uc.repo.TargetAuthor(ctx, target)
+	if err != nil {
+		return nil, err
+	}
+	if authorID == claims.UserID {
+		return nil, errReportOwnContent
+	}
+
+	report.ReporterID = &claims.UserID
+	openReports, err := uc.repo.CreateReport(ctx, report)
+	if err != nil {
+		return nil, err
+	}
+
+	if uc.cfg.AutoHideThreshold > 0 && openReports >= uc.cfg.AutoHideThreshold {
+		// The report itself is stored; a failed hide is retried by the next
+		// report or done by a moderator.
+		if err := uc.repo.HideTarget(ctx, target); err != nil {
+			uc.logger.Errorw("Failed to auto-hide reported content",
+				"target_type", target.Type, "target_id", target.ID, "error", err)
+		} else {
+			uc.logger.Infow("Reported content auto-hidden",
+				"target_type", target.Type, "target_id", target.ID, "open_reports", openReports)
+		}
+	}
+
+	return report, nil
+}
+
+// GetQueue returns a page of reported content in one triage state, open by
+// default. Moderators only.
+func (uc *ModerationUsecase) GetQueue(ctx context.Context, token string, filter entity.ModerationQueueFilter) (*entity.ModerationQueuePage, error) {
+	if err := uc.requireModerator(ctx, token); err != nil {
+		return nil, err
+	}
+
+	if filter.Status == "" {
+		filter.Status = entity.ReportStatusOpen
+	}
+	if !filter.Status.Valid() {
+		return nil, errInvalidReportStatus
+	}
+	if filter.TargetType != "" && !filter.TargetType.Valid() {
+		return nil, errInvalidReportTarget
+	}
+	if filter.Limit <= 0 {
+		filter.Limit = defaultQueueLimit
+	}
+	if filter.Limit > maxQueueLimit {
+		filter.Limit = maxQueueLimit
+	}
+
+	return uc.repo.GetQueue(ctx, filter)
+}
+
+// ListReports returns all reports about a piece of content. Moderators
+// only.
+func (uc *ModerationUsecase) ListReports(ctx context.Context, token string, target entity.ReportTarget) ([]*entity.Report, error) {
+	if err := uc.requireModerator(ctx, token); err != nil {
+		return nil, err
+	}
+	if err := validateReportTarget(target); err != nil {
+		return nil, err
+	}
+
+	return uc.repo.ListReports(ctx, target)
+}
+
+// Act resolves the open reports about a piece of content with action and
+// returns how many were resolved. The note is kept with a warning.
+// Moderators only.
+func (uc *ModerationUsecase) Act(ctx context.Context, token string, target entity.ReportTarget, action entity.ModerationAction, note string) (int64, error) {
+	claims, err := uc.verifier.Verify(ctx, token)
+	if err != nil {
+		return 0, err
+	}
+	if !isModerator(claims.Role) {
+		return 0, errModeratorOnly
+	}
+	if err := validateReportTarget(target); err != nil {
+		return 0, err
+	}
+	if !action.Valid() {
+		return 0, errInvalidModAction
+	}
+	note = strings.TrimSpace(note)
+	if utf8.RuneCountInString(note) > maxWarningNoteLength {
+		return 0, errWarningNoteLength
+	}
+
+	resolved, err := uc.repo.Resolve(ctx, target, action, claims.UserID, note)
+	if err != nil {
+		return 0, err
+	}
+
+	uc.logger.Infow("Reports resolved",
+		"target_type", target.Type, "target_id", target.ID, "action", action,
+		"moderator_id", claims.UserID, "reports", resolved)
+	return resolved, nil
+}
+
+// MyWarnings returns the warnings the caller got from moderators.
+func (uc *ModerationUsecase) MyWarnings(ctx context.Context, token string) ([]*entity.UserWarning, error) {
+	claims, err := uc.verifier.Verify(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+
+	return uc.repo.ListWarnings(ctx, claims.UserID)
+}
+
+func (uc *ModerationUsecase) requireModerator(ctx context.Context, token string) error {
+	claims, err := uc.verifier.Verify(ctx, token)
+	if err != nil {
+		return err
+	}
+	if !isModerator(claims.Role) {
+		return errModeratorOnly
+	}
+	return nil
+}
+
+func validateReportTarget(target entity.ReportTarget) error {
+	if !target.Type.Valid() {
+		return errInvalidReportTarget
+	}
+	if target.ID <= 0 {
+		return errInvalidTargetID
+	}
+	return nil
+}
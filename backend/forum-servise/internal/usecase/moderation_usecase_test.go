@@ -0,0 +1,205 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+
+	"backend.com/forum/apperrors"
+	"github.com/Ulyana-kru00/forum-project/forum-servise/internal/entity"
+	"github.com/Ulyana-kru00/forum-project/forum-servise/internal/repository"
+	"github.com/Ulyana-kru00/forum-project/forum-servise/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func newModerationUsecase(repo *MockModerationRepository, role string, threshold int64) *ModerationUsecase {
+	log := &logger.Logger{SugaredLogger: zap.NewNop().Sugar()}
+	return NewModerationUsecase(repo, roleVerifier(role), ModerationConfig{AutoHideThreshold: threshold}, log)
+}
+
+// reportingRepo accepts reports about content of author and answers with
+// openReports open reports. Hidden targets are recorded in hidden.
+func reportingRepo(author, openReports int64, hidden *[]entity.ReportTarget) *MockModerationRepository {
+	return &MockModerationRepository{
+		TargetAuthorFunc: func(ctx context.Context, target entity.ReportTarget) (int64, error) {
+			return author, nil
+		},
+		CreateReportFunc: func(ctx context.Context, report *entity.Report) (int64, error) {
+			report.ID = 10
+			return openReports, nil
+		},
+		HideTargetFunc: func(ctx context.Context, target entity.ReportTarget) error {
+			*hidden = append(*hidden, target)
+			return nil
+		},
+	}
+}
+
+func TestModerationUsecase_Report(t *testing.T) {
+	valid := func() *entity.Report {
+		return &entity.Report{
+			TargetType: entity.ReportTargetComment,
+			TargetID:   42,
+			Reason:     entity.ReportReasonSpam,
+			Details:    "  casino links ",
+		}
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		var hidden []entity.ReportTarget
+		uc := newModerationUsecase(reportingRepo(2, 1, &hidden), "user", 3)
+
+		report, err := uc.Report(context.Background(), "token", valid())
+		require.NoError(t, err)
+		assert.Equal(t, int64(10), report.ID)
+		assert.Equal(t, int64(1), *report.ReporterID)
+		assert.Equal(t, "casino links", report.Details)
+		assert.Empty(t, hidden)
+	})
+
+	t.Run("Threshold reached hides content", func(t *testing.T) {
+		var hidden []entity.ReportTarget
+		uc := newModerationUsecase(reportingRepo(2, 3, &hidden), "user", 3)
+
+		_, err := uc.Report(context.Background(), "token", valid())
+		require.NoError(t, err)
+		assert.Equal(t, []entity.ReportTarget{{Type: entity.ReportTargetComment, ID: 42}}, hidden)
+	})
+
+	t.Run("Zero threshold never hides", func(t *testing.T) {
+		var hidden []entity.ReportTarget
+		uc := newModerationUsecase(reportingRepo(2, 100, &hidden), "user", 0)
+
+		_, err := uc.Report(context.Background(), "token", valid())
+		require.NoError(t, err)
+		assert.Empty(t, hidden)
+	})
+
+	t.Run("Own content", func(t *testing.T) {
+		var hidden []entity.ReportTarget
+		uc := newModerationUsecase(reportingRepo(1, 1, &hidden), "user", 3)
+
+		_, err := uc.Report(context.Background(), "token", valid())
+		assert.ErrorIs(t, err, errReportOwnContent)
+	})
+
+	t.Run("Missing content", func(t *testing.T) {
+		uc := newModerationUsecase(&MockModerationRepository{
+			TargetAuthorFunc: func(ctx context.Context, target entity.ReportTarget) (int64, error) {
+				return 0, repository.ErrReportTargetNotFound
+			},
+		}, "user", 3)
+
+		_, err := uc.Report(context.Background(), "token", valid())
+		assert.ErrorIs(t, err, repository.ErrReportTargetNotFound)
+	})
+
+	invalid := []struct {
+		name    string
+		mutate  func(r *entity.Report)
+		wantErr error
+	}{
+		{"Unknown target type", func(r *entity.Report) { r.TargetType = "user" }, errInvalidReportTarget},
+		{"Non-positive target id", func(r *entity.Report) { r.TargetID = 0 }, errInvalidTargetID},
+		{"Unknown reason", func(r *entity.Report) { r.Reason = "boring" }, errInvalidReportReason},
+	}
+	for _, tt := range invalid {
+		t.Run(tt.name, func(t *testing.T) {
+			uc := newModerationUsecase(&MockModerationRepository{}, "user", 3)
+			report := valid()
+			tt.mutate(report)
+
+			_, err := uc.Report(context.Background(), "token", report)
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.ErrorIs(t, err, apperrors.ErrInvalidArgument)
+		})
+	}
+}
+
+func TestModerationUsecase_GetQueue(t *testing.T) {
+	var got entity.ModerationQueueFilter
+	repo := &MockModerationRepository{
+		GetQueueFunc: func(ctx context.Context, filter entity.ModerationQueueFilter) (*entity.ModerationQueuePage, error) {
+			got = filter
+			return &entity.ModerationQueuePage{}, nil
+		},
+	}
+
+	t.Run("Defaults", func(t *testing.T) {
+		_, err := newModerationUsecase(repo, "moderator", 3).GetQueue(context.Background(), "token", entity.ModerationQueueFilter{})
+		require.NoError(t, err)
+		assert.Equal(t, entity.ReportStatusOpen, got.Status)
+		assert.Equal(t, defaultQueueLimit, got.Limit)
+	})
+
+	t.Run("Limit is capped", func(t *testing.T) {
+		_, err := newModerationUsecase(repo, "admin", 3).GetQueue(context.Background(), "token", entity.ModerationQueueFilter{Limit: 1000})
+		require.NoError(t, err)
+		assert.Equal(t, maxQueueLimit, got.Limit)
+	})
+
+	t.Run("Users are denied", func(t *testing.T) {
+		_, err := newModerationUsecase(repo, "user", 3).GetQueue(context.Background(), "token", entity.ModerationQueueFilter{})
+		assert.ErrorIs(t, err, errModeratorOnly)
+	})
+
+	t.Run("Invalid status", func(t *testing.T) {
+		_, err := newModerationUsecase(repo, "admin", 3).GetQueue(context.Background(), "token", entity.ModerationQueueFilter{Status: "closed"})
+		assert.ErrorIs(t, err, errInvalidReportStatus)
+	})
+}
+
+func TestModerationUsecase_Act(t *testing.T) {
+	target := entity.ReportTarget{Type: entity.ReportTargetPost, ID: 5}
+
+	t.Run("Success", func(t *testing.T) {
+		repo := &MockModerationRepository{
+			ResolveFunc: func(ctx context.Context, gotTarget entity.ReportTarget, action entity.ModerationAction, moderatorID int64, note string) (int64, error) {
+				assert.Equal(t, target, gotTarget)
+				assert.Equal(t, entity.ModerationWarn, action)
+				assert.Equal(t, int64(1), moderatorID)
+				assert.Equal(t, "no ads", note)
+				return 2, nil
+			},
+		}
+
+		resolved, err := newModerationUsecase(repo, "moderator", 3).Act(context.Background(), "token", target, entity.ModerationWarn, " no ads ")
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), resolved)
+	})
+
+	t.Run("Users are denied", func(t *testing.T) {
+		_, err := newModerationUsecase(&MockModerationRepository{}, "user", 3).Act(context.Background(), "token", target, entity.ModerationHide, "")
+		assert.ErrorIs(t, err, errModeratorOnly)
+	})
+
+	t.Run("Unknown action", func(t *testing.T) {
+		_, err := newModerationUsecase(&MockModerationRepository{}, "admin", 3).Act(context.Background(), "token", target, "ban", "")
+		assert.ErrorIs(t, err, errInvalidModAction)
+	})
+
+	t.Run("Nothing to resolve", func(t *testing.T) {
+		repo := &MockModerationRepository{
+			ResolveFunc: func(ctx context.Context, target entity.ReportTarget, action entity.ModerationAction, moderatorID int64, note string) (int64, error) {
+				return 0, repository.ErrNoOpenReports
+			},
+		}
+
+		_, err := newModerationUsecase(repo, "admin", 3).Act(context.Background(), "token", target, entity.ModerationDismiss, "")
+		assert.ErrorIs(t, err, repository.ErrNoOpenReports)
+	})
+}
+
+func TestModerationUsecase_MyWarnings(t *testing.T) {
+	repo := &MockModerationRepository{
+		ListWarningsFunc: func(ctx context.Context, userID int64) ([]*entity.UserWarning, error) {
+			assert.Equal(t, int64(1), userID)
+			return []*entity.UserWarning{{ID: 3, UserID: userID}}, nil
+		},
+	}
+
+	warnings, err := newModerationUsecase(repo, "user", 3).MyWarnings(context.Background(), "token")
+	require.NoError(t, err)
+	assert.Len(t, warnings, 1)
+}
@@ -0,0 +1,338 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"time"
+
+	"backend.com/forum/apperrors"
+	pb "backend.com/forum/proto"
+	"github.com/Ulyana-kru00/forum-project/forum-servise/internal/entity"
+	"github.com/Ulyana-kru00/forum-project/forum-servise/internal/repository"
+	"github.com/Ulyana-kru00/forum-project/forum-servise/pkg/loader"
+	"github.com/Ulyana-kru00/forum-project/forum-servise/pkg/logger"
+	"github.com/Ulyana-kru00/forum-project/forum-servise/pkg/verifier"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var (
+	errInvalidNotificationType = apperrors.New(apperrors.ErrInvalidArgument, "notification type must be one of post_comment, comment_reply, mention")
+	errTooManyNotificationIDs  = apperrors.New(apperrors.ErrInvalidArgument, fmt.Sprintf("at most %d notification ids per request", maxMarkReadIDs))
+)
+
+const (
+	defaultNotificationsLimit = 20
+	maxNotificationsLimit     = 100
+	maxMarkReadIDs            = 100
+
+	// maxMentions bounds the usernames looked up for one post or comment.
+	maxMentions = 10
+
+	// notificationQueueSize is how many events may wait for RunDispatcher;
+	// events beyond it are dropped.
+	notificationQueueSize = 256
+	// notifyTimeout bounds the work done for one queued event.
+	notifyTimeout = 10 * time.Second
+)
+
+// mentionPattern matches @username not preceded by a word character, so
+// e-mail addresses are not taken for mentions.
+var mentionPattern = regexp.MustCompile(`(?:^|[^\p{L}\p{N}_@])@([\p{L}\p{N}_]+(?:[.-][\p{L}\p{N}_]+)*)`)
+
+// Notifier records notifications about new posts and comments. It logs
+// failures instead of returning them, as a lost notification must not undo
+// the write that caused it.
+type Notifier interface {
+	PostCreated(ctx context.Context, post *entity.Post)
+	// CommentCreated is called for a new comment on post; parent is the
+	// comment replied to, or nil.
+	CommentCreated(ctx context.Context, post *entity.Post, comment, parent *entity.Comment)
+}
+
+type NotificationUsecaseInterface interface {
+	List(ctx context.Context, token string, filter entity.NotificationFilter) (*entity.NotificationPage, error)
+	UnreadCount(ctx context.Context, token string) (int64, error)
+	MarkRead(ctx context.Context, token string, ids []int64) (int64, error)
+	GetPreferences(ctx context.Context, token string) (entity.NotificationPreferences, error)
+	UpdatePreferences(ctx context.Context, token string, prefs entity.NotificationPreferences) (entity.NotificationPreferences, error)
+}
+
+type NotificationUsecase struct {
+	repo       repository.NotificationRepository
+	authClient pb.AuthServiceClient
+	verifier   verifier.TokenVerifier
+	logger     *logger.Logger
+
+	events chan func(ctx context.Context)
+}
+
+func NewNotificationUsecase(
+	repo repository.NotificationRepository,
+	authClient pb.AuthServiceClient,
+	tokenVerifier verifier.TokenVerifier,
+	logger *logger.Logger,
+) *NotificationUsecase {
+	return &NotificationUsecase{
+		repo:       repo,
+		authClient: authClient,
+		verifier:   tokenVerifier,
+		logger:     logger,
+		events:     make(chan func(ctx context.Context), notificationQueueSize),
+	}
+}
+
+// RunDispatcher writes the notifications queued by PostCreated and
+// CommentCreated, one event at a time, until ctx is done. Resolving mentions
+// takes a GetUser call per name, which is kept off the request path.
+func (uc *NotificationUsecase) RunDispatcher(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event := <-uc.events:
+			eventCtx, cancel := context.WithTimeout(ctx, notifyTimeout)
+			event(eventCtx)
+			cancel()
+		}
+	}
+}
+
+// enqueue hands event to RunDispatcher without waiting for it.
+func (uc *NotificationUsecase) enqueue(postID int64, event func(ctx context.Context)) {
+	select {
+	case uc.events <- event:
+	default:
+		uc.logger.Warnw("Notification queue is full, dropping event", "post_id", postID)
+	}
+}
+
+// recipients collects who to notify about one event. Each user keeps the
+// types they qualify for in the order they were added, most specific first.
+type recipients struct {
+	users []int64
+	types map[int64][]entity.NotificationType
+}
+
+func (r *recipients) add(userID int64, t entity.NotificationType) {
+	if r.types == nil {
+		r.types = make(map[int64][]entity.NotificationType)
+	}
+	if _, ok := r.types[userID]; !ok {
+		r.users = append(r.users, userID)
+	}
+	r.types[userID] = append(r.types[userID], t)
+}
+
+// PostCreated queues notifications for the users mentioned in a new post.
+// The post is copied, so the caller may go on using it.
+func (uc *NotificationUsecase) PostCreated(ctx context.Context, post *entity.Post) {
+	p := *post
+	uc.enqueue(p.ID, func(ctx context.Context) { uc.postCreated(ctx, &p) })
+}
+
+// CommentCreated queues the notifications about a new comment like
+// PostCreated does.
+func (uc *NotificationUsecase) CommentCreated(ctx context.Context, post *entity.Post, comment, parent *entity.Comment) {
+	p, c := *post, *comment
+	var pc *entity.Comment
+	if parent != nil {
+		copied := *parent
+		pc = &copied
+	}
+	uc.enqueue(p.ID, func(ctx context.Context) { uc.commentCreated(ctx, &p, &c, pc) })
+}
+
+// postCreated notifies the users mentioned in a new post.
+func (uc *NotificationUsecase) postCreated(ctx context.Context, post *entity.Post) {
+	var to recipients
+	for _, userID := range uc.mentionedUsers(ctx, post.Title+"\n"+post.Content) {
+		to.add(userID, entity.NotificationMention)
+	}
+	uc.notify(ctx, &to, post.AuthorID, post.ID, nil)
+}
+
+// commentCreated notifies the author of the comment replied to, the users
+// mentioned in the comment and the author of the post. A user gets one
+// notification per comment, of the first of these types they have on.
+func (uc *NotificationUsecase) commentCreated(ctx context.Context, post *entity.Post, comment, parent *entity.Comment) {
+	var to recipients
+	if parent != nil {
+		to.add(parent.AuthorID, entity.NotificationCommentReply)
+	}
+	for _, userID := range uc.mentionedUsers(ctx, comment.Content) {
+		to.add(userID, entity.NotificationMention)
+	}
+	to.add(post.AuthorID, entity.NotificationPostComment)
+
+	commentID := comment.ID
+	uc.notify(ctx, &to, comment.AuthorID, post.ID, &commentID)
+}
+
+// notify stores the notifications of an event by actorID, leaving out the
+// actor and the types each recipient turned off.
+func (uc *NotificationUsecase) notify(ctx context.Context, to *recipients, actorID, postID int64, commentID *int64) {
+	users := make([]int64, 0, len(to.users))
+	for _, userID := range to.users {
+		if userID != actorID {
+			users = append(users, userID)
+		}
+	}
+	if len(users) == 0 {
+		return
+	}
+
+	disabled, err := uc.repo.DisabledTypes(ctx, users)
+	if err != nil {
+		uc.logger.Warnw("Failed to load notification preferences", "post_id", postID, "error", err)
+		return
+	}
+
+	notifications := make([]*entity.Notification, 0, len(users))
+	for _, userID := range users {
+		if t, ok := firstEnabled(to.types[userID], disabled[userID]); ok {
+			notifications = append(notifications, &entity.Notification{
+				UserID:    userID,
+				Type:      t,
+				ActorID:   &actorID,
+				PostID:    postID,
+				CommentID: commentID,
+			})
+		}
+	}
+
+	if err := uc.repo.CreateNotifications(ctx, notifications); err != nil {
+		uc.logger.Warnw("Failed to create notifications", "post_id", postID, "error", err)
+	}
+}
+
+func firstEnabled(types, disabled []entity.NotificationType) (entity.NotificationType, bool) {
+	for _, t := range types {
+		off := false
+		for _, d := range disabled {
+			if d == t {
+				off = true
+				break
+			}
+		}
+		if !off {
+			return t, true
+		}
+	}
+	return "", false
+}
+
+// mentionedUsers resolves the distinct @usernames in text to user ids,
+// skipping names auth-service does not know.
+func (uc *NotificationUsecase) mentionedUsers(ctx context.Context, text string) []int64 {
+	seen := make(map[string]bool)
+	var ids []int64
+	for _, m := range mentionPattern.FindAllStringSubmatch(text, -1) {
+		name := m[1]
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+		if len(seen) > maxMentions {
+			break
+		}
+
+		resp, err := uc.authClient.GetUser(ctx, &pb.GetUserRequest{Username: name})
+		if err != nil {
+			if status.Code(err) != codes.NotFound {
+				uc.logger.Warnw("Failed to resolve mention", "username", name, "error", err)
+			}
+			continue
+		}
+		if resp != nil && resp.User != nil {
+			ids = append(ids, resp.User.Id)
+		}
+	}
+	return ids
+}
+
+// List returns a page of the caller's notifications, newest first, with the
+// current names of the users who caused them.
+func (uc *NotificationUsecase) List(ctx context.Context, token string, filter entity.NotificationFilter) (*entity.NotificationPage, error) {
+	claims, err := uc.verifier.Verify(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	filter.UserID = claims.UserID
+	if filter.Limit <= 0 {
+		filter.Limit = defaultNotificationsLimit
+	}
+	if filter.Limit > maxNotificationsLimit {
+		filter.Limit = maxNotificationsLimit
+	}
+
+	page, err := uc.repo.ListNotifications(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	actorIDs := make([]int64, 0, len(page.Notifications))
+	for _, n := range page.Notifications {
+		if n.ActorID != nil {
+			actorIDs = append(actorIDs, *n.ActorID)
+		}
+	}
+	names, _ := loader.FromContext(ctx, uc.authClient).Names(ctx, actorIDs)
+	for _, n := range page.Notifications {
+		if n.ActorID != nil {
+			n.ActorName = names[*n.ActorID]
+		}
+	}
+	return page, nil
+}
+
+// UnreadCount returns how many of the caller's notifications are unread.
+func (uc *NotificationUsecase) UnreadCount(ctx context.Context, token string) (int64, error) {
+	claims, err := uc.verifier.Verify(ctx, token)
+	if err != nil {
+		return 0, err
+	}
+	return uc.repo.UnreadCount(ctx, claims.UserID)
+}
+
+// MarkRead marks the given notifications of the caller read, or all of them
+// when ids is empty, and returns how many were unread.
+func (uc *NotificationUsecase) MarkRead(ctx context.Context, token string, ids []int64) (int64, error) {
+	if len(ids) > maxMarkReadIDs {
+		return 0, errTooManyNotificationIDs
+	}
+	claims, err := uc.verifier.Verify(ctx, token)
+	if err != nil {
+		return 0, err
+	}
+	return uc.repo.MarkRead(ctx, claims.UserID, ids)
+}
+
+// GetPreferences returns which notification types the caller gets.
+func (uc *NotificationUsecase) GetPreferences(ctx context.Context, token string) (entity.NotificationPreferences, error) {
+	claims, err := uc.verifier.Verify(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	return uc.repo.GetPreferences(ctx, claims.UserID)
+}
+
+// UpdatePreferences turns the types in prefs on or off for the caller and
+// returns the resulting preferences. Types left out keep their setting.
+func (uc *NotificationUsecase) UpdatePreferences(ctx context.Context, token string, prefs entity.NotificationPreferences) (entity.NotificationPreferences, error) {
+	for t := range prefs {
+		if !t.Valid() {
+			return nil, errInvalidNotificationType
+		}
+	}
+	claims, err := uc.verifier.Verify(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := uc.repo.SetPreferences(ctx, claims.UserID, prefs); err != nil {
+		return nil, err
+	}
+	return uc.repo.GetPreferences(ctx, claims.UserID)
+}
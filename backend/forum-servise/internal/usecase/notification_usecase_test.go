@@ -0,0 +1,207 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"backend.com/forum/apperrors"
+	pb "backend.com/forum/proto"
+	"github.com/Ulyana-kru00/forum-project/forum-servise/internal/entity"
+	"github.com/Ulyana-kru00/forum-project/forum-servise/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// usersByName is an auth client that knows the given users by name.
+func usersByName(users map[string]int64) *MockAuthServiceClient {
+	return &MockAuthServiceClient{
+		GetUserFunc: func(ctx context.Context, in *pb.GetUserRequest, opts ...grpc.CallOption) (*pb.GetUserResponse, error) {
+			id, ok := users[in.Username]
+			if !ok {
+				return nil, status.Error(codes.NotFound, "user not found")
+			}
+			return &pb.GetUserResponse{User: &pb.User{Id: id, Username: in.Username}}, nil
+		},
+	}
+}
+
+func newNotificationUsecase(repo *MockNotificationRepository, auth *MockAuthServiceClient) *NotificationUsecase {
+	log := &logger.Logger{SugaredLogger: zap.NewNop().Sugar()}
+	return NewNotificationUsecase(repo, auth, roleVerifier("user"), log)
+}
+
+func TestNotificationUsecase_CommentCreated(t *testing.T) {
+	var created []*entity.Notification
+	repo := &MockNotificationRepository{
+		DisabledTypesFunc: func(ctx context.Context, userIDs []int64) (map[int64][]entity.NotificationType, error) {
+			assert.ElementsMatch(t, []int64{2, 3, 4, 5}, userIDs)
+			return map[int64][]entity.NotificationType{
+				4: {entity.NotificationMention},
+				5: {entity.NotificationCommentReply},
+			}, nil
+		},
+		CreateNotificationsFunc: func(ctx context.Context, notifications []*entity.Notification) error {
+			created = notifications
+			return nil
+		},
+	}
+	auth := usersByName(map[string]int64{"bob": 3, "carol": 4, "me": 1})
+	uc := newNotificationUsecase(repo, auth)
+
+	post := &entity.Post{ID: 10, AuthorID: 2}
+	parent := &entity.Comment{ID: 20, AuthorID: 5}
+	comment := &entity.Comment{ID: 21, AuthorID: 1, Content: "@bob @carol @bob @ghost @me mail@example.com"}
+	uc.commentCreated(context.Background(), post, comment, parent)
+
+	types := make(map[int64]entity.NotificationType)
+	for _, n := range created {
+		assert.Equal(t, int64(10), n.PostID)
+		assert.Equal(t, int64(21), *n.CommentID)
+		assert.Equal(t, int64(1), *n.ActorID)
+		types[n.UserID] = n.Type
+	}
+	assert.Equal(t, map[int64]entity.NotificationType{
+		2: entity.NotificationPostComment,
+		3: entity.NotificationMention,
+		// 4 turned mentions off and has no other reason to hear about it;
+		// 5 turned replies off, so nothing is left either.
+	}, types)
+}
+
+func TestNotificationUsecase_PostCreated(t *testing.T) {
+	var created []*entity.Notification
+	repo := &MockNotificationRepository{
+		DisabledTypesFunc: func(ctx context.Context, userIDs []int64) (map[int64][]entity.NotificationType, error) {
+			return nil, nil
+		},
+		CreateNotificationsFunc: func(ctx context.Context, notifications []*entity.Notification) error {
+			created = notifications
+			return nil
+		},
+	}
+	uc := newNotificationUsecase(repo, usersByName(map[string]int64{"анна": 7}))
+
+	uc.postCreated(context.Background(), &entity.Post{ID: 3, AuthorID: 1, Title: "Вопрос к @анна", Content: "без упоминаний"})
+	require.Len(t, created, 1)
+	assert.Equal(t, int64(7), created[0].UserID)
+	assert.Equal(t, entity.NotificationMention, created[0].Type)
+	assert.Nil(t, created[0].CommentID)
+
+	// Nobody to notify: the repository is not touched.
+	created = nil
+	uc.postCreated(context.Background(), &entity.Post{ID: 4, AuthorID: 1, Content: "no mentions"})
+	assert.Nil(t, created)
+}
+
+func TestNotificationUsecase_RunDispatcher(t *testing.T) {
+	created := make(chan []*entity.Notification, 1)
+	repo := &MockNotificationRepository{
+		DisabledTypesFunc: func(ctx context.Context, userIDs []int64) (map[int64][]entity.NotificationType, error) {
+			return nil, nil
+		},
+		CreateNotificationsFunc: func(ctx context.Context, notifications []*entity.Notification) error {
+			created <- notifications
+			return nil
+		},
+	}
+	resolved := make(chan struct{})
+	auth := &MockAuthServiceClient{
+		GetUserFunc: func(ctx context.Context, in *pb.GetUserRequest, opts ...grpc.CallOption) (*pb.GetUserResponse, error) {
+			<-resolved
+			return &pb.GetUserResponse{User: &pb.User{Id: 7, Username: in.Username}}, nil
+		},
+	}
+	uc := newNotificationUsecase(repo, auth)
+
+	// The caller returns before the mention is resolved.
+	post := &entity.Post{ID: 3, AuthorID: 1, Content: "@анна"}
+	uc.PostCreated(context.Background(), post)
+	post.Content = "changed by the caller"
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go uc.RunDispatcher(ctx)
+	close(resolved)
+
+	select {
+	case notifications := <-created:
+		require.Len(t, notifications, 1)
+		assert.Equal(t, int64(7), notifications[0].UserID)
+	case <-time.After(time.Second):
+		t.Fatal("queued notification was not written")
+	}
+}
+
+func TestNotificationUsecase_List(t *testing.T) {
+	actor := int64(3)
+	repo := &MockNotificationRepository{
+		ListNotificationsFunc: func(ctx context.Context, filter entity.NotificationFilter) (*entity.NotificationPage, error) {
+			assert.Equal(t, int64(1), filter.UserID)
+			assert.Equal(t, maxNotificationsLimit, filter.Limit)
+			assert.True(t, filter.UnreadOnly)
+			return &entity.NotificationPage{Notifications: []*entity.Notification{
+				{ID: 2, ActorID: &actor},
+				{ID: 1},
+			}}, nil
+		},
+	}
+	auth := &MockAuthServiceClient{
+		GetUsersFunc: func(ctx context.Context, in *pb.GetUsersRequest, opts ...grpc.CallOption) (*pb.GetUsersResponse, error) {
+			return &pb.GetUsersResponse{Users: []*pb.User{{Id: 3, Username: "bob"}}}, nil
+		},
+	}
+	uc := newNotificationUsecase(repo, auth)
+
+	page, err := uc.List(context.Background(), "token", entity.NotificationFilter{UnreadOnly: true, Limit: 1000})
+	require.NoError(t, err)
+	assert.Equal(t, "bob", page.Notifications[0].ActorName)
+	assert.Empty(t, page.Notifications[1].ActorName)
+}
+
+func TestNotificationUsecase_MarkRead(t *testing.T) {
+	repo := &MockNotificationRepository{
+		MarkReadFunc: func(ctx context.Context, userID int64, ids []int64) (int64, error) {
+			assert.Equal(t, int64(1), userID)
+			return int64(len(ids)), nil
+		},
+	}
+	uc := newNotificationUsecase(repo, &MockAuthServiceClient{})
+
+	n, err := uc.MarkRead(context.Background(), "token", []int64{1, 2})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), n)
+
+	_, err = uc.MarkRead(context.Background(), "token", make([]int64, maxMarkReadIDs+1))
+	assert.ErrorIs(t, err, apperrors.ErrInvalidArgument)
+}
+
+func TestNotificationUsecase_UpdatePreferences(t *testing.T) {
+	var saved entity.NotificationPreferences
+	repo := &MockNotificationRepository{
+		SetPreferencesFunc: func(ctx context.Context, userID int64, prefs entity.NotificationPreferences) error {
+			saved = prefs
+			return nil
+		},
+		GetPreferencesFunc: func(ctx context.Context, userID int64) (entity.NotificationPreferences, error) {
+			return entity.NotificationPreferences{
+				entity.NotificationPostComment:  true,
+				entity.NotificationCommentReply: true,
+				entity.NotificationMention:      false,
+			}, nil
+		},
+	}
+	uc := newNotificationUsecase(repo, &MockAuthServiceClient{})
+
+	prefs, err := uc.UpdatePreferences(context.Background(), "token", entity.NotificationPreferences{entity.NotificationMention: false})
+	require.NoError(t, err)
+	assert.Equal(t, entity.NotificationPreferences{entity.NotificationMention: false}, saved)
+	assert.Len(t, prefs, 3)
+
+	_, err = uc.UpdatePreferences(context.Background(), "token", entity.NotificationPreferences{"likes": true})
+	assert.ErrorIs(t, err, errInvalidNotificationType)
+}
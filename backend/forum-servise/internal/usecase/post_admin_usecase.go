@@ -0,0 +1,136 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"backend.com/forum/apperrors"
+	"github.com/Ulyana-kru00/forum-project/forum-servise/internal/entity"
+	"github.com/Ulyana-kru00/forum-project/forum-servise/internal/repository"
+	"github.com/Ulyana-kru00/forum-project/forum-servise/pkg/logger"
+	"github.com/Ulyana-kru00/forum-project/forum-servise/pkg/verifier"
+)
+
+var (
+	errPinLockModeratorOnly = apperrors.New(apperrors.ErrPermissionDenied, "only moderators can pin and lock posts")
+	errTrashAdminOnly       = apperrors.New(apperrors.ErrPermissionDenied, "only admins can manage the trash")
+)
+
+const (
+	defaultTrashLimit = 20
+	maxTrashLimit     = 100
+
+	// purgeBatch bounds the number of posts one purge removes.
+	purgeBatch = 500
+)
+
+type PostAdminUsecaseInterface interface {
+	SetPinned(ctx context.Context, token string, postID int64, pinned bool) error
+	SetLocked(ctx context.Context, token string, postID int64, locked bool) error
+	ListTrash(ctx context.Context, token string, limit int, cursor string) (*entity.PostPage, error)
+	RestorePost(ctx context.Context, token string, postID int64) error
+}
+
+// PostAdminUsecase pins and locks posts and manages the trash that deleted
+// posts go to.
+type PostAdminUsecase struct {
+	postRepo repository.PostRepository
+	verifier verifier.TokenVerifier
+	logger   *logger.Logger
+	now      func() time.Time
+}
+
+func NewPostAdminUsecase(postRepo repository.PostRepository, tokenVerifier verifier.TokenVerifier, logger *logger.Logger) *PostAdminUsecase {
+	return &PostAdminUsecase{
+		postRepo: postRepo,
+		verifier: tokenVerifier,
+		logger:   logger,
+		now:      time.Now,
+	}
+}
+
+// SetPinned pins a post above all others in listings, or unpins it.
+// Moderators only.
+func (uc *PostAdminUsecase) SetPinned(ctx context.Context, token string, postID int64, pinned bool) error {
+	if err := uc.require(ctx, token, isModerator, errPinLockModeratorOnly); err != nil {
+		return err
+	}
+
+	return uc.postRepo.SetPinned(ctx, postID, pinned)
+}
+
+// SetLocked closes a post for new comments, or opens it again. Moderators
+// only.
+func (uc *PostAdminUsecase) SetLocked(ctx context.Context, token string, postID int64, locked bool) error {
+	if err := uc.require(ctx, token, isModerator, errPinLockModeratorOnly); err != nil {
+		return err
+	}
+
+	return uc.postRepo.SetLocked(ctx, postID, locked)
+}
+
+// ListTrash returns a page of deleted posts, most recently deleted first.
+// Admins only.
+func (uc *PostAdminUsecase) ListTrash(ctx context.Context, token string, limit int, cursor string) (*entity.PostPage, error) {
+	if err := uc.require(ctx, token, isAdmin, errTrashAdminOnly); err != nil {
+		return nil, err
+	}
+
+	if limit <= 0 {
+		limit = defaultTrashLimit
+	}
+	if limit > maxTrashLimit {
+		limit = maxTrashLimit
+	}
+	return uc.postRepo.ListDeletedPosts(ctx, limit, cursor)
+}
+
+// RestorePost takes a post out of the trash. Admins only.
+func (uc *PostAdminUsecase) RestorePost(ctx context.Context, token string, postID int64) error {
+	if err := uc.require(ctx, token, isAdmin, errTrashAdminOnly); err != nil {
+		return err
+	}
+
+	return uc.postRepo.RestorePost(ctx, postID)
+}
+
+// PurgeTrash removes posts that have been in the trash for longer than
+// retention and returns how many were removed.
+func (uc *PostAdminUsecase) PurgeTrash(ctx context.Context, retention time.Duration) (int64, error) {
+	return uc.postRepo.PurgeDeletedPosts(ctx, uc.now().Add(-retention), purgeBatch)
+}
+
+// RunTrashPurger purges the trash every interval until ctx is cancelled.
+func (uc *PostAdminUsecase) RunTrashPurger(ctx context.Context, interval, retention time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			n, err := uc.PurgeTrash(ctx, retention)
+			if err != nil {
+				uc.logger.Warnw("Failed to purge deleted posts", "error", err)
+			} else if n > 0 {
+				uc.logger.Infow("Purged deleted posts", "count", n)
+			}
+		}
+	}
+}
+
+func (uc *PostAdminUsecase) require(ctx context.Context, token string, allowed func(role string) bool, denied error) error {
+	claims, err := uc.verifier.Verify(ctx, token)
+	if err != nil {
+		return err
+	}
+	if !allowed(claims.Role) {
+		return denied
+	}
+	return nil
+}
+
+func isAdmin(role string) bool {
+	return role == "admin"
+}
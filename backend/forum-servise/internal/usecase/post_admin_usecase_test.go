@@ -0,0 +1,109 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Ulyana-kru00/forum-project/forum-servise/internal/entity"
+	"github.com/Ulyana-kru00/forum-project/forum-servise/internal/repository"
+	"github.com/Ulyana-kru00/forum-project/forum-servise/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func newPostAdminUsecase(repo *MockPostRepository, role string) *PostAdminUsecase {
+	log := &logger.Logger{SugaredLogger: zap.NewNop().Sugar()}
+	return NewPostAdminUsecase(repo, roleVerifier(role), log)
+}
+
+func TestPostAdminUsecase_PinAndLock(t *testing.T) {
+	var pinned, locked []int64
+	repo := &MockPostRepository{
+		SetPinnedFunc: func(ctx context.Context, id int64, on bool) error {
+			if !on {
+				id = -id
+			}
+			pinned = append(pinned, id)
+			return nil
+		},
+		SetLockedFunc: func(ctx context.Context, id int64, on bool) error {
+			if id == 9 {
+				return repository.ErrPostNotFound
+			}
+			locked = append(locked, id)
+			return nil
+		},
+	}
+
+	uc := newPostAdminUsecase(repo, "moderator")
+	require.NoError(t, uc.SetPinned(context.Background(), "token", 1, true))
+	require.NoError(t, uc.SetPinned(context.Background(), "token", 1, false))
+	require.NoError(t, uc.SetLocked(context.Background(), "token", 2, true))
+	assert.ErrorIs(t, uc.SetLocked(context.Background(), "token", 9, true), repository.ErrPostNotFound)
+	assert.Equal(t, []int64{1, -1}, pinned)
+	assert.Equal(t, []int64{2}, locked)
+
+	uc = newPostAdminUsecase(repo, "user")
+	assert.ErrorIs(t, uc.SetPinned(context.Background(), "token", 1, true), errPinLockModeratorOnly)
+	assert.ErrorIs(t, uc.SetLocked(context.Background(), "token", 1, true), errPinLockModeratorOnly)
+}
+
+func TestPostAdminUsecase_Trash(t *testing.T) {
+	var gotLimit int
+	repo := &MockPostRepository{
+		ListDeletedPostsFunc: func(ctx context.Context, limit int, cursor string) (*entity.PostPage, error) {
+			gotLimit = limit
+			return &entity.PostPage{Posts: []*entity.Post{{ID: 3}}}, nil
+		},
+		RestorePostFunc: func(ctx context.Context, id int64) error {
+			if id != 3 {
+				return repository.ErrPostNotInTrash
+			}
+			return nil
+		},
+	}
+
+	t.Run("List", func(t *testing.T) {
+		uc := newPostAdminUsecase(repo, "admin")
+		page, err := uc.ListTrash(context.Background(), "token", 0, "")
+		require.NoError(t, err)
+		assert.Len(t, page.Posts, 1)
+		assert.Equal(t, defaultTrashLimit, gotLimit)
+
+		_, err = uc.ListTrash(context.Background(), "token", 1000, "")
+		require.NoError(t, err)
+		assert.Equal(t, maxTrashLimit, gotLimit)
+	})
+
+	t.Run("Restore", func(t *testing.T) {
+		uc := newPostAdminUsecase(repo, "admin")
+		assert.NoError(t, uc.RestorePost(context.Background(), "token", 3))
+		assert.ErrorIs(t, uc.RestorePost(context.Background(), "token", 4), repository.ErrPostNotInTrash)
+	})
+
+	t.Run("Moderators are denied", func(t *testing.T) {
+		uc := newPostAdminUsecase(repo, "moderator")
+		_, err := uc.ListTrash(context.Background(), "token", 0, "")
+		assert.ErrorIs(t, err, errTrashAdminOnly)
+		assert.ErrorIs(t, uc.RestorePost(context.Background(), "token", 3), errTrashAdminOnly)
+	})
+}
+
+func TestPostAdminUsecase_PurgeTrash(t *testing.T) {
+	now := time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC)
+	repo := &MockPostRepository{
+		PurgeDeletedPostsFunc: func(ctx context.Context, before time.Time, limit int) (int64, error) {
+			assert.Equal(t, now.Add(-30*24*time.Hour), before)
+			assert.Equal(t, purgeBatch, limit)
+			return 2, nil
+		},
+	}
+	uc := newPostAdminUsecase(repo, "admin")
+	uc.now = func() time.Time { return now }
+
+	n, err := uc.PurgeTrash(context.Background(), 30*24*time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), n)
+}
@@ -2,72 +2,153 @@ package usecase
 
 import (
 	"context"
-	"database/sql"
-	"errors"
 	"time"
 
+	"backend.com/forum/apperrors"
 	pb "backend.com/forum/proto"
 	"github.com/Ulyana-kru00/forum-project/forum-servise/internal/entity"
 	"github.com/Ulyana-kru00/forum-project/forum-servise/internal/repository"
+	"github.com/Ulyana-kru00/forum-project/forum-servise/pkg/loader"
 	"github.com/Ulyana-kru00/forum-project/forum-servise/pkg/logger"
+	"github.com/Ulyana-kru00/forum-project/forum-servise/pkg/markdown"
+	"github.com/Ulyana-kru00/forum-project/forum-servise/pkg/verifier"
+)
+
+var (
+	errEmptyPost    = apperrors.New(apperrors.ErrInvalidArgument, "title and content are required")
+	errInvalidRange = apperrors.New(apperrors.ErrInvalidArgument, "from must be before to")
+
+	errWindowWithoutTop = apperrors.New(apperrors.ErrInvalidArgument, "window is only supported with sort=top")
+)
+
+const (
+	defaultPostsLimit = 10
+	maxPostsLimit     = 100
 )
 
 type PostUsecase struct {
 	postRepo   repository.PostRepository
 	authClient pb.AuthServiceClient
+	verifier   verifier.TokenVerifier
 	logger     *logger.Logger
+
+	// Notifier, when set, is told about every new post.
+	Notifier Notifier
 }
 type PostUsecaseInterface interface {
-	CreatePost(ctx context.Context, token, title, content string) (*entity.Post, error)
-	GetPosts(ctx context.Context) ([]*entity.Post, map[int]string, error)
+	CreatePost(ctx context.Context, token, title, content string, categoryID int64, tags []string) (*entity.Post, error)
+	GetPosts(ctx context.Context, filter entity.PostFilter) (*entity.PostPage, map[int]string, error)
 	DeletePost(ctx context.Context, token string, postID int64) error
-	UpdatePost(ctx context.Context, token string, postID int64, title, content string) (*entity.Post, error)
+	UpdatePost(ctx context.Context, token string, postID int64, title, content string, categoryID int64, tags []string) (*entity.Post, error)
 }
 
 func NewPostUsecase(
 	postRepo repository.PostRepository,
 	authClient pb.AuthServiceClient,
+	tokenVerifier verifier.TokenVerifier,
 	logger *logger.Logger,
 ) *PostUsecase {
 	return &PostUsecase{
 		postRepo:   postRepo,
 		authClient: authClient,
+		verifier:   tokenVerifier,
 		logger:     logger,
 	}
 }
 
-func (uc *PostUsecase) CreatePost(ctx context.Context, token string, title, content string) (*entity.Post, error) {
-
-	validateResp, err := uc.authClient.ValidateToken(ctx, &pb.ValidateTokenRequest{Token: token})
+// CreatePost publishes a post of the caller with up to maxTagsPerPost tags.
+// The Markdown content is stored together with its rendered HTML. Users
+// mentioned in the post are notified.
+func (uc *PostUsecase) CreatePost(ctx context.Context, token string, title, content string, categoryID int64, tags []string) (*entity.Post, error) {
+	if title == "" || content == "" {
+		return nil, errEmptyPost
+	}
+	html, err := markdown.Render(content)
+	if err != nil {
+		return nil, err
+	}
+	tags, err = postTags(tags)
 	if err != nil {
 		return nil, err
 	}
-	if !validateResp.Valid {
-		return nil, errors.New("invalid token")
+
+	claims, err := uc.verifier.Verify(ctx, token)
+	if err != nil {
+		return nil, err
 	}
-	userID := validateResp.UserId
+	userID := claims.UserID
 
 	post := &entity.Post{
-		Title:     title,
-		Content:   content,
-		AuthorID:  userID,
-		CreatedAt: time.Now(),
+		Title:       title,
+		Content:     content,
+		ContentHTML: html,
+		AuthorID:    userID,
+		CategoryID:  categoryID,
+		CreatedAt:   time.Now(),
+		Tags:        tags,
 	}
 
 	id, err := uc.postRepo.CreatePost(ctx, post)
 	if err != nil {
 		return nil, err
 	}
-
 	post.ID = id
+
+	if uc.Notifier != nil {
+		uc.Notifier.PostCreated(ctx, post)
+	}
 	return post, nil
 }
 
-func (uc *PostUsecase) GetPosts(ctx context.Context) ([]*entity.Post, map[int]string, error) {
-	posts, err := uc.postRepo.GetPosts(ctx)
+func (uc *PostUsecase) GetPosts(ctx context.Context, filter entity.PostFilter) (*entity.PostPage, map[int]string, error) {
+	if filter.Limit <= 0 {
+		filter.Limit = defaultPostsLimit
+	}
+	if filter.Limit > maxPostsLimit {
+		filter.Limit = maxPostsLimit
+	}
+	if filter.Sort == "" {
+		filter.Sort = entity.PostSortNewest
+	}
+	if !filter.Sort.Valid() {
+		return nil, nil, apperrors.New(apperrors.ErrInvalidArgument, "unknown sort: "+string(filter.Sort))
+	}
+	if !filter.From.IsZero() && !filter.To.IsZero() && !filter.From.Before(filter.To) {
+		return nil, nil, errInvalidRange
+	}
+	if len(filter.Tags) > 0 {
+		tags, err := normalizeTags(filter.Tags)
+		if err != nil {
+			return nil, nil, err
+		}
+		if len(tags) > maxFilterTags {
+			return nil, nil, errTooManyFilterTags
+		}
+		filter.Tags = tags
+	}
+	if filter.TagMatch != "" && !filter.TagMatch.Valid() {
+		return nil, nil, apperrors.New(apperrors.ErrInvalidArgument, "unknown tag match: "+string(filter.TagMatch))
+	}
+	if filter.Window != "" {
+		if filter.Sort != entity.PostSortTop {
+			return nil, nil, errWindowWithoutTop
+		}
+		if !filter.Window.Valid() {
+			return nil, nil, apperrors.New(apperrors.ErrInvalidArgument, "unknown window: "+string(filter.Window))
+		}
+		// The window narrows an explicit range, it never widens it.
+		if d := filter.Window.Duration(); d > 0 {
+			if since := time.Now().Add(-d); since.After(filter.From) {
+				filter.From = since
+			}
+		}
+	}
+
+	page, err := uc.postRepo.GetPosts(ctx, filter)
 	if err != nil {
 		return nil, nil, err
 	}
+	posts := page.Posts
 
 	authorNames := make(map[int]string)
 
@@ -76,14 +157,12 @@ func (uc *PostUsecase) GetPosts(ctx context.Context) ([]*entity.Post, map[int]st
 		authorIDs = append(authorIDs, post.AuthorID)
 	}
 
-	for _, authorID := range authorIDs {
-		userResponse, err := uc.authClient.GetUser(ctx, &pb.GetUserRequest{
-			Id: authorID,
-		})
-
-		if err == nil && userResponse.User != nil {
-			authorNames[int(authorID)] = userResponse.User.Username
-		}
+	names, err := loader.FromContext(ctx, uc.authClient).Names(ctx, authorIDs)
+	if err != nil && uc.logger != nil {
+		uc.logger.Warnw("Failed to load post authors", "error", err)
+	}
+	for id, name := range names {
+		authorNames[int(id)] = name
 	}
 
 	for _, post := range posts {
@@ -92,61 +171,77 @@ func (uc *PostUsecase) GetPosts(ctx context.Context) ([]*entity.Post, map[int]st
 		}
 	}
 
-	return posts, authorNames, nil
+	return page, authorNames, nil
 }
 func (uc *PostUsecase) DeletePost(ctx context.Context, token string, postID int64) error {
-	validateResp, err := uc.authClient.ValidateToken(ctx, &pb.ValidateTokenRequest{Token: token})
+	claims, err := uc.verifier.Verify(ctx, token)
 	if err != nil {
 		return err
 	}
-	if !validateResp.Valid {
-		return errors.New("invalid token")
-	}
 
-	err = uc.postRepo.DeletePost(
+	return uc.postRepo.DeletePost(
 		ctx,
 		postID,
-		validateResp.UserId,
-		validateResp.Role,
+		claims.UserID,
+		claims.Role,
 	)
-
-	if err != nil {
-		switch {
-		case errors.Is(err, sql.ErrNoRows):
-			return errors.New("post not found")
-		case errors.Is(err, repository.ErrPermissionDenied):
-			return errors.New("permission denied")
-		default:
-			return err
-		}
-	}
-
-	return nil
 }
 
+// UpdatePost changes a post of the caller, or any post for admins. A zero
+// categoryID keeps the category of the post. Nil tags leave the tags of the
+// post as they are; an empty slice removes them.
 func (uc *PostUsecase) UpdatePost(
 	ctx context.Context,
 	token string,
 	postID int64,
 	title,
 	content string,
+	categoryID int64,
+	tags []string,
 ) (*entity.Post, error) {
-	validateResp, err := uc.authClient.ValidateToken(ctx, &pb.ValidateTokenRequest{Token: token})
+	if title == "" || content == "" {
+		return nil, errEmptyPost
+	}
+	html, err := markdown.Render(content)
 	if err != nil {
 		return nil, err
 	}
-	if !validateResp.Valid {
-		return nil, errors.New("invalid token")
+	if tags != nil {
+		if tags, err = postTags(tags); err != nil {
+			return nil, err
+		}
+	}
+
+	claims, err := uc.verifier.Verify(ctx, token)
+	if err != nil {
+		return nil, err
 	}
 
 	updatedPost, err := uc.postRepo.UpdatePost(
 		ctx,
 		postID,
-		validateResp.UserId,
-		validateResp.Role,
+		claims.UserID,
+		claims.Role,
 		title,
 		content,
+		html,
+		categoryID,
+		tags,
 	)
+	if err != nil {
+		return nil, err
+	}
+	return updatedPost, nil
+}
 
-	return updatedPost, err
+// postTags normalizes the tags of a post and enforces maxTagsPerPost.
+func postTags(tags []string) ([]string, error) {
+	tags, err := normalizeTags(tags)
+	if err != nil {
+		return nil, err
+	}
+	if len(tags) > maxTagsPerPost {
+		return nil, errTooManyTags
+	}
+	return tags, nil
 }
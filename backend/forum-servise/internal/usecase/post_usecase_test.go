@@ -4,13 +4,16 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"fmt"
 	"testing"
 	"time"
 
+	"backend.com/forum/apperrors"
 	pb "backend.com/forum/proto"
 	"github.com/Ulyana-kru00/forum-project/forum-servise/internal/entity"
 	"github.com/Ulyana-kru00/forum-project/forum-servise/internal/repository"
 	"github.com/Ulyana-kru00/forum-project/forum-servise/pkg/logger"
+	"github.com/Ulyana-kru00/forum-project/forum-servise/pkg/verifier"
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
@@ -63,7 +66,7 @@ func TestPostUsecase_UpdatePost(t *testing.T) {
 			},
 			mockRepo: func() *MockPostRepository {
 				return &MockPostRepository{
-					UpdatePostFunc: func(ctx context.Context, id, authorID int64, role, title, content string) (*entity.Post, error) {
+					UpdatePostFunc: func(ctx context.Context, id, authorID int64, role, title, content, contentHTML string, categoryID int64, tags []string) (*entity.Post, error) {
 						return updatedPost, nil
 					},
 				}
@@ -90,7 +93,7 @@ func TestPostUsecase_UpdatePost(t *testing.T) {
 			},
 			mockRepo: func() *MockPostRepository {
 				return &MockPostRepository{
-					UpdatePostFunc: func(ctx context.Context, id, authorID int64, role, title, content string) (*entity.Post, error) {
+					UpdatePostFunc: func(ctx context.Context, id, authorID int64, role, title, content, contentHTML string, categoryID int64, tags []string) (*entity.Post, error) {
 						return updatedPost, nil
 					},
 				}
@@ -115,7 +118,7 @@ func TestPostUsecase_UpdatePost(t *testing.T) {
 			},
 			mockRepo: func() *MockPostRepository {
 				return &MockPostRepository{
-					UpdatePostFunc: func(ctx context.Context, id, authorID int64, role, title, content string) (*entity.Post, error) {
+					UpdatePostFunc: func(ctx context.Context, id, authorID int64, role, title, content, contentHTML string, categoryID int64, tags []string) (*entity.Post, error) {
 						return nil, nil
 					},
 				}
@@ -142,7 +145,7 @@ func TestPostUsecase_UpdatePost(t *testing.T) {
 			},
 			mockRepo: func() *MockPostRepository {
 				return &MockPostRepository{
-					UpdatePostFunc: func(ctx context.Context, id, authorID int64, role, title, content string) (*entity.Post, error) {
+					UpdatePostFunc: func(ctx context.Context, id, authorID int64, role, title, content, contentHTML string, categoryID int64, tags []string) (*entity.Post, error) {
 						return nil, sql.ErrNoRows
 					},
 				}
@@ -161,10 +164,11 @@ func TestPostUsecase_UpdatePost(t *testing.T) {
 			uc := &PostUsecase{
 				postRepo:   mockRepo,
 				authClient: mockAuth,
+				verifier:   verifier.NewRemote(mockAuth),
 				logger:     mockLogger,
 			}
 
-			got, err := uc.UpdatePost(context.Background(), tt.token, tt.postID, tt.title, tt.content)
+			got, err := uc.UpdatePost(context.Background(), tt.token, tt.postID, tt.title, tt.content, 0, nil)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("UpdatePost() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -281,7 +285,7 @@ func TestPostUsecase_DeletePost(t *testing.T) {
 			mockRepo: func() *MockPostRepository {
 				return &MockPostRepository{
 					DeletePostFunc: func(ctx context.Context, id, authorID int64, role string) error {
-						return sql.ErrNoRows
+						return repository.ErrPostNotFound
 					},
 				}
 			},
@@ -324,6 +328,7 @@ func TestPostUsecase_DeletePost(t *testing.T) {
 			uc := &PostUsecase{
 				postRepo:   mockRepo,
 				authClient: mockAuth,
+				verifier:   verifier.NewRemote(mockAuth),
 				logger:     mockLogger,
 			}
 
@@ -374,20 +379,19 @@ func TestPostUsecase_GetPosts(t *testing.T) {
 			name: "Success with usernames",
 			mockAuth: func() *MockAuthServiceClient {
 				return &MockAuthServiceClient{
-					GetUserFunc: func(ctx context.Context, in *pb.GetUserRequest, opts ...grpc.CallOption) (*pb.GetUserResponse, error) {
-						return &pb.GetUserResponse{
-							User: &pb.User{
-								Id:       in.Id,
-								Username: "user" + string(rune(in.Id)),
-							},
-						}, nil
+					GetUsersFunc: func(ctx context.Context, in *pb.GetUsersRequest, opts ...grpc.CallOption) (*pb.GetUsersResponse, error) {
+						users := make([]*pb.User, 0, len(in.Ids))
+						for _, id := range in.Ids {
+							users = append(users, &pb.User{Id: id, Username: fmt.Sprintf("user%d", id)})
+						}
+						return &pb.GetUsersResponse{Users: users}, nil
 					},
 				}
 			},
 			mockRepo: func() *MockPostRepository {
 				return &MockPostRepository{
-					GetPostsFunc: func(ctx context.Context) ([]*entity.Post, error) {
-						return posts, nil
+					GetPostsFunc: func(ctx context.Context, filter entity.PostFilter) (*entity.PostPage, error) {
+						return &entity.PostPage{Posts: posts}, nil
 					},
 				}
 			},
@@ -405,7 +409,7 @@ func TestPostUsecase_GetPosts(t *testing.T) {
 			},
 			mockRepo: func() *MockPostRepository {
 				return &MockPostRepository{
-					GetPostsFunc: func(ctx context.Context) ([]*entity.Post, error) {
+					GetPostsFunc: func(ctx context.Context, filter entity.PostFilter) (*entity.PostPage, error) {
 						return nil, errors.New("database error")
 					},
 				}
@@ -417,20 +421,17 @@ func TestPostUsecase_GetPosts(t *testing.T) {
 			name: "Partial user info",
 			mockAuth: func() *MockAuthServiceClient {
 				return &MockAuthServiceClient{
-					GetUserFunc: func(ctx context.Context, in *pb.GetUserRequest, opts ...grpc.CallOption) (*pb.GetUserResponse, error) {
-						if in.Id == 1 {
-							return &pb.GetUserResponse{
-								User: &pb.User{Username: "user1"},
-							}, nil
-						}
-						return nil, errors.New("user not found")
+					GetUsersFunc: func(ctx context.Context, in *pb.GetUsersRequest, opts ...grpc.CallOption) (*pb.GetUsersResponse, error) {
+						return &pb.GetUsersResponse{
+							Users: []*pb.User{{Id: 1, Username: "user1"}},
+						}, nil
 					},
 				}
 			},
 			mockRepo: func() *MockPostRepository {
 				return &MockPostRepository{
-					GetPostsFunc: func(ctx context.Context) ([]*entity.Post, error) {
-						return posts, nil
+					GetPostsFunc: func(ctx context.Context, filter entity.PostFilter) (*entity.PostPage, error) {
+						return &entity.PostPage{Posts: posts}, nil
 					},
 				}
 			},
@@ -452,10 +453,11 @@ func TestPostUsecase_GetPosts(t *testing.T) {
 			uc := &PostUsecase{
 				postRepo:   mockRepo,
 				authClient: mockAuth,
+				verifier:   verifier.NewRemote(mockAuth),
 				logger:     mockLogger,
 			}
 
-			gotPosts, gotNames, err := uc.GetPosts(context.Background())
+			gotPage, gotNames, err := uc.GetPosts(context.Background(), entity.PostFilter{})
 			if (err != nil) != tt.wantErr {
 				t.Errorf("GetPosts() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -468,12 +470,87 @@ func TestPostUsecase_GetPosts(t *testing.T) {
 				return
 			}
 
-			assert.Equal(t, tt.wantPosts, gotPosts)
+			assert.Equal(t, tt.wantPosts, gotPage.Posts)
 			assert.Equal(t, tt.wantNames, gotNames)
 		})
 	}
 }
 
+func TestPostUsecase_GetPosts_Filter(t *testing.T) {
+	now := time.Now()
+
+	tests := []struct {
+		name    string
+		filter  entity.PostFilter
+		want    entity.PostFilter
+		wantErr error
+	}{
+		{
+			name:   "Defaults",
+			filter: entity.PostFilter{},
+			want:   entity.PostFilter{Limit: defaultPostsLimit, Sort: entity.PostSortNewest},
+		},
+		{
+			name:   "Limit is capped",
+			filter: entity.PostFilter{Limit: 1000, Sort: entity.PostSortOldest},
+			want:   entity.PostFilter{Limit: maxPostsLimit, Sort: entity.PostSortOldest},
+		},
+		{
+			name:    "Unknown sort",
+			filter:  entity.PostFilter{Sort: "random"},
+			wantErr: apperrors.ErrInvalidArgument,
+		},
+		{
+			name:    "Inverted date range",
+			filter:  entity.PostFilter{From: now, To: now.Add(-time.Hour)},
+			wantErr: apperrors.ErrInvalidArgument,
+		},
+		{
+			name:   "Tags are normalized",
+			filter: entity.PostFilter{Tags: []string{" Go", "go", "Machine Learning", ""}, TagMatch: entity.TagMatchAny},
+			want: entity.PostFilter{
+				Limit:    defaultPostsLimit,
+				Sort:     entity.PostSortNewest,
+				Tags:     []string{"go", "machine-learning"},
+				TagMatch: entity.TagMatchAny,
+			},
+		},
+		{
+			name:    "Invalid tag",
+			filter:  entity.PostFilter{Tags: []string{"go;drop"}},
+			wantErr: apperrors.ErrInvalidArgument,
+		},
+		{
+			name:    "Unknown tag match",
+			filter:  entity.PostFilter{Tags: []string{"go"}, TagMatch: "some"},
+			wantErr: apperrors.ErrInvalidArgument,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got entity.PostFilter
+			uc := &PostUsecase{
+				postRepo: &MockPostRepository{
+					GetPostsFunc: func(ctx context.Context, filter entity.PostFilter) (*entity.PostPage, error) {
+						got = filter
+						return &entity.PostPage{}, nil
+					},
+				},
+				authClient: &MockAuthServiceClient{},
+			}
+
+			_, _, err := uc.GetPosts(context.Background(), tt.filter)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
 func TestPostUsecase_CreatePost(t *testing.T) {
 	now := time.Now()
 	tests := []struct {
@@ -574,10 +651,11 @@ func TestPostUsecase_CreatePost(t *testing.T) {
 			uc := &PostUsecase{
 				postRepo:   mockRepo,
 				authClient: mockAuth,
+				verifier:   verifier.NewRemote(mockAuth),
 				logger:     mockLogger,
 			}
 
-			got, err := uc.CreatePost(context.Background(), tt.token, tt.title, tt.content)
+			got, err := uc.CreatePost(context.Background(), tt.token, tt.title, tt.content, 0, nil)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("CreatePost() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -598,3 +676,28 @@ func TestPostUsecase_CreatePost(t *testing.T) {
 		})
 	}
 }
+
+func TestPostUsecase_RendersMarkdown(t *testing.T) {
+	var stored *entity.Post
+	var updatedHTML string
+	repo := &MockPostRepository{
+		CreatePostFunc: func(ctx context.Context, post *entity.Post) (int64, error) {
+			stored = post
+			return 1, nil
+		},
+		UpdatePostFunc: func(ctx context.Context, id, authorID int64, role, title, content, contentHTML string, categoryID int64, tags []string) (*entity.Post, error) {
+			updatedHTML = contentHTML
+			return &entity.Post{ID: id, Title: title, Content: content, ContentHTML: contentHTML}, nil
+		},
+	}
+	uc := &PostUsecase{postRepo: repo, verifier: roleVerifier("user")}
+
+	post, err := uc.CreatePost(context.Background(), "token", "Title", "# Hi <script>x</script>", 0, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "<h1>Hi x</h1>\n", stored.ContentHTML)
+	assert.Equal(t, stored.ContentHTML, post.ContentHTML)
+
+	_, err = uc.UpdatePost(context.Background(), "token", 1, "Title", "`code`", 0, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "<p><code>code</code></p>\n", updatedHTML)
+}
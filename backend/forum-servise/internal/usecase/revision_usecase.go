@@ -0,0 +1,146 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"backend.com/forum/apperrors"
+	pb "backend.com/forum/proto"
+	"github.com/Ulyana-kru00/forum-project/forum-servise/internal/entity"
+	"github.com/Ulyana-kru00/forum-project/forum-servise/internal/repository"
+	"github.com/Ulyana-kru00/forum-project/forum-servise/pkg/loader"
+	"github.com/Ulyana-kru00/forum-project/forum-servise/pkg/verifier"
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+var (
+	errRestoreAdminOnly = apperrors.New(apperrors.ErrPermissionDenied, "only admins can restore revisions")
+	errInvalidRevision  = apperrors.New(apperrors.ErrInvalidArgument, "revision must be a positive number")
+)
+
+// diffContext is the number of unchanged lines shown around each change.
+const diffContext = 3
+
+type RevisionUsecaseInterface interface {
+	ListRevisions(ctx context.Context, postID int64) ([]*entity.PostRevision, error)
+	GetRevision(ctx context.Context, postID int64, revision int) (*entity.PostRevision, error)
+	DiffRevisions(ctx context.Context, postID int64, from, to int) (*entity.RevisionDiff, error)
+	RestoreRevision(ctx context.Context, token string, postID int64, revision int) (*entity.Post, error)
+}
+
+type RevisionUsecase struct {
+	revisionRepo repository.RevisionRepository
+	authClient   pb.AuthServiceClient
+	verifier     verifier.TokenVerifier
+}
+
+func NewRevisionUsecase(revisionRepo repository.RevisionRepository, authClient pb.AuthServiceClient, tokenVerifier verifier.TokenVerifier) *RevisionUsecase {
+	return &RevisionUsecase{
+		revisionRepo: revisionRepo,
+		authClient:   authClient,
+		verifier:     tokenVerifier,
+	}
+}
+
+// ListRevisions returns the revisions of a post, newest first, with the
+// names of their editors.
+func (uc *RevisionUsecase) ListRevisions(ctx context.Context, postID int64) ([]*entity.PostRevision, error) {
+	revisions, err := uc.revisionRepo.ListRevisions(ctx, postID)
+	if err != nil {
+		return nil, err
+	}
+
+	editorIDs := make([]int64, 0, len(revisions))
+	for _, rev := range revisions {
+		if rev.EditorID != nil {
+			editorIDs = append(editorIDs, *rev.EditorID)
+		}
+	}
+	names, _ := loader.FromContext(ctx, uc.authClient).Names(ctx, editorIDs)
+	for _, rev := range revisions {
+		if rev.EditorID == nil {
+			continue
+		}
+		if name, ok := names[*rev.EditorID]; ok {
+			rev.EditorName = name
+		} else {
+			rev.EditorName = "Unknown"
+		}
+	}
+
+	return revisions, nil
+}
+
+func (uc *RevisionUsecase) GetRevision(ctx context.Context, postID int64, revision int) (*entity.PostRevision, error) {
+	if revision <= 0 {
+		return nil, errInvalidRevision
+	}
+	return uc.revisionRepo.GetRevision(ctx, postID, revision)
+}
+
+// DiffRevisions returns a unified line diff of the content of two revisions
+// of a post. from may be newer than to, which gives the reverse diff.
+func (uc *RevisionUsecase) DiffRevisions(ctx context.Context, postID int64, from, to int) (*entity.RevisionDiff, error) {
+	if from <= 0 || to <= 0 {
+		return nil, errInvalidRevision
+	}
+	a, err := uc.revisionRepo.GetRevision(ctx, postID, from)
+	if err != nil {
+		return nil, err
+	}
+	b, err := uc.revisionRepo.GetRevision(ctx, postID, to)
+	if err != nil {
+		return nil, err
+	}
+
+	diff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        diffLines(a.Content),
+		B:        diffLines(b.Content),
+		FromFile: fmt.Sprintf("revision %d", from),
+		ToFile:   fmt.Sprintf("revision %d", to),
+		Context:  diffContext,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &entity.RevisionDiff{
+		PostID:    postID,
+		From:      from,
+		To:        to,
+		TitleFrom: a.Title,
+		TitleTo:   b.Title,
+		Diff:      diff,
+	}, nil
+}
+
+// RestoreRevision brings back the title and content of an earlier revision.
+// The restore is itself recorded as a new revision. Admins only.
+func (uc *RevisionUsecase) RestoreRevision(ctx context.Context, token string, postID int64, revision int) (*entity.Post, error) {
+	if revision <= 0 {
+		return nil, errInvalidRevision
+	}
+	claims, err := uc.verifier.Verify(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	if claims.Role != "admin" {
+		return nil, errRestoreAdminOnly
+	}
+
+	return uc.revisionRepo.RestoreRevision(ctx, postID, revision, claims.UserID)
+}
+
+// diffLines splits content into newline-terminated lines. Unlike
+// difflib.SplitLines it adds no empty line after a trailing newline, and it
+// terminates the last line when content has no trailing newline so the
+// diff stays line-oriented.
+func diffLines(content string) []string {
+	if content == "" {
+		return nil
+	}
+	lines := strings.SplitAfter(strings.TrimSuffix(content, "\n"), "\n")
+	lines[len(lines)-1] += "\n"
+	return lines
+}
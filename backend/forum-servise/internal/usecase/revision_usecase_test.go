@@ -0,0 +1,105 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+
+	"backend.com/forum/apperrors"
+	pb "backend.com/forum/proto"
+	"github.com/Ulyana-kru00/forum-project/forum-servise/internal/entity"
+	"github.com/Ulyana-kru00/forum-project/forum-servise/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+)
+
+func TestRevisionUsecase_ListRevisions(t *testing.T) {
+	editor := int64(1)
+	gone := int64(2)
+	repo := &MockRevisionRepository{
+		ListRevisionsFunc: func(ctx context.Context, postID int64) ([]*entity.PostRevision, error) {
+			return []*entity.PostRevision{
+				{Revision: 3, EditorID: &editor},
+				{Revision: 2, EditorID: &gone},
+				{Revision: 1},
+			}, nil
+		},
+	}
+	auth := &MockAuthServiceClient{
+		GetUsersFunc: func(ctx context.Context, in *pb.GetUsersRequest, opts ...grpc.CallOption) (*pb.GetUsersResponse, error) {
+			return &pb.GetUsersResponse{Users: []*pb.User{{Id: 1, Username: "alice"}}}, nil
+		},
+	}
+	uc := NewRevisionUsecase(repo, auth, roleVerifier("user"))
+
+	revisions, err := uc.ListRevisions(context.Background(), 1)
+	require.NoError(t, err)
+	assert.Equal(t, "alice", revisions[0].EditorName)
+	assert.Equal(t, "Unknown", revisions[1].EditorName)
+	assert.Empty(t, revisions[2].EditorName)
+}
+
+func TestRevisionUsecase_DiffRevisions(t *testing.T) {
+	repo := &MockRevisionRepository{
+		GetRevisionFunc: func(ctx context.Context, postID int64, revision int) (*entity.PostRevision, error) {
+			switch revision {
+			case 1:
+				return &entity.PostRevision{Revision: 1, Title: "Old", Content: "first\nsecond\nthird\n"}, nil
+			case 2:
+				return &entity.PostRevision{Revision: 2, Title: "New", Content: "first\n2nd\nthird\n"}, nil
+			}
+			return nil, repository.ErrRevisionNotFound
+		},
+	}
+	uc := NewRevisionUsecase(repo, nil, roleVerifier("user"))
+
+	t.Run("Success", func(t *testing.T) {
+		diff, err := uc.DiffRevisions(context.Background(), 1, 1, 2)
+		require.NoError(t, err)
+		assert.Equal(t, "Old", diff.TitleFrom)
+		assert.Equal(t, "New", diff.TitleTo)
+		assert.Equal(t, "--- revision 1\n+++ revision 2\n@@ -1,3 +1,3 @@\n first\n-second\n+2nd\n third\n", diff.Diff)
+	})
+
+	t.Run("Same content", func(t *testing.T) {
+		diff, err := uc.DiffRevisions(context.Background(), 1, 2, 2)
+		require.NoError(t, err)
+		assert.Empty(t, diff.Diff)
+	})
+
+	t.Run("Unknown revision", func(t *testing.T) {
+		_, err := uc.DiffRevisions(context.Background(), 1, 1, 7)
+		assert.ErrorIs(t, err, apperrors.ErrNotFound)
+	})
+
+	t.Run("Invalid revision", func(t *testing.T) {
+		_, err := uc.DiffRevisions(context.Background(), 1, 0, 2)
+		assert.ErrorIs(t, err, errInvalidRevision)
+	})
+}
+
+func TestRevisionUsecase_RestoreRevision(t *testing.T) {
+	var editor int64
+	repo := &MockRevisionRepository{
+		RestoreRevisionFunc: func(ctx context.Context, postID int64, revision int, editorID int64) (*entity.Post, error) {
+			editor = editorID
+			return &entity.Post{ID: postID, Title: "Old"}, nil
+		},
+	}
+
+	t.Run("Admin", func(t *testing.T) {
+		uc := NewRevisionUsecase(repo, nil, roleVerifier("admin"))
+
+		post, err := uc.RestoreRevision(context.Background(), "token", 1, 1)
+		require.NoError(t, err)
+		assert.Equal(t, "Old", post.Title)
+		assert.Equal(t, int64(1), editor)
+	})
+
+	t.Run("Not admin", func(t *testing.T) {
+		uc := NewRevisionUsecase(repo, nil, roleVerifier("user"))
+
+		_, err := uc.RestoreRevision(context.Background(), "token", 1, 1)
+		assert.ErrorIs(t, err, apperrors.ErrPermissionDenied)
+	})
+}
@@ -0,0 +1,68 @@
+package usecase
+
+import (
+	"context"
+	"strings"
+
+	"backend.com/forum/apperrors"
+	pb "backend.com/forum/proto"
+	"github.com/Ulyana-kru00/forum-project/forum-servise/internal/entity"
+	"github.com/Ulyana-kru00/forum-project/forum-servise/internal/repository"
+	"github.com/Ulyana-kru00/forum-project/forum-servise/pkg/loader"
+)
+
+var errEmptySearchQuery = apperrors.New(apperrors.ErrInvalidArgument, "search query is required")
+
+type SearchUsecaseInterface interface {
+	Search(ctx context.Context, filter entity.SearchFilter) (*entity.SearchPage, error)
+}
+
+type SearchUsecase struct {
+	searchRepo repository.SearchRepository
+	authClient pb.AuthServiceClient
+}
+
+func NewSearchUsecase(searchRepo repository.SearchRepository, authClient pb.AuthServiceClient) *SearchUsecase {
+	return &SearchUsecase{
+		searchRepo: searchRepo,
+		authClient: authClient,
+	}
+}
+
+// Search pages through posts and comments matching filter.Query. Limits
+// follow the posts listing.
+func (uc *SearchUsecase) Search(ctx context.Context, filter entity.SearchFilter) (*entity.SearchPage, error) {
+	filter.Query = strings.TrimSpace(filter.Query)
+	if filter.Query == "" {
+		return nil, errEmptySearchQuery
+	}
+	if !filter.Type.Valid() {
+		return nil, apperrors.New(apperrors.ErrInvalidArgument, "unknown search type: "+string(filter.Type))
+	}
+	if filter.Limit <= 0 {
+		filter.Limit = defaultPostsLimit
+	}
+	if filter.Limit > maxPostsLimit {
+		filter.Limit = maxPostsLimit
+	}
+
+	page, err := uc.searchRepo.Search(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	authorIDs := make([]int64, 0, len(page.Results))
+	for _, res := range page.Results {
+		authorIDs = append(authorIDs, res.AuthorID)
+	}
+	names, _ := loader.FromContext(ctx, uc.authClient).Names(ctx, authorIDs)
+	for _, res := range page.Results {
+		if name, ok := names[res.AuthorID]; ok {
+			res.AuthorName = name
+		} else {
+			res.AuthorName = "Unknown"
+		}
+	}
+
+	return page, nil
+}
@@ -0,0 +1,78 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"backend.com/forum/apperrors"
+	pb "backend.com/forum/proto"
+	"github.com/Ulyana-kru00/forum-project/forum-servise/internal/entity"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+)
+
+func TestSearchUsecase_Search(t *testing.T) {
+	auth := &MockAuthServiceClient{
+		GetUsersFunc: func(ctx context.Context, in *pb.GetUsersRequest, opts ...grpc.CallOption) (*pb.GetUsersResponse, error) {
+			return &pb.GetUsersResponse{Users: []*pb.User{{Id: 1, Username: "alice"}}}, nil
+		},
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		var got entity.SearchFilter
+		repo := &MockSearchRepository{
+			SearchFunc: func(ctx context.Context, filter entity.SearchFilter) (*entity.SearchPage, error) {
+				got = filter
+				return &entity.SearchPage{Results: []*entity.SearchResult{
+					{Type: entity.SearchTypePost, ID: 1, AuthorID: 1},
+					{Type: entity.SearchTypeComment, ID: 2, AuthorID: 2},
+				}, NextCursor: "next"}, nil
+			},
+		}
+		uc := NewSearchUsecase(repo, auth)
+
+		page, err := uc.Search(context.Background(), entity.SearchFilter{Query: "  go  ", Limit: 500})
+		require.NoError(t, err)
+		assert.Equal(t, entity.SearchFilter{Query: "go", Limit: maxPostsLimit}, got)
+		assert.Equal(t, "alice", page.Results[0].AuthorName)
+		assert.Equal(t, "Unknown", page.Results[1].AuthorName)
+		assert.Equal(t, "next", page.NextCursor)
+	})
+
+	t.Run("Default limit", func(t *testing.T) {
+		var got entity.SearchFilter
+		repo := &MockSearchRepository{
+			SearchFunc: func(ctx context.Context, filter entity.SearchFilter) (*entity.SearchPage, error) {
+				got = filter
+				return &entity.SearchPage{}, nil
+			},
+		}
+
+		_, err := NewSearchUsecase(repo, auth).Search(context.Background(), entity.SearchFilter{Query: "go", Type: entity.SearchTypeComment})
+		require.NoError(t, err)
+		assert.Equal(t, defaultPostsLimit, got.Limit)
+	})
+
+	t.Run("Invalid input", func(t *testing.T) {
+		uc := NewSearchUsecase(&MockSearchRepository{}, auth)
+
+		_, err := uc.Search(context.Background(), entity.SearchFilter{Query: "   "})
+		assert.ErrorIs(t, err, apperrors.ErrInvalidArgument)
+
+		_, err = uc.Search(context.Background(), entity.SearchFilter{Query: "go", Type: "user"})
+		assert.ErrorIs(t, err, apperrors.ErrInvalidArgument)
+	})
+
+	t.Run("Repository error", func(t *testing.T) {
+		repo := &MockSearchRepository{
+			SearchFunc: func(ctx context.Context, filter entity.SearchFilter) (*entity.SearchPage, error) {
+				return nil, errors.New("database error")
+			},
+		}
+
+		_, err := NewSearchUsecase(repo, auth).Search(context.Background(), entity.SearchFilter{Query: "go"})
+		assert.EqualError(t, err, "database error")
+	})
+}
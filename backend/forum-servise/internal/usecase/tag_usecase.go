@@ -0,0 +1,149 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"backend.com/forum/apperrors"
+	"github.com/Ulyana-kru00/forum-project/forum-servise/internal/entity"
+	"github.com/Ulyana-kru00/forum-project/forum-servise/internal/repository"
+	"github.com/Ulyana-kru00/forum-project/forum-servise/pkg/verifier"
+)
+
+var (
+	errTagAdminOnly = apperrors.New(apperrors.ErrPermissionDenied, "only admins can manage tags")
+	errEmptyTag     = apperrors.New(apperrors.ErrInvalidArgument, "tag name is required")
+	errInvalidTag   = apperrors.New(apperrors.ErrInvalidArgument,
+		fmt.Sprintf("tags may contain up to %d letters, digits and the characters - _ . + #", maxTagLength))
+	errTooManyTags       = apperrors.New(apperrors.ErrInvalidArgument, fmt.Sprintf("a post may have at most %d tags", maxTagsPerPost))
+	errTooManyFilterTags = apperrors.New(apperrors.ErrInvalidArgument, fmt.Sprintf("at most %d tags can be filtered on", maxFilterTags))
+	errSameTag           = apperrors.New(apperrors.ErrInvalidArgument, "source and target tags are the same")
+)
+
+// tagPattern is checked after whitespace inside a tag became dashes.
+var tagPattern = regexp.MustCompile(`^[\p{Ll}\p{Lo}\p{N}_.+#-]+$`)
+
+const (
+	// maxTagLength matches the tags.name column.
+	maxTagLength   = 32
+	maxTagsPerPost = 5
+	// maxFilterTags bounds the tag= parameters of a posts listing.
+	maxFilterTags = 10
+
+	defaultTagsLimit = 50
+	maxTagsLimit     = 200
+)
+
+type TagUsecaseInterface interface {
+	ListTags(ctx context.Context, filter entity.TagFilter) ([]*entity.Tag, error)
+	RenameTag(ctx context.Context, token, name, newName string) (*entity.Tag, error)
+	MergeTags(ctx context.Context, token, source, target string) (*entity.Tag, error)
+}
+
+type TagUsecase struct {
+	tagRepo  repository.TagRepository
+	verifier verifier.TokenVerifier
+}
+
+func NewTagUsecase(tagRepo repository.TagRepository, tokenVerifier verifier.TokenVerifier) *TagUsecase {
+	return &TagUsecase{
+		tagRepo:  tagRepo,
+		verifier: tokenVerifier,
+	}
+}
+
+// ListTags returns the tags in use, most used first, optionally only those
+// starting with filter.Prefix.
+func (uc *TagUsecase) ListTags(ctx context.Context, filter entity.TagFilter) ([]*entity.Tag, error) {
+	filter.Prefix = strings.ToLower(strings.TrimSpace(filter.Prefix))
+	if filter.Limit <= 0 {
+		filter.Limit = defaultTagsLimit
+	}
+	if filter.Limit > maxTagsLimit {
+		filter.Limit = maxTagsLimit
+	}
+
+	return uc.tagRepo.ListTags(ctx, filter)
+}
+
+// RenameTag renames a tag on all of its posts. Admins only.
+func (uc *TagUsecase) RenameTag(ctx context.Context, token, name, newName string) (*entity.Tag, error) {
+	name, newName, err := uc.adminTagPair(ctx, token, name, newName)
+	if err != nil {
+		return nil, err
+	}
+
+	return uc.tagRepo.RenameTag(ctx, name, newName)
+}
+
+// MergeTags replaces source with target on all posts and deletes source.
+// Admins only.
+func (uc *TagUsecase) MergeTags(ctx context.Context, token, source, target string) (*entity.Tag, error) {
+	source, target, err := uc.adminTagPair(ctx, token, source, target)
+	if err != nil {
+		return nil, err
+	}
+
+	return uc.tagRepo.MergeTags(ctx, source, target)
+}
+
+// adminTagPair checks that the caller is an admin and normalizes the two
+// distinct tag names of a rename or merge.
+func (uc *TagUsecase) adminTagPair(ctx context.Context, token, from, to string) (string, string, error) {
+	claims, err := uc.verifier.Verify(ctx, token)
+	if err != nil {
+		return "", "", err
+	}
+	if claims.Role != "admin" {
+		return "", "", errTagAdminOnly
+	}
+
+	if from, err = normalizeTag(from); err != nil {
+		return "", "", err
+	}
+	if to, err = normalizeTag(to); err != nil {
+		return "", "", err
+	}
+	if from == to {
+		return "", "", errSameTag
+	}
+	return from, to, nil
+}
+
+// normalizeTag lowercases a tag and turns inner whitespace into dashes, so
+// "Machine  Learning" becomes "machine-learning".
+func normalizeTag(tag string) (string, error) {
+	tag = strings.Join(strings.Fields(strings.ToLower(tag)), "-")
+	if tag == "" {
+		return "", errEmptyTag
+	}
+	if utf8.RuneCountInString(tag) > maxTagLength || !tagPattern.MatchString(tag) {
+		return "", errInvalidTag
+	}
+	return tag, nil
+}
+
+// normalizeTags normalizes tags and drops duplicates and blanks, keeping the
+// original order.
+func normalizeTags(tags []string) ([]string, error) {
+	normalized := make([]string, 0, len(tags))
+	seen := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		if strings.TrimSpace(tag) == "" {
+			continue
+		}
+		name, err := normalizeTag(tag)
+		if err != nil {
+			return nil, err
+		}
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+		normalized = append(normalized, name)
+	}
+	return normalized, nil
+}
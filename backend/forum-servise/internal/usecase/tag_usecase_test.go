@@ -0,0 +1,130 @@
+package usecase
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"backend.com/forum/apperrors"
+	"github.com/Ulyana-kru00/forum-project/forum-servise/internal/entity"
+	"github.com/Ulyana-kru00/forum-project/forum-servise/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeTag(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    string
+		wantErr error
+	}{
+		{in: "Go", want: "go"},
+		{in: "  Machine \t Learning ", want: "machine-learning"},
+		{in: "C++", want: "c++"},
+		{in: "Базы_Данных", want: "базы_данных"},
+		{in: "   ", wantErr: errEmptyTag},
+		{in: "go,sql", wantErr: errInvalidTag},
+		{in: strings.Repeat("я", maxTagLength), want: strings.Repeat("я", maxTagLength)},
+		{in: strings.Repeat("a", maxTagLength+1), wantErr: errInvalidTag},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := normalizeTag(tt.in)
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestPostUsecase_PostTags(t *testing.T) {
+	var saved []string
+	repo := &MockPostRepository{
+		CreatePostFunc: func(ctx context.Context, post *entity.Post) (int64, error) {
+			saved = post.Tags
+			return 4, nil
+		},
+		UpdatePostFunc: func(ctx context.Context, postID, authorID int64, role, title, content, contentHTML string, categoryID int64, tags []string) (*entity.Post, error) {
+			saved = tags
+			return &entity.Post{ID: postID, Title: title, Content: content, Tags: tags}, nil
+		},
+	}
+	uc := &PostUsecase{postRepo: repo, verifier: roleVerifier("user")}
+
+	// Tags are handed to the repository with the post so both are stored
+	// in one transaction.
+	post, err := uc.CreatePost(context.Background(), "token", "Title", "Body", 0, []string{"Go", "SQL", "go"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"go", "sql"}, saved)
+	assert.Equal(t, []string{"go", "sql"}, post.Tags)
+
+	_, err = uc.CreatePost(context.Background(), "token", "Title", "Body", 0, []string{"a", "b", "c", "d", "e", "f"})
+	assert.ErrorIs(t, err, errTooManyTags)
+
+	// Nil tags leave the tags of the post alone.
+	saved = []string{"unchanged"}
+	post, err = uc.UpdatePost(context.Background(), "token", 4, "Title", "Body", 0, nil)
+	require.NoError(t, err)
+	assert.Nil(t, saved)
+	assert.Nil(t, post.Tags)
+
+	post, err = uc.UpdatePost(context.Background(), "token", 4, "Title", "Body", 0, []string{})
+	require.NoError(t, err)
+	assert.Equal(t, []string{}, saved)
+	assert.Equal(t, []string{}, post.Tags)
+}
+
+func TestTagUsecase_ListTags(t *testing.T) {
+	var got entity.TagFilter
+	uc := NewTagUsecase(&MockTagRepository{
+		ListTagsFunc: func(ctx context.Context, filter entity.TagFilter) ([]*entity.Tag, error) {
+			got = filter
+			return []*entity.Tag{}, nil
+		},
+	}, roleVerifier("user"))
+
+	_, err := uc.ListTags(context.Background(), entity.TagFilter{Prefix: " Go ", Limit: 1000})
+	require.NoError(t, err)
+	assert.Equal(t, entity.TagFilter{Prefix: "go", Limit: maxTagsLimit}, got)
+}
+
+func TestTagUsecase_RenameAndMerge(t *testing.T) {
+	repo := &MockTagRepository{
+		RenameTagFunc: func(ctx context.Context, name, newName string) (*entity.Tag, error) {
+			return &entity.Tag{ID: 1, Name: newName}, nil
+		},
+		MergeTagsFunc: func(ctx context.Context, source, target string) (*entity.Tag, error) {
+			if target == "missing" {
+				return nil, repository.ErrTagNotFound
+			}
+			return &entity.Tag{ID: 2, Name: target, PostCount: 5}, nil
+		},
+	}
+
+	t.Run("Admin", func(t *testing.T) {
+		uc := NewTagUsecase(repo, roleVerifier("admin"))
+
+		tag, err := uc.RenameTag(context.Background(), "token", "golang", "Go Lang")
+		require.NoError(t, err)
+		assert.Equal(t, "go-lang", tag.Name)
+
+		tag, err = uc.MergeTags(context.Background(), "token", "golang", "go")
+		require.NoError(t, err)
+		assert.Equal(t, int64(5), tag.PostCount)
+
+		_, err = uc.MergeTags(context.Background(), "token", "golang", "missing")
+		assert.ErrorIs(t, err, apperrors.ErrNotFound)
+
+		_, err = uc.MergeTags(context.Background(), "token", "Go", "go")
+		assert.ErrorIs(t, err, errSameTag)
+	})
+
+	t.Run("Not admin", func(t *testing.T) {
+		uc := NewTagUsecase(repo, roleVerifier("user"))
+
+		_, err := uc.RenameTag(context.Background(), "token", "golang", "go")
+		assert.ErrorIs(t, err, apperrors.ErrPermissionDenied)
+		_, err = uc.MergeTags(context.Background(), "token", "golang", "go")
+		assert.ErrorIs(t, err, apperrors.ErrPermissionDenied)
+	})
+}
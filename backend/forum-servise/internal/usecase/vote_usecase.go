@@ -0,0 +1,61 @@
+package usecase
+
+import (
+	"context"
+
+	"backend.com/forum/apperrors"
+	"github.com/Ulyana-kru00/forum-project/forum-servise/internal/entity"
+	"github.com/Ulyana-kru00/forum-project/forum-servise/internal/repository"
+	"github.com/Ulyana-kru00/forum-project/forum-servise/pkg/verifier"
+)
+
+var errInvalidVote = apperrors.New(apperrors.ErrInvalidArgument, "vote must be 1, -1 or 0")
+
+type VoteUsecaseInterface interface {
+	VotePost(ctx context.Context, token string, postID int64, value int) (*entity.VoteResult, error)
+	VoteComment(ctx context.Context, token string, postID, commentID int64, value int) (*entity.VoteResult, error)
+}
+
+type VoteUsecase struct {
+	voteRepo repository.VoteRepository
+	verifier verifier.TokenVerifier
+}
+
+func NewVoteUsecase(voteRepo repository.VoteRepository, tokenVerifier verifier.TokenVerifier) *VoteUsecase {
+	return &VoteUsecase{
+		voteRepo: voteRepo,
+		verifier: tokenVerifier,
+	}
+}
+
+// VotePost records the caller's vote on a post. Voting again replaces the
+// previous vote; value 0 retracts it.
+func (uc *VoteUsecase) VotePost(ctx context.Context, token string, postID int64, value int) (*entity.VoteResult, error) {
+	if !validVote(value) {
+		return nil, errInvalidVote
+	}
+	claims, err := uc.verifier.Verify(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+
+	return uc.voteRepo.VotePost(ctx, claims.UserID, postID, value)
+}
+
+// VoteComment records the caller's vote on a comment of the given post, the
+// same way as VotePost.
+func (uc *VoteUsecase) VoteComment(ctx context.Context, token string, postID, commentID int64, value int) (*entity.VoteResult, error) {
+	if !validVote(value) {
+		return nil, errInvalidVote
+	}
+	claims, err := uc.verifier.Verify(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+
+	return uc.voteRepo.VoteComment(ctx, claims.UserID, postID, commentID, value)
+}
+
+func validVote(value int) bool {
+	return value >= -1 && value <= 1
+}
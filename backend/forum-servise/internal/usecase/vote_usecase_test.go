@@ -0,0 +1,67 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+
+	"backend.com/forum/apperrors"
+	pb "backend.com/forum/proto"
+	"github.com/Ulyana-kru00/forum-project/forum-servise/internal/entity"
+	"github.com/Ulyana-kru00/forum-project/forum-servise/internal/repository"
+	"github.com/Ulyana-kru00/forum-project/forum-servise/pkg/verifier"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+)
+
+func TestVoteUsecase_VotePost(t *testing.T) {
+	repo := &MockVoteRepository{
+		VotePostFunc: func(ctx context.Context, userID, postID int64, value int) (*entity.VoteResult, error) {
+			assert.Equal(t, int64(1), userID)
+			if postID == 9 {
+				return nil, repository.ErrPostNotFound
+			}
+			return &entity.VoteResult{Score: 3, Vote: value}, nil
+		},
+	}
+	uc := NewVoteUsecase(repo, roleVerifier("user"))
+
+	result, err := uc.VotePost(context.Background(), "token", 1, -1)
+	require.NoError(t, err)
+	assert.Equal(t, &entity.VoteResult{Score: 3, Vote: -1}, result)
+
+	_, err = uc.VotePost(context.Background(), "token", 9, 1)
+	assert.ErrorIs(t, err, apperrors.ErrNotFound)
+
+	_, err = uc.VotePost(context.Background(), "token", 1, 2)
+	assert.ErrorIs(t, err, apperrors.ErrInvalidArgument)
+}
+
+func TestVoteUsecase_VoteComment(t *testing.T) {
+	var gotPostID, gotCommentID int64
+	repo := &MockVoteRepository{
+		VoteCommentFunc: func(ctx context.Context, userID, postID, commentID int64, value int) (*entity.VoteResult, error) {
+			gotPostID, gotCommentID = postID, commentID
+			return &entity.VoteResult{Score: 0, Vote: value}, nil
+		},
+	}
+
+	t.Run("Retract", func(t *testing.T) {
+		uc := NewVoteUsecase(repo, roleVerifier("user"))
+		result, err := uc.VoteComment(context.Background(), "token", 1, 7, 0)
+		require.NoError(t, err)
+		assert.Equal(t, 0, result.Vote)
+		assert.Equal(t, int64(1), gotPostID)
+		assert.Equal(t, int64(7), gotCommentID)
+	})
+
+	t.Run("Invalid token", func(t *testing.T) {
+		uc := NewVoteUsecase(repo, verifier.NewRemote(&MockAuthServiceClient{
+			ValidateTokenFunc: func(ctx context.Context, in *pb.ValidateTokenRequest, opts ...grpc.CallOption) (*pb.ValidateTokenResponse, error) {
+				return &pb.ValidateTokenResponse{Valid: false}, nil
+			},
+		}))
+		_, err := uc.VoteComment(context.Background(), "bad", 1, 7, 1)
+		assert.ErrorIs(t, err, apperrors.ErrUnauthenticated)
+	})
+}
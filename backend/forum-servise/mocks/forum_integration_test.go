@@ -18,6 +18,7 @@ import (
 	"github.com/Ulyana-kru00/forum-project/forum-servise/internal/repository"
 	"github.com/Ulyana-kru00/forum-project/forum-servise/internal/usecase"
 	"github.com/Ulyana-kru00/forum-project/forum-servise/pkg/logger"
+	"github.com/Ulyana-kru00/forum-project/forum-servise/pkg/verifier"
 	"github.com/gin-gonic/gin"
 	"github.com/jmoiron/sqlx"
 	"github.com/stretchr/testify/assert"
@@ -25,6 +26,8 @@ import (
 	"google.golang.org/grpc"
 )
 
+const insertRevisionQuery = `INSERT INTO post_revisions (post_id, revision, title, content, content_html, editor_id, restored_from) SELECT id, COALESCE((SELECT MAX(revision) FROM post_revisions WHERE post_id = $1), 0) + 1, title, content, content_html, $2, $3 FROM posts WHERE id = $1`
+
 type mockAuthClient struct {
 	pb.AuthServiceClient
 	validateFunc func(context.Context, *pb.ValidateTokenRequest, ...grpc.CallOption) (*pb.ValidateTokenResponse, error)
@@ -39,6 +42,19 @@ func (m *mockAuthClient) GetUser(ctx context.Context, in *pb.GetUserRequest, opt
 	return m.getUserFunc(ctx, in, opts...)
 }
 
+func (m *mockAuthClient) GetUsers(ctx context.Context, in *pb.GetUsersRequest, opts ...grpc.CallOption) (*pb.GetUsersResponse, error) {
+	resp := &pb.GetUsersResponse{}
+	if m.getUserFunc == nil {
+		return resp, nil
+	}
+	for _, id := range in.Ids {
+		if u, err := m.getUserFunc(ctx, &pb.GetUserRequest{Id: id}, opts...); err == nil && u.User != nil {
+			resp.Users = append(resp.Users, u.User)
+		}
+	}
+	return resp, nil
+}
+
 type testDependencies struct {
 	db          *sql.DB
 	mock        sqlmock.Sqlmock
@@ -75,8 +91,8 @@ func setupTest(t *testing.T) *testDependencies {
 	postRepo := repository.NewPostRepository(sqlxDB)
 	commentRepo := repository.NewCommentRepository(sqlxDB)
 
-	postUC := usecase.NewPostUsecase(postRepo, authClient, nil)
-	commentUC := usecase.NewCommentUseCase(commentRepo, postRepo, authClient)
+	postUC := usecase.NewPostUsecase(postRepo, authClient, verifier.NewRemote(authClient), nil)
+	commentUC := usecase.NewCommentUseCase(commentRepo, postRepo, authClient, verifier.NewRemote(authClient))
 
 	return &testDependencies{
 		db:          db,
@@ -95,14 +111,19 @@ func TestForumService_Integrated(t *testing.T) {
 
 		t.Run("Create and get post", func(t *testing.T) {
 			now := time.Now()
-			createQuery := `INSERT INTO posts (title, content, author_id, created_at) VALUES ($1, $2, $3, $4) RETURNING id`
-			getQuery := `SELECT id, title, content, author_id, created_at FROM posts WHERE id = $1`
+			createQuery := `INSERT INTO posts (title, content, content_html, author_id, created_at, category_id) VALUES ($1, $2, $3, $4, $5, COALESCE(NULLIF($6, 0), (SELECT id FROM categories ORDER BY position, id LIMIT 1))) RETURNING id, category_id`
+			getQuery := `SELECT id, title, content, content_html, author_id, created_at, pinned_at IS NOT NULL AS pinned, locked_at IS NOT NULL AS locked FROM posts WHERE id = $1 AND deleted_at IS NULL AND hidden_at IS NULL`
 
+			deps.mock.ExpectBegin()
 			deps.mock.ExpectQuery(createQuery).
-				WithArgs("Test Post", "Test Content", int64(1), sqlmock.AnyArg()).
-				WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+				WithArgs("Test Post", "Test Content", "<p>Test Content</p>\n", int64(1), sqlmock.AnyArg(), int64(0)).
+				WillReturnRows(sqlmock.NewRows([]string{"id", "category_id"}).AddRow(1, 1))
+			deps.mock.ExpectExec(insertRevisionQuery).
+				WithArgs(int64(1), int64(1), nil).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+			deps.mock.ExpectCommit()
 
-			post, err := deps.postUC.CreatePost(context.Background(), "valid_token", "Test Post", "Test Content")
+			post, err := deps.postUC.CreatePost(context.Background(), "valid_token", "Test Post", "Test Content", 0, nil)
 			require.NoError(t, err)
 			assert.Equal(t, int64(1), post.ID)
 
@@ -117,23 +138,30 @@ func TestForumService_Integrated(t *testing.T) {
 		})
 
 		t.Run("Get posts list", func(t *testing.T) {
-			query := `SELECT id, title, content, author_id, created_at FROM posts ORDER BY created_at DESC`
+			query := `SELECT id, title, content, content_html, author_id, category_id, created_at, comment_count, last_activity_at, score, hot_rank, pinned_at IS NOT NULL AS pinned, locked_at IS NOT NULL AS locked FROM posts WHERE deleted_at IS NULL AND hidden_at IS NULL AND pinned_at IS NULL ORDER BY created_at DESC, id DESC LIMIT $1`
 			now := time.Now()
 
 			deps.mock.ExpectQuery(query).
 				WillReturnRows(sqlmock.NewRows([]string{"id", "title", "content", "author_id", "created_at"}).
 					AddRow(1, "First Post", "First Content", int64(1), now).
 					AddRow(2, "Second Post", "Second Content", int64(2), now.Add(-time.Hour)))
+			deps.mock.ExpectQuery(`SELECT id, title, content, content_html, author_id, category_id, created_at, comment_count, last_activity_at, score, hot_rank, pinned_at IS NOT NULL AS pinned, locked_at IS NOT NULL AS locked FROM posts WHERE deleted_at IS NULL AND hidden_at IS NULL AND pinned_at IS NOT NULL ORDER BY pinned_at DESC, id DESC`).
+				WillReturnRows(sqlmock.NewRows([]string{"id"}))
+			deps.mock.ExpectQuery(`SELECT pt.post_id, t.name FROM post_tags pt JOIN tags t ON t.id = pt.tag_id WHERE pt.post_id = ANY($1) ORDER BY t.name`).
+				WillReturnRows(sqlmock.NewRows([]string{"post_id", "name"}).AddRow(1, "go"))
 
-			posts, authorNames, err := deps.postUC.GetPosts(context.Background())
+			page, authorNames, err := deps.postUC.GetPosts(context.Background(), entity.PostFilter{})
 			require.NoError(t, err)
-			assert.Len(t, posts, 2)
+			assert.Len(t, page.Posts, 2)
+			assert.Equal(t, []string{"go"}, page.Posts[0].Tags)
+			assert.Equal(t, []string{}, page.Posts[1].Tags)
+			assert.Empty(t, page.NextCursor)
 			assert.Equal(t, "testuser", authorNames[1])
 		})
 
 		t.Run("Create comment", func(t *testing.T) {
-			postQuery := `SELECT id, title, content, author_id, created_at FROM posts WHERE id = $1`
-			commentQuery := `INSERT INTO comments (content, author_id, post_id, author_name) VALUES ($1, $2, $3, $4) RETURNING id`
+			postQuery := `SELECT id, title, content, content_html, author_id, created_at, pinned_at IS NOT NULL AS pinned, locked_at IS NOT NULL AS locked FROM posts WHERE id = $1 AND deleted_at IS NULL AND hidden_at IS NULL`
+			commentQuery := `INSERT INTO comments (content, content_html, author_id, post_id, author_name, parent_id) VALUES ($1, $2, $3, $4, $5, $6) RETURNING id`
 
 			deps.mock.ExpectQuery(postQuery).
 				WithArgs(int64(1)).
@@ -141,7 +169,7 @@ func TestForumService_Integrated(t *testing.T) {
 					AddRow(1, "Test Post", "Test Content", int64(1), time.Now()))
 
 			deps.mock.ExpectQuery(commentQuery).
-				WithArgs("Test Comment", int64(1), int64(1), "testuser").
+				WithArgs("Test Comment", "<p>Test Comment</p>\n", int64(1), int64(1), "testuser", nil).
 				WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
 
 			comment := &entity.Comment{
@@ -150,14 +178,14 @@ func TestForumService_Integrated(t *testing.T) {
 				PostID:   1,
 			}
 
-			err := deps.commentUC.CreateComment(context.Background(), comment)
+			err := deps.commentUC.CreateComment(context.Background(), "valid_token", comment)
 			require.NoError(t, err)
 			assert.Equal(t, int64(1), comment.ID)
 		})
 
 		t.Run("Get comments", func(t *testing.T) {
-			postQuery := `SELECT id, title, content, author_id, created_at FROM posts WHERE id = $1`
-			commentQuery := `SELECT id, content, author_id, post_id, author_name FROM comments WHERE post_id = $1 ORDER BY id DESC`
+			postQuery := `SELECT id, title, content, content_html, author_id, created_at, pinned_at IS NOT NULL AS pinned, locked_at IS NOT NULL AS locked FROM posts WHERE id = $1 AND deleted_at IS NULL AND hidden_at IS NULL`
+			commentQuery := `SELECT id, content, content_html, author_id, post_id, author_name, created_at, edited_at, deleted_at IS NOT NULL AS deleted, hidden_at IS NOT NULL AS hidden FROM comments WHERE post_id = $1 ORDER BY id DESC`
 
 			deps.mock.ExpectQuery(postQuery).
 				WithArgs(int64(1)).
@@ -175,20 +203,25 @@ func TestForumService_Integrated(t *testing.T) {
 		})
 
 		t.Run("Update post", func(t *testing.T) {
-			query := `UPDATE posts SET title = $1, content = $2 WHERE id = $3 AND (author_id = $4 OR $5 = 'admin') RETURNING id, title, content, author_id, created_at`
+			query := `UPDATE posts SET title = $1, content = $2, content_html = $3, category_id = COALESCE(NULLIF($4, 0), category_id) WHERE id = $5 AND deleted_at IS NULL AND (author_id = $6 OR $7 = 'admin') RETURNING id, title, content, content_html, author_id, category_id, created_at`
 
+			deps.mock.ExpectBegin()
 			deps.mock.ExpectQuery(query).
-				WithArgs("Updated Title", "Updated Content", int64(1), int64(1), "user").
-				WillReturnRows(sqlmock.NewRows([]string{"id", "title", "content", "author_id", "created_at"}).
-					AddRow(1, "Updated Title", "Updated Content", int64(1), time.Now()))
+				WithArgs("Updated Title", "Updated Content", "<p>Updated Content</p>\n", int64(0), int64(1), int64(1), "user").
+				WillReturnRows(sqlmock.NewRows([]string{"id", "title", "content", "content_html", "author_id", "category_id", "created_at"}).
+					AddRow(1, "Updated Title", "Updated Content", "<p>Updated Content</p>\n", int64(1), int64(1), time.Now()))
+			deps.mock.ExpectExec(insertRevisionQuery).
+				WithArgs(int64(1), int64(1), nil).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+			deps.mock.ExpectCommit()
 
-			post, err := deps.postUC.UpdatePost(context.Background(), "valid_token", 1, "Updated Title", "Updated Content")
+			post, err := deps.postUC.UpdatePost(context.Background(), "valid_token", 1, "Updated Title", "Updated Content", 0, nil)
 			require.NoError(t, err)
 			assert.Equal(t, "Updated Title", post.Title)
 		})
 
 		t.Run("Delete post", func(t *testing.T) {
-			query := `DELETE FROM posts WHERE id = $1 AND (author_id = $2 OR $3 = 'admin')`
+			query := `UPDATE posts SET deleted_at = CURRENT_TIMESTAMP, deleted_by = $2 WHERE id = $1 AND deleted_at IS NULL AND (author_id = $2 OR $3 = 'admin')`
 
 			deps.mock.ExpectExec(query).
 				WithArgs(int64(1), int64(1), "user").
@@ -206,28 +239,30 @@ func TestForumService_Integrated(t *testing.T) {
 		defer deps.db.Close()
 
 		t.Run("Create post database error", func(t *testing.T) {
-			query := `INSERT INTO posts (title, content, author_id, created_at) VALUES ($1, $2, $3, $4) RETURNING id`
+			query := `INSERT INTO posts (title, content, content_html, author_id, created_at, category_id) VALUES ($1, $2, $3, $4, $5, COALESCE(NULLIF($6, 0), (SELECT id FROM categories ORDER BY position, id LIMIT 1))) RETURNING id, category_id`
 
+			deps.mock.ExpectBegin()
 			deps.mock.ExpectQuery(query).
-				WithArgs("Bad Post", "Bad Content", int64(1), sqlmock.AnyArg()).
+				WithArgs("Bad Post", "Bad Content", "<p>Bad Content</p>\n", int64(1), sqlmock.AnyArg(), int64(0)).
 				WillReturnError(errors.New("database error"))
+			deps.mock.ExpectRollback()
 
-			_, err := deps.postUC.CreatePost(context.Background(), "valid_token", "Bad Post", "Bad Content")
+			_, err := deps.postUC.CreatePost(context.Background(), "valid_token", "Bad Post", "Bad Content", 0, nil)
 			require.Error(t, err)
 		})
 
 		t.Run("Get posts list error", func(t *testing.T) {
-			query := `SELECT id, title, content, author_id, created_at FROM posts ORDER BY created_at DESC`
+			query := `SELECT id, title, content, content_html, author_id, category_id, created_at, comment_count, last_activity_at, score, hot_rank, pinned_at IS NOT NULL AS pinned, locked_at IS NOT NULL AS locked FROM posts WHERE deleted_at IS NULL AND hidden_at IS NULL AND pinned_at IS NULL ORDER BY created_at DESC, id DESC LIMIT $1`
 
 			deps.mock.ExpectQuery(query).
 				WillReturnError(errors.New("database error"))
 
-			_, _, err := deps.postUC.GetPosts(context.Background())
+			_, _, err := deps.postUC.GetPosts(context.Background(), entity.PostFilter{})
 			require.Error(t, err)
 		})
 
 		t.Run("Create comment for non-existent post", func(t *testing.T) {
-			query := `SELECT id, title, content, author_id, created_at FROM posts WHERE id = $1`
+			query := `SELECT id, title, content, content_html, author_id, created_at, pinned_at IS NOT NULL AS pinned, locked_at IS NOT NULL AS locked FROM posts WHERE id = $1 AND deleted_at IS NULL AND hidden_at IS NULL`
 
 			deps.mock.ExpectQuery(query).
 				WithArgs(int64(999)).
@@ -239,29 +274,37 @@ func TestForumService_Integrated(t *testing.T) {
 				PostID:   999,
 			}
 
-			err := deps.commentUC.CreateComment(context.Background(), comment)
+			err := deps.commentUC.CreateComment(context.Background(), "valid_token", comment)
 			require.Error(t, err)
 			assert.True(t, errors.Is(err, repository.ErrPostNotFound))
 		})
 
 		t.Run("Update non-existent post", func(t *testing.T) {
-			query := `UPDATE posts SET title = $1, content = $2 WHERE id = $3 AND (author_id = $4 OR $5 = 'admin') RETURNING id, title, content, author_id, created_at`
+			query := `UPDATE posts SET title = $1, content = $2, content_html = $3, category_id = COALESCE(NULLIF($4, 0), category_id) WHERE id = $5 AND deleted_at IS NULL AND (author_id = $6 OR $7 = 'admin') RETURNING id, title, content, content_html, author_id, category_id, created_at`
 
+			deps.mock.ExpectBegin()
 			deps.mock.ExpectQuery(query).
-				WithArgs("New Title", "New Content", int64(999), int64(1), "user").
+				WithArgs("New Title", "New Content", "<p>New Content</p>\n", int64(0), int64(999), int64(1), "user").
 				WillReturnError(sql.ErrNoRows)
+			deps.mock.ExpectQuery(`SELECT EXISTS(SELECT 1 FROM posts WHERE id = $1 AND deleted_at IS NULL)`).
+				WithArgs(int64(999)).
+				WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+			deps.mock.ExpectRollback()
 
-			_, err := deps.postUC.UpdatePost(context.Background(), "valid_token", 999, "New Title", "New Content")
+			_, err := deps.postUC.UpdatePost(context.Background(), "valid_token", 999, "New Title", "New Content", 0, nil)
 			require.Error(t, err)
 			assert.True(t, errors.Is(err, repository.ErrPostNotFound))
 		})
 
 		t.Run("Delete non-existent post", func(t *testing.T) {
-			query := `DELETE FROM posts WHERE id = $1 AND (author_id = $2 OR $3 = 'admin')`
+			query := `UPDATE posts SET deleted_at = CURRENT_TIMESTAMP, deleted_by = $2 WHERE id = $1 AND deleted_at IS NULL AND (author_id = $2 OR $3 = 'admin')`
 
 			deps.mock.ExpectExec(query).
 				WithArgs(int64(999), int64(1), "user").
 				WillReturnResult(sqlmock.NewResult(0, 0))
+			deps.mock.ExpectQuery(`SELECT EXISTS(SELECT 1 FROM posts WHERE id = $1 AND deleted_at IS NULL)`).
+				WithArgs(int64(999)).
+				WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
 
 			err := deps.postUC.DeletePost(context.Background(), "valid_token", 999)
 			require.Error(t, err)
@@ -275,9 +318,9 @@ func TestForumService_Integrated(t *testing.T) {
 				},
 			}
 
-			errorPostUC := usecase.NewPostUsecase(deps.postRepo, errorAuthClient, nil)
+			errorPostUC := usecase.NewPostUsecase(deps.postRepo, errorAuthClient, verifier.NewRemote(errorAuthClient), nil)
 
-			_, err := errorPostUC.CreatePost(context.Background(), "invalid_token", "Test", "Content")
+			_, err := errorPostUC.CreatePost(context.Background(), "invalid_token", "Test", "Content", 0, nil)
 			require.Error(t, err)
 		})
 
@@ -289,20 +332,22 @@ func TestForumService_Integrated(t *testing.T) {
 		defer deps.db.Close()
 
 		t.Run("Empty posts list", func(t *testing.T) {
-			query := `SELECT id, title, content, author_id, created_at FROM posts ORDER BY created_at DESC`
+			query := `SELECT id, title, content, content_html, author_id, category_id, created_at, comment_count, last_activity_at, score, hot_rank, pinned_at IS NOT NULL AS pinned, locked_at IS NOT NULL AS locked FROM posts WHERE deleted_at IS NULL AND hidden_at IS NULL AND pinned_at IS NULL ORDER BY created_at DESC, id DESC LIMIT $1`
 
 			deps.mock.ExpectQuery(query).
 				WillReturnRows(sqlmock.NewRows([]string{"id", "title", "content", "author_id", "created_at"}))
+			deps.mock.ExpectQuery(`SELECT id, title, content, content_html, author_id, category_id, created_at, comment_count, last_activity_at, score, hot_rank, pinned_at IS NOT NULL AS pinned, locked_at IS NOT NULL AS locked FROM posts WHERE deleted_at IS NULL AND hidden_at IS NULL AND pinned_at IS NOT NULL ORDER BY pinned_at DESC, id DESC`).
+				WillReturnRows(sqlmock.NewRows([]string{"id"}))
 
-			posts, authorNames, err := deps.postUC.GetPosts(context.Background())
+			page, authorNames, err := deps.postUC.GetPosts(context.Background(), entity.PostFilter{})
 			require.NoError(t, err)
-			assert.Empty(t, posts)
+			assert.Empty(t, page.Posts)
 			assert.Empty(t, authorNames)
 		})
 
 		t.Run("Create comment database error", func(t *testing.T) {
-			postQuery := `SELECT id, title, content, author_id, created_at FROM posts WHERE id = $1`
-			commentQuery := `INSERT INTO comments (content, author_id, post_id, author_name) VALUES ($1, $2, $3, $4) RETURNING id`
+			postQuery := `SELECT id, title, content, content_html, author_id, created_at, pinned_at IS NOT NULL AS pinned, locked_at IS NOT NULL AS locked FROM posts WHERE id = $1 AND deleted_at IS NULL AND hidden_at IS NULL`
+			commentQuery := `INSERT INTO comments (content, content_html, author_id, post_id, author_name, parent_id) VALUES ($1, $2, $3, $4, $5, $6) RETURNING id`
 
 			deps.mock.ExpectQuery(postQuery).
 				WithArgs(int64(1)).
@@ -310,7 +355,7 @@ func TestForumService_Integrated(t *testing.T) {
 					AddRow(1, "Test Post", "Test Content", int64(1), time.Now()))
 
 			deps.mock.ExpectQuery(commentQuery).
-				WithArgs("Bad Comment", int64(1), int64(1), "testuser").
+				WithArgs("Bad Comment", "<p>Bad Comment</p>\n", int64(1), int64(1), "testuser", nil).
 				WillReturnError(errors.New("database error"))
 
 			comment := &entity.Comment{
@@ -319,7 +364,7 @@ func TestForumService_Integrated(t *testing.T) {
 				PostID:   1,
 			}
 
-			err := deps.commentUC.CreateComment(context.Background(), comment)
+			err := deps.commentUC.CreateComment(context.Background(), "valid_token", comment)
 			require.Error(t, err)
 		})
 
@@ -335,16 +380,21 @@ func TestForumService_Integrated(t *testing.T) {
 				},
 			}
 
-			postUC := usecase.NewPostUsecase(deps.postRepo, authClient, nil)
+			postUC := usecase.NewPostUsecase(deps.postRepo, authClient, verifier.NewRemote(authClient), nil)
 
-			query := `UPDATE posts SET title = $1, content = $2 WHERE id = $3 AND (author_id = $4 OR $5 = 'admin') RETURNING id, title, content, author_id, created_at`
+			query := `UPDATE posts SET title = $1, content = $2, content_html = $3, category_id = COALESCE(NULLIF($4, 0), category_id) WHERE id = $5 AND deleted_at IS NULL AND (author_id = $6 OR $7 = 'admin') RETURNING id, title, content, content_html, author_id, category_id, created_at`
 
+			deps.mock.ExpectBegin()
 			deps.mock.ExpectQuery(query).
-				WithArgs("Admin Updated", "Admin Content", int64(1), int64(2), "admin").
-				WillReturnRows(sqlmock.NewRows([]string{"id", "title", "content", "author_id", "created_at"}).
-					AddRow(1, "Admin Updated", "Admin Content", int64(1), time.Now()))
+				WithArgs("Admin Updated", "Admin Content", "<p>Admin Content</p>\n", int64(0), int64(1), int64(2), "admin").
+				WillReturnRows(sqlmock.NewRows([]string{"id", "title", "content", "content_html", "author_id", "category_id", "created_at"}).
+					AddRow(1, "Admin Updated", "Admin Content", "<p>Admin Content</p>\n", int64(1), int64(1), time.Now()))
+			deps.mock.ExpectExec(insertRevisionQuery).
+				WithArgs(int64(1), int64(2), nil).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+			deps.mock.ExpectCommit()
 
-			_, err := postUC.UpdatePost(context.Background(), "admin_token", 1, "Admin Updated", "Admin Content")
+			_, err := postUC.UpdatePost(context.Background(), "admin_token", 1, "Admin Updated", "Admin Content", 0, nil)
 			require.NoError(t, err)
 		})
 
@@ -362,9 +412,9 @@ func TestForumService_Integrated(t *testing.T) {
 				},
 			}
 
-			commentUC := usecase.NewCommentUseCase(deps.commentRepo, deps.postRepo, authClient)
+			commentUC := usecase.NewCommentUseCase(deps.commentRepo, deps.postRepo, authClient, verifier.NewRemote(authClient))
 
-			deps.mock.ExpectQuery(`SELECT id, title, content, author_id, created_at FROM posts WHERE id = $1`).
+			deps.mock.ExpectQuery(`SELECT id, title, content, content_html, author_id, created_at, pinned_at IS NOT NULL AS pinned, locked_at IS NOT NULL AS locked FROM posts WHERE id = $1 AND deleted_at IS NULL AND hidden_at IS NULL`).
 				WithArgs(int64(1)).
 				WillReturnRows(sqlmock.NewRows([]string{"id", "title", "content", "author_id", "created_at"}).
 					AddRow(1, "Test Post", "Test Content", int64(1), time.Now()))
@@ -375,7 +425,7 @@ func TestForumService_Integrated(t *testing.T) {
 				PostID:   1,
 			}
 
-			err := commentUC.CreateComment(context.Background(), comment)
+			err := commentUC.CreateComment(context.Background(), "valid_token", comment)
 			require.Error(t, err)
 		})
 
@@ -388,9 +438,9 @@ func TestForumService_Integrated(t *testing.T) {
 				},
 			}
 
-			postUC := usecase.NewPostUsecase(deps.postRepo, authClient, nil)
+			postUC := usecase.NewPostUsecase(deps.postRepo, authClient, verifier.NewRemote(authClient), nil)
 
-			_, err := postUC.CreatePost(context.Background(), "invalid_token", "Test", "Content")
+			_, err := postUC.CreatePost(context.Background(), "invalid_token", "Test", "Content", 0, nil)
 			require.Error(t, err)
 			assert.Contains(t, err.Error(), "invalid token")
 		})
@@ -407,21 +457,23 @@ func TestForumService_Integrated(t *testing.T) {
 				},
 			}
 
-			postUC := usecase.NewPostUsecase(deps.postRepo, authClient, nil)
+			postUC := usecase.NewPostUsecase(deps.postRepo, authClient, verifier.NewRemote(authClient), nil)
 
-			query := `UPDATE posts SET title = $1, content = $2 WHERE id = $3 AND (author_id = $4 OR $5 = 'admin') RETURNING id, title, content, author_id, created_at`
+			query := `UPDATE posts SET title = $1, content = $2, content_html = $3, category_id = COALESCE(NULLIF($4, 0), category_id) WHERE id = $5 AND deleted_at IS NULL AND (author_id = $6 OR $7 = 'admin') RETURNING id, title, content, content_html, author_id, category_id, created_at`
 
+			deps.mock.ExpectBegin()
 			deps.mock.ExpectQuery(query).
-				WithArgs("New Title", "New Content", int64(1), int64(2), "user").
+				WithArgs("New Title", "New Content", "<p>New Content</p>\n", int64(0), int64(1), int64(2), "user").
 				WillReturnError(repository.ErrPermissionDenied)
+			deps.mock.ExpectRollback()
 
-			_, err := postUC.UpdatePost(context.Background(), "valid_token", 1, "New Title", "New Content")
+			_, err := postUC.UpdatePost(context.Background(), "valid_token", 1, "New Title", "New Content", 0, nil)
 			require.Error(t, err)
 			assert.True(t, errors.Is(err, repository.ErrPermissionDenied))
 		})
 		t.Run("Get comments database error", func(t *testing.T) {
-			postQuery := `SELECT id, title, content, author_id, created_at FROM posts WHERE id = $1`
-			commentQuery := `SELECT id, content, author_id, post_id, author_name FROM comments WHERE post_id = $1 ORDER BY id DESC`
+			postQuery := `SELECT id, title, content, content_html, author_id, created_at, pinned_at IS NOT NULL AS pinned, locked_at IS NOT NULL AS locked FROM posts WHERE id = $1 AND deleted_at IS NULL AND hidden_at IS NULL`
+			commentQuery := `SELECT id, content, content_html, author_id, post_id, author_name, created_at, edited_at, deleted_at IS NOT NULL AS deleted, hidden_at IS NOT NULL AS hidden FROM comments WHERE post_id = $1 ORDER BY id DESC`
 
 			deps.mock.ExpectQuery(postQuery).
 				WithArgs(int64(1)).
@@ -437,8 +489,8 @@ func TestForumService_Integrated(t *testing.T) {
 		})
 
 		t.Run("Empty comments list", func(t *testing.T) {
-			postQuery := `SELECT id, title, content, author_id, created_at FROM posts WHERE id = $1`
-			commentQuery := `SELECT id, content, author_id, post_id, author_name FROM comments WHERE post_id = $1 ORDER BY id DESC`
+			postQuery := `SELECT id, title, content, content_html, author_id, created_at, pinned_at IS NOT NULL AS pinned, locked_at IS NOT NULL AS locked FROM posts WHERE id = $1 AND deleted_at IS NULL AND hidden_at IS NULL`
+			commentQuery := `SELECT id, content, content_html, author_id, post_id, author_name, created_at, edited_at, deleted_at IS NOT NULL AS deleted, hidden_at IS NOT NULL AS hidden FROM comments WHERE post_id = $1 ORDER BY id DESC`
 
 			deps.mock.ExpectQuery(postQuery).
 				WithArgs(int64(1)).
@@ -464,7 +516,7 @@ func TestPostHandler(t *testing.T) {
 
 	t.Run("CreatePost success", func(t *testing.T) {
 		mockUC := &mockPostUseCase{
-			createFunc: func(ctx context.Context, token, title, content string) (*entity.Post, error) {
+			createFunc: func(ctx context.Context, token, title, content string, categoryID int64) (*entity.Post, error) {
 				return &entity.Post{
 					ID:        1,
 					Title:     title,
@@ -591,7 +643,7 @@ func TestPostHandler(t *testing.T) {
 
 	t.Run("GetPosts database error", func(t *testing.T) {
 		mockUC := &mockPostUseCase{
-			getPostsFunc: func(ctx context.Context) ([]*entity.Post, map[int]string, error) {
+			getPostsFunc: func(ctx context.Context, filter entity.PostFilter) (*entity.PostPage, map[int]string, error) {
 				return nil, nil, errors.New("database error")
 			},
 		}
@@ -701,7 +753,7 @@ func TestPostHandler(t *testing.T) {
 			},
 		}
 
-		postUC := usecase.NewPostUsecase(nil, mockAuth, nil)
+		postUC := usecase.NewPostUsecase(nil, mockAuth, verifier.NewRemote(mockAuth), nil)
 		handler := handler.NewPostHandler(postUC, mockLogger)
 
 		router := gin.Default()
@@ -742,7 +794,7 @@ func TestPostHandler(t *testing.T) {
 			},
 		}
 
-		postUC := usecase.NewPostUsecase(nil, mockAuth, nil)
+		postUC := usecase.NewPostUsecase(nil, mockAuth, verifier.NewRemote(mockAuth), nil)
 		handler := handler.NewPostHandler(postUC, mockLogger)
 
 		router := gin.Default()
@@ -773,8 +825,8 @@ func TestPostHandler(t *testing.T) {
 	})
 	t.Run("GetPosts success", func(t *testing.T) {
 		mockUC := &mockPostUseCase{
-			getPostsFunc: func(ctx context.Context) ([]*entity.Post, map[int]string, error) {
-				return []*entity.Post{
+			getPostsFunc: func(ctx context.Context, filter entity.PostFilter) (*entity.PostPage, map[int]string, error) {
+				return &entity.PostPage{Posts: []*entity.Post{
 					{
 						ID:        1,
 						Title:     "Test Post",
@@ -782,7 +834,7 @@ func TestPostHandler(t *testing.T) {
 						AuthorID:  1,
 						CreatedAt: time.Now(),
 					},
-				}, map[int]string{1: "testuser"}, nil
+				}}, map[int]string{1: "testuser"}, nil
 			},
 		}
 
@@ -817,7 +869,7 @@ func TestCommentHandler(t *testing.T) {
 			},
 		}
 
-		commentUC := usecase.NewCommentUseCase(nil, nil, mockAuth)
+		commentUC := usecase.NewCommentUseCase(nil, nil, mockAuth, verifier.NewRemote(mockAuth))
 		handler := handler.NewCommentHandler(commentUC)
 
 		router := gin.Default()
@@ -905,7 +957,7 @@ func TestCommentHandler(t *testing.T) {
 			},
 		}
 
-		commentUC := usecase.NewCommentUseCase(mockUC, nil, mockAuth)
+		commentUC := usecase.NewCommentUseCase(mockUC, nil, mockAuth, verifier.NewRemote(mockAuth))
 		handler := handler.NewCommentHandler(commentUC)
 
 		router := gin.Default()
@@ -926,19 +978,20 @@ func TestCommentHandler(t *testing.T) {
 		deps := setupTest(t)
 		defer deps.db.Close()
 
-		postQuery := `SELECT id, title, content, author_id, created_at FROM posts WHERE id = $1`
-		commentQuery := `SELECT id, content, author_id, post_id, author_name FROM comments WHERE post_id = $1 ORDER BY id DESC`
+		postQuery := `SELECT id, title, content, content_html, author_id, created_at, pinned_at IS NOT NULL AS pinned, locked_at IS NOT NULL AS locked FROM posts WHERE id = $1 AND deleted_at IS NULL AND hidden_at IS NULL`
 
 		deps.mock.ExpectQuery(postQuery).
 			WithArgs(int64(1)).
 			WillReturnRows(sqlmock.NewRows([]string{"id", "title", "content", "author_id", "created_at"}).
 				AddRow(1, "Test Post", "Test Content", int64(1), time.Now()))
 
-		deps.mock.ExpectQuery(commentQuery).
-			WithArgs(int64(1)).
-			WillReturnError(errors.New("database error"))
-
-		handler := handler.NewCommentHandler(deps.commentUC)
+		mockUC := &mockCommentUseCase{
+			getCommentsFunc: func(ctx context.Context, postID int64) ([]entity.Comment, error) {
+				return nil, errors.New("database error")
+			},
+		}
+		commentUC := usecase.NewCommentUseCase(mockUC, deps.postRepo, &mockAuthClient{}, nil)
+		handler := handler.NewCommentHandler(commentUC)
 
 		router := gin.Default()
 		router.GET("/posts/:id/comments", handler.GetCommentsByPostID)
@@ -955,20 +1008,25 @@ func TestCommentHandler(t *testing.T) {
 		deps := setupTest(t)
 		defer deps.db.Close()
 
-		postQuery := `SELECT id, title, content, author_id, created_at FROM posts WHERE id = $1`
-		commentQuery := `SELECT id, content, author_id, post_id, author_name FROM comments WHERE post_id = $1 ORDER BY id DESC`
+		postQuery := `SELECT id, title, content, content_html, author_id, created_at, pinned_at IS NOT NULL AS pinned, locked_at IS NOT NULL AS locked FROM posts WHERE id = $1 AND deleted_at IS NULL AND hidden_at IS NULL`
 
 		deps.mock.ExpectQuery(postQuery).
 			WithArgs(int64(1)).
 			WillReturnRows(sqlmock.NewRows([]string{"id", "title", "content", "author_id", "created_at"}).
 				AddRow(1, "Test Post", "Test Content", int64(1), time.Now()))
 
-		deps.mock.ExpectQuery(commentQuery).
-			WithArgs(int64(1)).
-			WillReturnRows(sqlmock.NewRows([]string{"id", "content", "author_id", "post_id", "author_name"}).
-				AddRow(1, "Test Comment", int64(1), int64(1), "testuser"))
-
-		handler := handler.NewCommentHandler(deps.commentUC)
+		mockUC := &mockCommentUseCase{
+			getCommentsFunc: func(ctx context.Context, postID int64) ([]entity.Comment, error) {
+				return []entity.Comment{{ID: 1, Content: "Test Comment", AuthorID: 1, PostID: postID, AuthorName: "testuser"}}, nil
+			},
+		}
+		authClient := &mockAuthClient{
+			getUserFunc: func(ctx context.Context, req *pb.GetUserRequest, opts ...grpc.CallOption) (*pb.GetUserResponse, error) {
+				return &pb.GetUserResponse{User: &pb.User{Id: req.Id, Username: "testuser"}}, nil
+			},
+		}
+		commentUC := usecase.NewCommentUseCase(mockUC, deps.postRepo, authClient, nil)
+		handler := handler.NewCommentHandler(commentUC)
 
 		router := gin.Default()
 		router.GET("/posts/:id/comments", handler.GetCommentsByPostID)
@@ -989,25 +1047,25 @@ func TestCommentHandler(t *testing.T) {
 
 type mockPostUseCase struct {
 	usecase.PostUsecaseInterface
-	createFunc   func(context.Context, string, string, string) (*entity.Post, error)
-	getPostsFunc func(context.Context) ([]*entity.Post, map[int]string, error)
+	createFunc   func(context.Context, string, string, string, int64) (*entity.Post, error)
+	getPostsFunc func(context.Context, entity.PostFilter) (*entity.PostPage, map[int]string, error)
 	deleteFunc   func(context.Context, string, int64) error
 	updateFunc   func(context.Context, string, int64, string, string) (*entity.Post, error)
 }
 
-func (m *mockPostUseCase) CreatePost(ctx context.Context, token, title, content string) (*entity.Post, error) {
-	return m.createFunc(ctx, token, title, content)
+func (m *mockPostUseCase) CreatePost(ctx context.Context, token, title, content string, categoryID int64, tags []string) (*entity.Post, error) {
+	return m.createFunc(ctx, token, title, content, categoryID)
 }
 
-func (m *mockPostUseCase) GetPosts(ctx context.Context) ([]*entity.Post, map[int]string, error) {
-	return m.getPostsFunc(ctx)
+func (m *mockPostUseCase) GetPosts(ctx context.Context, filter entity.PostFilter) (*entity.PostPage, map[int]string, error) {
+	return m.getPostsFunc(ctx, filter)
 }
 
 func (m *mockPostUseCase) DeletePost(ctx context.Context, token string, postID int64) error {
 	return m.deleteFunc(ctx, token, postID)
 }
 
-func (m *mockPostUseCase) UpdatePost(ctx context.Context, token string, postID int64, title, content string) (*entity.Post, error) {
+func (m *mockPostUseCase) UpdatePost(ctx context.Context, token string, postID int64, title, content string, categoryID int64, tags []string) (*entity.Post, error) {
 	return m.updateFunc(ctx, token, postID, title, content)
 }
 
@@ -1018,13 +1076,29 @@ type mockCommentUseCase struct {
 	deleteFunc      func(context.Context, int64) error // Add this line
 }
 
-func (m *mockCommentUseCase) DeleteComment(ctx context.Context, commentID int64) error {
+func (m *mockCommentUseCase) DeleteComment(ctx context.Context, commentID, postID, authorID int64, role string) error {
 	if m.deleteFunc != nil {
 		return m.deleteFunc(ctx, commentID)
 	}
 	return nil
 }
 
+func (m *mockCommentUseCase) GetCommentByID(ctx context.Context, id int64) (*entity.Comment, error) {
+	return nil, repository.ErrCommentNotFound
+}
+
+func (m *mockCommentUseCase) GetCommentTree(ctx context.Context, filter entity.CommentTreeFilter) (*entity.CommentPage, error) {
+	comments, err := m.getCommentsFunc(ctx, filter.PostID)
+	if err != nil {
+		return nil, err
+	}
+	return &entity.CommentPage{Comments: comments}, nil
+}
+
+func (m *mockCommentUseCase) UpdateComment(ctx context.Context, commentID, postID, authorID int64, role, content, contentHTML string) (*entity.Comment, error) {
+	return &entity.Comment{ID: commentID, PostID: postID, AuthorID: authorID, Content: content, ContentHTML: contentHTML}, nil
+}
+
 func (m *mockCommentUseCase) CreateComment(ctx context.Context, comment *entity.Comment) error {
 	return m.createFunc(ctx, comment)
 }
@@ -0,0 +1,38 @@
+// Package blobstore keeps uploaded files outside the database, behind an
+// interface with a local-filesystem and an S3-compatible implementation.
+package blobstore
+
+import (
+	"context"
+	"io"
+	"regexp"
+
+	"backend.com/forum/apperrors"
+)
+
+var (
+	ErrNotFound   = apperrors.New(apperrors.ErrNotFound, "file not found")
+	ErrInvalidKey = apperrors.New(apperrors.ErrInvalidArgument, "invalid blob key")
+)
+
+// BlobStore stores immutable blobs under keys chosen by the caller. Keys
+// are slash-separated paths of lowercase letters, digits, dots, dashes and
+// underscores.
+type BlobStore interface {
+	// Put stores size bytes read from r under key, replacing any blob that
+	// was stored under it before.
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+	// Get opens the blob stored under key, or fails with ErrNotFound.
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	// Delete removes the blob stored under key. Deleting a missing blob is
+	// not an error.
+	Delete(ctx context.Context, key string) error
+}
+
+var keyPattern = regexp.MustCompile(`^[a-z0-9_-][a-z0-9._-]*(/[a-z0-9_-][a-z0-9._-]*)*$`)
+
+// validKey rejects keys that could escape the store, such as ".." segments
+// or absolute paths.
+func validKey(key string) bool {
+	return len(key) <= 512 && keyPattern.MatchString(key)
+}
@@ -0,0 +1,119 @@
+// Package loader batches and caches author lookups against auth-service for
+// the lifetime of a single request.
+package loader
+
+import (
+	"context"
+	"sync"
+
+	pb "backend.com/forum/proto"
+	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc"
+)
+
+// maxBatch matches the number of ids auth-service accepts in one GetUsers call.
+const maxBatch = 1000
+
+// UserLoader resolves user ids to usernames. Every id is fetched at most once
+// per loader; ids that were requested but not found are remembered as well so
+// that repeated lookups do not go back to auth-service.
+type UserLoader struct {
+	client pb.AuthServiceClient
+
+	mu    sync.Mutex
+	names map[int64]string
+	seen  map[int64]struct{}
+}
+
+func NewUserLoader(client pb.AuthServiceClient) *UserLoader {
+	return &UserLoader{
+		client: client,
+		names:  make(map[int64]string),
+		seen:   make(map[int64]struct{}),
+	}
+}
+
+// Names returns the usernames of the given users. Unknown users are absent
+// from the result. On error the names resolved so far are still returned.
+func (l *UserLoader) Names(ctx context.Context, ids []int64) (map[int64]string, error) {
+	l.mu.Lock()
+	var missing []int64
+	for _, id := range ids {
+		if _, ok := l.seen[id]; ok {
+			continue
+		}
+		l.seen[id] = struct{}{}
+		missing = append(missing, id)
+	}
+	l.mu.Unlock()
+
+	var fetchErr error
+	for start := 0; start < len(missing); start += maxBatch {
+		end := start + maxBatch
+		if end > len(missing) {
+			end = len(missing)
+		}
+		chunk := missing[start:end]
+
+		resp, err := l.client.GetUsers(ctx, &pb.GetUsersRequest{Ids: chunk})
+		if err != nil {
+			// Forget the failed ids so a later call can retry them.
+			l.mu.Lock()
+			for _, id := range missing[start:] {
+				delete(l.seen, id)
+			}
+			l.mu.Unlock()
+			fetchErr = err
+			break
+		}
+
+		l.mu.Lock()
+		for _, u := range resp.GetUsers() {
+			l.names[u.GetId()] = u.GetUsername()
+		}
+		l.mu.Unlock()
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	result := make(map[int64]string, len(ids))
+	for _, id := range ids {
+		if name, ok := l.names[id]; ok {
+			result[id] = name
+		}
+	}
+	return result, fetchErr
+}
+
+type ctxKey struct{}
+
+// WithUserLoader returns a copy of ctx carrying l.
+func WithUserLoader(ctx context.Context, l *UserLoader) context.Context {
+	return context.WithValue(ctx, ctxKey{}, l)
+}
+
+// FromContext returns the loader attached to ctx, or a fresh one backed by
+// client when the request has none.
+func FromContext(ctx context.Context, client pb.AuthServiceClient) *UserLoader {
+	if l, ok := ctx.Value(ctxKey{}).(*UserLoader); ok {
+		return l
+	}
+	return NewUserLoader(client)
+}
+
+// Middleware attaches a new UserLoader to every request so that all author
+// lookups made while serving it share one cache.
+func Middleware(client pb.AuthServiceClient) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := WithUserLoader(c.Request.Context(), NewUserLoader(client))
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
+
+// UnaryServerInterceptor is the gRPC counterpart of Middleware.
+func UnaryServerInterceptor(client pb.AuthServiceClient) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		return handler(WithUserLoader(ctx, NewUserLoader(client)), req)
+	}
+}
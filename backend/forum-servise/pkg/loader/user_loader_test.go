@@ -0,0 +1,125 @@
+package loader
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	pb "backend.com/forum/proto"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+)
+
+type fakeAuthClient struct {
+	pb.AuthServiceClient
+	batches [][]int64
+	err     error
+}
+
+func (f *fakeAuthClient) GetUsers(ctx context.Context, in *pb.GetUsersRequest, opts ...grpc.CallOption) (*pb.GetUsersResponse, error) {
+	f.batches = append(f.batches, in.Ids)
+	if f.err != nil {
+		return nil, f.err
+	}
+	users := make([]*pb.User, 0, len(in.Ids))
+	for _, id := range in.Ids {
+		// Odd ids exist, even ids do not.
+		if id%2 == 1 {
+			users = append(users, &pb.User{Id: id, Username: fmt.Sprintf("user%d", id)})
+		}
+	}
+	return &pb.GetUsersResponse{Users: users}, nil
+}
+
+func TestUserLoader_Names(t *testing.T) {
+	client := &fakeAuthClient{}
+	l := NewUserLoader(client)
+
+	names, err := l.Names(context.Background(), []int64{1, 2, 1, 3})
+	require.NoError(t, err)
+	assert.Equal(t, map[int64]string{1: "user1", 3: "user3"}, names)
+	assert.Equal(t, [][]int64{{1, 2, 3}}, client.batches)
+
+	// Known and known-missing ids are served from the cache.
+	names, err = l.Names(context.Background(), []int64{2, 3, 5})
+	require.NoError(t, err)
+	assert.Equal(t, map[int64]string{3: "user3", 5: "user5"}, names)
+	assert.Equal(t, [][]int64{{1, 2, 3}, {5}}, client.batches)
+}
+
+func TestUserLoader_Chunks(t *testing.T) {
+	client := &fakeAuthClient{}
+	l := NewUserLoader(client)
+
+	ids := make([]int64, maxBatch+10)
+	for i := range ids {
+		ids[i] = int64(i + 1)
+	}
+
+	names, err := l.Names(context.Background(), ids)
+	require.NoError(t, err)
+	assert.Len(t, names, len(ids)/2)
+	require.Len(t, client.batches, 2)
+	assert.Len(t, client.batches[0], maxBatch)
+	assert.Len(t, client.batches[1], 10)
+}
+
+func TestUserLoader_ErrorIsRetried(t *testing.T) {
+	client := &fakeAuthClient{err: errors.New("unavailable")}
+	l := NewUserLoader(client)
+
+	names, err := l.Names(context.Background(), []int64{1})
+	assert.Error(t, err)
+	assert.Empty(t, names)
+
+	client.err = nil
+	names, err = l.Names(context.Background(), []int64{1})
+	require.NoError(t, err)
+	assert.Equal(t, map[int64]string{1: "user1"}, names)
+	assert.Len(t, client.batches, 2)
+}
+
+func TestMiddleware_SharesLoaderPerRequest(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	client := &fakeAuthClient{}
+
+	router := gin.New()
+	router.Use(Middleware(client))
+	router.GET("/", func(c *gin.Context) {
+		ctx := c.Request.Context()
+		_, _ = FromContext(ctx, nil).Names(ctx, []int64{1})
+		_, _ = FromContext(ctx, nil).Names(ctx, []int64{1})
+		c.Status(http.StatusOK)
+	})
+
+	for i := 0; i < 2; i++ {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+		assert.Equal(t, http.StatusOK, w.Code)
+	}
+
+	// One fetch per request, none shared across requests.
+	assert.Len(t, client.batches, 2)
+}
+
+func TestUnaryServerInterceptor_SharesLoaderPerCall(t *testing.T) {
+	client := &fakeAuthClient{}
+	interceptor := UnaryServerInterceptor(client)
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		_, _ = FromContext(ctx, nil).Names(ctx, []int64{1})
+		_, _ = FromContext(ctx, nil).Names(ctx, []int64{1})
+		return nil, nil
+	}
+
+	for i := 0; i < 2; i++ {
+		_, err := interceptor(context.Background(), nil, &grpc.UnaryServerInfo{}, handler)
+		require.NoError(t, err)
+	}
+
+	assert.Len(t, client.batches, 2)
+}
@@ -0,0 +1,51 @@
+// Package markdown renders user-written Markdown to HTML that is safe to
+// embed in a page.
+package markdown
+
+import (
+	"bytes"
+	"regexp"
+
+	"backend.com/forum/apperrors"
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+)
+
+// MaxSourceLength bounds the size in bytes of a Markdown source, so that a
+// single post or preview cannot make rendering arbitrarily expensive.
+const MaxSourceLength = 64 << 10
+
+var ErrTooLong = apperrors.New(apperrors.ErrInvalidArgument, "content must be at most 64 KiB")
+
+// md is CommonMark with GFM tables. Raw HTML in the source is dropped by
+// goldmark itself; the policy below is the actual XSS barrier.
+var md = goldmark.New(
+	goldmark.WithExtensions(
+		extension.NewTable(extension.WithTableCellAlignMethod(extension.TableCellAlignAttribute)),
+	),
+)
+
+var policy = newPolicy()
+
+func newPolicy() *bluemonday.Policy {
+	p := bluemonday.UGCPolicy()
+	// Fenced code blocks keep their info string for client-side highlighting.
+	p.AllowAttrs("class").Matching(regexp.MustCompile(`^language-[\w+#.-]+$`)).OnElements("code")
+	p.AllowAttrs("align").Matching(regexp.MustCompile(`^(left|center|right)$`)).OnElements("th", "td")
+	p.AddTargetBlankToFullyQualifiedLinks(true)
+	return p
+}
+
+// Render converts source to sanitized HTML.
+func Render(source string) (string, error) {
+	if len(source) > MaxSourceLength {
+		return "", ErrTooLong
+	}
+
+	var buf bytes.Buffer
+	if err := md.Convert([]byte(source), &buf); err != nil {
+		return "", err
+	}
+	return policy.Sanitize(buf.String()), nil
+}
@@ -0,0 +1,74 @@
+package markdown
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRender(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		want   string
+	}{
+		{
+			name:   "Emphasis",
+			source: "*a* **b**",
+			want:   "<p><em>a</em> <strong>b</strong></p>\n",
+		},
+		{
+			name:   "Fenced code keeps language",
+			source: "```go\nfmt.Println(\"<b>\")\n```",
+			want:   "<pre><code class=\"language-go\">fmt.Println(&#34;&lt;b&gt;&#34;)\n</code></pre>\n",
+		},
+		{
+			name:   "Table",
+			source: "| a | b |\n|:--|--:|\n| 1 | 2 |",
+			want: "<table>\n<thead>\n<tr>\n<th align=\"left\">a</th>\n<th align=\"right\">b</th>\n</tr>\n</thead>\n" +
+				"<tbody>\n<tr>\n<td align=\"left\">1</td>\n<td align=\"right\">2</td>\n</tr>\n</tbody>\n</table>\n",
+		},
+		{
+			name:   "External link",
+			source: "[site](https://example.com)",
+			want:   "<p><a href=\"https://example.com\" rel=\"nofollow noopener\" target=\"_blank\">site</a></p>\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Render(tt.source)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestRender_Sanitizes(t *testing.T) {
+	sources := []string{
+		"<script>alert(1)</script>",
+		"<img src=x onerror=alert(1)>",
+		"[x](javascript:alert(1))",
+		"<a href=\"javascript:alert(1)\">x</a>",
+		"<div onclick=\"alert(1)\">x</div>",
+		"```\" onmouseover=\"alert(1)\n```",
+	}
+
+	for _, source := range sources {
+		got, err := Render(source)
+		require.NoError(t, err)
+		for _, bad := range []string{"<script", "onerror", "onclick", "onmouseover=", "javascript:"} {
+			assert.NotContains(t, got, bad, "source %q", source)
+		}
+	}
+}
+
+func TestRender_TooLong(t *testing.T) {
+	_, err := Render(strings.Repeat("a", MaxSourceLength+1))
+	assert.ErrorIs(t, err, ErrTooLong)
+
+	_, err = Render(strings.Repeat("a", MaxSourceLength))
+	assert.NoError(t, err)
+}
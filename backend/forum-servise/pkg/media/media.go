@@ -0,0 +1,110 @@
+// Package media recognises the kinds of files users may upload and makes
+// thumbnails of images.
+package media
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	_ "image/gif"
+	"image/jpeg"
+	_ "image/png"
+	"net/http"
+
+	"backend.com/forum/apperrors"
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp"
+)
+
+// ThumbnailType is the content type of every thumbnail.
+const ThumbnailType = "image/jpeg"
+
+// maxPixels bounds the decoded size of an image, so a small file declaring
+// huge dimensions cannot exhaust memory.
+const maxPixels = 50_000_000
+
+var (
+	ErrUnsupportedType = apperrors.New(apperrors.ErrInvalidArgument, "unsupported file type")
+	ErrImageTooLarge   = apperrors.New(apperrors.ErrInvalidArgument, "image dimensions are too large")
+)
+
+// allowed maps the sniffed type of each accepted kind of file to its
+// extension.
+var allowed = map[string]string{
+	"image/jpeg":                ".jpg",
+	"image/png":                 ".png",
+	"image/gif":                 ".gif",
+	"image/webp":                ".webp",
+	"application/pdf":           ".pdf",
+	"text/plain; charset=utf-8": ".txt",
+}
+
+// Sniff determines the type of a file from its content, ignoring whatever
+// type the client claimed. It fails with ErrUnsupportedType for files that
+// may not be uploaded.
+func Sniff(data []byte) (contentType, ext string, err error) {
+	contentType = http.DetectContentType(data)
+	ext, ok := allowed[contentType]
+	if !ok {
+		return "", "", ErrUnsupportedType
+	}
+	return contentType, ext, nil
+}
+
+// IsImage reports whether files of contentType get a thumbnail.
+func IsImage(contentType string) bool {
+	switch contentType {
+	case "image/jpeg", "image/png", "image/gif", "image/webp":
+		return true
+	}
+	return false
+}
+
+// Thumbnail is a JPEG preview of an image together with the dimensions of
+// the original.
+type Thumbnail struct {
+	Data   []byte
+	Width  int
+	Height int
+}
+
+// MakeThumbnail scales the image in data to fit into a maxSide square,
+// keeping its aspect ratio. Images smaller than that keep their size.
+// Transparent areas are flattened onto white.
+func MakeThumbnail(data []byte, maxSide int) (*Thumbnail, error) {
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrUnsupportedType
+	}
+	if cfg.Width <= 0 || cfg.Height <= 0 || cfg.Width*cfg.Height > maxPixels {
+		return nil, ErrImageTooLarge
+	}
+
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrUnsupportedType
+	}
+
+	w, h := fit(cfg.Width, cfg.Height, maxSide)
+	dst := image.NewRGBA(image.Rect(0, 0, w, h))
+	draw.Draw(dst, dst.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+	draw.CatmullRom.Scale(dst, dst.Bounds(), src, src.Bounds(), draw.Over, nil)
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, dst, &jpeg.Options{Quality: 80}); err != nil {
+		return nil, err
+	}
+	return &Thumbnail{Data: buf.Bytes(), Width: cfg.Width, Height: cfg.Height}, nil
+}
+
+// fit returns the size of a w×h image scaled down to fit into a maxSide
+// square.
+func fit(w, h, maxSide int) (int, int) {
+	if w <= maxSide && h <= maxSide {
+		return w, h
+	}
+	if w >= h {
+		return maxSide, max(1, h*maxSide/w)
+	}
+	return max(1, w*maxSide/h), maxSide
+}
@@ -0,0 +1,81 @@
+package media
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func encodePNG(t *testing.T, w, h int) []byte {
+	img := image.NewNRGBA(image.Rect(0, 0, w, h))
+	for x := 0; x < w; x++ {
+		img.Set(x, 0, color.NRGBA{R: 255, A: 255})
+	}
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, img))
+	return buf.Bytes()
+}
+
+func TestSniff(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+		want string
+		ext  string
+	}{
+		{"PNG", encodePNG(t, 2, 2), "image/png", ".png"},
+		{"PDF", []byte("%PDF-1.7\n..."), "application/pdf", ".pdf"},
+		{"Text", []byte("just some notes"), "text/plain; charset=utf-8", ".txt"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			contentType, ext, err := Sniff(tt.data)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, contentType)
+			assert.Equal(t, tt.ext, ext)
+		})
+	}
+
+	t.Run("HTML is rejected", func(t *testing.T) {
+		_, _, err := Sniff([]byte("<html><script>alert(1)</script></html>"))
+		assert.ErrorIs(t, err, ErrUnsupportedType)
+	})
+
+	t.Run("Executable is rejected", func(t *testing.T) {
+		_, _, err := Sniff([]byte("MZ\x90\x00\x03\x00\x00\x00"))
+		assert.ErrorIs(t, err, ErrUnsupportedType)
+	})
+}
+
+func TestMakeThumbnail(t *testing.T) {
+	t.Run("Scaled down", func(t *testing.T) {
+		thumb, err := MakeThumbnail(encodePNG(t, 800, 400), 200)
+		require.NoError(t, err)
+		assert.Equal(t, 800, thumb.Width)
+		assert.Equal(t, 400, thumb.Height)
+
+		img, err := jpeg.Decode(bytes.NewReader(thumb.Data))
+		require.NoError(t, err)
+		assert.Equal(t, image.Rect(0, 0, 200, 100), img.Bounds())
+	})
+
+	t.Run("Small image keeps its size", func(t *testing.T) {
+		thumb, err := MakeThumbnail(encodePNG(t, 30, 60), 200)
+		require.NoError(t, err)
+
+		img, err := jpeg.Decode(bytes.NewReader(thumb.Data))
+		require.NoError(t, err)
+		assert.Equal(t, image.Rect(0, 0, 30, 60), img.Bounds())
+	})
+
+	t.Run("Not an image", func(t *testing.T) {
+		_, err := MakeThumbnail([]byte("%PDF-1.7"), 200)
+		assert.ErrorIs(t, err, ErrUnsupportedType)
+	})
+}
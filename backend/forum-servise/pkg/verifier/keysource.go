@@ -0,0 +1,187 @@
+package verifier
+
+import (
+	"context"
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"sync"
+	"time"
+
+	"backend.com/forum/apperrors"
+	"github.com/Ulyana-kru00/forum-project/forum-servise/pkg/logger"
+)
+
+var ErrUnknownKey = apperrors.New(apperrors.ErrUnauthenticated, "unknown signing key")
+
+// minRefreshInterval limits how often an unknown kid may force a refetch,
+// so garbage tokens cannot be used to hammer auth-service.
+const minRefreshInterval = 30 * time.Second
+
+type publicKey struct {
+	alg string
+	key crypto.PublicKey
+}
+
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Alg string `json:"alg"`
+	Crv string `json:"crv"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	X   string `json:"x"`
+}
+
+// KeySource keeps a cached copy of the keys auth-service publishes at its
+// JWKS endpoint.
+type KeySource struct {
+	url    string
+	client *http.Client
+	logger *logger.Logger
+
+	mu        sync.RWMutex
+	keys      map[string]publicKey
+	lastFetch time.Time
+}
+
+func NewKeySource(url string, logger *logger.Logger) *KeySource {
+	return &KeySource{
+		url:    url,
+		client: &http.Client{Timeout: 5 * time.Second},
+		logger: logger,
+		keys:   make(map[string]publicKey),
+	}
+}
+
+// Run refreshes the key set every interval until ctx is cancelled. Failed
+// refreshes keep the previously fetched keys.
+func (s *KeySource) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.Refresh(ctx); err != nil && s.logger != nil {
+				s.logger.Warnw("Failed to refresh JWKS", "error", err)
+			}
+		}
+	}
+}
+
+// Refresh fetches the key set and replaces the cached keys.
+func (s *KeySource) Refresh(ctx context.Context) error {
+	s.mu.Lock()
+	s.lastFetch = time.Now()
+	s.mu.Unlock()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("jwks: unexpected status %d", resp.StatusCode)
+	}
+
+	var body struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return fmt.Errorf("jwks: %w", err)
+	}
+
+	keys := make(map[string]publicKey, len(body.Keys))
+	for _, k := range body.Keys {
+		pub, err := k.publicKey()
+		if err != nil {
+			if s.logger != nil {
+				s.logger.Warnw("Skipping unusable JWK", "kid", k.Kid, "error", err)
+			}
+			continue
+		}
+		keys[k.Kid] = publicKey{alg: k.Alg, key: pub}
+	}
+
+	s.mu.Lock()
+	s.keys = keys
+	s.mu.Unlock()
+	return nil
+}
+
+// Key returns the public key for kid. An unknown kid triggers a refetch,
+// since it usually means auth-service has rotated to a new key.
+func (s *KeySource) Key(ctx context.Context, kid string) (publicKey, error) {
+	s.mu.RLock()
+	key, ok := s.keys[kid]
+	stale := time.Since(s.lastFetch) >= minRefreshInterval
+	s.mu.RUnlock()
+
+	if ok {
+		return key, nil
+	}
+	if !stale {
+		return publicKey{}, ErrUnknownKey
+	}
+
+	if err := s.Refresh(ctx); err != nil {
+		if s.logger != nil {
+			s.logger.Warnw("Failed to refresh JWKS for unknown kid", "kid", kid, "error", err)
+		}
+		return publicKey{}, ErrUnknownKey
+	}
+
+	s.mu.RLock()
+	key, ok = s.keys[kid]
+	s.mu.RUnlock()
+	if !ok {
+		return publicKey{}, ErrUnknownKey
+	}
+	return key, nil
+}
+
+func (k jwk) publicKey() (crypto.PublicKey, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil {
+			return nil, err
+		}
+		return &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}, nil
+	case "OKP":
+		if k.Crv != "Ed25519" {
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil {
+			return nil, err
+		}
+		if len(x) != ed25519.PublicKeySize {
+			return nil, errors.New("invalid Ed25519 key size")
+		}
+		return ed25519.PublicKey(x), nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %q", k.Kty)
+	}
+}
@@ -0,0 +1,156 @@
+// Package verifier validates access tokens issued by auth-service without a
+// network round trip per request.
+package verifier
+
+import (
+	"context"
+	"crypto/sha256"
+	"sync"
+	"time"
+
+	"backend.com/forum/apperrors"
+	pb "backend.com/forum/proto"
+	"github.com/Ulyana-kru00/forum-project/forum-servise/pkg/logger"
+)
+
+var ErrTokenRevoked = apperrors.New(apperrors.ErrUnauthenticated, "token revoked")
+
+// TokenVerifier resolves a bearer token to the identity it was issued for.
+type TokenVerifier interface {
+	Verify(ctx context.Context, token string) (*Claims, error)
+}
+
+type Config struct {
+	// RevocationTTL is how long the outcome of a revocation check is reused.
+	// A logout becomes visible to forum-service within this window.
+	RevocationTTL time.Duration
+	// RevocationTimeout bounds the ValidateToken call to auth-service.
+	RevocationTimeout time.Duration
+}
+
+// Verifier checks signatures locally against the auth-service JWKS and
+// asks auth-service only whether the session behind a token was revoked.
+type Verifier struct {
+	keys       *KeySource
+	authClient pb.AuthServiceClient
+	cfg        Config
+	logger     *logger.Logger
+	now        func() time.Time
+
+	mu          sync.Mutex
+	revocations map[[sha256.Size]byte]revocationEntry
+}
+
+type revocationEntry struct {
+	revoked bool
+	expires time.Time
+}
+
+func New(keys *KeySource, authClient pb.AuthServiceClient, cfg Config, logger *logger.Logger) *Verifier {
+	return &Verifier{
+		keys:        keys,
+		authClient:  authClient,
+		cfg:         cfg,
+		logger:      logger,
+		now:         time.Now,
+		revocations: make(map[[sha256.Size]byte]revocationEntry),
+	}
+}
+
+func (v *Verifier) Verify(ctx context.Context, token string) (*Claims, error) {
+	claims, err := parseToken(ctx, v.keys, token, v.now())
+	if err != nil {
+		return nil, err
+	}
+
+	revoked, err := v.isRevoked(ctx, token, claims)
+	if err != nil {
+		return nil, err
+	}
+	if revoked {
+		return nil, ErrTokenRevoked
+	}
+	return claims, nil
+}
+
+func (v *Verifier) isRevoked(ctx context.Context, token string, claims *Claims) (bool, error) {
+	key := sha256.Sum256([]byte(token))
+	now := v.now()
+
+	v.mu.Lock()
+	entry, ok := v.revocations[key]
+	v.mu.Unlock()
+	if ok && now.Before(entry.expires) {
+		return entry.revoked, nil
+	}
+
+	callCtx, cancel := context.WithTimeout(ctx, v.cfg.RevocationTimeout)
+	defer cancel()
+
+	revoked := false
+	resp, err := v.authClient.ValidateToken(callCtx, &pb.ValidateTokenRequest{Token: token})
+	switch {
+	case err != nil:
+		if ctx.Err() != nil {
+			return false, ctx.Err()
+		}
+		// The signature is already verified, so an auth-service outage only
+		// delays revocation instead of rejecting every request.
+		if v.logger != nil {
+			v.logger.Warnw("Revocation check failed, accepting locally verified token",
+				"user_id", claims.UserID, "error", err)
+		}
+	case !resp.Valid:
+		revoked = true
+	}
+
+	expires := now.Add(v.cfg.RevocationTTL)
+	if claims.ExpiresAt.Before(expires) {
+		expires = claims.ExpiresAt
+	}
+
+	v.mu.Lock()
+	v.revocations[key] = revocationEntry{revoked: revoked, expires: expires}
+	v.evictExpired(now)
+	v.mu.Unlock()
+
+	return revoked, nil
+}
+
+// evictExpired drops stale entries once the cache grows, keeping memory
+// bounded by the number of tokens seen within one TTL. Callers hold mu.
+func (v *Verifier) evictExpired(now time.Time) {
+	if len(v.revocations) < 1024 {
+		return
+	}
+	for k, e := range v.revocations {
+		if !now.Before(e.expires) {
+			delete(v.revocations, k)
+		}
+	}
+}
+
+// Remote validates every token with a ValidateToken call to auth-service.
+// main falls back to it when -jwks-url is empty.
+type Remote struct {
+	authClient pb.AuthServiceClient
+}
+
+func NewRemote(authClient pb.AuthServiceClient) *Remote {
+	return &Remote{authClient: authClient}
+}
+
+func (r *Remote) Verify(ctx context.Context, token string) (*Claims, error) {
+	resp, err := r.authClient.ValidateToken(ctx, &pb.ValidateTokenRequest{Token: token})
+	if err != nil {
+		return nil, err
+	}
+	if resp == nil || !resp.Valid {
+		return nil, ErrInvalidToken
+	}
+	return &Claims{
+		UserID:   resp.UserId,
+		Username: resp.Username,
+		Role:     resp.Role,
+	}, nil
+}
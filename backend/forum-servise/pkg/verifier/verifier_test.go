@@ -0,0 +1,294 @@
+package verifier
+
+import (
+	"context"
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	pb "backend.com/forum/proto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+)
+
+type testKey struct {
+	kid     string
+	alg     string
+	signer  crypto.Signer
+	private bool
+}
+
+// jwksServer serves the public halves of keys and counts fetches.
+type jwksServer struct {
+	*httptest.Server
+	mu      sync.Mutex
+	keys    []testKey
+	fetches int32
+}
+
+func newJWKSServer(t *testing.T, keys ...testKey) *jwksServer {
+	s := &jwksServer{keys: keys}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&s.fetches, 1)
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		out := make([]jwk, 0, len(s.keys))
+		for _, k := range s.keys {
+			switch pub := k.signer.Public().(type) {
+			case *rsa.PublicKey:
+				out = append(out, jwk{
+					Kty: "RSA", Kid: k.kid, Alg: k.alg,
+					N: base64.RawURLEncoding.EncodeToString(pub.N.Bytes()),
+					E: base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes()),
+				})
+			case ed25519.PublicKey:
+				out = append(out, jwk{
+					Kty: "OKP", Kid: k.kid, Alg: k.alg, Crv: "Ed25519",
+					X: base64.RawURLEncoding.EncodeToString(pub),
+				})
+			}
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"keys": out})
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *jwksServer) setKeys(keys ...testKey) {
+	s.mu.Lock()
+	s.keys = keys
+	s.mu.Unlock()
+}
+
+func newEdKey(t *testing.T, kid string) testKey {
+	_, priv, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	return testKey{kid: kid, alg: "EdDSA", signer: priv}
+}
+
+func newRSAKey(t *testing.T, kid string) testKey {
+	priv, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	return testKey{kid: kid, alg: "RS256", signer: priv}
+}
+
+func signToken(t *testing.T, k testKey, alg string, claims map[string]interface{}) string {
+	enc := func(v interface{}) string {
+		b, err := json.Marshal(v)
+		require.NoError(t, err)
+		return base64.RawURLEncoding.EncodeToString(b)
+	}
+	signed := enc(map[string]string{"alg": alg, "kid": k.kid, "typ": "JWT"}) + "." + enc(claims)
+
+	var sig []byte
+	var err error
+	switch alg {
+	case "RS256":
+		digest := sha256.Sum256([]byte(signed))
+		sig, err = k.signer.Sign(rand.Reader, digest[:], crypto.SHA256)
+	default:
+		sig, err = k.signer.Sign(rand.Reader, []byte(signed), crypto.Hash(0))
+	}
+	require.NoError(t, err)
+	return signed + "." + base64.RawURLEncoding.EncodeToString(sig)
+}
+
+func validClaims() map[string]interface{} {
+	return map[string]interface{}{
+		"user_id":  42,
+		"username": "alice",
+		"role":     "user",
+		"exp":      time.Now().Add(time.Hour).Unix(),
+	}
+}
+
+type fakeAuthClient struct {
+	pb.AuthServiceClient
+	calls    int32
+	validate func(token string) (*pb.ValidateTokenResponse, error)
+}
+
+func (f *fakeAuthClient) ValidateToken(ctx context.Context, in *pb.ValidateTokenRequest, opts ...grpc.CallOption) (*pb.ValidateTokenResponse, error) {
+	atomic.AddInt32(&f.calls, 1)
+	return f.validate(in.Token)
+}
+
+func validAuth() *fakeAuthClient {
+	return &fakeAuthClient{validate: func(string) (*pb.ValidateTokenResponse, error) {
+		return &pb.ValidateTokenResponse{Valid: true}, nil
+	}}
+}
+
+func newTestVerifier(t *testing.T, srv *jwksServer, auth pb.AuthServiceClient) *Verifier {
+	keys := NewKeySource(srv.URL, nil)
+	require.NoError(t, keys.Refresh(context.Background()))
+	return New(keys, auth, Config{RevocationTTL: time.Minute, RevocationTimeout: time.Second}, nil)
+}
+
+func TestVerify(t *testing.T) {
+	ed := newEdKey(t, "ed")
+	rs := newRSAKey(t, "rs")
+	srv := newJWKSServer(t, ed, rs)
+
+	tests := []struct {
+		name    string
+		token   func() string
+		wantErr error
+	}{
+		{
+			name:  "EdDSA",
+			token: func() string { return signToken(t, ed, "EdDSA", validClaims()) },
+		},
+		{
+			name:  "RS256",
+			token: func() string { return signToken(t, rs, "RS256", validClaims()) },
+		},
+		{
+			name: "expired",
+			token: func() string {
+				c := validClaims()
+				c["exp"] = time.Now().Add(-time.Minute).Unix()
+				return signToken(t, ed, "EdDSA", c)
+			},
+			wantErr: ErrTokenExpired,
+		},
+		{
+			name: "tampered payload",
+			token: func() string {
+				parts := strings.Split(signToken(t, ed, "EdDSA", validClaims()), ".")
+				c := validClaims()
+				c["role"] = "admin"
+				payload, _ := json.Marshal(c)
+				parts[1] = base64.RawURLEncoding.EncodeToString(payload)
+				return strings.Join(parts, ".")
+			},
+			wantErr: ErrInvalidToken,
+		},
+		{
+			name:    "algorithm not matching key",
+			token:   func() string { return signToken(t, testKey{kid: "rs", signer: ed.signer}, "EdDSA", validClaims()) },
+			wantErr: ErrInvalidToken,
+		},
+		{
+			name:    "malformed",
+			token:   func() string { return "not-a-jwt" },
+			wantErr: ErrInvalidToken,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := newTestVerifier(t, srv, validAuth())
+
+			claims, err := v.Verify(context.Background(), tt.token())
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, int64(42), claims.UserID)
+			assert.Equal(t, "alice", claims.Username)
+			assert.Equal(t, "user", claims.Role)
+		})
+	}
+}
+
+func TestVerify_KeyRotation(t *testing.T) {
+	oldKey := newEdKey(t, "old")
+	newKey := newEdKey(t, "new")
+	srv := newJWKSServer(t, oldKey)
+	v := newTestVerifier(t, srv, validAuth())
+
+	srv.setKeys(oldKey, newKey)
+	// Pretend the last fetch is old enough that an unknown kid may refetch.
+	v.keys.lastFetch = time.Now().Add(-minRefreshInterval)
+
+	_, err := v.Verify(context.Background(), signToken(t, newKey, "EdDSA", validClaims()))
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&srv.fetches))
+
+	// Unknown kids right after a fetch do not hit the JWKS endpoint again.
+	_, err = v.Verify(context.Background(), signToken(t, newEdKey(t, "bogus"), "EdDSA", validClaims()))
+	assert.ErrorIs(t, err, ErrUnknownKey)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&srv.fetches))
+}
+
+func TestVerify_RevocationCache(t *testing.T) {
+	key := newEdKey(t, "k")
+	srv := newJWKSServer(t, key)
+	token := signToken(t, key, "EdDSA", validClaims())
+
+	t.Run("valid result is cached", func(t *testing.T) {
+		auth := validAuth()
+		v := newTestVerifier(t, srv, auth)
+
+		for i := 0; i < 3; i++ {
+			_, err := v.Verify(context.Background(), token)
+			require.NoError(t, err)
+		}
+		assert.Equal(t, int32(1), atomic.LoadInt32(&auth.calls))
+	})
+
+	t.Run("revoked token is rejected until TTL passes", func(t *testing.T) {
+		auth := &fakeAuthClient{validate: func(string) (*pb.ValidateTokenResponse, error) {
+			return &pb.ValidateTokenResponse{Valid: false}, nil
+		}}
+		v := newTestVerifier(t, srv, auth)
+		now := time.Now()
+		v.now = func() time.Time { return now }
+
+		_, err := v.Verify(context.Background(), token)
+		assert.ErrorIs(t, err, ErrTokenRevoked)
+		_, err = v.Verify(context.Background(), token)
+		assert.ErrorIs(t, err, ErrTokenRevoked)
+		assert.Equal(t, int32(1), atomic.LoadInt32(&auth.calls))
+
+		now = now.Add(2 * time.Minute)
+		_, err = v.Verify(context.Background(), token)
+		assert.ErrorIs(t, err, ErrTokenRevoked)
+		assert.Equal(t, int32(2), atomic.LoadInt32(&auth.calls))
+	})
+
+	t.Run("auth outage does not reject verified tokens", func(t *testing.T) {
+		auth := &fakeAuthClient{validate: func(string) (*pb.ValidateTokenResponse, error) {
+			return nil, errors.New("connection refused")
+		}}
+		v := newTestVerifier(t, srv, auth)
+
+		claims, err := v.Verify(context.Background(), token)
+		require.NoError(t, err)
+		assert.Equal(t, int64(42), claims.UserID)
+	})
+}
+
+func TestRemote_Verify(t *testing.T) {
+	auth := &fakeAuthClient{validate: func(token string) (*pb.ValidateTokenResponse, error) {
+		if token != "good" {
+			return &pb.ValidateTokenResponse{Valid: false}, nil
+		}
+		return &pb.ValidateTokenResponse{Valid: true, UserId: 7, Username: "bob", Role: "admin"}, nil
+	}}
+	r := NewRemote(auth)
+
+	claims, err := r.Verify(context.Background(), "good")
+	require.NoError(t, err)
+	assert.Equal(t, &Claims{UserID: 7, Username: "bob", Role: "admin"}, claims)
+
+	_, err = r.Verify(context.Background(), "bad")
+	assert.ErrorIs(t, err, ErrInvalidToken)
+}
@@ -24,13 +24,18 @@ const (
 
 // Модели данных
 type Category struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
-	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
-	Description   string                 `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
-	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	Id          int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name        string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Description string                 `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
+	CreatedAt   *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	Slug        string                 `protobuf:"bytes,5,opt,name=slug,proto3" json:"slug,omitempty"`
+	Position    int32                  `protobuf:"varint,6,opt,name=position,proto3" json:"position,omitempty"`
+	PostCount   int64                  `protobuf:"varint,7,opt,name=post_count,json=postCount,proto3" json:"post_count,omitempty"`
+	// Не задано, если в категории нет постов
+	LastActivityAt *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=last_activity_at,json=lastActivityAt,proto3" json:"last_activity_at,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
 }
 
 func (x *Category) Reset() {
@@ -91,78 +96,30 @@ func (x *Category) GetCreatedAt() *timestamppb.Timestamp {
 	return nil
 }
 
-type Topic struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
-	CategoryId    int64                  `protobuf:"varint,2,opt,name=category_id,json=categoryId,proto3" json:"category_id,omitempty"`
-	Title         string                 `protobuf:"bytes,3,opt,name=title,proto3" json:"title,omitempty"`
-	UserId        int64                  `protobuf:"varint,4,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
-	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
-}
-
-func (x *Topic) Reset() {
-	*x = Topic{}
-	mi := &file_forum_proto_msgTypes[1]
-	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-	ms.StoreMessageInfo(mi)
-}
-
-func (x *Topic) String() string {
-	return protoimpl.X.MessageStringOf(x)
-}
-
-func (*Topic) ProtoMessage() {}
-
-func (x *Topic) ProtoReflect() protoreflect.Message {
-	mi := &file_forum_proto_msgTypes[1]
+func (x *Category) GetSlug() string {
 	if x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
-		}
-		return ms
+		return x.Slug
 	}
-	return mi.MessageOf(x)
-}
-
-// Deprecated: Use Topic.ProtoReflect.Descriptor instead.
-func (*Topic) Descriptor() ([]byte, []int) {
-	return file_forum_proto_rawDescGZIP(), []int{1}
-}
-
-func (x *Topic) GetId() int64 {
-	if x != nil {
-		return x.Id
-	}
-	return 0
+	return ""
 }
 
-func (x *Topic) GetCategoryId() int64 {
+func (x *Category) GetPosition() int32 {
 	if x != nil {
-		return x.CategoryId
+		return x.Position
 	}
 	return 0
 }
 
-func (x *Topic) GetTitle() string {
-	if x != nil {
-		return x.Title
-	}
-	return ""
-}
-
-func (x *Topic) GetUserId() int64 {
+func (x *Category) GetPostCount() int64 {
 	if x != nil {
-		return x.UserId
+		return x.PostCount
 	}
 	return 0
 }
 
-func (x *Topic) GetCreatedAt() *timestamppb.Timestamp {
+func (x *Category) GetLastActivityAt() *timestamppb.Timestamp {
 	if x != nil {
-		return x.CreatedAt
+		return x.LastActivityAt
 	}
 	return nil
 }
@@ -176,7 +133,7 @@ type TokenRequest struct {
 
 func (x *TokenRequest) Reset() {
 	*x = TokenRequest{}
-	mi := &file_forum_proto_msgTypes[2]
+	mi := &file_forum_proto_msgTypes[1]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -188,7 +145,7 @@ func (x *TokenRequest) String() string {
 func (*TokenRequest) ProtoMessage() {}
 
 func (x *TokenRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_forum_proto_msgTypes[2]
+	mi := &file_forum_proto_msgTypes[1]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -201,7 +158,7 @@ func (x *TokenRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use TokenRequest.ProtoReflect.Descriptor instead.
 func (*TokenRequest) Descriptor() ([]byte, []int) {
-	return file_forum_proto_rawDescGZIP(), []int{2}
+	return file_forum_proto_rawDescGZIP(), []int{1}
 }
 
 func (x *TokenRequest) GetToken() string {
@@ -224,7 +181,7 @@ type Message struct {
 
 func (x *Message) Reset() {
 	*x = Message{}
-	mi := &file_forum_proto_msgTypes[3]
+	mi := &file_forum_proto_msgTypes[2]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -236,7 +193,7 @@ func (x *Message) String() string {
 func (*Message) ProtoMessage() {}
 
 func (x *Message) ProtoReflect() protoreflect.Message {
-	mi := &file_forum_proto_msgTypes[3]
+	mi := &file_forum_proto_msgTypes[2]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -249,7 +206,7 @@ func (x *Message) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Message.ProtoReflect.Descriptor instead.
 func (*Message) Descriptor() ([]byte, []int) {
-	return file_forum_proto_rawDescGZIP(), []int{3}
+	return file_forum_proto_rawDescGZIP(), []int{2}
 }
 
 func (x *Message) GetId() int64 {
@@ -288,19 +245,28 @@ func (x *Message) GetCreatedAt() *timestamppb.Timestamp {
 }
 
 type Post struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
-	Title         string                 `protobuf:"bytes,2,opt,name=title,proto3" json:"title,omitempty"`
-	Content       string                 `protobuf:"bytes,3,opt,name=content,proto3" json:"content,omitempty"`
-	AuthorId      int64                  `protobuf:"varint,4,opt,name=author_id,json=authorId,proto3" json:"author_id,omitempty"`
-	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Id             int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Title          string                 `protobuf:"bytes,2,opt,name=title,proto3" json:"title,omitempty"`
+	Content        string                 `protobuf:"bytes,3,opt,name=content,proto3" json:"content,omitempty"`
+	AuthorId       int64                  `protobuf:"varint,4,opt,name=author_id,json=authorId,proto3" json:"author_id,omitempty"`
+	CreatedAt      *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	CommentCount   int64                  `protobuf:"varint,6,opt,name=comment_count,json=commentCount,proto3" json:"comment_count,omitempty"`
+	LastActivityAt *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=last_activity_at,json=lastActivityAt,proto3" json:"last_activity_at,omitempty"`
+	CategoryId     int64                  `protobuf:"varint,8,opt,name=category_id,json=categoryId,proto3" json:"category_id,omitempty"`
+	// Заполняется только в ответах; "Unknown", если автор не найден
+	AuthorName string   `protobuf:"bytes,9,opt,name=author_name,json=authorName,proto3" json:"author_name,omitempty"`
+	Score      int64    `protobuf:"varint,10,opt,name=score,proto3" json:"score,omitempty"`
+	Tags       []string `protobuf:"bytes,11,rep,name=tags,proto3" json:"tags,omitempty"`
+	// Очищенный HTML, отрендеренный из Markdown в content
+	ContentHtml   string `protobuf:"bytes,12,opt,name=content_html,json=contentHtml,proto3" json:"content_html,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *Post) Reset() {
 	*x = Post{}
-	mi := &file_forum_proto_msgTypes[4]
+	mi := &file_forum_proto_msgTypes[3]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -312,7 +278,7 @@ func (x *Post) String() string {
 func (*Post) ProtoMessage() {}
 
 func (x *Post) ProtoReflect() protoreflect.Message {
-	mi := &file_forum_proto_msgTypes[4]
+	mi := &file_forum_proto_msgTypes[3]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -325,7 +291,7 @@ func (x *Post) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Post.ProtoReflect.Descriptor instead.
 func (*Post) Descriptor() ([]byte, []int) {
-	return file_forum_proto_rawDescGZIP(), []int{4}
+	return file_forum_proto_rawDescGZIP(), []int{3}
 }
 
 func (x *Post) GetId() int64 {
@@ -363,11 +329,60 @@ func (x *Post) GetCreatedAt() *timestamppb.Timestamp {
 	return nil
 }
 
+func (x *Post) GetCommentCount() int64 {
+	if x != nil {
+		return x.CommentCount
+	}
+	return 0
+}
+
+func (x *Post) GetLastActivityAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LastActivityAt
+	}
+	return nil
+}
+
+func (x *Post) GetCategoryId() int64 {
+	if x != nil {
+		return x.CategoryId
+	}
+	return 0
+}
+
+func (x *Post) GetAuthorName() string {
+	if x != nil {
+		return x.AuthorName
+	}
+	return ""
+}
+
+func (x *Post) GetScore() int64 {
+	if x != nil {
+		return x.Score
+	}
+	return 0
+}
+
+func (x *Post) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+func (x *Post) GetContentHtml() string {
+	if x != nil {
+		return x.ContentHtml
+	}
+	return ""
+}
+
 type ChatMessage struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
 	UserId        int64                  `protobuf:"varint,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
-	Username      string                 `protobuf:"bytes,3,opt,name=username,proto3" json:"username,omitempty"` // Добавьте это поле
+	Username      string                 `protobuf:"bytes,3,opt,name=username,proto3" json:"username,omitempty"`
 	Content       string                 `protobuf:"bytes,4,opt,name=content,proto3" json:"content,omitempty"`
 	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
 	unknownFields protoimpl.UnknownFields
@@ -376,7 +391,7 @@ type ChatMessage struct {
 
 func (x *ChatMessage) Reset() {
 	*x = ChatMessage{}
-	mi := &file_forum_proto_msgTypes[5]
+	mi := &file_forum_proto_msgTypes[4]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -388,7 +403,7 @@ func (x *ChatMessage) String() string {
 func (*ChatMessage) ProtoMessage() {}
 
 func (x *ChatMessage) ProtoReflect() protoreflect.Message {
-	mi := &file_forum_proto_msgTypes[5]
+	mi := &file_forum_proto_msgTypes[4]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -401,7 +416,7 @@ func (x *ChatMessage) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ChatMessage.ProtoReflect.Descriptor instead.
 func (*ChatMessage) Descriptor() ([]byte, []int) {
-	return file_forum_proto_rawDescGZIP(), []int{5}
+	return file_forum_proto_rawDescGZIP(), []int{4}
 }
 
 func (x *ChatMessage) GetId() int64 {
@@ -441,16 +456,19 @@ func (x *ChatMessage) GetCreatedAt() *timestamppb.Timestamp {
 
 // Запросы и ответы для категорий
 type CreateCategoryRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
-	Description   string                 `protobuf:"bytes,2,opt,name=description,proto3" json:"description,omitempty"`
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	Name        string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Description string                 `protobuf:"bytes,2,opt,name=description,proto3" json:"description,omitempty"`
+	// Если не задан, строится из названия
+	Slug          string `protobuf:"bytes,3,opt,name=slug,proto3" json:"slug,omitempty"`
+	Position      int32  `protobuf:"varint,4,opt,name=position,proto3" json:"position,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *CreateCategoryRequest) Reset() {
 	*x = CreateCategoryRequest{}
-	mi := &file_forum_proto_msgTypes[6]
+	mi := &file_forum_proto_msgTypes[5]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -462,7 +480,7 @@ func (x *CreateCategoryRequest) String() string {
 func (*CreateCategoryRequest) ProtoMessage() {}
 
 func (x *CreateCategoryRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_forum_proto_msgTypes[6]
+	mi := &file_forum_proto_msgTypes[5]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -475,7 +493,7 @@ func (x *CreateCategoryRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CreateCategoryRequest.ProtoReflect.Descriptor instead.
 func (*CreateCategoryRequest) Descriptor() ([]byte, []int) {
-	return file_forum_proto_rawDescGZIP(), []int{6}
+	return file_forum_proto_rawDescGZIP(), []int{5}
 }
 
 func (x *CreateCategoryRequest) GetName() string {
@@ -492,16 +510,31 @@ func (x *CreateCategoryRequest) GetDescription() string {
 	return ""
 }
 
+func (x *CreateCategoryRequest) GetSlug() string {
+	if x != nil {
+		return x.Slug
+	}
+	return ""
+}
+
+func (x *CreateCategoryRequest) GetPosition() int32 {
+	if x != nil {
+		return x.Position
+	}
+	return 0
+}
+
 type CreateCategoryResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Category      *Category              `protobuf:"bytes,2,opt,name=category,proto3" json:"category,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *CreateCategoryResponse) Reset() {
 	*x = CreateCategoryResponse{}
-	mi := &file_forum_proto_msgTypes[7]
+	mi := &file_forum_proto_msgTypes[6]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -513,7 +546,7 @@ func (x *CreateCategoryResponse) String() string {
 func (*CreateCategoryResponse) ProtoMessage() {}
 
 func (x *CreateCategoryResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_forum_proto_msgTypes[7]
+	mi := &file_forum_proto_msgTypes[6]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -526,7 +559,7 @@ func (x *CreateCategoryResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CreateCategoryResponse.ProtoReflect.Descriptor instead.
 func (*CreateCategoryResponse) Descriptor() ([]byte, []int) {
-	return file_forum_proto_rawDescGZIP(), []int{7}
+	return file_forum_proto_rawDescGZIP(), []int{6}
 }
 
 func (x *CreateCategoryResponse) GetId() int64 {
@@ -536,16 +569,25 @@ func (x *CreateCategoryResponse) GetId() int64 {
 	return 0
 }
 
+func (x *CreateCategoryResponse) GetCategory() *Category {
+	if x != nil {
+		return x.Category
+	}
+	return nil
+}
+
+// Поиск по id или, если id не задан, по slug
 type GetCategoryRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Slug          string                 `protobuf:"bytes,2,opt,name=slug,proto3" json:"slug,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *GetCategoryRequest) Reset() {
 	*x = GetCategoryRequest{}
-	mi := &file_forum_proto_msgTypes[8]
+	mi := &file_forum_proto_msgTypes[7]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -557,7 +599,7 @@ func (x *GetCategoryRequest) String() string {
 func (*GetCategoryRequest) ProtoMessage() {}
 
 func (x *GetCategoryRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_forum_proto_msgTypes[8]
+	mi := &file_forum_proto_msgTypes[7]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -570,7 +612,7 @@ func (x *GetCategoryRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetCategoryRequest.ProtoReflect.Descriptor instead.
 func (*GetCategoryRequest) Descriptor() ([]byte, []int) {
-	return file_forum_proto_rawDescGZIP(), []int{8}
+	return file_forum_proto_rawDescGZIP(), []int{7}
 }
 
 func (x *GetCategoryRequest) GetId() int64 {
@@ -580,6 +622,13 @@ func (x *GetCategoryRequest) GetId() int64 {
 	return 0
 }
 
+func (x *GetCategoryRequest) GetSlug() string {
+	if x != nil {
+		return x.Slug
+	}
+	return ""
+}
+
 type GetCategoryResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Category      *Category              `protobuf:"bytes,1,opt,name=category,proto3" json:"category,omitempty"`
@@ -589,7 +638,7 @@ type GetCategoryResponse struct {
 
 func (x *GetCategoryResponse) Reset() {
 	*x = GetCategoryResponse{}
-	mi := &file_forum_proto_msgTypes[9]
+	mi := &file_forum_proto_msgTypes[8]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -601,7 +650,7 @@ func (x *GetCategoryResponse) String() string {
 func (*GetCategoryResponse) ProtoMessage() {}
 
 func (x *GetCategoryResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_forum_proto_msgTypes[9]
+	mi := &file_forum_proto_msgTypes[8]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -614,7 +663,7 @@ func (x *GetCategoryResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetCategoryResponse.ProtoReflect.Descriptor instead.
 func (*GetCategoryResponse) Descriptor() ([]byte, []int) {
-	return file_forum_proto_rawDescGZIP(), []int{9}
+	return file_forum_proto_rawDescGZIP(), []int{8}
 }
 
 func (x *GetCategoryResponse) GetCategory() *Category {
@@ -624,31 +673,33 @@ func (x *GetCategoryResponse) GetCategory() *Category {
 	return nil
 }
 
-// Запросы и ответы для тем
-type CreateTopicRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	CategoryId    int64                  `protobuf:"varint,1,opt,name=category_id,json=categoryId,proto3" json:"category_id,omitempty"`
-	Title         string                 `protobuf:"bytes,2,opt,name=title,proto3" json:"title,omitempty"`
-	UserId        int64                  `protobuf:"varint,3,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+type UpdateCategoryRequest struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	Id          int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name        string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Description string                 `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
+	// Пустой slug оставляет текущий
+	Slug          string `protobuf:"bytes,4,opt,name=slug,proto3" json:"slug,omitempty"`
+	Position      int32  `protobuf:"varint,5,opt,name=position,proto3" json:"position,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *CreateTopicRequest) Reset() {
-	*x = CreateTopicRequest{}
-	mi := &file_forum_proto_msgTypes[10]
+func (x *UpdateCategoryRequest) Reset() {
+	*x = UpdateCategoryRequest{}
+	mi := &file_forum_proto_msgTypes[9]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *CreateTopicRequest) String() string {
+func (x *UpdateCategoryRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CreateTopicRequest) ProtoMessage() {}
+func (*UpdateCategoryRequest) ProtoMessage() {}
 
-func (x *CreateTopicRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_forum_proto_msgTypes[10]
+func (x *UpdateCategoryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_forum_proto_msgTypes[9]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -659,54 +710,68 @@ func (x *CreateTopicRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CreateTopicRequest.ProtoReflect.Descriptor instead.
-func (*CreateTopicRequest) Descriptor() ([]byte, []int) {
-	return file_forum_proto_rawDescGZIP(), []int{10}
+// Deprecated: Use UpdateCategoryRequest.ProtoReflect.Descriptor instead.
+func (*UpdateCategoryRequest) Descriptor() ([]byte, []int) {
+	return file_forum_proto_rawDescGZIP(), []int{9}
 }
 
-func (x *CreateTopicRequest) GetCategoryId() int64 {
+func (x *UpdateCategoryRequest) GetId() int64 {
 	if x != nil {
-		return x.CategoryId
+		return x.Id
 	}
 	return 0
 }
 
-func (x *CreateTopicRequest) GetTitle() string {
+func (x *UpdateCategoryRequest) GetName() string {
 	if x != nil {
-		return x.Title
+		return x.Name
 	}
 	return ""
 }
 
-func (x *CreateTopicRequest) GetUserId() int64 {
+func (x *UpdateCategoryRequest) GetDescription() string {
 	if x != nil {
-		return x.UserId
+		return x.Description
+	}
+	return ""
+}
+
+func (x *UpdateCategoryRequest) GetSlug() string {
+	if x != nil {
+		return x.Slug
+	}
+	return ""
+}
+
+func (x *UpdateCategoryRequest) GetPosition() int32 {
+	if x != nil {
+		return x.Position
 	}
 	return 0
 }
 
-type CreateTopicResponse struct {
+type UpdateCategoryResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Category      *Category              `protobuf:"bytes,1,opt,name=category,proto3" json:"category,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *CreateTopicResponse) Reset() {
-	*x = CreateTopicResponse{}
-	mi := &file_forum_proto_msgTypes[11]
+func (x *UpdateCategoryResponse) Reset() {
+	*x = UpdateCategoryResponse{}
+	mi := &file_forum_proto_msgTypes[10]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *CreateTopicResponse) String() string {
+func (x *UpdateCategoryResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CreateTopicResponse) ProtoMessage() {}
+func (*UpdateCategoryResponse) ProtoMessage() {}
 
-func (x *CreateTopicResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_forum_proto_msgTypes[11]
+func (x *UpdateCategoryResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_forum_proto_msgTypes[10]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -717,40 +782,39 @@ func (x *CreateTopicResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CreateTopicResponse.ProtoReflect.Descriptor instead.
-func (*CreateTopicResponse) Descriptor() ([]byte, []int) {
-	return file_forum_proto_rawDescGZIP(), []int{11}
+// Deprecated: Use UpdateCategoryResponse.ProtoReflect.Descriptor instead.
+func (*UpdateCategoryResponse) Descriptor() ([]byte, []int) {
+	return file_forum_proto_rawDescGZIP(), []int{10}
 }
 
-func (x *CreateTopicResponse) GetId() int64 {
+func (x *UpdateCategoryResponse) GetCategory() *Category {
 	if x != nil {
-		return x.Id
+		return x.Category
 	}
-	return 0
+	return nil
 }
 
-type GetTopicRequest struct {
+type ListCategoriesRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetTopicRequest) Reset() {
-	*x = GetTopicRequest{}
-	mi := &file_forum_proto_msgTypes[12]
+func (x *ListCategoriesRequest) Reset() {
+	*x = ListCategoriesRequest{}
+	mi := &file_forum_proto_msgTypes[11]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetTopicRequest) String() string {
+func (x *ListCategoriesRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetTopicRequest) ProtoMessage() {}
+func (*ListCategoriesRequest) ProtoMessage() {}
 
-func (x *GetTopicRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_forum_proto_msgTypes[12]
+func (x *ListCategoriesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_forum_proto_msgTypes[11]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -761,40 +825,33 @@ func (x *GetTopicRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetTopicRequest.ProtoReflect.Descriptor instead.
-func (*GetTopicRequest) Descriptor() ([]byte, []int) {
-	return file_forum_proto_rawDescGZIP(), []int{12}
-}
-
-func (x *GetTopicRequest) GetId() int64 {
-	if x != nil {
-		return x.Id
-	}
-	return 0
+// Deprecated: Use ListCategoriesRequest.ProtoReflect.Descriptor instead.
+func (*ListCategoriesRequest) Descriptor() ([]byte, []int) {
+	return file_forum_proto_rawDescGZIP(), []int{11}
 }
 
-type GetTopicResponse struct {
+type ListCategoriesResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Topic         *Topic                 `protobuf:"bytes,1,opt,name=topic,proto3" json:"topic,omitempty"`
+	Categories    []*Category            `protobuf:"bytes,1,rep,name=categories,proto3" json:"categories,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetTopicResponse) Reset() {
-	*x = GetTopicResponse{}
-	mi := &file_forum_proto_msgTypes[13]
+func (x *ListCategoriesResponse) Reset() {
+	*x = ListCategoriesResponse{}
+	mi := &file_forum_proto_msgTypes[12]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetTopicResponse) String() string {
+func (x *ListCategoriesResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetTopicResponse) ProtoMessage() {}
+func (*ListCategoriesResponse) ProtoMessage() {}
 
-func (x *GetTopicResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_forum_proto_msgTypes[13]
+func (x *ListCategoriesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_forum_proto_msgTypes[12]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -805,14 +862,14 @@ func (x *GetTopicResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetTopicResponse.ProtoReflect.Descriptor instead.
-func (*GetTopicResponse) Descriptor() ([]byte, []int) {
-	return file_forum_proto_rawDescGZIP(), []int{13}
+// Deprecated: Use ListCategoriesResponse.ProtoReflect.Descriptor instead.
+func (*ListCategoriesResponse) Descriptor() ([]byte, []int) {
+	return file_forum_proto_rawDescGZIP(), []int{12}
 }
 
-func (x *GetTopicResponse) GetTopic() *Topic {
+func (x *ListCategoriesResponse) GetCategories() []*Category {
 	if x != nil {
-		return x.Topic
+		return x.Categories
 	}
 	return nil
 }
@@ -829,7 +886,7 @@ type CreateMessageRequest struct {
 
 func (x *CreateMessageRequest) Reset() {
 	*x = CreateMessageRequest{}
-	mi := &file_forum_proto_msgTypes[14]
+	mi := &file_forum_proto_msgTypes[13]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -841,7 +898,7 @@ func (x *CreateMessageRequest) String() string {
 func (*CreateMessageRequest) ProtoMessage() {}
 
 func (x *CreateMessageRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_forum_proto_msgTypes[14]
+	mi := &file_forum_proto_msgTypes[13]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -854,7 +911,7 @@ func (x *CreateMessageRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CreateMessageRequest.ProtoReflect.Descriptor instead.
 func (*CreateMessageRequest) Descriptor() ([]byte, []int) {
-	return file_forum_proto_rawDescGZIP(), []int{14}
+	return file_forum_proto_rawDescGZIP(), []int{13}
 }
 
 func (x *CreateMessageRequest) GetTopicId() int64 {
@@ -887,7 +944,7 @@ type CreateMessageResponse struct {
 
 func (x *CreateMessageResponse) Reset() {
 	*x = CreateMessageResponse{}
-	mi := &file_forum_proto_msgTypes[15]
+	mi := &file_forum_proto_msgTypes[14]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -899,7 +956,7 @@ func (x *CreateMessageResponse) String() string {
 func (*CreateMessageResponse) ProtoMessage() {}
 
 func (x *CreateMessageResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_forum_proto_msgTypes[15]
+	mi := &file_forum_proto_msgTypes[14]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -912,7 +969,7 @@ func (x *CreateMessageResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CreateMessageResponse.ProtoReflect.Descriptor instead.
 func (*CreateMessageResponse) Descriptor() ([]byte, []int) {
-	return file_forum_proto_rawDescGZIP(), []int{15}
+	return file_forum_proto_rawDescGZIP(), []int{14}
 }
 
 func (x *CreateMessageResponse) GetId() int64 {
@@ -931,7 +988,7 @@ type GetMessageRequest struct {
 
 func (x *GetMessageRequest) Reset() {
 	*x = GetMessageRequest{}
-	mi := &file_forum_proto_msgTypes[16]
+	mi := &file_forum_proto_msgTypes[15]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -943,7 +1000,7 @@ func (x *GetMessageRequest) String() string {
 func (*GetMessageRequest) ProtoMessage() {}
 
 func (x *GetMessageRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_forum_proto_msgTypes[16]
+	mi := &file_forum_proto_msgTypes[15]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -956,7 +1013,7 @@ func (x *GetMessageRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetMessageRequest.ProtoReflect.Descriptor instead.
 func (*GetMessageRequest) Descriptor() ([]byte, []int) {
-	return file_forum_proto_rawDescGZIP(), []int{16}
+	return file_forum_proto_rawDescGZIP(), []int{15}
 }
 
 func (x *GetMessageRequest) GetId() int64 {
@@ -975,7 +1032,7 @@ type GetMessageResponse struct {
 
 func (x *GetMessageResponse) Reset() {
 	*x = GetMessageResponse{}
-	mi := &file_forum_proto_msgTypes[17]
+	mi := &file_forum_proto_msgTypes[16]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -987,7 +1044,7 @@ func (x *GetMessageResponse) String() string {
 func (*GetMessageResponse) ProtoMessage() {}
 
 func (x *GetMessageResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_forum_proto_msgTypes[17]
+	mi := &file_forum_proto_msgTypes[16]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1000,7 +1057,7 @@ func (x *GetMessageResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetMessageResponse.ProtoReflect.Descriptor instead.
 func (*GetMessageResponse) Descriptor() ([]byte, []int) {
-	return file_forum_proto_rawDescGZIP(), []int{17}
+	return file_forum_proto_rawDescGZIP(), []int{16}
 }
 
 func (x *GetMessageResponse) GetMessage() *Message {
@@ -1012,17 +1069,22 @@ func (x *GetMessageResponse) GetMessage() *Message {
 
 // Запросы и ответы для постов
 type CreatePostRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Title         string                 `protobuf:"bytes,1,opt,name=title,proto3" json:"title,omitempty"`
-	Content       string                 `protobuf:"bytes,2,opt,name=content,proto3" json:"content,omitempty"`
-	AuthorId      int64                  `protobuf:"varint,3,opt,name=author_id,json=authorId,proto3" json:"author_id,omitempty"`
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Title   string                 `protobuf:"bytes,1,opt,name=title,proto3" json:"title,omitempty"`
+	Content string                 `protobuf:"bytes,2,opt,name=content,proto3" json:"content,omitempty"`
+	// Игнорируется: автор берется из токена в метаданных authorization
+	AuthorId int64 `protobuf:"varint,3,opt,name=author_id,json=authorId,proto3" json:"author_id,omitempty"`
+	// 0 - первая категория
+	CategoryId int64 `protobuf:"varint,4,opt,name=category_id,json=categoryId,proto3" json:"category_id,omitempty"`
+	// Не больше 5, приводятся к нижнему регистру
+	Tags          []string `protobuf:"bytes,5,rep,name=tags,proto3" json:"tags,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *CreatePostRequest) Reset() {
 	*x = CreatePostRequest{}
-	mi := &file_forum_proto_msgTypes[18]
+	mi := &file_forum_proto_msgTypes[17]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1034,7 +1096,7 @@ func (x *CreatePostRequest) String() string {
 func (*CreatePostRequest) ProtoMessage() {}
 
 func (x *CreatePostRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_forum_proto_msgTypes[18]
+	mi := &file_forum_proto_msgTypes[17]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1047,7 +1109,7 @@ func (x *CreatePostRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CreatePostRequest.ProtoReflect.Descriptor instead.
 func (*CreatePostRequest) Descriptor() ([]byte, []int) {
-	return file_forum_proto_rawDescGZIP(), []int{18}
+	return file_forum_proto_rawDescGZIP(), []int{17}
 }
 
 func (x *CreatePostRequest) GetTitle() string {
@@ -1071,6 +1133,20 @@ func (x *CreatePostRequest) GetAuthorId() int64 {
 	return 0
 }
 
+func (x *CreatePostRequest) GetCategoryId() int64 {
+	if x != nil {
+		return x.CategoryId
+	}
+	return 0
+}
+
+func (x *CreatePostRequest) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
 type CreatePostResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
@@ -1081,7 +1157,7 @@ type CreatePostResponse struct {
 
 func (x *CreatePostResponse) Reset() {
 	*x = CreatePostResponse{}
-	mi := &file_forum_proto_msgTypes[19]
+	mi := &file_forum_proto_msgTypes[18]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1093,7 +1169,7 @@ func (x *CreatePostResponse) String() string {
 func (*CreatePostResponse) ProtoMessage() {}
 
 func (x *CreatePostResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_forum_proto_msgTypes[19]
+	mi := &file_forum_proto_msgTypes[18]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1106,7 +1182,7 @@ func (x *CreatePostResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CreatePostResponse.ProtoReflect.Descriptor instead.
 func (*CreatePostResponse) Descriptor() ([]byte, []int) {
-	return file_forum_proto_rawDescGZIP(), []int{19}
+	return file_forum_proto_rawDescGZIP(), []int{18}
 }
 
 func (x *CreatePostResponse) GetId() int64 {
@@ -1123,18 +1199,30 @@ func (x *CreatePostResponse) GetPost() *Post {
 	return nil
 }
 
+// Keyset-пагинация: следующая страница запрашивается с next_cursor из ответа.
 type GetPostsRequest struct {
-	state protoimpl.MessageState `protogen:"open.v1"`
-	// Можно добавить параметры пагинации или фильтрации
-	Limit         int32 `protobuf:"varint,1,opt,name=limit,proto3" json:"limit,omitempty"`
-	Offset        int32 `protobuf:"varint,2,opt,name=offset,proto3" json:"offset,omitempty"`
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Limit  int32                  `protobuf:"varint,1,opt,name=limit,proto3" json:"limit,omitempty"`
+	Cursor string                 `protobuf:"bytes,3,opt,name=cursor,proto3" json:"cursor,omitempty"`
+	// newest (по умолчанию), oldest, most_commented, recently_active, top, hot
+	Sort       string                 `protobuf:"bytes,4,opt,name=sort,proto3" json:"sort,omitempty"`
+	AuthorId   int64                  `protobuf:"varint,5,opt,name=author_id,json=authorId,proto3" json:"author_id,omitempty"`
+	From       *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=from,proto3" json:"from,omitempty"`
+	To         *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=to,proto3" json:"to,omitempty"`
+	Query      string                 `protobuf:"bytes,8,opt,name=query,proto3" json:"query,omitempty"`
+	CategoryId int64                  `protobuf:"varint,9,opt,name=category_id,json=categoryId,proto3" json:"category_id,omitempty"`
+	// Только для sort=top: day, week, month, year, all (по умолчанию)
+	Window string   `protobuf:"bytes,10,opt,name=window,proto3" json:"window,omitempty"`
+	Tags   []string `protobuf:"bytes,11,rep,name=tags,proto3" json:"tags,omitempty"`
+	// all (по умолчанию) - посты со всеми тегами, any - хотя бы с одним
+	TagMatch      string `protobuf:"bytes,12,opt,name=tag_match,json=tagMatch,proto3" json:"tag_match,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *GetPostsRequest) Reset() {
 	*x = GetPostsRequest{}
-	mi := &file_forum_proto_msgTypes[20]
+	mi := &file_forum_proto_msgTypes[19]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1146,7 +1234,7 @@ func (x *GetPostsRequest) String() string {
 func (*GetPostsRequest) ProtoMessage() {}
 
 func (x *GetPostsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_forum_proto_msgTypes[20]
+	mi := &file_forum_proto_msgTypes[19]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1159,7 +1247,7 @@ func (x *GetPostsRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetPostsRequest.ProtoReflect.Descriptor instead.
 func (*GetPostsRequest) Descriptor() ([]byte, []int) {
-	return file_forum_proto_rawDescGZIP(), []int{20}
+	return file_forum_proto_rawDescGZIP(), []int{19}
 }
 
 func (x *GetPostsRequest) GetLimit() int32 {
@@ -1169,23 +1257,87 @@ func (x *GetPostsRequest) GetLimit() int32 {
 	return 0
 }
 
-func (x *GetPostsRequest) GetOffset() int32 {
+func (x *GetPostsRequest) GetCursor() string {
+	if x != nil {
+		return x.Cursor
+	}
+	return ""
+}
+
+func (x *GetPostsRequest) GetSort() string {
 	if x != nil {
-		return x.Offset
+		return x.Sort
+	}
+	return ""
+}
+
+func (x *GetPostsRequest) GetAuthorId() int64 {
+	if x != nil {
+		return x.AuthorId
 	}
 	return 0
 }
 
+func (x *GetPostsRequest) GetFrom() *timestamppb.Timestamp {
+	if x != nil {
+		return x.From
+	}
+	return nil
+}
+
+func (x *GetPostsRequest) GetTo() *timestamppb.Timestamp {
+	if x != nil {
+		return x.To
+	}
+	return nil
+}
+
+func (x *GetPostsRequest) GetQuery() string {
+	if x != nil {
+		return x.Query
+	}
+	return ""
+}
+
+func (x *GetPostsRequest) GetCategoryId() int64 {
+	if x != nil {
+		return x.CategoryId
+	}
+	return 0
+}
+
+func (x *GetPostsRequest) GetWindow() string {
+	if x != nil {
+		return x.Window
+	}
+	return ""
+}
+
+func (x *GetPostsRequest) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+func (x *GetPostsRequest) GetTagMatch() string {
+	if x != nil {
+		return x.TagMatch
+	}
+	return ""
+}
+
 type GetPostsResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Posts         []*Post                `protobuf:"bytes,1,rep,name=posts,proto3" json:"posts,omitempty"`
+	NextCursor    string                 `protobuf:"bytes,2,opt,name=next_cursor,json=nextCursor,proto3" json:"next_cursor,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *GetPostsResponse) Reset() {
 	*x = GetPostsResponse{}
-	mi := &file_forum_proto_msgTypes[21]
+	mi := &file_forum_proto_msgTypes[20]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1197,7 +1349,7 @@ func (x *GetPostsResponse) String() string {
 func (*GetPostsResponse) ProtoMessage() {}
 
 func (x *GetPostsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_forum_proto_msgTypes[21]
+	mi := &file_forum_proto_msgTypes[20]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1210,7 +1362,7 @@ func (x *GetPostsResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetPostsResponse.ProtoReflect.Descriptor instead.
 func (*GetPostsResponse) Descriptor() ([]byte, []int) {
-	return file_forum_proto_rawDescGZIP(), []int{21}
+	return file_forum_proto_rawDescGZIP(), []int{20}
 }
 
 func (x *GetPostsResponse) GetPosts() []*Post {
@@ -1220,6 +1372,13 @@ func (x *GetPostsResponse) GetPosts() []*Post {
 	return nil
 }
 
+func (x *GetPostsResponse) GetNextCursor() string {
+	if x != nil {
+		return x.NextCursor
+	}
+	return ""
+}
+
 // Запросы и ответы для чата
 type CreateChatMessageRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
@@ -1231,7 +1390,7 @@ type CreateChatMessageRequest struct {
 
 func (x *CreateChatMessageRequest) Reset() {
 	*x = CreateChatMessageRequest{}
-	mi := &file_forum_proto_msgTypes[22]
+	mi := &file_forum_proto_msgTypes[21]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1243,7 +1402,7 @@ func (x *CreateChatMessageRequest) String() string {
 func (*CreateChatMessageRequest) ProtoMessage() {}
 
 func (x *CreateChatMessageRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_forum_proto_msgTypes[22]
+	mi := &file_forum_proto_msgTypes[21]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1256,7 +1415,7 @@ func (x *CreateChatMessageRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CreateChatMessageRequest.ProtoReflect.Descriptor instead.
 func (*CreateChatMessageRequest) Descriptor() ([]byte, []int) {
-	return file_forum_proto_rawDescGZIP(), []int{22}
+	return file_forum_proto_rawDescGZIP(), []int{21}
 }
 
 func (x *CreateChatMessageRequest) GetUserId() int64 {
@@ -1282,7 +1441,7 @@ type CreateChatMessageResponse struct {
 
 func (x *CreateChatMessageResponse) Reset() {
 	*x = CreateChatMessageResponse{}
-	mi := &file_forum_proto_msgTypes[23]
+	mi := &file_forum_proto_msgTypes[22]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1294,7 +1453,7 @@ func (x *CreateChatMessageResponse) String() string {
 func (*CreateChatMessageResponse) ProtoMessage() {}
 
 func (x *CreateChatMessageResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_forum_proto_msgTypes[23]
+	mi := &file_forum_proto_msgTypes[22]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1307,7 +1466,7 @@ func (x *CreateChatMessageResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CreateChatMessageResponse.ProtoReflect.Descriptor instead.
 func (*CreateChatMessageResponse) Descriptor() ([]byte, []int) {
-	return file_forum_proto_rawDescGZIP(), []int{23}
+	return file_forum_proto_rawDescGZIP(), []int{22}
 }
 
 func (x *CreateChatMessageResponse) GetId() int64 {
@@ -1325,7 +1484,7 @@ type StreamChatMessagesRequest struct {
 
 func (x *StreamChatMessagesRequest) Reset() {
 	*x = StreamChatMessagesRequest{}
-	mi := &file_forum_proto_msgTypes[24]
+	mi := &file_forum_proto_msgTypes[23]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1337,7 +1496,7 @@ func (x *StreamChatMessagesRequest) String() string {
 func (*StreamChatMessagesRequest) ProtoMessage() {}
 
 func (x *StreamChatMessagesRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_forum_proto_msgTypes[24]
+	mi := &file_forum_proto_msgTypes[23]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1350,28 +1509,25 @@ func (x *StreamChatMessagesRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use StreamChatMessagesRequest.ProtoReflect.Descriptor instead.
 func (*StreamChatMessagesRequest) Descriptor() ([]byte, []int) {
-	return file_forum_proto_rawDescGZIP(), []int{24}
+	return file_forum_proto_rawDescGZIP(), []int{23}
 }
 
 var File_forum_proto protoreflect.FileDescriptor
 
 const file_forum_proto_rawDesc = "" +
 	"\n" +
-	"\vforum.proto\x12\x05forum\x1a\x1fgoogle/protobuf/timestamp.proto\"\x8b\x01\n" +
+	"\vforum.proto\x12\x05forum\x1a\x1fgoogle/protobuf/timestamp.proto\"\xa0\x02\n" +
 	"\bCategory\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x12\n" +
 	"\x04name\x18\x02 \x01(\tR\x04name\x12 \n" +
 	"\vdescription\x18\x03 \x01(\tR\vdescription\x129\n" +
 	"\n" +
-	"created_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\"\xa2\x01\n" +
-	"\x05Topic\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x1f\n" +
-	"\vcategory_id\x18\x02 \x01(\x03R\n" +
-	"categoryId\x12\x14\n" +
-	"\x05title\x18\x03 \x01(\tR\x05title\x12\x17\n" +
-	"\auser_id\x18\x04 \x01(\x03R\x06userId\x129\n" +
+	"created_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x12\x12\n" +
+	"\x04slug\x18\x05 \x01(\tR\x04slug\x12\x1a\n" +
+	"\bposition\x18\x06 \x01(\x05R\bposition\x12\x1d\n" +
 	"\n" +
-	"created_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\"$\n" +
+	"post_count\x18\a \x01(\x03R\tpostCount\x12D\n" +
+	"\x10last_activity_at\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\x0elastActivityAt\"$\n" +
 	"\fTokenRequest\x12\x14\n" +
 	"\x05token\x18\x01 \x01(\tR\x05token\"\xa2\x01\n" +
 	"\aMessage\x12\x0e\n" +
@@ -1380,41 +1536,57 @@ const file_forum_proto_rawDesc = "" +
 	"\auser_id\x18\x03 \x01(\x03R\x06userId\x12\x18\n" +
 	"\acontent\x18\x04 \x01(\tR\acontent\x129\n" +
 	"\n" +
-	"created_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\"\x9e\x01\n" +
+	"created_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\"\x98\x03\n" +
 	"\x04Post\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x14\n" +
 	"\x05title\x18\x02 \x01(\tR\x05title\x12\x18\n" +
 	"\acontent\x18\x03 \x01(\tR\acontent\x12\x1b\n" +
 	"\tauthor_id\x18\x04 \x01(\x03R\bauthorId\x129\n" +
 	"\n" +
-	"created_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\"\xa7\x01\n" +
+	"created_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x12#\n" +
+	"\rcomment_count\x18\x06 \x01(\x03R\fcommentCount\x12D\n" +
+	"\x10last_activity_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\x0elastActivityAt\x12\x1f\n" +
+	"\vcategory_id\x18\b \x01(\x03R\n" +
+	"categoryId\x12\x1f\n" +
+	"\vauthor_name\x18\t \x01(\tR\n" +
+	"authorName\x12\x14\n" +
+	"\x05score\x18\n" +
+	" \x01(\x03R\x05score\x12\x12\n" +
+	"\x04tags\x18\v \x03(\tR\x04tags\x12!\n" +
+	"\fcontent_html\x18\f \x01(\tR\vcontentHtml\"\xa7\x01\n" +
 	"\vChatMessage\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x17\n" +
 	"\auser_id\x18\x02 \x01(\x03R\x06userId\x12\x1a\n" +
 	"\busername\x18\x03 \x01(\tR\busername\x12\x18\n" +
 	"\acontent\x18\x04 \x01(\tR\acontent\x129\n" +
 	"\n" +
-	"created_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\"M\n" +
+	"created_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\"}\n" +
 	"\x15CreateCategoryRequest\x12\x12\n" +
 	"\x04name\x18\x01 \x01(\tR\x04name\x12 \n" +
-	"\vdescription\x18\x02 \x01(\tR\vdescription\"(\n" +
+	"\vdescription\x18\x02 \x01(\tR\vdescription\x12\x12\n" +
+	"\x04slug\x18\x03 \x01(\tR\x04slug\x12\x1a\n" +
+	"\bposition\x18\x04 \x01(\x05R\bposition\"U\n" +
 	"\x16CreateCategoryResponse\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\x03R\x02id\"$\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12+\n" +
+	"\bcategory\x18\x02 \x01(\v2\x0f.forum.CategoryR\bcategory\"8\n" +
 	"\x12GetCategoryRequest\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\x03R\x02id\"B\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x12\n" +
+	"\x04slug\x18\x02 \x01(\tR\x04slug\"B\n" +
 	"\x13GetCategoryResponse\x12+\n" +
-	"\bcategory\x18\x01 \x01(\v2\x0f.forum.CategoryR\bcategory\"d\n" +
-	"\x12CreateTopicRequest\x12\x1f\n" +
-	"\vcategory_id\x18\x01 \x01(\x03R\n" +
-	"categoryId\x12\x14\n" +
-	"\x05title\x18\x02 \x01(\tR\x05title\x12\x17\n" +
-	"\auser_id\x18\x03 \x01(\x03R\x06userId\"%\n" +
-	"\x13CreateTopicResponse\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\x03R\x02id\"!\n" +
-	"\x0fGetTopicRequest\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\x03R\x02id\"6\n" +
-	"\x10GetTopicResponse\x12\"\n" +
-	"\x05topic\x18\x01 \x01(\v2\f.forum.TopicR\x05topic\"d\n" +
+	"\bcategory\x18\x01 \x01(\v2\x0f.forum.CategoryR\bcategory\"\x8d\x01\n" +
+	"\x15UpdateCategoryRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12 \n" +
+	"\vdescription\x18\x03 \x01(\tR\vdescription\x12\x12\n" +
+	"\x04slug\x18\x04 \x01(\tR\x04slug\x12\x1a\n" +
+	"\bposition\x18\x05 \x01(\x05R\bposition\"E\n" +
+	"\x16UpdateCategoryResponse\x12+\n" +
+	"\bcategory\x18\x01 \x01(\v2\x0f.forum.CategoryR\bcategory\"\x17\n" +
+	"\x15ListCategoriesRequest\"I\n" +
+	"\x16ListCategoriesResponse\x12/\n" +
+	"\n" +
+	"categories\x18\x01 \x03(\v2\x0f.forum.CategoryR\n" +
+	"categories\"d\n" +
 	"\x14CreateMessageRequest\x12\x19\n" +
 	"\btopic_id\x18\x01 \x01(\x03R\atopicId\x12\x17\n" +
 	"\auser_id\x18\x02 \x01(\x03R\x06userId\x12\x18\n" +
@@ -1424,30 +1596,46 @@ const file_forum_proto_rawDesc = "" +
 	"\x11GetMessageRequest\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\x03R\x02id\">\n" +
 	"\x12GetMessageResponse\x12(\n" +
-	"\amessage\x18\x01 \x01(\v2\x0e.forum.MessageR\amessage\"`\n" +
+	"\amessage\x18\x01 \x01(\v2\x0e.forum.MessageR\amessage\"\x95\x01\n" +
 	"\x11CreatePostRequest\x12\x14\n" +
 	"\x05title\x18\x01 \x01(\tR\x05title\x12\x18\n" +
 	"\acontent\x18\x02 \x01(\tR\acontent\x12\x1b\n" +
-	"\tauthor_id\x18\x03 \x01(\x03R\bauthorId\"E\n" +
+	"\tauthor_id\x18\x03 \x01(\x03R\bauthorId\x12\x1f\n" +
+	"\vcategory_id\x18\x04 \x01(\x03R\n" +
+	"categoryId\x12\x12\n" +
+	"\x04tags\x18\x05 \x03(\tR\x04tags\"E\n" +
 	"\x12CreatePostResponse\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x1f\n" +
-	"\x04post\x18\x02 \x01(\v2\v.forum.PostR\x04post\"?\n" +
+	"\x04post\x18\x02 \x01(\v2\v.forum.PostR\x04post\"\xda\x02\n" +
 	"\x0fGetPostsRequest\x12\x14\n" +
 	"\x05limit\x18\x01 \x01(\x05R\x05limit\x12\x16\n" +
-	"\x06offset\x18\x02 \x01(\x05R\x06offset\"5\n" +
+	"\x06cursor\x18\x03 \x01(\tR\x06cursor\x12\x12\n" +
+	"\x04sort\x18\x04 \x01(\tR\x04sort\x12\x1b\n" +
+	"\tauthor_id\x18\x05 \x01(\x03R\bauthorId\x12.\n" +
+	"\x04from\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\x04from\x12*\n" +
+	"\x02to\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\x02to\x12\x14\n" +
+	"\x05query\x18\b \x01(\tR\x05query\x12\x1f\n" +
+	"\vcategory_id\x18\t \x01(\x03R\n" +
+	"categoryId\x12\x16\n" +
+	"\x06window\x18\n" +
+	" \x01(\tR\x06window\x12\x12\n" +
+	"\x04tags\x18\v \x03(\tR\x04tags\x12\x1b\n" +
+	"\ttag_match\x18\f \x01(\tR\btagMatchJ\x04\b\x02\x10\x03R\x06offset\"V\n" +
 	"\x10GetPostsResponse\x12!\n" +
-	"\x05posts\x18\x01 \x03(\v2\v.forum.PostR\x05posts\"M\n" +
+	"\x05posts\x18\x01 \x03(\v2\v.forum.PostR\x05posts\x12\x1f\n" +
+	"\vnext_cursor\x18\x02 \x01(\tR\n" +
+	"nextCursor\"M\n" +
 	"\x18CreateChatMessageRequest\x12\x17\n" +
 	"\auser_id\x18\x01 \x01(\x03R\x06userId\x12\x18\n" +
 	"\acontent\x18\x02 \x01(\tR\acontent\"+\n" +
 	"\x19CreateChatMessageResponse\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\x03R\x02id\"\x1b\n" +
-	"\x19StreamChatMessagesRequest2\xdb\x05\n" +
+	"\x19StreamChatMessagesRequest2\xf6\x05\n" +
 	"\fForumService\x12M\n" +
 	"\x0eCreateCategory\x12\x1c.forum.CreateCategoryRequest\x1a\x1d.forum.CreateCategoryResponse\x12D\n" +
-	"\vGetCategory\x12\x19.forum.GetCategoryRequest\x1a\x1a.forum.GetCategoryResponse\x12D\n" +
-	"\vCreateTopic\x12\x19.forum.CreateTopicRequest\x1a\x1a.forum.CreateTopicResponse\x12;\n" +
-	"\bGetTopic\x12\x16.forum.GetTopicRequest\x1a\x17.forum.GetTopicResponse\x12J\n" +
+	"\vGetCategory\x12\x19.forum.GetCategoryRequest\x1a\x1a.forum.GetCategoryResponse\x12M\n" +
+	"\x0eUpdateCategory\x12\x1c.forum.UpdateCategoryRequest\x1a\x1d.forum.UpdateCategoryResponse\x12M\n" +
+	"\x0eListCategories\x12\x1c.forum.ListCategoriesRequest\x1a\x1d.forum.ListCategoriesResponse\x12J\n" +
 	"\rCreateMessage\x12\x1b.forum.CreateMessageRequest\x1a\x1c.forum.CreateMessageResponse\x12A\n" +
 	"\n" +
 	"GetMessage\x12\x18.forum.GetMessageRequest\x1a\x19.forum.GetMessageResponse\x12A\n" +
@@ -1469,71 +1657,75 @@ func file_forum_proto_rawDescGZIP() []byte {
 	return file_forum_proto_rawDescData
 }
 
-var file_forum_proto_msgTypes = make([]protoimpl.MessageInfo, 25)
+var file_forum_proto_msgTypes = make([]protoimpl.MessageInfo, 24)
 var file_forum_proto_goTypes = []any{
 	(*Category)(nil),                  // 0: forum.Category
-	(*Topic)(nil),                     // 1: forum.Topic
-	(*TokenRequest)(nil),              // 2: forum.TokenRequest
-	(*Message)(nil),                   // 3: forum.Message
-	(*Post)(nil),                      // 4: forum.Post
-	(*ChatMessage)(nil),               // 5: forum.ChatMessage
-	(*CreateCategoryRequest)(nil),     // 6: forum.CreateCategoryRequest
-	(*CreateCategoryResponse)(nil),    // 7: forum.CreateCategoryResponse
-	(*GetCategoryRequest)(nil),        // 8: forum.GetCategoryRequest
-	(*GetCategoryResponse)(nil),       // 9: forum.GetCategoryResponse
-	(*CreateTopicRequest)(nil),        // 10: forum.CreateTopicRequest
-	(*CreateTopicResponse)(nil),       // 11: forum.CreateTopicResponse
-	(*GetTopicRequest)(nil),           // 12: forum.GetTopicRequest
-	(*GetTopicResponse)(nil),          // 13: forum.GetTopicResponse
-	(*CreateMessageRequest)(nil),      // 14: forum.CreateMessageRequest
-	(*CreateMessageResponse)(nil),     // 15: forum.CreateMessageResponse
-	(*GetMessageRequest)(nil),         // 16: forum.GetMessageRequest
-	(*GetMessageResponse)(nil),        // 17: forum.GetMessageResponse
-	(*CreatePostRequest)(nil),         // 18: forum.CreatePostRequest
-	(*CreatePostResponse)(nil),        // 19: forum.CreatePostResponse
-	(*GetPostsRequest)(nil),           // 20: forum.GetPostsRequest
-	(*GetPostsResponse)(nil),          // 21: forum.GetPostsResponse
-	(*CreateChatMessageRequest)(nil),  // 22: forum.CreateChatMessageRequest
-	(*CreateChatMessageResponse)(nil), // 23: forum.CreateChatMessageResponse
-	(*StreamChatMessagesRequest)(nil), // 24: forum.StreamChatMessagesRequest
-	(*timestamppb.Timestamp)(nil),     // 25: google.protobuf.Timestamp
+	(*TokenRequest)(nil),              // 1: forum.TokenRequest
+	(*Message)(nil),                   // 2: forum.Message
+	(*Post)(nil),                      // 3: forum.Post
+	(*ChatMessage)(nil),               // 4: forum.ChatMessage
+	(*CreateCategoryRequest)(nil),     // 5: forum.CreateCategoryRequest
+	(*CreateCategoryResponse)(nil),    // 6: forum.CreateCategoryResponse
+	(*GetCategoryRequest)(nil),        // 7: forum.GetCategoryRequest
+	(*GetCategoryResponse)(nil),       // 8: forum.GetCategoryResponse
+	(*UpdateCategoryRequest)(nil),     // 9: forum.UpdateCategoryRequest
+	(*UpdateCategoryResponse)(nil),    // 10: forum.UpdateCategoryResponse
+	(*ListCategoriesRequest)(nil),     // 11: forum.ListCategoriesRequest
+	(*ListCategoriesResponse)(nil),    // 12: forum.ListCategoriesResponse
+	(*CreateMessageRequest)(nil),      // 13: forum.CreateMessageRequest
+	(*CreateMessageResponse)(nil),     // 14: forum.CreateMessageResponse
+	(*GetMessageRequest)(nil),         // 15: forum.GetMessageRequest
+	(*GetMessageResponse)(nil),        // 16: forum.GetMessageResponse
+	(*CreatePostRequest)(nil),         // 17: forum.CreatePostRequest
+	(*CreatePostResponse)(nil),        // 18: forum.CreatePostResponse
+	(*GetPostsRequest)(nil),           // 19: forum.GetPostsRequest
+	(*GetPostsResponse)(nil),          // 20: forum.GetPostsResponse
+	(*CreateChatMessageRequest)(nil),  // 21: forum.CreateChatMessageRequest
+	(*CreateChatMessageResponse)(nil), // 22: forum.CreateChatMessageResponse
+	(*StreamChatMessagesRequest)(nil), // 23: forum.StreamChatMessagesRequest
+	(*timestamppb.Timestamp)(nil),     // 24: google.protobuf.Timestamp
 }
 var file_forum_proto_depIdxs = []int32{
-	25, // 0: forum.Category.created_at:type_name -> google.protobuf.Timestamp
-	25, // 1: forum.Topic.created_at:type_name -> google.protobuf.Timestamp
-	25, // 2: forum.Message.created_at:type_name -> google.protobuf.Timestamp
-	25, // 3: forum.Post.created_at:type_name -> google.protobuf.Timestamp
-	25, // 4: forum.ChatMessage.created_at:type_name -> google.protobuf.Timestamp
-	0,  // 5: forum.GetCategoryResponse.category:type_name -> forum.Category
-	1,  // 6: forum.GetTopicResponse.topic:type_name -> forum.Topic
-	3,  // 7: forum.GetMessageResponse.message:type_name -> forum.Message
-	4,  // 8: forum.CreatePostResponse.post:type_name -> forum.Post
-	4,  // 9: forum.GetPostsResponse.posts:type_name -> forum.Post
-	6,  // 10: forum.ForumService.CreateCategory:input_type -> forum.CreateCategoryRequest
-	8,  // 11: forum.ForumService.GetCategory:input_type -> forum.GetCategoryRequest
-	10, // 12: forum.ForumService.CreateTopic:input_type -> forum.CreateTopicRequest
-	12, // 13: forum.ForumService.GetTopic:input_type -> forum.GetTopicRequest
-	14, // 14: forum.ForumService.CreateMessage:input_type -> forum.CreateMessageRequest
-	16, // 15: forum.ForumService.GetMessage:input_type -> forum.GetMessageRequest
-	18, // 16: forum.ForumService.CreatePost:input_type -> forum.CreatePostRequest
-	20, // 17: forum.ForumService.GetPosts:input_type -> forum.GetPostsRequest
-	22, // 18: forum.ForumService.CreateChatMessage:input_type -> forum.CreateChatMessageRequest
-	24, // 19: forum.ForumService.StreamChatMessages:input_type -> forum.StreamChatMessagesRequest
-	7,  // 20: forum.ForumService.CreateCategory:output_type -> forum.CreateCategoryResponse
-	9,  // 21: forum.ForumService.GetCategory:output_type -> forum.GetCategoryResponse
-	11, // 22: forum.ForumService.CreateTopic:output_type -> forum.CreateTopicResponse
-	13, // 23: forum.ForumService.GetTopic:output_type -> forum.GetTopicResponse
-	15, // 24: forum.ForumService.CreateMessage:output_type -> forum.CreateMessageResponse
-	17, // 25: forum.ForumService.GetMessage:output_type -> forum.GetMessageResponse
-	19, // 26: forum.ForumService.CreatePost:output_type -> forum.CreatePostResponse
-	21, // 27: forum.ForumService.GetPosts:output_type -> forum.GetPostsResponse
-	23, // 28: forum.ForumService.CreateChatMessage:output_type -> forum.CreateChatMessageResponse
-	5,  // 29: forum.ForumService.StreamChatMessages:output_type -> forum.ChatMessage
-	20, // [20:30] is the sub-list for method output_type
-	10, // [10:20] is the sub-list for method input_type
-	10, // [10:10] is the sub-list for extension type_name
-	10, // [10:10] is the sub-list for extension extendee
-	0,  // [0:10] is the sub-list for field type_name
+	24, // 0: forum.Category.created_at:type_name -> google.protobuf.Timestamp
+	24, // 1: forum.Category.last_activity_at:type_name -> google.protobuf.Timestamp
+	24, // 2: forum.Message.created_at:type_name -> google.protobuf.Timestamp
+	24, // 3: forum.Post.created_at:type_name -> google.protobuf.Timestamp
+	24, // 4: forum.Post.last_activity_at:type_name -> google.protobuf.Timestamp
+	24, // 5: forum.ChatMessage.created_at:type_name -> google.protobuf.Timestamp
+	0,  // 6: forum.CreateCategoryResponse.category:type_name -> forum.Category
+	0,  // 7: forum.GetCategoryResponse.category:type_name -> forum.Category
+	0,  // 8: forum.UpdateCategoryResponse.category:type_name -> forum.Category
+	0,  // 9: forum.ListCategoriesResponse.categories:type_name -> forum.Category
+	2,  // 10: forum.GetMessageResponse.message:type_name -> forum.Message
+	3,  // 11: forum.CreatePostResponse.post:type_name -> forum.Post
+	24, // 12: forum.GetPostsRequest.from:type_name -> google.protobuf.Timestamp
+	24, // 13: forum.GetPostsRequest.to:type_name -> google.protobuf.Timestamp
+	3,  // 14: forum.GetPostsResponse.posts:type_name -> forum.Post
+	5,  // 15: forum.ForumService.CreateCategory:input_type -> forum.CreateCategoryRequest
+	7,  // 16: forum.ForumService.GetCategory:input_type -> forum.GetCategoryRequest
+	9,  // 17: forum.ForumService.UpdateCategory:input_type -> forum.UpdateCategoryRequest
+	11, // 18: forum.ForumService.ListCategories:input_type -> forum.ListCategoriesRequest
+	13, // 19: forum.ForumService.CreateMessage:input_type -> forum.CreateMessageRequest
+	15, // 20: forum.ForumService.GetMessage:input_type -> forum.GetMessageRequest
+	17, // 21: forum.ForumService.CreatePost:input_type -> forum.CreatePostRequest
+	19, // 22: forum.ForumService.GetPosts:input_type -> forum.GetPostsRequest
+	21, // 23: forum.ForumService.CreateChatMessage:input_type -> forum.CreateChatMessageRequest
+	23, // 24: forum.ForumService.StreamChatMessages:input_type -> forum.StreamChatMessagesRequest
+	6,  // 25: forum.ForumService.CreateCategory:output_type -> forum.CreateCategoryResponse
+	8,  // 26: forum.ForumService.GetCategory:output_type -> forum.GetCategoryResponse
+	10, // 27: forum.ForumService.UpdateCategory:output_type -> forum.UpdateCategoryResponse
+	12, // 28: forum.ForumService.ListCategories:output_type -> forum.ListCategoriesResponse
+	14, // 29: forum.ForumService.CreateMessage:output_type -> forum.CreateMessageResponse
+	16, // 30: forum.ForumService.GetMessage:output_type -> forum.GetMessageResponse
+	18, // 31: forum.ForumService.CreatePost:output_type -> forum.CreatePostResponse
+	20, // 32: forum.ForumService.GetPosts:output_type -> forum.GetPostsResponse
+	22, // 33: forum.ForumService.CreateChatMessage:output_type -> forum.CreateChatMessageResponse
+	4,  // 34: forum.ForumService.StreamChatMessages:output_type -> forum.ChatMessage
+	25, // [25:35] is the sub-list for method output_type
+	15, // [15:25] is the sub-list for method input_type
+	15, // [15:15] is the sub-list for extension type_name
+	15, // [15:15] is the sub-list for extension extendee
+	0,  // [0:15] is the sub-list for field type_name
 }
 
 func init() { file_forum_proto_init() }
@@ -1547,7 +1739,7 @@ func file_forum_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_forum_proto_rawDesc), len(file_forum_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   25,
+			NumMessages:   24,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
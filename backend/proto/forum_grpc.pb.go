@@ -21,8 +21,8 @@ const _ = grpc.SupportPackageIsVersion9
 const (
 	ForumService_CreateCategory_FullMethodName     = "/forum.ForumService/CreateCategory"
 	ForumService_GetCategory_FullMethodName        = "/forum.ForumService/GetCategory"
-	ForumService_CreateTopic_FullMethodName        = "/forum.ForumService/CreateTopic"
-	ForumService_GetTopic_FullMethodName           = "/forum.ForumService/GetTopic"
+	ForumService_UpdateCategory_FullMethodName     = "/forum.ForumService/UpdateCategory"
+	ForumService_ListCategories_FullMethodName     = "/forum.ForumService/ListCategories"
 	ForumService_CreateMessage_FullMethodName      = "/forum.ForumService/CreateMessage"
 	ForumService_GetMessage_FullMethodName         = "/forum.ForumService/GetMessage"
 	ForumService_CreatePost_FullMethodName         = "/forum.ForumService/CreatePost"
@@ -40,9 +40,8 @@ type ForumServiceClient interface {
 	// Категории
 	CreateCategory(ctx context.Context, in *CreateCategoryRequest, opts ...grpc.CallOption) (*CreateCategoryResponse, error)
 	GetCategory(ctx context.Context, in *GetCategoryRequest, opts ...grpc.CallOption) (*GetCategoryResponse, error)
-	// Темы
-	CreateTopic(ctx context.Context, in *CreateTopicRequest, opts ...grpc.CallOption) (*CreateTopicResponse, error)
-	GetTopic(ctx context.Context, in *GetTopicRequest, opts ...grpc.CallOption) (*GetTopicResponse, error)
+	UpdateCategory(ctx context.Context, in *UpdateCategoryRequest, opts ...grpc.CallOption) (*UpdateCategoryResponse, error)
+	ListCategories(ctx context.Context, in *ListCategoriesRequest, opts ...grpc.CallOption) (*ListCategoriesResponse, error)
 	// Сообщения
 	CreateMessage(ctx context.Context, in *CreateMessageRequest, opts ...grpc.CallOption) (*CreateMessageResponse, error)
 	GetMessage(ctx context.Context, in *GetMessageRequest, opts ...grpc.CallOption) (*GetMessageResponse, error)
@@ -82,20 +81,20 @@ func (c *forumServiceClient) GetCategory(ctx context.Context, in *GetCategoryReq
 	return out, nil
 }
 
-func (c *forumServiceClient) CreateTopic(ctx context.Context, in *CreateTopicRequest, opts ...grpc.CallOption) (*CreateTopicResponse, error) {
+func (c *forumServiceClient) UpdateCategory(ctx context.Context, in *UpdateCategoryRequest, opts ...grpc.CallOption) (*UpdateCategoryResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
-	out := new(CreateTopicResponse)
-	err := c.cc.Invoke(ctx, ForumService_CreateTopic_FullMethodName, in, out, cOpts...)
+	out := new(UpdateCategoryResponse)
+	err := c.cc.Invoke(ctx, ForumService_UpdateCategory_FullMethodName, in, out, cOpts...)
 	if err != nil {
 		return nil, err
 	}
 	return out, nil
 }
 
-func (c *forumServiceClient) GetTopic(ctx context.Context, in *GetTopicRequest, opts ...grpc.CallOption) (*GetTopicResponse, error) {
+func (c *forumServiceClient) ListCategories(ctx context.Context, in *ListCategoriesRequest, opts ...grpc.CallOption) (*ListCategoriesResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
-	out := new(GetTopicResponse)
-	err := c.cc.Invoke(ctx, ForumService_GetTopic_FullMethodName, in, out, cOpts...)
+	out := new(ListCategoriesResponse)
+	err := c.cc.Invoke(ctx, ForumService_ListCategories_FullMethodName, in, out, cOpts...)
 	if err != nil {
 		return nil, err
 	}
@@ -180,9 +179,8 @@ type ForumServiceServer interface {
 	// Категории
 	CreateCategory(context.Context, *CreateCategoryRequest) (*CreateCategoryResponse, error)
 	GetCategory(context.Context, *GetCategoryRequest) (*GetCategoryResponse, error)
-	// Темы
-	CreateTopic(context.Context, *CreateTopicRequest) (*CreateTopicResponse, error)
-	GetTopic(context.Context, *GetTopicRequest) (*GetTopicResponse, error)
+	UpdateCategory(context.Context, *UpdateCategoryRequest) (*UpdateCategoryResponse, error)
+	ListCategories(context.Context, *ListCategoriesRequest) (*ListCategoriesResponse, error)
 	// Сообщения
 	CreateMessage(context.Context, *CreateMessageRequest) (*CreateMessageResponse, error)
 	GetMessage(context.Context, *GetMessageRequest) (*GetMessageResponse, error)
@@ -208,11 +206,11 @@ func (UnimplementedForumServiceServer) CreateCategory(context.Context, *CreateCa
 func (UnimplementedForumServiceServer) GetCategory(context.Context, *GetCategoryRequest) (*GetCategoryResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetCategory not implemented")
 }
-func (UnimplementedForumServiceServer) CreateTopic(context.Context, *CreateTopicRequest) (*CreateTopicResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method CreateTopic not implemented")
+func (UnimplementedForumServiceServer) UpdateCategory(context.Context, *UpdateCategoryRequest) (*UpdateCategoryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateCategory not implemented")
 }
-func (UnimplementedForumServiceServer) GetTopic(context.Context, *GetTopicRequest) (*GetTopicResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method GetTopic not implemented")
+func (UnimplementedForumServiceServer) ListCategories(context.Context, *ListCategoriesRequest) (*ListCategoriesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListCategories not implemented")
 }
 func (UnimplementedForumServiceServer) CreateMessage(context.Context, *CreateMessageRequest) (*CreateMessageResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method CreateMessage not implemented")
@@ -289,38 +287,38 @@ func _ForumService_GetCategory_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
-func _ForumService_CreateTopic_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(CreateTopicRequest)
+func _ForumService_UpdateCategory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateCategoryRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(ForumServiceServer).CreateTopic(ctx, in)
+		return srv.(ForumServiceServer).UpdateCategory(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: ForumService_CreateTopic_FullMethodName,
+		FullMethod: ForumService_UpdateCategory_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ForumServiceServer).CreateTopic(ctx, req.(*CreateTopicRequest))
+		return srv.(ForumServiceServer).UpdateCategory(ctx, req.(*UpdateCategoryRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _ForumService_GetTopic_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(GetTopicRequest)
+func _ForumService_ListCategories_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListCategoriesRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(ForumServiceServer).GetTopic(ctx, in)
+		return srv.(ForumServiceServer).ListCategories(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: ForumService_GetTopic_FullMethodName,
+		FullMethod: ForumService_ListCategories_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ForumServiceServer).GetTopic(ctx, req.(*GetTopicRequest))
+		return srv.(ForumServiceServer).ListCategories(ctx, req.(*ListCategoriesRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
@@ -442,12 +440,12 @@ var ForumService_ServiceDesc = grpc.ServiceDesc{
 			Handler:    _ForumService_GetCategory_Handler,
 		},
 		{
-			MethodName: "CreateTopic",
-			Handler:    _ForumService_CreateTopic_Handler,
+			MethodName: "UpdateCategory",
+			Handler:    _ForumService_UpdateCategory_Handler,
 		},
 		{
-			MethodName: "GetTopic",
-			Handler:    _ForumService_GetTopic_Handler,
+			MethodName: "ListCategories",
+			Handler:    _ForumService_ListCategories_Handler,
 		},
 		{
 			MethodName: "CreateMessage",
@@ -1,6 +1,7 @@
 import React, { useState } from 'react';
 import { useNavigate } from 'react-router-dom';
 import axios from 'axios';
+import { saveSession } from '../../services/authService';
 import '../MainLayout.css';
 
 const Login = () => {
@@ -11,21 +12,8 @@ const Login = () => {
     const handleLogin = async () => {
         try {
             const response = await axios.post('http://localhost:8080/api/v1/auth/login', { username, password });
-            console.log('Login response:', response.data);
-            const token = response.data.token;
-            localStorage.setItem('token', token);
-
-            // Распарсить user_id из токена
-            const base64Url = token.split('.')[1];
-            const base64 = base64Url.replace(/-/g, '+').replace(/_/g, '/');
-            const payload = JSON.parse(atob(base64));
-            console.log(payload);
-            localStorage.setItem('userId', String(payload.user_id));
-            localStorage.setItem('username', response.data.username);
-            localStorage.setItem('userRole', payload.role); 
-            console.log('username', response.data.username);
-            
-        console.log('User role:', payload.role);
+            // Токены и данные пользователя из токена
+            saveSession(response.data);
             navigate('/posts');
         } catch (error) {
             console.error('Login failed:', error);
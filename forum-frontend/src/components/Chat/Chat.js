@@ -1,24 +1,46 @@
-import React, { useState, useEffect, useRef } from 'react';
-import axios from 'axios';
+import React, { useState, useEffect, useRef, useCallback } from 'react';
 import useWebSocket from '../../hooks/useWebSocket.js';
 import { useNavigate } from 'react-router-dom';
+import { getValidToken } from '../../services/authService';
+import {
+    CHAT_WS_URL,
+    getRooms,
+    getRoomMessages,
+    joinFrame,
+    leaveFrame,
+    messageFrame,
+} from '../../services/chatService';
 import '../MainLayout.css';
 
+// appendNew добавляет к списку сообщения, которых в нем еще нет: после
+// переподключения сервер может прислать уже показанные.
+const appendNew = (list, incoming) => {
+    const lastId = list.length > 0 ? list[list.length - 1].id : 0;
+    const fresh = incoming.filter(msg => msg.id > lastId);
+    return fresh.length > 0 ? [...list, ...fresh] : list;
+};
+
 const Chat = () => {
     const [message, setMessage] = useState('');
+    const [rooms, setRooms] = useState([]);
+    const [roomId, setRoomId] = useState(null);
     const [messages, setMessages] = useState([]);
+    const [nextBefore, setNextBefore] = useState(0);
+    const [historyRoomId, setHistoryRoomId] = useState(null);
     const [connectionStatus, setConnectionStatus] = useState('connecting');
     const [error, setError] = useState(null);
     const [isLoading, setIsLoading] = useState(true);
     const navigate = useNavigate();
     const messagesEndRef = useRef(null);
+    const messagesRef = useRef(messages);
+    messagesRef.current = messages;
 
-    const token = localStorage.getItem('token');
     const username = localStorage.getItem('username');
-    const isAuthenticated = !!token;
+    const isAuthenticated = !!localStorage.getItem('token');
 
-    const { sendMessage, lastMessage } = useWebSocket(
-        'ws://localhost:8082/ws',
+    const { sendMessage, lastMessage, status } = useWebSocket(
+        CHAT_WS_URL,
+        getValidToken,
         {
             onOpen: () => {
                 console.log('WebSocket connection established');
@@ -41,62 +63,140 @@ const Chat = () => {
         }
     );
 
+    // Комнаты пользователя; по умолчанию открывается первая
     useEffect(() => {
-        const fetchMessages = async () => {
-            try {
-                const response = await axios.get('http://localhost:8082/messages');
-                // Обрабатываем timestamp при загрузке сообщений
-                const processedMessages = (response.data || []).map(msg => ({
-                    ...msg,
-                    timestamp: msg.timestamp || new Date().toISOString()
-                }));
-                setMessages(processedMessages);
-                setIsLoading(false);
-            } catch (error) {
-                console.error('Error fetching messages:', error);
+        if (!isAuthenticated) {
+            setIsLoading(false);
+            return;
+        }
+        getRooms()
+            .then(list => {
+                setRooms(list);
+                setRoomId(list.length > 0 ? list[0].id : null);
+                if (list.length === 0) setIsLoading(false);
+            })
+            .catch(err => {
+                console.error('Error fetching rooms:', err);
                 setError('Failed to load chat history');
                 setIsLoading(false);
-            }
+            });
+    }, [isAuthenticated]);
+
+    // История выбранной комнаты
+    useEffect(() => {
+        if (roomId === null) return;
+        let cancelled = false;
+
+        setHistoryRoomId(null);
+        getRoomMessages(roomId)
+            .then(page => {
+                if (cancelled) return;
+                setMessages(page.messages);
+                setNextBefore(page.nextBefore);
+                setHistoryRoomId(roomId);
+            })
+            .catch(err => {
+                if (cancelled) return;
+                console.error('Error fetching messages:', err);
+                setError('Failed to load chat history');
+            })
+            .finally(() => {
+                if (!cancelled) setIsLoading(false);
+            });
+
+        return () => {
+            cancelled = true;
         };
+    }, [roomId]);
+
+    // Подписка на комнату после загрузки истории и после каждого
+    // переподключения. after - последнее полученное сообщение, сервер
+    // пришлет пропущенные кадром history.
+    useEffect(() => {
+        if (status !== 'connected' || historyRoomId === null) return;
 
-        fetchMessages();
-    }, []);
+        const current = messagesRef.current;
+        const after = current.length > 0 ? current[current.length - 1].id : 0;
+        sendMessage(joinFrame(historyRoomId, after));
+
+        return () => {
+            sendMessage(leaveFrame(historyRoomId));
+        };
+    }, [status, historyRoomId, sendMessage]);
+
+    const reloadHistory = useCallback(async () => {
+        try {
+            const page = await getRoomMessages(roomId);
+            setMessages(page.messages);
+            setNextBefore(page.nextBefore);
+        } catch (err) {
+            console.error('Error fetching messages:', err);
+            setError('Failed to load chat history');
+        }
+    }, [roomId]);
 
     useEffect(() => {
-        if (lastMessage !== null) {
-            try {
-                const newMessage = JSON.parse(lastMessage.data);
-                // Добавляем timestamp, если его нет
-                if (!newMessage.timestamp) {
-                    newMessage.timestamp = new Date().toISOString();
+        if (lastMessage === null) return;
+
+        let frame;
+        try {
+            frame = JSON.parse(lastMessage.data);
+        } catch (err) {
+            console.error('Error parsing message:', err);
+            return;
+        }
+
+        switch (frame.type) {
+            case 'message':
+                if (frame.room_id === historyRoomId) {
+                    setMessages(prev => appendNew(prev, [frame]));
                 }
-                setMessages(prev => [...prev, newMessage]);
-            } catch (err) {
-                console.error('Error parsing message:', err);
-            }
+                break;
+            case 'history':
+                if (frame.room_id !== historyRoomId) break;
+                if (frame.history?.next_before) {
+                    // Пропущено больше, чем помещается в кадр: загружаем
+                    // последнюю страницу заново.
+                    reloadHistory();
+                } else {
+                    setMessages(prev => appendNew(prev, frame.history?.messages || []));
+                }
+                break;
+            case 'error':
+                setError(frame.error);
+                break;
+            default:
+                break;
         }
-    }, [lastMessage]);
+    }, [lastMessage, historyRoomId, reloadHistory]);
 
     useEffect(() => {
         messagesEndRef.current?.scrollIntoView({ behavior: 'smooth' });
     }, [messages]);
 
+    const loadOlder = async () => {
+        try {
+            const page = await getRoomMessages(roomId, { before: nextBefore });
+            setMessages(prev => [...page.messages, ...prev]);
+            setNextBefore(page.nextBefore);
+        } catch (err) {
+            console.error('Error fetching messages:', err);
+            setError('Failed to load chat history');
+        }
+    };
+
     const handleSendMessage = () => {
         if (!isAuthenticated) {
             navigate('/login');
             return;
         }
 
-        if (!message.trim()) return;
-
-        const msg = {
-            username: username || 'Anonymous',
-            message: message.trim(),
-            timestamp: new Date().toISOString() // Всегда добавляем timestamp при отправке
-        };
+        if (!message.trim() || roomId === null) return;
 
-        sendMessage(JSON.stringify(msg));
-        setMessage('');
+        // Автор и время сообщения задаются сервером
+        if (sendMessage(messageFrame(roomId, message.trim()))) {
+            setMessage('');
+        }
     };
 
     // Функция для форматирования времени сообщения
@@ -125,7 +225,18 @@ const Chat = () => {
         <div className="chat-wrapper">
             <div className="chat-container">
                 <div className="chat-header">
-                    <h2>Общий чат</h2>
+                    <h2>Чат</h2>
+                    {rooms.length > 1 && (
+                        <select
+                            className="chat-room-select"
+                            value={roomId ?? ''}
+                            onChange={(e) => setRoomId(Number(e.target.value))}
+                        >
+                            {rooms.map(room => (
+                                <option key={room.id} value={room.id}>{room.name}</option>
+                            ))}
+                        </select>
+                    )}
                     <div className={`connection-status ${connectionStatus}`}>
                         {connectionStatus === 'connected' ? 'ПОДКЛЮЧЕНО' : 
                          connectionStatus === 'connecting' ? 'ПОДКЛЮЧЕНИЕ' : 
@@ -140,13 +251,18 @@ const Chat = () => {
                                                         error}</div>}
 
                 <div className="messages-window">
+                    {nextBefore > 0 && (
+                        <button className="load-older" onClick={loadOlder}>
+                            Показать более ранние сообщения
+                        </button>
+                    )}
                     {messages.length > 0 ? (
-                        messages.map((msg, index) => (
-                            <div key={index} className={`message ${msg.username === username ? 'own-message' : ''}`}>
+                        messages.map(msg => (
+                            <div key={msg.id} className={`message ${msg.username === username ? 'own-message' : ''}`}>
                                 <div className="message-header">
                                     <span className="message-username">{msg.username}</span>
                                     <span className="message-time">
-                                        {formatMessageTime(msg.timestamp)}
+                                        {formatMessageTime(msg.created_at)}
                                     </span>
                                 </div>
                                 <div className="message-content">{msg.message}</div>
@@ -165,11 +281,11 @@ const Chat = () => {
                         onChange={(e) => setMessage(e.target.value)}
                         onKeyPress={(e) => e.key === 'Enter' && handleSendMessage()}
                         placeholder={isAuthenticated ? "Введите сообщение..." : "Пожалуйста, войдите в систему для отправки сообщений"}
-                        disabled={!isAuthenticated || connectionStatus !== 'connected'}
+                        disabled={!isAuthenticated || roomId === null || connectionStatus !== 'connected'}
                     />
                     <button
                         onClick={handleSendMessage}
-                        disabled={!message.trim() || !isAuthenticated || connectionStatus !== 'connected'}
+                        disabled={!message.trim() || !isAuthenticated || roomId === null || connectionStatus !== 'connected'}
                     >
                         Отправить
                     </button>
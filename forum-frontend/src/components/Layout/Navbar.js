@@ -1,13 +1,14 @@
 import React from 'react';
  import '../MainLayout.css'; // Импортируйте CSS здесь
 import { Link, useNavigate } from 'react-router-dom';
+import { logout } from '../../services/authService';
 
 const Navbar = () => {
     const navigate = useNavigate();
     const isLoggedIn = localStorage.getItem('token') !== null;
 
-    const handleLogout = () => {
-        localStorage.removeItem('token');
+    const handleLogout = async () => {
+        await logout();
         navigate('/login');
     };
 
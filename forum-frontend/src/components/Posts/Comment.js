@@ -51,12 +51,18 @@ const Comments = ({ postId }) => {
                 commentsArray = rawData.comments;
             }
 
+            // У удаленных и скрытых комментариев сервер убирает автора
+            // и подставляет заглушку вместо текста
             const processedComments = commentsArray.map(comment => ({
                 id: parseInt(comment.id, 10),
                 author_id: parseInt(comment.author_id, 10),
                 post_id: parseInt(comment.post_id, 10),
                 content: comment.content || '',
-                author_name: comment.author_name || `User #${comment.author_id}`,
+                content_html: comment.content_html || '',
+                deleted: !!comment.deleted,
+                hidden: !!comment.hidden,
+                author_name: comment.author_name ||
+                    (comment.deleted || comment.hidden ? '' : `User #${comment.author_id}`),
                 created_at: comment.created_at || new Date().toISOString()
             }));
 
@@ -178,11 +184,22 @@ const Comments = ({ postId }) => {
                                 })}
                             </span>
                         </div>
-                        <div className="comment-content">
-                            {(comment.content || '').split('\n').map((line, index) => (
-                                <p key={index}>{line}</p>
-                            ))}
-                        </div>
+                        {comment.deleted || comment.hidden ? (
+                            <div className="comment-content comment-placeholder">
+                                <p>{comment.content}</p>
+                            </div>
+                        ) : comment.content_html ? (
+                            <div
+                                className="comment-content"
+                                dangerouslySetInnerHTML={{ __html: comment.content_html }}
+                            />
+                        ) : (
+                            <div className="comment-content">
+                                {(comment.content || '').split('\n').map((line, index) => (
+                                    <p key={index}>{line}</p>
+                                ))}
+                            </div>
+                        )}
                     </div>
                 ))}
             </div>
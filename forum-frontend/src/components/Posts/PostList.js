@@ -6,6 +6,7 @@ import { useNavigate } from 'react-router-dom';
 
 const PostList = ({ refreshTrigger }) => {
     const [posts, setPosts] = useState([]);
+    const [nextCursor, setNextCursor] = useState('');
     const [loading, setLoading] = useState(true);
     const [error, setError] = useState(null);
     const [editingPostId, setEditingPostId] = useState(null);
@@ -20,13 +21,14 @@ const PostList = ({ refreshTrigger }) => {
     const isAuthenticated = !!token;
     const navigate = useNavigate();
 
-    const fetchPosts = useCallback(async () => {
+    const fetchPosts = useCallback(async (cursor = '') => {
         try {
             setLoading(true);
             setError(null);
             setEditingPostId(null);
 
             const response = await axios.get('http://localhost:8081/api/v1/posts', {
+                params: cursor ? { cursor } : {},
                 headers: { 
                     'Accept': 'application/json',
                     ...(token && { 'Authorization': `Bearer ${token}` })
@@ -46,7 +48,8 @@ const PostList = ({ refreshTrigger }) => {
                 created_at: new Date(post.created_at).toISOString()
             }));
 
-            setPosts(processedPosts);
+            setPosts(prev => cursor ? [...prev, ...processedPosts] : processedPosts);
+            setNextCursor(response.data.next_cursor || '');
         } catch (err) {
             setError(err.message || 'Failed to load posts');
         } finally {
@@ -201,11 +204,19 @@ const PostList = ({ refreshTrigger }) => {
                                     </div>
                                 )}
                             </div>
-                            <div className="post-content">
-                                {post.content.split('\n').map((p, i) => (
-                                    <p key={i}>{p}</p>
-                                ))}
-                            </div>
+                            {/* content_html is sanitized by forum-service */}
+                            {post.content_html ? (
+                                <div
+                                    className="post-content"
+                                    dangerouslySetInnerHTML={{ __html: post.content_html }}
+                                />
+                            ) : (
+                                <div className="post-content">
+                                    {post.content.split('\n').map((p, i) => (
+                                        <p key={i}>{p}</p>
+                                    ))}
+                                </div>
+                            )}
                         </>
                     )}
                     
@@ -229,6 +240,12 @@ const PostList = ({ refreshTrigger }) => {
                     <Comments postId={post.id} />
                 </div>
             ))}
+
+            {nextCursor && !loading && (
+                <button onClick={() => fetchPosts(nextCursor)} className="load-more-button">
+                    Показать еще
+                </button>
+            )}
         </div>
     );
 };
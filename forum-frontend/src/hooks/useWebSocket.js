@@ -1,109 +1,91 @@
 import { useState, useEffect, useCallback, useRef } from 'react';
 
-const useWebSocket = (webSocketUrl, apiUrl, token) => {
-  const [messages, setMessages] = useState([]);
+// Browsers cannot set headers on a WebSocket handshake, and a token in the
+// URL would end up in server logs, so it travels as a subprotocol.
+const BEARER_SUBPROTOCOL = 'bearer';
+
+// useWebSocket keeps a connection to url open, reconnecting after drops.
+// getToken returns the access token or a promise of it; it is called before
+// every connection, so a refreshed session is picked up.
+const useWebSocket = (url, getToken, options = {}) => {
+  const [lastMessage, setLastMessage] = useState(null);
   const [status, setStatus] = useState('disconnected');
-  const [error, setError] = useState(null);
   const ws = useRef(null);
-  const reconnectAttempts = useRef(0);
+  const attempts = useRef(0);
+  const optionsRef = useRef(options);
+  optionsRef.current = options;
 
-  const loadHistory = useCallback(async () => {
-    try {
-      const response = await fetch(apiUrl, {
-        headers: { 'Authorization': `Bearer ${token}` }
-      });
-      
-      if (!response.ok) throw new Error(`HTTP error! status: ${response.status}`);
-      
-      const history = await response.json();
-      setMessages(history);
-    } catch (err) {
-      setError(err.message);
-      console.error('History load error:', err);
-    }
-  }, [apiUrl, token]);
-
-  const connectWebSocket = useCallback(() => {
-    if (!token || status === 'connected') return;
-
-    const wsUrl = new URL(webSocketUrl);
-    wsUrl.searchParams.set('token', token);
-
-    ws.current = new WebSocket(wsUrl.href);
-    setStatus('connecting');
-
-    ws.current.onopen = () => {
-      console.log('WebSocket connected');
-      reconnectAttempts.current = 0;
-      setStatus('connected');
-      setError(null);
-    };
-
-    ws.current.onmessage = (event) => {
-      try {
-        const newMessage = JSON.parse(event.data);
-        setMessages(prev => [...prev, newMessage]);
-      } catch (err) {
-        console.error('Message parse error:', err);
-      }
-    };
-
-    ws.current.onclose = (event) => {
-      console.log('WebSocket closed:', event.code, event.reason);
-      setStatus('disconnected');
-      
-      if (event.code === 4001 || event.code === 4002) {
-        setError('Authentication required');
+  useEffect(() => {
+    let closed = false;
+    let timer = null;
+
+    const connect = async () => {
+      const token = await getToken();
+      if (closed) return;
+      if (!token) {
+        setStatus('error');
+        optionsRef.current.onError?.(new Error('Authentication token is required'));
         return;
       }
 
-      // Exponential backoff reconnect
-      const timeout = Math.min(1000 * 2 ** reconnectAttempts.current, 30000);
-      setTimeout(() => {
-        reconnectAttempts.current++;
-        connectWebSocket();
-      }, timeout);
-    };
-
-    ws.current.onerror = (err) => {
-      console.error('WebSocket error:', err);
-      setStatus('error');
-      setError('Connection error');
+      setStatus('connecting');
+      const socket = new WebSocket(url, [BEARER_SUBPROTOCOL, token]);
+      ws.current = socket;
+
+      socket.onopen = (event) => {
+        attempts.current = 0;
+        setStatus('connected');
+        optionsRef.current.onOpen?.(event, socket);
+      };
+
+      socket.onmessage = (event) => {
+        setLastMessage(event);
+        optionsRef.current.onMessage?.(event);
+      };
+
+      socket.onerror = (event) => {
+        setStatus('error');
+        optionsRef.current.onError?.(event);
+      };
+
+      socket.onclose = (event) => {
+        ws.current = null;
+        if (closed) return;
+        setStatus('disconnected');
+        optionsRef.current.onClose?.(event);
+
+        const {
+          shouldReconnect = () => true,
+          reconnectAttempts = Infinity,
+          reconnectInterval = 1000,
+        } = optionsRef.current;
+        if (!shouldReconnect(event) || attempts.current >= reconnectAttempts) return;
+
+        // Exponential backoff reconnect
+        const timeout = Math.min(reconnectInterval * 2 ** attempts.current, 30000);
+        attempts.current++;
+        timer = setTimeout(connect, timeout);
+      };
     };
-  }, [webSocketUrl, token, status]);
 
-  useEffect(() => {
-    if (!token) {
-      setError('Authentication token is required');
-      return;
-    }
-
-    loadHistory();
-    connectWebSocket();
+    connect();
 
     return () => {
-      if (ws.current?.readyState === WebSocket.OPEN) {
-        ws.current.close();
-      }
+      closed = true;
+      clearTimeout(timer);
+      ws.current?.close();
     };
-  }, [token, loadHistory, connectWebSocket]);
-
-  const sendMessage = useCallback((message) => {
-    if (ws.current?.readyState === WebSocket.OPEN) {
-      ws.current.send(JSON.stringify({
-        ...message,
-        timestamp: new Date().toISOString()
-      }));
+  }, [url, getToken]);
+
+  const sendMessage = useCallback((data) => {
+    if (ws.current?.readyState !== WebSocket.OPEN) {
+      return false;
     }
+    ws.current.send(typeof data === 'string' ? data : JSON.stringify(data));
+    return true;
   }, []);
 
-  return {
-    messages,
-    sendMessage,
-    status,
-    error,
-    retry: loadHistory
-  };
+  return { sendMessage, lastMessage, status };
 };
 
 export default useWebSocket;
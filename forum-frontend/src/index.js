@@ -1,7 +1,12 @@
 // index.js (или index.tsx)
 import React from 'react';
 import ReactDOM from 'react-dom/client'; //Изменилось в React 18
+import axios from 'axios';
 import App from './App';
+import { installAuthInterceptors } from './services/authService';
+
+// Access-токен обновляется по refresh-токену без повторного входа
+installAuthInterceptors(axios);
 
 const root = ReactDOM.createRoot(document.getElementById('root')); //React 18
 root.render(
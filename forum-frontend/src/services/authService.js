@@ -54,4 +54,142 @@ export const validateToken = async (token) => {
     }
 
     return data;
-};
\ No newline at end of file
+};
+
+// Сессия: access-токен живет недолго, refresh-токен одноразовый и
+// меняется при каждом обновлении.
+const AUTH_API_URL = 'http://localhost:8080/api/v1/auth';
+
+// Обновляем access-токен заранее, чтобы запрос не ушел с истекшим
+const REFRESH_MARGIN_MS = 30 * 1000;
+
+const SESSION_KEYS = ['token', 'refreshToken', 'userId', 'username', 'userRole'];
+
+const parseToken = (token) => {
+  const base64 = token.split('.')[1].replace(/-/g, '+').replace(/_/g, '/');
+  return JSON.parse(atob(base64));
+};
+
+export const saveSession = (data) => {
+  const payload = parseToken(data.token);
+  localStorage.setItem('token', data.token);
+  localStorage.setItem('refreshToken', data.refresh_token);
+  localStorage.setItem('userId', String(payload.user_id));
+  localStorage.setItem('username', data.username);
+  localStorage.setItem('userRole', payload.role);
+};
+
+export const clearSession = () => {
+  SESSION_KEYS.forEach((key) => localStorage.removeItem(key));
+};
+
+const tokenExpiresSoon = (token) => {
+  try {
+    return parseToken(token).exp * 1000 - Date.now() < REFRESH_MARGIN_MS;
+  } catch {
+    return true;
+  }
+};
+
+// Одновременные запросы ждут одного обновления: refresh-токен одноразовый,
+// и повторное его использование отзывает всю сессию.
+let pendingRefresh = null;
+
+export const refreshSession = () => {
+  if (!pendingRefresh) {
+    pendingRefresh = (async () => {
+      const refreshToken = localStorage.getItem('refreshToken');
+      if (!refreshToken) {
+        throw new Error('No refresh token');
+      }
+      const response = await fetch(`${AUTH_API_URL}/refresh`, {
+        method: 'POST',
+        headers: { 'Content-Type': 'application/json' },
+        body: JSON.stringify({ refresh_token: refreshToken }),
+      });
+      if (!response.ok) {
+        clearSession();
+        throw new Error('Session expired');
+      }
+      const data = await response.json();
+      saveSession(data);
+      return data.token;
+    })().finally(() => {
+      pendingRefresh = null;
+    });
+  }
+  return pendingRefresh;
+};
+
+// getValidToken возвращает действующий access-токен, при необходимости
+// обновив его, или null, если пользователь не вошел.
+export const getValidToken = async () => {
+  const token = localStorage.getItem('token');
+  if (!token) {
+    return null;
+  }
+  if (!tokenExpiresSoon(token)) {
+    return token;
+  }
+  try {
+    return await refreshSession();
+  } catch {
+    return null;
+  }
+};
+
+export const logout = async () => {
+  const token = localStorage.getItem('token');
+  const refreshToken = localStorage.getItem('refreshToken');
+  clearSession();
+  if (!token) {
+    return;
+  }
+  try {
+    await fetch(`${AUTH_API_URL}/logout`, {
+      method: 'POST',
+      headers: {
+        'Content-Type': 'application/json',
+        'Authorization': `Bearer ${token}`,
+      },
+      body: JSON.stringify({ refresh_token: refreshToken || '' }),
+    });
+  } catch (error) {
+    console.error('Logout failed:', error);
+  }
+};
+
+const hasBearer = (config) => {
+  const header = config.headers && config.headers.Authorization;
+  return typeof header === 'string' && header.startsWith('Bearer ');
+};
+
+// installAuthInterceptors подставляет свежий access-токен в запросы с
+// заголовком Authorization и один раз повторяет запрос, получивший 401.
+export const installAuthInterceptors = (client) => {
+  client.interceptors.request.use(async (config) => {
+    if (hasBearer(config)) {
+      const token = await getValidToken();
+      if (token) {
+        config.headers.Authorization = `Bearer ${token}`;
+      }
+    }
+    return config;
+  });
+
+  client.interceptors.response.use(undefined, async (error) => {
+    const config = error.config;
+    if (!error.response || error.response.status !== 401 || !config || config.retried || !hasBearer(config)) {
+      throw error;
+    }
+    config.retried = true;
+    try {
+      const token = await refreshSession();
+      config.headers.Authorization = `Bearer ${token}`;
+    } catch {
+      window.location.assign('/login');
+      throw error;
+    }
+    return client(config);
+  });
+};
@@ -1,81 +1,42 @@
 // src/services/chatService.js
+import { getValidToken } from './authService';
 
-const API_URL = 'http://localhost:50051'; // Замените на URL вашего chat_service (gRPC или REST)
+const CHAT_API_URL = 'http://localhost:8082';
+export const CHAT_WS_URL = 'ws://localhost:8082/ws';
 
-// Функция для получения истории сообщений (если используется REST API)
-export const getChatHistory = async () => {
-  try {
-    const response = await fetch(`${API_URL}/messages`);
-    if (!response.ok) {
-      throw new Error(`HTTP error! status: ${response.status}`);
-    }
-    const data = await response.json();
-    return data;
-  } catch (error) {
-    console.error("Could not fetch chat history:", error);
-    throw error;
+// Запрос к chat-service с токеном текущей сессии
+const request = async (path) => {
+  const token = await getValidToken();
+  if (!token) {
+    throw new Error('Authentication token not found');
   }
-};
-
-// Функция для отправки сообщений (если используется REST API)
-export const sendMessage = async (message) => {
-  try {
-    const response = await fetch(`${API_URL}/messages`, {
-      method: 'POST',
-      headers: {
-        'Content-Type': 'application/json',
-      },
-      body: JSON.stringify(message),
-    });
-    if (!response.ok) {
-      throw new Error(`HTTP error! status: ${response.status}`);
-    }
-    const data = await response.json();
-    return data;
-  } catch (error) {
-    console.error("Could not send message:", error);
-    throw error;
+  const response = await fetch(`${CHAT_API_URL}${path}`, {
+    headers: { 'Authorization': `Bearer ${token}` },
+  });
+  if (!response.ok) {
+    const body = await response.json().catch(() => ({}));
+    throw new Error(body.error || `HTTP error! status: ${response.status}`);
   }
+  return response.json();
 };
 
-// Если используется gRPC (пример - требует библиотеки grpc-web):
-/*
-import { ChatServiceClient } from './grpc/ChatServiceClient'; // Путь к вашему сгенерированному gRPC клиенту
-import { SendMessageRequest, GetChatHistoryRequest } from './grpc/chat_pb'; // Пути к сообщениям protobuf
-
-const chatClient = new ChatServiceClient(API_URL);
-
-export const getChatHistory = async () => {
-    return new Promise((resolve, reject) => {
-        chatClient.getChatHistory(new GetChatHistoryRequest(), {}, (err, response) => {
-            if (err) {
-                console.error("Could not fetch chat history:", err);
-                reject(err);
-                return;
-            }
-            resolve(response.getMessagesList());
-        });
-    });
+// Комнаты, доступные пользователю
+export const getRooms = async () => {
+  return (await request('/rooms')) || [];
 };
 
-export const sendMessage = async (message) => {
-    const request = new SendMessageRequest();
-    request.setText(message.text); // Предполагается, что сообщение имеет поле text
-
-    return new Promise((resolve, reject) => {
-        chatClient.sendMessage(request, {}, (err, response) => {
-            if (err) {
-                console.error("Could not send message:", err);
-                reject(err);
-                return;
-            }
-            resolve(response); // Возвращаем ответ от сервера
-        });
-    });
+// Страница сообщений комнаты от старых к новым: последние сообщения или,
+// с before, предшествующие ему. next_before задан, если есть более старые.
+export const getRoomMessages = async (roomId, { before, limit } = {}) => {
+  const params = new URLSearchParams();
+  if (before) params.set('before', before);
+  if (limit) params.set('limit', limit);
+  const query = params.toString() ? `?${params}` : '';
+  const page = await request(`/rooms/${roomId}/messages${query}`);
+  return { messages: page.messages || [], nextBefore: page.next_before || 0 };
 };
-*/
-
-// WebSocket - обрабатывается напрямую в ChatRoom.jsx
-// (Код WebSocket уже предоставлен в предыдущем примере)
 
-export default { getChatHistory, sendMessage };
\ No newline at end of file
+// Кадры WebSocket-протокола чата
+export const joinFrame = (roomId, after) => ({ type: 'join', room_id: roomId, after });
+export const leaveFrame = (roomId) => ({ type: 'leave', room_id: roomId });
+export const messageFrame = (roomId, message) => ({ type: 'message', room_id: roomId, message });